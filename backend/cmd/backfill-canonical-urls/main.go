@@ -0,0 +1,43 @@
+// Command backfill-canonical-urls stores the canonical form of the URLs of
+// items saved before duplicate detection was added. Migration 0015 copied
+// their URLs as is, so resubmitting one of those pages with tracking
+// parameters or another spelling would save it again. Run it once after
+// migrating; running it again is harmless.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+const batchSize = 500
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Note: .env file not found, using environment variables: %v", err)
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		log.Fatalf("Unable to create connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	updated, err := services.BackfillCanonicalURLs(ctx, db.New(pool), batchSize)
+	if err != nil {
+		log.Fatalf("Backfill stopped after updating %d items: %v", updated, err)
+	}
+	log.Printf("Updated the canonical URL of %d items", updated)
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEnv returns a lookup function backed by a map
+func fakeEnv(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := load("", fakeEnv(map[string]string{"GROQ_API_KEY": "gsk_test"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, int32(25), cfg.Database.MaxConns)
+	assert.Equal(t, 30*time.Second, cfg.Database.ConnectTimeout)
+	assert.Equal(t, 2, cfg.Worker.Count)
+	assert.Equal(t, 5*time.Second, cfg.Worker.PollInterval)
+	assert.True(t, cfg.Worker.EnablePodcasts)
+	assert.Equal(t, "https://api.groq.com/openai/v1", cfg.AI.BaseURL)
+	assert.Equal(t, []string{"openid", "email", "profile"}, cfg.OIDC.Scopes)
+	assert.False(t, cfg.Storage.Enabled())
+	assert.False(t, cfg.Email.Enabled())
+	assert.Equal(t, RateLimitStoreMemory, cfg.RateLimit.Store)
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	cfg, err := load("", fakeEnv(map[string]string{
+		"GROQ_API_KEY":           "gsk_test",
+		"WORKER_COUNT":           "8",
+		"WORKER_POLL_INTERVAL":   "750ms",
+		"WORKER_ENABLE_PODCASTS": "false",
+		"WORKER_BATCH_SIZE":      "50",
+		"DB_MAX_CONNS":           "40",
+		"AI_MODEL":               "llama-3.3-70b-versatile",
+		"OIDC_SCOPES":            "openid, email groups",
+		"CORS_ALLOWED_ORIGINS":   "https://app.example.com, https://*.example.org",
+		"PORT":                   " 9090 ",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 8, cfg.Worker.Count)
+	assert.Equal(t, 750*time.Millisecond, cfg.Worker.PollInterval)
+	assert.False(t, cfg.Worker.EnablePodcasts)
+	assert.Equal(t, int32(50), cfg.Worker.BatchSize)
+	assert.Equal(t, int32(40), cfg.Database.MaxConns)
+	assert.Equal(t, "llama-3.3-70b-versatile", cfg.AI.Model)
+	assert.Equal(t, []string{"openid", "email", "groups"}, cfg.OIDC.Scopes)
+	assert.Equal(t, []string{"https://app.example.com", "https://*.example.org"}, cfg.CORS.AllowedOrigins)
+	assert.Equal(t, "9090", cfg.Server.Port)
+}
+
+func TestLoad_EmptyEnvKeepsDefault(t *testing.T) {
+	cfg, err := load("", fakeEnv(map[string]string{"GROQ_API_KEY": "gsk_test", "WORKER_COUNT": ""}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Worker.Count)
+}
+
+func TestLoad_InvalidEnvValue(t *testing.T) {
+	tests := map[string]string{
+		"WORKER_COUNT":         "two",
+		"WORKER_POLL_INTERVAL": "5",
+		"RATE_LIMIT_ENABLED":   "sometimes",
+		"DB_MAX_CONNS":         "99999999999",
+	}
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := load("", fakeEnv(map[string]string{"GROQ_API_KEY": "gsk_test", name: value}))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), name)
+		})
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	path := writeConfigFile(t, `
+worker:
+  count: 6
+  poll_interval: 2s
+database:
+  max_conns: 50
+ai:
+  api_key: gsk_from_file
+  model: file-model
+digest:
+  scheduler_enabled: true
+  scheduler_interval: 15m
+cors:
+  allowed_origins:
+    - https://app.example.com
+`)
+
+	cfg, err := load(path, fakeEnv(map[string]string{"AI_MODEL": "env-model"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, cfg.Worker.Count)
+	assert.Equal(t, 2*time.Second, cfg.Worker.PollInterval)
+	assert.Equal(t, 3, cfg.Worker.MaxRetries, "settings missing from the file keep their defaults")
+	assert.Equal(t, int32(50), cfg.Database.MaxConns)
+	assert.Equal(t, "gsk_from_file", cfg.AI.APIKey)
+	assert.Equal(t, "env-model", cfg.AI.Model, "environment overrides the file")
+	assert.True(t, cfg.Digest.SchedulerEnabled)
+	assert.Equal(t, 15*time.Minute, cfg.Digest.SchedulerInterval)
+	assert.Equal(t, []string{"https://app.example.com"}, cfg.CORS.AllowedOrigins)
+}
+
+func TestLoad_FileErrors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, err := load(filepath.Join(t.TempDir(), "missing.yaml"), fakeEnv(nil))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read config file")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		path := writeConfigFile(t, "worker:\n  workers: 4\n")
+
+		_, err := load(path, fakeEnv(map[string]string{"GROQ_API_KEY": "gsk_test"}))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "workers")
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		path := writeConfigFile(t, "")
+
+		cfg, err := load(path, fakeEnv(map[string]string{"GROQ_API_KEY": "gsk_test"}))
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, cfg.Worker.Count)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		problem string
+	}{
+		{"missing AI key", func(c *Config) { c.AI.APIKey = "" }, "GROQ_API_KEY is required"},
+		{"bad port", func(c *Config) { c.Server.Port = "http" }, "PORT must be a port number"},
+		{"no workers", func(c *Config) { c.Worker.Count = 0 }, "WORKER_COUNT must be greater than 0"},
+		{"min above max conns", func(c *Config) { c.Database.MinConns = 30 }, "DB_MIN_CONNS (30) must not exceed DB_MAX_CONNS (25)"},
+		{"zero poll interval", func(c *Config) { c.Worker.PollInterval = 0 }, "WORKER_POLL_INTERVAL must be a positive duration"},
+		{"relative frontend URL", func(c *Config) { c.Server.FrontendBaseURL = "localhost:3000" }, "FRONTEND_BASE_URL must be an absolute URL"},
+		{"partial R2", func(c *Config) { c.Storage.BucketName = "audio" }, "R2 storage is partly configured; also set R2_ACCESS_KEY_ID, R2_SECRET_ACCESS_KEY, R2_ACCOUNT_ID"},
+		{"partial SES", func(c *Config) { c.Email.Region = "us-east-1" }, "SES email is partly configured"},
+		{"OIDC without client", func(c *Config) { c.OIDC.IssuerURL = "https://accounts.example.com" }, "OIDC_CLIENT_ID is required"},
+		{"unknown rate limit store", func(c *Config) { c.RateLimit.Store = "redis" }, `RATE_LIMIT_STORE must be "memory" or "postgres"`},
+		{"origin without scheme", func(c *Config) { c.CORS.ExtensionOrigins = []string{"abcdef"} }, `CORS origin "abcdef" must include a scheme`},
+		{"negative quota", func(c *Config) { c.Quota.ItemsPerDay = -1 }, "QUOTA_ITEMS_PER_DAY must not be negative"},
+		{"no scraper concurrency", func(c *Config) { c.Scraper.DomainConcurrency = 0 }, "SCRAPER_DOMAIN_CONCURRENCY must be greater than 0"},
+		{"relative render endpoint", func(c *Config) { c.Scraper.RenderEndpoint = "localhost:9222" }, `SCRAPER_RENDER_ENDPOINT must be an absolute URL`},
+		{"no feed refresh interval", func(c *Config) { c.Feeds.RefreshInterval = 0 }, "FEEDS_REFRESH_INTERVAL must be a positive duration"},
+		{"embeddings without key", func(c *Config) { c.Embeddings.Enabled = true }, "EMBEDDINGS_API_KEY is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.AI.APIKey = "gsk_test"
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	cfg := Default()
+	cfg.Worker.Count = 0
+	cfg.Worker.BatchSize = 0
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Problems, 3)
+	assert.Contains(t, err.Error(), "GROQ_API_KEY is required")
+	assert.Contains(t, err.Error(), "WORKER_COUNT")
+	assert.Contains(t, err.Error(), "WORKER_BATCH_SIZE")
+}
+
+func TestValidate_DisabledSectionsAreNotChecked(t *testing.T) {
+	cfg := Default()
+	cfg.AI.APIKey = "gsk_test"
+	cfg.RateLimit.Enabled = false
+	cfg.RateLimit.Store = ""
+	cfg.Digest.SchedulerInterval = 0
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoad_ExampleFileMatchesDefaults(t *testing.T) {
+	cfg, err := load("../../config.example.yaml", fakeEnv(map[string]string{"GROQ_API_KEY": "gsk_test"}))
+	require.NoError(t, err)
+
+	expected := Default()
+	expected.AI.APIKey = "gsk_test"
+	expected.CORS.AllowedOrigins = []string{}
+	expected.CORS.ExtensionOrigins = []string{}
+	assert.Equal(t, expected, cfg)
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// validator collects problems so they can all be reported at once
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(name, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s is required", name)
+	}
+}
+
+func (v *validator) positive(name string, value int64) {
+	if value <= 0 {
+		v.addf("%s must be greater than 0, got %d", name, value)
+	}
+}
+
+func (v *validator) nonNegative(name string, value int64) {
+	if value < 0 {
+		v.addf("%s must not be negative, got %d", name, value)
+	}
+}
+
+func (v *validator) positiveDuration(name string, value time.Duration) {
+	if value <= 0 {
+		v.addf("%s must be a positive duration, got %s", name, value)
+	}
+}
+
+func (v *validator) nonNegativeDuration(name string, value time.Duration) {
+	if value < 0 {
+		v.addf("%s must not be negative, got %s", name, value)
+	}
+}
+
+func (v *validator) absoluteURL(name, value string) {
+	if value == "" {
+		return
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		v.addf("%s must be an absolute URL, got %q", name, value)
+	}
+}
+
+// requiredTogether reports the missing fields of a section that is only partly
+// set. Each field is a (name, value) pair.
+func (v *validator) requiredTogether(section string, fields ...[2]string) {
+	var missing []string
+	for _, field := range fields {
+		if strings.TrimSpace(field[1]) == "" {
+			missing = append(missing, field[0])
+		}
+	}
+	if len(missing) > 0 {
+		v.addf("%s is partly configured; also set %s", section, strings.Join(missing, ", "))
+	}
+}
+
+// Validate checks every section and returns a *ValidationError listing all problems
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// Server
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		v.addf("PORT must be a port number between 1 and 65535, got %q", c.Server.Port)
+	}
+	v.required("FRONTEND_BASE_URL", c.Server.FrontendBaseURL)
+	v.absoluteURL("FRONTEND_BASE_URL", c.Server.FrontendBaseURL)
+
+	// Database
+	v.required("DATABASE_URL", c.Database.URL)
+	v.positive("DB_MAX_CONNS", int64(c.Database.MaxConns))
+	v.nonNegative("DB_MIN_CONNS", int64(c.Database.MinConns))
+	if c.Database.MaxConns > 0 && c.Database.MinConns > c.Database.MaxConns {
+		v.addf("DB_MIN_CONNS (%d) must not exceed DB_MAX_CONNS (%d)", c.Database.MinConns, c.Database.MaxConns)
+	}
+	v.positiveDuration("DB_MAX_CONN_LIFETIME", c.Database.MaxConnLifetime)
+	v.positiveDuration("DB_MAX_CONN_IDLE_TIME", c.Database.MaxConnIdleTime)
+	v.positiveDuration("DB_HEALTH_CHECK_PERIOD", c.Database.HealthCheckPeriod)
+	v.positiveDuration("DB_CONNECT_TIMEOUT", c.Database.ConnectTimeout)
+
+	// Auth
+	v.positiveDuration("AUTH_SESSION_TTL", c.Auth.SessionTTL)
+
+	// OIDC
+	if c.OIDC.Enabled() {
+		v.absoluteURL("OIDC_ISSUER_URL", c.OIDC.IssuerURL)
+		v.required("OIDC_CLIENT_ID", c.OIDC.ClientID)
+		v.required("OIDC_REDIRECT_URL", c.OIDC.RedirectURL)
+		v.absoluteURL("OIDC_REDIRECT_URL", c.OIDC.RedirectURL)
+		if len(c.OIDC.Scopes) == 0 {
+			v.addf("OIDC_SCOPES must not be empty")
+		}
+	}
+
+	// Worker
+	v.positive("WORKER_COUNT", int64(c.Worker.Count))
+	v.positiveDuration("WORKER_POLL_INTERVAL", c.Worker.PollInterval)
+	v.nonNegative("WORKER_MAX_RETRIES", int64(c.Worker.MaxRetries))
+	v.positive("WORKER_BATCH_SIZE", int64(c.Worker.BatchSize))
+
+	// AI
+	v.required("GROQ_API_KEY", c.AI.APIKey)
+	v.required("AI_BASE_URL", c.AI.BaseURL)
+	v.absoluteURL("AI_BASE_URL", c.AI.BaseURL)
+	v.required("AI_MODEL", c.AI.Model)
+
+	// Speech
+	v.positive("MAX_CONCURRENT_AUDIO_REQUESTS", int64(c.Speech.MaxConcurrentRequests))
+	v.positiveDuration("SPEECH_POLL_INTERVAL", c.Speech.PollInterval)
+
+	// Storage
+	if c.Storage.Enabled() {
+		v.requiredTogether("R2 storage",
+			[2]string{"R2_ACCESS_KEY_ID", c.Storage.AccessKeyID},
+			[2]string{"R2_SECRET_ACCESS_KEY", c.Storage.SecretAccessKey},
+			[2]string{"R2_ACCOUNT_ID", c.Storage.AccountID},
+			[2]string{"R2_BUCKET_NAME", c.Storage.BucketName},
+		)
+	}
+
+	// Email
+	if c.Email.Enabled() {
+		v.requiredTogether("SES email",
+			[2]string{"AWS_ACCESS_KEY_ID", c.Email.AccessKeyID},
+			[2]string{"AWS_SECRET_ACCESS_KEY", c.Email.SecretAccessKey},
+			[2]string{"AWS_REGION", c.Email.Region},
+			[2]string{"SES_FROM_EMAIL", c.Email.FromEmail},
+		)
+	}
+
+	// Digest
+	v.required("DAILY_DIGEST_SUBJECT", c.Digest.Subject)
+	if c.Digest.SchedulerEnabled {
+		v.positiveDuration("DIGEST_SCHEDULER_INTERVAL", c.Digest.SchedulerInterval)
+	}
+
+	// CORS and security headers
+	for _, origin := range append(append([]string{}, c.CORS.AllowedOrigins...), c.CORS.ExtensionOrigins...) {
+		if origin != "*" && !strings.Contains(origin, "://") {
+			v.addf("CORS origin %q must include a scheme, e.g. https://app.example.com", origin)
+		}
+	}
+	v.nonNegativeDuration("CORS_MAX_AGE", c.CORS.MaxAge)
+	v.nonNegativeDuration("SECURITY_HSTS_MAX_AGE", c.Security.HSTSMaxAge)
+
+	// Rate limits
+	if c.RateLimit.Enabled {
+		if c.RateLimit.Store != RateLimitStoreMemory && c.RateLimit.Store != RateLimitStorePostgres {
+			v.addf("RATE_LIMIT_STORE must be %q or %q, got %q", RateLimitStoreMemory, RateLimitStorePostgres, c.RateLimit.Store)
+		}
+		v.positive("RATE_LIMIT_API_PER_MINUTE", int64(c.RateLimit.APIPerMinute))
+		v.positive("RATE_LIMIT_API_BURST", int64(c.RateLimit.APIBurst))
+		v.positive("RATE_LIMIT_CREATE_PER_MINUTE", int64(c.RateLimit.CreatePerMinute))
+		v.positive("RATE_LIMIT_CREATE_BURST", int64(c.RateLimit.CreateBurst))
+		v.positive("RATE_LIMIT_AUTH_PER_MINUTE", int64(c.RateLimit.AuthPerMinute))
+		v.positive("RATE_LIMIT_AUTH_BURST", int64(c.RateLimit.AuthBurst))
+	}
+
+	// Quotas
+	v.nonNegative("QUOTA_ITEMS_PER_DAY", int64(c.Quota.ItemsPerDay))
+	v.nonNegative("QUOTA_PODCAST_MINUTES_PER_DAY", int64(c.Quota.PodcastMinutesPerDay))
+
+	// Scraper
+	v.required("SCRAPER_USER_AGENT", c.Scraper.UserAgent)
+	v.positiveDuration("SCRAPER_TIMEOUT", c.Scraper.Timeout)
+	v.positive("SCRAPER_MAX_BODY_SIZE", c.Scraper.MaxBodySize)
+	v.nonNegativeDuration("SCRAPER_DOMAIN_INTERVAL", c.Scraper.DomainInterval)
+	v.positive("SCRAPER_DOMAIN_CONCURRENCY", int64(c.Scraper.DomainConcurrency))
+	v.nonNegative("SCRAPER_CACHE_ENTRIES", int64(c.Scraper.CacheEntries))
+	v.absoluteURL("SCRAPER_RENDER_ENDPOINT", c.Scraper.RenderEndpoint)
+	v.positiveDuration("SCRAPER_RENDER_TIMEOUT", c.Scraper.RenderTimeout)
+
+	// Feeds
+	if c.Feeds.PollEnabled {
+		v.positiveDuration("FEEDS_POLL_INTERVAL", c.Feeds.PollInterval)
+		v.positiveDuration("FEEDS_REFRESH_INTERVAL", c.Feeds.RefreshInterval)
+		v.positive("FEEDS_BATCH_SIZE", int64(c.Feeds.BatchSize))
+		v.positive("FEEDS_MAX_NEW_ENTRIES", int64(c.Feeds.MaxNewEntries))
+	}
+
+	// Embeddings
+	if c.Embeddings.Enabled {
+		v.required("EMBEDDINGS_API_KEY", c.Embeddings.APIKey)
+		v.required("EMBEDDINGS_BASE_URL", c.Embeddings.BaseURL)
+		v.absoluteURL("EMBEDDINGS_BASE_URL", c.Embeddings.BaseURL)
+		v.required("EMBEDDINGS_MODEL", c.Embeddings.Model)
+		v.positive("EMBEDDINGS_BATCH_SIZE", int64(c.Embeddings.BatchSize))
+		v.positive("EMBEDDINGS_MAX_INPUT_CHARS", int64(c.Embeddings.MaxInputChars))
+		v.positive("EMBEDDINGS_MAX_CANDIDATES", int64(c.Embeddings.MaxCandidates))
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
@@ -7,10 +7,74 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
+const addItemTags = `-- name: AddItemTags :exec
+UPDATE items
+SET tags = COALESCE(tags, '{}') || ARRAY(
+  SELECT t FROM unnest($1::text[]) WITH ORDINALITY AS added(t, n)
+  WHERE lower(t) NOT IN (SELECT lower(x) FROM unnest(COALESCE(tags, '{}')) AS x)
+  ORDER BY n
+)
+WHERE id = $2
+`
+
+type AddItemTagsParams struct {
+	Tags []string `json:"tags"`
+	ID   int32    `json:"id"`
+}
+
+// Appends the tags the item does not have yet, ignoring case
+func (q *Queries) AddItemTags(ctx context.Context, arg AddItemTagsParams) error {
+	_, err := q.db.Exec(ctx, addItemTags, arg.Tags, arg.ID)
+	return err
+}
+
+const backfillItemCanonicalURL = `-- name: BackfillItemCanonicalURL :execrows
+UPDATE items SET canonical_url = $2
+WHERE id = $1
+  AND canonical_url IS DISTINCT FROM $2
+  AND NOT EXISTS (
+    SELECT 1 FROM items other
+    WHERE other.user_id = items.user_id AND other.canonical_url = $2 AND other.id <> items.id
+  )
+`
+
+type BackfillItemCanonicalURLParams struct {
+	ID           int32   `json:"id"`
+	CanonicalUrl *string `json:"canonical_url"`
+}
+
+// Sets the canonical URL unless another item of the same user already has it
+func (q *Queries) BackfillItemCanonicalURL(ctx context.Context, arg BackfillItemCanonicalURLParams) (int64, error) {
+	result, err := q.db.Exec(ctx, backfillItemCanonicalURL, arg.ID, arg.CanonicalUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countItemsVisibleToUser = `-- name: CountItemsVisibleToUser :one
+SELECT COUNT(*) FROM items
+WHERE id = ANY($1::int[])
+  AND (items.user_id = $2 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $2))
+`
+
+type CountItemsVisibleToUserParams struct {
+	ItemIds []int32 `json:"item_ids"`
+	UserID  *int32  `json:"user_id"`
+}
+
+func (q *Queries) CountItemsVisibleToUser(ctx context.Context, arg CountItemsVisibleToUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countItemsVisibleToUser, arg.ItemIds, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createItem = `-- name: CreateItem :one
-INSERT INTO items (user_id, title, url, text_content, summary, type, tags, platform, authors, processing_status, processing_error) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error
+INSERT INTO items (user_id, title, url, text_content, summary, type, tags, platform, authors, processing_status, processing_error, workspace_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url
 `
 
 type CreateItemParams struct {
@@ -25,6 +89,7 @@ type CreateItemParams struct {
 	Authors          []string `json:"authors"`
 	ProcessingStatus *string  `json:"processing_status"`
 	ProcessingError  *string  `json:"processing_error"`
+	WorkspaceID      *int32   `json:"workspace_id"`
 }
 
 func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (Item, error) {
@@ -40,13 +105,13 @@ func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (Item, e
 		arg.Authors,
 		arg.ProcessingStatus,
 		arg.ProcessingError,
+		arg.WorkspaceID,
 	)
 	var i Item
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.Url,
-		&i.IsRead,
 		&i.TextContent,
 		&i.Summary,
 		&i.Type,
@@ -58,28 +123,85 @@ func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (Item, e
 		&i.Title,
 		&i.ProcessingStatus,
 		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
 
 const createPendingItem = `-- name: CreatePendingItem :one
-INSERT INTO items (user_id, title, url, processing_status) VALUES ($1, $2, $3, 'pending') RETURNING id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error
+INSERT INTO items (user_id, title, url, canonical_url, workspace_id, processing_status) VALUES ($1, $2, $3, $4, $5, 'pending') RETURNING id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url
 `
 
 type CreatePendingItemParams struct {
-	UserID *int32  `json:"user_id"`
-	Title  string  `json:"title"`
-	Url    *string `json:"url"`
+	UserID       *int32  `json:"user_id"`
+	Title        string  `json:"title"`
+	Url          *string `json:"url"`
+	CanonicalUrl *string `json:"canonical_url"`
+	WorkspaceID  *int32  `json:"workspace_id"`
 }
 
 func (q *Queries) CreatePendingItem(ctx context.Context, arg CreatePendingItemParams) (Item, error) {
-	row := q.db.QueryRow(ctx, createPendingItem, arg.UserID, arg.Title, arg.Url)
+	row := q.db.QueryRow(ctx, createPendingItem,
+		arg.UserID,
+		arg.Title,
+		arg.Url,
+		arg.CanonicalUrl,
+		arg.WorkspaceID,
+	)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.TextContent,
+		&i.Summary,
+		&i.Type,
+		&i.Tags,
+		&i.Platform,
+		&i.Authors,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Title,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
+const createPendingSourceItem = `-- name: CreatePendingSourceItem :one
+INSERT INTO items (user_id, title, source_type, source_content, workspace_id, processing_status) VALUES ($1, $2, $3, $4, $5, 'pending') RETURNING id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url
+`
+
+type CreatePendingSourceItemParams struct {
+	UserID        *int32  `json:"user_id"`
+	Title         string  `json:"title"`
+	SourceType    string  `json:"source_type"`
+	SourceContent *string `json:"source_content"`
+	WorkspaceID   *int32  `json:"workspace_id"`
+}
+
+func (q *Queries) CreatePendingSourceItem(ctx context.Context, arg CreatePendingSourceItemParams) (Item, error) {
+	row := q.db.QueryRow(ctx, createPendingSourceItem,
+		arg.UserID,
+		arg.Title,
+		arg.SourceType,
+		arg.SourceContent,
+		arg.WorkspaceID,
+	)
 	var i Item
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.Url,
-		&i.IsRead,
 		&i.TextContent,
 		&i.Summary,
 		&i.Type,
@@ -91,6 +213,11 @@ func (q *Queries) CreatePendingItem(ctx context.Context, arg CreatePendingItemPa
 		&i.Title,
 		&i.ProcessingStatus,
 		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
@@ -104,8 +231,17 @@ func (q *Queries) DeleteItem(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteItemsByUser = `-- name: DeleteItemsByUser :exec
+DELETE FROM items WHERE user_id = $1
+`
+
+func (q *Queries) DeleteItemsByUser(ctx context.Context, userID *int32) error {
+	_, err := q.db.Exec(ctx, deleteItemsByUser, userID)
+	return err
+}
+
 const getFailedItemsForRetry = `-- name: GetFailedItemsForRetry :many
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items WHERE processing_status = 'failed' AND created_at > NOW() - INTERVAL '24 hours' ORDER BY created_at ASC LIMIT $1
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE processing_status = 'failed' AND created_at > NOW() - INTERVAL '24 hours' ORDER BY created_at ASC LIMIT $1
 `
 
 func (q *Queries) GetFailedItemsForRetry(ctx context.Context, limit int32) ([]Item, error) {
@@ -121,7 +257,6 @@ func (q *Queries) GetFailedItemsForRetry(ctx context.Context, limit int32) ([]It
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -133,6 +268,11 @@ func (q *Queries) GetFailedItemsForRetry(ctx context.Context, limit int32) ([]It
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -145,7 +285,7 @@ func (q *Queries) GetFailedItemsForRetry(ctx context.Context, limit int32) ([]It
 }
 
 const getItem = `-- name: GetItem :one
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items WHERE id = $1
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE id = $1
 `
 
 func (q *Queries) GetItem(ctx context.Context, id int32) (Item, error) {
@@ -155,7 +295,6 @@ func (q *Queries) GetItem(ctx context.Context, id int32) (Item, error) {
 		&i.ID,
 		&i.UserID,
 		&i.Url,
-		&i.IsRead,
 		&i.TextContent,
 		&i.Summary,
 		&i.Type,
@@ -167,12 +306,159 @@ func (q *Queries) GetItem(ctx context.Context, id int32) (Item, error) {
 		&i.Title,
 		&i.ProcessingStatus,
 		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
+const getItemByCanonicalURL = `-- name: GetItemByCanonicalURL :one
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE user_id = $1 AND canonical_url = $2
+`
+
+type GetItemByCanonicalURLParams struct {
+	UserID       *int32  `json:"user_id"`
+	CanonicalUrl *string `json:"canonical_url"`
+}
+
+func (q *Queries) GetItemByCanonicalURL(ctx context.Context, arg GetItemByCanonicalURLParams) (Item, error) {
+	row := q.db.QueryRow(ctx, getItemByCanonicalURL, arg.UserID, arg.CanonicalUrl)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.TextContent,
+		&i.Summary,
+		&i.Type,
+		&i.Tags,
+		&i.Platform,
+		&i.Authors,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Title,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
+const getItemForUser = `-- name: GetItemForUser :one
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE id = $1 AND user_id = $2
+`
+
+type GetItemForUserParams struct {
+	ID     int32  `json:"id"`
+	UserID *int32 `json:"user_id"`
+}
+
+func (q *Queries) GetItemForUser(ctx context.Context, arg GetItemForUserParams) (Item, error) {
+	row := q.db.QueryRow(ctx, getItemForUser, arg.ID, arg.UserID)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.TextContent,
+		&i.Summary,
+		&i.Type,
+		&i.Tags,
+		&i.Platform,
+		&i.Authors,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Title,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
+
+const getItemReadsByUser = `-- name: GetItemReadsByUser :many
+SELECT item_id, read_at FROM item_reads WHERE user_id = $1
+`
+
+type GetItemReadsByUserRow struct {
+	ItemID int32     `json:"item_id"`
+	ReadAt time.Time `json:"read_at"`
+}
+
+func (q *Queries) GetItemReadsByUser(ctx context.Context, userID int32) ([]GetItemReadsByUserRow, error) {
+	rows, err := q.db.Query(ctx, getItemReadsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetItemReadsByUserRow{}
+	for rows.Next() {
+		var i GetItemReadsByUserRow
+		if err := rows.Scan(
+			&i.ItemID,
+			&i.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getItemVisibleToUser = `-- name: GetItemVisibleToUser :one
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items
+WHERE id = $1
+  AND (items.user_id = $2 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $2))
+`
+
+type GetItemVisibleToUserParams struct {
+	ID     int32  `json:"id"`
+	UserID *int32 `json:"user_id"`
+}
+
+func (q *Queries) GetItemVisibleToUser(ctx context.Context, arg GetItemVisibleToUserParams) (Item, error) {
+	row := q.db.QueryRow(ctx, getItemVisibleToUser, arg.ID, arg.UserID)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.TextContent,
+		&i.Summary,
+		&i.Type,
+		&i.Tags,
+		&i.Platform,
+		&i.Authors,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Title,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
 
 const getItemsByProcessingStatus = `-- name: GetItemsByProcessingStatus :many
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items WHERE processing_status = $1 ORDER BY created_at DESC
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE processing_status = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetItemsByProcessingStatus(ctx context.Context, processingStatus *string) ([]Item, error) {
@@ -188,7 +474,6 @@ func (q *Queries) GetItemsByProcessingStatus(ctx context.Context, processingStat
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -200,6 +485,11 @@ func (q *Queries) GetItemsByProcessingStatus(ctx context.Context, processingStat
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -212,7 +502,10 @@ func (q *Queries) GetItemsByProcessingStatus(ctx context.Context, processingStat
 }
 
 const getItemsByUser = `-- name: GetItemsByUser :many
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items WHERE user_id = $1 ORDER BY created_at DESC
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items
+WHERE items.user_id = $1
+   OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1)
+ORDER BY created_at DESC
 `
 
 func (q *Queries) GetItemsByUser(ctx context.Context, userID *int32) ([]Item, error) {
@@ -228,7 +521,6 @@ func (q *Queries) GetItemsByUser(ctx context.Context, userID *int32) ([]Item, er
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -240,6 +532,11 @@ func (q *Queries) GetItemsByUser(ctx context.Context, userID *int32) ([]Item, er
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -251,12 +548,17 @@ func (q *Queries) GetItemsByUser(ctx context.Context, userID *int32) ([]Item, er
 	return items, nil
 }
 
-const getPendingItems = `-- name: GetPendingItems :many
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items WHERE processing_status = 'pending' ORDER BY created_at ASC LIMIT $1
+const getItemsByUserAndProcessingStatus = `-- name: GetItemsByUserAndProcessingStatus :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE user_id = $1 AND processing_status = $2 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetPendingItems(ctx context.Context, limit int32) ([]Item, error) {
-	rows, err := q.db.Query(ctx, getPendingItems, limit)
+type GetItemsByUserAndProcessingStatusParams struct {
+	UserID           *int32  `json:"user_id"`
+	ProcessingStatus *string `json:"processing_status"`
+}
+
+func (q *Queries) GetItemsByUserAndProcessingStatus(ctx context.Context, arg GetItemsByUserAndProcessingStatusParams) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getItemsByUserAndProcessingStatus, arg.UserID, arg.ProcessingStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -268,7 +570,6 @@ func (q *Queries) GetPendingItems(ctx context.Context, limit int32) ([]Item, err
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -280,6 +581,11 @@ func (q *Queries) GetPendingItems(ctx context.Context, limit int32) ([]Item, err
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -291,12 +597,12 @@ func (q *Queries) GetPendingItems(ctx context.Context, limit int32) ([]Item, err
 	return items, nil
 }
 
-const getUnreadItemsByUser = `-- name: GetUnreadItemsByUser :many
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items WHERE user_id = $1 AND is_read = FALSE ORDER BY created_at DESC
+const getItemsByWorkspace = `-- name: GetItemsByWorkspace :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE workspace_id = $1 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]Item, error) {
-	rows, err := q.db.Query(ctx, getUnreadItemsByUser, userID)
+func (q *Queries) GetItemsByWorkspace(ctx context.Context, workspaceID *int32) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getItemsByWorkspace, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -308,7 +614,6 @@ func (q *Queries) GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]It
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -320,6 +625,11 @@ func (q *Queries) GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]It
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -331,17 +641,12 @@ func (q *Queries) GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]It
 	return items, nil
 }
 
-const getUnreadItemsFromPreviousDay = `-- name: GetUnreadItemsFromPreviousDay :many
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items 
-WHERE created_at >= DATE_TRUNC('day', NOW() - INTERVAL '1 day') 
-  AND created_at < DATE_TRUNC('day', NOW())
-  AND is_read = FALSE
-  AND processing_status = 'completed'
-ORDER BY created_at DESC
+const getItemsOwnedByUser = `-- name: GetItemsOwnedByUser :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE user_id = $1 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]Item, error) {
-	rows, err := q.db.Query(ctx, getUnreadItemsFromPreviousDay)
+func (q *Queries) GetItemsOwnedByUser(ctx context.Context, userID *int32) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getItemsOwnedByUser, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -353,7 +658,6 @@ func (q *Queries) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]Item, er
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -365,6 +669,11 @@ func (q *Queries) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]Item, er
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -376,18 +685,12 @@ func (q *Queries) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]Item, er
 	return items, nil
 }
 
-const getUnreadItemsFromPreviousDayByUser = `-- name: GetUnreadItemsFromPreviousDayByUser :many
-SELECT id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error FROM items
-WHERE user_id = $1
-  AND created_at >= DATE_TRUNC('day', NOW() - INTERVAL '1 day')
-  AND created_at < DATE_TRUNC('day', NOW())
-  AND is_read = FALSE
-  AND processing_status = 'completed'
-ORDER BY created_at DESC
+const getPendingItems = `-- name: GetPendingItems :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items WHERE processing_status = 'pending' ORDER BY created_at ASC LIMIT $1
 `
 
-func (q *Queries) GetUnreadItemsFromPreviousDayByUser(ctx context.Context, userID *int32) ([]Item, error) {
-	rows, err := q.db.Query(ctx, getUnreadItemsFromPreviousDayByUser, userID)
+func (q *Queries) GetPendingItems(ctx context.Context, limit int32) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getPendingItems, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +702,6 @@ func (q *Queries) GetUnreadItemsFromPreviousDayByUser(ctx context.Context, userI
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -411,6 +713,11 @@ func (q *Queries) GetUnreadItemsFromPreviousDayByUser(ctx context.Context, userI
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -422,78 +729,795 @@ func (q *Queries) GetUnreadItemsFromPreviousDayByUser(ctx context.Context, userI
 	return items, nil
 }
 
-const markItemAsRead = `-- name: MarkItemAsRead :exec
-UPDATE items SET is_read = TRUE, modified_at = CURRENT_TIMESTAMP WHERE id = $1
-`
-
-func (q *Queries) MarkItemAsRead(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, markItemAsRead, id)
-	return err
-}
-
-const patchItem = `-- name: PatchItem :one
-UPDATE items
-SET
-  title = COALESCE(NULLIF($1, ''), title),
-  summary = CASE WHEN $2::text IS NULL THEN summary ELSE $2 END,
-  tags = CASE WHEN $3::text[] IS NULL THEN tags ELSE $3 END,
-  authors = CASE WHEN $4::text[] IS NULL THEN authors ELSE $4 END,
-  modified_at = CURRENT_TIMESTAMP
-WHERE id = $5
-RETURNING id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error
+const getReadItemIDs = `-- name: GetReadItemIDs :many
+SELECT item_id FROM item_reads WHERE user_id = $1 AND item_id = ANY($2::int[])
 `
 
-type PatchItemParams struct {
-	Title   interface{} `json:"title"`
-	Summary *string     `json:"summary"`
-	Tags    []string    `json:"tags"`
-	Authors []string    `json:"authors"`
-	ID      int32       `json:"id"`
+type GetReadItemIDsParams struct {
+	UserID  int32   `json:"user_id"`
+	ItemIds []int32 `json:"item_ids"`
 }
 
-func (q *Queries) PatchItem(ctx context.Context, arg PatchItemParams) (Item, error) {
-	row := q.db.QueryRow(ctx, patchItem,
-		arg.Title,
-		arg.Summary,
-		arg.Tags,
-		arg.Authors,
-		arg.ID,
-	)
-	var i Item
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Url,
-		&i.IsRead,
-		&i.TextContent,
-		&i.Summary,
-		&i.Type,
-		&i.Tags,
-		&i.Platform,
-		&i.Authors,
-		&i.CreatedAt,
-		&i.ModifiedAt,
-		&i.Title,
-		&i.ProcessingStatus,
-		&i.ProcessingError,
-	)
-	return i, err
+func (q *Queries) GetReadItemIDs(ctx context.Context, arg GetReadItemIDsParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, getReadItemIDs, arg.UserID, arg.ItemIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var item_id int32
+		if err := rows.Scan(&item_id); err != nil {
+			return nil, err
+		}
+		items = append(items, item_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const toggleItemReadStatus = `-- name: ToggleItemReadStatus :one
-UPDATE items SET is_read = NOT is_read, modified_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING id, user_id, url, is_read, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error
+const getUnreadItemsByUser = `-- name: GetUnreadItemsByUser :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items
+WHERE (items.user_id = $1 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1))
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1)
+ORDER BY created_at DESC
 `
 
-func (q *Queries) ToggleItemReadStatus(ctx context.Context, id int32) (Item, error) {
-	row := q.db.QueryRow(ctx, toggleItemReadStatus, id)
-	var i Item
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Url,
-		&i.IsRead,
-		&i.TextContent,
-		&i.Summary,
+func (q *Queries) GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getUnreadItemsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Item{}
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.TextContent,
+			&i.Summary,
+			&i.Type,
+			&i.Tags,
+			&i.Platform,
+			&i.Authors,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.Title,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnreadItemsByUserInRange = `-- name: GetUnreadItemsByUserInRange :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items
+WHERE user_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = items.user_id)
+  AND processing_status = 'completed'
+ORDER BY created_at DESC
+`
+
+type GetUnreadItemsByUserInRangeParams struct {
+	UserID      *int32     `json:"user_id"`
+	WindowStart *time.Time `json:"window_start"`
+	WindowEnd   *time.Time `json:"window_end"`
+}
+
+func (q *Queries) GetUnreadItemsByUserInRange(ctx context.Context, arg GetUnreadItemsByUserInRangeParams) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getUnreadItemsByUserInRange, arg.UserID, arg.WindowStart, arg.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Item{}
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.TextContent,
+			&i.Summary,
+			&i.Type,
+			&i.Tags,
+			&i.Platform,
+			&i.Authors,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.Title,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnreadItemsByWorkspace = `-- name: GetUnreadItemsByWorkspace :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items
+WHERE workspace_id = $1
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $2)
+ORDER BY created_at DESC
+`
+
+type GetUnreadItemsByWorkspaceParams struct {
+	WorkspaceID *int32 `json:"workspace_id"`
+	UserID      int32  `json:"user_id"`
+}
+
+func (q *Queries) GetUnreadItemsByWorkspace(ctx context.Context, arg GetUnreadItemsByWorkspaceParams) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getUnreadItemsByWorkspace, arg.WorkspaceID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Item{}
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.TextContent,
+			&i.Summary,
+			&i.Type,
+			&i.Tags,
+			&i.Platform,
+			&i.Authors,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.Title,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnreadItemsFromPreviousDay = `-- name: GetUnreadItemsFromPreviousDay :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items 
+WHERE created_at >= DATE_TRUNC('day', NOW() - INTERVAL '1 day') 
+  AND created_at < DATE_TRUNC('day', NOW())
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = items.user_id)
+  AND processing_status = 'completed'
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getUnreadItemsFromPreviousDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Item{}
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.TextContent,
+			&i.Summary,
+			&i.Type,
+			&i.Tags,
+			&i.Platform,
+			&i.Authors,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.Title,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnreadItemsFromPreviousDayByUser = `-- name: GetUnreadItemsFromPreviousDayByUser :many
+SELECT id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url FROM items
+WHERE user_id = $1
+  AND created_at >= DATE_TRUNC('day', NOW() - INTERVAL '1 day')
+  AND created_at < DATE_TRUNC('day', NOW())
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = items.user_id)
+  AND processing_status = 'completed'
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetUnreadItemsFromPreviousDayByUser(ctx context.Context, userID *int32) ([]Item, error) {
+	rows, err := q.db.Query(ctx, getUnreadItemsFromPreviousDayByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Item{}
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.TextContent,
+			&i.Summary,
+			&i.Type,
+			&i.Tags,
+			&i.Platform,
+			&i.Authors,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.Title,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isItemRead = `-- name: IsItemRead :one
+SELECT EXISTS (SELECT 1 FROM item_reads WHERE user_id = $1 AND item_id = $2)
+`
+
+type IsItemReadParams struct {
+	UserID int32 `json:"user_id"`
+	ItemID int32 `json:"item_id"`
+}
+
+func (q *Queries) IsItemRead(ctx context.Context, arg IsItemReadParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isItemRead, arg.UserID, arg.ItemID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listItemsPageByCreatedAsc = `-- name: ListItemsPageByCreatedAsc :many
+SELECT items.id, items.user_id, items.url, items.text_content, items.summary, items.type, items.tags, items.platform, items.authors, items.created_at, items.modified_at, items.title, items.processing_status, items.processing_error, items.workspace_id, items.source_type, items.source_content, items.failure_reason, items.canonical_url,
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) AS is_read
+FROM items
+WHERE (items.user_id = $1 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1))
+  AND ($2::int IS NULL OR items.workspace_id = $2)
+  AND ($3::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND ($4::text IS NULL OR lower(items.platform) = lower($4))
+  AND ($5::text IS NULL OR lower(items.type) = lower($5))
+  AND ($6::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) = $6)
+  AND ($7::text IS NULL OR items.processing_status = $7)
+  AND ($8::timestamptz IS NULL OR items.created_at >= $8)
+  AND ($9::timestamptz IS NULL OR items.created_at < $9)
+  AND ($10::int IS NULL OR (items.created_at, items.id) > ($11::timestamptz, $10))
+ORDER BY items.created_at ASC, items.id ASC
+LIMIT $12
+`
+
+type ListItemsPageByCreatedAscParams struct {
+	UserID          int32      `json:"user_id"`
+	WorkspaceID     *int32     `json:"workspace_id"`
+	Tags            []string   `json:"tags"`
+	Platform        *string    `json:"platform"`
+	Type            *string    `json:"type"`
+	IsRead          *bool      `json:"is_read"`
+	Status          *string    `json:"status"`
+	CreatedAfter    *time.Time `json:"created_after"`
+	CreatedBefore   *time.Time `json:"created_before"`
+	CursorID        *int32     `json:"cursor_id"`
+	CursorCreatedAt *time.Time `json:"cursor_created_at"`
+	Limit           int32      `json:"limit"`
+}
+
+type ListItemsPageByCreatedAscRow struct {
+	Item   Item `json:"item"`
+	IsRead bool `json:"is_read"`
+}
+
+// One page of the items a user can see, oldest first. Rows come after the cursor,
+// the creation time and ID of the last row of the previous page.
+func (q *Queries) ListItemsPageByCreatedAsc(ctx context.Context, arg ListItemsPageByCreatedAscParams) ([]ListItemsPageByCreatedAscRow, error) {
+	rows, err := q.db.Query(ctx, listItemsPageByCreatedAsc,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.Tags,
+		arg.Platform,
+		arg.Type,
+		arg.IsRead,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListItemsPageByCreatedAscRow{}
+	for rows.Next() {
+		var i ListItemsPageByCreatedAscRow
+		if err := rows.Scan(
+			&i.Item.ID,
+			&i.Item.UserID,
+			&i.Item.Url,
+			&i.Item.TextContent,
+			&i.Item.Summary,
+			&i.Item.Type,
+			&i.Item.Tags,
+			&i.Item.Platform,
+			&i.Item.Authors,
+			&i.Item.CreatedAt,
+			&i.Item.ModifiedAt,
+			&i.Item.Title,
+			&i.Item.ProcessingStatus,
+			&i.Item.ProcessingError,
+			&i.Item.WorkspaceID,
+			&i.Item.SourceType,
+			&i.Item.SourceContent,
+			&i.Item.FailureReason,
+			&i.Item.CanonicalUrl,
+			&i.IsRead,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemsPageByCreatedDesc = `-- name: ListItemsPageByCreatedDesc :many
+SELECT items.id, items.user_id, items.url, items.text_content, items.summary, items.type, items.tags, items.platform, items.authors, items.created_at, items.modified_at, items.title, items.processing_status, items.processing_error, items.workspace_id, items.source_type, items.source_content, items.failure_reason, items.canonical_url,
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) AS is_read
+FROM items
+WHERE (items.user_id = $1 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1))
+  AND ($2::int IS NULL OR items.workspace_id = $2)
+  AND ($3::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND ($4::text IS NULL OR lower(items.platform) = lower($4))
+  AND ($5::text IS NULL OR lower(items.type) = lower($5))
+  AND ($6::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) = $6)
+  AND ($7::text IS NULL OR items.processing_status = $7)
+  AND ($8::timestamptz IS NULL OR items.created_at >= $8)
+  AND ($9::timestamptz IS NULL OR items.created_at < $9)
+  AND ($10::int IS NULL OR (items.created_at, items.id) < ($11::timestamptz, $10))
+ORDER BY items.created_at DESC, items.id DESC
+LIMIT $12
+`
+
+type ListItemsPageByCreatedDescParams struct {
+	UserID          int32      `json:"user_id"`
+	WorkspaceID     *int32     `json:"workspace_id"`
+	Tags            []string   `json:"tags"`
+	Platform        *string    `json:"platform"`
+	Type            *string    `json:"type"`
+	IsRead          *bool      `json:"is_read"`
+	Status          *string    `json:"status"`
+	CreatedAfter    *time.Time `json:"created_after"`
+	CreatedBefore   *time.Time `json:"created_before"`
+	CursorID        *int32     `json:"cursor_id"`
+	CursorCreatedAt *time.Time `json:"cursor_created_at"`
+	Limit           int32      `json:"limit"`
+}
+
+type ListItemsPageByCreatedDescRow struct {
+	Item   Item `json:"item"`
+	IsRead bool `json:"is_read"`
+}
+
+// One page of the items a user can see, newest first, after the cursor
+func (q *Queries) ListItemsPageByCreatedDesc(ctx context.Context, arg ListItemsPageByCreatedDescParams) ([]ListItemsPageByCreatedDescRow, error) {
+	rows, err := q.db.Query(ctx, listItemsPageByCreatedDesc,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.Tags,
+		arg.Platform,
+		arg.Type,
+		arg.IsRead,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListItemsPageByCreatedDescRow{}
+	for rows.Next() {
+		var i ListItemsPageByCreatedDescRow
+		if err := rows.Scan(
+			&i.Item.ID,
+			&i.Item.UserID,
+			&i.Item.Url,
+			&i.Item.TextContent,
+			&i.Item.Summary,
+			&i.Item.Type,
+			&i.Item.Tags,
+			&i.Item.Platform,
+			&i.Item.Authors,
+			&i.Item.CreatedAt,
+			&i.Item.ModifiedAt,
+			&i.Item.Title,
+			&i.Item.ProcessingStatus,
+			&i.Item.ProcessingError,
+			&i.Item.WorkspaceID,
+			&i.Item.SourceType,
+			&i.Item.SourceContent,
+			&i.Item.FailureReason,
+			&i.Item.CanonicalUrl,
+			&i.IsRead,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemsPageByTitleAsc = `-- name: ListItemsPageByTitleAsc :many
+SELECT items.id, items.user_id, items.url, items.text_content, items.summary, items.type, items.tags, items.platform, items.authors, items.created_at, items.modified_at, items.title, items.processing_status, items.processing_error, items.workspace_id, items.source_type, items.source_content, items.failure_reason, items.canonical_url,
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) AS is_read
+FROM items
+WHERE (items.user_id = $1 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1))
+  AND ($2::int IS NULL OR items.workspace_id = $2)
+  AND ($3::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND ($4::text IS NULL OR lower(items.platform) = lower($4))
+  AND ($5::text IS NULL OR lower(items.type) = lower($5))
+  AND ($6::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) = $6)
+  AND ($7::text IS NULL OR items.processing_status = $7)
+  AND ($8::timestamptz IS NULL OR items.created_at >= $8)
+  AND ($9::timestamptz IS NULL OR items.created_at < $9)
+  AND ($10::int IS NULL OR (items.title, items.id) > ($11::text, $10))
+ORDER BY items.title ASC, items.id ASC
+LIMIT $12
+`
+
+type ListItemsPageByTitleAscParams struct {
+	UserID        int32      `json:"user_id"`
+	WorkspaceID   *int32     `json:"workspace_id"`
+	Tags          []string   `json:"tags"`
+	Platform      *string    `json:"platform"`
+	Type          *string    `json:"type"`
+	IsRead        *bool      `json:"is_read"`
+	Status        *string    `json:"status"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+	CursorID      *int32     `json:"cursor_id"`
+	CursorTitle   *string    `json:"cursor_title"`
+	Limit         int32      `json:"limit"`
+}
+
+type ListItemsPageByTitleAscRow struct {
+	Item   Item `json:"item"`
+	IsRead bool `json:"is_read"`
+}
+
+// One page of the items a user can see, by title A to Z, after the cursor
+func (q *Queries) ListItemsPageByTitleAsc(ctx context.Context, arg ListItemsPageByTitleAscParams) ([]ListItemsPageByTitleAscRow, error) {
+	rows, err := q.db.Query(ctx, listItemsPageByTitleAsc,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.Tags,
+		arg.Platform,
+		arg.Type,
+		arg.IsRead,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorTitle,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListItemsPageByTitleAscRow{}
+	for rows.Next() {
+		var i ListItemsPageByTitleAscRow
+		if err := rows.Scan(
+			&i.Item.ID,
+			&i.Item.UserID,
+			&i.Item.Url,
+			&i.Item.TextContent,
+			&i.Item.Summary,
+			&i.Item.Type,
+			&i.Item.Tags,
+			&i.Item.Platform,
+			&i.Item.Authors,
+			&i.Item.CreatedAt,
+			&i.Item.ModifiedAt,
+			&i.Item.Title,
+			&i.Item.ProcessingStatus,
+			&i.Item.ProcessingError,
+			&i.Item.WorkspaceID,
+			&i.Item.SourceType,
+			&i.Item.SourceContent,
+			&i.Item.FailureReason,
+			&i.Item.CanonicalUrl,
+			&i.IsRead,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemsPageByTitleDesc = `-- name: ListItemsPageByTitleDesc :many
+SELECT items.id, items.user_id, items.url, items.text_content, items.summary, items.type, items.tags, items.platform, items.authors, items.created_at, items.modified_at, items.title, items.processing_status, items.processing_error, items.workspace_id, items.source_type, items.source_content, items.failure_reason, items.canonical_url,
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) AS is_read
+FROM items
+WHERE (items.user_id = $1 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1))
+  AND ($2::int IS NULL OR items.workspace_id = $2)
+  AND ($3::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND ($4::text IS NULL OR lower(items.platform) = lower($4))
+  AND ($5::text IS NULL OR lower(items.type) = lower($5))
+  AND ($6::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) = $6)
+  AND ($7::text IS NULL OR items.processing_status = $7)
+  AND ($8::timestamptz IS NULL OR items.created_at >= $8)
+  AND ($9::timestamptz IS NULL OR items.created_at < $9)
+  AND ($10::int IS NULL OR (items.title, items.id) < ($11::text, $10))
+ORDER BY items.title DESC, items.id DESC
+LIMIT $12
+`
+
+type ListItemsPageByTitleDescParams struct {
+	UserID        int32      `json:"user_id"`
+	WorkspaceID   *int32     `json:"workspace_id"`
+	Tags          []string   `json:"tags"`
+	Platform      *string    `json:"platform"`
+	Type          *string    `json:"type"`
+	IsRead        *bool      `json:"is_read"`
+	Status        *string    `json:"status"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+	CursorID      *int32     `json:"cursor_id"`
+	CursorTitle   *string    `json:"cursor_title"`
+	Limit         int32      `json:"limit"`
+}
+
+type ListItemsPageByTitleDescRow struct {
+	Item   Item `json:"item"`
+	IsRead bool `json:"is_read"`
+}
+
+// One page of the items a user can see, by title Z to A, after the cursor
+func (q *Queries) ListItemsPageByTitleDesc(ctx context.Context, arg ListItemsPageByTitleDescParams) ([]ListItemsPageByTitleDescRow, error) {
+	rows, err := q.db.Query(ctx, listItemsPageByTitleDesc,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.Tags,
+		arg.Platform,
+		arg.Type,
+		arg.IsRead,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorTitle,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListItemsPageByTitleDescRow{}
+	for rows.Next() {
+		var i ListItemsPageByTitleDescRow
+		if err := rows.Scan(
+			&i.Item.ID,
+			&i.Item.UserID,
+			&i.Item.Url,
+			&i.Item.TextContent,
+			&i.Item.Summary,
+			&i.Item.Type,
+			&i.Item.Tags,
+			&i.Item.Platform,
+			&i.Item.Authors,
+			&i.Item.CreatedAt,
+			&i.Item.ModifiedAt,
+			&i.Item.Title,
+			&i.Item.ProcessingStatus,
+			&i.Item.ProcessingError,
+			&i.Item.WorkspaceID,
+			&i.Item.SourceType,
+			&i.Item.SourceContent,
+			&i.Item.FailureReason,
+			&i.Item.CanonicalUrl,
+			&i.IsRead,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemURLsAfter = `-- name: ListItemURLsAfter :many
+SELECT id, user_id, url FROM items
+WHERE id > $1 AND user_id IS NOT NULL AND url IS NOT NULL
+ORDER BY id
+LIMIT $2
+`
+
+type ListItemURLsAfterParams struct {
+	ID    int32 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+type ListItemURLsAfterRow struct {
+	ID     int32   `json:"id"`
+	UserID *int32  `json:"user_id"`
+	Url    *string `json:"url"`
+}
+
+func (q *Queries) ListItemURLsAfter(ctx context.Context, arg ListItemURLsAfterParams) ([]ListItemURLsAfterRow, error) {
+	rows, err := q.db.Query(ctx, listItemURLsAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListItemURLsAfterRow{}
+	for rows.Next() {
+		var i ListItemURLsAfterRow
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Url); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markItemRead = `-- name: MarkItemRead :exec
+INSERT INTO item_reads (user_id, item_id) VALUES ($1, $2) ON CONFLICT (user_id, item_id) DO NOTHING
+`
+
+type MarkItemReadParams struct {
+	UserID int32 `json:"user_id"`
+	ItemID int32 `json:"item_id"`
+}
+
+func (q *Queries) MarkItemRead(ctx context.Context, arg MarkItemReadParams) error {
+	_, err := q.db.Exec(ctx, markItemRead, arg.UserID, arg.ItemID)
+	return err
+}
+
+const markItemUnread = `-- name: MarkItemUnread :exec
+DELETE FROM item_reads WHERE user_id = $1 AND item_id = $2
+`
+
+type MarkItemUnreadParams struct {
+	UserID int32 `json:"user_id"`
+	ItemID int32 `json:"item_id"`
+}
+
+func (q *Queries) MarkItemUnread(ctx context.Context, arg MarkItemUnreadParams) error {
+	_, err := q.db.Exec(ctx, markItemUnread, arg.UserID, arg.ItemID)
+	return err
+}
+
+const patchItem = `-- name: PatchItem :one
+UPDATE items
+SET
+  title = COALESCE(NULLIF($1, ''), title),
+  summary = CASE WHEN $2::text IS NULL THEN summary ELSE $2 END,
+  tags = CASE WHEN $3::text[] IS NULL THEN tags ELSE $3 END,
+  authors = CASE WHEN $4::text[] IS NULL THEN authors ELSE $4 END,
+  modified_at = CURRENT_TIMESTAMP
+WHERE id = $5
+RETURNING id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url
+`
+
+type PatchItemParams struct {
+	Title   interface{} `json:"title"`
+	Summary *string     `json:"summary"`
+	Tags    []string    `json:"tags"`
+	Authors []string    `json:"authors"`
+	ID      int32       `json:"id"`
+}
+
+func (q *Queries) PatchItem(ctx context.Context, arg PatchItemParams) (Item, error) {
+	row := q.db.QueryRow(ctx, patchItem,
+		arg.Title,
+		arg.Summary,
+		arg.Tags,
+		arg.Authors,
+		arg.ID,
+	)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.TextContent,
+		&i.Summary,
 		&i.Type,
 		&i.Tags,
 		&i.Platform,
@@ -503,19 +1527,125 @@ func (q *Queries) ToggleItemReadStatus(ctx context.Context, id int32) (Item, err
 		&i.Title,
 		&i.ProcessingStatus,
 		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
 	)
 	return i, err
 }
 
+const searchItems = `-- name: SearchItems :many
+SELECT items.id, items.user_id, items.url, items.text_content, items.summary, items.type, items.tags, items.platform, items.authors, items.created_at, items.modified_at, items.title, items.processing_status, items.processing_error, items.workspace_id, items.source_type, items.source_content, items.failure_reason, items.canonical_url,
+  ts_rank_cd(item_search.search_vector, q.query, 32)::real AS rank,
+  ts_headline('english', items.title, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+  ts_headline('english', COALESCE(items.summary, '') || ' ' || LEFT(COALESCE(items.text_content, ''), 20000), q.query,
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "') AS snippet,
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) AS is_read
+FROM items
+JOIN item_search ON item_search.item_id = items.id
+CROSS JOIN websearch_to_tsquery('english', $2) AS q(query)
+WHERE (items.user_id = $1 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1))
+  AND item_search.search_vector @@ q.query
+  AND ($3::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND ($4::text IS NULL OR lower(items.platform) = lower($4))
+  AND ($5::text IS NULL OR lower(items.type) = lower($5))
+  AND ($6::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1) = $6)
+  AND ($7::timestamptz IS NULL OR items.created_at >= $7)
+  AND ($8::timestamptz IS NULL OR items.created_at < $8)
+ORDER BY rank DESC, items.created_at DESC, items.id DESC
+LIMIT $9 OFFSET $10
+`
+
+type SearchItemsParams struct {
+	UserID        int32      `json:"user_id"`
+	Query         string     `json:"query"`
+	Tags          []string   `json:"tags"`
+	Platform      *string    `json:"platform"`
+	Type          *string    `json:"type"`
+	IsRead        *bool      `json:"is_read"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+	Limit         int32      `json:"limit"`
+	Offset        int32      `json:"offset"`
+}
+
+type SearchItemsRow struct {
+	Item           Item    `json:"item"`
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+	IsRead         bool    `json:"is_read"`
+}
+
+func (q *Queries) SearchItems(ctx context.Context, arg SearchItemsParams) ([]SearchItemsRow, error) {
+	rows, err := q.db.Query(ctx, searchItems,
+		arg.UserID,
+		arg.Query,
+		arg.Tags,
+		arg.Platform,
+		arg.Type,
+		arg.IsRead,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchItemsRow{}
+	for rows.Next() {
+		var i SearchItemsRow
+		if err := rows.Scan(
+			&i.Item.ID,
+			&i.Item.UserID,
+			&i.Item.Url,
+			&i.Item.TextContent,
+			&i.Item.Summary,
+			&i.Item.Type,
+			&i.Item.Tags,
+			&i.Item.Platform,
+			&i.Item.Authors,
+			&i.Item.CreatedAt,
+			&i.Item.ModifiedAt,
+			&i.Item.Title,
+			&i.Item.ProcessingStatus,
+			&i.Item.ProcessingError,
+			&i.Item.WorkspaceID,
+			&i.Item.SourceType,
+			&i.Item.SourceContent,
+			&i.Item.FailureReason,
+			&i.Item.CanonicalUrl,
+			&i.Rank,
+			&i.TitleHighlight,
+			&i.Snippet,
+			&i.IsRead,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateItem = `-- name: UpdateItem :exec
-UPDATE items SET title = $2, url = $3, is_read = $4, text_content = $5, summary = $6, type = $7, tags = $8, platform = $9, authors = $10, modified_at = CURRENT_TIMESTAMP WHERE id = $1
+UPDATE items SET title = $2, url = $3, text_content = $4, summary = $5, type = $6, tags = $7, platform = $8, authors = $9, modified_at = CURRENT_TIMESTAMP WHERE id = $1
 `
 
 type UpdateItemParams struct {
 	ID          int32    `json:"id"`
 	Title       string   `json:"title"`
 	Url         *string  `json:"url"`
-	IsRead      *bool    `json:"is_read"`
 	TextContent *string  `json:"text_content"`
 	Summary     *string  `json:"summary"`
 	Type        *string  `json:"type"`
@@ -529,7 +1659,6 @@ func (q *Queries) UpdateItem(ctx context.Context, arg UpdateItemParams) error {
 		arg.ID,
 		arg.Title,
 		arg.Url,
-		arg.IsRead,
 		arg.TextContent,
 		arg.Summary,
 		arg.Type,
@@ -549,17 +1678,73 @@ func (q *Queries) UpdateItemAsProcessing(ctx context.Context, id int32) error {
 	return err
 }
 
+const updateItemCanonicalURL = `-- name: UpdateItemCanonicalURL :exec
+UPDATE items SET canonical_url = $2, modified_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+type UpdateItemCanonicalURLParams struct {
+	ID           int32   `json:"id"`
+	CanonicalUrl *string `json:"canonical_url"`
+}
+
+func (q *Queries) UpdateItemCanonicalURL(ctx context.Context, arg UpdateItemCanonicalURLParams) error {
+	_, err := q.db.Exec(ctx, updateItemCanonicalURL, arg.ID, arg.CanonicalUrl)
+	return err
+}
+
 const updateItemProcessingStatus = `-- name: UpdateItemProcessingStatus :exec
-UPDATE items SET processing_status = $2, processing_error = $3, modified_at = CURRENT_TIMESTAMP WHERE id = $1
+UPDATE items SET processing_status = $2, processing_error = $3, failure_reason = $4, modified_at = CURRENT_TIMESTAMP WHERE id = $1
 `
 
 type UpdateItemProcessingStatusParams struct {
 	ID               int32   `json:"id"`
 	ProcessingStatus *string `json:"processing_status"`
 	ProcessingError  *string `json:"processing_error"`
+	FailureReason    *string `json:"failure_reason"`
 }
 
 func (q *Queries) UpdateItemProcessingStatus(ctx context.Context, arg UpdateItemProcessingStatusParams) error {
-	_, err := q.db.Exec(ctx, updateItemProcessingStatus, arg.ID, arg.ProcessingStatus, arg.ProcessingError)
+	_, err := q.db.Exec(ctx, updateItemProcessingStatus,
+		arg.ID,
+		arg.ProcessingStatus,
+		arg.ProcessingError,
+		arg.FailureReason,
+	)
 	return err
 }
+
+const updateItemWorkspace = `-- name: UpdateItemWorkspace :one
+UPDATE items SET workspace_id = $2, modified_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING id, user_id, url, text_content, summary, type, tags, platform, authors, created_at, modified_at, title, processing_status, processing_error, workspace_id, source_type, source_content, failure_reason, canonical_url
+`
+
+type UpdateItemWorkspaceParams struct {
+	ID          int32  `json:"id"`
+	WorkspaceID *int32 `json:"workspace_id"`
+}
+
+func (q *Queries) UpdateItemWorkspace(ctx context.Context, arg UpdateItemWorkspaceParams) (Item, error) {
+	row := q.db.QueryRow(ctx, updateItemWorkspace, arg.ID, arg.WorkspaceID)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.TextContent,
+		&i.Summary,
+		&i.Type,
+		&i.Tags,
+		&i.Platform,
+		&i.Authors,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Title,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.WorkspaceID,
+		&i.SourceType,
+		&i.SourceContent,
+		&i.FailureReason,
+		&i.CanonicalUrl,
+	)
+	return i, err
+}
@@ -10,11 +10,69 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiToken struct {
+	ID          int32      `json:"id"`
+	UserID      int32      `json:"user_id"`
+	Name        string     `json:"name"`
+	TokenHash   string     `json:"token_hash"`
+	TokenPrefix string     `json:"token_prefix"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+type Collection struct {
+	ID          int32      `json:"id"`
+	UserID      int32      `json:"user_id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+type CollectionItem struct {
+	CollectionID int32      `json:"collection_id"`
+	ItemID       int32      `json:"item_id"`
+	Position     int32      `json:"position"`
+	AddedAt      *time.Time `json:"added_at"`
+}
+
+type Feed struct {
+	ID                  int32      `json:"id"`
+	Url                 string     `json:"url"`
+	Title               *string    `json:"title"`
+	SiteUrl             *string    `json:"site_url"`
+	Etag                *string    `json:"etag"`
+	LastModified        *string    `json:"last_modified"`
+	LastPolledAt        *time.Time `json:"last_polled_at"`
+	NextPollAt          time.Time  `json:"next_poll_at"`
+	ConsecutiveFailures int32      `json:"consecutive_failures"`
+	LastError           *string    `json:"last_error"`
+	CreatedAt           *time.Time `json:"created_at"`
+	UpdatedAt           *time.Time `json:"updated_at"`
+}
+
+type FeedEntry struct {
+	FeedID      int32      `json:"feed_id"`
+	Guid        string     `json:"guid"`
+	Url         *string    `json:"url"`
+	Title       *string    `json:"title"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+type FeedSubscription struct {
+	FeedID    int32      `json:"feed_id"`
+	UserID    int32      `json:"user_id"`
+	Title     *string    `json:"title"`
+	CreatedAt *time.Time `json:"created_at"`
+	Tags      []string   `json:"tags"`
+}
+
 type Item struct {
 	ID               int32      `json:"id"`
 	UserID           *int32     `json:"user_id"`
 	Url              *string    `json:"url"`
-	IsRead           *bool      `json:"is_read"`
 	TextContent      *string    `json:"text_content"`
 	Summary          *string    `json:"summary"`
 	Type             *string    `json:"type"`
@@ -26,6 +84,39 @@ type Item struct {
 	Title            string     `json:"title"`
 	ProcessingStatus *string    `json:"processing_status"`
 	ProcessingError  *string    `json:"processing_error"`
+	WorkspaceID      *int32     `json:"workspace_id"`
+	SourceType       string     `json:"source_type"`
+	SourceContent    *string    `json:"source_content"`
+	FailureReason    *string    `json:"failure_reason"`
+	CanonicalUrl     *string    `json:"canonical_url"`
+}
+
+type ItemEmbedding struct {
+	ItemID    int32     `json:"item_id"`
+	Model     string    `json:"model"`
+	Embedding []float32 `json:"embedding"`
+	Error     *string   `json:"error"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ItemSearch struct {
+	ItemID       int32  `json:"item_id"`
+	SearchVector string `json:"-"`
+}
+
+type ItemRead struct {
+	UserID int32     `json:"user_id"`
+	ItemID int32     `json:"item_id"`
+	ReadAt time.Time `json:"read_at"`
+}
+
+type PasswordResetToken struct {
+	ID        int32      `json:"id"`
+	UserID    int32      `json:"user_id"`
+	TokenHash string     `json:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt *time.Time `json:"created_at"`
 }
 
 type Podcast struct {
@@ -50,13 +141,73 @@ type PodcastItem struct {
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+type RateLimitBucket struct {
+	BucketKey string    `json:"bucket_key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Tag struct {
+	ID        int32      `json:"id"`
+	UserID    int32      `json:"user_id"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type TagAlias struct {
+	UserID    int32      `json:"user_id"`
+	Alias     string     `json:"alias"`
+	TagID     int32      `json:"tag_id"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type UsageCounter struct {
+	UserID         int32       `json:"user_id"`
+	Day            pgtype.Date `json:"day"`
+	Items          int32       `json:"items"`
+	PodcastSeconds int32       `json:"podcast_seconds"`
+}
+
 type User struct {
-	ID           int32      `json:"id"`
-	Name         *string    `json:"name"`
-	Email        *string    `json:"email"`
-	AuthProvider *string    `json:"auth_provider"`
-	OauthID      *string    `json:"oauth_id"`
-	PasswordHash *string    `json:"password_hash"`
-	CreatedAt    *time.Time `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at"`
+	ID             int32      `json:"id"`
+	Name           *string    `json:"name"`
+	Email          *string    `json:"email"`
+	AuthProvider   *string    `json:"auth_provider"`
+	OauthID        *string    `json:"oauth_id"`
+	PasswordHash   *string    `json:"password_hash"`
+	CreatedAt      *time.Time `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+	EmailVerified  bool       `json:"email_verified"`
+	SessionVersion int32      `json:"session_version"`
+}
+
+type UserPreference struct {
+	UserID           int32      `json:"user_id"`
+	Timezone         string     `json:"timezone"`
+	DigestTime       string     `json:"digest_time"`
+	DigestFrequency  string     `json:"digest_frequency"`
+	PodcastEnabled   *bool      `json:"podcast_enabled"`
+	HostVoice        string     `json:"host_voice"`
+	CohostVoice      string     `json:"cohost_voice"`
+	SpeechSpeed      float64    `json:"speech_speed"`
+	SummaryLength    string     `json:"summary_length"`
+	SummaryLanguage  string     `json:"summary_language"`
+	LastDigestSentAt *time.Time `json:"last_digest_sent_at"`
+	CreatedAt        *time.Time `json:"created_at"`
+	UpdatedAt        *time.Time `json:"updated_at"`
+}
+
+type Workspace struct {
+	ID        int32      `json:"id"`
+	Name      string     `json:"name"`
+	CreatedBy *int32     `json:"created_by"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID int32      `json:"workspace_id"`
+	UserID      int32      `json:"user_id"`
+	Role        string     `json:"role"`
+	CreatedAt   *time.Time `json:"created_at"`
 }
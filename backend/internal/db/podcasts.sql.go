@@ -136,6 +136,26 @@ func (q *Queries) DeletePodcast(ctx context.Context, id int32) error {
 	return err
 }
 
+const deletePodcastItemsByUser = `-- name: DeletePodcastItemsByUser :exec
+DELETE FROM podcast_items
+WHERE podcast_id IN (SELECT id FROM podcasts WHERE podcasts.user_id = $1)
+   OR item_id IN (SELECT id FROM items WHERE items.user_id = $1)
+`
+
+func (q *Queries) DeletePodcastItemsByUser(ctx context.Context, userID *int32) error {
+	_, err := q.db.Exec(ctx, deletePodcastItemsByUser, userID)
+	return err
+}
+
+const deletePodcastsByUser = `-- name: DeletePodcastsByUser :exec
+DELETE FROM podcasts WHERE user_id = $1
+`
+
+func (q *Queries) DeletePodcastsByUser(ctx context.Context, userID *int32) error {
+	_, err := q.db.Exec(ctx, deletePodcastsByUser, userID)
+	return err
+}
+
 const getCompletedPodcasts = `-- name: GetCompletedPodcasts :many
 SELECT id, user_id, title, description, status, audio_url, dialogues, duration_seconds, created_at, updated_at, completed_at FROM podcasts WHERE status = 'completed' ORDER BY created_at DESC LIMIT $1
 `
@@ -271,6 +291,34 @@ func (q *Queries) GetPodcastByUser(ctx context.Context, userID *int32) ([]Podcas
 	return items, nil
 }
 
+const getPodcastForUser = `-- name: GetPodcastForUser :one
+SELECT id, user_id, title, description, status, audio_url, dialogues, duration_seconds, created_at, updated_at, completed_at FROM podcasts WHERE id = $1 AND user_id = $2
+`
+
+type GetPodcastForUserParams struct {
+	ID     int32  `json:"id"`
+	UserID *int32 `json:"user_id"`
+}
+
+func (q *Queries) GetPodcastForUser(ctx context.Context, arg GetPodcastForUserParams) (Podcast, error) {
+	row := q.db.QueryRow(ctx, getPodcastForUser, arg.ID, arg.UserID)
+	var i Podcast
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.AudioUrl,
+		&i.Dialogues,
+		&i.DurationSeconds,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
 const getPodcastItemIDs = `-- name: GetPodcastItemIDs :many
 SELECT item_id FROM podcast_items WHERE podcast_id = $1 ORDER BY item_order ASC
 `
@@ -296,7 +344,7 @@ func (q *Queries) GetPodcastItemIDs(ctx context.Context, podcastID *int32) ([]*i
 }
 
 const getPodcastItems = `-- name: GetPodcastItems :many
-SELECT items.id, items.user_id, items.url, items.is_read, items.text_content, items.summary, items.type, items.tags, items.platform, items.authors, items.created_at, items.modified_at, items.title, items.processing_status, items.processing_error, podcast_items.item_order 
+SELECT items.id, items.user_id, items.url, items.text_content, items.summary, items.type, items.tags, items.platform, items.authors, items.created_at, items.modified_at, items.title, items.processing_status, items.processing_error, items.workspace_id, items.source_type, items.source_content, items.failure_reason, items.canonical_url, podcast_items.item_order 
 FROM items 
 JOIN podcast_items ON items.id = podcast_items.item_id 
 WHERE podcast_items.podcast_id = $1 
@@ -307,7 +355,6 @@ type GetPodcastItemsRow struct {
 	ID               int32      `json:"id"`
 	UserID           *int32     `json:"user_id"`
 	Url              *string    `json:"url"`
-	IsRead           *bool      `json:"is_read"`
 	TextContent      *string    `json:"text_content"`
 	Summary          *string    `json:"summary"`
 	Type             *string    `json:"type"`
@@ -319,6 +366,11 @@ type GetPodcastItemsRow struct {
 	Title            string     `json:"title"`
 	ProcessingStatus *string    `json:"processing_status"`
 	ProcessingError  *string    `json:"processing_error"`
+	WorkspaceID      *int32     `json:"workspace_id"`
+	SourceType       string     `json:"source_type"`
+	SourceContent    *string    `json:"source_content"`
+	FailureReason    *string    `json:"failure_reason"`
+	CanonicalUrl     *string    `json:"canonical_url"`
 	ItemOrder        int32      `json:"item_order"`
 }
 
@@ -335,7 +387,6 @@ func (q *Queries) GetPodcastItems(ctx context.Context, podcastID *int32) ([]GetP
 			&i.ID,
 			&i.UserID,
 			&i.Url,
-			&i.IsRead,
 			&i.TextContent,
 			&i.Summary,
 			&i.Type,
@@ -347,6 +398,11 @@ func (q *Queries) GetPodcastItems(ctx context.Context, podcastID *int32) ([]GetP
 			&i.Title,
 			&i.ProcessingStatus,
 			&i.ProcessingError,
+			&i.WorkspaceID,
+			&i.SourceType,
+			&i.SourceContent,
+			&i.FailureReason,
+			&i.CanonicalUrl,
 			&i.ItemOrder,
 		); err != nil {
 			return nil, err
@@ -637,6 +693,254 @@ func (q *Queries) GetUserPodcastStats(ctx context.Context, userID *int32) (GetUs
 	return i, err
 }
 
+const listPodcastsPageByCreatedAsc = `-- name: ListPodcastsPageByCreatedAsc :many
+SELECT id, user_id, title, description, status, audio_url, dialogues, duration_seconds, created_at, updated_at, completed_at FROM podcasts
+WHERE user_id = $1
+  AND ($2::text IS NULL OR status = $2)
+  AND ($3::timestamp IS NULL OR created_at >= $3)
+  AND ($4::timestamp IS NULL OR created_at < $4)
+  AND ($5::int IS NULL OR (created_at, id) > ($6::timestamp, $5))
+ORDER BY created_at ASC, id ASC
+LIMIT $7
+`
+
+type ListPodcastsPageByCreatedAscParams struct {
+	UserID          *int32           `json:"user_id"`
+	Status          *string          `json:"status"`
+	CreatedAfter    pgtype.Timestamp `json:"created_after"`
+	CreatedBefore   pgtype.Timestamp `json:"created_before"`
+	CursorID        *int32           `json:"cursor_id"`
+	CursorCreatedAt pgtype.Timestamp `json:"cursor_created_at"`
+	Limit           int32            `json:"limit"`
+}
+
+// One page of a user's podcasts, oldest first, after the cursor
+func (q *Queries) ListPodcastsPageByCreatedAsc(ctx context.Context, arg ListPodcastsPageByCreatedAscParams) ([]Podcast, error) {
+	rows, err := q.db.Query(ctx, listPodcastsPageByCreatedAsc,
+		arg.UserID,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Podcast{}
+	for rows.Next() {
+		var i Podcast
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.AudioUrl,
+			&i.Dialogues,
+			&i.DurationSeconds,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPodcastsPageByCreatedDesc = `-- name: ListPodcastsPageByCreatedDesc :many
+SELECT id, user_id, title, description, status, audio_url, dialogues, duration_seconds, created_at, updated_at, completed_at FROM podcasts
+WHERE user_id = $1
+  AND ($2::text IS NULL OR status = $2)
+  AND ($3::timestamp IS NULL OR created_at >= $3)
+  AND ($4::timestamp IS NULL OR created_at < $4)
+  AND ($5::int IS NULL OR (created_at, id) < ($6::timestamp, $5))
+ORDER BY created_at DESC, id DESC
+LIMIT $7
+`
+
+type ListPodcastsPageByCreatedDescParams struct {
+	UserID          *int32           `json:"user_id"`
+	Status          *string          `json:"status"`
+	CreatedAfter    pgtype.Timestamp `json:"created_after"`
+	CreatedBefore   pgtype.Timestamp `json:"created_before"`
+	CursorID        *int32           `json:"cursor_id"`
+	CursorCreatedAt pgtype.Timestamp `json:"cursor_created_at"`
+	Limit           int32            `json:"limit"`
+}
+
+// One page of a user's podcasts, newest first, after the cursor
+func (q *Queries) ListPodcastsPageByCreatedDesc(ctx context.Context, arg ListPodcastsPageByCreatedDescParams) ([]Podcast, error) {
+	rows, err := q.db.Query(ctx, listPodcastsPageByCreatedDesc,
+		arg.UserID,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Podcast{}
+	for rows.Next() {
+		var i Podcast
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.AudioUrl,
+			&i.Dialogues,
+			&i.DurationSeconds,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPodcastsPageByTitleAsc = `-- name: ListPodcastsPageByTitleAsc :many
+SELECT id, user_id, title, description, status, audio_url, dialogues, duration_seconds, created_at, updated_at, completed_at FROM podcasts
+WHERE user_id = $1
+  AND ($2::text IS NULL OR status = $2)
+  AND ($3::timestamp IS NULL OR created_at >= $3)
+  AND ($4::timestamp IS NULL OR created_at < $4)
+  AND ($5::int IS NULL OR (title, id) > ($6::text, $5))
+ORDER BY title ASC, id ASC
+LIMIT $7
+`
+
+type ListPodcastsPageByTitleAscParams struct {
+	UserID        *int32           `json:"user_id"`
+	Status        *string          `json:"status"`
+	CreatedAfter  pgtype.Timestamp `json:"created_after"`
+	CreatedBefore pgtype.Timestamp `json:"created_before"`
+	CursorID      *int32           `json:"cursor_id"`
+	CursorTitle   *string          `json:"cursor_title"`
+	Limit         int32            `json:"limit"`
+}
+
+// One page of a user's podcasts, by title A to Z, after the cursor
+func (q *Queries) ListPodcastsPageByTitleAsc(ctx context.Context, arg ListPodcastsPageByTitleAscParams) ([]Podcast, error) {
+	rows, err := q.db.Query(ctx, listPodcastsPageByTitleAsc,
+		arg.UserID,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorTitle,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Podcast{}
+	for rows.Next() {
+		var i Podcast
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.AudioUrl,
+			&i.Dialogues,
+			&i.DurationSeconds,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPodcastsPageByTitleDesc = `-- name: ListPodcastsPageByTitleDesc :many
+SELECT id, user_id, title, description, status, audio_url, dialogues, duration_seconds, created_at, updated_at, completed_at FROM podcasts
+WHERE user_id = $1
+  AND ($2::text IS NULL OR status = $2)
+  AND ($3::timestamp IS NULL OR created_at >= $3)
+  AND ($4::timestamp IS NULL OR created_at < $4)
+  AND ($5::int IS NULL OR (title, id) < ($6::text, $5))
+ORDER BY title DESC, id DESC
+LIMIT $7
+`
+
+type ListPodcastsPageByTitleDescParams struct {
+	UserID        *int32           `json:"user_id"`
+	Status        *string          `json:"status"`
+	CreatedAfter  pgtype.Timestamp `json:"created_after"`
+	CreatedBefore pgtype.Timestamp `json:"created_before"`
+	CursorID      *int32           `json:"cursor_id"`
+	CursorTitle   *string          `json:"cursor_title"`
+	Limit         int32            `json:"limit"`
+}
+
+// One page of a user's podcasts, by title Z to A, after the cursor
+func (q *Queries) ListPodcastsPageByTitleDesc(ctx context.Context, arg ListPodcastsPageByTitleDescParams) ([]Podcast, error) {
+	rows, err := q.db.Query(ctx, listPodcastsPageByTitleDesc,
+		arg.UserID,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorID,
+		arg.CursorTitle,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Podcast{}
+	for rows.Next() {
+		var i Podcast
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.AudioUrl,
+			&i.Dialogues,
+			&i.DurationSeconds,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const removeItemFromPodcast = `-- name: RemoveItemFromPodcast :exec
 DELETE FROM podcast_items WHERE podcast_id = $1 AND item_id = $2
 `
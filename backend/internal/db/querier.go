@@ -6,29 +6,72 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	AddItemTags(ctx context.Context, arg AddItemTagsParams) error
+	AddItemToCollection(ctx context.Context, arg AddItemToCollectionParams) error
 	AddItemToPodcast(ctx context.Context, arg AddItemToPodcastParams) (PodcastItem, error)
+	AddPodcastUsage(ctx context.Context, arg AddPodcastUsageParams) error
+	AddWorkspaceMember(ctx context.Context, arg AddWorkspaceMemberParams) (WorkspaceMember, error)
+	BackfillItemCanonicalURL(ctx context.Context, arg BackfillItemCanonicalURLParams) (int64, error)
 	ClearPodcastItems(ctx context.Context, podcastID *int32) error
+	CountItemsVisibleToUser(ctx context.Context, arg CountItemsVisibleToUserParams) (int64, error)
 	CountPodcastItems(ctx context.Context, podcastID *int32) (int64, error)
+	CountWorkspaceOwners(ctx context.Context, workspaceID int32) (int64, error)
+	CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error)
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error)
+	CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error)
+	CreateFeedSubscription(ctx context.Context, arg CreateFeedSubscriptionParams) (FeedSubscription, error)
 	CreateItem(ctx context.Context, arg CreateItemParams) (Item, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
 	CreatePendingItem(ctx context.Context, arg CreatePendingItemParams) (Item, error)
+	CreatePendingSourceItem(ctx context.Context, arg CreatePendingSourceItemParams) (Item, error)
 	CreatePodcast(ctx context.Context, arg CreatePodcastParams) (Podcast, error)
 	CreatePodcastWithDialogues(ctx context.Context, arg CreatePodcastWithDialoguesParams) (Podcast, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) (Workspace, error)
+	DeleteAPIToken(ctx context.Context, arg DeleteAPITokenParams) (int64, error)
+	DeleteAPITokensByUser(ctx context.Context, userID int32) error
+	DeleteCollection(ctx context.Context, arg DeleteCollectionParams) (int64, error)
+	DeleteFeedSubscription(ctx context.Context, arg DeleteFeedSubscriptionParams) (int64, error)
 	DeleteItem(ctx context.Context, id int32) error
+	DeleteItemsByUser(ctx context.Context, userID *int32) error
+	DeletePasswordResetTokensByUser(ctx context.Context, userID int32) error
 	DeletePodcast(ctx context.Context, id int32) error
+	DeletePodcastItemsByUser(ctx context.Context, userID *int32) error
+	DeletePodcastsByUser(ctx context.Context, userID *int32) error
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt time.Time) error
+	DeleteTagAlias(ctx context.Context, arg DeleteTagAliasParams) (int64, error)
+	DeleteTagsByName(ctx context.Context, arg DeleteTagsByNameParams) error
 	DeleteUser(ctx context.Context, id int32) error
+	DeleteWorkspace(ctx context.Context, id int32) error
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (ApiToken, error)
+	GetCollectionByUser(ctx context.Context, arg GetCollectionByUserParams) (Collection, error)
 	GetCompletedPodcasts(ctx context.Context, limit int32) ([]Podcast, error)
 	GetFailedItemsForRetry(ctx context.Context, limit int32) ([]Item, error)
+	GetFeed(ctx context.Context, id int32) (Feed, error)
+	GetFeedByURL(ctx context.Context, url string) (Feed, error)
+	GetFeedSubscription(ctx context.Context, arg GetFeedSubscriptionParams) (FeedSubscription, error)
 	GetItem(ctx context.Context, id int32) (Item, error)
+	GetItemByCanonicalURL(ctx context.Context, arg GetItemByCanonicalURLParams) (Item, error)
+	GetItemEmbedding(ctx context.Context, arg GetItemEmbeddingParams) (ItemEmbedding, error)
+	GetItemForUser(ctx context.Context, arg GetItemForUserParams) (Item, error)
+	GetItemReadsByUser(ctx context.Context, userID int32) ([]GetItemReadsByUserRow, error)
+	GetItemVisibleToUser(ctx context.Context, arg GetItemVisibleToUserParams) (Item, error)
 	GetItemsByProcessingStatus(ctx context.Context, processingStatus *string) ([]Item, error)
 	GetItemsByUser(ctx context.Context, userID *int32) ([]Item, error)
+	GetItemsByUserAndProcessingStatus(ctx context.Context, arg GetItemsByUserAndProcessingStatusParams) ([]Item, error)
+	GetItemsByWorkspace(ctx context.Context, workspaceID *int32) ([]Item, error)
+	GetItemsOwnedByUser(ctx context.Context, userID *int32) ([]Item, error)
+	GetItemsVisibleToUserByIDs(ctx context.Context, arg GetItemsVisibleToUserByIDsParams) ([]GetItemsVisibleToUserByIDsRow, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (PasswordResetToken, error)
 	GetPendingItems(ctx context.Context, limit int32) ([]Item, error)
 	GetPendingPodcasts(ctx context.Context, limit int32) ([]Podcast, error)
 	GetPodcast(ctx context.Context, id int32) (Podcast, error)
 	GetPodcastByUser(ctx context.Context, userID *int32) ([]Podcast, error)
+	GetPodcastForUser(ctx context.Context, arg GetPodcastForUserParams) (Podcast, error)
 	GetPodcastItemIDs(ctx context.Context, podcastID *int32) ([]*int32, error)
 	GetPodcastItems(ctx context.Context, podcastID *int32) ([]GetPodcastItemsRow, error)
 	GetPodcastWithItems(ctx context.Context, id int32) (GetPodcastWithItemsRow, error)
@@ -36,21 +79,77 @@ type Querier interface {
 	GetPodcastsByUserAndStatus(ctx context.Context, arg GetPodcastsByUserAndStatusParams) ([]Podcast, error)
 	GetPodcastsForItem(ctx context.Context, itemID *int32) ([]Podcast, error)
 	GetProcessingPodcasts(ctx context.Context, limit int32) ([]Podcast, error)
+	GetRateLimitTokens(ctx context.Context, arg GetRateLimitTokensParams) (float64, error)
+	GetReadItemIDs(ctx context.Context, arg GetReadItemIDsParams) ([]int32, error)
 	GetRecentPodcasts(ctx context.Context, limit int32) ([]Podcast, error)
+	GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error)
 	GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]Item, error)
+	GetUnreadItemsByUserInRange(ctx context.Context, arg GetUnreadItemsByUserInRangeParams) ([]Item, error)
+	GetUnreadItemsByWorkspace(ctx context.Context, arg GetUnreadItemsByWorkspaceParams) ([]Item, error)
 	GetUnreadItemsFromPreviousDay(ctx context.Context) ([]Item, error)
 	GetUnreadItemsFromPreviousDayByUser(ctx context.Context, userID *int32) ([]Item, error)
+	GetUsageCounter(ctx context.Context, arg GetUsageCounterParams) (UsageCounter, error)
 	GetUser(ctx context.Context, id int32) (User, error)
 	GetUserByEmail(ctx context.Context, email *string) (User, error)
+	GetUserByOAuthID(ctx context.Context, arg GetUserByOAuthIDParams) (User, error)
 	GetUserPodcastStats(ctx context.Context, userID *int32) (GetUserPodcastStatsRow, error)
+	GetUserPreferences(ctx context.Context, userID int32) (UserPreference, error)
+	GetWorkspace(ctx context.Context, id int32) (Workspace, error)
+	GetWorkspaceMember(ctx context.Context, arg GetWorkspaceMemberParams) (WorkspaceMember, error)
+	IncrementItemUsage(ctx context.Context, arg IncrementItemUsageParams) (int32, error)
+	InsertFeedEntry(ctx context.Context, arg InsertFeedEntryParams) (int64, error)
+	IsItemRead(ctx context.Context, arg IsItemReadParams) (bool, error)
+	LinkUserOAuthIdentity(ctx context.Context, arg LinkUserOAuthIdentityParams) (User, error)
+	ListAPITokensByUser(ctx context.Context, userID int32) ([]ApiToken, error)
+	ListCollectionItems(ctx context.Context, arg ListCollectionItemsParams) ([]ListCollectionItemsRow, error)
+	ListCollectionsByUser(ctx context.Context, userID int32) ([]ListCollectionsByUserRow, error)
+	ListCollectionsForItem(ctx context.Context, arg ListCollectionsForItemParams) ([]Collection, error)
+	ListDueFeeds(ctx context.Context, arg ListDueFeedsParams) ([]Feed, error)
+	ListFeedEntryGUIDs(ctx context.Context, arg ListFeedEntryGUIDsParams) ([]string, error)
+	ListFeedSubscribers(ctx context.Context, feedID int32) ([]ListFeedSubscribersRow, error)
+	ListFeedSubscriptionsByUser(ctx context.Context, userID int32) ([]ListFeedSubscriptionsByUserRow, error)
+	ListItemEmbeddingsVisibleToUser(ctx context.Context, arg ListItemEmbeddingsVisibleToUserParams) ([]ListItemEmbeddingsVisibleToUserRow, error)
+	ListItemURLsAfter(ctx context.Context, arg ListItemURLsAfterParams) ([]ListItemURLsAfterRow, error)
+	ListItemsPageByCreatedAsc(ctx context.Context, arg ListItemsPageByCreatedAscParams) ([]ListItemsPageByCreatedAscRow, error)
+	ListItemsPageByCreatedDesc(ctx context.Context, arg ListItemsPageByCreatedDescParams) ([]ListItemsPageByCreatedDescRow, error)
+	ListItemsPageByTitleAsc(ctx context.Context, arg ListItemsPageByTitleAscParams) ([]ListItemsPageByTitleAscRow, error)
+	ListItemsPageByTitleDesc(ctx context.Context, arg ListItemsPageByTitleDescParams) ([]ListItemsPageByTitleDescRow, error)
+	ListItemsToEmbed(ctx context.Context, arg ListItemsToEmbedParams) ([]Item, error)
+	ListPodcastsPageByCreatedAsc(ctx context.Context, arg ListPodcastsPageByCreatedAscParams) ([]Podcast, error)
+	ListPodcastsPageByCreatedDesc(ctx context.Context, arg ListPodcastsPageByCreatedDescParams) ([]Podcast, error)
+	ListPodcastsPageByTitleAsc(ctx context.Context, arg ListPodcastsPageByTitleAscParams) ([]Podcast, error)
+	ListPodcastsPageByTitleDesc(ctx context.Context, arg ListPodcastsPageByTitleDescParams) ([]Podcast, error)
+	ListTagAliasesByUser(ctx context.Context, userID int32) ([]ListTagAliasesByUserRow, error)
+	ListTagCounts(ctx context.Context, userID *int32) ([]ListTagCountsRow, error)
+	ListTagsByUser(ctx context.Context, userID int32) ([]Tag, error)
 	ListUsers(ctx context.Context) ([]User, error)
-	MarkItemAsRead(ctx context.Context, id int32) error
+	ListWorkspaceMembers(ctx context.Context, workspaceID int32) ([]ListWorkspaceMembersRow, error)
+	ListWorkspacesByUser(ctx context.Context, userID int32) ([]ListWorkspacesByUserRow, error)
+	MarkItemRead(ctx context.Context, arg MarkItemReadParams) error
+	MarkItemUnread(ctx context.Context, arg MarkItemUnreadParams) error
+	MarkPasswordResetTokenUsed(ctx context.Context, id int32) (int64, error)
+	MarkUserEmailVerified(ctx context.Context, id int32) error
 	PatchItem(ctx context.Context, arg PatchItemParams) (Item, error)
+	RemoveItemFromCollection(ctx context.Context, arg RemoveItemFromCollectionParams) (int64, error)
 	RemoveItemFromPodcast(ctx context.Context, arg RemoveItemFromPodcastParams) error
-	ToggleItemReadStatus(ctx context.Context, id int32) (Item, error)
+	RemoveWorkspaceMember(ctx context.Context, arg RemoveWorkspaceMemberParams) (int64, error)
+	ReplaceItemTags(ctx context.Context, arg ReplaceItemTagsParams) (int64, error)
+	ResolveTagAliases(ctx context.Context, arg ResolveTagAliasesParams) ([]ResolveTagAliasesRow, error)
+	RetargetTagAliases(ctx context.Context, arg RetargetTagAliasesParams) error
+	SearchItems(ctx context.Context, arg SearchItemsParams) ([]SearchItemsRow, error)
+	SetCollectionItemPositions(ctx context.Context, arg SetCollectionItemPositionsParams) error
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (float64, error)
+	TouchAPIToken(ctx context.Context, id int32) error
+	UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (Collection, error)
+	UpdateFeedPollFailed(ctx context.Context, arg UpdateFeedPollFailedParams) error
+	UpdateFeedPolled(ctx context.Context, arg UpdateFeedPolledParams) error
+	UpdateFeedSubscription(ctx context.Context, arg UpdateFeedSubscriptionParams) (FeedSubscription, error)
 	UpdateItem(ctx context.Context, arg UpdateItemParams) error
 	UpdateItemAsProcessing(ctx context.Context, id int32) error
+	UpdateItemCanonicalURL(ctx context.Context, arg UpdateItemCanonicalURLParams) error
 	UpdateItemProcessingStatus(ctx context.Context, arg UpdateItemProcessingStatusParams) error
+	UpdateItemWorkspace(ctx context.Context, arg UpdateItemWorkspaceParams) (Item, error)
+	UpdateLastDigestSent(ctx context.Context, arg UpdateLastDigestSentParams) error
 	UpdatePodcast(ctx context.Context, arg UpdatePodcastParams) error
 	UpdatePodcastAudio(ctx context.Context, arg UpdatePodcastAudioParams) error
 	UpdatePodcastDialogues(ctx context.Context, arg UpdatePodcastDialoguesParams) error
@@ -59,6 +158,14 @@ type Querier interface {
 	UpdatePodcastStatusWithAudio(ctx context.Context, arg UpdatePodcastStatusWithAudioParams) error
 	UpdatePodcastsStatus(ctx context.Context, arg UpdatePodcastsStatusParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) error
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateWorkspaceMemberRole(ctx context.Context, arg UpdateWorkspaceMemberRoleParams) (WorkspaceMember, error)
+	UpdateWorkspaceName(ctx context.Context, arg UpdateWorkspaceNameParams) (Workspace, error)
+	UpsertItemEmbedding(ctx context.Context, arg UpsertItemEmbeddingParams) error
+	UpsertTag(ctx context.Context, arg UpsertTagParams) (Tag, error)
+	UpsertTagAlias(ctx context.Context, arg UpsertTagAliasParams) error
+	UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error)
+	UserHasTag(ctx context.Context, arg UserHasTagParams) (bool, error)
 }
 
 var _ Querier = (*Queries)(nil)
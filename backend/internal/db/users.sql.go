@@ -10,15 +10,16 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (name, email, auth_provider, oauth_id, password_hash) VALUES ($1, $2, $3, $4, $5) RETURNING id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at
+INSERT INTO users (name, email, auth_provider, oauth_id, password_hash, email_verified) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at, email_verified, session_version
 `
 
 type CreateUserParams struct {
-	Name         *string `json:"name"`
-	Email        *string `json:"email"`
-	AuthProvider *string `json:"auth_provider"`
-	OauthID      *string `json:"oauth_id"`
-	PasswordHash *string `json:"password_hash"`
+	Name          *string `json:"name"`
+	Email         *string `json:"email"`
+	AuthProvider  *string `json:"auth_provider"`
+	OauthID       *string `json:"oauth_id"`
+	PasswordHash  *string `json:"password_hash"`
+	EmailVerified bool    `json:"email_verified"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -28,6 +29,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.AuthProvider,
 		arg.OauthID,
 		arg.PasswordHash,
+		arg.EmailVerified,
 	)
 	var i User
 	err := row.Scan(
@@ -39,6 +41,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
 	)
 	return i, err
 }
@@ -53,7 +57,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at FROM users WHERE id = $1
+SELECT id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at, email_verified, session_version FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
@@ -68,12 +72,14 @@ func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at FROM users WHERE email = $1
+SELECT id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at, email_verified, session_version FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email *string) (User, error) {
@@ -88,12 +94,69 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email *string) (User, erro
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
+	)
+	return i, err
+}
+
+const getUserByOAuthID = `-- name: GetUserByOAuthID :one
+SELECT id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at, email_verified, session_version FROM users WHERE auth_provider = $1 AND oauth_id = $2
+`
+
+type GetUserByOAuthIDParams struct {
+	AuthProvider *string `json:"auth_provider"`
+	OauthID      *string `json:"oauth_id"`
+}
+
+func (q *Queries) GetUserByOAuthID(ctx context.Context, arg GetUserByOAuthIDParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByOAuthID, arg.AuthProvider, arg.OauthID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.AuthProvider,
+		&i.OauthID,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
+	)
+	return i, err
+}
+
+const linkUserOAuthIdentity = `-- name: LinkUserOAuthIdentity :one
+UPDATE users SET auth_provider = $2, oauth_id = $3, email_verified = TRUE, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at, email_verified, session_version
+`
+
+type LinkUserOAuthIdentityParams struct {
+	ID           int32   `json:"id"`
+	AuthProvider *string `json:"auth_provider"`
+	OauthID      *string `json:"oauth_id"`
+}
+
+func (q *Queries) LinkUserOAuthIdentity(ctx context.Context, arg LinkUserOAuthIdentityParams) (User, error) {
+	row := q.db.QueryRow(ctx, linkUserOAuthIdentity, arg.ID, arg.AuthProvider, arg.OauthID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.AuthProvider,
+		&i.OauthID,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.SessionVersion,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at FROM users ORDER BY created_at DESC
+SELECT id, name, email, auth_provider, oauth_id, password_hash, created_at, updated_at, email_verified, session_version FROM users ORDER BY created_at DESC
 `
 
 func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
@@ -114,6 +177,8 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.PasswordHash,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EmailVerified,
+			&i.SessionVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -125,27 +190,47 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :exec
+UPDATE users SET email_verified = TRUE, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, markUserEmailVerified, id)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :exec
-UPDATE users SET name = $2, email = $3, auth_provider = $4, oauth_id = $5, password_hash = $6, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+UPDATE users
+SET name = $2,
+    email = $3,
+    email_verified = email_verified AND email IS NOT DISTINCT FROM $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
 `
 
 type UpdateUserParams struct {
+	ID    int32   `json:"id"`
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+// Changing the email clears its verified state
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
+	_, err := q.db.Exec(ctx, updateUser, arg.ID, arg.Name, arg.Email)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2, session_version = session_version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
 	ID           int32   `json:"id"`
-	Name         *string `json:"name"`
-	Email        *string `json:"email"`
-	AuthProvider *string `json:"auth_provider"`
-	OauthID      *string `json:"oauth_id"`
 	PasswordHash *string `json:"password_hash"`
 }
 
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
-	_, err := q.db.Exec(ctx, updateUser,
-		arg.ID,
-		arg.Name,
-		arg.Email,
-		arg.AuthProvider,
-		arg.OauthID,
-		arg.PasswordHash,
-	)
+// Bumping the session version signs out every session issued before the change
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
 	return err
 }
@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/middleware"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// oidcStateCookie holds the OIDC login state between the redirect to the
+// identity provider and the callback
+const oidcStateCookie = "briefbot_oidc_state"
+
+// AuthHandler handles login, registration and API token HTTP requests
+type AuthHandler struct {
+	authService          services.AuthService
+	oidcService          services.OIDCService
+	postLoginRedirectURL string
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authService services.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// SetOIDCService enables OIDC sign-in. When postLoginRedirectURL is set the
+// callback redirects there with the session in the URL fragment instead of
+// responding with JSON.
+func (h *AuthHandler) SetOIDCService(oidcService services.OIDCService, postLoginRedirectURL string) {
+	h.oidcService = oidcService
+	h.postLoginRedirectURL = postLoginRedirectURL
+}
+
+// currentUserID returns the authenticated user's ID, writing a 401 response
+// when the request has no authenticated user
+func currentUserID(c *gin.Context) (int32, bool) {
+	user, ok := middleware.AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return 0, false
+	}
+	return user.ID, true
+}
+
+// Register godoc
+// @Summary      Register a new account
+// @Description  Create an email/password account and return a session token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        account  body      RegisterRequest  true  "Registration request"
+// @Success      201      {object}  AuthResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.authService.Register(c.Request.Context(), req.Name, req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.authService.IssueSession(c.Request.Context(), user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, newAuthResponse(session))
+}
+
+// Login godoc
+// @Summary      Log in
+// @Description  Verify an email and password and return a session token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      LoginRequest  true  "Login request"
+// @Success      200          {object}  AuthResponse
+// @Failure      400          {object}  ErrorResponse
+// @Failure      401          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(session))
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Email a single-use password reset link. The response is the same whether or not the email is registered.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ForgotPasswordRequest  true  "Password reset request"
+// @Success      202      {object}  MessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Failure      503      {object}  ErrorResponse
+// @Router       /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		if errors.Is(err, services.ErrPasswordResetUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account exists for that email, a password reset link has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary      Reset a password
+// @Description  Set a new password using the token from a password reset email. Signs out every session and revokes the account's personal API tokens.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ResetPasswordRequest  true  "Password reset"
+// @Success      200      {object}  MessageResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) || errors.Is(err, services.ErrPasswordTooShort) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// OIDCLogin godoc
+// @Summary      Start OIDC sign-in
+// @Description  Redirect to the configured identity provider to sign in
+// @Tags         auth
+// @Success      302
+// @Failure      500  {object}  ErrorResponse
+// @Router       /auth/oidc/login [get]
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	login, err := h.oidcService.BeginLogin(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Bind the state to this browser so a callback cannot be replayed from another
+	maxAge := int(time.Until(login.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, login.State, maxAge, "/auth/oidc", "", isSecureRequest(c), true)
+
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+// OIDCCallback godoc
+// @Summary      Complete OIDC sign-in
+// @Description  Handle the identity provider redirect, creating or linking the user and returning a session token
+// @Tags         auth
+// @Produce      json
+// @Param        state  query     string  true  "Login state"
+// @Param        code   query     string  true  "Authorization code"
+// @Success      200    {object}  AuthResponse
+// @Success      302
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      409    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /auth/oidc/callback [get]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Identity provider returned an error: " + providerError})
+		return
+	}
+
+	state := c.Query("state")
+	code := c.Query("code")
+	if state == "" || code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing state or code"})
+		return
+	}
+
+	cookieState, err := c.Cookie(oidcStateCookie)
+	if err != nil || cookieState != state {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrOIDCStateInvalid.Error()})
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, "/auth/oidc", "", isSecureRequest(c), true)
+
+	session, err := h.oidcService.CompleteLogin(c.Request.Context(), state, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOIDCStateInvalid):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOIDCAuthFailed):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOIDCAccountConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if h.postLoginRedirectURL != "" {
+		// The fragment is never sent to servers, keeping the token out of logs
+		fragment := url.Values{
+			"access_token": {session.Token},
+			"token_type":   {"Bearer"},
+			"expires_at":   {session.ExpiresAt.UTC().Format(time.RFC3339)},
+		}
+		c.Redirect(http.StatusFound, h.postLoginRedirectURL+"#"+fragment.Encode())
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(session))
+}
+
+// isSecureRequest reports whether the request reached us, or the proxy in
+// front of us, over HTTPS
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
+
+// Me godoc
+// @Summary      Get the current user
+// @Description  Retrieve the user the request is authenticated as
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  UserResponse
+// @Failure      401  {object}  ErrorResponse
+// @Router       /auth/me [get]
+func (h *AuthHandler) Me(c *gin.Context) {
+	user, ok := middleware.AuthUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(*user))
+}
+
+// ListAPITokens godoc
+// @Summary      List API tokens
+// @Description  List the authenticated user's personal API tokens
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   APITokenResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /auth/tokens [get]
+func (h *AuthHandler) ListAPITokens(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.authService.ListAPITokens(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, newAPITokenResponse(token))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateAPIToken godoc
+// @Summary      Create an API token
+// @Description  Create a long-lived personal API token. The token is only returned once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        token  body      CreateAPITokenRequest  true  "API token creation request"
+// @Success      201    {object}  CreateAPITokenResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /auth/tokens [post]
+func (h *AuthHandler) CreateAPIToken(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAPITokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		expiry := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		expiresAt = &expiry
+	}
+
+	created, err := h.authService.CreateAPIToken(c.Request.Context(), userID, req.Name, expiresAt)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPITokenResponse{
+		Token:    created.Token,
+		APIToken: newAPITokenResponse(*created.APIToken),
+	})
+}
+
+// RevokeAPIToken godoc
+// @Summary      Revoke an API token
+// @Description  Delete one of the authenticated user's personal API tokens
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "API token ID"
+// @Success      200  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /auth/tokens/{id} [delete]
+func (h *AuthHandler) RevokeAPIToken(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	if err := h.authService.RevokeAPIToken(c.Request.Context(), userID, int32(id)); err != nil {
+		if errors.Is(err, services.ErrAPITokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API token revoked successfully"})
+}
+
+// newAuthResponse converts an issued session into its response body
+func newAuthResponse(session *services.AuthSession) AuthResponse {
+	return AuthResponse{
+		Token:     session.Token,
+		TokenType: "Bearer",
+		ExpiresAt: session.ExpiresAt,
+		UserID:    session.User.ID,
+	}
+}
@@ -0,0 +1,836 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/middleware"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Register(ctx context.Context, name, email, password string) (*db.User, error) {
+	args := m.Called(ctx, name, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.User), args.Error(1)
+}
+
+func (m *MockAuthService) Login(ctx context.Context, email, password string) (*services.AuthSession, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.AuthSession), args.Error(1)
+}
+
+func (m *MockAuthService) IssueSession(ctx context.Context, user *db.User) (*services.AuthSession, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.AuthSession), args.Error(1)
+}
+
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID int32, currentPassword, newPassword string) error {
+	args := m.Called(ctx, userID, currentPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	args := m.Called(ctx, token, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) SetEmailService(emailService services.EmailService) {
+	m.Called(emailService)
+}
+
+func (m *MockAuthService) Authenticate(ctx context.Context, token string) (*db.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.User), args.Error(1)
+}
+
+func (m *MockAuthService) CreateAPIToken(ctx context.Context, userID int32, name string, expiresAt *time.Time) (*services.CreatedAPIToken, error) {
+	args := m.Called(ctx, userID, name, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.CreatedAPIToken), args.Error(1)
+}
+
+func (m *MockAuthService) ListAPITokens(ctx context.Context, userID int32) ([]db.ApiToken, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ApiToken), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeAPIToken(ctx context.Context, userID int32, tokenID int32) error {
+	args := m.Called(ctx, userID, tokenID)
+	return args.Error(0)
+}
+
+type MockOIDCService struct {
+	mock.Mock
+}
+
+func (m *MockOIDCService) BeginLogin(ctx context.Context) (*services.OIDCLoginRequest, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.OIDCLoginRequest), args.Error(1)
+}
+
+func (m *MockOIDCService) CompleteLogin(ctx context.Context, state string, code string) (*services.AuthSession, error) {
+	args := m.Called(ctx, state, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.AuthSession), args.Error(1)
+}
+
+func TestRegister(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupAnonymousRouter()
+	router.POST("/auth/register", handler.Register)
+
+	user := &db.User{ID: 1}
+	session := &services.AuthSession{Token: "session-token", ExpiresAt: time.Now().Add(time.Hour), User: user}
+
+	mockAuthService.On("Register", mock.Anything, "John Doe", "john@example.com", "password123").Return(user, nil)
+	mockAuthService.On("IssueSession", mock.Anything, user).Return(session, nil)
+
+	reqBody := map[string]interface{}{
+		"name":     "John Doe",
+		"email":    "john@example.com",
+		"password": "password123",
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response AuthResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "session-token", response.Token)
+	assert.Equal(t, "Bearer", response.TokenType)
+	assert.Equal(t, int32(1), response.UserID)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestRegister_EmailTaken(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupAnonymousRouter()
+	router.POST("/auth/register", handler.Register)
+
+	mockAuthService.On("Register", mock.Anything, "", "john@example.com", "password123").Return(nil, services.ErrEmailTaken)
+
+	reqBody := map[string]interface{}{
+		"email":    "john@example.com",
+		"password": "password123",
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestRegister_ValidationError(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupAnonymousRouter()
+	router.POST("/auth/register", handler.Register)
+
+	reqBody := map[string]interface{}{
+		"email":    "not-an-email",
+		"password": "short",
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAuthService.AssertNotCalled(t, "Register")
+}
+
+func TestLogin(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupAnonymousRouter()
+	router.POST("/auth/login", handler.Login)
+
+	session := &services.AuthSession{Token: "session-token", ExpiresAt: time.Now().Add(time.Hour), User: &db.User{ID: 1}}
+	mockAuthService.On("Login", mock.Anything, "john@example.com", "password123").Return(session, nil)
+
+	reqBody := map[string]interface{}{
+		"email":    "john@example.com",
+		"password": "password123",
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestLogin_InvalidCredentials(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupAnonymousRouter()
+	router.POST("/auth/login", handler.Login)
+
+	mockAuthService.On("Login", mock.Anything, "john@example.com", "wrong").Return(nil, services.ErrInvalidCredentials)
+
+	reqBody := map[string]interface{}{
+		"email":    "john@example.com",
+		"password": "wrong",
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestMe(t *testing.T) {
+	handler := NewAuthHandler(new(MockAuthService))
+
+	router := setupTestRouter()
+	router.GET("/auth/me", handler.Me)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/auth/me", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var user db.User
+	json.Unmarshal(w.Body.Bytes(), &user)
+	assert.Equal(t, testUserID, user.ID)
+}
+
+func TestMe_Unauthenticated(t *testing.T) {
+	handler := NewAuthHandler(new(MockAuthService))
+
+	router := setupAnonymousRouter()
+	router.GET("/auth/me", handler.Me)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/auth/me", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestListAPITokens(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupTestRouter()
+	router.GET("/auth/tokens", handler.ListAPITokens)
+
+	tokens := []db.ApiToken{
+		{ID: 1, UserID: testUserID, Name: "CLI", TokenHash: "secret-hash", TokenPrefix: "bb_abc123"},
+	}
+	mockAuthService.On("ListAPITokens", mock.Anything, testUserID).Return(tokens, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/auth/tokens", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret-hash")
+	assert.Contains(t, w.Body.String(), "bb_abc123")
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestCreateAPIToken(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupTestRouter()
+	router.POST("/auth/tokens", handler.CreateAPIToken)
+
+	created := &services.CreatedAPIToken{
+		Token:    "bb_plaintext",
+		APIToken: &db.ApiToken{ID: 1, UserID: testUserID, Name: "CLI", TokenHash: "secret-hash"},
+	}
+	mockAuthService.On("CreateAPIToken", mock.Anything, testUserID, "CLI", mock.MatchedBy(func(expiresAt *time.Time) bool {
+		return expiresAt != nil && expiresAt.After(time.Now().AddDate(0, 0, 29))
+	})).Return(created, nil)
+
+	reqBody := map[string]interface{}{
+		"name":            "CLI",
+		"expires_in_days": 30,
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/tokens", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response CreateAPITokenResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "bb_plaintext", response.Token)
+	assert.NotContains(t, w.Body.String(), "secret-hash")
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestCreateAPIToken_InvalidName(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupTestRouter()
+	router.POST("/auth/tokens", handler.CreateAPIToken)
+
+	mockAuthService.On("CreateAPIToken", mock.Anything, testUserID, "  ", (*time.Time)(nil)).
+		Return(nil, fmt.Errorf("%w: token name is required", services.ErrInvalidAPIToken))
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"name": "  "})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/tokens", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestCreateAPIToken_Unauthenticated(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupAnonymousRouter()
+	router.POST("/auth/tokens", handler.CreateAPIToken)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"name": "CLI"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/tokens", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockAuthService.AssertNotCalled(t, "CreateAPIToken")
+}
+
+func TestRevokeAPIToken(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupTestRouter()
+	router.DELETE("/auth/tokens/:id", handler.RevokeAPIToken)
+
+	mockAuthService.On("RevokeAPIToken", mock.Anything, testUserID, int32(5)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/auth/tokens/5", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestRevokeAPIToken_NotFound(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupTestRouter()
+	router.DELETE("/auth/tokens/:id", handler.RevokeAPIToken)
+
+	mockAuthService.On("RevokeAPIToken", mock.Anything, testUserID, int32(5)).Return(services.ErrAPITokenNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/auth/tokens/5", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockAuthService.AssertExpectations(t)
+}
+
+func TestRevokeAPIToken_InvalidID(t *testing.T) {
+	handler := NewAuthHandler(new(MockAuthService))
+
+	router := setupTestRouter()
+	router.DELETE("/auth/tokens/:id", handler.RevokeAPIToken)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/auth/tokens/invalid", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetupRoutes_RequiresAuth(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewHandler(nil, new(MockItemService), nil, nil, nil)
+	handler.SetAuthService(mockAuthService)
+
+	router := setupAnonymousRouter()
+	handler.SetupRoutes(router)
+
+	mockAuthService.On("Authenticate", mock.Anything, "bad-token").Return(nil, services.ErrInvalidToken)
+
+	// No token
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Invalid token
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/items", nil)
+	req.Header.Set("Authorization", "Bearer bad-token")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Health stays public
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/health", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSetupRoutes_ScopesToAuthenticatedUser(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+	handler.SetAuthService(mockAuthService)
+
+	router := setupAnonymousRouter()
+	handler.SetupRoutes(router)
+
+	userID := int32(42)
+	mockAuthService.On("Authenticate", mock.Anything, "good-token").Return(&db.User{ID: userID}, nil)
+	mockItemService.On("ListItems", mock.Anything, userID, services.ItemListQuery{}).Return(&services.ItemPage{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items", nil)
+	req.Header.Set("Authorization", "Bearer good-token")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestSetupRoutes_AppliesRateLimits(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+	handler.SetAuthService(mockAuthService)
+
+	apiCalls := 0
+	handler.SetRateLimits(RouteRateLimits{
+		API: func(c *gin.Context) {
+			apiCalls++
+			c.Next()
+		},
+		Create: func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+		},
+	})
+
+	router := setupAnonymousRouter()
+	handler.SetupRoutes(router)
+
+	userID := int32(42)
+	mockAuthService.On("Authenticate", mock.Anything, "good-token").Return(&db.User{ID: userID}, nil)
+	mockItemService.On("ListItems", mock.Anything, userID, services.ItemListQuery{}).Return(&services.ItemPage{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items", nil)
+	req.Header.Set("Authorization", "Bearer good-token")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/items", bytes.NewBufferString(`{"url":"https://example.com"}`))
+	req.Header.Set("Authorization", "Bearer good-token")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	assert.Equal(t, 2, apiCalls)
+	mockItemService.AssertNotCalled(t, "CreateItemAsync", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetupRoutes_LimitsLoginAttemptsPerIP(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewHandler(nil, nil, nil, nil, nil)
+	handler.SetAuthService(mockAuthService)
+	handler.SetRateLimits(RouteRateLimits{
+		Auth: middleware.RateLimitMiddleware("auth", middleware.NewMemoryRateLimitStore(), middleware.PerMinute(5, 3)),
+	})
+
+	router := setupAnonymousRouter()
+	handler.SetupRoutes(router)
+
+	mockAuthService.On("Login", mock.Anything, "john@example.com", "wrong").Return(nil, services.ErrInvalidCredentials)
+
+	login := func(remoteAddr string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"email":"john@example.com","password":"wrong"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	codes := []int{}
+	for i := 0; i < 4; i++ {
+		codes = append(codes, login("203.0.113.9:1234"))
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	// Other clients have their own bucket
+	assert.Equal(t, http.StatusUnauthorized, login("198.51.100.4:1234"))
+	mockAuthService.AssertNumberOfCalls(t, "Login", 4)
+}
+
+func TestLogin_ServiceError(t *testing.T) {
+	mockAuthService := new(MockAuthService)
+	handler := NewAuthHandler(mockAuthService)
+
+	router := setupAnonymousRouter()
+	router.POST("/auth/login", handler.Login)
+
+	mockAuthService.On("Login", mock.Anything, "john@example.com", "password123").Return(nil, errors.New("database error"))
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{
+		"email":    "john@example.com",
+		"password": "password123",
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockAuthService.AssertExpectations(t)
+}
+
+// setupOIDCRouter registers the OIDC routes the way SetupRoutes does
+func setupOIDCRouter(mockOIDCService *MockOIDCService, postLoginRedirectURL string) http.Handler {
+	handler := NewHandler(nil, nil, nil, nil, nil)
+	handler.SetAuthService(new(MockAuthService))
+	handler.SetOIDCService(mockOIDCService, postLoginRedirectURL)
+
+	router := setupAnonymousRouter()
+	handler.SetupRoutes(router)
+	return router
+}
+
+// oidcCallbackRequest builds a callback request carrying the state cookie
+func oidcCallbackRequest(queryState, cookieState, code string) *http.Request {
+	query := url.Values{"state": {queryState}, "code": {code}}
+	req, _ := http.NewRequest("GET", "/auth/oidc/callback?"+query.Encode(), nil)
+	if cookieState != "" {
+		req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: cookieState})
+	}
+	return req
+}
+
+func TestOIDCLogin(t *testing.T) {
+	mockOIDCService := new(MockOIDCService)
+	router := setupOIDCRouter(mockOIDCService, "")
+
+	mockOIDCService.On("BeginLogin", mock.Anything).Return(&services.OIDCLoginRequest{
+		AuthURL:   "https://idp.example.com/authorize?state=signed-state",
+		State:     "signed-state",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/auth/oidc/login", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=signed-state", w.Header().Get("Location"))
+
+	cookies := w.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, oidcStateCookie, cookies[0].Name)
+		assert.Equal(t, "signed-state", cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	}
+	mockOIDCService.AssertExpectations(t)
+}
+
+func TestOIDCCallback(t *testing.T) {
+	mockOIDCService := new(MockOIDCService)
+	router := setupOIDCRouter(mockOIDCService, "")
+
+	session := &services.AuthSession{Token: "session-token", ExpiresAt: time.Now().Add(time.Hour), User: &db.User{ID: 7}}
+	mockOIDCService.On("CompleteLogin", mock.Anything, "signed-state", "auth-code").Return(session, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oidcCallbackRequest("signed-state", "signed-state", "auth-code"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response AuthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "session-token", response.Token)
+	assert.Equal(t, int32(7), response.UserID)
+	mockOIDCService.AssertExpectations(t)
+}
+
+func TestOIDCCallback_PostLoginRedirect(t *testing.T) {
+	mockOIDCService := new(MockOIDCService)
+	router := setupOIDCRouter(mockOIDCService, "https://app.example.com/login/complete")
+
+	session := &services.AuthSession{Token: "session-token", ExpiresAt: time.Now().Add(time.Hour), User: &db.User{ID: 7}}
+	mockOIDCService.On("CompleteLogin", mock.Anything, "signed-state", "auth-code").Return(session, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, oidcCallbackRequest("signed-state", "signed-state", "auth-code"))
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	location, err := url.Parse(w.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/login/complete", location.Path)
+	assert.Empty(t, location.RawQuery)
+
+	fragment, err := url.ParseQuery(location.Fragment)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-token", fragment.Get("access_token"))
+	assert.Equal(t, "Bearer", fragment.Get("token_type"))
+}
+
+func TestOIDCCallback_StateMismatch(t *testing.T) {
+	mockOIDCService := new(MockOIDCService)
+	router := setupOIDCRouter(mockOIDCService, "")
+
+	tests := []struct {
+		name        string
+		cookieState string
+	}{
+		{name: "missing cookie", cookieState: ""},
+		{name: "different cookie", cookieState: "other-state"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, oidcCallbackRequest("signed-state", tt.cookieState, "auth-code"))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+	mockOIDCService.AssertNotCalled(t, "CompleteLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOIDCCallback_ProviderError(t *testing.T) {
+	mockOIDCService := new(MockOIDCService)
+	router := setupOIDCRouter(mockOIDCService, "")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/auth/oidc/callback?error=access_denied&state=signed-state", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockOIDCService.AssertNotCalled(t, "CompleteLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOIDCCallback_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "invalid state", err: services.ErrOIDCStateInvalid, expectedStatus: http.StatusBadRequest},
+		{name: "auth failed", err: fmt.Errorf("%w: id token has expired", services.ErrOIDCAuthFailed), expectedStatus: http.StatusUnauthorized},
+		{name: "account conflict", err: services.ErrOIDCAccountConflict, expectedStatus: http.StatusConflict},
+		{name: "internal error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOIDCService := new(MockOIDCService)
+			router := setupOIDCRouter(mockOIDCService, "")
+
+			mockOIDCService.On("CompleteLogin", mock.Anything, "signed-state", "auth-code").Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, oidcCallbackRequest("signed-state", "signed-state", "auth-code"))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockOIDCService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetupRoutes_OIDCDisabled(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil)
+	handler.SetAuthService(new(MockAuthService))
+
+	router := setupAnonymousRouter()
+	handler.SetupRoutes(router)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/auth/oidc/login", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMe_OmitsCredentials(t *testing.T) {
+	handler := NewAuthHandler(new(MockAuthService))
+
+	passwordHash := "$2a$10$secret"
+	router := setupAnonymousRouter()
+	router.Use(func(c *gin.Context) {
+		middleware.SetAuthUser(c, &db.User{ID: testUserID, PasswordHash: &passwordHash})
+		c.Next()
+	})
+	router.GET("/auth/me", handler.Me)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/auth/me", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "password_hash")
+	assert.NotContains(t, w.Body.String(), passwordHash)
+}
+
+func TestForgotPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "accepted", err: nil, expectedStatus: http.StatusAccepted},
+		{name: "email not configured", err: services.ErrPasswordResetUnavailable, expectedStatus: http.StatusServiceUnavailable},
+		{name: "service error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := new(MockAuthService)
+			handler := NewAuthHandler(mockAuthService)
+
+			router := setupAnonymousRouter()
+			router.POST("/auth/password/forgot", handler.ForgotPassword)
+
+			mockAuthService.On("RequestPasswordReset", mock.Anything, "john@example.com").Return(tt.err)
+
+			jsonBody, _ := json.Marshal(map[string]interface{}{"email": "john@example.com"})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/auth/password/forgot", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "success", err: nil, expectedStatus: http.StatusOK},
+		{name: "invalid token", err: services.ErrInvalidResetToken, expectedStatus: http.StatusBadRequest},
+		{name: "short password", err: services.ErrPasswordTooShort, expectedStatus: http.StatusBadRequest},
+		{name: "service error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := new(MockAuthService)
+			handler := NewAuthHandler(mockAuthService)
+
+			router := setupAnonymousRouter()
+			router.POST("/auth/password/reset", handler.ResetPassword)
+
+			mockAuthService.On("ResetPassword", mock.Anything, "reset-token", "new-password").Return(tt.err)
+
+			jsonBody, _ := json.Marshal(map[string]interface{}{
+				"token":        "reset-token",
+				"new_password": "new-password",
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/auth/password/reset", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
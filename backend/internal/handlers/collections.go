@@ -0,0 +1,472 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// CollectionHandler handles collection HTTP requests
+type CollectionHandler struct {
+	collectionService services.CollectionService
+}
+
+// NewCollectionHandler creates a new collection handler
+func NewCollectionHandler(collectionService services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// CreateCollection godoc
+// @Summary      Create a collection
+// @Description  Create a collection for organizing items, such as "Q3 research". Collection names are unique per user.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        collection  body      CollectionRequest  true  "Collection to create"
+// @Success      201         {object}  CollectionResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      401         {object}  ErrorResponse
+// @Failure      409         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /collections [post]
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(c.Request.Context(), userID, req.Name, req.Description)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCollectionResponse(*collection))
+}
+
+// ListCollections godoc
+// @Summary      List collections
+// @Description  List the authenticated user's collections by name, with the number of items in each
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   CollectionSummaryResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /collections [get]
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	collections, err := h.collectionService.ListCollections(c.Request.Context(), userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := make([]CollectionSummaryResponse, len(collections))
+	for i, row := range collections {
+		response[i] = CollectionSummaryResponse{
+			CollectionResponse: newCollectionResponse(row.Collection),
+			ItemCount:          row.ItemCount,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCollection godoc
+// @Summary      Get a collection
+// @Description  Get one of the authenticated user's collections
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Collection ID"
+// @Success      200  {object}  CollectionResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /collections/{id} [get]
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(c.Request.Context(), userID, collectionID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCollectionResponse(*collection))
+}
+
+// UpdateCollection godoc
+// @Summary      Update a collection
+// @Description  Rename a collection or change its description. Omitted fields are left unchanged; an empty description clears it.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int                      true  "Collection ID"
+// @Param        collection  body      UpdateCollectionRequest  true  "Fields to change"
+// @Success      200         {object}  CollectionResponse
+// @Failure      400         {object}  ErrorResponse
+// @Failure      401         {object}  ErrorResponse
+// @Failure      404         {object}  ErrorResponse
+// @Failure      409         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /collections/{id} [patch]
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := h.collectionService.UpdateCollection(c.Request.Context(), userID, collectionID, req.Name, req.Description)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCollectionResponse(*collection))
+}
+
+// DeleteCollection godoc
+// @Summary      Delete a collection
+// @Description  Delete a collection. The items in it are not deleted.
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Collection ID"
+// @Success      200  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /collections/{id} [delete]
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(c.Request.Context(), userID, collectionID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
+
+// ListCollectionItems godoc
+// @Summary      List collection items
+// @Description  List the items of a collection in order, with whether the authenticated user has read each. Workspace items the user can no longer see are left out.
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Collection ID"
+// @Success      200  {object}  CollectionItemsResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /collections/{id}/items [get]
+func (h *CollectionHandler) ListCollectionItems(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.collectionService.ListItems(c.Request.Context(), userID, collectionID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCollectionItemsResponse(items))
+}
+
+// AddCollectionItem godoc
+// @Summary      Add an item to a collection
+// @Description  Append an item the authenticated user can see to a collection. Adding an item already in the collection leaves it where it is.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                    true  "Collection ID"
+// @Param        item  body      CollectionItemRequest  true  "Item to add"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /collections/{id}/items [post]
+func (h *CollectionHandler) AddCollectionItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req CollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.collectionService.AddItem(c.Request.Context(), userID, collectionID, req.ItemID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item added to collection successfully"})
+}
+
+// RemoveCollectionItem godoc
+// @Summary      Remove an item from a collection
+// @Description  Remove an item from a collection. The item itself is not deleted.
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true  "Collection ID"
+// @Param        itemID  path      int  true  "Item ID"
+// @Success      200     {object}  MessageResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /collections/{id}/items/{itemID} [delete]
+func (h *CollectionHandler) RemoveCollectionItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("itemID"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	if err := h.collectionService.RemoveItem(c.Request.Context(), userID, collectionID, int32(itemID)); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item removed from collection successfully"})
+}
+
+// ReorderCollectionItems godoc
+// @Summary      Reorder a collection
+// @Description  Move the listed items to the front of a collection in the order given. Items not listed keep their order after them.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int                            true  "Collection ID"
+// @Param        order  body      ReorderCollectionItemsRequest  true  "New item order"
+// @Success      200    {object}  CollectionItemsResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /collections/{id}/items/order [put]
+func (h *CollectionHandler) ReorderCollectionItems(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req ReorderCollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := h.collectionService.ReorderItems(c.Request.Context(), userID, collectionID, req.ItemIDs)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCollectionItemsResponse(items))
+}
+
+// ListItemCollections godoc
+// @Summary      List an item's collections
+// @Description  List the authenticated user's collections an item is in
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Item ID"
+// @Success      200  {array}   CollectionResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /items/{id}/collections [get]
+func (h *CollectionHandler) ListItemCollections(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	collections, err := h.collectionService.ListCollectionsForItem(c.Request.Context(), userID, int32(itemID))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := make([]CollectionResponse, len(collections))
+	for i, collection := range collections {
+		response[i] = newCollectionResponse(collection)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateCollectionPodcast godoc
+// @Summary      Create podcast from collection
+// @Description  Generate a podcast from the items of a collection in collection order, titled after the collection
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Collection ID"
+// @Success      201  {object}  CreatePodcastResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      429  {object}  ErrorResponse  "Rate limit or daily podcast minutes quota exceeded; see Retry-After"
+// @Failure      500  {object}  ErrorResponse
+// @Router       /collections/{id}/podcast [post]
+func (h *CollectionHandler) CreateCollectionPodcast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	podcast, err := h.collectionService.CreatePodcast(c.Request.Context(), userID, collectionID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"podcast":           podcast,
+		"message":           "Podcast created successfully from collection and will be processed in the background",
+		"processing_status": podcast.Status,
+	})
+}
+
+// SendCollectionDigest godoc
+// @Summary      Email a digest of a collection
+// @Description  Email the authenticated user a digest of a collection's items, read or not, with a podcast of them when their digests include one. The digest is sent in the background.
+// @Tags         collections
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Collection ID"
+// @Success      202  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /collections/{id}/digest [post]
+func (h *CollectionHandler) SendCollectionDigest(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	collectionID, ok := collectionIDParam(c)
+	if !ok {
+		return
+	}
+
+	// Check the collection before answering, since failures in the background are only logged
+	items, err := h.collectionService.ListItems(c.Request.Context(), userID, collectionID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if len(items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Collection has no items"})
+		return
+	}
+
+	go func() {
+		ctx := context.Background() // Use background context for async processing
+		result, err := h.collectionService.SendDigest(ctx, userID, collectionID)
+		if err != nil {
+			log.Printf("Background collection digest failed for user %d, collection %d: %v", userID, collectionID, err)
+		} else {
+			log.Printf("Background collection digest completed for user %d, collection %d: emailSent=%v, podcastGenerated=%v",
+				userID, collectionID, result.EmailSent, result.PodcastURL != nil)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Collection digest processing started in background"})
+}
+
+// collectionIDParam parses the :id path parameter
+func collectionIDParam(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return 0, false
+	}
+	return int32(id), true
+}
@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+type MockCollectionService struct {
+	mock.Mock
+}
+
+func (m *MockCollectionService) CreateCollection(ctx context.Context, userID int32, name string, description *string) (*db.Collection, error) {
+	args := m.Called(ctx, userID, name, description)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) ListCollections(ctx context.Context, userID int32) ([]db.ListCollectionsByUserRow, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ListCollectionsByUserRow), args.Error(1)
+}
+
+func (m *MockCollectionService) GetCollection(ctx context.Context, userID int32, collectionID int32) (*db.Collection, error) {
+	args := m.Called(ctx, userID, collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) UpdateCollection(ctx context.Context, userID int32, collectionID int32, name *string, description *string) (*db.Collection, error) {
+	args := m.Called(ctx, userID, collectionID, name, description)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) DeleteCollection(ctx context.Context, userID int32, collectionID int32) error {
+	args := m.Called(ctx, userID, collectionID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) ListItems(ctx context.Context, userID int32, collectionID int32) ([]db.ListCollectionItemsRow, error) {
+	args := m.Called(ctx, userID, collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ListCollectionItemsRow), args.Error(1)
+}
+
+func (m *MockCollectionService) AddItem(ctx context.Context, userID int32, collectionID int32, itemID int32) error {
+	args := m.Called(ctx, userID, collectionID, itemID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) RemoveItem(ctx context.Context, userID int32, collectionID int32, itemID int32) error {
+	args := m.Called(ctx, userID, collectionID, itemID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) ReorderItems(ctx context.Context, userID int32, collectionID int32, itemIDs []int32) ([]db.ListCollectionItemsRow, error) {
+	args := m.Called(ctx, userID, collectionID, itemIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ListCollectionItemsRow), args.Error(1)
+}
+
+func (m *MockCollectionService) ListCollectionsForItem(ctx context.Context, userID int32, itemID int32) ([]db.Collection, error) {
+	args := m.Called(ctx, userID, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) CreatePodcast(ctx context.Context, userID int32, collectionID int32) (*db.Podcast, error) {
+	args := m.Called(ctx, userID, collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Podcast), args.Error(1)
+}
+
+func (m *MockCollectionService) SendDigest(ctx context.Context, userID int32, collectionID int32) (*services.DigestResult, error) {
+	args := m.Called(ctx, userID, collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DigestResult), args.Error(1)
+}
+
+func (m *MockCollectionService) SetDigestService(digestService services.DigestService) {
+	m.Called(digestService)
+}
+
+func TestCreateCollection(t *testing.T) {
+	mockCollectionService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockCollectionService)
+
+	router := setupTestRouter()
+	router.POST("/collections", handler.CreateCollection)
+
+	description := "Reading for the planning review"
+	mockCollectionService.On("CreateCollection", mock.Anything, testUserID, "Q3 research", &description).
+		Return(&db.Collection{ID: 4, UserID: testUserID, Name: "Q3 research", Description: &description}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/collections", bytes.NewBufferString(`{"name": "Q3 research", "description": "Reading for the planning review"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response CollectionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int32(4), response.ID)
+	assert.Equal(t, "Q3 research", response.Name)
+	mockCollectionService.AssertExpectations(t)
+}
+
+func TestListCollections(t *testing.T) {
+	mockCollectionService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockCollectionService)
+
+	router := setupTestRouter()
+	router.GET("/collections", handler.ListCollections)
+
+	mockCollectionService.On("ListCollections", mock.Anything, testUserID).Return([]db.ListCollectionsByUserRow{
+		{Collection: db.Collection{ID: 4, Name: "Q3 research"}, ItemCount: 3},
+		{Collection: db.Collection{ID: 5, Name: "To share with team"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/collections", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []CollectionSummaryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, "Q3 research", response[0].Name)
+	assert.Equal(t, int32(3), response[0].ItemCount)
+	mockCollectionService.AssertExpectations(t)
+}
+
+func TestListCollectionItems(t *testing.T) {
+	mockCollectionService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockCollectionService)
+
+	router := setupTestRouter()
+	router.GET("/collections/:id/items", handler.ListCollectionItems)
+
+	mockCollectionService.On("ListItems", mock.Anything, testUserID, int32(4)).Return([]db.ListCollectionItemsRow{
+		{Item: db.Item{ID: 7, Title: "First"}, IsRead: true},
+		{Item: db.Item{ID: 3, Title: "Second"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/collections/4/items", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response CollectionItemsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	assert.Equal(t, int32(7), response.Items[0].ID)
+	assert.True(t, response.Items[0].IsRead)
+	mockCollectionService.AssertExpectations(t)
+}
+
+func TestReorderCollectionItems(t *testing.T) {
+	mockCollectionService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockCollectionService)
+
+	router := setupTestRouter()
+	router.PUT("/collections/:id/items/order", handler.ReorderCollectionItems)
+
+	mockCollectionService.On("ReorderItems", mock.Anything, testUserID, int32(4), []int32{3, 7}).Return([]db.ListCollectionItemsRow{
+		{Item: db.Item{ID: 3}},
+		{Item: db.Item{ID: 7}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/collections/4/items/order", bytes.NewBufferString(`{"item_ids": [3, 7]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response CollectionItemsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Items, 2)
+	assert.Equal(t, int32(3), response.Items[0].ID)
+	mockCollectionService.AssertExpectations(t)
+}
+
+func TestCreateCollectionPodcast(t *testing.T) {
+	mockCollectionService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockCollectionService)
+
+	router := setupTestRouter()
+	router.POST("/collections/:id/podcast", handler.CreateCollectionPodcast)
+
+	mockCollectionService.On("CreatePodcast", mock.Anything, testUserID, int32(4)).
+		Return(&db.Podcast{ID: 11, Title: "Q3 research", Status: "pending"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/collections/4/podcast", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response CreatePodcastResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int32(11), response.Podcast.ID)
+	assert.Equal(t, "pending", response.ProcessingStatus)
+	mockCollectionService.AssertExpectations(t)
+}
+
+func TestSendCollectionDigest(t *testing.T) {
+	mockCollectionService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockCollectionService)
+
+	router := setupTestRouter()
+	router.POST("/collections/:id/digest", handler.SendCollectionDigest)
+
+	sent := make(chan struct{})
+	mockCollectionService.On("ListItems", mock.Anything, testUserID, int32(4)).
+		Return([]db.ListCollectionItemsRow{{Item: db.Item{ID: 7}}}, nil)
+	mockCollectionService.On("SendDigest", mock.Anything, testUserID, int32(4)).
+		Run(func(args mock.Arguments) { close(sent) }).
+		Return(&services.DigestResult{EmailSent: true, ItemsCount: 1, DigestType: "collection"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/collections/4/digest", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	<-sent
+	mockCollectionService.AssertExpectations(t)
+}
+
+func TestCollectionRoutes_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setup        func(m *MockCollectionService)
+		expectedCode int
+	}{
+		{
+			name:         "create without a name",
+			method:       http.MethodPost,
+			path:         "/collections",
+			body:         `{"description": "No name"}`,
+			setup:        func(m *MockCollectionService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "create a duplicate",
+			method: http.MethodPost,
+			path:   "/collections",
+			body:   `{"name": "Reading"}`,
+			setup: func(m *MockCollectionService) {
+				m.On("CreateCollection", mock.Anything, testUserID, "Reading", (*string)(nil)).Return(nil, services.ErrCollectionExists)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "invalid collection ID",
+			method:       http.MethodGet,
+			path:         "/collections/abc",
+			setup:        func(m *MockCollectionService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "another user's collection",
+			method: http.MethodGet,
+			path:   "/collections/4",
+			setup: func(m *MockCollectionService) {
+				m.On("GetCollection", mock.Anything, testUserID, int32(4)).Return(nil, services.ErrCollectionNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "rename to a blank name",
+			method: http.MethodPatch,
+			path:   "/collections/4",
+			body:   `{"name": " "}`,
+			setup: func(m *MockCollectionService) {
+				name := " "
+				m.On("UpdateCollection", mock.Anything, testUserID, int32(4), &name, (*string)(nil)).
+					Return(nil, fmt.Errorf("%w: name cannot be empty", services.ErrInvalidCollection))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "delete an unknown collection",
+			method: http.MethodDelete,
+			path:   "/collections/4",
+			setup: func(m *MockCollectionService) {
+				m.On("DeleteCollection", mock.Anything, testUserID, int32(4)).Return(services.ErrCollectionNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "add an item the user cannot see",
+			method: http.MethodPost,
+			path:   "/collections/4/items",
+			body:   `{"item_id": 9}`,
+			setup: func(m *MockCollectionService) {
+				m.On("AddItem", mock.Anything, testUserID, int32(4), int32(9)).Return(services.ErrItemNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "remove with an invalid item ID",
+			method:       http.MethodDelete,
+			path:         "/collections/4/items/abc",
+			setup:        func(m *MockCollectionService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "reorder without items",
+			method:       http.MethodPut,
+			path:         "/collections/4/items/order",
+			body:         `{"item_ids": []}`,
+			setup:        func(m *MockCollectionService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "podcast of an empty collection",
+			method: http.MethodPost,
+			path:   "/collections/4/podcast",
+			setup: func(m *MockCollectionService) {
+				m.On("CreatePodcast", mock.Anything, testUserID, int32(4)).
+					Return(nil, fmt.Errorf("%w: collection has no items", services.ErrInvalidCollection))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "digest of an empty collection",
+			method: http.MethodPost,
+			path:   "/collections/4/digest",
+			setup: func(m *MockCollectionService) {
+				m.On("ListItems", mock.Anything, testUserID, int32(4)).Return([]db.ListCollectionItemsRow{}, nil)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "collections of an unknown item",
+			method: http.MethodGet,
+			path:   "/items/9/collections",
+			setup: func(m *MockCollectionService) {
+				m.On("ListCollectionsForItem", mock.Anything, testUserID, int32(9)).Return(nil, services.ErrItemNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollectionService := new(MockCollectionService)
+			tt.setup(mockCollectionService)
+			handler := NewCollectionHandler(mockCollectionService)
+
+			router := setupTestRouter()
+			router.POST("/collections", handler.CreateCollection)
+			router.GET("/collections/:id", handler.GetCollection)
+			router.PATCH("/collections/:id", handler.UpdateCollection)
+			router.DELETE("/collections/:id", handler.DeleteCollection)
+			router.POST("/collections/:id/items", handler.AddCollectionItem)
+			router.PUT("/collections/:id/items/order", handler.ReorderCollectionItems)
+			router.DELETE("/collections/:id/items/:itemID", handler.RemoveCollectionItem)
+			router.POST("/collections/:id/podcast", handler.CreateCollectionPodcast)
+			router.POST("/collections/:id/digest", handler.SendCollectionDigest)
+			router.GET("/items/:id/collections", handler.ListItemCollections)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockCollectionService.AssertExpectations(t)
+		})
+	}
+}
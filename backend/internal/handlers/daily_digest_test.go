@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -46,6 +47,14 @@ func (m *MockDigestService) SendIntegratedDigestForUser(ctx context.Context, use
 	return args.Get(0).(*services.DigestResult), args.Error(1)
 }
 
+func (m *MockDigestService) SendCollectionDigest(ctx context.Context, userID int32, name string, items []db.Item) (*services.DigestResult, error) {
+	args := m.Called(ctx, userID, name, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DigestResult), args.Error(1)
+}
+
 func (m *MockDigestService) SetPodcastGenerationEnabled(enabled bool) {
 	m.Called(enabled)
 }
@@ -55,54 +64,13 @@ func (m *MockDigestService) IsPodcastGenerationEnabled() bool {
 	return args.Bool(0)
 }
 
-func TestTriggerDailyDigest(t *testing.T) {
-	mockDigestService := new(MockDigestService)
-	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
-
-	router := setupTestRouter()
-	router.POST("/digest/trigger", handler.TriggerDailyDigest)
-
-	mockDigestService.On("SendDailyDigest", mock.Anything).Return(nil)
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger", nil)
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-	mockDigestService.AssertExpectations(t)
-}
-
-func TestTriggerDailyDigest_ServiceUnavailable(t *testing.T) {
-	handler := NewHandler(nil, nil, nil, nil, nil) // No digest service
-
-	router := setupTestRouter()
-	router.POST("/digest/trigger", handler.TriggerDailyDigest)
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger", nil)
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+func (m *MockDigestService) SendDueDigests(ctx context.Context, now time.Time) error {
+	args := m.Called(ctx, now)
+	return args.Error(0)
 }
 
-func TestTriggerDailyDigest_Error(t *testing.T) {
-	mockDigestService := new(MockDigestService)
-	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
-
-	router := setupTestRouter()
-	router.POST("/digest/trigger", handler.TriggerDailyDigest)
-
-	mockDigestService.On("SendDailyDigest", mock.Anything).Return(errors.New("digest error"))
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger", nil)
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	mockDigestService.AssertExpectations(t)
+func (m *MockDigestService) SetPreferencesService(preferencesService services.PreferencesService) {
+	m.Called(preferencesService)
 }
 
 func TestTriggerDailyDigestForUser(t *testing.T) {
@@ -110,7 +78,7 @@ func TestTriggerDailyDigestForUser(t *testing.T) {
 	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
 
 	router := setupTestRouter()
-	router.POST("/digest/trigger/user/:userID", handler.TriggerDailyDigestForUser)
+	router.POST("/digest/trigger/me", handler.TriggerDailyDigestForUser)
 
 	expectedItems := []db.Item{
 		{ID: 1, Title: "Item 1"},
@@ -120,7 +88,7 @@ func TestTriggerDailyDigestForUser(t *testing.T) {
 	mockDigestService.On("SendDailyDigestForUser", mock.Anything, int32(1)).Return(nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/user/1", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/me", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -133,12 +101,12 @@ func TestTriggerDailyDigestForUser_NoItems(t *testing.T) {
 	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
 
 	router := setupTestRouter()
-	router.POST("/digest/trigger/user/:userID", handler.TriggerDailyDigestForUser)
+	router.POST("/digest/trigger/me", handler.TriggerDailyDigestForUser)
 
 	mockDigestService.On("GetDailyDigestItemsForUser", mock.Anything, int32(1)).Return([]db.Item{}, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/user/1", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/me", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -148,54 +116,19 @@ func TestTriggerDailyDigestForUser_NoItems(t *testing.T) {
 	assert.Contains(t, response["message"], "No unread items")
 }
 
-func TestTriggerDailyDigestForUser_InvalidID(t *testing.T) {
+func TestTriggerDailyDigestForUser_Unauthenticated(t *testing.T) {
 	mockDigestService := new(MockDigestService)
 	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
 
-	router := setupTestRouter()
-	router.POST("/digest/trigger/user/:userID", handler.TriggerDailyDigestForUser)
+	router := setupAnonymousRouter()
+	router.POST("/digest/trigger/me", handler.TriggerDailyDigestForUser)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/user/invalid", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/me", nil)
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
-}
-
-func TestTriggerIntegratedDigest(t *testing.T) {
-	mockDigestService := new(MockDigestService)
-	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
-
-	router := setupTestRouter()
-	router.POST("/digest/trigger/integrated", handler.TriggerIntegratedDigest)
-
-	// The method spawns a goroutine, but we still test that the endpoint responds correctly
-	mockDigestService.On("SendIntegratedDigest", mock.Anything).Return(nil)
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/integrated", nil)
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusAccepted, w.Code)
-
-	// Note: The mock won't be called immediately due to the goroutine,
-	// but we're testing the handler's response behavior
-}
-
-func TestTriggerIntegratedDigest_ServiceUnavailable(t *testing.T) {
-	handler := NewHandler(nil, nil, nil, nil, nil) // No digest service
-
-	router := setupTestRouter()
-	router.POST("/digest/trigger/integrated", handler.TriggerIntegratedDigest)
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/integrated", nil)
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestTriggerIntegratedDigestForUser(t *testing.T) {
@@ -203,7 +136,7 @@ func TestTriggerIntegratedDigestForUser(t *testing.T) {
 	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
 
 	router := setupTestRouter()
-	router.POST("/digest/trigger/integrated/user/:userID", handler.TriggerIntegratedDigestForUser)
+	router.POST("/digest/trigger/integrated/me", handler.TriggerIntegratedDigestForUser)
 
 	// The method spawns a goroutine, but we still test that the endpoint responds correctly
 	mockDigestService.On("SendIntegratedDigestForUser", mock.Anything, int32(1)).Return(&services.DigestResult{
@@ -212,36 +145,36 @@ func TestTriggerIntegratedDigestForUser(t *testing.T) {
 	}, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/integrated/user/1", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/integrated/me", nil)
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusAccepted, w.Code)
 }
 
-func TestTriggerIntegratedDigestForUser_InvalidID(t *testing.T) {
+func TestTriggerIntegratedDigestForUser_Unauthenticated(t *testing.T) {
 	mockDigestService := new(MockDigestService)
 	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
 
-	router := setupTestRouter()
-	router.POST("/digest/trigger/integrated/user/:userID", handler.TriggerIntegratedDigestForUser)
+	router := setupAnonymousRouter()
+	router.POST("/digest/trigger/integrated/me", handler.TriggerIntegratedDigestForUser)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/integrated/user/invalid", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/integrated/me", nil)
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestTriggerIntegratedDigestForUser_ServiceUnavailable(t *testing.T) {
 	handler := NewHandler(nil, nil, nil, nil, nil) // No digest service
 
 	router := setupTestRouter()
-	router.POST("/digest/trigger/integrated/user/:userID", handler.TriggerIntegratedDigestForUser)
+	router.POST("/digest/trigger/integrated/me", handler.TriggerIntegratedDigestForUser)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/integrated/user/1", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/integrated/me", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -253,12 +186,12 @@ func TestTriggerDailyDigestForUser_GetItemsError(t *testing.T) {
 	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
 
 	router := setupTestRouter()
-	router.POST("/digest/trigger/user/:userID", handler.TriggerDailyDigestForUser)
+	router.POST("/digest/trigger/me", handler.TriggerDailyDigestForUser)
 
 	mockDigestService.On("GetDailyDigestItemsForUser", mock.Anything, int32(1)).Return([]db.Item{}, errors.New("service error"))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/user/1", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/me", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -271,7 +204,7 @@ func TestTriggerDailyDigestForUser_SendDigestError(t *testing.T) {
 	handler := NewHandler(nil, nil, mockDigestService, nil, nil)
 
 	router := setupTestRouter()
-	router.POST("/digest/trigger/user/:userID", handler.TriggerDailyDigestForUser)
+	router.POST("/digest/trigger/me", handler.TriggerDailyDigestForUser)
 
 	expectedItems := []db.Item{
 		{ID: 1, Title: "Item 1"},
@@ -281,7 +214,7 @@ func TestTriggerDailyDigestForUser_SendDigestError(t *testing.T) {
 	mockDigestService.On("SendDailyDigestForUser", mock.Anything, int32(1)).Return(errors.New("send error"))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/user/1", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/me", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -293,10 +226,10 @@ func TestTriggerDailyDigestForUser_ServiceUnavailable(t *testing.T) {
 	handler := NewHandler(nil, nil, nil, nil, nil) // No digest service
 
 	router := setupTestRouter()
-	router.POST("/digest/trigger/user/:userID", handler.TriggerDailyDigestForUser)
+	router.POST("/digest/trigger/me", handler.TriggerDailyDigestForUser)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/digest/trigger/user/1", nil)
+	req, _ := http.NewRequest("POST", "/digest/trigger/me", nil)
 
 	router.ServeHTTP(w, req)
 
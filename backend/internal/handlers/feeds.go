@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// FeedHandler handles feed subscription HTTP requests
+type FeedHandler struct {
+	feedService services.FeedService
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(feedService services.FeedService) *FeedHandler {
+	return &FeedHandler{feedService: feedService}
+}
+
+// Subscribe godoc
+// @Summary      Subscribe to a feed
+// @Description  Follow an RSS, Atom or JSON feed, or a web page that links to one. New entries are saved as items as the feed is polled; entries already in the feed are not.
+// @Tags         feeds
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        feed  body      FeedRequest  true  "Feed subscription request"
+// @Success      201   {object}  FeedResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /feeds [post]
+func (h *FeedHandler) Subscribe(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req FeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, subscription, err := h.feedService.Subscribe(c.Request.Context(), userID, req.URL, req.Title, req.Tags)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newFeedResponse(*feed, *subscription))
+}
+
+// ListFeeds godoc
+// @Summary      List feeds
+// @Description  List the feeds the authenticated user is subscribed to
+// @Tags         feeds
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   FeedResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /feeds [get]
+func (h *FeedHandler) ListFeeds(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feeds, err := h.feedService.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := make([]FeedResponse, len(feeds))
+	for i, row := range feeds {
+		response[i] = FeedResponse{
+			ID:           row.ID,
+			URL:          row.Url,
+			Title:        row.Title,
+			CustomTitle:  row.CustomTitle,
+			Tags:         row.Tags,
+			SiteURL:      row.SiteUrl,
+			LastPolledAt: row.LastPolledAt,
+			LastError:    row.LastError,
+			SubscribedAt: row.SubscribedAt,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFeed godoc
+// @Summary      Get a feed
+// @Description  Retrieve a feed the authenticated user is subscribed to
+// @Tags         feeds
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Feed ID"
+// @Success      200  {object}  FeedResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /feeds/{id} [get]
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feedID, ok := feedIDParam(c)
+	if !ok {
+		return
+	}
+
+	feed, subscription, err := h.feedService.GetSubscription(c.Request.Context(), userID, feedID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newFeedResponse(*feed, *subscription))
+}
+
+// UpdateFeed godoc
+// @Summary      Update a feed
+// @Description  Set the authenticated user's own title and tags for a feed they are subscribed to. New tags apply to items saved from then on.
+// @Tags         feeds
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                true  "Feed ID"
+// @Param        feed  body      UpdateFeedRequest  true  "Custom title and tags"
+// @Success      200   {object}  FeedResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /feeds/{id} [patch]
+func (h *FeedHandler) UpdateFeed(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feedID, ok := feedIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, subscription, err := h.feedService.UpdateSubscription(c.Request.Context(), userID, feedID, req.Title, req.Tags)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newFeedResponse(*feed, *subscription))
+}
+
+// Unsubscribe godoc
+// @Summary      Unsubscribe from a feed
+// @Description  Stop following a feed. Items already saved from it are kept.
+// @Tags         feeds
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Feed ID"
+// @Success      200  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /feeds/{id} [delete]
+func (h *FeedHandler) Unsubscribe(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feedID, ok := feedIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.feedService.Unsubscribe(c.Request.Context(), userID, feedID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed from feed successfully"})
+}
+
+// ImportOPML godoc
+// @Summary      Import feeds from OPML
+// @Description  Subscribe to every feed in an OPML 1.0 or 2.0 file of up to 1 MB, as exported by other feed readers. Folder names become tags on the subscriptions. Feeds that cannot be fetched or parsed, or fail to import, are reported and skipped while the others are still imported. With dry_run, each feed is fetched to report whether it is reachable and nothing is subscribed.
+// @Tags         feeds
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file     formData  file  true   "OPML file"
+// @Param        dry_run  query     bool  false  "Check the feeds without subscribing"
+// @Success      200  {object}  OPMLImportResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      413  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /feeds/opml [post]
+func (h *FeedHandler) ImportOPML(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run value"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	// Leave room for the multipart headers
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxOPMLSize+64<<10)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required"})
+		return
+	}
+	if fileHeader.Size > services.MaxOPMLSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	results, err := h.feedService.ImportOPML(c.Request.Context(), userID, data, dryRun)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOPMLImportResponse(results, dryRun))
+}
+
+// ExportOPML godoc
+// @Summary      Export feeds as OPML
+// @Description  Download the authenticated user's feed subscriptions as an OPML 2.0 file for other feed readers. Feeds are grouped into folders by their first tag.
+// @Tags         feeds
+// @Produce      text/x-opml
+// @Security     BearerAuth
+// @Success      200  {file}    file
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /feeds/opml [get]
+func (h *FeedHandler) ExportOPML(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.feedService.ExportOPML(c.Request.Context(), userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("briefbot-feeds-%d-%s.opml", userID, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/x-opml; charset=utf-8", data)
+}
+
+// feedIDParam parses the :id path parameter
+func feedIDParam(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feed ID"})
+		return 0, false
+	}
+	return int32(id), true
+}
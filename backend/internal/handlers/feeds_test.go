@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+type MockFeedService struct {
+	mock.Mock
+}
+
+func (m *MockFeedService) Subscribe(ctx context.Context, userID int32, feedURL string, title *string, tags []string) (*db.Feed, *db.FeedSubscription, error) {
+	args := m.Called(ctx, userID, feedURL, title, tags)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*db.Feed), args.Get(1).(*db.FeedSubscription), args.Error(2)
+}
+
+func (m *MockFeedService) ListSubscriptions(ctx context.Context, userID int32) ([]db.ListFeedSubscriptionsByUserRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ListFeedSubscriptionsByUserRow), args.Error(1)
+}
+
+func (m *MockFeedService) GetSubscription(ctx context.Context, userID int32, feedID int32) (*db.Feed, *db.FeedSubscription, error) {
+	args := m.Called(ctx, userID, feedID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*db.Feed), args.Get(1).(*db.FeedSubscription), args.Error(2)
+}
+
+func (m *MockFeedService) UpdateSubscription(ctx context.Context, userID int32, feedID int32, title *string, tags []string) (*db.Feed, *db.FeedSubscription, error) {
+	args := m.Called(ctx, userID, feedID, title, tags)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*db.Feed), args.Get(1).(*db.FeedSubscription), args.Error(2)
+}
+
+func (m *MockFeedService) Unsubscribe(ctx context.Context, userID int32, feedID int32) error {
+	args := m.Called(ctx, userID, feedID)
+	return args.Error(0)
+}
+
+func (m *MockFeedService) ImportOPML(ctx context.Context, userID int32, data []byte, dryRun bool) ([]services.OPMLImportResult, error) {
+	args := m.Called(ctx, userID, data, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.OPMLImportResult), args.Error(1)
+}
+
+func (m *MockFeedService) ExportOPML(ctx context.Context, userID int32) ([]byte, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockFeedService) PollDueFeeds(ctx context.Context, now time.Time) error {
+	args := m.Called(ctx, now)
+	return args.Error(0)
+}
+
+func (m *MockFeedService) SetQuotaService(quotaService services.QuotaService) {
+	m.Called(quotaService)
+}
+
+func TestSubscribeFeed(t *testing.T) {
+	mockFeedService := new(MockFeedService)
+	handler := NewFeedHandler(mockFeedService)
+
+	router := setupTestRouter()
+	router.POST("/feeds", handler.Subscribe)
+
+	feedTitle, customTitle := "Example Blog", "Example"
+	mockFeedService.On("Subscribe", mock.Anything, testUserID, "https://blog.example.com/feed.xml", &customTitle, []string{"tech"}).Return(
+		&db.Feed{ID: 7, Url: "https://blog.example.com/feed.xml", Title: &feedTitle},
+		&db.FeedSubscription{FeedID: 7, UserID: testUserID, Title: &customTitle, Tags: []string{"tech"}},
+		nil,
+	)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"url": "https://blog.example.com/feed.xml", "title": "Example", "tags": []string{"tech"}})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/feeds", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response FeedResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), response.ID)
+	assert.Equal(t, &feedTitle, response.Title)
+	assert.Equal(t, &customTitle, response.CustomTitle)
+	assert.Equal(t, []string{"tech"}, response.Tags)
+	mockFeedService.AssertExpectations(t)
+}
+
+func TestSubscribeFeed_MissingURL(t *testing.T) {
+	mockFeedService := new(MockFeedService)
+	handler := NewFeedHandler(mockFeedService)
+
+	router := setupTestRouter()
+	router.POST("/feeds", handler.Subscribe)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/feeds", bytes.NewBufferString(`{"title": "Example"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockFeedService.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListFeeds(t *testing.T) {
+	mockFeedService := new(MockFeedService)
+	handler := NewFeedHandler(mockFeedService)
+
+	router := setupTestRouter()
+	router.GET("/feeds", handler.ListFeeds)
+
+	lastError := "connection refused"
+	mockFeedService.On("ListSubscriptions", mock.Anything, testUserID).Return([]db.ListFeedSubscriptionsByUserRow{
+		{ID: 7, Url: "https://blog.example.com/feed.xml", LastError: &lastError},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/feeds", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []FeedResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, "https://blog.example.com/feed.xml", response[0].URL)
+	assert.Equal(t, &lastError, response[0].LastError)
+	mockFeedService.AssertExpectations(t)
+}
+
+func TestUpdateFeed(t *testing.T) {
+	mockFeedService := new(MockFeedService)
+	handler := NewFeedHandler(mockFeedService)
+
+	router := setupTestRouter()
+	router.PATCH("/feeds/:id", handler.UpdateFeed)
+
+	mockFeedService.On("UpdateSubscription", mock.Anything, testUserID, int32(7), (*string)(nil), []string{}).
+		Return(&db.Feed{ID: 7}, &db.FeedSubscription{FeedID: 7, UserID: testUserID, Tags: []string{}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/feeds/7", bytes.NewBufferString(`{"title": null, "tags": []}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockFeedService.AssertExpectations(t)
+}
+
+// opmlUpload builds a multipart request carrying an OPML file
+func opmlUpload(t *testing.T, path string, content string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "subscriptions.opml")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportOPML(t *testing.T) {
+	mockFeedService := new(MockFeedService)
+	handler := NewFeedHandler(mockFeedService)
+
+	router := setupTestRouter()
+	router.POST("/feeds/opml", handler.ImportOPML)
+
+	feedID := int32(7)
+	document := `<opml version="2.0"><body/></opml>`
+	mockFeedService.On("ImportOPML", mock.Anything, testUserID, []byte(document), true).Return([]services.OPMLImportResult{
+		{URL: "https://blog.example.com/feed.xml", Tags: []string{"Tech"}, Status: services.OPMLImportReachable, FeedID: &feedID},
+		{URL: "https://gone.example.com/feed.xml", Status: services.OPMLImportUnreachable, Error: "not found"},
+		{URL: "https://other.example.com/feed.xml", Status: services.OPMLImportReachable},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, opmlUpload(t, "/feeds/opml?dry_run=true", document))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response OPMLImportResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.DryRun)
+	assert.Len(t, response.Feeds, 3)
+	assert.Equal(t, "not found", response.Feeds[1].Error)
+	assert.Equal(t, map[string]int{"reachable": 2, "unreachable": 1}, response.Counts)
+	mockFeedService.AssertExpectations(t)
+}
+
+func TestImportOPML_BadRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      func(t *testing.T) *http.Request
+		setup        func(m *MockFeedService)
+		expectedCode int
+	}{
+		{
+			name: "missing file",
+			request: func(t *testing.T) *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, "/feeds/opml", nil)
+				return req
+			},
+			setup:        func(m *MockFeedService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid dry_run",
+			request: func(t *testing.T) *http.Request {
+				return opmlUpload(t, "/feeds/opml?dry_run=maybe", "<opml/>")
+			},
+			setup:        func(m *MockFeedService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "too large",
+			request: func(t *testing.T) *http.Request {
+				return opmlUpload(t, "/feeds/opml", strings.Repeat("x", services.MaxOPMLSize+1))
+			},
+			setup:        func(m *MockFeedService) {},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "not OPML",
+			request: func(t *testing.T) *http.Request {
+				return opmlUpload(t, "/feeds/opml", "<rss/>")
+			},
+			setup: func(m *MockFeedService) {
+				m.On("ImportOPML", mock.Anything, testUserID, []byte("<rss/>"), false).
+					Return(nil, fmt.Errorf("%w: unexpected <rss> document", services.ErrInvalidOPML))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFeedService := new(MockFeedService)
+			tt.setup(mockFeedService)
+			handler := NewFeedHandler(mockFeedService)
+
+			router := setupTestRouter()
+			router.POST("/feeds/opml", handler.ImportOPML)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.request(t))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockFeedService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportOPML(t *testing.T) {
+	mockFeedService := new(MockFeedService)
+	handler := NewFeedHandler(mockFeedService)
+
+	router := setupTestRouter()
+	router.GET("/feeds/opml", handler.ExportOPML)
+
+	document := []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<opml version="2.0"></opml>`)
+	mockFeedService.On("ExportOPML", mock.Anything, testUserID).Return(document, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/feeds/opml", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/x-opml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="briefbot-feeds-`)
+	assert.Equal(t, document, w.Body.Bytes())
+	mockFeedService.AssertExpectations(t)
+}
+
+func TestFeedRoutes_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setup        func(m *MockFeedService)
+		expectedCode int
+	}{
+		{
+			name:   "not a feed",
+			method: http.MethodPost,
+			path:   "/feeds",
+			body:   `{"url": "https://example.com/"}`,
+			setup: func(m *MockFeedService) {
+				m.On("Subscribe", mock.Anything, testUserID, "https://example.com/", (*string)(nil), []string(nil)).
+					Return(nil, nil, fmt.Errorf("%w: unexpected <html> document", services.ErrInvalidFeed))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "already subscribed",
+			method: http.MethodPost,
+			path:   "/feeds",
+			body:   `{"url": "https://blog.example.com/feed.xml"}`,
+			setup: func(m *MockFeedService) {
+				m.On("Subscribe", mock.Anything, testUserID, "https://blog.example.com/feed.xml", (*string)(nil), []string(nil)).
+					Return(nil, nil, services.ErrFeedSubscriptionExists)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "feed of another user",
+			method: http.MethodGet,
+			path:   "/feeds/9",
+			setup: func(m *MockFeedService) {
+				m.On("GetSubscription", mock.Anything, testUserID, int32(9)).Return(nil, nil, services.ErrFeedNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "unsubscribe from unknown feed",
+			method: http.MethodDelete,
+			path:   "/feeds/9",
+			setup: func(m *MockFeedService) {
+				m.On("Unsubscribe", mock.Anything, testUserID, int32(9)).Return(services.ErrFeedNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid feed ID",
+			method:       http.MethodDelete,
+			path:         "/feeds/abc",
+			setup:        func(m *MockFeedService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFeedService := new(MockFeedService)
+			tt.setup(mockFeedService)
+			handler := NewFeedHandler(mockFeedService)
+
+			router := setupTestRouter()
+			router.POST("/feeds", handler.Subscribe)
+			router.GET("/feeds/:id", handler.GetFeed)
+			router.DELETE("/feeds/:id", handler.Unsubscribe)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockFeedService.AssertExpectations(t)
+		})
+	}
+}
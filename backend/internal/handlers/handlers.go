@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/middleware"
 	"github.com/yamirghofran/briefbot/internal/services"
 )
 
@@ -10,7 +15,34 @@ type Handler struct {
 	itemService    services.ItemService
 	digestService  services.DigestService
 	podcastService services.PodcastService
+	authService    services.AuthService
 	sseManager     *services.SSEManager
+
+	preferencesService services.PreferencesService
+	workspaceService   services.WorkspaceService
+	feedService        services.FeedService
+	semanticService    services.SemanticService
+	tagService         services.TagService
+	collectionService  services.CollectionService
+
+	oidcService           services.OIDCService
+	oidcPostLoginRedirect string
+
+	rateLimits RouteRateLimits
+}
+
+// RouteRateLimits holds the rate limiting middleware for the API routes. A nil
+// entry disables that limiter.
+type RouteRateLimits struct {
+	// Auth applies to the public auth routes (sign-in, registration and
+	// password reset), keyed by client IP, to slow down password guessing
+	// and reset email floods
+	Auth gin.HandlerFunc
+	// API applies to every authenticated request
+	API gin.HandlerFunc
+	// Create additionally applies to item and podcast creation, which trigger
+	// scraping, LLM and TTS calls
+	Create gin.HandlerFunc
 }
 
 func NewHandler(userService services.UserService, itemService services.ItemService, digestService services.DigestService, podcastService services.PodcastService, sseManager *services.SSEManager) *Handler {
@@ -23,6 +55,60 @@ func NewHandler(userService services.UserService, itemService services.ItemServi
 	}
 }
 
+// SetAuthService sets the auth service used by the auth routes and middleware
+func (h *Handler) SetAuthService(authService services.AuthService) {
+	h.authService = authService
+}
+
+// SetPreferencesService sets the service behind the user preferences routes
+func (h *Handler) SetPreferencesService(preferencesService services.PreferencesService) {
+	h.preferencesService = preferencesService
+}
+
+// SetWorkspaceService sets the service behind the workspace routes
+func (h *Handler) SetWorkspaceService(workspaceService services.WorkspaceService) {
+	h.workspaceService = workspaceService
+}
+
+// SetFeedService sets the service behind the feed subscription routes
+func (h *Handler) SetFeedService(feedService services.FeedService) {
+	h.feedService = feedService
+}
+
+// SetSemanticService sets the service behind semantic search and related items
+func (h *Handler) SetSemanticService(semanticService services.SemanticService) {
+	h.semanticService = semanticService
+}
+
+// SetTagService sets the service behind the tag routes
+func (h *Handler) SetTagService(tagService services.TagService) {
+	h.tagService = tagService
+}
+
+// SetCollectionService sets the service behind the collection routes
+func (h *Handler) SetCollectionService(collectionService services.CollectionService) {
+	h.collectionService = collectionService
+}
+
+// SetOIDCService enables the OIDC sign-in routes
+func (h *Handler) SetOIDCService(oidcService services.OIDCService, postLoginRedirectURL string) {
+	h.oidcService = oidcService
+	h.oidcPostLoginRedirect = postLoginRedirectURL
+}
+
+// SetRateLimits sets the rate limiting middleware applied by SetupRoutes
+func (h *Handler) SetRateLimits(rateLimits RouteRateLimits) {
+	h.rateLimits = rateLimits
+}
+
+// limitCreate puts the create limiter in front of an expensive route when one is configured
+func (h *Handler) limitCreate(handler gin.HandlerFunc) []gin.HandlerFunc {
+	if h.rateLimits.Create == nil {
+		return []gin.HandlerFunc{handler}
+	}
+	return []gin.HandlerFunc{h.rateLimits.Create, handler}
+}
+
 // Code smell Improvement
 // Problem: The Handler struct depends on 5 different services and handles routing for multiple domains (users, items, podcasts, digests). This violates SRP as it has too many reasons to change.
 // The solution is to split into separate handlers
@@ -42,6 +128,41 @@ func NewHandler(userService services.UserService, itemService services.ItemServi
 //     digestService services.DigestService
 // }
 
+// respondWithError maps service errors onto HTTP responses. Resources owned by
+// another user are reported as missing so their existence is not revealed.
+func respondWithError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrPodcastNotFound),
+		errors.Is(err, services.ErrWorkspaceNotFound), errors.Is(err, services.ErrWorkspaceMemberMissing),
+		errors.Is(err, services.ErrFeedNotFound), errors.Is(err, services.ErrTagNotFound), errors.Is(err, services.ErrTagAliasNotFound),
+		errors.Is(err, services.ErrCollectionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWorkspaceForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidWorkspace), errors.Is(err, services.ErrInvalidUpload), errors.Is(err, services.ErrInvalidSource),
+		errors.Is(err, services.ErrInvalidFeed), errors.Is(err, services.ErrInvalidOPML), errors.Is(err, services.ErrInvalidSearch),
+		errors.Is(err, services.ErrInvalidListQuery), errors.Is(err, services.ErrInvalidTag), errors.Is(err, services.ErrInvalidCollection),
+		errors.Is(err, services.ErrInvalidAPIToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWorkspaceMemberExists), errors.Is(err, services.ErrLastWorkspaceOwner),
+		errors.Is(err, services.ErrFeedSubscriptionExists), errors.Is(err, services.ErrEmbeddingNotReady),
+		errors.Is(err, services.ErrTagExists), errors.Is(err, services.ErrCollectionExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrQuotaExceeded):
+		retryAfter := 0
+		var quotaErr *services.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			retryAfter = middleware.RetryAfterSeconds(quotaErr.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+		}
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": retryAfter})
+	case errors.Is(err, services.ErrUploadsUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *Handler) Health(c *gin.Context) {
 	c.JSON(200, gin.H{"status": "up"})
 }
@@ -50,48 +171,159 @@ func (h *Handler) SetupRoutes(router *gin.Engine) {
 	// Health check endpoint
 	router.GET("/health", h.Health)
 
+	// Public auth routes
+	authHandler := NewAuthHandler(h.authService)
+	publicAuthGroup := router.Group("/auth")
+	if h.rateLimits.Auth != nil {
+		publicAuthGroup.Use(h.rateLimits.Auth)
+	}
+	{
+		publicAuthGroup.POST("/register", authHandler.Register)
+		publicAuthGroup.POST("/login", authHandler.Login)
+		publicAuthGroup.POST("/password/forgot", authHandler.ForgotPassword)
+		publicAuthGroup.POST("/password/reset", authHandler.ResetPassword)
+
+		// OIDC sign-in is only available when an identity provider is configured
+		if h.oidcService != nil {
+			authHandler.SetOIDCService(h.oidcService, h.oidcPostLoginRedirect)
+			publicAuthGroup.GET("/oidc/login", authHandler.OIDCLogin)
+			publicAuthGroup.GET("/oidc/callback", authHandler.OIDCCallback)
+		}
+	}
+
+	// Every route below requires a session or API token
+	protected := router.Group("")
+	protected.Use(middleware.RequireAuth(h.authService))
+	if h.rateLimits.API != nil {
+		protected.Use(h.rateLimits.API)
+	}
+
+	// Account routes
+	authGroup := protected.Group("/auth")
+	{
+		authGroup.GET("/me", authHandler.Me)
+		authGroup.GET("/tokens", authHandler.ListAPITokens)
+		authGroup.POST("/tokens", authHandler.CreateAPIToken)
+		authGroup.DELETE("/tokens/:id", authHandler.RevokeAPIToken)
+	}
+
 	// User routes
-	userGroup := router.Group("/users")
+	userGroup := protected.Group("/users")
 	{
-		userGroup.GET("", h.ListUsers)
-		userGroup.POST("", h.CreateUser)
+		userGroup.PUT("/me", h.UpdateUser)
+		userGroup.PUT("/me/password", h.ChangePassword)
+		userGroup.GET("/me/preferences", h.GetPreferences)
+		userGroup.PATCH("/me/preferences", h.UpdatePreferences)
+		userGroup.DELETE("/me", h.DeleteUser)
 		userGroup.GET("/:id", h.GetUser)
-		userGroup.GET("/email/:email", h.GetUserByEmail)
-		userGroup.PUT("/:id", h.UpdateUser)
-		userGroup.DELETE("/:id", h.DeleteUser)
+		userGroup.GET("/:id/export", h.ExportUserData)
 	}
 
+	collectionHandler := NewCollectionHandler(h.collectionService)
+
 	// Item routes
-	itemGroup := router.Group("/items")
+	itemGroup := protected.Group("/items")
 	{
-		itemGroup.POST("", h.CreateItem)
+		itemGroup.POST("", h.limitCreate(h.CreateItem)...)
+		itemGroup.POST("/uploads", h.CreateItemUploadURL)
+		itemGroup.POST("/uploads/complete", h.limitCreate(h.CreateItemFromUpload)...)
+		itemGroup.POST("/content", h.limitCreate(h.CreateItemFromContent)...)
+		itemGroup.POST("/files", h.limitCreate(h.CreateItemFromFile)...)
+		itemGroup.GET("", h.GetItemsByUser)
+		itemGroup.GET("/unread", h.GetUnreadItemsByUser)
+		itemGroup.GET("/stream", h.StreamItemUpdates) // SSE endpoint
+		itemGroup.GET("/search", h.SearchItems)
+		itemGroup.GET("/semantic-search", h.SemanticSearchItems)
 		itemGroup.GET("/:id", h.GetItem)
+		itemGroup.GET("/:id/related", h.GetRelatedItems)
+		itemGroup.GET("/:id/collections", collectionHandler.ListItemCollections)
 		itemGroup.GET("/:id/status", h.GetItemProcessingStatus)
 		itemGroup.GET("/status", h.GetItemsByProcessingStatus)
-		itemGroup.GET("/user/:userID", h.GetItemsByUser)
-		itemGroup.GET("/user/:userID/unread", h.GetUnreadItemsByUser)
-		itemGroup.GET("/user/:userID/stream", h.StreamItemUpdates) // SSE endpoint
 		itemGroup.PUT("/:id", h.UpdateItem)
 		itemGroup.PATCH("/:id", h.PatchItem)
 		itemGroup.PATCH("/:id/read", h.MarkItemAsRead)
 		itemGroup.PATCH("/:id/toggle-read", h.ToggleItemReadStatus)
+		itemGroup.PUT("/:id/workspace", h.MoveItemToWorkspace)
 		itemGroup.DELETE("/:id", h.DeleteItem)
 	}
 
+	// Workspace routes
+	workspaceHandler := NewWorkspaceHandler(h.workspaceService)
+	workspaceGroup := protected.Group("/workspaces")
+	{
+		workspaceGroup.POST("", workspaceHandler.CreateWorkspace)
+		workspaceGroup.GET("", workspaceHandler.ListWorkspaces)
+		workspaceGroup.GET("/:id", workspaceHandler.GetWorkspace)
+		workspaceGroup.PATCH("/:id", workspaceHandler.RenameWorkspace)
+		workspaceGroup.DELETE("/:id", workspaceHandler.DeleteWorkspace)
+
+		// Membership management
+		workspaceGroup.GET("/:id/members", workspaceHandler.ListMembers)
+		workspaceGroup.POST("/:id/members", workspaceHandler.AddMember)
+		workspaceGroup.PATCH("/:id/members/:userID", workspaceHandler.UpdateMemberRole)
+		workspaceGroup.DELETE("/:id/members/:userID", workspaceHandler.RemoveMember)
+	}
+
+	// Feed subscription routes
+	feedHandler := NewFeedHandler(h.feedService)
+	feedGroup := protected.Group("/feeds")
+	{
+		feedGroup.POST("", h.limitCreate(feedHandler.Subscribe)...)
+		feedGroup.GET("", feedHandler.ListFeeds)
+		feedGroup.POST("/opml", h.limitCreate(feedHandler.ImportOPML)...)
+		feedGroup.GET("/opml", feedHandler.ExportOPML)
+		feedGroup.GET("/:id", feedHandler.GetFeed)
+		feedGroup.PATCH("/:id", feedHandler.UpdateFeed)
+		feedGroup.DELETE("/:id", feedHandler.Unsubscribe)
+	}
+
+	// Tag routes
+	tagHandler := NewTagHandler(h.tagService)
+	tagGroup := protected.Group("/tags")
+	{
+		tagGroup.GET("", tagHandler.ListTags)
+		tagGroup.POST("/merge", tagHandler.MergeTags)
+		tagGroup.POST("/rename", tagHandler.RenameTag)
+		tagGroup.DELETE("/aliases", tagHandler.DeleteTagAlias)
+	}
+
+	// Collection routes
+	collectionGroup := protected.Group("/collections")
+	{
+		collectionGroup.POST("", collectionHandler.CreateCollection)
+		collectionGroup.GET("", collectionHandler.ListCollections)
+		collectionGroup.GET("/:id", collectionHandler.GetCollection)
+		collectionGroup.PATCH("/:id", collectionHandler.UpdateCollection)
+		collectionGroup.DELETE("/:id", collectionHandler.DeleteCollection)
+
+		// Membership and ordering
+		collectionGroup.GET("/:id/items", collectionHandler.ListCollectionItems)
+		collectionGroup.POST("/:id/items", collectionHandler.AddCollectionItem)
+		collectionGroup.PUT("/:id/items/order", collectionHandler.ReorderCollectionItems)
+		collectionGroup.DELETE("/:id/items/:itemID", collectionHandler.RemoveCollectionItem)
+
+		// Podcasts and digests of a collection
+		collectionGroup.POST("/:id/podcast", h.limitCreate(collectionHandler.CreateCollectionPodcast)...)
+		// Digests are only available when email is configured
+		if h.digestService != nil {
+			collectionGroup.POST("/:id/digest", h.limitCreate(collectionHandler.SendCollectionDigest)...)
+		}
+	}
+
 	// Podcast routes
 	podcastHandler := NewPodcastHandler(h.podcastService)
 	podcastHandler.SetSSEManager(h.sseManager)
-	podcastGroup := router.Group("/podcasts")
+	podcastGroup := protected.Group("/podcasts")
 	{
 		// Podcast creation
-		podcastGroup.POST("", podcastHandler.CreatePodcast)
-		podcastGroup.POST("/from-item", podcastHandler.CreatePodcastFromSingleItem)
+		podcastGroup.POST("", h.limitCreate(podcastHandler.CreatePodcast)...)
+		podcastGroup.POST("/from-item", h.limitCreate(podcastHandler.CreatePodcastFromSingleItem)...)
 
 		// Podcast retrieval
+		podcastGroup.GET("", podcastHandler.GetPodcastsByUser)
+		podcastGroup.GET("/stream", podcastHandler.StreamPodcastUpdates) // SSE endpoint
 		podcastGroup.GET("/:id", podcastHandler.GetPodcast)
 		podcastGroup.GET("/:id/status", podcastHandler.GetPodcastProcessingStatus)
-		podcastGroup.GET("/user/:userID", podcastHandler.GetPodcastsByUser)
-		podcastGroup.GET("/user/:userID/stream", podcastHandler.StreamPodcastUpdates) // SSE endpoint
 		podcastGroup.GET("/status/:status", podcastHandler.GetPodcastsByStatus)
 		podcastGroup.GET("/pending", podcastHandler.GetPendingPodcasts)
 
@@ -110,11 +342,9 @@ func (h *Handler) SetupRoutes(router *gin.Engine) {
 	}
 
 	// Digest routes (unified - handles both regular and integrated digests)
-	digestGroup := router.Group("/digest")
+	digestGroup := protected.Group("/digest")
 	{
-		digestGroup.POST("/trigger", h.TriggerDailyDigest)
-		digestGroup.POST("/trigger/user/:userID", h.TriggerDailyDigestForUser)
-		digestGroup.POST("/trigger/integrated", h.TriggerIntegratedDigest)
-		digestGroup.POST("/trigger/integrated/user/:userID", h.TriggerIntegratedDigestForUser)
+		digestGroup.POST("/trigger/me", h.TriggerDailyDigestForUser)
+		digestGroup.POST("/trigger/integrated/me", h.TriggerIntegratedDigestForUser)
 	}
 }
@@ -1,24 +1,42 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
 )
 
 // CreateItem godoc
 // @Summary      Create a new content item
-// @Description  Create a new content item from URL with async processing
+// @Description  Create a new content item from URL with async processing. URLs are compared in canonical form, without tracking parameters and after redirects; when the user already saved the page, the existing item is returned with duplicate set and nothing is queued. A saved page that failed to process is queued again and returned as a new submission.
 // @Tags         items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        item  body      CreateItemRequest  true  "Item creation request"
+// @Success      200   {object}  CreateItemResponse  "The page was already saved"
 // @Success      201   {object}  CreateItemResponse
 // @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse  "Rate limit or daily item quota exceeded; see Retry-After"
 // @Failure      500   {object}  ErrorResponse
 // @Router       /items [post]
 func (h *Handler) CreateItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var req CreateItemRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -27,15 +45,224 @@ func (h *Handler) CreateItem(c *gin.Context) {
 	}
 
 	// Use async creation - just save the URL and return immediately
-	item, err := h.itemService.CreateItemAsync(c.Request.Context(), *req.UserID, *req.URL)
+	item, duplicate, err := h.itemService.CreateItemAsync(c.Request.Context(), userID, *req.URL, req.WorkspaceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
+		return
+	}
+
+	if duplicate {
+		readState, err := h.itemService.GetReadState(c.Request.Context(), userID, []int32{item.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"item":              newItemResponse(*item, readState[item.ID]),
+			"message":           "Item already saved",
+			"processing_status": item.ProcessingStatus,
+			"duplicate":         true,
+		})
 		return
 	}
 
 	// Return the item with pending status
 	c.JSON(http.StatusCreated, gin.H{
-		"item":              item,
+		"item":              newItemResponse(*item, false),
+		"message":           "Item created successfully and will be processed in the background",
+		"processing_status": item.ProcessingStatus,
+		"duplicate":         false,
+	})
+}
+
+// CreateItemUploadURL godoc
+// @Summary      Generate an upload URL for a file item
+// @Description  Generate a presigned URL for uploading a PDF to R2 storage. PUT the file to upload_url with the same Content-Type, then call POST /items/uploads/complete with the key.
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        upload  body      CreateUploadURLRequest  true  "File content type"
+// @Success      200     {object}  UploadURLResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Failure      503     {object}  ErrorResponse  "File uploads not configured"
+// @Router       /items/uploads [post]
+func (h *Handler) CreateItemUploadURL(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateUploadURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	uploadInfo, err := h.itemService.CreateUploadURL(c.Request.Context(), userID, req.ContentType)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, uploadInfo)
+}
+
+// CreateItemFromUpload godoc
+// @Summary      Create an item from an uploaded file
+// @Description  Create a content item from a file uploaded through POST /items/uploads. The file is processed in the background like a URL.
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        item  body      CreateItemFromUploadRequest  true  "Uploaded file"
+// @Success      201   {object}  CreateItemResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse  "Rate limit or daily item quota exceeded; see Retry-After"
+// @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse  "File uploads not configured"
+// @Router       /items/uploads/complete [post]
+func (h *Handler) CreateItemFromUpload(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateItemFromUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.itemService.CreateItemFromUpload(c.Request.Context(), userID, req.Key, req.WorkspaceID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"item":              newItemResponse(*item, false),
+		"message":           "Item created successfully and will be processed in the background",
+		"processing_status": item.ProcessingStatus,
+	})
+}
+
+// CreateItemFromContent godoc
+// @Summary      Create an item from pasted content
+// @Description  Create a content item from pasted text, HTML or Markdown, such as a newsletter or a note. The content is stored with the item and processed in the background without scraping.
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        item  body      CreateItemFromContentRequest  true  "Pasted content"
+// @Success      201   {object}  CreateItemResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse  "Rate limit or daily item quota exceeded; see Retry-After"
+// @Failure      500   {object}  ErrorResponse
+// @Router       /items/content [post]
+func (h *Handler) CreateItemFromContent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateItemFromContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.itemService.CreateItemFromSource(c.Request.Context(), userID, req.Title, req.SourceType, req.Content, req.WorkspaceID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"item":              newItemResponse(*item, false),
+		"message":           "Item created successfully and will be processed in the background",
+		"processing_status": item.ProcessingStatus,
+	})
+}
+
+// CreateItemFromFile godoc
+// @Summary      Create an item from a file
+// @Description  Create a content item from an uploaded text, Markdown, HTML, XHTML (e.g. an EPUB chapter) or PDF file of up to 5 MB. The file's text is stored with the item and processed in the background without scraping.
+// @Tags         items
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file          formData  file    true   "File to create the item from"
+// @Param        title         formData  string  false  "Title; extracted from the content when empty"
+// @Param        workspace_id  formData  int     false  "Workspace to share the item with"
+// @Success      201   {object}  CreateItemResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      413   {object}  ErrorResponse
+// @Failure      429   {object}  ErrorResponse  "Rate limit or daily item quota exceeded; see Retry-After"
+// @Failure      500   {object}  ErrorResponse
+// @Router       /items/files [post]
+func (h *Handler) CreateItemFromFile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart headers and the other fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxSourceSize+64<<10)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required"})
+		return
+	}
+
+	var workspaceID *int32
+	if value := c.PostForm("workspace_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+			return
+		}
+		id32 := int32(id)
+		workspaceID = &id32
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	item, err := h.itemService.CreateItemFromFile(c.Request.Context(), userID, c.PostForm("title"), fileHeader.Filename, fileHeader.Header.Get("Content-Type"), data, workspaceID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"item":              newItemResponse(*item, false),
 		"message":           "Item created successfully and will be processed in the background",
 		"processing_status": item.ProcessingStatus,
 	})
@@ -46,12 +273,20 @@ func (h *Handler) CreateItem(c *gin.Context) {
 // @Description  Retrieve a content item's information by its ID
 // @Tags         items
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Item ID"
-// @Success      200  {object}  github_com_yamirghofran_briefbot_internal_db.Item
+// @Success      200  {object}  ItemResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /items/{id} [get]
 func (h *Handler) GetItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -59,69 +294,118 @@ func (h *Handler) GetItem(c *gin.Context) {
 		return
 	}
 
-	item, err := h.itemService.GetItem(c.Request.Context(), int32(id))
+	item, err := h.itemService.GetItem(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	h.respondWithItem(c, userID, *item)
 }
 
 // GetItemsByUser godoc
-// @Summary      Get items by user
-// @Description  Retrieve all content items for a specific user
+// @Summary      List items
+// @Description  Lists the authenticated user's items and those shared with their workspaces, one page at a time. Pass the returned next_cursor as cursor to get the following page; it is null on the last page. A cursor only works with the sort and order it was issued for.
 // @Tags         items
 // @Produce      json
-// @Param        userID  path      int  true  "User ID"
-// @Success      200     {array}   github_com_yamirghofran_briefbot_internal_db.Item
-// @Failure      400     {object}  ErrorResponse
-// @Failure      500     {object}  ErrorResponse
-// @Router       /items/user/{userID} [get]
+// @Security     BearerAuth
+// @Param        workspace_id  query     int       false  "Only list items shared with this workspace"
+// @Param        tag           query     []string  false  "Only items with all of these tags, matched in their normalized form and through your tag aliases"  collectionFormat(multi)
+// @Param        platform      query     string    false  "Only items from this platform"
+// @Param        type          query     string    false  "Only items of this type"
+// @Param        is_read       query     bool      false  "Only read or only unread items"
+// @Param        status        query     string    false  "Only items with this processing status (pending, processing, completed, failed)"
+// @Param        from          query     string    false  "Only items saved on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param        to            query     string    false  "Only items saved up to this date, inclusive (YYYY-MM-DD), or before this time (RFC 3339)"
+// @Param        sort          query     string    false  "Sort by created_at or title"  default(created_at)
+// @Param        order         query     string    false  "asc or desc; defaults to desc for created_at and asc for title"
+// @Param        limit         query     int       false  "Page size (1-200)"  default(50)
+// @Param        cursor        query     string    false  "next_cursor of the previous page"
+// @Success      200           {object}  ItemListResponse
+// @Failure      400           {object}  ErrorResponse
+// @Failure      401           {object}  ErrorResponse
+// @Failure      404           {object}  ErrorResponse
+// @Failure      500           {object}  ErrorResponse
+// @Router       /items [get]
 func (h *Handler) GetItemsByUser(c *gin.Context) {
-	userIDStr := c.Param("userID")
-	userID, err := strconv.ParseInt(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	userID32 := int32(userID)
-	items, err := h.itemService.GetItemsByUser(c.Request.Context(), &userID32)
+	query := services.ItemListQuery{
+		Tags:     c.QueryArray("tag"),
+		Platform: optionalQuery(c, "platform"),
+		Type:     optionalQuery(c, "type"),
+		Status:   optionalQuery(c, "status"),
+	}
+	if query.WorkspaceID, ok = workspaceIDQuery(c); !ok {
+		return
+	}
+	if query.IsRead, ok = boolQuery(c, "is_read"); !ok {
+		return
+	}
+	if query.CreatedAfter, ok = dateQuery(c, "from", false); !ok {
+		return
+	}
+	if query.CreatedBefore, ok = dateQuery(c, "to", true); !ok {
+		return
+	}
+	if query.Page, ok = pageQuery(c); !ok {
+		return
+	}
+
+	page, err := h.itemService.ListItems(c.Request.Context(), userID, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, items)
+	c.JSON(http.StatusOK, ItemListResponse{
+		Items:      newItemResponses(page.Items, page.ReadState),
+		Count:      len(page.Items),
+		NextCursor: optionalCursor(page.NextCursor),
+	})
 }
 
 // GetUnreadItemsByUser godoc
 // @Summary      Get unread items by user
-// @Description  Retrieve all unread content items for a specific user
+// @Description  Retrieve the items the authenticated user has not read, optionally limited to one workspace
 // @Tags         items
 // @Produce      json
-// @Param        userID  path      int  true  "User ID"
-// @Success      200     {array}   github_com_yamirghofran_briefbot_internal_db.Item
-// @Failure      400     {object}  ErrorResponse
-// @Failure      500     {object}  ErrorResponse
-// @Router       /items/user/{userID}/unread [get]
+// @Security     BearerAuth
+// @Param        workspace_id  query     int  false  "Only list items shared with this workspace"
+// @Success      200           {array}   ItemResponse
+// @Failure      400           {object}  ErrorResponse
+// @Failure      401           {object}  ErrorResponse
+// @Failure      404           {object}  ErrorResponse
+// @Failure      500           {object}  ErrorResponse
+// @Router       /items/unread [get]
 func (h *Handler) GetUnreadItemsByUser(c *gin.Context) {
-	userIDStr := c.Param("userID")
-	userID, err := strconv.ParseInt(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	userID32 := int32(userID)
-	items, err := h.itemService.GetUnreadItemsByUser(c.Request.Context(), &userID32)
+	workspaceID, ok := workspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var items []db.Item
+	var err error
+	if workspaceID != nil {
+		items, err = h.itemService.GetUnreadItemsByWorkspace(c.Request.Context(), userID, *workspaceID)
+	} else {
+		items, err = h.itemService.GetUnreadItemsByUser(c.Request.Context(), &userID)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, items)
+	// Every item in this list is unread for the current user
+	c.JSON(http.StatusOK, newItemResponses(items, nil))
 }
 
 // UpdateItem godoc
@@ -130,13 +414,21 @@ func (h *Handler) GetUnreadItemsByUser(c *gin.Context) {
 // @Tags         items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id    path      int                 true  "Item ID"
 // @Param        item  body      UpdateItemRequest   true  "Item update request"
 // @Success      200   {object}  MessageResponse
 // @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /items/{id} [put]
 func (h *Handler) UpdateItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -151,26 +443,42 @@ func (h *Handler) UpdateItem(c *gin.Context) {
 		return
 	}
 
-	err = h.itemService.UpdateItem(c.Request.Context(), int32(id), req.Title, req.URL, req.TextContent, req.Summary, req.Type, req.Platform, req.Tags, req.Authors, req.IsRead)
+	err = h.itemService.UpdateItem(c.Request.Context(), userID, int32(id), req.Title, req.URL, req.TextContent, req.Summary, req.Type, req.Platform, req.Tags, req.Authors)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
+	// is_read applies to the current user's read state rather than the item itself
+	if req.IsRead != nil {
+		if err := h.itemService.SetItemReadStatus(c.Request.Context(), userID, int32(id), *req.IsRead); err != nil {
+			respondWithError(c, err)
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Item updated successfully"})
 }
 
 // MarkItemAsRead godoc
 // @Summary      Mark item as read
-// @Description  Mark a content item as read
+// @Description  Mark a content item as read for the authenticated user
 // @Tags         items
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Item ID"
-// @Success      200  {object}  github_com_yamirghofran_briefbot_internal_db.Item
+// @Success      200  {object}  ItemResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /items/{id}/read [patch]
 func (h *Handler) MarkItemAsRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -178,33 +486,41 @@ func (h *Handler) MarkItemAsRead(c *gin.Context) {
 		return
 	}
 
-	err = h.itemService.MarkItemAsRead(c.Request.Context(), int32(id))
+	err = h.itemService.MarkItemAsRead(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
 	// Return the updated item
-	item, err := h.itemService.GetItem(c.Request.Context(), int32(id))
+	item, err := h.itemService.GetItem(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	c.JSON(http.StatusOK, newItemResponse(*item, true))
 }
 
 // ToggleItemReadStatus godoc
 // @Summary      Toggle item read status
-// @Description  Toggle a content item's read/unread status
+// @Description  Toggle a content item's read/unread status for the authenticated user
 // @Tags         items
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Item ID"
-// @Success      200  {object}  github_com_yamirghofran_briefbot_internal_db.Item
+// @Success      200  {object}  ItemResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /items/{id}/toggle-read [patch]
 func (h *Handler) ToggleItemReadStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -212,13 +528,13 @@ func (h *Handler) ToggleItemReadStatus(c *gin.Context) {
 		return
 	}
 
-	item, err := h.itemService.ToggleItemReadStatus(c.Request.Context(), int32(id))
+	item, isRead, err := h.itemService.ToggleItemReadStatus(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	c.JSON(http.StatusOK, newItemResponse(*item, isRead))
 }
 
 // PatchItem godoc
@@ -227,13 +543,21 @@ func (h *Handler) ToggleItemReadStatus(c *gin.Context) {
 // @Tags         items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id    path      int                true  "Item ID"
 // @Param        item  body      PatchItemRequest   true  "Item patch request"
-// @Success      200   {object}  github_com_yamirghofran_briefbot_internal_db.Item
+// @Success      200   {object}  ItemResponse
 // @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /items/{id} [patch]
 func (h *Handler) PatchItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -248,13 +572,57 @@ func (h *Handler) PatchItem(c *gin.Context) {
 		return
 	}
 
-	item, err := h.itemService.PatchItem(c.Request.Context(), int32(id), req.Title, req.Summary, req.Tags, req.Authors)
+	item, err := h.itemService.PatchItem(c.Request.Context(), userID, int32(id), req.Title, req.Summary, req.Tags, req.Authors)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, item)
+	h.respondWithItem(c, userID, *item)
+}
+
+// MoveItemToWorkspace godoc
+// @Summary      Move an item to a workspace
+// @Description  Share one of the authenticated user's items with a workspace, or make it personal again with a null workspace_id
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                 true  "Item ID"
+// @Param        move  body      MoveItemRequest     true  "Target workspace"
+// @Success      200   {object}  ItemResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      403   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /items/{id}/workspace [put]
+func (h *Handler) MoveItemToWorkspace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req MoveItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.itemService.MoveItemToWorkspace(c.Request.Context(), userID, int32(id), req.WorkspaceID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.respondWithItem(c, userID, *item)
 }
 
 // DeleteItem godoc
@@ -262,12 +630,20 @@ func (h *Handler) PatchItem(c *gin.Context) {
 // @Description  Delete a content item from the system
 // @Tags         items
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Item ID"
 // @Success      200  {object}  MessageResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /items/{id} [delete]
 func (h *Handler) DeleteItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -275,9 +651,9 @@ func (h *Handler) DeleteItem(c *gin.Context) {
 		return
 	}
 
-	err = h.itemService.DeleteItem(c.Request.Context(), int32(id))
+	err = h.itemService.DeleteItem(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -289,12 +665,20 @@ func (h *Handler) DeleteItem(c *gin.Context) {
 // @Description  Retrieve the processing status of a content item
 // @Tags         items
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Item ID"
 // @Success      200  {object}  ItemProcessingStatusResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /items/{id}/status [get]
 func (h *Handler) GetItemProcessingStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -302,9 +686,9 @@ func (h *Handler) GetItemProcessingStatus(c *gin.Context) {
 		return
 	}
 
-	status, err := h.itemService.GetItemProcessingStatus(c.Request.Context(), int32(id))
+	status, err := h.itemService.GetItemProcessingStatus(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -315,20 +699,32 @@ func (h *Handler) GetItemProcessingStatus(c *gin.Context) {
 		"is_completed":      status.IsCompleted,
 		"is_failed":         status.IsFailed,
 		"processing_error":  status.ProcessingError,
+		"failure_reason":    status.FailureReason,
 	})
 }
 
 // GetItemsByProcessingStatus godoc
 // @Summary      Get items by processing status
-// @Description  Retrieve content items filtered by their processing status
+// @Description  Lists the items the authenticated user can see with a processing status, one page at a time, newest first unless sorted otherwise
 // @Tags         items
 // @Produce      json
+// @Security     BearerAuth
 // @Param        status  query     string  false  "Processing status (pending, processing, completed, failed)"  default(pending)
+// @Param        sort    query     string  false  "Sort by created_at or title"  default(created_at)
+// @Param        order   query     string  false  "asc or desc; defaults to desc for created_at and asc for title"
+// @Param        limit   query     int     false  "Page size (1-200)"  default(50)
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
 // @Success      200     {object}  ItemsByStatusResponse
 // @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
 // @Router       /items/status [get]
 func (h *Handler) GetItemsByProcessingStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	status := c.Query("status")
 	if status == "" {
 		status = "pending"
@@ -347,15 +743,296 @@ func (h *Handler) GetItemsByProcessingStatus(c *gin.Context) {
 		return
 	}
 
-	items, err := h.itemService.GetItemsByProcessingStatus(c.Request.Context(), &status)
+	pageRequest, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.itemService.ListItems(c.Request.Context(), userID, services.ItemListQuery{
+		Status: &status,
+		Page:   pageRequest,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": status,
-		"items":  items,
-		"count":  len(items),
+	c.JSON(http.StatusOK, ItemsByStatusResponse{
+		Status:     status,
+		Items:      newItemResponses(page.Items, page.ReadState),
+		Count:      len(page.Items),
+		NextCursor: optionalCursor(page.NextCursor),
+	})
+}
+
+// SearchItems godoc
+// @Summary      Search items
+// @Description  Full-text search over the authenticated user's items and those shared with their workspaces, best matches first. The query supports "quoted phrases", OR and -excluded words. Highlights are HTML with matches wrapped in <mark>.
+// @Tags         items
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q         query     string    true   "Search query"
+// @Param        tag       query     []string  false  "Only items with all of these tags, matched in their normalized form and through your tag aliases"  collectionFormat(multi)
+// @Param        platform  query     string    false  "Only items from this platform"
+// @Param        type      query     string    false  "Only items of this type"
+// @Param        is_read   query     bool      false  "Only read or only unread items"
+// @Param        from      query     string    false  "Only items saved on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param        to        query     string    false  "Only items saved up to this date, inclusive (YYYY-MM-DD), or before this time (RFC 3339)"
+// @Param        limit     query     int       false  "Maximum number of results (1-100)"  default(20)
+// @Param        offset    query     int       false  "Number of results to skip"  default(0)
+// @Success      200       {object}  SearchItemsResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      401       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /items/search [get]
+func (h *Handler) SearchItems(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	search := services.ItemSearch{
+		Query:    c.Query("q"),
+		Tags:     c.QueryArray("tag"),
+		Platform: optionalQuery(c, "platform"),
+		Type:     optionalQuery(c, "type"),
+	}
+	if search.IsRead, ok = boolQuery(c, "is_read"); !ok {
+		return
+	}
+	if search.CreatedAfter, ok = dateQuery(c, "from", false); !ok {
+		return
+	}
+	if search.CreatedBefore, ok = dateQuery(c, "to", true); !ok {
+		return
+	}
+	for name, target := range map[string]*int{"limit": &search.Limit, "offset": &search.Offset} {
+		if value := c.Query(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s value", name)})
+				return
+			}
+			*target = n
+		}
+	}
+
+	results, err := h.itemService.SearchItems(c.Request.Context(), userID, search)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSearchItemsResponse(search.Query, results))
+}
+
+// SemanticSearchItems godoc
+// @Summary      Search items by meaning
+// @Description  Finds the authenticated user's items, and those shared with their workspaces, whose content is closest in meaning to the query, even when they share no keywords with it. Only items that have been embedded are searched.
+// @Tags         items
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q      query     string  true   "Search query"
+// @Param        limit  query     int     false  "Maximum number of results (1-100)"  default(20)
+// @Success      200    {object}  SemanticSearchResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
+// @Router       /items/semantic-search [get]
+func (h *Handler) SemanticSearchItems(c *gin.Context) {
+	if h.semanticService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Semantic search not available"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	limit, ok := limitQuery(c)
+	if !ok {
+		return
+	}
+
+	query := c.Query("q")
+	results, err := h.semanticService.SearchItems(c.Request.Context(), userID, query, limit)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SemanticSearchResponse{
+		Query:   query,
+		Results: newSemanticResultResponses(results),
+		Count:   len(results),
 	})
 }
+
+// GetRelatedItems godoc
+// @Summary      Get related items
+// @Description  Lists the items closest in meaning to an item, among those the authenticated user can see. Returns 409 until the item has been embedded, shortly after it finishes processing.
+// @Tags         items
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int  true   "Item ID"
+// @Param        limit  query     int  false  "Maximum number of results (1-100)"  default(20)
+// @Success      200    {object}  RelatedItemsResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      409    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Failure      503    {object}  ErrorResponse
+// @Router       /items/{id}/related [get]
+func (h *Handler) GetRelatedItems(c *gin.Context) {
+	if h.semanticService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Semantic search not available"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+	limit, ok := limitQuery(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.semanticService.RelatedItems(c.Request.Context(), userID, int32(id), limit)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RelatedItemsResponse{
+		ItemID:  int32(id),
+		Results: newSemanticResultResponses(results),
+		Count:   len(results),
+	})
+}
+
+// respondWithItem writes a single item with the user's read state
+func (h *Handler) respondWithItem(c *gin.Context, userID int32, item db.Item) {
+	readState, err := h.itemService.GetReadState(c.Request.Context(), userID, []int32{item.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newItemResponse(item, readState[item.ID]))
+}
+
+// itemResponses attaches the user's read state to a list of items, writing an
+// error response and returning false if it cannot be loaded
+func (h *Handler) itemResponses(c *gin.Context, userID int32, items []db.Item) ([]ItemResponse, bool) {
+	ids := make([]int32, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	readState, err := h.itemService.GetReadState(c.Request.Context(), userID, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return newItemResponses(items, readState), true
+}
+
+// workspaceIDQuery parses the optional workspace_id query parameter
+func workspaceIDQuery(c *gin.Context) (*int32, bool) {
+	value := c.Query("workspace_id")
+	if value == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return nil, false
+	}
+	workspaceID := int32(id)
+	return &workspaceID, true
+}
+
+// limitQuery parses the optional limit query parameter; zero means the default
+func limitQuery(c *gin.Context) (int, bool) {
+	value := c.Query("limit")
+	if value == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit value"})
+		return 0, false
+	}
+	return limit, true
+}
+
+// pageQuery parses the sort, order, limit and cursor query parameters of a paginated list
+func pageQuery(c *gin.Context) (services.PageQuery, bool) {
+	limit, ok := limitQuery(c)
+	if !ok {
+		return services.PageQuery{}, false
+	}
+	return services.PageQuery{
+		Sort:   c.Query("sort"),
+		Order:  c.Query("order"),
+		Limit:  limit,
+		Cursor: c.Query("cursor"),
+	}, true
+}
+
+// optionalCursor returns the next page cursor, or nil on the last page
+func optionalCursor(cursor string) *string {
+	if cursor == "" {
+		return nil
+	}
+	return &cursor
+}
+
+// boolQuery parses an optional boolean query parameter
+func boolQuery(c *gin.Context, name string) (*bool, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s value", name)})
+		return nil, false
+	}
+	return &b, true
+}
+
+// optionalQuery returns a query parameter, or nil when it is missing or blank
+func optionalQuery(c *gin.Context, name string) *string {
+	value := strings.TrimSpace(c.Query(name))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// dateQuery parses an optional date or timestamp query parameter. A plain date
+// as the end of a range includes the whole day, so it becomes the next midnight.
+func dateQuery(c *gin.Context, name string, endOfRange bool) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, true
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s date, expected YYYY-MM-DD or RFC 3339", name)})
+		return nil, false
+	}
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, true
+}
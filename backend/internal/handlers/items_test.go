@@ -5,12 +5,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/yamirghofran/briefbot/internal/db"
 	"github.com/yamirghofran/briefbot/internal/services"
 )
@@ -19,7 +24,15 @@ type MockItemService struct {
 	mock.Mock
 }
 
-func (m *MockItemService) CreateItemAsync(ctx context.Context, userID int32, url string) (*db.Item, error) {
+func (m *MockItemService) CreateItemAsync(ctx context.Context, userID int32, url string, workspaceID *int32) (*db.Item, bool, error) {
+	args := m.Called(ctx, userID, url, workspaceID)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*db.Item), args.Bool(1), args.Error(2)
+}
+
+func (m *MockItemService) ProcessURL(ctx context.Context, userID int32, url string) (*db.Item, error) {
 	args := m.Called(ctx, userID, url)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -27,8 +40,32 @@ func (m *MockItemService) CreateItemAsync(ctx context.Context, userID int32, url
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) ProcessURL(ctx context.Context, userID int32, url string) (*db.Item, error) {
-	args := m.Called(ctx, userID, url)
+func (m *MockItemService) CreateUploadURL(ctx context.Context, userID int32, contentType string) (*services.UploadURLResponse, error) {
+	args := m.Called(ctx, userID, contentType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.UploadURLResponse), args.Error(1)
+}
+
+func (m *MockItemService) CreateItemFromUpload(ctx context.Context, userID int32, key string, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, key, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Item), args.Error(1)
+}
+
+func (m *MockItemService) CreateItemFromSource(ctx context.Context, userID int32, title string, sourceType string, content string, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, title, sourceType, content, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Item), args.Error(1)
+}
+
+func (m *MockItemService) CreateItemFromFile(ctx context.Context, userID int32, title string, filename string, contentType string, data []byte, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, title, filename, contentType, data, workspaceID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -43,8 +80,8 @@ func (m *MockItemService) CreateItem(ctx context.Context, userID *int32, title s
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) GetItem(ctx context.Context, id int32) (*db.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockItemService) GetItem(ctx context.Context, userID int32, id int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -66,50 +103,111 @@ func (m *MockItemService) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]
 	return args.Get(0).([]db.Item), args.Error(1)
 }
 
-func (m *MockItemService) UpdateItem(ctx context.Context, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string, isRead *bool) error {
-	args := m.Called(ctx, id, title, url, textContent, summary, itemType, platform, tags, authors, isRead)
+func (m *MockItemService) UpdateItem(ctx context.Context, userID int32, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string) error {
+	args := m.Called(ctx, userID, id, title, url, textContent, summary, itemType, platform, tags, authors)
 	return args.Error(0)
 }
 
-func (m *MockItemService) PatchItem(ctx context.Context, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error) {
-	args := m.Called(ctx, id, title, summary, tags, authors)
+func (m *MockItemService) PatchItem(ctx context.Context, userID int32, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error) {
+	args := m.Called(ctx, userID, id, title, summary, tags, authors)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) MarkItemAsRead(ctx context.Context, id int32) error {
-	args := m.Called(ctx, id)
+func (m *MockItemService) MarkItemAsRead(ctx context.Context, userID int32, id int32) error {
+	args := m.Called(ctx, userID, id)
 	return args.Error(0)
 }
 
-func (m *MockItemService) ToggleItemReadStatus(ctx context.Context, id int32) (*db.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockItemService) SetItemReadStatus(ctx context.Context, userID int32, id int32, isRead bool) error {
+	args := m.Called(ctx, userID, id, isRead)
+	return args.Error(0)
+}
+
+func (m *MockItemService) ToggleItemReadStatus(ctx context.Context, userID int32, id int32) (*db.Item, bool, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*db.Item), args.Bool(1), args.Error(2)
+}
+
+func (m *MockItemService) GetReadState(ctx context.Context, userID int32, itemIDs []int32) (map[int32]bool, error) {
+	args := m.Called(ctx, userID, itemIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int32]bool), args.Error(1)
+}
+
+func (m *MockItemService) SetQuotaService(quotaService services.QuotaService) {
+	m.Called(quotaService)
+}
+
+func (m *MockItemService) SetUploadStore(uploadStore services.UploadStore) {
+	m.Called(uploadStore)
+}
+
+func (m *MockItemService) GetItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error) {
+	args := m.Called(ctx, userID, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockItemService) GetUnreadItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error) {
+	args := m.Called(ctx, userID, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockItemService) MoveItemToWorkspace(ctx context.Context, userID int32, id int32, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, id, workspaceID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) DeleteItem(ctx context.Context, id int32) error {
-	args := m.Called(ctx, id)
+func (m *MockItemService) DeleteItem(ctx context.Context, userID int32, id int32) error {
+	args := m.Called(ctx, userID, id)
 	return args.Error(0)
 }
 
-func (m *MockItemService) GetItemProcessingStatus(ctx context.Context, itemID int32) (*services.ItemStatus, error) {
-	args := m.Called(ctx, itemID)
+func (m *MockItemService) GetItemProcessingStatus(ctx context.Context, userID int32, itemID int32) (*services.ItemStatus, error) {
+	args := m.Called(ctx, userID, itemID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*services.ItemStatus), args.Error(1)
 }
 
-func (m *MockItemService) GetItemsByProcessingStatus(ctx context.Context, status *string) ([]db.Item, error) {
-	args := m.Called(ctx, status)
+func (m *MockItemService) GetItemsByProcessingStatus(ctx context.Context, userID int32, status *string) ([]db.Item, error) {
+	args := m.Called(ctx, userID, status)
 	return args.Get(0).([]db.Item), args.Error(1)
 }
 
+func (m *MockItemService) SearchItems(ctx context.Context, userID int32, search services.ItemSearch) ([]services.SearchResult, error) {
+	args := m.Called(ctx, userID, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.SearchResult), args.Error(1)
+}
+
+func (m *MockItemService) ListItems(ctx context.Context, userID int32, query services.ItemListQuery) (*services.ItemPage, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ItemPage), args.Error(1)
+}
+
 func TestCreateItem(t *testing.T) {
 	mockItemService := new(MockItemService)
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
@@ -128,11 +226,10 @@ func TestCreateItem(t *testing.T) {
 		ProcessingStatus: &status,
 	}
 
-	mockItemService.On("CreateItemAsync", mock.Anything, userID, url).Return(expectedItem, nil)
+	mockItemService.On("CreateItemAsync", mock.Anything, userID, url, (*int32)(nil)).Return(expectedItem, false, nil)
 
 	reqBody := map[string]interface{}{
-		"user_id": userID,
-		"url":     url,
+		"url": url,
 	}
 	jsonBody, _ := json.Marshal(reqBody)
 
@@ -143,9 +240,238 @@ func TestCreateItem(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"duplicate":false`)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestCreateItem_Duplicate(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items", handler.CreateItem)
+
+	userID := int32(1)
+	url := "https://example.com/post?utm_source=newsletter"
+	saved := "https://example.com/post"
+	status := "completed"
+	existing := &db.Item{ID: 7, UserID: &userID, Url: &saved, ProcessingStatus: &status}
+
+	mockItemService.On("CreateItemAsync", mock.Anything, userID, url, (*int32)(nil)).Return(existing, true, nil)
+	mockItemService.On("GetReadState", mock.Anything, userID, []int32{7}).Return(map[int32]bool{7: true}, nil)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"url": url})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, true, response["duplicate"])
+	item := response["item"].(map[string]interface{})
+	assert.Equal(t, float64(7), item["id"])
+	assert.Equal(t, true, item["is_read"])
+	mockItemService.AssertExpectations(t)
+}
+
+func TestCreateItemUploadURL(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items/uploads", handler.CreateItemUploadURL)
+
+	userID := int32(1)
+	uploadInfo := &services.UploadURLResponse{
+		UploadURL: "https://r2.example.com/put",
+		Key:       "uploads/1/abc.pdf",
+		PublicURL: "https://files.example.com/uploads/1/abc.pdf",
+	}
+	mockItemService.On("CreateUploadURL", mock.Anything, userID, "application/pdf").Return(uploadInfo, nil)
+	mockItemService.On("CreateUploadURL", mock.Anything, userID, "image/png").Return(nil, fmt.Errorf("%w: unsupported content type", services.ErrInvalidUpload))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items/uploads", bytes.NewBufferString(`{"content_type":"application/pdf"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response services.UploadURLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, *uploadInfo, response)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/items/uploads", bytes.NewBufferString(`{"content_type":"image/png"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 	mockItemService.AssertExpectations(t)
 }
 
+func TestCreateItemUploadURL_StorageNotConfigured(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items/uploads", handler.CreateItemUploadURL)
+
+	mockItemService.On("CreateUploadURL", mock.Anything, int32(1), "application/pdf").Return(nil, services.ErrUploadsUnavailable)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items/uploads", bytes.NewBufferString(`{"content_type":"application/pdf"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestCreateItemFromUpload(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items/uploads/complete", handler.CreateItemFromUpload)
+
+	userID := int32(1)
+	url := "https://files.example.com/uploads/1/abc.pdf"
+	status := "pending"
+	mockItemService.On("CreateItemFromUpload", mock.Anything, userID, "uploads/1/abc.pdf", (*int32)(nil)).
+		Return(&db.Item{ID: 1, UserID: &userID, Url: &url, ProcessingStatus: &status}, nil)
+	mockItemService.On("CreateItemFromUpload", mock.Anything, userID, "uploads/2/abc.pdf", (*int32)(nil)).
+		Return(nil, fmt.Errorf("%w: not one of your uploads", services.ErrInvalidUpload))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items/uploads/complete", bytes.NewBufferString(`{"key":"uploads/1/abc.pdf"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), url)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/items/uploads/complete", bytes.NewBufferString(`{"key":"uploads/2/abc.pdf"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestCreateItemFromContent(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items/content", handler.CreateItemFromContent)
+
+	userID := int32(1)
+	status := "pending"
+	content := "# Planning Notes\n\nShip the importer first."
+	mockItemService.On("CreateItemFromSource", mock.Anything, userID, "", services.SourceTypeMarkdown, content, (*int32)(nil)).
+		Return(&db.Item{ID: 1, UserID: &userID, SourceType: services.SourceTypeMarkdown, SourceContent: &content, ProcessingStatus: &status}, nil)
+	mockItemService.On("CreateItemFromSource", mock.Anything, userID, "", "rtf", "{}", (*int32)(nil)).
+		Return(nil, fmt.Errorf("%w: unsupported source type", services.ErrInvalidSource))
+
+	body, _ := json.Marshal(CreateItemFromContentRequest{SourceType: services.SourceTypeMarkdown, Content: content})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items/content", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"source_type":"markdown"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/items/content", bytes.NewBufferString(`{"source_type":"rtf","content":"{}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/items/content", bytes.NewBufferString(`{"source_type":"text"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestCreateItemFromFile(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items/files", handler.CreateItemFromFile)
+
+	userID := int32(1)
+	workspaceID := int32(3)
+	status := "pending"
+	data := []byte("<html><body><h1>Chapter 3</h1><p>It was a quiet night.</p></body></html>")
+	mockItemService.On("CreateItemFromFile", mock.Anything, userID, "Chapter 3", "chapter-03.xhtml", "application/xhtml+xml", data, &workspaceID).
+		Return(&db.Item{ID: 1, UserID: &userID, SourceType: services.SourceTypeHTML, ProcessingStatus: &status}, nil)
+
+	newRequest := func(includeFile bool, workspace string) *http.Request {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		_ = form.WriteField("title", "Chapter 3")
+		if workspace != "" {
+			_ = form.WriteField("workspace_id", workspace)
+		}
+		if includeFile {
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition", `form-data; name="file"; filename="chapter-03.xhtml"`)
+			header.Set("Content-Type", "application/xhtml+xml")
+			part, _ := form.CreatePart(header)
+			_, _ = part.Write(data)
+		}
+		_ = form.Close()
+		req, _ := http.NewRequest("POST", "/items/files", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(true, "3"))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(false, ""))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(true, "abc"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockItemService.AssertExpectations(t)
+}
+
+func TestCreateItemFromFile_TooLarge(t *testing.T) {
+	handler := NewHandler(nil, new(MockItemService), nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items/files", handler.CreateItemFromFile)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "huge.txt")
+	_, _ = part.Write(bytes.Repeat([]byte("a"), services.MaxSourceSize+128<<10))
+	_ = form.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items/files", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
 func TestGetItem(t *testing.T) {
 	mockItemService := new(MockItemService)
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
@@ -158,7 +484,8 @@ func TestGetItem(t *testing.T) {
 		Title: "Test Item",
 	}
 
-	mockItemService.On("GetItem", mock.Anything, int32(1)).Return(expectedItem, nil)
+	mockItemService.On("GetItem", mock.Anything, testUserID, int32(1)).Return(expectedItem, nil)
+	mockItemService.On("GetReadState", mock.Anything, testUserID, mock.Anything).Return(map[int32]bool{}, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/items/1", nil)
@@ -174,31 +501,118 @@ func TestGetItemsByUser(t *testing.T) {
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.GET("/items/user/:userID", handler.GetItemsByUser)
+	router.GET("/items", handler.GetItemsByUser)
 
-	userID := int32(1)
-	expectedItems := []db.Item{
-		{ID: 1, Title: "Item 1"},
-		{ID: 2, Title: "Item 2"},
+	page := &services.ItemPage{
+		Items: []db.Item{
+			{ID: 1, Title: "Item 1"},
+			{ID: 2, Title: "Item 2"},
+		},
+		ReadState:  map[int32]bool{2: true},
+		NextCursor: "next-page",
 	}
 
-	mockItemService.On("GetItemsByUser", mock.Anything, &userID).Return(expectedItems, nil)
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{}).Return(page, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ItemListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Items, 2)
+	assert.Equal(t, 2, response.Count)
+	assert.False(t, response.Items[0].IsRead)
+	assert.True(t, response.Items[1].IsRead)
+	require.NotNil(t, response.NextCursor)
+	assert.Equal(t, "next-page", *response.NextCursor)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestGetItemsByUser_FiltersAndPage(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items", handler.GetItemsByUser)
+
+	platform := "youtube"
+	itemType := "video"
+	status := "completed"
+	isRead := false
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{
+		Tags:          []string{"go", "databases"},
+		Platform:      &platform,
+		Type:          &itemType,
+		IsRead:        &isRead,
+		Status:        &status,
+		CreatedAfter:  &from,
+		CreatedBefore: &to,
+		Page: services.PageQuery{
+			Sort:   "title",
+			Order:  "desc",
+			Limit:  10,
+			Cursor: "abc",
+		},
+	}).Return(&services.ItemPage{Items: []db.Item{}, ReadState: map[int32]bool{}}, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/items/user/1", nil)
+	req, _ := http.NewRequest("GET", "/items?tag=go&tag=databases&platform=youtube&type=video&is_read=false&status=completed&from=2024-01-01&to=2024-01-31&sort=title&order=desc&limit=10&cursor=abc", nil)
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next_cursor":null`)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestGetItemsByUser_InvalidListQuery(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items", handler.GetItemsByUser)
+
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{
+		Page: services.PageQuery{Sort: "rating"},
+	}).Return(nil, fmt.Errorf("%w: sort must be \"created_at\" or \"title\"", services.ErrInvalidListQuery))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items?sort=rating", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 	mockItemService.AssertExpectations(t)
 }
 
+func TestGetItemsByUser_InvalidIsRead(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items", handler.GetItemsByUser)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items?is_read=maybe", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockItemService.AssertNotCalled(t, "ListItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestGetUnreadItemsByUser(t *testing.T) {
 	mockItemService := new(MockItemService)
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.GET("/items/user/:userID/unread", handler.GetUnreadItemsByUser)
+	router.GET("/items/unread", handler.GetUnreadItemsByUser)
 
 	userID := int32(1)
 	expectedItems := []db.Item{
@@ -208,7 +622,7 @@ func TestGetUnreadItemsByUser(t *testing.T) {
 	mockItemService.On("GetUnreadItemsByUser", mock.Anything, &userID).Return(expectedItems, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/items/user/1/unread", nil)
+	req, _ := http.NewRequest("GET", "/items/unread", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -223,7 +637,7 @@ func TestUpdateItem(t *testing.T) {
 	router := setupTestRouter()
 	router.PUT("/items/:id", handler.UpdateItem)
 
-	mockItemService.On("UpdateItem", mock.Anything, int32(1), "Updated Title", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockItemService.On("UpdateItem", mock.Anything, testUserID, int32(1), "Updated Title", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	reqBody := map[string]interface{}{
 		"title": "Updated Title",
@@ -260,7 +674,8 @@ func TestPatchItem(t *testing.T) {
 		Authors: newAuthors,
 	}
 
-	mockItemService.On("PatchItem", mock.Anything, int32(1), &newTitle, &newSummary, newTags, newAuthors).Return(expectedItem, nil)
+	mockItemService.On("PatchItem", mock.Anything, testUserID, int32(1), &newTitle, &newSummary, newTags, newAuthors).Return(expectedItem, nil)
+	mockItemService.On("GetReadState", mock.Anything, testUserID, mock.Anything).Return(map[int32]bool{}, nil)
 
 	reqBody := map[string]interface{}{
 		"title":   newTitle,
@@ -299,7 +714,8 @@ func TestPatchItem_PartialUpdate(t *testing.T) {
 		Title: newTitle,
 	}
 
-	mockItemService.On("PatchItem", mock.Anything, int32(1), &newTitle, (*string)(nil), []string(nil), []string(nil)).Return(expectedItem, nil)
+	mockItemService.On("PatchItem", mock.Anything, testUserID, int32(1), &newTitle, (*string)(nil), []string(nil), []string(nil)).Return(expectedItem, nil)
+	mockItemService.On("GetReadState", mock.Anything, testUserID, mock.Anything).Return(map[int32]bool{}, nil)
 
 	reqBody := map[string]interface{}{
 		"title": newTitle,
@@ -361,7 +777,7 @@ func TestPatchItem_ServiceError(t *testing.T) {
 	router.PATCH("/items/:id", handler.PatchItem)
 
 	newTitle := "Updated Title"
-	mockItemService.On("PatchItem", mock.Anything, int32(1), &newTitle, (*string)(nil), []string(nil), []string(nil)).Return(nil, errors.New("service error"))
+	mockItemService.On("PatchItem", mock.Anything, testUserID, int32(1), &newTitle, (*string)(nil), []string(nil), []string(nil)).Return(nil, errors.New("service error"))
 
 	reqBody := map[string]interface{}{
 		"title": newTitle,
@@ -385,14 +801,12 @@ func TestMarkItemAsRead(t *testing.T) {
 	router := setupTestRouter()
 	router.PATCH("/items/:id/read", handler.MarkItemAsRead)
 
-	isRead := true
 	expectedItem := &db.Item{
-		ID:     1,
-		IsRead: &isRead,
+		ID: 1,
 	}
 
-	mockItemService.On("MarkItemAsRead", mock.Anything, int32(1)).Return(nil)
-	mockItemService.On("GetItem", mock.Anything, int32(1)).Return(expectedItem, nil)
+	mockItemService.On("MarkItemAsRead", mock.Anything, testUserID, int32(1)).Return(nil)
+	mockItemService.On("GetItem", mock.Anything, testUserID, int32(1)).Return(expectedItem, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PATCH", "/items/1/read", nil)
@@ -410,13 +824,11 @@ func TestToggleItemReadStatus(t *testing.T) {
 	router := setupTestRouter()
 	router.PATCH("/items/:id/toggle-read", handler.ToggleItemReadStatus)
 
-	isRead := true
 	expectedItem := &db.Item{
-		ID:     1,
-		IsRead: &isRead,
+		ID: 1,
 	}
 
-	mockItemService.On("ToggleItemReadStatus", mock.Anything, int32(1)).Return(expectedItem, nil)
+	mockItemService.On("ToggleItemReadStatus", mock.Anything, testUserID, int32(1)).Return(expectedItem, true, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PATCH", "/items/1/toggle-read", nil)
@@ -424,6 +836,9 @@ func TestToggleItemReadStatus(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	var response ItemResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.IsRead)
 	mockItemService.AssertExpectations(t)
 }
 
@@ -434,7 +849,7 @@ func TestDeleteItem(t *testing.T) {
 	router := setupTestRouter()
 	router.DELETE("/items/:id", handler.DeleteItem)
 
-	mockItemService.On("DeleteItem", mock.Anything, int32(1)).Return(nil)
+	mockItemService.On("DeleteItem", mock.Anything, testUserID, int32(1)).Return(nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/items/1", nil)
@@ -461,7 +876,7 @@ func TestGetItemProcessingStatus(t *testing.T) {
 		IsCompleted: true,
 	}
 
-	mockItemService.On("GetItemProcessingStatus", mock.Anything, int32(1)).Return(expectedStatus, nil)
+	mockItemService.On("GetItemProcessingStatus", mock.Anything, testUserID, int32(1)).Return(expectedStatus, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/items/1/status", nil)
@@ -480,18 +895,30 @@ func TestGetItemsByProcessingStatus(t *testing.T) {
 	router.GET("/items/status", handler.GetItemsByProcessingStatus)
 
 	status := "pending"
-	expectedItems := []db.Item{
-		{ID: 1, Title: "Pending Item"},
+	page := &services.ItemPage{
+		Items:      []db.Item{{ID: 1, Title: "Pending Item"}},
+		ReadState:  map[int32]bool{},
+		NextCursor: "next-page",
 	}
 
-	mockItemService.On("GetItemsByProcessingStatus", mock.Anything, &status).Return(expectedItems, nil)
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{
+		Status: &status,
+		Page:   services.PageQuery{Limit: 1},
+	}).Return(page, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/items/status?status=pending", nil)
+	req, _ := http.NewRequest("GET", "/items/status?status=pending&limit=1", nil)
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ItemsByStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "pending", response.Status)
+	assert.Equal(t, 1, response.Count)
+	require.NotNil(t, response.NextCursor)
+	assert.Equal(t, "next-page", *response.NextCursor)
 	mockItemService.AssertExpectations(t)
 }
 
@@ -517,11 +944,10 @@ func TestCreateItem_Error(t *testing.T) {
 	router := setupTestRouter()
 	router.POST("/items", handler.CreateItem)
 
-	mockItemService.On("CreateItemAsync", mock.Anything, int32(1), "https://example.com").Return(nil, errors.New("service error"))
+	mockItemService.On("CreateItemAsync", mock.Anything, int32(1), "https://example.com", (*int32)(nil)).Return(nil, false, errors.New("service error"))
 
 	reqBody := map[string]interface{}{
-		"user_id": 1,
-		"url":     "https://example.com",
+		"url": "https://example.com",
 	}
 	jsonBody, _ := json.Marshal(reqBody)
 
@@ -535,6 +961,30 @@ func TestCreateItem_Error(t *testing.T) {
 	mockItemService.AssertExpectations(t)
 }
 
+func TestCreateItem_QuotaExceeded(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.POST("/items", handler.CreateItem)
+
+	mockItemService.On("CreateItemAsync", mock.Anything, int32(1), "https://example.com", (*int32)(nil)).
+		Return(nil, false, &services.QuotaExceededError{Quota: services.QuotaItems, Limit: 50, RetryAfter: 90 * time.Minute})
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"url": "https://example.com"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "5400", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "daily items quota of 50 exceeded")
+	mockItemService.AssertExpectations(t)
+}
+
 func TestCreateItem_InvalidJSON(t *testing.T) {
 	mockItemService := new(MockItemService)
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
@@ -551,6 +1001,28 @@ func TestCreateItem_InvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestCreateItem_Unauthenticated(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupAnonymousRouter()
+	router.POST("/items", handler.CreateItem)
+
+	reqBody := map[string]interface{}{
+		"url": "https://example.com",
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockItemService.AssertNotCalled(t, "CreateItemAsync")
+}
+
 func TestGetItem_InvalidID(t *testing.T) {
 	mockItemService := new(MockItemService)
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
@@ -573,7 +1045,7 @@ func TestGetItem_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/items/:id", handler.GetItem)
 
-	mockItemService.On("GetItem", mock.Anything, int32(1)).Return(nil, errors.New("service error"))
+	mockItemService.On("GetItem", mock.Anything, testUserID, int32(1)).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/items/1", nil)
@@ -584,19 +1056,19 @@ func TestGetItem_ServiceError(t *testing.T) {
 	mockItemService.AssertExpectations(t)
 }
 
-func TestGetItemsByUser_InvalidID(t *testing.T) {
+func TestGetItemsByUser_Unauthenticated(t *testing.T) {
 	mockItemService := new(MockItemService)
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
 
-	router := setupTestRouter()
-	router.GET("/items/user/:userID", handler.GetItemsByUser)
+	router := setupAnonymousRouter()
+	router.GET("/items", handler.GetItemsByUser)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/items/user/invalid", nil)
+	req, _ := http.NewRequest("GET", "/items", nil)
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestGetItemsByUser_ServiceError(t *testing.T) {
@@ -604,13 +1076,12 @@ func TestGetItemsByUser_ServiceError(t *testing.T) {
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.GET("/items/user/:userID", handler.GetItemsByUser)
+	router.GET("/items", handler.GetItemsByUser)
 
-	userID := int32(1)
-	mockItemService.On("GetItemsByUser", mock.Anything, &userID).Return([]db.Item{}, errors.New("service error"))
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{}).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/items/user/1", nil)
+	req, _ := http.NewRequest("GET", "/items", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -618,19 +1089,19 @@ func TestGetItemsByUser_ServiceError(t *testing.T) {
 	mockItemService.AssertExpectations(t)
 }
 
-func TestGetUnreadItemsByUser_InvalidID(t *testing.T) {
+func TestGetUnreadItemsByUser_Unauthenticated(t *testing.T) {
 	mockItemService := new(MockItemService)
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
 
-	router := setupTestRouter()
-	router.GET("/items/user/:userID/unread", handler.GetUnreadItemsByUser)
+	router := setupAnonymousRouter()
+	router.GET("/items/unread", handler.GetUnreadItemsByUser)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/items/user/invalid/unread", nil)
+	req, _ := http.NewRequest("GET", "/items/unread", nil)
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestGetUnreadItemsByUser_ServiceError(t *testing.T) {
@@ -638,13 +1109,13 @@ func TestGetUnreadItemsByUser_ServiceError(t *testing.T) {
 	handler := NewHandler(nil, mockItemService, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.GET("/items/user/:userID/unread", handler.GetUnreadItemsByUser)
+	router.GET("/items/unread", handler.GetUnreadItemsByUser)
 
 	userID := int32(1)
 	mockItemService.On("GetUnreadItemsByUser", mock.Anything, &userID).Return([]db.Item{}, errors.New("service error"))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/items/user/1/unread", nil)
+	req, _ := http.NewRequest("GET", "/items/unread", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -680,7 +1151,7 @@ func TestUpdateItem_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.PUT("/items/:id", handler.UpdateItem)
 
-	mockItemService.On("UpdateItem", mock.Anything, int32(1), "Updated Title", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("service error"))
+	mockItemService.On("UpdateItem", mock.Anything, testUserID, int32(1), "Updated Title", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("service error"))
 
 	reqBody := map[string]interface{}{
 		"title": "Updated Title",
@@ -719,7 +1190,7 @@ func TestMarkItemAsRead_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.PATCH("/items/:id/read", handler.MarkItemAsRead)
 
-	mockItemService.On("MarkItemAsRead", mock.Anything, int32(1)).Return(errors.New("service error"))
+	mockItemService.On("MarkItemAsRead", mock.Anything, testUserID, int32(1)).Return(errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PATCH", "/items/1/read", nil)
@@ -737,8 +1208,8 @@ func TestMarkItemAsRead_GetItemError(t *testing.T) {
 	router := setupTestRouter()
 	router.PATCH("/items/:id/read", handler.MarkItemAsRead)
 
-	mockItemService.On("MarkItemAsRead", mock.Anything, int32(1)).Return(nil)
-	mockItemService.On("GetItem", mock.Anything, int32(1)).Return(nil, errors.New("service error"))
+	mockItemService.On("MarkItemAsRead", mock.Anything, testUserID, int32(1)).Return(nil)
+	mockItemService.On("GetItem", mock.Anything, testUserID, int32(1)).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PATCH", "/items/1/read", nil)
@@ -771,7 +1242,7 @@ func TestToggleItemReadStatus_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.PATCH("/items/:id/toggle-read", handler.ToggleItemReadStatus)
 
-	mockItemService.On("ToggleItemReadStatus", mock.Anything, int32(1)).Return(nil, errors.New("service error"))
+	mockItemService.On("ToggleItemReadStatus", mock.Anything, testUserID, int32(1)).Return(nil, false, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PATCH", "/items/1/toggle-read", nil)
@@ -804,7 +1275,7 @@ func TestDeleteItem_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.DELETE("/items/:id", handler.DeleteItem)
 
-	mockItemService.On("DeleteItem", mock.Anything, int32(1)).Return(errors.New("service error"))
+	mockItemService.On("DeleteItem", mock.Anything, testUserID, int32(1)).Return(errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/items/1", nil)
@@ -837,7 +1308,7 @@ func TestGetItemProcessingStatus_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/items/:id/status", handler.GetItemProcessingStatus)
 
-	mockItemService.On("GetItemProcessingStatus", mock.Anything, int32(1)).Return(nil, errors.New("service error"))
+	mockItemService.On("GetItemProcessingStatus", mock.Anything, testUserID, int32(1)).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/items/1/status", nil)
@@ -856,7 +1327,7 @@ func TestGetItemsByProcessingStatus_ServiceError(t *testing.T) {
 	router.GET("/items/status", handler.GetItemsByProcessingStatus)
 
 	status := "pending"
-	mockItemService.On("GetItemsByProcessingStatus", mock.Anything, &status).Return([]db.Item{}, errors.New("service error"))
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{Status: &status}).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/items/status?status=pending", nil)
@@ -866,3 +1337,461 @@ func TestGetItemsByProcessingStatus_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockItemService.AssertExpectations(t)
 }
+
+func TestItemRoutes_OtherUsersItemNotFound(t *testing.T) {
+	// Item 2 belongs to another user, so every scoped lookup reports it missing
+	otherItemID := int32(2)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		setup  func(m *MockItemService)
+	}{
+		{
+			name:   "GET /items/:id",
+			method: http.MethodGet,
+			path:   "/items/2",
+			setup: func(m *MockItemService) {
+				m.On("GetItem", mock.Anything, testUserID, otherItemID).Return(nil, services.ErrItemNotFound)
+			},
+		},
+		{
+			name:   "GET /items/:id/status",
+			method: http.MethodGet,
+			path:   "/items/2/status",
+			setup: func(m *MockItemService) {
+				m.On("GetItemProcessingStatus", mock.Anything, testUserID, otherItemID).Return(nil, services.ErrItemNotFound)
+			},
+		},
+		{
+			name:   "PUT /items/:id",
+			method: http.MethodPut,
+			path:   "/items/2",
+			body:   `{"title":"Stolen"}`,
+			setup: func(m *MockItemService) {
+				m.On("UpdateItem", mock.Anything, testUserID, otherItemID, "Stolen", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(services.ErrItemNotFound)
+			},
+		},
+		{
+			name:   "PATCH /items/:id",
+			method: http.MethodPatch,
+			path:   "/items/2",
+			body:   `{"title":"Stolen"}`,
+			setup: func(m *MockItemService) {
+				m.On("PatchItem", mock.Anything, testUserID, otherItemID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrItemNotFound)
+			},
+		},
+		{
+			name:   "PATCH /items/:id/read",
+			method: http.MethodPatch,
+			path:   "/items/2/read",
+			setup: func(m *MockItemService) {
+				m.On("MarkItemAsRead", mock.Anything, testUserID, otherItemID).Return(services.ErrItemNotFound)
+			},
+		},
+		{
+			name:   "PATCH /items/:id/toggle-read",
+			method: http.MethodPatch,
+			path:   "/items/2/toggle-read",
+			setup: func(m *MockItemService) {
+				m.On("ToggleItemReadStatus", mock.Anything, testUserID, otherItemID).Return(nil, false, services.ErrItemNotFound)
+			},
+		},
+		{
+			name:   "PUT /items/:id/workspace",
+			method: http.MethodPut,
+			path:   "/items/2/workspace",
+			body:   `{"workspace_id":null}`,
+			setup: func(m *MockItemService) {
+				m.On("MoveItemToWorkspace", mock.Anything, testUserID, otherItemID, (*int32)(nil)).Return(nil, services.ErrItemNotFound)
+			},
+		},
+		{
+			name:   "DELETE /items/:id",
+			method: http.MethodDelete,
+			path:   "/items/2",
+			setup: func(m *MockItemService) {
+				m.On("DeleteItem", mock.Anything, testUserID, otherItemID).Return(services.ErrItemNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockItemService := new(MockItemService)
+			handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+			router := setupTestRouter()
+			router.GET("/items/:id", handler.GetItem)
+			router.GET("/items/:id/status", handler.GetItemProcessingStatus)
+			router.PUT("/items/:id", handler.UpdateItem)
+			router.PATCH("/items/:id", handler.PatchItem)
+			router.PATCH("/items/:id/read", handler.MarkItemAsRead)
+			router.PATCH("/items/:id/toggle-read", handler.ToggleItemReadStatus)
+			router.PUT("/items/:id/workspace", handler.MoveItemToWorkspace)
+			router.DELETE("/items/:id", handler.DeleteItem)
+
+			tt.setup(mockItemService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), "item not found")
+			mockItemService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetItemsByProcessingStatus_ScopedToUser(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items/status", handler.GetItemsByProcessingStatus)
+
+	status := "failed"
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{Status: &status}).Return(&services.ItemPage{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items/status?status=failed", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestGetItemsByUser_Workspace(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items", handler.GetItemsByUser)
+
+	workspaceID := int32(3)
+	page := &services.ItemPage{
+		Items:     []db.Item{{ID: 4, Title: "Shared Item"}},
+		ReadState: map[int32]bool{4: true},
+	}
+
+	mockItemService.On("ListItems", mock.Anything, testUserID, services.ItemListQuery{WorkspaceID: &workspaceID}).Return(page, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items?workspace_id=3", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ItemListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Items, 1)
+	assert.True(t, response.Items[0].IsRead)
+	assert.Nil(t, response.NextCursor)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestGetItemsByUser_InvalidWorkspaceID(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items", handler.GetItemsByUser)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items?workspace_id=abc", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetUnreadItemsByUser_WorkspaceForbidden(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items/unread", handler.GetUnreadItemsByUser)
+
+	mockItemService.On("GetUnreadItemsByWorkspace", mock.Anything, testUserID, int32(3)).Return(nil, services.ErrWorkspaceNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items/unread?workspace_id=3", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestUpdateItem_SetsReadState(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.PUT("/items/:id", handler.UpdateItem)
+
+	mockItemService.On("UpdateItem", mock.Anything, testUserID, int32(1), "Updated Title", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockItemService.On("SetItemReadStatus", mock.Anything, testUserID, int32(1), true).Return(nil)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{
+		"title":   "Updated Title",
+		"is_read": true,
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/items/1", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestMoveItemToWorkspace(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.PUT("/items/:id/workspace", handler.MoveItemToWorkspace)
+
+	workspaceID := int32(3)
+	mockItemService.On("MoveItemToWorkspace", mock.Anything, testUserID, int32(1), &workspaceID).Return(&db.Item{ID: 1, WorkspaceID: &workspaceID}, nil)
+	mockItemService.On("GetReadState", mock.Anything, testUserID, []int32{1}).Return(map[int32]bool{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/items/1/workspace", bytes.NewBufferString(`{"workspace_id":3}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockItemService.AssertExpectations(t)
+}
+
+func TestSearchItems(t *testing.T) {
+	mockItemService := new(MockItemService)
+	handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items/search", handler.SearchItems)
+
+	isRead, itemType := true, "article"
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	mockItemService.On("SearchItems", mock.Anything, testUserID, services.ItemSearch{
+		Query:         "postgres",
+		Tags:          []string{"databases", "go"},
+		Type:          &itemType,
+		IsRead:        &isRead,
+		CreatedAfter:  &from,
+		CreatedBefore: &to,
+		Limit:         5,
+	}).Return([]services.SearchResult{{
+		Item:           db.Item{ID: 4, Title: "Tuning Postgres"},
+		IsRead:         true,
+		Rank:           0.5,
+		TitleHighlight: "Tuning <mark>Postgres</mark>",
+		Snippet:        "<mark>Postgres</mark> tips",
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items/search?q=postgres&tag=databases&tag=go&type=article&is_read=true&from=2024-03-01&to=2024-03-31&limit=5", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response SearchItemsResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, int32(4), response.Results[0].ID)
+	assert.True(t, response.Results[0].IsRead)
+	assert.Equal(t, "<mark>Postgres</mark> tips", response.Results[0].Snippet)
+	assert.NotContains(t, w.Body.String(), "search_vector")
+	mockItemService.AssertExpectations(t)
+}
+
+func TestSearchItems_BadRequests(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		setup func(m *MockItemService)
+	}{
+		{name: "invalid is_read", query: "q=go&is_read=maybe", setup: func(m *MockItemService) {}},
+		{name: "invalid date", query: "q=go&from=March", setup: func(m *MockItemService) {}},
+		{name: "invalid limit", query: "q=go&limit=-1", setup: func(m *MockItemService) {}},
+		{
+			name:  "missing query",
+			query: "tag=go",
+			setup: func(m *MockItemService) {
+				m.On("SearchItems", mock.Anything, testUserID, mock.Anything).
+					Return(nil, fmt.Errorf("%w: a query is required", services.ErrInvalidSearch))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockItemService := new(MockItemService)
+			tt.setup(mockItemService)
+			handler := NewHandler(nil, mockItemService, nil, nil, nil)
+
+			router := setupTestRouter()
+			router.GET("/items/search", handler.SearchItems)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/items/search?"+tt.query, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockItemService.AssertExpectations(t)
+		})
+	}
+}
+
+// MockSemanticService is a mock implementation of services.SemanticService
+type MockSemanticService struct {
+	mock.Mock
+}
+
+func (m *MockSemanticService) EmbedPendingItems(ctx context.Context, limit int32) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSemanticService) SearchItems(ctx context.Context, userID int32, query string, limit int) ([]services.SemanticResult, error) {
+	args := m.Called(ctx, userID, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.SemanticResult), args.Error(1)
+}
+
+func (m *MockSemanticService) RelatedItems(ctx context.Context, userID int32, itemID int32, limit int) ([]services.SemanticResult, error) {
+	args := m.Called(ctx, userID, itemID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.SemanticResult), args.Error(1)
+}
+
+func TestSemanticSearchItems(t *testing.T) {
+	mockSemanticService := new(MockSemanticService)
+	handler := NewHandler(nil, nil, nil, nil, nil)
+	handler.SetSemanticService(mockSemanticService)
+
+	router := setupTestRouter()
+	router.GET("/items/semantic-search", handler.SemanticSearchItems)
+
+	mockSemanticService.On("SearchItems", mock.Anything, testUserID, "making databases faster", 5).Return([]services.SemanticResult{{
+		Item:   db.Item{ID: 4, Title: "Tuning Postgres"},
+		IsRead: true,
+		Score:  0.83,
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items/semantic-search?q=making+databases+faster&limit=5", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response SemanticSearchResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, int32(4), response.Results[0].ID)
+	assert.True(t, response.Results[0].IsRead)
+	assert.InDelta(t, 0.83, response.Results[0].Score, 1e-6)
+	mockSemanticService.AssertExpectations(t)
+}
+
+func TestSemanticSearchItems_NotConfigured(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.GET("/items/semantic-search", handler.SemanticSearchItems)
+	router.GET("/items/:id/related", handler.GetRelatedItems)
+
+	for _, path := range []string{"/items/semantic-search?q=go", "/items/1/related"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+	}
+}
+
+func TestGetRelatedItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setup          func(m *MockSemanticService)
+		expectedStatus int
+	}{
+		{
+			name: "related items",
+			path: "/items/1/related",
+			setup: func(m *MockSemanticService) {
+				m.On("RelatedItems", mock.Anything, testUserID, int32(1), 0).
+					Return([]services.SemanticResult{{Item: db.Item{ID: 2}, Score: 0.7}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not embedded yet",
+			path: "/items/1/related",
+			setup: func(m *MockSemanticService) {
+				m.On("RelatedItems", mock.Anything, testUserID, int32(1), 0).Return(nil, services.ErrEmbeddingNotReady)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "not visible",
+			path: "/items/1/related",
+			setup: func(m *MockSemanticService) {
+				m.On("RelatedItems", mock.Anything, testUserID, int32(1), 0).Return(nil, services.ErrItemNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{name: "invalid ID", path: "/items/abc/related", setup: func(m *MockSemanticService) {}, expectedStatus: http.StatusBadRequest},
+		{name: "invalid limit", path: "/items/1/related?limit=many", setup: func(m *MockSemanticService) {}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSemanticService := new(MockSemanticService)
+			tt.setup(mockSemanticService)
+			handler := NewHandler(nil, nil, nil, nil, nil)
+			handler.SetSemanticService(mockSemanticService)
+
+			router := setupTestRouter()
+			router.GET("/items/:id/related", handler.GetRelatedItems)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response RelatedItemsResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, int32(1), response.ItemID)
+				assert.Equal(t, int32(2), response.Results[0].ID)
+			}
+			mockSemanticService.AssertExpectations(t)
+		})
+	}
+}
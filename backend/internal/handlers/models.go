@@ -1,40 +1,298 @@
 package handlers
 
-import "github.com/yamirghofran/briefbot/internal/db"
+import (
+	"time"
+
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// Auth request/response models
+
+// RegisterRequest represents the request body for registering a new account
+type RegisterRequest struct {
+	Name     string `json:"name" example:"John Doe"`
+	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
+	Password string `json:"password" binding:"required,min=8" example:"correct-horse-battery"`
+}
+
+// LoginRequest represents the request body for logging in
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required" example:"john@example.com"`
+	Password string `json:"password" binding:"required" example:"correct-horse-battery"`
+}
+
+// AuthResponse represents a newly issued session token
+type AuthResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type" example:"Bearer"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UserID    int32     `json:"user_id" example:"1"`
+}
+
+// CreateAPITokenRequest represents the request body for creating a personal API token
+type CreateAPITokenRequest struct {
+	Name          string `json:"name" binding:"required" example:"Browser extension"`
+	ExpiresInDays *int   `json:"expires_in_days" binding:"omitempty,min=1" example:"90"`
+}
+
+// APITokenResponse represents a personal API token without its secret
+type APITokenResponse struct {
+	ID          int32      `json:"id" example:"1"`
+	Name        string     `json:"name" example:"Browser extension"`
+	TokenPrefix string     `json:"token_prefix" example:"bb_a1b2c3"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+// CreateAPITokenResponse contains the plaintext token, which is only returned once
+type CreateAPITokenResponse struct {
+	Token    string           `json:"token"`
+	APIToken APITokenResponse `json:"api_token"`
+}
+
+// newAPITokenResponse converts a stored API token into its public representation
+func newAPITokenResponse(token db.ApiToken) APITokenResponse {
+	return APITokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		LastUsedAt:  token.LastUsedAt,
+		ExpiresAt:   token.ExpiresAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
 
 // User request/response models
 
-// CreateUserRequest represents the request body for creating a user
-type CreateUserRequest struct {
-	Name         *string `json:"name" example:"John Doe"`
-	Email        *string `json:"email" example:"john@example.com"`
-	AuthProvider *string `json:"auth_provider" example:"google"`
-	OauthID      *string `json:"oauth_id" example:"google_123456"`
-	PasswordHash *string `json:"password_hash"`
+// UserResponse is the public representation of a user. Credentials and
+// identity provider subjects are never included.
+type UserResponse struct {
+	ID            int32      `json:"id" example:"1"`
+	Name          *string    `json:"name" example:"John Doe"`
+	Email         *string    `json:"email" example:"john@example.com"`
+	AuthProvider  *string    `json:"auth_provider" example:"password"`
+	HasPassword   bool       `json:"has_password" example:"true"`
+	EmailVerified bool       `json:"email_verified" example:"true"`
+	CreatedAt     *time.Time `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
 }
 
-// UpdateUserRequest represents the request body for updating a user
+// newUserResponse converts a database user into its public response
+func newUserResponse(user db.User) UserResponse {
+	return UserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		AuthProvider:  user.AuthProvider,
+		HasPassword:   user.PasswordHash != nil,
+		EmailVerified: user.EmailVerified,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// UpdateUserRequest represents the request body for updating a user. Changing
+// the email marks it unverified.
 type UpdateUserRequest struct {
-	Name         *string `json:"name" example:"Jane Doe"`
-	Email        *string `json:"email" example:"jane@example.com"`
-	AuthProvider *string `json:"auth_provider"`
-	OauthID      *string `json:"oauth_id"`
-	PasswordHash *string `json:"password_hash"`
+	Name  *string `json:"name" example:"Jane Doe"`
+	Email *string `json:"email" example:"jane@example.com"`
+}
+
+// ChangePasswordRequest represents the request body for setting or changing a password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"old-password"`
+	NewPassword     string `json:"new_password" binding:"required" example:"correct-horse-battery"`
+}
+
+// ForgotPasswordRequest represents the request body for requesting a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+// ResetPasswordRequest represents the request body for completing a password reset
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required" example:"correct-horse-battery"`
+}
+
+// Preferences request/response models
+
+// PreferencesResponse represents the current user's settings. A null
+// podcast_enabled means the server-wide default applies.
+type PreferencesResponse struct {
+	Timezone         string     `json:"timezone" example:"Europe/Madrid"`
+	DigestTime       string     `json:"digest_time" example:"08:00"`
+	DigestFrequency  string     `json:"digest_frequency" example:"daily" enums:"daily,weekdays,weekly,off"`
+	PodcastEnabled   *bool      `json:"podcast_enabled" example:"true"`
+	HostVoice        string     `json:"host_voice" example:"af_heart"`
+	CohostVoice      string     `json:"cohost_voice" example:"am_adam"`
+	SpeechSpeed      float64    `json:"speech_speed" example:"1.0"`
+	SummaryLength    string     `json:"summary_length" example:"medium" enums:"short,medium,long"`
+	SummaryLanguage  string     `json:"summary_language" example:"en"`
+	LastDigestSentAt *time.Time `json:"last_digest_sent_at"`
+}
+
+// newPreferencesResponse converts stored preferences into their public response
+func newPreferencesResponse(prefs db.UserPreference) PreferencesResponse {
+	return PreferencesResponse{
+		Timezone:         prefs.Timezone,
+		DigestTime:       prefs.DigestTime,
+		DigestFrequency:  prefs.DigestFrequency,
+		PodcastEnabled:   prefs.PodcastEnabled,
+		HostVoice:        prefs.HostVoice,
+		CohostVoice:      prefs.CohostVoice,
+		SpeechSpeed:      prefs.SpeechSpeed,
+		SummaryLength:    prefs.SummaryLength,
+		SummaryLanguage:  prefs.SummaryLanguage,
+		LastDigestSentAt: prefs.LastDigestSentAt,
+	}
+}
+
+// UpdatePreferencesRequest represents a partial update of the current user's settings
+type UpdatePreferencesRequest struct {
+	Timezone        *string  `json:"timezone" example:"Europe/Madrid"`
+	DigestTime      *string  `json:"digest_time" example:"07:30"`
+	DigestFrequency *string  `json:"digest_frequency" example:"weekdays"`
+	PodcastEnabled  *bool    `json:"podcast_enabled" example:"false"`
+	HostVoice       *string  `json:"host_voice" example:"af_nova"`
+	CohostVoice     *string  `json:"cohost_voice" example:"am_liam"`
+	SpeechSpeed     *float64 `json:"speech_speed" example:"1.2"`
+	SummaryLength   *string  `json:"summary_length" example:"short"`
+	SummaryLanguage *string  `json:"summary_language" example:"es"`
 }
 
 // Item request/response models
 
 // CreateItemRequest represents the request body for creating an item
 type CreateItemRequest struct {
-	UserID *int32  `json:"user_id" binding:"required" example:"1"`
-	URL    *string `json:"url" binding:"required" example:"https://example.com/article"`
+	URL         *string `json:"url" binding:"required" example:"https://example.com/article"`
+	WorkspaceID *int32  `json:"workspace_id" example:"1"`
+}
+
+// CreateUploadURLRequest represents the request body for uploading a file as an item
+type CreateUploadURLRequest struct {
+	ContentType string `json:"content_type" binding:"required" example:"application/pdf"`
+}
+
+// UploadURLResponse is a presigned URL to PUT the file to, valid for 15 minutes
+type UploadURLResponse struct {
+	UploadURL string `json:"upload_url"`
+	Key       string `json:"key" example:"uploads/1/0b8e9f0c-5d4a-4a53-9a55-2c3e4f1d2b7a.pdf"`
+	PublicURL string `json:"public_url"`
+}
+
+// CreateItemFromUploadRequest represents the request body for creating an item from an uploaded file
+type CreateItemFromUploadRequest struct {
+	Key         string `json:"key" binding:"required" example:"uploads/1/0b8e9f0c-5d4a-4a53-9a55-2c3e4f1d2b7a.pdf"`
+	WorkspaceID *int32 `json:"workspace_id" example:"1"`
+}
+
+// CreateItemFromContentRequest represents the request body for creating an item from pasted content
+type CreateItemFromContentRequest struct {
+	Title       string `json:"title" example:"Weekly Newsletter"` // Optional; extracted from the content when empty
+	SourceType  string `json:"source_type" binding:"required" enums:"text,html,markdown" example:"markdown"`
+	Content     string `json:"content" binding:"required" example:"# Weekly Newsletter\n\nThis week in infrastructure..."`
+	WorkspaceID *int32 `json:"workspace_id" example:"1"`
 }
 
 // CreateItemResponse represents the response after creating an item
 type CreateItemResponse struct {
-	Item             db.Item `json:"item"`
-	Message          string  `json:"message"`
-	ProcessingStatus string  `json:"processing_status"`
+	Item             ItemResponse `json:"item"`
+	Message          string       `json:"message"`
+	ProcessingStatus string       `json:"processing_status"`
+	// Duplicate is set when the user already saved the page and the existing item is returned
+	Duplicate bool `json:"duplicate" example:"false"`
+}
+
+// ItemResponse is an item together with the current user's read state.
+// Read state is per user, so members of a workspace see their own.
+type ItemResponse struct {
+	db.Item
+	IsRead bool `json:"is_read" example:"false"`
+}
+
+// newItemResponse attaches the current user's read state to an item
+func newItemResponse(item db.Item, isRead bool) ItemResponse {
+	return ItemResponse{Item: item, IsRead: isRead}
+}
+
+// newItemResponses attaches read state to a list of items; items missing from readState are unread
+func newItemResponses(items []db.Item, readState map[int32]bool) []ItemResponse {
+	responses := make([]ItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = newItemResponse(item, readState[item.ID])
+	}
+	return responses
+}
+
+// SearchResultResponse represents an item matching a search
+type SearchResultResponse struct {
+	ItemResponse
+	Rank float32 `json:"rank" example:"0.42"`
+	// TitleHighlight is the title as HTML with matches wrapped in <mark>
+	TitleHighlight string `json:"title_highlight" example:"Scaling <mark>Postgres</mark>"`
+	// Snippet is HTML with excerpts of the summary and text around matches
+	Snippet string `json:"snippet" example:"… how we tuned <mark>Postgres</mark> for …"`
+}
+
+// SearchItemsResponse represents the results of an item search
+type SearchItemsResponse struct {
+	Query   string                 `json:"query" example:"postgres"`
+	Results []SearchResultResponse `json:"results"`
+	Count   int                    `json:"count" example:"1"`
+}
+
+// newSearchItemsResponse converts search results into their response
+func newSearchItemsResponse(query string, results []services.SearchResult) SearchItemsResponse {
+	response := SearchItemsResponse{
+		Query:   query,
+		Results: make([]SearchResultResponse, len(results)),
+		Count:   len(results),
+	}
+	for i, result := range results {
+		response.Results[i] = SearchResultResponse{
+			ItemResponse:   newItemResponse(result.Item, result.IsRead),
+			Rank:           result.Rank,
+			TitleHighlight: result.TitleHighlight,
+			Snippet:        result.Snippet,
+		}
+	}
+	return response
+}
+
+// SemanticResultResponse represents an item close in meaning to a query or another item
+type SemanticResultResponse struct {
+	ItemResponse
+	// Score is the cosine similarity of the embeddings, at most 1
+	Score float32 `json:"score" example:"0.83"`
+}
+
+// SemanticSearchResponse represents the results of a semantic search
+type SemanticSearchResponse struct {
+	Query   string                   `json:"query" example:"making databases faster"`
+	Results []SemanticResultResponse `json:"results"`
+	Count   int                      `json:"count" example:"1"`
+}
+
+// RelatedItemsResponse represents the items related to an item
+type RelatedItemsResponse struct {
+	ItemID  int32                    `json:"item_id" example:"1"`
+	Results []SemanticResultResponse `json:"results"`
+	Count   int                      `json:"count" example:"1"`
+}
+
+// newSemanticResultResponses converts semantic matches into their responses
+func newSemanticResultResponses(results []services.SemanticResult) []SemanticResultResponse {
+	responses := make([]SemanticResultResponse, len(results))
+	for i, result := range results {
+		responses[i] = SemanticResultResponse{
+			ItemResponse: newItemResponse(result.Item, result.IsRead),
+			Score:        result.Score,
+		}
+	}
+	return responses
 }
 
 // UpdateItemRequest represents the request body for updating an item
@@ -47,7 +305,12 @@ type UpdateItemRequest struct {
 	Platform    *string  `json:"platform" example:"web"`
 	Tags        []string `json:"tags"`
 	Authors     []string `json:"authors"`
-	IsRead      *bool    `json:"is_read"`
+	IsRead      *bool    `json:"is_read"` // Sets the current user's read state
+}
+
+// MoveItemRequest represents the request body for moving an item between workspaces
+type MoveItemRequest struct {
+	WorkspaceID *int32 `json:"workspace_id" example:"1"`
 }
 
 // PatchItemRequest represents the request body for patching an item
@@ -66,20 +329,180 @@ type ItemProcessingStatusResponse struct {
 	IsCompleted      bool    `json:"is_completed"`
 	IsFailed         bool    `json:"is_failed"`
 	ProcessingError  *string `json:"processing_error"`
+	// Why a failed item failed: paywall, login_wall, captcha, forbidden,
+	// robots, not_found, client_error, server_error, empty_content,
+	// too_large, network, timeout, parse, ai or unknown
+	FailureReason *string `json:"failure_reason" example:"paywall"`
 }
 
-// ItemsByStatusResponse represents items filtered by processing status
+// ItemListResponse represents one page of items
+type ItemListResponse struct {
+	Items []ItemResponse `json:"items"`
+	Count int            `json:"count" example:"1"`
+	// NextCursor fetches the following page; null on the last page
+	NextCursor *string `json:"next_cursor" example:"eyJzIjoiY3JlYXRlZF9hdCIsImQiOnRydWUsImlkIjo0Mn0"`
+}
+
+// ItemsByStatusResponse represents one page of items filtered by processing status
 type ItemsByStatusResponse struct {
-	Status string    `json:"status"`
-	Items  []db.Item `json:"items"`
-	Count  int       `json:"count"`
+	Status string         `json:"status"`
+	Items  []ItemResponse `json:"items"`
+	Count  int            `json:"count"`
+	// NextCursor fetches the following page; null on the last page
+	NextCursor *string `json:"next_cursor"`
+}
+
+// Workspace request/response models
+
+// WorkspaceRequest represents the request body for creating or renaming a workspace
+type WorkspaceRequest struct {
+	Name string `json:"name" binding:"required" example:"Research team"`
+}
+
+// WorkspaceResponse represents a workspace and the current user's role in it
+type WorkspaceResponse struct {
+	ID        int32      `json:"id" example:"1"`
+	Name      string     `json:"name" example:"Research team"`
+	Role      string     `json:"role" example:"owner" enums:"owner,admin,member,viewer"`
+	CreatedBy *int32     `json:"created_by" example:"1"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// newWorkspaceResponse converts a workspace into its response for a member with the given role
+func newWorkspaceResponse(workspace db.Workspace, role string) WorkspaceResponse {
+	return WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      role,
+		CreatedBy: workspace.CreatedBy,
+		CreatedAt: workspace.CreatedAt,
+		UpdatedAt: workspace.UpdatedAt,
+	}
+}
+
+// AddWorkspaceMemberRequest represents the request body for adding a user to a workspace
+type AddWorkspaceMemberRequest struct {
+	Email string `json:"email" binding:"required,email" example:"jane@example.com"`
+	Role  string `json:"role" example:"member" enums:"owner,admin,member,viewer"`
+}
+
+// UpdateWorkspaceMemberRequest represents the request body for changing a member's role
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role" binding:"required" example:"admin" enums:"owner,admin,member,viewer"`
+}
+
+// WorkspaceMemberResponse represents a member of a workspace
+type WorkspaceMemberResponse struct {
+	UserID   int32      `json:"user_id" example:"2"`
+	Name     *string    `json:"name" example:"Jane Doe"`
+	Email    *string    `json:"email" example:"jane@example.com"`
+	Role     string     `json:"role" example:"member" enums:"owner,admin,member,viewer"`
+	JoinedAt *time.Time `json:"joined_at"`
+}
+
+// newWorkspaceMemberResponse converts a membership row into its public response
+func newWorkspaceMemberResponse(member db.ListWorkspaceMembersRow) WorkspaceMemberResponse {
+	return WorkspaceMemberResponse{
+		UserID:   member.UserID,
+		Name:     member.Name,
+		Email:    member.Email,
+		Role:     member.Role,
+		JoinedAt: member.CreatedAt,
+	}
+}
+
+// Feed request/response models
+
+// FeedRequest represents the request body for subscribing to a feed
+type FeedRequest struct {
+	// URL of an RSS, Atom or JSON feed, or of a page that links to one
+	URL   string  `json:"url" binding:"required" example:"https://blog.example.com/feed.xml"`
+	Title *string `json:"title" example:"Example Blog"` // Optional custom title
+	// Tags added to every item saved from the feed
+	Tags []string `json:"tags" example:"tech,blogs"`
+}
+
+// UpdateFeedRequest represents the request body for changing a feed's custom title and tags.
+// Fields left out or null are unchanged.
+type UpdateFeedRequest struct {
+	Title *string  `json:"title" example:"Example Blog"` // Blank restores the feed's own title
+	Tags  []string `json:"tags" example:"tech,blogs"`    // Empty removes the tags
+}
+
+// FeedResponse represents a feed the current user is subscribed to
+type FeedResponse struct {
+	ID    int32   `json:"id" example:"1"`
+	URL   string  `json:"url" example:"https://blog.example.com/feed.xml"`
+	Title *string `json:"title" example:"Example Blog"`
+	// CustomTitle is the user's own title, which takes the place of the feed's title when set
+	CustomTitle  *string    `json:"custom_title" example:"Example Blog"`
+	Tags         []string   `json:"tags" example:"tech,blogs"`
+	SiteURL      *string    `json:"site_url" example:"https://blog.example.com/"`
+	LastPolledAt *time.Time `json:"last_polled_at"`
+	LastError    *string    `json:"last_error"`
+	SubscribedAt *time.Time `json:"subscribed_at"`
+}
+
+// newFeedResponse converts a feed and the user's subscription into its response
+func newFeedResponse(feed db.Feed, subscription db.FeedSubscription) FeedResponse {
+	return FeedResponse{
+		ID:           feed.ID,
+		URL:          feed.Url,
+		Title:        feed.Title,
+		CustomTitle:  subscription.Title,
+		Tags:         subscription.Tags,
+		SiteURL:      feed.SiteUrl,
+		LastPolledAt: feed.LastPolledAt,
+		LastError:    feed.LastError,
+		SubscribedAt: subscription.CreatedAt,
+	}
+}
+
+// OPMLImportFeedResponse represents the outcome of importing one feed of an OPML document
+type OPMLImportFeedResponse struct {
+	URL   string   `json:"url" example:"https://blog.example.com/feed.xml"`
+	Title string   `json:"title" example:"Example Blog"`
+	Tags  []string `json:"tags" example:"tech"` // Folders the feed was in
+	// One of subscribed, reachable (dry run), already_subscribed, unreachable, invalid or failed
+	Status string `json:"status" example:"subscribed"`
+	FeedID *int32 `json:"feed_id,omitempty" example:"1"`
+	Error  string `json:"error,omitempty" example:""`
+}
+
+// OPMLImportResponse represents the result of an OPML import
+type OPMLImportResponse struct {
+	DryRun bool                     `json:"dry_run" example:"false"`
+	Feeds  []OPMLImportFeedResponse `json:"feeds"`
+	// Counts holds the number of feeds with each status
+	Counts map[string]int `json:"counts"`
+}
+
+// newOPMLImportResponse converts import results into their response
+func newOPMLImportResponse(results []services.OPMLImportResult, dryRun bool) OPMLImportResponse {
+	response := OPMLImportResponse{
+		DryRun: dryRun,
+		Feeds:  make([]OPMLImportFeedResponse, len(results)),
+		Counts: make(map[string]int),
+	}
+	for i, result := range results {
+		response.Feeds[i] = OPMLImportFeedResponse{
+			URL:    result.URL,
+			Title:  result.Title,
+			Tags:   result.Tags,
+			Status: result.Status,
+			FeedID: result.FeedID,
+			Error:  result.Error,
+		}
+		response.Counts[result.Status]++
+	}
+	return response
 }
 
 // Podcast request/response models
 
 // CreatePodcastRequest represents the request body for creating a podcast
 type CreatePodcastRequest struct {
-	UserID      int32   `json:"user_id" binding:"required" example:"1"`
 	Title       string  `json:"title" binding:"required" example:"My Daily Digest"`
 	Description string  `json:"description" example:"Today's top articles"`
 	ItemIDs     []int32 `json:"item_ids" binding:"required,min=1" example:"1,2,3"`
@@ -87,7 +510,6 @@ type CreatePodcastRequest struct {
 
 // CreatePodcastFromItemRequest represents the request body for creating a podcast from a single item
 type CreatePodcastFromItemRequest struct {
-	UserID int32 `json:"user_id" binding:"required" example:"1"`
 	ItemID int32 `json:"item_id" binding:"required" example:"1"`
 }
 
@@ -114,6 +536,8 @@ type UpdatePodcastRequest struct {
 type PodcastsResponse struct {
 	Podcasts []db.Podcast `json:"podcasts"`
 	Count    int          `json:"count"`
+	// NextCursor fetches the following page of a paginated list; null on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
 }
 
 // PodcastItemsResponse represents items in a podcast
@@ -149,6 +573,121 @@ type PodcastUploadInfo struct {
 	ExpiresAt string `json:"expires_at"`
 }
 
+// Tag request/response models
+
+// TagResponse represents a tag and how many of the user's items carry it
+type TagResponse struct {
+	Name      string `json:"name" example:"machine-learning"`
+	ItemCount int    `json:"item_count" example:"12"`
+	// Aliases are other spellings replaced by this tag in newly extracted tags
+	Aliases []string `json:"aliases" example:"ml,deep-learning"`
+}
+
+// newTagResponse converts a tag summary into its response
+func newTagResponse(tag services.TagSummary) TagResponse {
+	aliases := tag.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return TagResponse{Name: tag.Name, ItemCount: tag.ItemCount, Aliases: aliases}
+}
+
+// MergeTagsRequest represents the request body for merging tags
+type MergeTagsRequest struct {
+	Sources []string `json:"sources" binding:"required,min=1" example:"AI,artificial intelligence"`
+	Target  string   `json:"target" binding:"required" example:"ai"`
+}
+
+// RenameTagRequest represents the request body for renaming a tag
+type RenameTagRequest struct {
+	From string `json:"from" binding:"required" example:"ml"`
+	To   string `json:"to" binding:"required" example:"machine-learning"`
+}
+
+// TagMergeResponse represents the outcome of merging or renaming tags
+type TagMergeResponse struct {
+	Tag string `json:"tag" example:"ai"`
+	// Aliases recorded for the tag by this change
+	Aliases      []string `json:"aliases" example:"artificial-intelligence"`
+	ItemsUpdated int64    `json:"items_updated" example:"7"`
+}
+
+// newTagMergeResponse converts a merge result into its response
+func newTagMergeResponse(result *services.TagMergeResult) TagMergeResponse {
+	aliases := result.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return TagMergeResponse{Tag: result.Tag, Aliases: aliases, ItemsUpdated: result.ItemsUpdated}
+}
+
+// Collection request/response models
+
+// CollectionRequest represents the request body for creating a collection
+type CollectionRequest struct {
+	Name        string  `json:"name" binding:"required" example:"Q3 research"`
+	Description *string `json:"description" example:"Reading for the Q3 planning review"`
+}
+
+// UpdateCollectionRequest represents the request body for updating a
+// collection. Omitted fields are left unchanged; an empty description clears it.
+type UpdateCollectionRequest struct {
+	Name        *string `json:"name" example:"Q3 research"`
+	Description *string `json:"description" example:"Reading for the Q3 planning review"`
+}
+
+// CollectionResponse represents a collection
+type CollectionResponse struct {
+	ID          int32      `json:"id" example:"1"`
+	Name        string     `json:"name" example:"Q3 research"`
+	Description *string    `json:"description" example:"Reading for the Q3 planning review"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// newCollectionResponse converts a collection into its response
+func newCollectionResponse(collection db.Collection) CollectionResponse {
+	return CollectionResponse{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+	}
+}
+
+// CollectionSummaryResponse represents a collection with the number of items in it
+type CollectionSummaryResponse struct {
+	CollectionResponse
+	ItemCount int32 `json:"item_count" example:"8"`
+}
+
+// CollectionItemRequest represents the request body for adding an item to a collection
+type CollectionItemRequest struct {
+	ItemID int32 `json:"item_id" binding:"required" example:"42"`
+}
+
+// ReorderCollectionItemsRequest represents the request body for reordering a
+// collection. The items listed move to the front in the order given.
+type ReorderCollectionItemsRequest struct {
+	ItemIDs []int32 `json:"item_ids" binding:"required,min=1" example:"3,1,2"`
+}
+
+// CollectionItemsResponse represents the items of a collection in order
+type CollectionItemsResponse struct {
+	Items []ItemResponse `json:"items"`
+	Count int            `json:"count" example:"3"`
+}
+
+// newCollectionItemsResponse converts collection items into their response
+func newCollectionItemsResponse(rows []db.ListCollectionItemsRow) CollectionItemsResponse {
+	items := make([]ItemResponse, len(rows))
+	for i, row := range rows {
+		items[i] = newItemResponse(row.Item, row.IsRead)
+	}
+	return CollectionItemsResponse{Items: items, Count: len(items)}
+}
+
 // Generic response models
 
 // ErrorResponse represents an error response
@@ -35,12 +35,21 @@ func (h *PodcastHandler) SetSSEManager(sseManager *services.SSEManager) {
 // @Tags         podcasts
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        podcast  body      CreatePodcastRequest  true  "Podcast creation request"
 // @Success      201      {object}  CreatePodcastResponse
 // @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Rate limit or daily podcast minutes quota exceeded; see Retry-After"
 // @Failure      500      {object}  ErrorResponse
 // @Router       /podcasts [post]
 func (h *PodcastHandler) CreatePodcast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var req CreatePodcastRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -49,9 +58,9 @@ func (h *PodcastHandler) CreatePodcast(c *gin.Context) {
 	}
 
 	// Create podcast from items
-	podcast, err := h.podcastService.CreatePodcastFromItems(c.Request.Context(), req.UserID, req.Title, req.Description, req.ItemIDs)
+	podcast, err := h.podcastService.CreatePodcastFromItems(c.Request.Context(), userID, req.Title, req.Description, req.ItemIDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -68,12 +77,21 @@ func (h *PodcastHandler) CreatePodcast(c *gin.Context) {
 // @Tags         podcasts
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        podcast  body      CreatePodcastFromItemRequest  true  "Podcast from item request"
 // @Success      201      {object}  CreatePodcastResponse
 // @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      429      {object}  ErrorResponse  "Rate limit or daily podcast minutes quota exceeded; see Retry-After"
 // @Failure      500      {object}  ErrorResponse
 // @Router       /podcasts/from-item [post]
 func (h *PodcastHandler) CreatePodcastFromSingleItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	var req CreatePodcastFromItemRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -82,9 +100,9 @@ func (h *PodcastHandler) CreatePodcastFromSingleItem(c *gin.Context) {
 	}
 
 	// Create podcast from single item
-	podcast, err := h.podcastService.CreatePodcastFromSingleItem(c.Request.Context(), req.UserID, req.ItemID)
+	podcast, err := h.podcastService.CreatePodcastFromSingleItem(c.Request.Context(), userID, req.ItemID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -100,12 +118,20 @@ func (h *PodcastHandler) CreatePodcastFromSingleItem(c *gin.Context) {
 // @Description  Retrieve a podcast's information by its ID
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Podcast ID"
 // @Success      200  {object}  github_com_yamirghofran_briefbot_internal_db.Podcast
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /podcasts/{id} [get]
 func (h *PodcastHandler) GetPodcast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -113,9 +139,9 @@ func (h *PodcastHandler) GetPodcast(c *gin.Context) {
 		return
 	}
 
-	podcast, err := h.podcastService.GetPodcast(c.Request.Context(), int32(id))
+	podcast, err := h.podcastService.GetPodcast(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -124,45 +150,74 @@ func (h *PodcastHandler) GetPodcast(c *gin.Context) {
 
 // GetPodcastsByUser godoc
 // @Summary      Get podcasts by user
-// @Description  Retrieve all podcasts for a specific user
+// @Description  Lists the authenticated user's podcasts one page at a time, newest first unless sorted otherwise. Pass the returned next_cursor as cursor to get the following page; it is null on the last page.
 // @Tags         podcasts
 // @Produce      json
-// @Param        userID  path      int  true  "User ID"
+// @Security     BearerAuth
+// @Param        status  query     string  false  "Only podcasts with this status (pending, writing, generating, completed, failed)"
+// @Param        from    query     string  false  "Only podcasts created on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param        to      query     string  false  "Only podcasts created up to this date, inclusive (YYYY-MM-DD), or before this time (RFC 3339)"
+// @Param        sort    query     string  false  "Sort by created_at or title"  default(created_at)
+// @Param        order   query     string  false  "asc or desc; defaults to desc for created_at and asc for title"
+// @Param        limit   query     int     false  "Page size (1-200)"  default(50)
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
 // @Success      200     {object}  PodcastsResponse
 // @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
-// @Router       /podcasts/user/{userID} [get]
+// @Router       /podcasts [get]
 func (h *PodcastHandler) GetPodcastsByUser(c *gin.Context) {
-	userIDStr := c.Param("userID")
-	userID, err := strconv.ParseInt(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	podcasts, err := h.podcastService.GetPodcastsByUser(c.Request.Context(), int32(userID))
+	var query services.PodcastListQuery
+	if status := c.Query("status"); status != "" {
+		podcastStatus := services.PodcastStatus(status)
+		query.Status = &podcastStatus
+	}
+	if query.CreatedAfter, ok = dateQuery(c, "from", false); !ok {
+		return
+	}
+	if query.CreatedBefore, ok = dateQuery(c, "to", true); !ok {
+		return
+	}
+	if query.Page, ok = pageQuery(c); !ok {
+		return
+	}
+
+	page, err := h.podcastService.ListPodcasts(c.Request.Context(), userID, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"podcasts": podcasts,
-		"count":    len(podcasts),
+	c.JSON(http.StatusOK, PodcastsResponse{
+		Podcasts:   page.Podcasts,
+		Count:      len(page.Podcasts),
+		NextCursor: optionalCursor(page.NextCursor),
 	})
 }
 
 // GetPodcastsByStatus godoc
 // @Summary      Get podcasts by status
-// @Description  Retrieve podcasts filtered by their processing status
+// @Description  Retrieve the authenticated user's podcasts filtered by their processing status
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        status  path      string  true  "Podcast status (pending, writing, generating, completed, failed)"
 // @Success      200     {object}  PodcastsResponse
 // @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
 // @Router       /podcasts/status/{status} [get]
 func (h *PodcastHandler) GetPodcastsByStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	status := c.Param("status")
 
 	// Validate status
@@ -180,7 +235,7 @@ func (h *PodcastHandler) GetPodcastsByStatus(c *gin.Context) {
 		return
 	}
 
-	podcasts, err := h.podcastService.GetPodcastsByStatus(c.Request.Context(), services.PodcastStatus(status))
+	podcasts, err := h.podcastService.GetPodcastsByStatus(c.Request.Context(), userID, services.PodcastStatus(status))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -194,15 +249,22 @@ func (h *PodcastHandler) GetPodcastsByStatus(c *gin.Context) {
 
 // GetPendingPodcasts godoc
 // @Summary      Get pending podcasts
-// @Description  Retrieve pending podcasts awaiting processing
+// @Description  Retrieve the authenticated user's pending podcasts awaiting processing
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        limit  query     int  false  "Maximum number of podcasts to return"  default(10)
 // @Success      200    {object}  PodcastsResponse
 // @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
 // @Failure      500    {object}  ErrorResponse
 // @Router       /podcasts/pending [get]
 func (h *PodcastHandler) GetPendingPodcasts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	limitStr := c.DefaultQuery("limit", "10")
 	limit, err := strconv.ParseInt(limitStr, 10, 32)
 	if err != nil {
@@ -210,12 +272,16 @@ func (h *PodcastHandler) GetPendingPodcasts(c *gin.Context) {
 		return
 	}
 
-	podcasts, err := h.podcastService.GetPendingPodcasts(c.Request.Context(), int32(limit))
+	podcasts, err := h.podcastService.GetPodcastsByStatus(c.Request.Context(), userID, services.PodcastStatusPending)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if limit >= 0 && int(limit) < len(podcasts) {
+		podcasts = podcasts[:limit]
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"podcasts": podcasts,
 		"count":    len(podcasts),
@@ -227,12 +293,20 @@ func (h *PodcastHandler) GetPendingPodcasts(c *gin.Context) {
 // @Description  Retrieve all content items in a podcast
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Podcast ID"
 // @Success      200  {object}  PodcastItemsResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /podcasts/{id}/items [get]
 func (h *PodcastHandler) GetPodcastItems(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -240,9 +314,9 @@ func (h *PodcastHandler) GetPodcastItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.podcastService.GetPodcastItems(c.Request.Context(), int32(id))
+	items, err := h.podcastService.GetPodcastItems(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -257,12 +331,19 @@ func (h *PodcastHandler) GetPodcastItems(c *gin.Context) {
 // @Description  Retrieve the audio URL for a completed podcast
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Podcast ID"
 // @Success      200  {object}  PodcastAudioResponse
 // @Failure      404  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /podcasts/{id}/audio [get]
 func (h *PodcastHandler) GetPodcastAudio(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -271,9 +352,9 @@ func (h *PodcastHandler) GetPodcastAudio(c *gin.Context) {
 	}
 
 	// Check if podcast has audio
-	hasAudio, err := h.podcastService.HasPodcastAudio(c.Request.Context(), int32(id))
+	hasAudio, err := h.podcastService.HasPodcastAudio(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -283,9 +364,9 @@ func (h *PodcastHandler) GetPodcastAudio(c *gin.Context) {
 	}
 
 	// Get the podcast to retrieve the audio URL
-	podcast, err := h.podcastService.GetPodcast(c.Request.Context(), int32(id))
+	podcast, err := h.podcastService.GetPodcast(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -307,12 +388,20 @@ func (h *PodcastHandler) GetPodcastAudio(c *gin.Context) {
 // @Description  Generate a presigned URL for uploading podcast audio to R2 storage
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Podcast ID"
 // @Success      200  {object}  PodcastUploadInfo
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /podcasts/{id}/upload-url [get]
 func (h *PodcastHandler) GeneratePodcastUploadURL(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -320,9 +409,9 @@ func (h *PodcastHandler) GeneratePodcastUploadURL(c *gin.Context) {
 		return
 	}
 
-	uploadInfo, err := h.podcastService.GeneratePodcastUploadURL(c.Request.Context(), int32(id))
+	uploadInfo, err := h.podcastService.GeneratePodcastUploadURL(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -335,13 +424,21 @@ func (h *PodcastHandler) GeneratePodcastUploadURL(c *gin.Context) {
 // @Tags         podcasts
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id    path      int                        true  "Podcast ID"
 // @Param        item  body      AddItemToPodcastRequest    true  "Add item request"
 // @Success      200   {object}  MessageResponse
 // @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /podcasts/{id}/items [post]
 func (h *PodcastHandler) AddItemToPodcast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	podcastIDStr := c.Param("id")
 	podcastID, err := strconv.ParseInt(podcastIDStr, 10, 32)
 	if err != nil {
@@ -356,9 +453,9 @@ func (h *PodcastHandler) AddItemToPodcast(c *gin.Context) {
 		return
 	}
 
-	err = h.podcastService.AddItemToPodcast(c.Request.Context(), int32(podcastID), req.ItemID, req.Order)
+	err = h.podcastService.AddItemToPodcast(c.Request.Context(), userID, int32(podcastID), req.ItemID, req.Order)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -372,13 +469,21 @@ func (h *PodcastHandler) AddItemToPodcast(c *gin.Context) {
 // @Description  Remove a content item from a podcast
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id      path      int  true  "Podcast ID"
 // @Param        itemID  path      int  true  "Item ID"
 // @Success      200     {object}  MessageResponse
 // @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
 // @Router       /podcasts/{id}/items/{itemID} [delete]
 func (h *PodcastHandler) RemoveItemFromPodcast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	podcastIDStr := c.Param("id")
 	podcastID, err := strconv.ParseInt(podcastIDStr, 10, 32)
 	if err != nil {
@@ -393,9 +498,9 @@ func (h *PodcastHandler) RemoveItemFromPodcast(c *gin.Context) {
 		return
 	}
 
-	err = h.podcastService.RemoveItemFromPodcast(c.Request.Context(), int32(podcastID), int32(itemID))
+	err = h.podcastService.RemoveItemFromPodcast(c.Request.Context(), userID, int32(podcastID), int32(itemID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -410,13 +515,21 @@ func (h *PodcastHandler) RemoveItemFromPodcast(c *gin.Context) {
 // @Tags         podcasts
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int                     true  "Podcast ID"
 // @Param        podcast  body      UpdatePodcastRequest    true  "Podcast update request"
 // @Success      200      {object}  MessageResponse
 // @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
 // @Failure      500      {object}  ErrorResponse
 // @Router       /podcasts/{id} [put]
 func (h *PodcastHandler) UpdatePodcast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -431,9 +544,9 @@ func (h *PodcastHandler) UpdatePodcast(c *gin.Context) {
 		return
 	}
 
-	err = h.podcastService.UpdatePodcast(c.Request.Context(), int32(id), req.Title, req.Description)
+	err = h.podcastService.UpdatePodcast(c.Request.Context(), userID, int32(id), req.Title, req.Description)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -447,12 +560,20 @@ func (h *PodcastHandler) UpdatePodcast(c *gin.Context) {
 // @Description  Delete a podcast from the system
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Podcast ID"
 // @Success      200  {object}  MessageResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /podcasts/{id} [delete]
 func (h *PodcastHandler) DeletePodcast(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -460,9 +581,9 @@ func (h *PodcastHandler) DeletePodcast(c *gin.Context) {
 		return
 	}
 
-	err = h.podcastService.DeletePodcast(c.Request.Context(), int32(id))
+	err = h.podcastService.DeletePodcast(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -476,12 +597,20 @@ func (h *PodcastHandler) DeletePodcast(c *gin.Context) {
 // @Description  Retrieve the processing status of a podcast
 // @Tags         podcasts
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Podcast ID"
 // @Success      200  {object}  PodcastProcessingStatusResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /podcasts/{id}/status [get]
 func (h *PodcastHandler) GetPodcastProcessingStatus(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -489,9 +618,9 @@ func (h *PodcastHandler) GetPodcastProcessingStatus(c *gin.Context) {
 		return
 	}
 
-	podcast, err := h.podcastService.GetPodcast(c.Request.Context(), int32(id))
+	podcast, err := h.podcastService.GetPodcast(c.Request.Context(), userID, int32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -521,16 +650,15 @@ func (h *PodcastHandler) GetPodcastProcessingStatus(c *gin.Context) {
 // @Description  Server-Sent Events endpoint for real-time podcast processing updates
 // @Tags         podcasts
 // @Produce      text/event-stream
-// @Param        userID  path      int  true  "User ID"
-// @Success      200     {string}  string "SSE stream"
-// @Failure      400     {object}  ErrorResponse
-// @Failure      500     {object}  ErrorResponse
-// @Router       /podcasts/user/{userID}/stream [get]
+// @Security     BearerAuth
+// @Param        access_token  query     string  false  "Token for clients that cannot set the Authorization header"
+// @Success      200           {string}  string "SSE stream"
+// @Failure      401           {object}  ErrorResponse
+// @Failure      500           {object}  ErrorResponse
+// @Router       /podcasts/stream [get]
 func (h *PodcastHandler) StreamPodcastUpdates(c *gin.Context) {
-	userIDStr := c.Param("userID")
-	userID, err := strconv.ParseInt(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
@@ -543,13 +671,12 @@ func (h *PodcastHandler) StreamPodcastUpdates(c *gin.Context) {
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
-	c.Header("Access-Control-Allow-Origin", "*")
 
 	// Create a channel for writing SSE messages
 	messageChan := make(chan string, 10)
 
 	// Register client with SSE manager
-	client := h.sseManager.AddClient(int32(userID))
+	client := h.sseManager.AddClient(userID)
 	defer h.sseManager.RemoveClient(client)
 
 	// Start goroutine to write messages from the client channel
@@ -5,12 +5,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/yamirghofran/briefbot/internal/db"
 	"github.com/yamirghofran/briefbot/internal/services"
 )
@@ -50,8 +53,8 @@ func (m *MockPodcastService) ProcessPodcast(ctx context.Context, podcastID int32
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) GetPodcast(ctx context.Context, podcastID int32) (*db.Podcast, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GetPodcast(ctx context.Context, userID int32, podcastID int32) (*db.Podcast, error) {
+	args := m.Called(ctx, userID, podcastID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -63,33 +66,41 @@ func (m *MockPodcastService) GetPodcastsByUser(ctx context.Context, userID int32
 	return args.Get(0).([]db.Podcast), args.Error(1)
 }
 
-func (m *MockPodcastService) GetPodcastsByStatus(ctx context.Context, status services.PodcastStatus) ([]db.Podcast, error) {
-	args := m.Called(ctx, status)
+func (m *MockPodcastService) GetPodcastsByStatus(ctx context.Context, userID int32, status services.PodcastStatus) ([]db.Podcast, error) {
+	args := m.Called(ctx, userID, status)
 	return args.Get(0).([]db.Podcast), args.Error(1)
 }
 
-func (m *MockPodcastService) UpdatePodcast(ctx context.Context, podcastID int32, title string, description string) error {
-	args := m.Called(ctx, podcastID, title, description)
+func (m *MockPodcastService) ListPodcasts(ctx context.Context, userID int32, query services.PodcastListQuery) (*services.PodcastPage, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.PodcastPage), args.Error(1)
+}
+
+func (m *MockPodcastService) UpdatePodcast(ctx context.Context, userID int32, podcastID int32, title string, description string) error {
+	args := m.Called(ctx, userID, podcastID, title, description)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) DeletePodcast(ctx context.Context, podcastID int32) error {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) DeletePodcast(ctx context.Context, userID int32, podcastID int32) error {
+	args := m.Called(ctx, userID, podcastID)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) AddItemToPodcast(ctx context.Context, podcastID int32, itemID int32, order int) error {
-	args := m.Called(ctx, podcastID, itemID, order)
+func (m *MockPodcastService) AddItemToPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32, order int) error {
+	args := m.Called(ctx, userID, podcastID, itemID, order)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) RemoveItemFromPodcast(ctx context.Context, podcastID int32, itemID int32) error {
-	args := m.Called(ctx, podcastID, itemID)
+func (m *MockPodcastService) RemoveItemFromPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32) error {
+	args := m.Called(ctx, userID, podcastID, itemID)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) GetPodcastItems(ctx context.Context, podcastID int32) ([]db.GetPodcastItemsRow, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GetPodcastItems(ctx context.Context, userID int32, podcastID int32) ([]db.GetPodcastItemsRow, error) {
+	args := m.Called(ctx, userID, podcastID)
 	return args.Get(0).([]db.GetPodcastItemsRow), args.Error(1)
 }
 
@@ -113,21 +124,21 @@ func (m *MockPodcastService) GetProcessingPodcasts(ctx context.Context, limit in
 	return args.Get(0).([]db.Podcast), args.Error(1)
 }
 
-func (m *MockPodcastService) GetPodcastAudio(ctx context.Context, podcastID int32) ([]byte, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GetPodcastAudio(ctx context.Context, userID int32, podcastID int32) ([]byte, error) {
+	args := m.Called(ctx, userID, podcastID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]byte), args.Error(1)
 }
 
-func (m *MockPodcastService) HasPodcastAudio(ctx context.Context, podcastID int32) (bool, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) HasPodcastAudio(ctx context.Context, userID int32, podcastID int32) (bool, error) {
+	args := m.Called(ctx, userID, podcastID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockPodcastService) GeneratePodcastUploadURL(ctx context.Context, podcastID int32) (*services.UploadURLResponse, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GeneratePodcastUploadURL(ctx context.Context, userID int32, podcastID int32) (*services.UploadURLResponse, error) {
+	args := m.Called(ctx, userID, podcastID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -138,6 +149,14 @@ func (m *MockPodcastService) SetSSEManager(sseManager *services.SSEManager) {
 	m.Called(sseManager)
 }
 
+func (m *MockPodcastService) SetPreferencesService(preferencesService services.PreferencesService) {
+	m.Called(preferencesService)
+}
+
+func (m *MockPodcastService) SetQuotaService(quotaService services.QuotaService) {
+	m.Called(quotaService)
+}
+
 func TestCreatePodcast(t *testing.T) {
 	mockPodcastService := new(MockPodcastService)
 	handler := NewPodcastHandler(mockPodcastService)
@@ -154,7 +173,6 @@ func TestCreatePodcast(t *testing.T) {
 	mockPodcastService.On("CreatePodcastFromItems", mock.Anything, int32(1), "Test Podcast", "Description", []int32{1, 2}).Return(expectedPodcast, nil)
 
 	reqBody := map[string]interface{}{
-		"user_id":     1,
 		"title":       "Test Podcast",
 		"description": "Description",
 		"item_ids":    []int{1, 2},
@@ -187,7 +205,6 @@ func TestCreatePodcastFromSingleItem(t *testing.T) {
 	mockPodcastService.On("CreatePodcastFromSingleItem", mock.Anything, int32(1), int32(1)).Return(expectedPodcast, nil)
 
 	reqBody := map[string]interface{}{
-		"user_id": 1,
 		"item_id": 1,
 	}
 	jsonBody, _ := json.Marshal(reqBody)
@@ -213,7 +230,7 @@ func TestGetPodcast(t *testing.T) {
 		ID: 1,
 	}
 
-	mockPodcastService.On("GetPodcast", mock.Anything, int32(1)).Return(expectedPodcast, nil)
+	mockPodcastService.On("GetPodcast", mock.Anything, testUserID, int32(1)).Return(expectedPodcast, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1", nil)
@@ -232,9 +249,7 @@ func TestCreatePodcast_ValidationError(t *testing.T) {
 	router.POST("/podcasts", handler.CreatePodcast)
 
 	// Missing required fields
-	reqBody := map[string]interface{}{
-		"user_id": 1,
-	}
+	reqBody := map[string]interface{}{}
 	jsonBody, _ := json.Marshal(reqBody)
 
 	w := httptest.NewRecorder()
@@ -256,7 +271,6 @@ func TestCreatePodcast_ServiceError(t *testing.T) {
 	mockPodcastService.On("CreatePodcastFromItems", mock.Anything, int32(1), "Test", "Desc", []int32{1}).Return(nil, errors.New("service error"))
 
 	reqBody := map[string]interface{}{
-		"user_id":     1,
 		"title":       "Test",
 		"description": "Desc",
 		"item_ids":    []int{1},
@@ -278,37 +292,90 @@ func TestGetPodcastsByUser(t *testing.T) {
 	handler := NewPodcastHandler(mockPodcastService)
 
 	router := setupTestRouter()
-	router.GET("/podcasts/user/:userID", handler.GetPodcastsByUser)
-
-	expectedPodcasts := []db.Podcast{
-		{ID: 1, Title: "Podcast 1"},
-		{ID: 2, Title: "Podcast 2"},
+	router.GET("/podcasts", handler.GetPodcastsByUser)
+
+	page := &services.PodcastPage{
+		Podcasts: []db.Podcast{
+			{ID: 1, Title: "Podcast 1"},
+			{ID: 2, Title: "Podcast 2"},
+		},
+		NextCursor: "next-page",
 	}
 
-	mockPodcastService.On("GetPodcastsByUser", mock.Anything, int32(1)).Return(expectedPodcasts, nil)
+	mockPodcastService.On("ListPodcasts", mock.Anything, int32(1), services.PodcastListQuery{}).Return(page, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/podcasts/user/1", nil)
+	req, _ := http.NewRequest("GET", "/podcasts", nil)
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response PodcastsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	require.NotNil(t, response.NextCursor)
+	assert.Equal(t, "next-page", *response.NextCursor)
 	mockPodcastService.AssertExpectations(t)
 }
 
-func TestGetPodcastsByUser_InvalidID(t *testing.T) {
+func TestGetPodcastsByUser_FiltersAndPage(t *testing.T) {
 	mockPodcastService := new(MockPodcastService)
 	handler := NewPodcastHandler(mockPodcastService)
 
 	router := setupTestRouter()
-	router.GET("/podcasts/user/:userID", handler.GetPodcastsByUser)
+	router.GET("/podcasts", handler.GetPodcastsByUser)
+
+	status := services.PodcastStatusCompleted
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockPodcastService.On("ListPodcasts", mock.Anything, int32(1), services.PodcastListQuery{
+		Status:       &status,
+		CreatedAfter: &from,
+		Page:         services.PageQuery{Sort: "title", Limit: 5, Cursor: "abc"},
+	}).Return(&services.PodcastPage{Podcasts: []db.Podcast{}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/podcasts?status=completed&from=2024-03-01&sort=title&limit=5&cursor=abc", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockPodcastService.AssertExpectations(t)
+}
+
+func TestGetPodcastsByUser_InvalidStatus(t *testing.T) {
+	mockPodcastService := new(MockPodcastService)
+	handler := NewPodcastHandler(mockPodcastService)
+
+	router := setupTestRouter()
+	router.GET("/podcasts", handler.GetPodcastsByUser)
+
+	status := services.PodcastStatus("queued")
+	mockPodcastService.On("ListPodcasts", mock.Anything, int32(1), services.PodcastListQuery{Status: &status}).
+		Return(nil, fmt.Errorf("%w: unknown status %q", services.ErrInvalidListQuery, status))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/podcasts/user/invalid", nil)
+	req, _ := http.NewRequest("GET", "/podcasts?status=queued", nil)
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockPodcastService.AssertExpectations(t)
+}
+
+func TestGetPodcastsByUser_Unauthenticated(t *testing.T) {
+	mockPodcastService := new(MockPodcastService)
+	handler := NewPodcastHandler(mockPodcastService)
+
+	router := setupAnonymousRouter()
+	router.GET("/podcasts", handler.GetPodcastsByUser)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/podcasts", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
 func TestGetPodcastsByStatus(t *testing.T) {
@@ -322,7 +389,7 @@ func TestGetPodcastsByStatus(t *testing.T) {
 		{ID: 1, Status: "pending"},
 	}
 
-	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, services.PodcastStatus("pending")).Return(expectedPodcasts, nil)
+	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, testUserID, services.PodcastStatus("pending")).Return(expectedPodcasts, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/status/pending", nil)
@@ -360,7 +427,7 @@ func TestGetPendingPodcasts(t *testing.T) {
 		{ID: 2, Status: "pending"},
 	}
 
-	mockPodcastService.On("GetPendingPodcasts", mock.Anything, int32(10)).Return(expectedPodcasts, nil)
+	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, testUserID, services.PodcastStatusPending).Return(expectedPodcasts, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/pending", nil)
@@ -380,16 +447,22 @@ func TestGetPendingPodcasts_WithLimit(t *testing.T) {
 
 	expectedPodcasts := []db.Podcast{
 		{ID: 1, Status: "pending"},
+		{ID: 2, Status: "pending"},
+		{ID: 3, Status: "pending"},
 	}
 
-	mockPodcastService.On("GetPendingPodcasts", mock.Anything, int32(5)).Return(expectedPodcasts, nil)
+	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, testUserID, services.PodcastStatusPending).Return(expectedPodcasts, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/podcasts/pending?limit=5", nil)
+	req, _ := http.NewRequest("GET", "/podcasts/pending?limit=2", nil)
 
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(2), response["count"])
 	mockPodcastService.AssertExpectations(t)
 }
 
@@ -407,7 +480,7 @@ func TestGetPodcastItems(t *testing.T) {
 		{ID: 2, Title: title2},
 	}
 
-	mockPodcastService.On("GetPodcastItems", mock.Anything, int32(1)).Return(expectedItems, nil)
+	mockPodcastService.On("GetPodcastItems", mock.Anything, testUserID, int32(1)).Return(expectedItems, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/items", nil)
@@ -433,8 +506,8 @@ func TestGetPodcastAudio(t *testing.T) {
 		DurationSeconds: &duration,
 	}
 
-	mockPodcastService.On("HasPodcastAudio", mock.Anything, int32(1)).Return(true, nil)
-	mockPodcastService.On("GetPodcast", mock.Anything, int32(1)).Return(expectedPodcast, nil)
+	mockPodcastService.On("HasPodcastAudio", mock.Anything, testUserID, int32(1)).Return(true, nil)
+	mockPodcastService.On("GetPodcast", mock.Anything, testUserID, int32(1)).Return(expectedPodcast, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/audio", nil)
@@ -452,7 +525,7 @@ func TestGetPodcastAudio_NotAvailable(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/:id/audio", handler.GetPodcastAudio)
 
-	mockPodcastService.On("HasPodcastAudio", mock.Anything, int32(1)).Return(false, nil)
+	mockPodcastService.On("HasPodcastAudio", mock.Anything, testUserID, int32(1)).Return(false, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/audio", nil)
@@ -475,7 +548,7 @@ func TestGeneratePodcastUploadURL(t *testing.T) {
 		Key:       "podcasts/1/audio.mp3",
 	}
 
-	mockPodcastService.On("GeneratePodcastUploadURL", mock.Anything, int32(1)).Return(expectedResponse, nil)
+	mockPodcastService.On("GeneratePodcastUploadURL", mock.Anything, testUserID, int32(1)).Return(expectedResponse, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/upload-url", nil)
@@ -493,7 +566,7 @@ func TestAddItemToPodcast(t *testing.T) {
 	router := setupTestRouter()
 	router.POST("/podcasts/:id/items", handler.AddItemToPodcast)
 
-	mockPodcastService.On("AddItemToPodcast", mock.Anything, int32(1), int32(5), 0).Return(nil)
+	mockPodcastService.On("AddItemToPodcast", mock.Anything, testUserID, int32(1), int32(5), 0).Return(nil)
 
 	reqBody := map[string]interface{}{
 		"item_id": 5,
@@ -518,7 +591,7 @@ func TestRemoveItemFromPodcast(t *testing.T) {
 	router := setupTestRouter()
 	router.DELETE("/podcasts/:id/items/:itemID", handler.RemoveItemFromPodcast)
 
-	mockPodcastService.On("RemoveItemFromPodcast", mock.Anything, int32(1), int32(5)).Return(nil)
+	mockPodcastService.On("RemoveItemFromPodcast", mock.Anything, testUserID, int32(1), int32(5)).Return(nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/podcasts/1/items/5", nil)
@@ -536,7 +609,7 @@ func TestUpdatePodcast(t *testing.T) {
 	router := setupTestRouter()
 	router.PUT("/podcasts/:id", handler.UpdatePodcast)
 
-	mockPodcastService.On("UpdatePodcast", mock.Anything, int32(1), "Updated Title", "Updated Description").Return(nil)
+	mockPodcastService.On("UpdatePodcast", mock.Anything, testUserID, int32(1), "Updated Title", "Updated Description").Return(nil)
 
 	reqBody := map[string]interface{}{
 		"title":       "Updated Title",
@@ -561,7 +634,7 @@ func TestDeletePodcast(t *testing.T) {
 	router := setupTestRouter()
 	router.DELETE("/podcasts/:id", handler.DeletePodcast)
 
-	mockPodcastService.On("DeletePodcast", mock.Anything, int32(1)).Return(nil)
+	mockPodcastService.On("DeletePodcast", mock.Anything, testUserID, int32(1)).Return(nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/podcasts/1", nil)
@@ -594,7 +667,7 @@ func TestGetPodcast_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/:id", handler.GetPodcast)
 
-	mockPodcastService.On("GetPodcast", mock.Anything, int32(1)).Return(nil, errors.New("service error"))
+	mockPodcastService.On("GetPodcast", mock.Anything, testUserID, int32(1)).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1", nil)
@@ -610,12 +683,12 @@ func TestGetPodcastsByUser_ServiceError(t *testing.T) {
 	handler := NewPodcastHandler(mockPodcastService)
 
 	router := setupTestRouter()
-	router.GET("/podcasts/user/:userID", handler.GetPodcastsByUser)
+	router.GET("/podcasts", handler.GetPodcastsByUser)
 
-	mockPodcastService.On("GetPodcastsByUser", mock.Anything, int32(1)).Return([]db.Podcast{}, errors.New("service error"))
+	mockPodcastService.On("ListPodcasts", mock.Anything, int32(1), services.PodcastListQuery{}).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/podcasts/user/1", nil)
+	req, _ := http.NewRequest("GET", "/podcasts", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -630,7 +703,7 @@ func TestGetPodcastsByStatus_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/status/:status", handler.GetPodcastsByStatus)
 
-	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, services.PodcastStatus("pending")).Return([]db.Podcast{}, errors.New("service error"))
+	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, testUserID, services.PodcastStatus("pending")).Return([]db.Podcast{}, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/status/pending", nil)
@@ -663,7 +736,7 @@ func TestGetPendingPodcasts_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/pending", handler.GetPendingPodcasts)
 
-	mockPodcastService.On("GetPendingPodcasts", mock.Anything, int32(10)).Return([]db.Podcast{}, errors.New("service error"))
+	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, testUserID, services.PodcastStatusPending).Return([]db.Podcast{}, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/pending", nil)
@@ -696,7 +769,7 @@ func TestGetPodcastItems_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/:id/items", handler.GetPodcastItems)
 
-	mockPodcastService.On("GetPodcastItems", mock.Anything, int32(1)).Return([]db.GetPodcastItemsRow{}, errors.New("service error"))
+	mockPodcastService.On("GetPodcastItems", mock.Anything, testUserID, int32(1)).Return([]db.GetPodcastItemsRow{}, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/items", nil)
@@ -729,7 +802,7 @@ func TestGetPodcastAudio_HasAudioError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/:id/audio", handler.GetPodcastAudio)
 
-	mockPodcastService.On("HasPodcastAudio", mock.Anything, int32(1)).Return(false, errors.New("service error"))
+	mockPodcastService.On("HasPodcastAudio", mock.Anything, testUserID, int32(1)).Return(false, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/audio", nil)
@@ -747,8 +820,8 @@ func TestGetPodcastAudio_GetPodcastError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/:id/audio", handler.GetPodcastAudio)
 
-	mockPodcastService.On("HasPodcastAudio", mock.Anything, int32(1)).Return(true, nil)
-	mockPodcastService.On("GetPodcast", mock.Anything, int32(1)).Return(nil, errors.New("service error"))
+	mockPodcastService.On("HasPodcastAudio", mock.Anything, testUserID, int32(1)).Return(true, nil)
+	mockPodcastService.On("GetPodcast", mock.Anything, testUserID, int32(1)).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/audio", nil)
@@ -766,8 +839,8 @@ func TestGetPodcastAudio_NoAudioURL(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/:id/audio", handler.GetPodcastAudio)
 
-	mockPodcastService.On("HasPodcastAudio", mock.Anything, int32(1)).Return(true, nil)
-	mockPodcastService.On("GetPodcast", mock.Anything, int32(1)).Return(&db.Podcast{ID: 1, AudioUrl: nil}, nil)
+	mockPodcastService.On("HasPodcastAudio", mock.Anything, testUserID, int32(1)).Return(true, nil)
+	mockPodcastService.On("GetPodcast", mock.Anything, testUserID, int32(1)).Return(&db.Podcast{ID: 1, AudioUrl: nil}, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/audio", nil)
@@ -800,7 +873,7 @@ func TestGeneratePodcastUploadURL_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.GET("/podcasts/:id/upload-url", handler.GeneratePodcastUploadURL)
 
-	mockPodcastService.On("GeneratePodcastUploadURL", mock.Anything, int32(1)).Return(nil, errors.New("service error"))
+	mockPodcastService.On("GeneratePodcastUploadURL", mock.Anything, testUserID, int32(1)).Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/podcasts/1/upload-url", nil)
@@ -860,7 +933,7 @@ func TestAddItemToPodcast_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.POST("/podcasts/:id/items", handler.AddItemToPodcast)
 
-	mockPodcastService.On("AddItemToPodcast", mock.Anything, int32(1), int32(5), 0).Return(errors.New("service error"))
+	mockPodcastService.On("AddItemToPodcast", mock.Anything, testUserID, int32(1), int32(5), 0).Return(errors.New("service error"))
 
 	reqBody := map[string]interface{}{
 		"item_id": 5,
@@ -915,7 +988,7 @@ func TestRemoveItemFromPodcast_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.DELETE("/podcasts/:id/items/:itemID", handler.RemoveItemFromPodcast)
 
-	mockPodcastService.On("RemoveItemFromPodcast", mock.Anything, int32(1), int32(5)).Return(errors.New("service error"))
+	mockPodcastService.On("RemoveItemFromPodcast", mock.Anything, testUserID, int32(1), int32(5)).Return(errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/podcasts/1/items/5", nil)
@@ -976,7 +1049,7 @@ func TestUpdatePodcast_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.PUT("/podcasts/:id", handler.UpdatePodcast)
 
-	mockPodcastService.On("UpdatePodcast", mock.Anything, int32(1), "Updated Title", "Updated Description").Return(errors.New("service error"))
+	mockPodcastService.On("UpdatePodcast", mock.Anything, testUserID, int32(1), "Updated Title", "Updated Description").Return(errors.New("service error"))
 
 	reqBody := map[string]interface{}{
 		"title":       "Updated Title",
@@ -1016,7 +1089,7 @@ func TestDeletePodcast_ServiceError(t *testing.T) {
 	router := setupTestRouter()
 	router.DELETE("/podcasts/:id", handler.DeletePodcast)
 
-	mockPodcastService.On("DeletePodcast", mock.Anything, int32(1)).Return(errors.New("service error"))
+	mockPodcastService.On("DeletePodcast", mock.Anything, testUserID, int32(1)).Return(errors.New("service error"))
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/podcasts/1", nil)
@@ -1034,9 +1107,7 @@ func TestCreatePodcastFromSingleItem_ValidationError(t *testing.T) {
 	router := setupTestRouter()
 	router.POST("/podcasts/from-item", handler.CreatePodcastFromSingleItem)
 
-	reqBody := map[string]interface{}{
-		"user_id": 1,
-	}
+	reqBody := map[string]interface{}{}
 	jsonBody, _ := json.Marshal(reqBody)
 
 	w := httptest.NewRecorder()
@@ -1058,7 +1129,6 @@ func TestCreatePodcastFromSingleItem_ServiceError(t *testing.T) {
 	mockPodcastService.On("CreatePodcastFromSingleItem", mock.Anything, int32(1), int32(1)).Return(nil, errors.New("service error"))
 
 	reqBody := map[string]interface{}{
-		"user_id": 1,
 		"item_id": 1,
 	}
 	jsonBody, _ := json.Marshal(reqBody)
@@ -1072,3 +1142,183 @@ func TestCreatePodcastFromSingleItem_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockPodcastService.AssertExpectations(t)
 }
+
+func TestPodcastRoutes_OtherUsersResourcesNotFound(t *testing.T) {
+	// Podcast 2 and item 3 belong to another user
+	otherPodcastID := int32(2)
+	otherItemID := int32(3)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		setup         func(m *MockPodcastService)
+		expectedError string
+	}{
+		{
+			name:   "GET /podcasts/:id",
+			method: http.MethodGet,
+			path:   "/podcasts/2",
+			setup: func(m *MockPodcastService) {
+				m.On("GetPodcast", mock.Anything, testUserID, otherPodcastID).Return(nil, services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "GET /podcasts/:id/status",
+			method: http.MethodGet,
+			path:   "/podcasts/2/status",
+			setup: func(m *MockPodcastService) {
+				m.On("GetPodcast", mock.Anything, testUserID, otherPodcastID).Return(nil, services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "GET /podcasts/:id/items",
+			method: http.MethodGet,
+			path:   "/podcasts/2/items",
+			setup: func(m *MockPodcastService) {
+				m.On("GetPodcastItems", mock.Anything, testUserID, otherPodcastID).Return([]db.GetPodcastItemsRow(nil), services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "POST /podcasts/:id/items",
+			method: http.MethodPost,
+			path:   "/podcasts/2/items",
+			body:   `{"item_id":1,"order":0}`,
+			setup: func(m *MockPodcastService) {
+				m.On("AddItemToPodcast", mock.Anything, testUserID, otherPodcastID, int32(1), 0).Return(services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "POST /podcasts/:id/items with another user's item",
+			method: http.MethodPost,
+			path:   "/podcasts/1/items",
+			body:   `{"item_id":3,"order":0}`,
+			setup: func(m *MockPodcastService) {
+				m.On("AddItemToPodcast", mock.Anything, testUserID, int32(1), otherItemID, 0).Return(services.ErrItemNotFound)
+			},
+			expectedError: "item not found",
+		},
+		{
+			name:   "DELETE /podcasts/:id/items/:itemID",
+			method: http.MethodDelete,
+			path:   "/podcasts/2/items/1",
+			setup: func(m *MockPodcastService) {
+				m.On("RemoveItemFromPodcast", mock.Anything, testUserID, otherPodcastID, int32(1)).Return(services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "GET /podcasts/:id/audio",
+			method: http.MethodGet,
+			path:   "/podcasts/2/audio",
+			setup: func(m *MockPodcastService) {
+				m.On("HasPodcastAudio", mock.Anything, testUserID, otherPodcastID).Return(false, services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "GET /podcasts/:id/upload-url",
+			method: http.MethodGet,
+			path:   "/podcasts/2/upload-url",
+			setup: func(m *MockPodcastService) {
+				m.On("GeneratePodcastUploadURL", mock.Anything, testUserID, otherPodcastID).Return(nil, services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "PUT /podcasts/:id",
+			method: http.MethodPut,
+			path:   "/podcasts/2",
+			body:   `{"title":"Stolen","description":"Stolen"}`,
+			setup: func(m *MockPodcastService) {
+				m.On("UpdatePodcast", mock.Anything, testUserID, otherPodcastID, "Stolen", "Stolen").Return(services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "DELETE /podcasts/:id",
+			method: http.MethodDelete,
+			path:   "/podcasts/2",
+			setup: func(m *MockPodcastService) {
+				m.On("DeletePodcast", mock.Anything, testUserID, otherPodcastID).Return(services.ErrPodcastNotFound)
+			},
+			expectedError: "podcast not found",
+		},
+		{
+			name:   "POST /podcasts with another user's item",
+			method: http.MethodPost,
+			path:   "/podcasts",
+			body:   `{"title":"Stolen","description":"Stolen","item_ids":[3]}`,
+			setup: func(m *MockPodcastService) {
+				m.On("CreatePodcastFromItems", mock.Anything, testUserID, "Stolen", "Stolen", []int32{otherItemID}).Return(nil, services.ErrItemNotFound)
+			},
+			expectedError: "item not found",
+		},
+		{
+			name:   "POST /podcasts/from-item with another user's item",
+			method: http.MethodPost,
+			path:   "/podcasts/from-item",
+			body:   `{"item_id":3}`,
+			setup: func(m *MockPodcastService) {
+				m.On("CreatePodcastFromSingleItem", mock.Anything, testUserID, otherItemID).Return(nil, services.ErrItemNotFound)
+			},
+			expectedError: "item not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPodcastService := new(MockPodcastService)
+			handler := NewPodcastHandler(mockPodcastService)
+
+			router := setupTestRouter()
+			router.POST("/podcasts", handler.CreatePodcast)
+			router.POST("/podcasts/from-item", handler.CreatePodcastFromSingleItem)
+			router.GET("/podcasts/:id", handler.GetPodcast)
+			router.GET("/podcasts/:id/status", handler.GetPodcastProcessingStatus)
+			router.GET("/podcasts/:id/items", handler.GetPodcastItems)
+			router.POST("/podcasts/:id/items", handler.AddItemToPodcast)
+			router.DELETE("/podcasts/:id/items/:itemID", handler.RemoveItemFromPodcast)
+			router.GET("/podcasts/:id/audio", handler.GetPodcastAudio)
+			router.GET("/podcasts/:id/upload-url", handler.GeneratePodcastUploadURL)
+			router.PUT("/podcasts/:id", handler.UpdatePodcast)
+			router.DELETE("/podcasts/:id", handler.DeletePodcast)
+
+			tt.setup(mockPodcastService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedError)
+			mockPodcastService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPodcastsByStatus_ScopedToUser(t *testing.T) {
+	mockPodcastService := new(MockPodcastService)
+	handler := NewPodcastHandler(mockPodcastService)
+
+	router := setupTestRouter()
+	router.GET("/podcasts/status/:status", handler.GetPodcastsByStatus)
+
+	mockPodcastService.On("GetPodcastsByStatus", mock.Anything, testUserID, services.PodcastStatusCompleted).Return([]db.Podcast{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/podcasts/status/completed", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockPodcastService.AssertExpectations(t)
+	mockPodcastService.AssertNotCalled(t, "GetPendingPodcasts", mock.Anything, mock.Anything)
+}
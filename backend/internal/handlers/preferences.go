@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// GetPreferences godoc
+// @Summary      Get the current user's preferences
+// @Description  Retrieve digest, podcast and summary settings. Users who never saved preferences get the defaults.
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  PreferencesResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /users/me/preferences [get]
+func (h *Handler) GetPreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.preferencesService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPreferencesResponse(*prefs))
+}
+
+// UpdatePreferences godoc
+// @Summary      Update the current user's preferences
+// @Description  Change any subset of the digest, podcast and summary settings. Omitted fields keep their current value.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        preferences  body      UpdatePreferencesRequest  true  "Preferences to change"
+// @Success      200          {object}  PreferencesResponse
+// @Failure      400          {object}  ErrorResponse
+// @Failure      401          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /users/me/preferences [patch]
+func (h *Handler) UpdatePreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefs, err := h.preferencesService.UpdatePreferences(c.Request.Context(), userID, services.PreferencesUpdate{
+		Timezone:        req.Timezone,
+		DigestTime:      req.DigestTime,
+		DigestFrequency: req.DigestFrequency,
+		PodcastEnabled:  req.PodcastEnabled,
+		HostVoice:       req.HostVoice,
+		CohostVoice:     req.CohostVoice,
+		SpeechSpeed:     req.SpeechSpeed,
+		SummaryLength:   req.SummaryLength,
+		SummaryLanguage: req.SummaryLanguage,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPreferences) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPreferencesResponse(*prefs))
+}
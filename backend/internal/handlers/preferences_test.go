@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+type MockPreferencesService struct {
+	mock.Mock
+}
+
+func (m *MockPreferencesService) GetPreferences(ctx context.Context, userID int32) (*db.UserPreference, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.UserPreference), args.Error(1)
+}
+
+func (m *MockPreferencesService) UpdatePreferences(ctx context.Context, userID int32, update services.PreferencesUpdate) (*db.UserPreference, error) {
+	args := m.Called(ctx, userID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.UserPreference), args.Error(1)
+}
+
+func (m *MockPreferencesService) MarkDigestSent(ctx context.Context, userID int32, sentAt time.Time) error {
+	args := m.Called(ctx, userID, sentAt)
+	return args.Error(0)
+}
+
+func TestGetPreferences(t *testing.T) {
+	mockPreferencesService := new(MockPreferencesService)
+	handler := NewHandler(nil, nil, nil, nil, nil)
+	handler.SetPreferencesService(mockPreferencesService)
+
+	router := setupTestRouter()
+	router.GET("/users/me/preferences", handler.GetPreferences)
+
+	prefs := services.DefaultUserPreferences(testUserID)
+	mockPreferencesService.On("GetPreferences", mock.Anything, testUserID).Return(&prefs, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/me/preferences", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response PreferencesResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "UTC", response.Timezone)
+	assert.Equal(t, services.DigestFrequencyDaily, response.DigestFrequency)
+	assert.Nil(t, response.PodcastEnabled)
+	mockPreferencesService.AssertExpectations(t)
+}
+
+func TestGetPreferences_Error(t *testing.T) {
+	mockPreferencesService := new(MockPreferencesService)
+	handler := NewHandler(nil, nil, nil, nil, nil)
+	handler.SetPreferencesService(mockPreferencesService)
+
+	router := setupTestRouter()
+	router.GET("/users/me/preferences", handler.GetPreferences)
+
+	mockPreferencesService.On("GetPreferences", mock.Anything, testUserID).Return(nil, errors.New("database error"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/me/preferences", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	timezone := "Europe/Madrid"
+	speed := 1.25
+	disabled := false
+	expectedUpdate := services.PreferencesUpdate{
+		Timezone:       &timezone,
+		PodcastEnabled: &disabled,
+		SpeechSpeed:    &speed,
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "success", err: nil, expectedStatus: http.StatusOK},
+		{name: "invalid preferences", err: fmt.Errorf("%w: unknown timezone", services.ErrInvalidPreferences), expectedStatus: http.StatusBadRequest},
+		{name: "service error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPreferencesService := new(MockPreferencesService)
+			handler := NewHandler(nil, nil, nil, nil, nil)
+			handler.SetPreferencesService(mockPreferencesService)
+
+			router := setupTestRouter()
+			router.PATCH("/users/me/preferences", handler.UpdatePreferences)
+
+			if tt.err != nil {
+				mockPreferencesService.On("UpdatePreferences", mock.Anything, testUserID, expectedUpdate).Return(nil, tt.err)
+			} else {
+				prefs := services.DefaultUserPreferences(testUserID)
+				prefs.Timezone = timezone
+				prefs.PodcastEnabled = &disabled
+				prefs.SpeechSpeed = speed
+				mockPreferencesService.On("UpdatePreferences", mock.Anything, testUserID, expectedUpdate).Return(&prefs, nil)
+			}
+
+			jsonBody, _ := json.Marshal(map[string]interface{}{
+				"timezone":        timezone,
+				"podcast_enabled": false,
+				"speech_speed":    speed,
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", "/users/me/preferences", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.err == nil {
+				var response PreferencesResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, timezone, response.Timezone)
+				assert.False(t, *response.PodcastEnabled)
+			}
+			mockPreferencesService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatePreferences_InvalidJSON(t *testing.T) {
+	handler := NewHandler(nil, nil, nil, nil, nil)
+	handler.SetPreferencesService(new(MockPreferencesService))
+
+	router := setupTestRouter()
+	router.PATCH("/users/me/preferences", handler.UpdatePreferences)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/users/me/preferences", bytes.NewBufferString("{not json"))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
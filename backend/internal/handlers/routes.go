@@ -5,7 +5,20 @@ import (
 	"github.com/yamirghofran/briefbot/internal/services"
 )
 
-func SetupRoutes(router *gin.Engine, userService services.UserService, itemService services.ItemService, digestService services.DigestService, podcastService services.PodcastService, sseManager *services.SSEManager) {
+func SetupRoutes(router *gin.Engine, userService services.UserService, itemService services.ItemService, digestService services.DigestService, podcastService services.PodcastService, authService services.AuthService, preferencesService services.PreferencesService, workspaceService services.WorkspaceService, feedService services.FeedService, semanticService services.SemanticService, tagService services.TagService, collectionService services.CollectionService, oidcService services.OIDCService, oidcPostLoginRedirect string, rateLimits RouteRateLimits, sseManager *services.SSEManager) {
 	handler := NewHandler(userService, itemService, digestService, podcastService, sseManager)
+	handler.SetAuthService(authService)
+	handler.SetPreferencesService(preferencesService)
+	handler.SetWorkspaceService(workspaceService)
+	handler.SetFeedService(feedService)
+	handler.SetTagService(tagService)
+	handler.SetCollectionService(collectionService)
+	if semanticService != nil {
+		handler.SetSemanticService(semanticService)
+	}
+	if oidcService != nil {
+		handler.SetOIDCService(oidcService, oidcPostLoginRedirect)
+	}
+	handler.SetRateLimits(rateLimits)
 	handler.SetupRoutes(router)
 }
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yamirghofran/briefbot/internal/services"
@@ -15,16 +14,15 @@ import (
 // @Description  Server-Sent Events endpoint for real-time item processing updates
 // @Tags         items
 // @Produce      text/event-stream
-// @Param        userID  path      int  true  "User ID"
-// @Success      200     {string}  string "SSE stream"
-// @Failure      400     {object}  ErrorResponse
-// @Failure      500     {object}  ErrorResponse
-// @Router       /items/user/{userID}/stream [get]
+// @Security     BearerAuth
+// @Param        access_token  query     string  false  "Token for clients that cannot set the Authorization header"
+// @Success      200           {string}  string "SSE stream"
+// @Failure      401           {object}  ErrorResponse
+// @Failure      500           {object}  ErrorResponse
+// @Router       /items/stream [get]
 func (h *Handler) StreamItemUpdates(c *gin.Context) {
-	userIDStr := c.Param("userID")
-	userID, err := strconv.ParseInt(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
@@ -32,13 +30,12 @@ func (h *Handler) StreamItemUpdates(c *gin.Context) {
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
-	c.Header("Access-Control-Allow-Origin", "*")
 
 	// Create a channel for writing SSE messages
 	messageChan := make(chan string, 10)
 
 	// Register client with SSE manager
-	client := h.sseManager.AddClient(int32(userID))
+	client := h.sseManager.AddClient(userID)
 	defer h.sseManager.RemoveClient(client)
 
 	// Start goroutine to write messages from the client channel
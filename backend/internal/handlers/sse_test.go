@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// streamLines reads an SSE response body line by line in the background
+func streamLines(body io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReader(body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines <- line
+		}
+	}()
+	return lines
+}
+
+// waitForLine consumes lines until one contains want, returning every line
+// read along the way
+func waitForLine(t *testing.T, lines <-chan string, want string) []string {
+	t.Helper()
+
+	var seen []string
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			require.True(t, ok, "stream closed before %q was received", want)
+			seen = append(seen, line)
+			if strings.Contains(line, want) {
+				return seen
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %q, got %v", want, seen)
+		}
+	}
+}
+
+func TestStreams_OnlyDeliverOwnUpdates(t *testing.T) {
+	otherUserID := testUserID + 1
+
+	tests := []struct {
+		name   string
+		path   string
+		notify func(manager *services.SSEManager, userID int32, id int32)
+	}{
+		{
+			name: "item stream",
+			path: "/items/stream",
+			notify: func(manager *services.SSEManager, userID int32, id int32) {
+				status := services.ProcessingStatusCompleted
+				manager.NotifyItemUpdate(userID, id, &status, "completed")
+			},
+		},
+		{
+			name: "podcast stream",
+			path: "/podcasts/stream",
+			notify: func(manager *services.SSEManager, userID int32, id int32) {
+				manager.NotifyPodcastUpdate(userID, id, string(services.PodcastStatusCompleted), "completed")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sseManager := services.NewSSEManager()
+			handler := NewHandler(nil, nil, nil, nil, sseManager)
+			podcastHandler := NewPodcastHandler(nil)
+			podcastHandler.SetSSEManager(sseManager)
+
+			router := setupTestRouter()
+			router.GET("/items/stream", handler.StreamItemUpdates)
+			router.GET("/podcasts/stream", podcastHandler.StreamPodcastUpdates)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			lines := streamLines(resp.Body)
+			// The connected comment is only written once the client is registered
+			waitForLine(t, lines, ": connected")
+
+			tt.notify(sseManager, otherUserID, 99)
+			tt.notify(sseManager, testUserID, 1)
+
+			for _, line := range waitForLine(t, lines, `_id":1,`) {
+				assert.NotContains(t, line, `_id":99,`)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// TagHandler handles tag management HTTP requests
+type TagHandler struct {
+	tagService services.TagService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService services.TagService) *TagHandler {
+	return &TagHandler{tagService: tagService}
+}
+
+// ListTags godoc
+// @Summary      List tags
+// @Description  List the tags on the authenticated user's items with how many items carry each, most used first, followed by curated tags no item carries yet. Each tag lists the aliases that resolve to it.
+// @Tags         tags
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   TagResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tags, err := h.tagService.ListTags(c.Request.Context(), userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := make([]TagResponse, len(tags))
+	for i, tag := range tags {
+		response[i] = newTagResponse(tag)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// MergeTags godoc
+// @Summary      Merge tags
+// @Description  Replace the source tags by the target tag on every item the authenticated user owns, ignoring case. The sources become aliases of the target, so tags extracted from later items use the target instead. Tag names are normalized to lowercase words joined by hyphens.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        merge  body      MergeTagsRequest  true  "Tags to merge"
+// @Success      200    {object}  TagMergeResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /tags/merge [post]
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.tagService.MergeTags(c.Request.Context(), userID, req.Sources, req.Target)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTagMergeResponse(result))
+}
+
+// RenameTag godoc
+// @Summary      Rename a tag
+// @Description  Rename a tag on every item the authenticated user owns. The old name becomes an alias of the new one. Renaming to a tag that already exists is refused; merge the tags instead.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        rename  body      RenameTagRequest  true  "Tag to rename"
+// @Success      200     {object}  TagMergeResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /tags/rename [post]
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.tagService.RenameTag(c.Request.Context(), userID, req.From, req.To)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTagMergeResponse(result))
+}
+
+// DeleteTagAlias godoc
+// @Summary      Delete a tag alias
+// @Description  Stop replacing an alias by its tag in newly extracted tags. Items already rewritten keep their tags.
+// @Tags         tags
+// @Produce      json
+// @Security     BearerAuth
+// @Param        alias  query     string  true  "Alias to delete"
+// @Success      200    {object}  MessageResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      401    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /tags/aliases [delete]
+func (h *TagHandler) DeleteTagAlias(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	alias := c.Query("alias")
+	if alias == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "alias is required"})
+		return
+	}
+
+	if err := h.tagService.DeleteAlias(c.Request.Context(), userID, alias); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag alias deleted successfully"})
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) ListTags(ctx context.Context, userID int32) ([]services.TagSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.TagSummary), args.Error(1)
+}
+
+func (m *MockTagService) MergeTags(ctx context.Context, userID int32, sources []string, target string) (*services.TagMergeResult, error) {
+	args := m.Called(ctx, userID, sources, target)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TagMergeResult), args.Error(1)
+}
+
+func (m *MockTagService) RenameTag(ctx context.Context, userID int32, from, to string) (*services.TagMergeResult, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TagMergeResult), args.Error(1)
+}
+
+func (m *MockTagService) DeleteAlias(ctx context.Context, userID int32, alias string) error {
+	args := m.Called(ctx, userID, alias)
+	return args.Error(0)
+}
+
+func (m *MockTagService) SetTxRunner(txRunner services.TxRunner) {
+	m.Called(txRunner)
+}
+
+func TestListTags(t *testing.T) {
+	mockTagService := new(MockTagService)
+	handler := NewTagHandler(mockTagService)
+
+	router := setupTestRouter()
+	router.GET("/tags", handler.ListTags)
+
+	mockTagService.On("ListTags", mock.Anything, testUserID).Return([]services.TagSummary{
+		{Name: "ai", ItemCount: 4, Aliases: []string{"artificial-intelligence"}},
+		{Name: "golang", ItemCount: 1},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tags", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []TagResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, TagResponse{Name: "ai", ItemCount: 4, Aliases: []string{"artificial-intelligence"}}, response[0])
+	assert.Equal(t, []string{}, response[1].Aliases)
+	mockTagService.AssertExpectations(t)
+}
+
+func TestMergeTags(t *testing.T) {
+	mockTagService := new(MockTagService)
+	handler := NewTagHandler(mockTagService)
+
+	router := setupTestRouter()
+	router.POST("/tags/merge", handler.MergeTags)
+
+	mockTagService.On("MergeTags", mock.Anything, testUserID, []string{"AI", "Artificial Intelligence"}, "ai").Return(
+		&services.TagMergeResult{Tag: "ai", Aliases: []string{"artificial-intelligence"}, ItemsUpdated: 3}, nil)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"sources": []string{"AI", "Artificial Intelligence"}, "target": "ai"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tags/merge", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response TagMergeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ai", response.Tag)
+	assert.Equal(t, []string{"artificial-intelligence"}, response.Aliases)
+	assert.Equal(t, int64(3), response.ItemsUpdated)
+	mockTagService.AssertExpectations(t)
+}
+
+func TestRenameTag(t *testing.T) {
+	mockTagService := new(MockTagService)
+	handler := NewTagHandler(mockTagService)
+
+	router := setupTestRouter()
+	router.POST("/tags/rename", handler.RenameTag)
+
+	mockTagService.On("RenameTag", mock.Anything, testUserID, "ml", "Machine Learning").Return(
+		&services.TagMergeResult{Tag: "machine-learning", Aliases: []string{"ml"}, ItemsUpdated: 2}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tags/rename", bytes.NewBufferString(`{"from": "ml", "to": "Machine Learning"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response TagMergeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "machine-learning", response.Tag)
+	mockTagService.AssertExpectations(t)
+}
+
+func TestTagRoutes_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setup        func(m *MockTagService)
+		expectedCode int
+	}{
+		{
+			name:         "merge without sources",
+			method:       http.MethodPost,
+			path:         "/tags/merge",
+			body:         `{"sources": [], "target": "ai"}`,
+			setup:        func(m *MockTagService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "merge into a blank tag",
+			method: http.MethodPost,
+			path:   "/tags/merge",
+			body:   `{"sources": ["AI"], "target": " - "}`,
+			setup: func(m *MockTagService) {
+				m.On("MergeTags", mock.Anything, testUserID, []string{"AI"}, " - ").
+					Return(nil, fmt.Errorf("%w: a tag name is required", services.ErrInvalidTag))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "rename an unknown tag",
+			method: http.MethodPost,
+			path:   "/tags/rename",
+			body:   `{"from": "rust", "to": "rustlang"}`,
+			setup: func(m *MockTagService) {
+				m.On("RenameTag", mock.Anything, testUserID, "rust", "rustlang").Return(nil, services.ErrTagNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "rename onto an existing tag",
+			method: http.MethodPost,
+			path:   "/tags/rename",
+			body:   `{"from": "ml", "to": "ai"}`,
+			setup: func(m *MockTagService) {
+				m.On("RenameTag", mock.Anything, testUserID, "ml", "ai").Return(nil, fmt.Errorf("%w: \"ai\"", services.ErrTagExists))
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "delete an unknown alias",
+			method: http.MethodDelete,
+			path:   "/tags/aliases?alias=ml",
+			setup: func(m *MockTagService) {
+				m.On("DeleteAlias", mock.Anything, testUserID, "ml").Return(services.ErrTagAliasNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "delete without an alias",
+			method:       http.MethodDelete,
+			path:         "/tags/aliases",
+			setup:        func(m *MockTagService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTagService := new(MockTagService)
+			tt.setup(mockTagService)
+			handler := NewTagHandler(mockTagService)
+
+			router := setupTestRouter()
+			router.POST("/tags/merge", handler.MergeTags)
+			router.POST("/tags/rename", handler.RenameTag)
+			router.DELETE("/tags/aliases", handler.DeleteTagAlias)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockTagService.AssertExpectations(t)
+		})
+	}
+}
@@ -1,170 +1,201 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/services"
 )
 
-// CreateUser godoc
-// @Summary      Create a new user
-// @Description  Create a new user account with optional OAuth or password authentication
-// @Tags         users
-// @Accept       json
-// @Produce      json
-// @Param        user  body      CreateUserRequest  true  "User creation request"
-// @Success      201   {object}  github_com_yamirghofran_briefbot_internal_db.User
-// @Failure      400   {object}  ErrorResponse
-// @Failure      500   {object}  ErrorResponse
-// @Router       /users [post]
-func (h *Handler) CreateUser(c *gin.Context) {
-	var req CreateUserRequest
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	user, err := h.userService.CreateUser(c.Request.Context(), req.Name, req.Email, req.AuthProvider, req.OauthID, req.PasswordHash)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, user)
-}
-
 // GetUser godoc
 // @Summary      Get a user by ID
-// @Description  Retrieve a user's information by their ID
+// @Description  Retrieve a user's information by their ID. Users can only retrieve their own account.
 // @Tags         users
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "User ID"
-// @Success      201   {object}  github_com_yamirghofran_briefbot_internal_db.User
+// @Success      200  {object}  UserResponse
 // @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /users/{id} [get]
 func (h *Handler) GetUser(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if int32(id) != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own account"})
+		return
+	}
 
-	user, err := h.userService.GetUser(c.Request.Context(), int32(id))
+	user, err := h.userService.GetUser(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, newUserResponse(*user))
 }
 
-// GetUserByEmail godoc
-// @Summary      Get a user by email
-// @Description  Retrieve a user's information by their email address
+// UpdateUser godoc
+// @Summary      Update the current user
+// @Description  Update the authenticated user's name and email. Changing the email marks it unverified until a password reset is completed through it.
 // @Tags         users
+// @Accept       json
 // @Produce      json
-// @Param        email  path      string  true  "User Email"
-// @Success      201   {object}  github_com_yamirghofran_briefbot_internal_db.User
-// @Failure      400    {object}  ErrorResponse
-// @Failure      500    {object}  ErrorResponse
-// @Router       /users/email/{email} [get]
-func (h *Handler) GetUserByEmail(c *gin.Context) {
-	email := c.Param("email")
-	if email == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+// @Security     BearerAuth
+// @Param        user  body      UpdateUserRequest   true  "User update request"
+// @Success      200   {object}  MessageResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      401   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /users/me [put]
+func (h *Handler) UpdateUser(c *gin.Context) {
+	id, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	user, err := h.userService.GetUserByEmail(c.Request.Context(), &email)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var req UpdateUserRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
-}
-
-// ListUsers godoc
-// @Summary      List all users
-// @Description  Retrieve a list of all users in the system
-// @Tags         users
-// @Produce      json
-// @Success      200  {array}   github_com_yamirghofran_briefbot_internal_db.User
-// @Failure      500  {object}  ErrorResponse
-// @Router       /users [get]
-func (h *Handler) ListUsers(c *gin.Context) {
-	users, err := h.userService.ListUsers(c.Request.Context())
+	err := h.userService.UpdateUser(c.Request.Context(), id, req.Name, req.Email)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
 }
 
-// UpdateUser godoc
-// @Summary      Update a user
-// @Description  Update a user's information
+// ChangePassword godoc
+// @Summary      Set or change the current user's password
+// @Description  Hash and store a new password. The current password is required when one is already set; accounts created through single sign-on can set their first password without it. Signs out every session, including the current one; personal API tokens keep working.
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Param        id    path      int                 true  "User ID"
-// @Param        user  body      UpdateUserRequest   true  "User update request"
-// @Success      200   {object}  MessageResponse
-// @Failure      400   {object}  ErrorResponse
-// @Failure      500   {object}  ErrorResponse
-// @Router       /users/{id} [put]
-func (h *Handler) UpdateUser(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+// @Security     BearerAuth
+// @Param        password  body      ChangePasswordRequest  true  "Password change request"
+// @Success      200       {object}  MessageResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      401       {object}  ErrorResponse
+// @Failure      403       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /users/me/password [put]
+func (h *Handler) ChangePassword(c *gin.Context) {
+	id, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	var req UpdateUserRequest
+	var req ChangePasswordRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	err = h.userService.UpdateUser(c.Request.Context(), int32(id), req.Name, req.Email, req.AuthProvider, req.OauthID, req.PasswordHash)
+	err := h.authService.ChangePassword(c.Request.Context(), id, req.CurrentPassword, req.NewPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, services.ErrPasswordTooShort):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrIncorrectPassword):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User updated successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
 }
 
 // DeleteUser godoc
-// @Summary      Delete a user
-// @Description  Delete a user from the system
+// @Summary      Delete the current user
+// @Description  Delete the authenticated user's account along with their items, podcasts and stored podcast audio
 // @Tags         users
 // @Produce      json
-// @Param        id   path      int  true  "User ID"
+// @Security     BearerAuth
 // @Success      200  {object}  MessageResponse
-// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
-// @Router       /users/{id} [delete]
+// @Router       /users/me [delete]
 func (h *Handler) DeleteUser(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 32)
+	id, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.userService.DeleteUser(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+// ExportUserData godoc
+// @Summary      Export a user's data
+// @Description  Download a zip of JSON files with the user's profile, preferences, items (including summaries and read state) and podcasts (including scripts and audio links). Users can only export their own data.
+// @Tags         users
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /users/{id}/export [get]
+func (h *Handler) ExportUserData(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if int32(id) != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only export your own data"})
+		return
+	}
 
-	err = h.userService.DeleteUser(c.Request.Context(), int32(id))
+	export, err := h.userService.ExportUserData(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+	// Build the archive before writing headers so failures still return JSON
+	var buf bytes.Buffer
+	if err := export.WriteZip(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("briefbot-export-%d-%s.zip", userID, export.ExportedAt.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
 }
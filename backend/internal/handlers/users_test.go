@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,25 +9,20 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/middleware"
+	"github.com/yamirghofran/briefbot/internal/services"
 )
 
 type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) CreateUser(ctx context.Context, name, email, authProvider, oauthID, passwordHash *string) (*db.User, error) {
-	args := m.Called(ctx, name, email, authProvider, oauthID, passwordHash)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*db.User), args.Error(1)
-}
-
 func (m *MockUserService) GetUser(ctx context.Context, id int32) (*db.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -48,8 +44,8 @@ func (m *MockUserService) ListUsers(ctx context.Context) ([]db.User, error) {
 	return args.Get(0).([]db.User), args.Error(1)
 }
 
-func (m *MockUserService) UpdateUser(ctx context.Context, id int32, name, email, authProvider, oauthID, passwordHash *string) error {
-	args := m.Called(ctx, id, name, email, authProvider, oauthID, passwordHash)
+func (m *MockUserService) UpdateUser(ctx context.Context, id int32, name, email *string) error {
+	args := m.Called(ctx, id, name, email)
 	return args.Error(0)
 }
 
@@ -58,68 +54,39 @@ func (m *MockUserService) DeleteUser(ctx context.Context, id int32) error {
 	return args.Error(0)
 }
 
-func setupTestRouter() *gin.Engine {
-	gin.SetMode(gin.TestMode)
-	return gin.New()
-}
-
-func TestCreateUser(t *testing.T) {
-	mockUserService := new(MockUserService)
-	handler := NewHandler(mockUserService, nil, nil, nil, nil)
-
-	router := setupTestRouter()
-	router.POST("/users", handler.CreateUser)
-
-	name := "John Doe"
-	email := "john@example.com"
-
-	expectedUser := &db.User{
-		ID:    1,
-		Name:  &name,
-		Email: &email,
-	}
-
-	mockUserService.On("CreateUser", mock.Anything, &name, &email, mock.Anything, mock.Anything, mock.Anything).Return(expectedUser, nil)
-
-	reqBody := map[string]interface{}{
-		"name":  name,
-		"email": email,
+func (m *MockUserService) ExportUserData(ctx context.Context, id int32) (*services.UserExport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
-	jsonBody, _ := json.Marshal(reqBody)
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusCreated, w.Code)
-	mockUserService.AssertExpectations(t)
+	return args.Get(0).(*services.UserExport), args.Error(1)
 }
 
-func TestCreateUser_Error(t *testing.T) {
-	mockUserService := new(MockUserService)
-	handler := NewHandler(mockUserService, nil, nil, nil, nil)
-
-	router := setupTestRouter()
-	router.POST("/users", handler.CreateUser)
-
-	mockUserService.On("CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+func (m *MockUserService) SetFileStore(fileStore services.FileStore) {
+	m.Called(fileStore)
+}
 
-	reqBody := map[string]interface{}{
-		"name":  "John",
-		"email": "john@example.com",
-	}
-	jsonBody, _ := json.Marshal(reqBody)
+func (m *MockUserService) SetTxRunner(txRunner services.TxRunner) {
+	m.Called(txRunner)
+}
 
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
+// testUserID is the authenticated user injected by setupTestRouter
+const testUserID int32 = 1
 
-	router.ServeHTTP(w, req)
+// setupTestRouter returns a router whose requests are authenticated as testUserID
+func setupTestRouter() *gin.Engine {
+	router := setupAnonymousRouter()
+	router.Use(func(c *gin.Context) {
+		middleware.SetAuthUser(c, &db.User{ID: testUserID})
+		c.Next()
+	})
+	return router
+}
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	mockUserService.AssertExpectations(t)
+// setupAnonymousRouter returns a router without an authenticated user
+func setupAnonymousRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
 }
 
 func TestGetUser(t *testing.T) {
@@ -161,53 +128,20 @@ func TestGetUser_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
-func TestGetUserByEmail(t *testing.T) {
+func TestGetUser_OtherUserForbidden(t *testing.T) {
 	mockUserService := new(MockUserService)
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.GET("/users/email/:email", handler.GetUserByEmail)
-
-	email := "john@example.com"
-	expectedUser := &db.User{
-		ID:    1,
-		Email: &email,
-	}
-
-	mockUserService.On("GetUserByEmail", mock.Anything, &email).Return(expectedUser, nil)
-
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/users/email/john@example.com", nil)
-
-	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-	mockUserService.AssertExpectations(t)
-}
-
-func TestListUsers(t *testing.T) {
-	mockUserService := new(MockUserService)
-	handler := NewHandler(mockUserService, nil, nil, nil, nil)
-
-	router := setupTestRouter()
-	router.GET("/users", handler.ListUsers)
-
-	name1 := "User 1"
-	name2 := "User 2"
-	expectedUsers := []db.User{
-		{ID: 1, Name: &name1},
-		{ID: 2, Name: &name2},
-	}
-
-	mockUserService.On("ListUsers", mock.Anything).Return(expectedUsers, nil)
+	router.GET("/users/:id", handler.GetUser)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/users", nil)
+	req, _ := http.NewRequest("GET", "/users/2", nil)
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
-	mockUserService.AssertExpectations(t)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockUserService.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
 }
 
 func TestUpdateUser(t *testing.T) {
@@ -215,11 +149,11 @@ func TestUpdateUser(t *testing.T) {
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.PUT("/users/:id", handler.UpdateUser)
+	router.PUT("/users/me", handler.UpdateUser)
 
 	name := "Updated Name"
 
-	mockUserService.On("UpdateUser", mock.Anything, int32(1), &name, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockUserService.On("UpdateUser", mock.Anything, int32(1), &name, mock.Anything).Return(nil)
 
 	reqBody := map[string]interface{}{
 		"name": name,
@@ -227,7 +161,7 @@ func TestUpdateUser(t *testing.T) {
 	jsonBody, _ := json.Marshal(reqBody)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("PUT", "/users/1", bytes.NewBuffer(jsonBody))
+	req, _ := http.NewRequest("PUT", "/users/me", bytes.NewBuffer(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
 
 	router.ServeHTTP(w, req)
@@ -241,12 +175,12 @@ func TestDeleteUser(t *testing.T) {
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.DELETE("/users/:id", handler.DeleteUser)
+	router.DELETE("/users/me", handler.DeleteUser)
 
 	mockUserService.On("DeleteUser", mock.Anything, int32(1)).Return(nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("DELETE", "/users/1", nil)
+	req, _ := http.NewRequest("DELETE", "/users/me", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -272,18 +206,46 @@ func TestGetUser_ServiceError(t *testing.T) {
 	mockUserService.AssertExpectations(t)
 }
 
-func TestGetUserByEmail_ServiceError(t *testing.T) {
+func TestUpdateUser_Unauthenticated(t *testing.T) {
+	mockUserService := new(MockUserService)
+	handler := NewHandler(mockUserService, nil, nil, nil, nil)
+
+	router := setupAnonymousRouter()
+	router.PUT("/users/me", handler.UpdateUser)
+
+	name := "Updated Name"
+	reqBody := map[string]interface{}{
+		"name": name,
+	}
+	jsonBody, _ := json.Marshal(reqBody)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/users/me", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUpdateUser_ServiceError(t *testing.T) {
 	mockUserService := new(MockUserService)
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.GET("/users/email/:email", handler.GetUserByEmail)
+	router.PUT("/users/me", handler.UpdateUser)
 
-	email := "john@example.com"
-	mockUserService.On("GetUserByEmail", mock.Anything, &email).Return(nil, errors.New("service error"))
+	name := "Updated Name"
+	mockUserService.On("UpdateUser", mock.Anything, int32(1), &name, mock.Anything).Return(errors.New("service error"))
+
+	reqBody := map[string]interface{}{
+		"name": name,
+	}
+	jsonBody, _ := json.Marshal(reqBody)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/users/email/john@example.com", nil)
+	req, _ := http.NewRequest("PUT", "/users/me", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
 
 	router.ServeHTTP(w, req)
 
@@ -291,17 +253,32 @@ func TestGetUserByEmail_ServiceError(t *testing.T) {
 	mockUserService.AssertExpectations(t)
 }
 
-func TestListUsers_ServiceError(t *testing.T) {
+func TestDeleteUser_Unauthenticated(t *testing.T) {
+	mockUserService := new(MockUserService)
+	handler := NewHandler(mockUserService, nil, nil, nil, nil)
+
+	router := setupAnonymousRouter()
+	router.DELETE("/users/me", handler.DeleteUser)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/users/me", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestDeleteUser_ServiceError(t *testing.T) {
 	mockUserService := new(MockUserService)
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.GET("/users", handler.ListUsers)
+	router.DELETE("/users/me", handler.DeleteUser)
 
-	mockUserService.On("ListUsers", mock.Anything).Return([]db.User{}, errors.New("service error"))
+	mockUserService.On("DeleteUser", mock.Anything, int32(1)).Return(errors.New("service error"))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/users", nil)
+	req, _ := http.NewRequest("DELETE", "/users/me", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -309,79 +286,130 @@ func TestListUsers_ServiceError(t *testing.T) {
 	mockUserService.AssertExpectations(t)
 }
 
-func TestUpdateUser_InvalidID(t *testing.T) {
+func TestUserResponses_OmitCredentials(t *testing.T) {
 	mockUserService := new(MockUserService)
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.PUT("/users/:id", handler.UpdateUser)
+	router.GET("/users/:id", handler.GetUser)
 
-	name := "Updated Name"
-	reqBody := map[string]interface{}{
-		"name": name,
+	email := "john@example.com"
+	passwordHash := "$2a$10$secret"
+	oauthID := "subject-123"
+	user := db.User{ID: 1, Email: &email, PasswordHash: &passwordHash, OauthID: &oauthID}
+
+	mockUserService.On("GetUser", mock.Anything, int32(1)).Return(&user, nil)
+
+	for _, path := range []string{"/users/1"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.NotContains(t, w.Body.String(), "password_hash", path)
+		assert.NotContains(t, w.Body.String(), passwordHash, path)
+		assert.NotContains(t, w.Body.String(), oauthID, path)
+		assert.Contains(t, w.Body.String(), `"has_password":true`, path)
 	}
-	jsonBody, _ := json.Marshal(reqBody)
+}
 
-	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("PUT", "/users/invalid", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "success", err: nil, expectedStatus: http.StatusOK},
+		{name: "incorrect current password", err: services.ErrIncorrectPassword, expectedStatus: http.StatusForbidden},
+		{name: "short password", err: services.ErrPasswordTooShort, expectedStatus: http.StatusBadRequest},
+		{name: "service error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
 
-	router.ServeHTTP(w, req)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthService := new(MockAuthService)
+			handler := NewHandler(nil, nil, nil, nil, nil)
+			handler.SetAuthService(mockAuthService)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+			router := setupTestRouter()
+			router.PUT("/users/me/password", handler.ChangePassword)
+
+			mockAuthService.On("ChangePassword", mock.Anything, testUserID, "old-password", "new-password").Return(tt.err)
+
+			jsonBody, _ := json.Marshal(map[string]interface{}{
+				"current_password": "old-password",
+				"new_password":     "new-password",
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/users/me/password", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
 }
 
-func TestUpdateUser_ServiceError(t *testing.T) {
+func TestExportUserData(t *testing.T) {
 	mockUserService := new(MockUserService)
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.PUT("/users/:id", handler.UpdateUser)
-
-	name := "Updated Name"
-	mockUserService.On("UpdateUser", mock.Anything, int32(1), &name, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("service error"))
+	router.GET("/users/:id/export", handler.ExportUserData)
 
-	reqBody := map[string]interface{}{
-		"name": name,
+	export := &services.UserExport{
+		ExportedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		User:       services.ExportedUser{ID: testUserID},
+		Items:      []services.ExportedItem{},
+		Podcasts:   []services.ExportedPodcast{},
 	}
-	jsonBody, _ := json.Marshal(reqBody)
+	mockUserService.On("ExportUserData", mock.Anything, testUserID).Return(export, nil)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("PUT", "/users/1", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
+	req, _ := http.NewRequest("GET", "/users/1/export", nil)
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "briefbot-export-1-20260102.zip")
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, archive.File)
 	mockUserService.AssertExpectations(t)
 }
 
-func TestDeleteUser_InvalidID(t *testing.T) {
+func TestExportUserData_OtherUser(t *testing.T) {
 	mockUserService := new(MockUserService)
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.DELETE("/users/:id", handler.DeleteUser)
+	router.GET("/users/:id/export", handler.ExportUserData)
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("DELETE", "/users/invalid", nil)
+	req, _ := http.NewRequest("GET", "/users/2/export", nil)
 
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockUserService.AssertNotCalled(t, "ExportUserData", mock.Anything, mock.Anything)
 }
 
-func TestDeleteUser_ServiceError(t *testing.T) {
+func TestExportUserData_ServiceError(t *testing.T) {
 	mockUserService := new(MockUserService)
 	handler := NewHandler(mockUserService, nil, nil, nil, nil)
 
 	router := setupTestRouter()
-	router.DELETE("/users/:id", handler.DeleteUser)
+	router.GET("/users/:id/export", handler.ExportUserData)
 
-	mockUserService.On("DeleteUser", mock.Anything, int32(1)).Return(errors.New("service error"))
+	mockUserService.On("ExportUserData", mock.Anything, testUserID).Return(nil, errors.New("database error"))
 
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("DELETE", "/users/1", nil)
+	req, _ := http.NewRequest("GET", "/users/1/export", nil)
 
 	router.ServeHTTP(w, req)
 
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// WorkspaceHandler handles workspace and membership HTTP requests
+type WorkspaceHandler struct {
+	workspaceService services.WorkspaceService
+}
+
+// NewWorkspaceHandler creates a new workspace handler
+func NewWorkspaceHandler(workspaceService services.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+// CreateWorkspace godoc
+// @Summary      Create a workspace
+// @Description  Create a workspace with the authenticated user as its owner
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workspace  body      WorkspaceRequest  true  "Workspace creation request"
+// @Success      201        {object}  WorkspaceResponse
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newWorkspaceResponse(*workspace, services.WorkspaceRoleOwner))
+}
+
+// ListWorkspaces godoc
+// @Summary      List workspaces
+// @Description  List the workspaces the authenticated user belongs to
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   WorkspaceResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /workspaces [get]
+func (h *WorkspaceHandler) ListWorkspaces(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaces, err := h.workspaceService.ListWorkspaces(c.Request.Context(), userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := make([]WorkspaceResponse, len(workspaces))
+	for i, row := range workspaces {
+		response[i] = WorkspaceResponse{
+			ID:        row.ID,
+			Name:      row.Name,
+			Role:      row.Role,
+			CreatedBy: row.CreatedBy,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWorkspace godoc
+// @Summary      Get a workspace
+// @Description  Retrieve a workspace the authenticated user belongs to
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Workspace ID"
+// @Success      200  {object}  WorkspaceResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	workspace, role, err := h.workspaceService.GetWorkspace(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newWorkspaceResponse(*workspace, role))
+}
+
+// RenameWorkspace godoc
+// @Summary      Rename a workspace
+// @Description  Rename a workspace; requires the admin or owner role
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int               true  "Workspace ID"
+// @Param        workspace  body      WorkspaceRequest  true  "New workspace name"
+// @Success      200        {object}  WorkspaceResponse
+// @Failure      400        {object}  ErrorResponse
+// @Failure      401        {object}  ErrorResponse
+// @Failure      403        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /workspaces/{id} [patch]
+func (h *WorkspaceHandler) RenameWorkspace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workspace, role, err := h.workspaceService.RenameWorkspace(c.Request.Context(), userID, workspaceID, req.Name)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newWorkspaceResponse(*workspace, role))
+}
+
+// DeleteWorkspace godoc
+// @Summary      Delete a workspace
+// @Description  Delete a workspace; requires the owner role. Shared items return to their owners' personal lists.
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Workspace ID"
+// @Success      200  {object}  MessageResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /workspaces/{id} [delete]
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceService.DeleteWorkspace(c.Request.Context(), userID, workspaceID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Workspace deleted successfully"})
+}
+
+// ListMembers godoc
+// @Summary      List workspace members
+// @Description  List the members of a workspace the authenticated user belongs to
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Workspace ID"
+// @Success      200  {array}   WorkspaceMemberResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /workspaces/{id}/members [get]
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaceService.ListMembers(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	response := make([]WorkspaceMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = newWorkspaceMemberResponse(member)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// AddMember godoc
+// @Summary      Add a workspace member
+// @Description  Add an existing user to a workspace by email; requires the admin or owner role
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int                        true  "Workspace ID"
+// @Param        member  body      AddWorkspaceMemberRequest  true  "Member to add"
+// @Success      201     {object}  MessageResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      403     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /workspaces/{id}/members [post]
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req AddWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.workspaceService.AddMember(c.Request.Context(), userID, workspaceID, req.Email, req.Role); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Member added successfully"})
+}
+
+// UpdateMemberRole godoc
+// @Summary      Change a member's role
+// @Description  Change a workspace member's role; requires the admin role, or the owner role to grant or revoke ownership
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int                           true  "Workspace ID"
+// @Param        userID  path      int                           true  "Member user ID"
+// @Param        member  body      UpdateWorkspaceMemberRequest  true  "New role"
+// @Success      200     {object}  MessageResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      403     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /workspaces/{id}/members/{userID} [patch]
+func (h *WorkspaceHandler) UpdateMemberRole(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("userID"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req UpdateWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.workspaceService.UpdateMemberRole(c.Request.Context(), userID, workspaceID, int32(memberID), req.Role); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated successfully"})
+}
+
+// RemoveMember godoc
+// @Summary      Remove a workspace member
+// @Description  Remove a member from a workspace. Members may remove themselves; removing others requires the admin role.
+// @Tags         workspaces
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true  "Workspace ID"
+// @Param        userID  path      int  true  "Member user ID"
+// @Success      200     {object}  MessageResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      401     {object}  ErrorResponse
+// @Failure      403     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /workspaces/{id}/members/{userID} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := workspaceIDParam(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("userID"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(c.Request.Context(), userID, workspaceID, int32(memberID)); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// workspaceIDParam parses the :id path parameter
+func workspaceIDParam(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return 0, false
+	}
+	return int32(id), true
+}
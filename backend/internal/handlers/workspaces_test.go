@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+type MockWorkspaceService struct {
+	mock.Mock
+}
+
+func (m *MockWorkspaceService) CreateWorkspace(ctx context.Context, userID int32, name string) (*db.Workspace, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Workspace), args.Error(1)
+}
+
+func (m *MockWorkspaceService) ListWorkspaces(ctx context.Context, userID int32) ([]db.ListWorkspacesByUserRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ListWorkspacesByUserRow), args.Error(1)
+}
+
+func (m *MockWorkspaceService) GetWorkspace(ctx context.Context, userID int32, workspaceID int32) (*db.Workspace, string, error) {
+	args := m.Called(ctx, userID, workspaceID)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*db.Workspace), args.String(1), args.Error(2)
+}
+
+func (m *MockWorkspaceService) RenameWorkspace(ctx context.Context, userID int32, workspaceID int32, name string) (*db.Workspace, string, error) {
+	args := m.Called(ctx, userID, workspaceID, name)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*db.Workspace), args.String(1), args.Error(2)
+}
+
+func (m *MockWorkspaceService) DeleteWorkspace(ctx context.Context, userID int32, workspaceID int32) error {
+	args := m.Called(ctx, userID, workspaceID)
+	return args.Error(0)
+}
+
+func (m *MockWorkspaceService) ListMembers(ctx context.Context, userID int32, workspaceID int32) ([]db.ListWorkspaceMembersRow, error) {
+	args := m.Called(ctx, userID, workspaceID)
+	return args.Get(0).([]db.ListWorkspaceMembersRow), args.Error(1)
+}
+
+func (m *MockWorkspaceService) AddMember(ctx context.Context, userID int32, workspaceID int32, email string, role string) (*db.WorkspaceMember, error) {
+	args := m.Called(ctx, userID, workspaceID, email, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockWorkspaceService) UpdateMemberRole(ctx context.Context, userID int32, workspaceID int32, memberID int32, role string) (*db.WorkspaceMember, error) {
+	args := m.Called(ctx, userID, workspaceID, memberID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockWorkspaceService) RemoveMember(ctx context.Context, userID int32, workspaceID int32, memberID int32) error {
+	args := m.Called(ctx, userID, workspaceID, memberID)
+	return args.Error(0)
+}
+
+func TestCreateWorkspace(t *testing.T) {
+	mockWorkspaceService := new(MockWorkspaceService)
+	handler := NewWorkspaceHandler(mockWorkspaceService)
+
+	router := setupTestRouter()
+	router.POST("/workspaces", handler.CreateWorkspace)
+
+	userID := testUserID
+	mockWorkspaceService.On("CreateWorkspace", mock.Anything, testUserID, "Research").Return(&db.Workspace{ID: 3, Name: "Research", CreatedBy: &userID}, nil)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"name": "Research"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/workspaces", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response WorkspaceResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), response.ID)
+	assert.Equal(t, services.WorkspaceRoleOwner, response.Role)
+	mockWorkspaceService.AssertExpectations(t)
+}
+
+func TestCreateWorkspace_MissingName(t *testing.T) {
+	mockWorkspaceService := new(MockWorkspaceService)
+	handler := NewWorkspaceHandler(mockWorkspaceService)
+
+	router := setupTestRouter()
+	router.POST("/workspaces", handler.CreateWorkspace)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/workspaces", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockWorkspaceService.AssertNotCalled(t, "CreateWorkspace", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListWorkspaces(t *testing.T) {
+	mockWorkspaceService := new(MockWorkspaceService)
+	handler := NewWorkspaceHandler(mockWorkspaceService)
+
+	router := setupTestRouter()
+	router.GET("/workspaces", handler.ListWorkspaces)
+
+	mockWorkspaceService.On("ListWorkspaces", mock.Anything, testUserID).Return([]db.ListWorkspacesByUserRow{
+		{ID: 3, Name: "Research", Role: services.WorkspaceRoleMember},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/workspaces", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []WorkspaceResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response, 1)
+	assert.Equal(t, services.WorkspaceRoleMember, response[0].Role)
+	mockWorkspaceService.AssertExpectations(t)
+}
+
+func TestGetWorkspace_InvalidID(t *testing.T) {
+	mockWorkspaceService := new(MockWorkspaceService)
+	handler := NewWorkspaceHandler(mockWorkspaceService)
+
+	router := setupTestRouter()
+	router.GET("/workspaces/:id", handler.GetWorkspace)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/workspaces/invalid", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAddWorkspaceMember(t *testing.T) {
+	mockWorkspaceService := new(MockWorkspaceService)
+	handler := NewWorkspaceHandler(mockWorkspaceService)
+
+	router := setupTestRouter()
+	router.POST("/workspaces/:id/members", handler.AddMember)
+
+	mockWorkspaceService.On("AddMember", mock.Anything, testUserID, int32(3), "jane@example.com", services.WorkspaceRoleViewer).
+		Return(&db.WorkspaceMember{WorkspaceID: 3, UserID: 8, Role: services.WorkspaceRoleViewer}, nil)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"email": "jane@example.com", "role": "viewer"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/workspaces/3/members", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockWorkspaceService.AssertExpectations(t)
+}
+
+func TestWorkspaceRoutes_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setup        func(m *MockWorkspaceService)
+		expectedCode int
+	}{
+		{
+			name:   "non-member sees not found",
+			method: http.MethodGet,
+			path:   "/workspaces/3",
+			setup: func(m *MockWorkspaceService) {
+				m.On("GetWorkspace", mock.Anything, testUserID, int32(3)).Return(nil, "", services.ErrWorkspaceNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "member cannot rename",
+			method: http.MethodPatch,
+			path:   "/workspaces/3",
+			body:   `{"name":"Renamed"}`,
+			setup: func(m *MockWorkspaceService) {
+				m.On("RenameWorkspace", mock.Anything, testUserID, int32(3), "Renamed").Return(nil, "", services.ErrWorkspaceForbidden)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:   "unknown email",
+			method: http.MethodPost,
+			path:   "/workspaces/3/members",
+			body:   `{"email":"nobody@example.com"}`,
+			setup: func(m *MockWorkspaceService) {
+				m.On("AddMember", mock.Anything, testUserID, int32(3), "nobody@example.com", "").Return(nil, services.ErrInvalidWorkspace)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "existing member",
+			method: http.MethodPost,
+			path:   "/workspaces/3/members",
+			body:   `{"email":"jane@example.com"}`,
+			setup: func(m *MockWorkspaceService) {
+				m.On("AddMember", mock.Anything, testUserID, int32(3), "jane@example.com", "").Return(nil, services.ErrWorkspaceMemberExists)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "last owner cannot leave",
+			method: http.MethodDelete,
+			path:   "/workspaces/3/members/1",
+			setup: func(m *MockWorkspaceService) {
+				m.On("RemoveMember", mock.Anything, testUserID, int32(3), testUserID).Return(services.ErrLastWorkspaceOwner)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "missing member",
+			method: http.MethodPatch,
+			path:   "/workspaces/3/members/9",
+			body:   `{"role":"admin"}`,
+			setup: func(m *MockWorkspaceService) {
+				m.On("UpdateMemberRole", mock.Anything, testUserID, int32(3), int32(9), "admin").Return(nil, services.ErrWorkspaceMemberMissing)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkspaceService := new(MockWorkspaceService)
+			handler := NewWorkspaceHandler(mockWorkspaceService)
+
+			router := setupTestRouter()
+			router.GET("/workspaces/:id", handler.GetWorkspace)
+			router.PATCH("/workspaces/:id", handler.RenameWorkspace)
+			router.POST("/workspaces/:id/members", handler.AddMember)
+			router.PATCH("/workspaces/:id/members/:userID", handler.UpdateMemberRole)
+			router.DELETE("/workspaces/:id/members/:userID", handler.RemoveMember)
+
+			tt.setup(mockWorkspaceService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockWorkspaceService.AssertExpectations(t)
+		})
+	}
+}
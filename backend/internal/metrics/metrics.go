@@ -237,6 +237,31 @@ var (
 		[]string{"error_type"},
 	)
 
+	// Rate Limiting Metrics
+	rateLimitRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "briefbot_rate_limit_requests_total",
+			Help: "Total number of requests checked by a rate limiter",
+		},
+		[]string{"limiter", "result"},
+	)
+
+	quotaUsageTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "briefbot_quota_usage_total",
+			Help: "Total usage counted against daily quotas (items, podcast minutes)",
+		},
+		[]string{"quota"},
+	)
+
+	quotaExceededTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "briefbot_quota_exceeded_total",
+			Help: "Total number of requests rejected by a daily quota",
+		},
+		[]string{"quota"},
+	)
+
 	// SSE Metrics
 	sseConnectionsActive = promauto.NewGauge(
 		prometheus.GaugeOpts{
@@ -380,6 +405,23 @@ func IncrementScrapingFailures(errorType string) {
 	scrapingFailuresTotal.WithLabelValues(errorType).Inc()
 }
 
+// Rate Limiting Metrics helpers
+func RecordRateLimitDecision(limiter string, allowed bool) {
+	result := "allowed"
+	if !allowed {
+		result = "limited"
+	}
+	rateLimitRequestsTotal.WithLabelValues(limiter, result).Inc()
+}
+
+func AddQuotaUsage(quota string, amount float64) {
+	quotaUsageTotal.WithLabelValues(quota).Add(amount)
+}
+
+func IncrementQuotaExceeded(quota string) {
+	quotaExceededTotal.WithLabelValues(quota).Inc()
+}
+
 // SSE Metrics helpers
 func IncrementSSEConnections() {
 	sseConnectionsActive.Inc()
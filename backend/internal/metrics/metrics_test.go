@@ -344,6 +344,43 @@ func TestIncrementScrapingFailures(t *testing.T) {
 	}
 }
 
+// Rate Limiting Metrics Tests
+
+func TestRecordRateLimitDecision(t *testing.T) {
+	allowedBefore := testutil.ToFloat64(rateLimitRequestsTotal.WithLabelValues("api", "allowed"))
+	limitedBefore := testutil.ToFloat64(rateLimitRequestsTotal.WithLabelValues("api", "limited"))
+	RecordRateLimitDecision("api", true)
+	RecordRateLimitDecision("api", false)
+	RecordRateLimitDecision("api", false)
+
+	if after := testutil.ToFloat64(rateLimitRequestsTotal.WithLabelValues("api", "allowed")); after != allowedBefore+1 {
+		t.Errorf("Expected allowed counter to increment by 1, got %f", after-allowedBefore)
+	}
+	if after := testutil.ToFloat64(rateLimitRequestsTotal.WithLabelValues("api", "limited")); after != limitedBefore+2 {
+		t.Errorf("Expected limited counter to increment by 2, got %f", after-limitedBefore)
+	}
+}
+
+func TestAddQuotaUsage(t *testing.T) {
+	before := testutil.ToFloat64(quotaUsageTotal.WithLabelValues("podcast_minutes"))
+	AddQuotaUsage("podcast_minutes", 90)
+	after := testutil.ToFloat64(quotaUsageTotal.WithLabelValues("podcast_minutes"))
+
+	if after != before+90 {
+		t.Errorf("Expected counter to increase by 90, got %f", after-before)
+	}
+}
+
+func TestIncrementQuotaExceeded(t *testing.T) {
+	before := testutil.ToFloat64(quotaExceededTotal.WithLabelValues("items"))
+	IncrementQuotaExceeded("items")
+	after := testutil.ToFloat64(quotaExceededTotal.WithLabelValues("items"))
+
+	if after != before+1 {
+		t.Errorf("Expected counter to increment by 1, got %f", after-before)
+	}
+}
+
 // SSE Metrics Tests
 
 func TestIncrementSSEConnections(t *testing.T) {
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// authUserKey is the gin context key holding the authenticated *db.User
+const authUserKey = "auth_user"
+
+// Authenticator resolves a bearer token to a user. It is satisfied by services.AuthService.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*db.User, error)
+}
+
+// RequireAuth returns a Gin middleware that rejects requests without a valid
+// session or API token and stores the authenticated user on the context
+func RequireAuth(authenticator Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := extractToken(c.Request)
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		user, err := authenticator.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidToken) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		SetAuthUser(c, user)
+		c.Next()
+	}
+}
+
+// SetAuthUser stores the authenticated user on the gin context
+func SetAuthUser(c *gin.Context, user *db.User) {
+	c.Set(authUserKey, user)
+}
+
+// AuthUser returns the authenticated user stored on the gin context, if any
+func AuthUser(c *gin.Context) (*db.User, bool) {
+	value, exists := c.Get(authUserKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*db.User)
+	if !ok || user == nil {
+		return nil, false
+	}
+	return user, true
+}
+
+// extractToken reads the bearer token from the Authorization header. The
+// access_token query parameter is accepted as a fallback because browsers
+// cannot set headers on EventSource (SSE) connections.
+func extractToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+
+	return r.URL.Query().Get("access_token")
+}
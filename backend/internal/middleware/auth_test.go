@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/services"
+)
+
+// stubAuthenticator accepts a single fixed token
+type stubAuthenticator struct {
+	token string
+	user  *db.User
+	err   error
+}
+
+func (s *stubAuthenticator) Authenticate(ctx context.Context, token string) (*db.User, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if token != s.token {
+		return nil, services.ErrInvalidToken
+	}
+	return s.user, nil
+}
+
+func setupAuthRouter(authenticator Authenticator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequireAuth(authenticator))
+	router.GET("/me", func(c *gin.Context) {
+		user, ok := AuthUser(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "no user"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": user.ID})
+	})
+	return router
+}
+
+func TestRequireAuth(t *testing.T) {
+	authenticator := &stubAuthenticator{token: "valid-token", user: &db.User{ID: 7}}
+
+	tests := []struct {
+		name           string
+		setup          func(req *http.Request)
+		path           string
+		expectedStatus int
+	}{
+		{
+			name:           "missing token",
+			setup:          func(req *http.Request) {},
+			path:           "/me",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "valid bearer token",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer valid-token")
+			},
+			path:           "/me",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "case-insensitive scheme",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "bearer valid-token")
+			},
+			path:           "/me",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid bearer token",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer wrong-token")
+			},
+			path:           "/me",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "non-bearer scheme",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+			},
+			path:           "/me",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "access_token query parameter",
+			setup:          func(req *http.Request) {},
+			path:           "/me?access_token=valid-token",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	router := setupAuthRouter(authenticator)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireAuth_SetsUser(t *testing.T) {
+	router := setupAuthRouter(&stubAuthenticator{token: "valid-token", user: &db.User{ID: 7}})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":7}`, w.Body.String())
+}
+
+func TestRequireAuth_AuthenticatorError(t *testing.T) {
+	router := setupAuthRouter(&stubAuthenticator{err: errors.New("database unavailable")})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAuthUser_NotSet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	user, ok := AuthUser(c)
+
+	assert.False(t, ok)
+	assert.Nil(t, user)
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCORSRouter(config CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(config))
+	router.GET("/items", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return router
+}
+
+func testCORSConfig(origins ...string) CORSConfig {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = origins
+	return config
+}
+
+func TestCORS_AllowedOrigin(t *testing.T) {
+	router := setupCORSRouter(testCORSConfig("https://app.briefbot.com", "chrome-extension://abcdefghijklmnop"))
+
+	for _, origin := range []string{"https://app.briefbot.com", "chrome-extension://abcdefghijklmnop"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("Origin", origin)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "Retry-After")
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	}
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	router := setupCORSRouter(testCORSConfig("https://app.briefbot.com"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	router.ServeHTTP(w, req)
+
+	// The request is served, but without CORS headers the browser hides the response
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	config := testCORSConfig("https://app.briefbot.com")
+	config.MaxAge = 2 * time.Hour
+	router := setupCORSRouter(config)
+
+	tests := []struct {
+		name           string
+		origin         string
+		expectedStatus int
+		expectedOrigin string
+	}{
+		{name: "allowed origin", origin: "https://app.briefbot.com", expectedStatus: http.StatusNoContent, expectedOrigin: "https://app.briefbot.com"},
+		{name: "disallowed origin", origin: "https://evil.example.com", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodOptions, "/items", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "Authorization, Content-Type")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			if tt.expectedStatus == http.StatusNoContent {
+				assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+				assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+				assert.Equal(t, "7200", w.Header().Get("Access-Control-Max-Age"))
+			}
+		})
+	}
+}
+
+func TestCORS_WildcardPatterns(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowed     string
+		origin      string
+		expected    string
+		credentials string
+	}{
+		{name: "subdomain pattern", allowed: "https://*.briefbot.com", origin: "https://preview-42.briefbot.com", expected: "https://preview-42.briefbot.com", credentials: "true"},
+		{name: "pattern needs a subdomain", allowed: "https://*.briefbot.com", origin: "https://.briefbot.com"},
+		{name: "pattern does not match other domains", allowed: "https://*.briefbot.com", origin: "https://briefbot.com.evil.io"},
+		{name: "any origin never sends credentials", allowed: "*", origin: "https://anywhere.example", expected: "*"},
+		{name: "case and trailing slash ignored", allowed: "HTTPS://App.BriefBot.com/", origin: "https://app.briefbot.com", expected: "https://app.briefbot.com", credentials: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupCORSRouter(testCORSConfig(tt.allowed))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+			req.Header.Set("Origin", tt.origin)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.credentials, w.Header().Get("Access-Control-Allow-Credentials"))
+		})
+	}
+}
+
+func TestCORS_SameOriginRequest(t *testing.T) {
+	router := setupCORSRouter(testCORSConfig("https://app.briefbot.com"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Vary"))
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yamirghofran/briefbot/internal/metrics"
+)
+
+// RateLimit describes a token bucket: Rate tokens are added per second, up to Burst
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns a limit of requests per minute that allows bursts of up to
+// burst requests. The burst is at least one so the limit can be met.
+func PerMinute(requests int, burst int) RateLimit {
+	if burst < 1 {
+		burst = 1
+	}
+	return RateLimit{Rate: float64(requests) / 60, Burst: burst}
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// RateLimitStore holds token buckets keyed by caller. MemoryRateLimitStore
+// serves a single instance; PostgresRateLimitStore shares buckets between instances.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// RateLimitMiddleware returns a Gin middleware that rejects requests with 429
+// once the caller's bucket is empty. Authenticated requests share one bucket
+// per user, whether they use a session or any of the user's API tokens, and
+// anonymous requests one bucket per client IP. Store errors are logged and the
+// request is allowed.
+func RateLimitMiddleware(name string, store RateLimitStore, limit RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := name + ":" + rateLimitKey(c)
+
+		result, err := store.Take(c.Request.Context(), key, limit)
+		if err != nil {
+			log.Printf("Rate limiter %s failed, allowing request: %v", name, err)
+			c.Next()
+			return
+		}
+		metrics.RecordRateLimitDecision(name, result.Allowed)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+		if !result.Allowed {
+			retryAfter := RetryAfterSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Rate limit exceeded",
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RetryAfterSeconds rounds a wait up to whole seconds for the Retry-After header
+func RetryAfterSeconds(wait time.Duration) int {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// rateLimitKey identifies the caller: the authenticated user, or the client IP
+// for anonymous requests
+func rateLimitKey(c *gin.Context) string {
+	user, ok := AuthUser(c)
+	if !ok {
+		return "ip:" + c.ClientIP()
+	}
+	return fmt.Sprintf("user:%d", user.ID)
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// rateLimitSweepInterval is how often the memory store drops idle buckets
+const rateLimitSweepInterval = time.Minute
+
+// tokenBucket is the state of one caller's bucket
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	limit   RateLimit
+}
+
+// MemoryRateLimitStore keeps token buckets in process memory
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Take refills the bucket for the time elapsed since it was last used and takes one token
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	burst := float64(limit.Burst)
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, updated: now}
+		s.buckets[key] = bucket
+	}
+	bucket.limit = limit
+
+	elapsed := now.Sub(bucket.updated).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(burst, bucket.tokens+elapsed*limit.Rate)
+		bucket.updated = now
+	}
+
+	if bucket.tokens < 1 {
+		return RateLimitResult{
+			Allowed:    false,
+			RetryAfter: refillWait(bucket.tokens, limit),
+		}, nil
+	}
+
+	bucket.tokens--
+	return RateLimitResult{Allowed: true, Remaining: int(bucket.tokens)}, nil
+}
+
+// sweep drops buckets that have refilled completely, since a full bucket is
+// the same as a missing one
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*bucket.limit.Rate >= float64(bucket.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// PostgresRateLimitStore keeps token buckets in the rate_limit_buckets table
+// so every API instance draws from the same bucket
+type PostgresRateLimitStore struct {
+	querier db.Querier
+}
+
+// NewPostgresRateLimitStore creates a store backed by the database
+func NewPostgresRateLimitStore(querier db.Querier) *PostgresRateLimitStore {
+	return &PostgresRateLimitStore{querier: querier}
+}
+
+// Take refills and takes a token in a single statement. When the bucket is
+// empty the current level is read back to work out the retry delay.
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	remaining, err := s.querier.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		BucketKey: key,
+		Burst:     float64(limit.Burst),
+		Rate:      limit.Rate,
+	})
+	if err == nil {
+		return RateLimitResult{Allowed: true, Remaining: int(remaining)}, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return RateLimitResult{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	tokens, err := s.querier.GetRateLimitTokens(ctx, db.GetRateLimitTokensParams{
+		Burst:     float64(limit.Burst),
+		Rate:      limit.Rate,
+		BucketKey: key,
+	})
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("failed to read rate limit bucket: %w", err)
+	}
+
+	return RateLimitResult{Allowed: false, RetryAfter: refillWait(tokens, limit)}, nil
+}
+
+// DeleteStaleBuckets removes buckets that have not been used since before
+func (s *PostgresRateLimitStore) DeleteStaleBuckets(ctx context.Context, before time.Time) error {
+	if err := s.querier.DeleteStaleRateLimitBuckets(ctx, before); err != nil {
+		return fmt.Errorf("failed to delete stale rate limit buckets: %w", err)
+	}
+	return nil
+}
+
+// refillWait is how long a bucket holding tokens takes to reach one token
+func refillWait(tokens float64, limit RateLimit) time.Duration {
+	if limit.Rate <= 0 {
+		return time.Hour
+	}
+	return time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
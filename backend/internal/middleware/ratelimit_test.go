@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+// fakeClock is a controllable time source for the memory store
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func newTestMemoryStore() (*MemoryRateLimitStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryRateLimitStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryRateLimitStore_TakesBurstThenLimits(t *testing.T) {
+	store, clock := newTestMemoryStore()
+	limit := PerMinute(60, 3) // one token per second
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// Other callers have their own bucket
+	result, err = store.Take(ctx, "user:2", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Half a token is not enough
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	result, err = store.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	result, err = store.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryRateLimitStore_RefillIsCappedAtBurst(t *testing.T) {
+	store, clock := newTestMemoryStore()
+	limit := PerMinute(60, 2)
+	ctx := context.Background()
+
+	_, _ = store.Take(ctx, "user:1", limit)
+	clock.now = clock.now.Add(time.Hour)
+
+	result, err := store.Take(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Remaining)
+}
+
+func TestMemoryRateLimitStore_SweepsFullBuckets(t *testing.T) {
+	store, clock := newTestMemoryStore()
+	ctx := context.Background()
+
+	_, _ = store.Take(ctx, "user:1", PerMinute(60, 2))
+	_, _ = store.Take(ctx, "user:2", PerMinute(1, 3))
+	_, _ = store.Take(ctx, "user:2", PerMinute(1, 3))
+
+	clock.now = clock.now.Add(rateLimitSweepInterval)
+	_, _ = store.Take(ctx, "user:3", PerMinute(60, 2))
+
+	assert.NotContains(t, store.buckets, "user:1")
+	assert.Contains(t, store.buckets, "user:2", "bucket still refilling must be kept")
+	assert.Contains(t, store.buckets, "user:3")
+}
+
+func TestPostgresRateLimitStore_Take(t *testing.T) {
+	ctx := context.Background()
+	limit := RateLimit{Rate: 0.5, Burst: 10}
+
+	t.Run("allowed", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		store := NewPostgresRateLimitStore(mockQuerier)
+		mockQuerier.On("TakeRateLimitToken", ctx, db.TakeRateLimitTokenParams{BucketKey: "api:user:1", Burst: 10, Rate: 0.5}).Return(float64(7.4), nil)
+
+		result, err := store.Take(ctx, "api:user:1", limit)
+
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 7, result.Remaining)
+		mockQuerier.AssertExpectations(t)
+	})
+
+	t.Run("empty bucket", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		store := NewPostgresRateLimitStore(mockQuerier)
+		mockQuerier.On("TakeRateLimitToken", ctx, db.TakeRateLimitTokenParams{BucketKey: "api:user:1", Burst: 10, Rate: 0.5}).Return(float64(0), pgx.ErrNoRows)
+		mockQuerier.On("GetRateLimitTokens", ctx, db.GetRateLimitTokensParams{Burst: 10, Rate: 0.5, BucketKey: "api:user:1"}).Return(float64(0.5), nil)
+
+		result, err := store.Take(ctx, "api:user:1", limit)
+
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, time.Second, result.RetryAfter)
+		mockQuerier.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		store := NewPostgresRateLimitStore(mockQuerier)
+		mockQuerier.On("TakeRateLimitToken", ctx, db.TakeRateLimitTokenParams{BucketKey: "api:user:1", Burst: 10, Rate: 0.5}).Return(float64(0), errors.New("connection refused"))
+
+		_, err := store.Take(ctx, "api:user:1", limit)
+
+		assert.Error(t, err)
+	})
+}
+
+// stubRateLimitStore returns fixed results and records the keys it was asked for
+type stubRateLimitStore struct {
+	result RateLimitResult
+	err    error
+	keys   []string
+}
+
+func (s *stubRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	s.keys = append(s.keys, key)
+	return s.result, s.err
+}
+
+func setupRateLimitRouter(store RateLimitStore, user *db.User) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if user != nil {
+			SetAuthUser(c, user)
+		}
+		c.Next()
+	})
+	router.Use(RateLimitMiddleware("api", store, PerMinute(60, 5)))
+	router.GET("/items", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return router
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Run("allowed request passes through", func(t *testing.T) {
+		store := &stubRateLimitStore{result: RateLimitResult{Allowed: true, Remaining: 4}}
+		router := setupRateLimitRouter(store, &db.User{ID: 7})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("Authorization", "Bearer session-token")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "5", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "4", w.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, []string{"api:user:7"}, store.keys)
+	})
+
+	t.Run("limited request gets 429 with Retry-After", func(t *testing.T) {
+		store := &stubRateLimitStore{result: RateLimitResult{Allowed: false, RetryAfter: 1500 * time.Millisecond}}
+		router := setupRateLimitRouter(store, &db.User{ID: 7})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "Rate limit exceeded")
+	})
+
+	t.Run("API tokens share their owner's bucket", func(t *testing.T) {
+		store := &stubRateLimitStore{result: RateLimitResult{Allowed: true}}
+		router := setupRateLimitRouter(store, &db.User{ID: 7})
+
+		for _, token := range []string{"bb_first", "bb_second", "session-token"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			router.ServeHTTP(w, req)
+		}
+
+		assert.Equal(t, []string{"api:user:7", "api:user:7", "api:user:7"}, store.keys)
+	})
+
+	t.Run("anonymous requests are keyed by IP", func(t *testing.T) {
+		store := &stubRateLimitStore{result: RateLimitResult{Allowed: true}}
+		router := setupRateLimitRouter(store, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+		req.RemoteAddr = "203.0.113.9:1234"
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, []string{"api:ip:203.0.113.9"}, store.keys)
+	})
+
+	t.Run("store errors fail open", func(t *testing.T) {
+		store := &stubRateLimitStore{err: errors.New("database unavailable")}
+		router := setupRateLimitRouter(store, &db.User{ID: 7})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestRateLimitMiddleware_WithMemoryStore(t *testing.T) {
+	store, _ := newTestMemoryStore()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		SetAuthUser(c, &db.User{ID: 1})
+		c.Next()
+	})
+	router.Use(RateLimitMiddleware("create", store, PerMinute(1, 2)))
+	router.POST("/items", func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+
+	codes := []int{}
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/items", nil)
+		router.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+		if w.Code == http.StatusTooManyRequests {
+			assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		}
+	}
+
+	assert.Equal(t, []int{http.StatusAccepted, http.StatusAccepted, http.StatusTooManyRequests}, codes)
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	assert.Equal(t, 1, RetryAfterSeconds(0))
+	assert.Equal(t, 1, RetryAfterSeconds(200*time.Millisecond))
+	assert.Equal(t, 3, RetryAfterSeconds(2100*time.Millisecond))
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSecurityHeadersRouter(config SecurityHeadersConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SecurityHeaders(config))
+	router.GET("/items", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/swagger/*any", func(c *gin.Context) {
+		c.String(http.StatusOK, "<html></html>")
+	})
+	return router
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	router := setupSecurityHeadersRouter(DefaultSecurityHeadersConfig())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", w.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "default-src 'none'; frame-ancestors 'none'", w.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"), "HSTS is not sent over plain HTTP")
+}
+
+func TestSecurityHeaders_HSTSBehindTLSProxy(t *testing.T) {
+	router := setupSecurityHeadersRouter(DefaultSecurityHeadersConfig())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "max-age=15552000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecurityHeaders_HSTSDisabled(t *testing.T) {
+	config := DefaultSecurityHeadersConfig()
+	config.HSTSMaxAge = 0
+	router := setupSecurityHeadersRouter(config)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	router.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecurityHeaders_SwaggerExemptFromCSP(t *testing.T) {
+	router := setupSecurityHeadersRouter(DefaultSecurityHeadersConfig())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/swagger/index.html", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+}
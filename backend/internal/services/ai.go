@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"sync"
 
 	"github.com/invopop/jsonschema"
@@ -13,25 +12,46 @@ import (
 
 type AIService interface {
 	ExtractContent(ctx context.Context, content string) (ItemExtraction, error)
-	SummarizeContent(ctx context.Context, content string) (ItemSummary, error)
+	SummarizeContent(ctx context.Context, content string, options SummaryOptions) (ItemSummary, error)
 	WritePodcast(content string) (Podcast, error)
 }
 
+// DefaultAIModel is the chat model used when AIConfig.Model is empty
+const DefaultAIModel = "moonshotai/kimi-k2-instruct-0905"
+
+// AIConfig holds the settings for the OpenAI-compatible chat API
+type AIConfig struct {
+	APIKey string
+	Model  string
+}
+
 type aiService struct {
 	textClient openai.Client
+	model      string
 }
 
-func NewAIService(oaiClient *openai.Client) (AIService, error) {
-	apiKey := os.Getenv("GROQ_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("GROQ_API_KEY environment variable not set")
+func NewAIService(oaiClient *openai.Client, config AIConfig) (AIService, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("GROQ_API_KEY not set")
+	}
+	if config.Model == "" {
+		config.Model = DefaultAIModel
 	}
 
 	return &aiService{
 		textClient: *oaiClient,
+		model:      config.Model,
 	}, nil
 }
 
+// chatModel returns the configured model, falling back to DefaultAIModel
+func (s *aiService) chatModel() string {
+	if s.model == "" {
+		return DefaultAIModel
+	}
+	return s.model
+}
+
 type ItemExtraction struct {
 	Title    string   `json:"title" jsonschema_description:"The title for this item."`
 	Authors  []string `json:"authors" jsonschema_description:"The authors of this item"`
@@ -45,6 +65,13 @@ type ItemSummary struct {
 	KeyPoints []string `json:"key_points" jsonschema_description:"A list of key points that succinctly deliver the most important facts from the item."`
 }
 
+// SummaryOptions tunes the length and language of generated summaries.
+// Zero values keep the model's default behaviour.
+type SummaryOptions struct {
+	Length   string
+	Language string
+}
+
 type Podcast struct {
 	Dialogues []Dialogue `json:"dialogues" jsonschema:"required" jsonschema_description:"The dialogues that make up the podcast"`
 }
@@ -122,7 +149,7 @@ func (s *aiService) ExtractContent(ctx context.Context, content string) (ItemExt
 				},
 			},
 		},
-		Model: "moonshotai/kimi-k2-instruct-0905",
+		Model: s.chatModel(),
 	})
 	if err != nil {
 		return ItemExtraction{}, err
@@ -135,10 +162,10 @@ func (s *aiService) ExtractContent(ctx context.Context, content string) (ItemExt
 	return itemExtraction, nil
 }
 
-func (s *aiService) SummarizeContent(ctx context.Context, content string) (ItemSummary, error) {
+func (s *aiService) SummarizeContent(ctx context.Context, content string, options SummaryOptions) (ItemSummary, error) {
 	chatCompletion, err := s.textClient.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.SystemMessage("You are an expert content summarizer. Your job is to create a structured summary of the provided material in the exact JSON schema format specified. You must return ONLY the JSON object with the required fields: overview (a brief overview) and key_points (a list of key points). Do not include any additional text or explanation."),
+			openai.SystemMessage("You are an expert content summarizer. Your job is to create a structured summary of the provided material in the exact JSON schema format specified. You must return ONLY the JSON object with the required fields: overview (a brief overview) and key_points (a list of key points). Do not include any additional text or explanation." + summaryInstructions(options)),
 			openai.UserMessage("Summarize this content in the exact JSON schema format with overview and key_points fields."),
 			openai.UserMessage(content),
 		},
@@ -152,7 +179,7 @@ func (s *aiService) SummarizeContent(ctx context.Context, content string) (ItemS
 				},
 			},
 		},
-		Model: "moonshotai/kimi-k2-instruct-0905",
+		Model: s.chatModel(),
 	})
 	if err != nil {
 		return ItemSummary{}, err
@@ -165,6 +192,23 @@ func (s *aiService) SummarizeContent(ctx context.Context, content string) (ItemS
 	return itemSummary, nil
 }
 
+// summaryInstructions turns summary options into extra system prompt instructions
+func summaryInstructions(options SummaryOptions) string {
+	var instructions string
+	switch options.Length {
+	case SummaryLengthShort:
+		instructions += " Keep the overview to one or two sentences and give at most 3 key points."
+	case SummaryLengthMedium:
+		instructions += " Keep the overview to a short paragraph and give 3 to 6 key points."
+	case SummaryLengthLong:
+		instructions += " Write a detailed overview of several paragraphs and give up to 10 key points."
+	}
+	if options.Language != "" {
+		instructions += fmt.Sprintf(" Write the overview and key points in the language with code %q, regardless of the source language.", options.Language)
+	}
+	return instructions
+}
+
 // WritePodcastSection generates a specific section of the podcast concurrently
 func (s *aiService) WritePodcastSection(content string, section string, resultChan chan<- PodcastSectionResult, wg *sync.WaitGroup) {
 	defer wg.Done()
@@ -193,7 +237,7 @@ func (s *aiService) WritePodcastSection(content string, section string, resultCh
 				},
 			},
 		},
-		Model: "moonshotai/kimi-k2-instruct-0905",
+		Model: s.chatModel(),
 	})
 
 	if err != nil {
@@ -15,8 +15,8 @@ func (m *MockAIService) ExtractContent(ctx context.Context, content string) (Ite
 	return args.Get(0).(ItemExtraction), args.Error(1)
 }
 
-func (m *MockAIService) SummarizeContent(ctx context.Context, content string) (ItemSummary, error) {
-	args := m.Called(ctx, content)
+func (m *MockAIService) SummarizeContent(ctx context.Context, content string, options SummaryOptions) (ItemSummary, error) {
+	args := m.Called(ctx, content, options)
 	return args.Get(0).(ItemSummary), args.Error(1)
 }
 
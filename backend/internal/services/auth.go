@@ -0,0 +1,535 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Authentication errors returned by AuthService
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrAPITokenNotFound   = errors.New("api token not found")
+	ErrInvalidAPIToken    = errors.New("invalid api token")
+
+	ErrPasswordTooShort         = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrIncorrectPassword        = errors.New("current password is incorrect")
+	ErrInvalidResetToken        = errors.New("invalid or expired password reset token")
+	ErrPasswordResetUnavailable = errors.New("password reset email is not configured")
+)
+
+// MinPasswordLength is the minimum accepted length for user passwords
+const MinPasswordLength = 8
+
+// apiTokenPrefix marks personal API tokens so they can be told apart from session tokens
+const apiTokenPrefix = "bb_"
+
+// AuthProviderPassword is stored in users.auth_provider for email/password accounts
+const AuthProviderPassword = "password"
+
+// dummyPasswordHash is compared against when a login email is unknown so that
+// response timing does not reveal which emails are registered
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("briefbot-dummy-password"), bcrypt.DefaultCost)
+
+// AuthService handles user login, session tokens and personal API tokens
+type AuthService interface {
+	// Password authentication
+	Register(ctx context.Context, name, email, password string) (*db.User, error)
+	Login(ctx context.Context, email, password string) (*AuthSession, error)
+	IssueSession(ctx context.Context, user *db.User) (*AuthSession, error)
+
+	// Password management
+	ChangePassword(ctx context.Context, userID int32, currentPassword, newPassword string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	SetEmailService(emailService EmailService)
+
+	// Authenticate resolves a session token or personal API token to its user
+	Authenticate(ctx context.Context, token string) (*db.User, error)
+
+	// Personal API tokens
+	CreateAPIToken(ctx context.Context, userID int32, name string, expiresAt *time.Time) (*CreatedAPIToken, error)
+	ListAPITokens(ctx context.Context, userID int32) ([]db.ApiToken, error)
+	RevokeAPIToken(ctx context.Context, userID int32, tokenID int32) error
+}
+
+// AuthConfig holds configuration for the auth service
+type AuthConfig struct {
+	SessionSecret []byte        // HMAC key used to sign session tokens
+	SessionTTL    time.Duration // Lifetime of a session token
+
+	PasswordResetURL string        // Frontend page that accepts ?token=<reset token>
+	PasswordResetTTL time.Duration // Lifetime of a password reset link
+}
+
+// AuthSession is a signed session token issued after a successful login
+type AuthSession struct {
+	Token     string
+	ExpiresAt time.Time
+	User      *db.User
+}
+
+// CreatedAPIToken contains a newly created API token. The plaintext token is
+// only available at creation time; only its hash is persisted.
+type CreatedAPIToken struct {
+	Token    string
+	APIToken *db.ApiToken
+}
+
+// sessionClaims is the signed payload of a session token
+type sessionClaims struct {
+	UserID    int32 `json:"sub"`
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp"`
+	// Version is the user's session version when the token was issued
+	Version int32 `json:"ver,omitempty"`
+}
+
+type authService struct {
+	querier      db.Querier
+	emailService EmailService
+	config       AuthConfig
+	now          func() time.Time
+}
+
+// NewAuthService creates a new auth service
+func NewAuthService(querier db.Querier, config AuthConfig) AuthService {
+	if config.SessionTTL <= 0 {
+		config.SessionTTL = 24 * time.Hour
+	}
+	if config.PasswordResetTTL <= 0 {
+		config.PasswordResetTTL = time.Hour
+	}
+
+	return &authService{
+		querier: querier,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// SetEmailService sets the email service used to deliver password reset links
+func (s *authService) SetEmailService(emailService EmailService) {
+	s.emailService = emailService
+}
+
+// Register creates a new email/password user, hashing the password server-side
+func (s *authService) Register(ctx context.Context, name, email, password string) (*db.User, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, fmt.Errorf("email is required")
+	}
+	if len(password) < MinPasswordLength {
+		return nil, ErrPasswordTooShort
+	}
+
+	if _, err := s.querier.GetUserByEmail(ctx, &email); err == nil {
+		return nil, ErrEmailTaken
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	passwordHash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := AuthProviderPassword
+	params := db.CreateUserParams{
+		Name:         &name,
+		Email:        &email,
+		AuthProvider: &provider,
+		PasswordHash: &passwordHash,
+	}
+
+	user, err := s.querier.CreateUser(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// Login verifies an email/password pair and issues a session token
+func (s *authService) Login(ctx context.Context, email, password string) (*AuthSession, error) {
+	email = strings.TrimSpace(email)
+	user, err := s.querier.GetUserByEmail(ctx, &email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	if user.PasswordHash == nil || !CheckPassword(*user.PasswordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.IssueSession(ctx, &user)
+}
+
+// IssueSession signs a new session token for an already authenticated user
+func (s *authService) IssueSession(ctx context.Context, user *db.User) (*AuthSession, error) {
+	if len(s.config.SessionSecret) == 0 {
+		return nil, fmt.Errorf("session secret not configured")
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.config.SessionTTL)
+	claims := sessionClaims{
+		UserID:    user.ID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		Version:   user.SessionVersion,
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session claims: %w", err)
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	token := encodedPayload + "." + s.sign(encodedPayload)
+
+	return &AuthSession{
+		Token:     token,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		User:      user,
+	}, nil
+}
+
+// Authenticate resolves a bearer token to the user it was issued for
+func (s *authService) Authenticate(ctx context.Context, token string) (*db.User, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if IsAPIToken(token) {
+		userID, err := s.authenticateAPIToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		return s.tokenUser(ctx, userID)
+	}
+	return s.authenticateSession(ctx, token)
+}
+
+// tokenUser loads the user a valid token was issued for
+func (s *authService) tokenUser(ctx context.Context, userID int32) (*db.User, error) {
+	user, err := s.querier.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The user was deleted after the token was issued
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &user, nil
+}
+
+// authenticateSession verifies a session token's signature and expiry, and
+// that it was issued since the user last changed their password
+func (s *authService) authenticateSession(ctx context.Context, token string) (*db.User, error) {
+	if len(s.config.SessionSecret) == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	encodedPayload, signature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(encodedPayload))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims sessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if s.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.tokenUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Version != user.SessionVersion {
+		return nil, ErrInvalidToken
+	}
+	return user, nil
+}
+
+// authenticateAPIToken looks up a personal API token by its hash
+func (s *authService) authenticateAPIToken(ctx context.Context, token string) (int32, error) {
+	apiToken, err := s.querier.GetAPITokenByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrInvalidToken
+		}
+		return 0, fmt.Errorf("failed to look up api token: %w", err)
+	}
+
+	if apiToken.ExpiresAt != nil && !s.now().Before(*apiToken.ExpiresAt) {
+		return 0, ErrInvalidToken
+	}
+
+	if err := s.querier.TouchAPIToken(ctx, apiToken.ID); err != nil {
+		// Usage tracking is best effort and must not block the request
+		log.Printf("Warning: Failed to update last_used_at for api token %d: %v", apiToken.ID, err)
+	}
+
+	return apiToken.UserID, nil
+}
+
+// CreateAPIToken generates a new personal API token for a user
+func (s *authService) CreateAPIToken(ctx context.Context, userID int32, name string, expiresAt *time.Time) (*CreatedAPIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: token name is required", ErrInvalidAPIToken)
+	}
+	if expiresAt != nil && !expiresAt.After(s.now()) {
+		return nil, fmt.Errorf("%w: token expiry must be in the future", ErrInvalidAPIToken)
+	}
+
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate api token: %w", err)
+	}
+	token := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(randomBytes)
+
+	params := db.CreateAPITokenParams{
+		UserID:      userID,
+		Name:        name,
+		TokenHash:   hashToken(token),
+		TokenPrefix: token[:len(apiTokenPrefix)+6],
+		ExpiresAt:   expiresAt,
+	}
+
+	apiToken, err := s.querier.CreateAPIToken(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	return &CreatedAPIToken{
+		Token:    token,
+		APIToken: &apiToken,
+	}, nil
+}
+
+// ListAPITokens lists a user's personal API tokens
+func (s *authService) ListAPITokens(ctx context.Context, userID int32) ([]db.ApiToken, error) {
+	tokens, err := s.querier.ListAPITokensByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken deletes one of a user's personal API tokens
+func (s *authService) RevokeAPIToken(ctx context.Context, userID int32, tokenID int32) error {
+	params := db.DeleteAPITokenParams{
+		ID:     tokenID,
+		UserID: userID,
+	}
+
+	rows, err := s.querier.DeleteAPIToken(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+	if rows == 0 {
+		return ErrAPITokenNotFound
+	}
+
+	return nil
+}
+
+// ChangePassword sets a new password for a user. Users who already have a
+// password must confirm it; users who signed up through an identity provider
+// can set their first password without one.
+func (s *authService) ChangePassword(ctx context.Context, userID int32, currentPassword, newPassword string) error {
+	if len(newPassword) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+
+	user, err := s.querier.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.PasswordHash != nil && !CheckPassword(*user.PasswordHash, currentPassword) {
+		return ErrIncorrectPassword
+	}
+
+	return s.setPassword(ctx, userID, newPassword)
+}
+
+// RequestPasswordReset emails a single-use reset link. Unknown emails are
+// ignored without an error so callers cannot probe which emails are registered.
+func (s *authService) RequestPasswordReset(ctx context.Context, email string) error {
+	if s.emailService == nil {
+		return ErrPasswordResetUnavailable
+	}
+
+	email = strings.TrimSpace(email)
+	user, err := s.querier.GetUserByEmail(ctx, &email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(randomBytes)
+
+	params := db.CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: s.now().Add(s.config.PasswordResetTTL),
+	}
+	if _, err := s.querier.CreatePasswordResetToken(ctx, params); err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	resetURL := s.config.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	htmlBody, textBody := GeneratePasswordResetEmail(resetURL, s.config.PasswordResetTTL)
+
+	err = s.emailService.SendEmail(ctx, EmailRequest{
+		ToAddresses: []string{email},
+		Subject:     "Reset your BriefBot password",
+		HTMLBody:    htmlBody,
+		TextBody:    textBody,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password using a token from RequestPasswordReset
+func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if len(newPassword) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+
+	resetToken, err := s.querier.GetPasswordResetTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to look up password reset token: %w", err)
+	}
+
+	if resetToken.UsedAt != nil || !s.now().Before(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	// Claim the token first so concurrent requests cannot both use it
+	rows, err := s.querier.MarkPasswordResetTokenUsed(ctx, resetToken.ID)
+	if err != nil {
+		return fmt.Errorf("failed to use password reset token: %w", err)
+	}
+	if rows == 0 {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.setPassword(ctx, resetToken.UserID, newPassword); err != nil {
+		return err
+	}
+	// Whoever made the reset necessary may hold one of the user's API tokens
+	if err := s.querier.DeleteAPITokensByUser(ctx, resetToken.UserID); err != nil {
+		return fmt.Errorf("failed to revoke api tokens: %w", err)
+	}
+
+	// The reset link reached the user's inbox, which proves they control the
+	// address. Links are revoked when the email changes, so it is the current one.
+	if err := s.querier.MarkUserEmailVerified(ctx, resetToken.UserID); err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	return nil
+}
+
+// setPassword hashes and stores a new password, signing out every session and
+// invalidating any outstanding reset links for the user
+func (s *authService) setPassword(ctx context.Context, userID int32, password string) error {
+	passwordHash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	params := db.UpdateUserPasswordParams{
+		ID:           userID,
+		PasswordHash: &passwordHash,
+	}
+	if err := s.querier.UpdateUserPassword(ctx, params); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.querier.DeletePasswordResetTokensByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	return nil
+}
+
+// sign returns the base64url-encoded HMAC-SHA256 of a session payload
+func (s *authService) sign(encodedPayload string) string {
+	mac := hmac.New(sha256.New, s.config.SessionSecret)
+	mac.Write([]byte(encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// IsAPIToken reports whether token is a personal API token rather than a session token
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, apiTokenPrefix)
+}
+
+// hashToken returns the hex SHA-256 digest under which API and password reset
+// tokens are stored
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashPassword hashes a plaintext password with bcrypt
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether a plaintext password matches a bcrypt hash
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,542 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func newTestAuthService(querier db.Querier) *authService {
+	return NewAuthService(querier, AuthConfig{
+		SessionSecret: []byte("test-secret"),
+		SessionTTL:    time.Hour,
+	}).(*authService)
+}
+
+func TestAuthRegister(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	email := "john@example.com"
+
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateUser", ctx, mock.MatchedBy(func(params db.CreateUserParams) bool {
+		return *params.Email == email &&
+			*params.AuthProvider == AuthProviderPassword &&
+			params.PasswordHash != nil &&
+			*params.PasswordHash != "password123" &&
+			CheckPassword(*params.PasswordHash, "password123")
+	})).Return(db.User{ID: 1, Email: &email}, nil)
+
+	user, err := service.Register(ctx, "John Doe", email, "password123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), user.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAuthRegister_EmailTaken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	email := "john@example.com"
+
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 1}, nil)
+
+	user, err := service.Register(ctx, "John Doe", email, "password123")
+
+	assert.ErrorIs(t, err, ErrEmailTaken)
+	assert.Nil(t, user)
+	mockQuerier.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestAuthRegister_ShortPassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	user, err := service.Register(context.Background(), "John Doe", "john@example.com", "short")
+
+	assert.ErrorIs(t, err, ErrPasswordTooShort)
+	assert.Nil(t, user)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAuthLogin(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	email := "john@example.com"
+	hash, err := HashPassword("password123")
+	assert.NoError(t, err)
+
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 1, Email: &email, PasswordHash: &hash}, nil)
+
+	session, err := service.Login(ctx, email, "password123")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session.Token)
+	assert.Equal(t, int32(1), session.User.ID)
+	assert.True(t, session.ExpiresAt.After(time.Now()))
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAuthLogin_WrongPassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	email := "john@example.com"
+	hash, err := HashPassword("password123")
+	assert.NoError(t, err)
+
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 1, PasswordHash: &hash}, nil)
+
+	session, err := service.Login(ctx, email, "wrong-password")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Nil(t, session)
+}
+
+func TestAuthLogin_UnknownEmail(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	email := "nobody@example.com"
+
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{}, pgx.ErrNoRows)
+
+	session, err := service.Login(ctx, email, "password123")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Nil(t, session)
+}
+
+func TestAuthLogin_OAuthUserWithoutPassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	email := "john@example.com"
+
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 1}, nil)
+
+	session, err := service.Login(ctx, email, "password123")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Nil(t, session)
+}
+
+func TestAuthenticate_SessionToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	user := db.User{ID: 1}
+
+	session, err := service.IssueSession(ctx, &user)
+	assert.NoError(t, err)
+
+	mockQuerier.On("GetUser", ctx, int32(1)).Return(user, nil)
+
+	authenticated, err := service.Authenticate(ctx, session.Token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), authenticated.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAuthenticate_SessionFromBeforePasswordChange(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	session, err := service.IssueSession(ctx, &db.User{ID: 1, SessionVersion: 2})
+	assert.NoError(t, err)
+
+	// The password changed after the session was issued
+	mockQuerier.On("GetUser", ctx, int32(1)).Return(db.User{ID: 1, SessionVersion: 3}, nil)
+
+	user, err := service.Authenticate(ctx, session.Token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, user)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAuthenticate_TamperedSessionToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	session, err := service.IssueSession(ctx, &db.User{ID: 1})
+	assert.NoError(t, err)
+
+	// Re-sign the same payload with a different secret
+	other := NewAuthService(mockQuerier, AuthConfig{SessionSecret: []byte("other-secret")}).(*authService)
+	payload, _, _ := strings.Cut(session.Token, ".")
+	forged := payload + "." + other.sign(payload)
+
+	user, err := service.Authenticate(ctx, forged)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, user)
+	mockQuerier.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_ExpiredSessionToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	session, err := service.IssueSession(ctx, &db.User{ID: 1})
+	assert.NoError(t, err)
+
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	user, err := service.Authenticate(ctx, session.Token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, user)
+}
+
+func TestAuthenticate_DeletedUser(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	session, err := service.IssueSession(ctx, &db.User{ID: 1})
+	assert.NoError(t, err)
+
+	mockQuerier.On("GetUser", ctx, int32(1)).Return(db.User{}, pgx.ErrNoRows)
+
+	user, err := service.Authenticate(ctx, session.Token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, user)
+}
+
+func TestCreateAPIToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	var storedHash string
+
+	mockQuerier.On("CreateAPIToken", ctx, mock.MatchedBy(func(params db.CreateAPITokenParams) bool {
+		storedHash = params.TokenHash
+		return params.UserID == 1 && params.Name == "CLI" && strings.HasPrefix(params.TokenPrefix, apiTokenPrefix)
+	})).Return(db.ApiToken{ID: 5, UserID: 1, Name: "CLI"}, nil)
+
+	created, err := service.CreateAPIToken(ctx, 1, "CLI", nil)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, apiTokenPrefix))
+	assert.Equal(t, hashToken(created.Token), storedHash)
+	assert.NotContains(t, storedHash, created.Token)
+	assert.Equal(t, int32(5), created.APIToken.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestCreateAPIToken_PastExpiry(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	past := time.Now().Add(-time.Hour)
+	created, err := service.CreateAPIToken(context.Background(), 1, "CLI", &past)
+
+	assert.ErrorIs(t, err, ErrInvalidAPIToken)
+	assert.Nil(t, created)
+	mockQuerier.AssertNotCalled(t, "CreateAPIToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_APIToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	token := "bb_testtoken"
+
+	mockQuerier.On("GetAPITokenByHash", ctx, hashToken(token)).Return(db.ApiToken{ID: 5, UserID: 1}, nil)
+	mockQuerier.On("TouchAPIToken", ctx, int32(5)).Return(nil)
+	mockQuerier.On("GetUser", ctx, int32(1)).Return(db.User{ID: 1}, nil)
+
+	user, err := service.Authenticate(ctx, token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), user.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAuthenticate_UnknownAPIToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	token := "bb_unknown"
+
+	mockQuerier.On("GetAPITokenByHash", ctx, hashToken(token)).Return(db.ApiToken{}, pgx.ErrNoRows)
+
+	user, err := service.Authenticate(ctx, token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, user)
+}
+
+func TestAuthenticate_ExpiredAPIToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	token := "bb_expired"
+	expiredAt := time.Now().Add(-time.Minute)
+
+	mockQuerier.On("GetAPITokenByHash", ctx, hashToken(token)).Return(db.ApiToken{ID: 5, UserID: 1, ExpiresAt: &expiredAt}, nil)
+
+	user, err := service.Authenticate(ctx, token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, user)
+	mockQuerier.AssertNotCalled(t, "TouchAPIToken", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_APITokenLookupError(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	token := "bb_testtoken"
+
+	mockQuerier.On("GetAPITokenByHash", ctx, hashToken(token)).Return(db.ApiToken{}, errors.New("database error"))
+
+	user, err := service.Authenticate(ctx, token)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, user)
+}
+
+func TestRevokeAPIToken(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	params := db.DeleteAPITokenParams{ID: 5, UserID: 1}
+
+	mockQuerier.On("DeleteAPIToken", ctx, params).Return(int64(1), nil)
+
+	err := service.RevokeAPIToken(ctx, 1, 5)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestRevokeAPIToken_NotFound(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	params := db.DeleteAPITokenParams{ID: 5, UserID: 2}
+
+	mockQuerier.On("DeleteAPIToken", ctx, params).Return(int64(0), nil)
+
+	err := service.RevokeAPIToken(ctx, 2, 5)
+
+	assert.ErrorIs(t, err, ErrAPITokenNotFound)
+}
+
+func TestChangePassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	hash, err := HashPassword("old-password")
+	assert.NoError(t, err)
+
+	mockQuerier.On("GetUser", ctx, int32(1)).Return(db.User{ID: 1, PasswordHash: &hash}, nil)
+	mockQuerier.On("UpdateUserPassword", ctx, mock.MatchedBy(func(params db.UpdateUserPasswordParams) bool {
+		return params.ID == 1 && CheckPassword(*params.PasswordHash, "new-password")
+	})).Return(nil)
+	mockQuerier.On("DeletePasswordResetTokensByUser", ctx, int32(1)).Return(nil)
+
+	err = service.ChangePassword(ctx, 1, "old-password", "new-password")
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestChangePassword_IncorrectCurrentPassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	hash, err := HashPassword("old-password")
+	assert.NoError(t, err)
+
+	mockQuerier.On("GetUser", ctx, int32(1)).Return(db.User{ID: 1, PasswordHash: &hash}, nil)
+
+	err = service.ChangePassword(ctx, 1, "wrong-password", "new-password")
+
+	assert.ErrorIs(t, err, ErrIncorrectPassword)
+	mockQuerier.AssertNotCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_OAuthUserSetsFirstPassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	subject := "subject-123"
+
+	mockQuerier.On("GetUser", ctx, int32(1)).Return(db.User{ID: 1, OauthID: &subject}, nil)
+	mockQuerier.On("UpdateUserPassword", ctx, mock.Anything).Return(nil)
+	mockQuerier.On("DeletePasswordResetTokensByUser", ctx, int32(1)).Return(nil)
+
+	err := service.ChangePassword(ctx, 1, "", "new-password")
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestChangePassword_ShortPassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	err := service.ChangePassword(context.Background(), 1, "old-password", "short")
+
+	assert.ErrorIs(t, err, ErrPasswordTooShort)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmailService := new(MockEmailService)
+	service := newTestAuthService(mockQuerier)
+	service.config.PasswordResetURL = "https://app.example.com/reset-password"
+	service.SetEmailService(mockEmailService)
+
+	ctx := context.Background()
+	email := "john@example.com"
+
+	var storedHash string
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 1, Email: &email}, nil)
+	mockQuerier.On("CreatePasswordResetToken", ctx, mock.MatchedBy(func(params db.CreatePasswordResetTokenParams) bool {
+		storedHash = params.TokenHash
+		return params.UserID == 1 && params.ExpiresAt.After(time.Now())
+	})).Return(db.PasswordResetToken{ID: 1}, nil)
+
+	var sentRequest EmailRequest
+	mockEmailService.On("SendEmail", ctx, mock.MatchedBy(func(request EmailRequest) bool {
+		sentRequest = request
+		return len(request.ToAddresses) == 1 && request.ToAddresses[0] == email
+	})).Return(nil)
+
+	err := service.RequestPasswordReset(ctx, email)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+	mockEmailService.AssertExpectations(t)
+
+	// The emailed link carries the plaintext token; only its hash is stored
+	_, token, found := strings.Cut(sentRequest.TextBody, "reset-password?token=")
+	assert.True(t, found)
+	token = strings.Fields(token)[0]
+	assert.Equal(t, hashToken(token), storedHash)
+}
+
+func TestRequestPasswordReset_UnknownEmail(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmailService := new(MockEmailService)
+	service := newTestAuthService(mockQuerier)
+	service.SetEmailService(mockEmailService)
+
+	ctx := context.Background()
+	email := "nobody@example.com"
+
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{}, pgx.ErrNoRows)
+
+	err := service.RequestPasswordReset(ctx, email)
+
+	assert.NoError(t, err)
+	mockEmailService.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything)
+}
+
+func TestRequestPasswordReset_NoEmailService(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	err := service.RequestPasswordReset(context.Background(), "john@example.com")
+
+	assert.ErrorIs(t, err, ErrPasswordResetUnavailable)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestResetPassword(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := newTestAuthService(mockQuerier)
+
+	ctx := context.Background()
+	token := "reset-token"
+
+	mockQuerier.On("GetPasswordResetTokenByHash", ctx, hashToken(token)).Return(db.PasswordResetToken{
+		ID:        3,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	mockQuerier.On("MarkPasswordResetTokenUsed", ctx, int32(3)).Return(int64(1), nil)
+	mockQuerier.On("UpdateUserPassword", ctx, mock.MatchedBy(func(params db.UpdateUserPasswordParams) bool {
+		return params.ID == 1 && CheckPassword(*params.PasswordHash, "new-password")
+	})).Return(nil)
+	mockQuerier.On("DeletePasswordResetTokensByUser", ctx, int32(1)).Return(nil)
+	mockQuerier.On("DeleteAPITokensByUser", ctx, int32(1)).Return(nil)
+	mockQuerier.On("MarkUserEmailVerified", ctx, int32(1)).Return(nil)
+
+	err := service.ResetPassword(ctx, token, "new-password")
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		resetToken db.PasswordResetToken
+		lookupErr  error
+		claimed    int64
+	}{
+		{name: "unknown", lookupErr: pgx.ErrNoRows},
+		{name: "expired", resetToken: db.PasswordResetToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}},
+		{name: "already used", resetToken: db.PasswordResetToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}},
+		{name: "claimed concurrently", resetToken: db.PasswordResetToken{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, claimed: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			service := newTestAuthService(mockQuerier)
+
+			ctx := context.Background()
+
+			mockQuerier.On("GetPasswordResetTokenByHash", ctx, hashToken("reset-token")).Return(tt.resetToken, tt.lookupErr)
+			mockQuerier.On("MarkPasswordResetTokenUsed", ctx, int32(3)).Return(tt.claimed, nil).Maybe()
+
+			err := service.ResetPassword(ctx, "reset-token", "new-password")
+
+			assert.ErrorIs(t, err, ErrInvalidResetToken)
+			mockQuerier.AssertNotCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// ErrDuplicateItem is returned when a user already has an item for the same page
+var ErrDuplicateItem = errors.New("item already saved")
+
+// trackingParams identify the campaign, click or share a link came from
+// rather than the page it points to
+var trackingParams = map[string]bool{
+	"fbclid":           true,
+	"gclid":            true,
+	"gclsrc":           true,
+	"dclid":            true,
+	"gbraid":           true,
+	"wbraid":           true,
+	"msclkid":          true,
+	"yclid":            true,
+	"twclid":           true,
+	"ttclid":           true,
+	"li_fat_id":        true,
+	"mc_cid":           true,
+	"mc_eid":           true,
+	"mkt_tok":          true,
+	"igshid":           true,
+	"igsh":             true,
+	"_ga":              true,
+	"_gl":              true,
+	"ref_src":          true,
+	"ref_url":          true,
+	"smid":             true,
+	"cmpid":            true,
+	"ocid":             true,
+	"s_cid":            true,
+	"sr_share":         true,
+	"wt.mc_id":         true,
+	"vero_id":          true,
+	"oly_anon_id":      true,
+	"oly_enc_id":       true,
+	"rb_clickid":       true,
+	"_branch_match_id": true,
+}
+
+// trackingParamPrefixes cover families of campaign parameters, such as
+// Google's utm_source and utm_medium
+var trackingParamPrefixes = []string{"utm_", "_hs", "__hs", "pk_", "mtm_", "at_"}
+
+// shareParams are tracking parameters only some sites add to shared links,
+// but which other sites use for content, keyed by host
+var shareParams = map[string][]string{
+	"youtube.com":      {"si", "feature", "pp"},
+	"youtu.be":         {"si", "feature"},
+	"open.spotify.com": {"si", "context"},
+	"x.com":            {"s", "t"},
+	"twitter.com":      {"s", "t"},
+	"instagram.com":    {"img_index"},
+	"linkedin.com":     {"trk", "trackingid", "lipi"},
+}
+
+// CanonicalizeURL returns the form of a URL used to recognize the same page
+// submitted twice. The scheme becomes https, the host loses its case, port
+// and "www." or "amp." prefix, AMP cache and /amp variants point to the
+// article, and tracking parameters, trailing slashes and fragments are
+// removed. Fragments that route single page apps, like "#!/post/1", are kept.
+// The result is a lookup key; items keep the URL they were submitted with.
+func CanonicalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid URL %q", rawURL)
+	}
+	if target := ampCacheTarget(u); target != "" {
+		return CanonicalizeURL(target)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for _, prefix := range []string{"www.", "amp."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	params := shareParams[host]
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	path := u.EscapedPath()
+	path = strings.TrimSuffix(path, "/")
+	path = strings.TrimSuffix(path, "/amp")
+	path = strings.TrimRight(path, "/")
+	if path == "" {
+		path = "/"
+	}
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+
+	canonical := &url.URL{Scheme: "https", Host: host, Path: unescaped, RawPath: path}
+	canonical.RawQuery = canonicalQuery(u.RawQuery, params)
+	if strings.HasPrefix(u.Fragment, "!") || strings.HasPrefix(u.Fragment, "/") {
+		canonical.Fragment = u.Fragment
+	}
+	return canonical.String(), nil
+}
+
+// canonicalQuery drops tracking and AMP parameters and sorts the rest
+func canonicalQuery(rawQuery string, hostParams []string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, _ := url.ParseQuery(rawQuery)
+	for key, value := range values {
+		name := strings.ToLower(key)
+		switch {
+		case name == "" || isTrackingParam(name):
+			delete(values, key)
+		case name == "amp" || (name == "outputtype" && len(value) == 1 && strings.EqualFold(value[0], "amp")):
+			delete(values, key)
+		}
+		for _, param := range hostParams {
+			if name == param {
+				delete(values, key)
+			}
+		}
+	}
+	return values.Encode()
+}
+
+func isTrackingParam(name string) bool {
+	if trackingParams[name] {
+		return true
+	}
+	for _, prefix := range trackingParamPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ampCacheTarget returns the article URL behind an AMP cache or Google AMP
+// viewer URL, such as https://example-com.cdn.ampproject.org/c/s/example.com/post
+func ampCacheTarget(u *url.URL) string {
+	host := strings.ToLower(u.Hostname())
+	var rest string
+	switch {
+	case strings.HasSuffix(host, ".cdn.ampproject.org"):
+		rest = strings.TrimPrefix(u.Path, "/c")
+		// Viewer URLs use /v/ for the same path
+		if rest == u.Path {
+			rest = strings.TrimPrefix(u.Path, "/v")
+		}
+	case host == "google.com" || host == "www.google.com":
+		rest = strings.TrimPrefix(u.Path, "/amp")
+	}
+	if rest == "" || rest == u.Path || !strings.HasPrefix(rest, "/") {
+		return ""
+	}
+
+	scheme := "http"
+	if after, ok := strings.CutPrefix(rest, "/s/"); ok {
+		scheme, rest = "https", "/"+after
+	}
+	target := scheme + ":/" + rest
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	return target
+}
+
+// scrapedCanonicalURL is the URL a scraped page identifies itself by: its
+// <link rel=canonical> when it has one, otherwise the URL it redirected to.
+// A canonical link to the site's home page from an article is a common
+// misconfiguration and is ignored.
+func scrapedCanonicalURL(scraped *ScrapeResult) string {
+	if scraped.CanonicalURL != "" {
+		canonical, err := url.Parse(scraped.CanonicalURL)
+		page, pageErr := url.Parse(scraped.URL)
+		homePage := err == nil && strings.Trim(canonical.Path, "/") == "" && canonical.RawQuery == ""
+		if err == nil && (!homePage || (pageErr == nil && strings.Trim(page.Path, "/") == "")) {
+			return scraped.CanonicalURL
+		}
+	}
+	return scraped.URL
+}
+
+// BackfillCanonicalURLs stores the canonical form of every saved URL, for
+// items saved before URLs were canonicalized. Items are visited oldest first,
+// so when several of a user's items are the same page the oldest keeps it and
+// the others are left as they are. Redirects and <link rel=canonical> are not
+// followed. It returns the number of items updated.
+func BackfillCanonicalURLs(ctx context.Context, querier db.Querier, batchSize int32) (int, error) {
+	updated := 0
+	var lastID int32
+	for {
+		rows, err := querier.ListItemURLsAfter(ctx, db.ListItemURLsAfterParams{ID: lastID, Limit: batchSize})
+		if err != nil {
+			return updated, fmt.Errorf("failed to list items: %w", err)
+		}
+		for _, row := range rows {
+			lastID = row.ID
+			canonical, err := CanonicalizeURL(*row.Url)
+			if err != nil {
+				continue
+			}
+			affected, err := querier.BackfillItemCanonicalURL(ctx, db.BackfillItemCanonicalURLParams{ID: row.ID, CanonicalUrl: &canonical})
+			if err != nil && !isUniqueViolation(err) {
+				return updated, fmt.Errorf("failed to update item %d: %w", row.ID, err)
+			}
+			updated += int(affected)
+		}
+		if int32(len(rows)) < batchSize {
+			return updated, nil
+		}
+	}
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
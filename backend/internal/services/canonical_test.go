@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func TestCanonicalizeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"already canonical", "https://example.com/post", "https://example.com/post"},
+		{"scheme, case and www", "HTTP://WWW.Example.COM/post", "https://example.com/post"},
+		{"default port", "https://example.com:443/post", "https://example.com/post"},
+		{"other port kept", "http://localhost:8080/post", "https://localhost:8080/post"},
+		{"trailing slash", "https://example.com/post/", "https://example.com/post"},
+		{"home page", "https://example.com", "https://example.com/"},
+		{"utm parameters", "https://example.com/post?utm_source=newsletter&utm_medium=email", "https://example.com/post"},
+		{"click identifiers", "https://example.com/post?fbclid=abc&gclid=def&mc_eid=ghi", "https://example.com/post"},
+		{"content parameters kept and sorted", "https://example.com/search?q=go&page=2&utm_campaign=x", "https://example.com/search?page=2&q=go"},
+		{"ref kept for code hosts", "https://github.com/org/repo/blob/main/README.md?ref=v1", "https://github.com/org/repo/blob/main/README.md?ref=v1"},
+		{"fragment dropped", "https://example.com/post#comments", "https://example.com/post"},
+		{"hash route kept", "https://app.example.com/#!/post/1", "https://app.example.com/#!/post/1"},
+		{"amp path", "https://example.com/news/story/amp/", "https://example.com/news/story"},
+		{"amp parameter", "https://example.com/news/story?amp=1", "https://example.com/news/story"},
+		{"amp subdomain", "https://amp.example.com/news/story", "https://example.com/news/story"},
+		{"amp cache", "https://example-com.cdn.ampproject.org/c/s/example.com/news/story", "https://example.com/news/story"},
+		{"google amp viewer", "https://www.google.com/amp/s/www.example.com/news/story/amp", "https://example.com/news/story"},
+		{"youtube share", "https://www.youtube.com/watch?v=dQw4w9WgXcQ&si=abc123", "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"share parameter kept on other hosts", "https://example.com/docs?si=2", "https://example.com/docs?si=2"},
+		{"escaped path", "https://example.com/caf%C3%A9/", "https://example.com/caf%C3%A9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, err := CanonicalizeURL(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, canonical)
+		})
+	}
+}
+
+func TestCanonicalizeURL_Invalid(t *testing.T) {
+	for _, rawURL := range []string{"", "not a url", "ftp://example.com/file", "mailto:someone@example.com", "https://"} {
+		_, err := CanonicalizeURL(rawURL)
+		assert.Error(t, err, rawURL)
+	}
+}
+
+func TestScrapedCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		scraped  ScrapeResult
+		expected string
+	}{
+		{"canonical link", ScrapeResult{URL: "https://m.example.com/post?id=1", CanonicalURL: "https://example.com/post"}, "https://example.com/post"},
+		{"final URL without canonical link", ScrapeResult{URL: "https://example.com/post"}, "https://example.com/post"},
+		{"home page canonical on an article", ScrapeResult{URL: "https://example.com/post", CanonicalURL: "https://example.com/"}, "https://example.com/post"},
+		{"home page canonical on the home page", ScrapeResult{URL: "https://example.com/?lang=en", CanonicalURL: "https://example.com/"}, "https://example.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, scrapedCanonicalURL(&tt.scraped))
+		})
+	}
+}
+
+func TestBackfillCanonicalURLs(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	ctx := context.Background()
+	userID := int32(1)
+	tracked := "https://www.example.com/post/?utm_source=newsletter"
+	plain := "https://example.com/post"
+	invalid := "not a url"
+	canonical := "https://example.com/post"
+
+	mockQuerier.On("ListItemURLsAfter", ctx, db.ListItemURLsAfterParams{ID: 0, Limit: 2}).Return([]db.ListItemURLsAfterRow{
+		{ID: 1, UserID: &userID, Url: &tracked},
+		{ID: 2, UserID: &userID, Url: &plain},
+	}, nil)
+	mockQuerier.On("ListItemURLsAfter", ctx, db.ListItemURLsAfterParams{ID: 2, Limit: 2}).Return([]db.ListItemURLsAfterRow{
+		{ID: 3, UserID: &userID, Url: &invalid},
+	}, nil)
+	mockQuerier.On("BackfillItemCanonicalURL", ctx, db.BackfillItemCanonicalURLParams{ID: 1, CanonicalUrl: &canonical}).Return(int64(1), nil)
+	// The older item already has the canonical URL
+	mockQuerier.On("BackfillItemCanonicalURL", ctx, db.BackfillItemCanonicalURLParams{ID: 2, CanonicalUrl: &canonical}).Return(int64(0), nil)
+
+	updated, err := BackfillCanonicalURLs(ctx, mockQuerier, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNumberOfCalls(t, "BackfillItemCanonicalURL", 2)
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// Collection errors returned by CollectionService
+var (
+	ErrInvalidCollection  = errors.New("invalid collection")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrCollectionExists   = errors.New("a collection with this name already exists")
+)
+
+// CollectionService manages collections, ordered lists of items a user
+// curates such as "Q3 research". An item can be in any number of the user's
+// collections, and workspace items the user can see can be added too.
+type CollectionService interface {
+	CreateCollection(ctx context.Context, userID int32, name string, description *string) (*db.Collection, error)
+	ListCollections(ctx context.Context, userID int32) ([]db.ListCollectionsByUserRow, error)
+	GetCollection(ctx context.Context, userID int32, collectionID int32) (*db.Collection, error)
+	// UpdateCollection changes the name and description given; an empty description clears it
+	UpdateCollection(ctx context.Context, userID int32, collectionID int32, name *string, description *string) (*db.Collection, error)
+	DeleteCollection(ctx context.Context, userID int32, collectionID int32) error
+
+	// Membership
+	ListItems(ctx context.Context, userID int32, collectionID int32) ([]db.ListCollectionItemsRow, error)
+	AddItem(ctx context.Context, userID int32, collectionID int32, itemID int32) error
+	RemoveItem(ctx context.Context, userID int32, collectionID int32, itemID int32) error
+	// ReorderItems moves the given items to the front of the collection in the order given
+	ReorderItems(ctx context.Context, userID int32, collectionID int32, itemIDs []int32) ([]db.ListCollectionItemsRow, error)
+	ListCollectionsForItem(ctx context.Context, userID int32, itemID int32) ([]db.Collection, error)
+
+	// Podcasts and digests of a collection's items, in collection order
+	CreatePodcast(ctx context.Context, userID int32, collectionID int32) (*db.Podcast, error)
+	SendDigest(ctx context.Context, userID int32, collectionID int32) (*DigestResult, error)
+	SetDigestService(digestService DigestService)
+}
+
+type collectionService struct {
+	querier        db.Querier
+	podcastService PodcastService
+	digestService  DigestService
+}
+
+func NewCollectionService(querier db.Querier, podcastService PodcastService) CollectionService {
+	return &collectionService{
+		querier:        querier,
+		podcastService: podcastService,
+	}
+}
+
+// SetDigestService enables emailing digests of collections
+func (s *collectionService) SetDigestService(digestService DigestService) {
+	s.digestService = digestService
+}
+
+func (s *collectionService) CreateCollection(ctx context.Context, userID int32, name string, description *string) (*db.Collection, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidCollection)
+	}
+
+	collection, err := s.querier.CreateCollection(ctx, db.CreateCollectionParams{
+		UserID:      userID,
+		Name:        name,
+		Description: normalizeCollectionDescription(description),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrCollectionExists
+		}
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	return &collection, nil
+}
+
+func (s *collectionService) ListCollections(ctx context.Context, userID int32) ([]db.ListCollectionsByUserRow, error) {
+	collections, err := s.querier.ListCollectionsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	return collections, nil
+}
+
+func (s *collectionService) GetCollection(ctx context.Context, userID int32, collectionID int32) (*db.Collection, error) {
+	collection, err := s.querier.GetCollectionByUser(ctx, db.GetCollectionByUserParams{ID: collectionID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return &collection, nil
+}
+
+func (s *collectionService) UpdateCollection(ctx context.Context, userID int32, collectionID int32, name *string, description *string) (*db.Collection, error) {
+	collection, err := s.GetCollection(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.UpdateCollectionParams{
+		ID:          collectionID,
+		UserID:      userID,
+		Name:        collection.Name,
+		Description: collection.Description,
+	}
+	if name != nil {
+		params.Name = strings.TrimSpace(*name)
+		if params.Name == "" {
+			return nil, fmt.Errorf("%w: name cannot be empty", ErrInvalidCollection)
+		}
+	}
+	if description != nil {
+		params.Description = normalizeCollectionDescription(description)
+	}
+
+	updated, err := s.querier.UpdateCollection(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCollectionNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrCollectionExists
+		}
+		return nil, fmt.Errorf("failed to update collection: %w", err)
+	}
+	return &updated, nil
+}
+
+// DeleteCollection removes the collection; its items are left as they are
+func (s *collectionService) DeleteCollection(ctx context.Context, userID int32, collectionID int32) error {
+	deleted, err := s.querier.DeleteCollection(ctx, db.DeleteCollectionParams{ID: collectionID, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	if deleted == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// ListItems returns the collection's items in order, leaving out items the
+// user can no longer see
+func (s *collectionService) ListItems(ctx context.Context, userID int32, collectionID int32) ([]db.ListCollectionItemsRow, error) {
+	if _, err := s.GetCollection(ctx, userID, collectionID); err != nil {
+		return nil, err
+	}
+	return s.listItems(ctx, userID, collectionID)
+}
+
+func (s *collectionService) listItems(ctx context.Context, userID int32, collectionID int32) ([]db.ListCollectionItemsRow, error) {
+	items, err := s.querier.ListCollectionItems(ctx, db.ListCollectionItemsParams{UserID: userID, CollectionID: collectionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection items: %w", err)
+	}
+	return items, nil
+}
+
+// AddItem appends the item to the collection. Adding an item already in the
+// collection leaves it where it is.
+func (s *collectionService) AddItem(ctx context.Context, userID int32, collectionID int32, itemID int32) error {
+	if _, err := s.GetCollection(ctx, userID, collectionID); err != nil {
+		return err
+	}
+	if _, err := s.querier.GetItemVisibleToUser(ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrItemNotFound
+		}
+		return fmt.Errorf("failed to get item: %w", err)
+	}
+
+	if err := s.querier.AddItemToCollection(ctx, db.AddItemToCollectionParams{CollectionID: collectionID, ItemID: itemID}); err != nil {
+		return fmt.Errorf("failed to add item to collection: %w", err)
+	}
+	return nil
+}
+
+func (s *collectionService) RemoveItem(ctx context.Context, userID int32, collectionID int32, itemID int32) error {
+	if _, err := s.GetCollection(ctx, userID, collectionID); err != nil {
+		return err
+	}
+
+	removed, err := s.querier.RemoveItemFromCollection(ctx, db.RemoveItemFromCollectionParams{CollectionID: collectionID, ItemID: itemID})
+	if err != nil {
+		return fmt.Errorf("failed to remove item from collection: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("%w: item %d is not in this collection", ErrItemNotFound, itemID)
+	}
+	return nil
+}
+
+func (s *collectionService) ReorderItems(ctx context.Context, userID int32, collectionID int32, itemIDs []int32) ([]db.ListCollectionItemsRow, error) {
+	if len(itemIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", ErrInvalidCollection)
+	}
+	items, err := s.ListItems(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int32]db.ListCollectionItemsRow, len(items))
+	for _, item := range items {
+		byID[item.Item.ID] = item
+	}
+	ordered := make([]db.ListCollectionItemsRow, 0, len(items))
+	for _, itemID := range itemIDs {
+		item, ok := byID[itemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: item %d is not in this collection or is listed twice", ErrInvalidCollection, itemID)
+		}
+		delete(byID, itemID)
+		ordered = append(ordered, item)
+	}
+	// Items not listed keep their relative order after the listed ones
+	for _, item := range items {
+		if _, ok := byID[item.Item.ID]; ok {
+			ordered = append(ordered, item)
+		}
+	}
+
+	positions := make([]int32, len(ordered))
+	for i, item := range ordered {
+		positions[i] = item.Item.ID
+	}
+	if err := s.querier.SetCollectionItemPositions(ctx, db.SetCollectionItemPositionsParams{
+		ItemIds:      positions,
+		CollectionID: collectionID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to reorder collection items: %w", err)
+	}
+	return ordered, nil
+}
+
+// ListCollectionsForItem returns the user's collections the item is in
+func (s *collectionService) ListCollectionsForItem(ctx context.Context, userID int32, itemID int32) ([]db.Collection, error) {
+	if _, err := s.querier.GetItemVisibleToUser(ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	collections, err := s.querier.ListCollectionsForItem(ctx, db.ListCollectionsForItemParams{UserID: userID, ItemID: itemID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	return collections, nil
+}
+
+// CreatePodcast queues a podcast of the collection's items, named after the collection
+func (s *collectionService) CreatePodcast(ctx context.Context, userID int32, collectionID int32) (*db.Podcast, error) {
+	collection, items, err := s.collectionWithItems(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := make([]int32, len(items))
+	for i, item := range items {
+		itemIDs[i] = item.ID
+	}
+	description := fmt.Sprintf("A podcast of the %d items in your %s collection", len(items), collection.Name)
+	if collection.Description != nil {
+		description = *collection.Description
+	}
+
+	return s.podcastService.CreatePodcastFromItems(ctx, userID, collection.Name, description, itemIDs)
+}
+
+// SendDigest emails the user a digest of the collection's items, with a
+// podcast of them when the user's digests include one
+func (s *collectionService) SendDigest(ctx context.Context, userID int32, collectionID int32) (*DigestResult, error) {
+	if s.digestService == nil {
+		return nil, fmt.Errorf("digest service not available")
+	}
+	collection, items, err := s.collectionWithItems(ctx, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.digestService.SendCollectionDigest(ctx, userID, collection.Name, items)
+}
+
+// collectionWithItems returns the collection and its items in order,
+// refusing an empty collection
+func (s *collectionService) collectionWithItems(ctx context.Context, userID int32, collectionID int32) (*db.Collection, []db.Item, error) {
+	collection, err := s.GetCollection(ctx, userID, collectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := s.listItems(ctx, userID, collectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("%w: collection has no items", ErrInvalidCollection)
+	}
+
+	items := make([]db.Item, len(rows))
+	for i, row := range rows {
+		items[i] = row.Item
+	}
+	return collection, items, nil
+}
+
+// normalizeCollectionDescription trims a description, dropping an empty one
+func normalizeCollectionDescription(description *string) *string {
+	if description == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*description)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
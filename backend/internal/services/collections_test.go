@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func TestCreateCollection(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+	description := "Reading for the planning review"
+
+	mockQuerier.On("CreateCollection", ctx, db.CreateCollectionParams{
+		UserID:      1,
+		Name:        "Q3 research",
+		Description: &description,
+	}).Return(db.Collection{ID: 4, UserID: 1, Name: "Q3 research", Description: &description}, nil)
+
+	collection, err := service.CreateCollection(ctx, 1, "  Q3 research ", stringPtr(" Reading for the planning review "))
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), collection.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestCreateCollection_Invalid(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+
+	_, err := service.CreateCollection(ctx, 1, "  ", nil)
+	assert.True(t, errors.Is(err, ErrInvalidCollection))
+
+	mockQuerier.On("CreateCollection", ctx, db.CreateCollectionParams{UserID: 1, Name: "Reading"}).
+		Return(db.Collection{}, &pgconn.PgError{Code: "23505"})
+
+	_, err = service.CreateCollection(ctx, 1, "Reading", stringPtr(""))
+	assert.True(t, errors.Is(err, ErrCollectionExists), "got %v", err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestUpdateCollection(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+	description := "Old notes"
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 1}).
+		Return(db.Collection{ID: 4, UserID: 1, Name: "Reading", Description: &description}, nil)
+	mockQuerier.On("UpdateCollection", ctx, db.UpdateCollectionParams{
+		ID:     4,
+		UserID: 1,
+		Name:   "Reading",
+	}).Return(db.Collection{ID: 4, UserID: 1, Name: "Reading"}, nil)
+
+	collection, err := service.UpdateCollection(ctx, 1, 4, nil, stringPtr(""))
+
+	require.NoError(t, err)
+	assert.Nil(t, collection.Description, "an empty description clears it")
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestUpdateCollection_NotFound(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 2}).
+		Return(db.Collection{}, pgx.ErrNoRows)
+
+	_, err := service.UpdateCollection(ctx, 2, 4, stringPtr("Mine now"), nil)
+
+	assert.True(t, errors.Is(err, ErrCollectionNotFound))
+	mockQuerier.AssertNotCalled(t, "UpdateCollection", mock.Anything, mock.Anything)
+}
+
+func TestDeleteCollection_NotFound(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+
+	mockQuerier.On("DeleteCollection", ctx, db.DeleteCollectionParams{ID: 4, UserID: 1}).Return(int64(0), nil)
+
+	err := service.DeleteCollection(ctx, 1, 4)
+
+	assert.True(t, errors.Is(err, ErrCollectionNotFound))
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAddItemToCollection(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: userID}).
+		Return(db.Collection{ID: 4, UserID: userID, Name: "Reading"}, nil)
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: 9, UserID: &userID}).
+		Return(db.Item{ID: 9}, nil)
+	mockQuerier.On("AddItemToCollection", ctx, db.AddItemToCollectionParams{CollectionID: 4, ItemID: 9}).Return(nil)
+
+	require.NoError(t, service.AddItem(ctx, userID, 4, 9))
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAddItemToCollection_ItemNotVisible(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: userID}).
+		Return(db.Collection{ID: 4, UserID: userID, Name: "Reading"}, nil)
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: 9, UserID: &userID}).
+		Return(db.Item{}, pgx.ErrNoRows)
+
+	err := service.AddItem(ctx, userID, 4, 9)
+
+	assert.True(t, errors.Is(err, ErrItemNotFound))
+	mockQuerier.AssertNotCalled(t, "AddItemToCollection", mock.Anything, mock.Anything)
+}
+
+func TestRemoveItemFromCollection_NotInCollection(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 1}).
+		Return(db.Collection{ID: 4, UserID: 1, Name: "Reading"}, nil)
+	mockQuerier.On("RemoveItemFromCollection", ctx, db.RemoveItemFromCollectionParams{CollectionID: 4, ItemID: 9}).
+		Return(int64(0), nil)
+
+	err := service.RemoveItem(ctx, 1, 4, 9)
+
+	assert.True(t, errors.Is(err, ErrItemNotFound))
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestReorderCollectionItems(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 1}).
+		Return(db.Collection{ID: 4, UserID: 1, Name: "Reading"}, nil)
+	mockQuerier.On("ListCollectionItems", ctx, db.ListCollectionItemsParams{UserID: 1, CollectionID: 4}).
+		Return([]db.ListCollectionItemsRow{
+			{Item: db.Item{ID: 1}},
+			{Item: db.Item{ID: 2}, IsRead: true},
+			{Item: db.Item{ID: 3}},
+			{Item: db.Item{ID: 4}},
+		}, nil)
+	mockQuerier.On("SetCollectionItemPositions", ctx, db.SetCollectionItemPositionsParams{
+		ItemIds:      []int32{3, 1, 2, 4},
+		CollectionID: 4,
+	}).Return(nil)
+
+	items, err := service.ReorderItems(ctx, 1, 4, []int32{3, 1})
+
+	require.NoError(t, err)
+	require.Len(t, items, 4)
+	assert.Equal(t, int32(3), items[0].Item.ID)
+	assert.Equal(t, int32(2), items[2].Item.ID, "unlisted items keep their order after the listed ones")
+	assert.True(t, items[2].IsRead)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestReorderCollectionItems_Invalid(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 1}).
+		Return(db.Collection{ID: 4, UserID: 1, Name: "Reading"}, nil)
+	mockQuerier.On("ListCollectionItems", ctx, db.ListCollectionItemsParams{UserID: 1, CollectionID: 4}).
+		Return([]db.ListCollectionItemsRow{{Item: db.Item{ID: 1}}, {Item: db.Item{ID: 2}}}, nil)
+
+	_, err := service.ReorderItems(ctx, 1, 4, []int32{2, 7})
+	assert.True(t, errors.Is(err, ErrInvalidCollection), "an item outside the collection")
+
+	_, err = service.ReorderItems(ctx, 1, 4, []int32{2, 2})
+	assert.True(t, errors.Is(err, ErrInvalidCollection), "an item listed twice")
+
+	mockQuerier.AssertNotCalled(t, "SetCollectionItemPositions", mock.Anything, mock.Anything)
+}
+
+func TestCreatePodcastFromCollection(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockPodcast := new(MockPodcastService)
+	service := NewCollectionService(mockQuerier, mockPodcast)
+	ctx := context.Background()
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 1}).
+		Return(db.Collection{ID: 4, UserID: 1, Name: "Q3 research"}, nil)
+	mockQuerier.On("ListCollectionItems", ctx, db.ListCollectionItemsParams{UserID: 1, CollectionID: 4}).
+		Return([]db.ListCollectionItemsRow{{Item: db.Item{ID: 7}}, {Item: db.Item{ID: 3}}}, nil)
+	mockPodcast.On("CreatePodcastFromItems", ctx, int32(1), "Q3 research",
+		"A podcast of the 2 items in your Q3 research collection", []int32{7, 3}).
+		Return(&db.Podcast{ID: 11, Title: "Q3 research", Status: string(PodcastStatusPending)}, nil)
+
+	podcast, err := service.CreatePodcast(ctx, 1, 4)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(11), podcast.ID)
+	mockQuerier.AssertExpectations(t)
+	mockPodcast.AssertExpectations(t)
+}
+
+func TestCreatePodcastFromCollection_Empty(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockPodcast := new(MockPodcastService)
+	service := NewCollectionService(mockQuerier, mockPodcast)
+	ctx := context.Background()
+
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 1}).
+		Return(db.Collection{ID: 4, UserID: 1, Name: "Q3 research"}, nil)
+	mockQuerier.On("ListCollectionItems", ctx, db.ListCollectionItemsParams{UserID: 1, CollectionID: 4}).
+		Return([]db.ListCollectionItemsRow{}, nil)
+
+	_, err := service.CreatePodcast(ctx, 1, 4)
+
+	assert.True(t, errors.Is(err, ErrInvalidCollection))
+	mockPodcast.AssertNotCalled(t, "CreatePodcastFromItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendDigestFromCollection(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockDigest := new(MockDigestService)
+	service := NewCollectionService(mockQuerier, nil)
+	ctx := context.Background()
+	items := []db.Item{{ID: 7, Title: "First"}, {ID: 3, Title: "Second"}}
+
+	_, err := service.SendDigest(ctx, 1, 4)
+	assert.Error(t, err, "digests need the digest service")
+
+	service.SetDigestService(mockDigest)
+	mockQuerier.On("GetCollectionByUser", ctx, db.GetCollectionByUserParams{ID: 4, UserID: 1}).
+		Return(db.Collection{ID: 4, UserID: 1, Name: "Q3 research"}, nil)
+	mockQuerier.On("ListCollectionItems", ctx, db.ListCollectionItemsParams{UserID: 1, CollectionID: 4}).
+		Return([]db.ListCollectionItemsRow{{Item: items[0], IsRead: true}, {Item: items[1]}}, nil)
+	mockDigest.On("SendCollectionDigest", ctx, int32(1), "Q3 research", items).
+		Return(&DigestResult{EmailSent: true, ItemsCount: 2, DigestType: "collection"}, nil)
+
+	result, err := service.SendDigest(ctx, 1, 4)
+
+	require.NoError(t, err)
+	assert.True(t, result.EmailSent)
+	mockQuerier.AssertExpectations(t)
+	mockDigest.AssertExpectations(t)
+}
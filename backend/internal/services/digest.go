@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/yamirghofran/briefbot/internal/db"
@@ -20,10 +20,16 @@ type DigestService interface {
 	// Integrated digest methods (with podcast)
 	SendIntegratedDigest(ctx context.Context) error
 	SendIntegratedDigestForUser(ctx context.Context, userID int32) (*DigestResult, error)
+	// SendCollectionDigest sends an integrated digest of the given items, titled after a collection
+	SendCollectionDigest(ctx context.Context, userID int32, name string, items []db.Item) (*DigestResult, error)
+
+	// Scheduled delivery driven by each user's preferences
+	SendDueDigests(ctx context.Context, now time.Time) error
 
 	// Configuration methods
 	SetPodcastGenerationEnabled(enabled bool)
 	IsPodcastGenerationEnabled() bool
+	SetPreferencesService(preferencesService PreferencesService)
 }
 
 // DigestResult contains the result of sending a digest (regular or integrated)
@@ -32,7 +38,7 @@ type DigestResult struct {
 	PodcastURL *string
 	ItemsCount int
 	Error      error
-	DigestType string // "regular" or "integrated"
+	DigestType string // "regular", "integrated" or "collection"
 }
 
 type digestService struct {
@@ -41,33 +47,34 @@ type digestService struct {
 	podcastService PodcastService
 	config         DigestConfig
 	podcastEnabled bool
+
+	preferencesService PreferencesService
 }
 
 type DigestConfig struct {
-	Subject        string
-	PodcastEnabled bool // Global setting for podcast generation
+	Subject        string // fmt format with one %s for the date
+	PodcastEnabled bool   // Global setting for podcast generation
 }
 
-// NewDigestService creates a new unified digest service
-func NewDigestService(queries db.Querier, emailService EmailService, podcastService PodcastService) DigestService {
-	// Load configuration from environment variables
-	subject := os.Getenv("DAILY_DIGEST_SUBJECT")
-	if subject == "" {
-		subject = "Your Daily BriefBot Digest - %s"
+// DefaultDigestConfig returns the default digest subject with podcasts disabled
+func DefaultDigestConfig() DigestConfig {
+	return DigestConfig{
+		Subject: "Your Daily BriefBot Digest - %s",
 	}
+}
 
-	// Check if podcast generation is globally enabled
-	podcastEnabled := os.Getenv("DIGEST_PODCAST_ENABLED") == "true"
+// NewDigestService creates a new unified digest service
+func NewDigestService(queries db.Querier, emailService EmailService, podcastService PodcastService, config DigestConfig) DigestService {
+	if config.Subject == "" {
+		config.Subject = DefaultDigestConfig().Subject
+	}
 
 	return &digestService{
 		queries:        queries,
 		emailService:   emailService,
 		podcastService: podcastService,
-		config: DigestConfig{
-			Subject:        subject,
-			PodcastEnabled: podcastEnabled,
-		},
-		podcastEnabled: podcastEnabled,
+		config:         config,
+		podcastEnabled: config.PodcastEnabled,
 	}
 }
 
@@ -81,6 +88,73 @@ func (s *digestService) IsPodcastGenerationEnabled() bool {
 	return s.podcastEnabled
 }
 
+// SetPreferencesService enables per-user digest frequency, timezone and podcast settings
+func (s *digestService) SetPreferencesService(preferencesService PreferencesService) {
+	s.preferencesService = preferencesService
+}
+
+// preferencesFor loads the user's preferences, falling back to defaults
+func (s *digestService) preferencesFor(ctx context.Context, userID int32) db.UserPreference {
+	if s.preferencesService == nil {
+		return DefaultUserPreferences(userID)
+	}
+	prefs, err := s.preferencesService.GetPreferences(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load preferences for user %d, using defaults: %v", userID, err)
+		return DefaultUserPreferences(userID)
+	}
+	return *prefs
+}
+
+// podcastEnabledFor applies the user's podcast choice, inheriting the global setting when unset
+func (s *digestService) podcastEnabledFor(prefs db.UserPreference) bool {
+	if prefs.PodcastEnabled != nil {
+		return *prefs.PodcastEnabled
+	}
+	return s.podcastEnabled
+}
+
+// SendDueDigests sends integrated digests to every user whose preferred digest time has passed
+func (s *digestService) SendDueDigests(ctx context.Context, now time.Time) error {
+	if s.preferencesService == nil {
+		return fmt.Errorf("preferences service is required for scheduled digests")
+	}
+
+	users, err := s.queries.ListUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	sent := 0
+	for _, user := range users {
+		if user.Email == nil || *user.Email == "" {
+			continue
+		}
+
+		prefs := s.preferencesFor(ctx, user.ID)
+		if !IsDigestDue(prefs, now) {
+			continue
+		}
+
+		result, err := s.sendIntegratedDigest(ctx, user, prefs, now)
+		if err != nil {
+			// Leave the digest unmarked so the next tick retries it
+			log.Printf("Failed to send scheduled digest for user %d: %v", user.ID, err)
+			continue
+		}
+		if result.EmailSent {
+			sent++
+		}
+
+		if err := s.preferencesService.MarkDigestSent(ctx, user.ID, now); err != nil {
+			log.Printf("Failed to record scheduled digest for user %d: %v", user.ID, err)
+		}
+	}
+
+	log.Printf("Scheduled digest run completed: totalUsers=%d, digestsSent=%d", len(users), sent)
+	return nil
+}
+
 // SendDailyDigest sends regular daily digest to all users (backward compatibility)
 func (s *digestService) SendDailyDigest(ctx context.Context) error {
 	users, err := s.queries.ListUsers(ctx)
@@ -90,6 +164,9 @@ func (s *digestService) SendDailyDigest(ctx context.Context) error {
 
 	for _, user := range users {
 		if user.Email != nil && *user.Email != "" {
+			if s.preferencesFor(ctx, user.ID).DigestFrequency == DigestFrequencyOff {
+				continue
+			}
 			if err := s.SendDailyDigestForUser(ctx, user.ID); err != nil {
 				// Log error but continue with other users
 				log.Printf("Failed to send daily digest to user %d: %v", user.ID, err)
@@ -102,8 +179,11 @@ func (s *digestService) SendDailyDigest(ctx context.Context) error {
 
 // SendDailyDigestForUser sends regular daily digest to a specific user (backward compatibility)
 func (s *digestService) SendDailyDigestForUser(ctx context.Context, userID int32) error {
+	prefs := s.preferencesFor(ctx, userID)
+	now := time.Now()
+
 	// Get items for specific user from previous day
-	items, err := s.GetDailyDigestItemsForUser(ctx, userID)
+	items, err := s.digestItems(ctx, userID, prefs, now)
 	if err != nil {
 		return fmt.Errorf("failed to get daily digest items for user %d: %w", userID, err)
 	}
@@ -123,8 +203,8 @@ func (s *digestService) SendDailyDigestForUser(ctx context.Context, userID int32
 		return fmt.Errorf("user %d has no email address", userID)
 	}
 
-	// Generate regular email content (no podcast)
-	yesterday := time.Now().Add(-24 * time.Hour)
+	// Generate regular email content (no podcast), dated in the user's timezone
+	yesterday := now.In(preferenceLocation(prefs)).AddDate(0, 0, -1)
 	htmlBody, textBody := GenerateDailyDigestEmail(items, yesterday)
 
 	// Prepare subject with date
@@ -155,6 +235,9 @@ func (s *digestService) SendIntegratedDigest(ctx context.Context) error {
 	var results []*DigestResult
 	for _, user := range users {
 		if user.Email != nil && *user.Email != "" {
+			if s.preferencesFor(ctx, user.ID).DigestFrequency == DigestFrequencyOff {
+				continue
+			}
 			result, err := s.SendIntegratedDigestForUser(ctx, user.ID)
 			if err != nil {
 				log.Printf("Failed to send integrated digest for user %d: %v", user.ID, err)
@@ -185,28 +268,63 @@ func (s *digestService) SendIntegratedDigest(ctx context.Context) error {
 
 // SendIntegratedDigestForUser sends integrated digest (with podcast) to a specific user
 func (s *digestService) SendIntegratedDigestForUser(ctx context.Context, userID int32) (*DigestResult, error) {
+	// Get user info
+	user, err := s.queries.GetUser(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("failed to get user: %w", err)
+		return &DigestResult{Error: err, DigestType: "integrated"}, err
+	}
+
+	return s.sendIntegratedDigest(ctx, user, s.preferencesFor(ctx, userID), time.Now())
+}
+
+// SendCollectionDigest emails the user the items of a collection in the
+// integrated digest format, whether or not they have been read
+func (s *digestService) SendCollectionDigest(ctx context.Context, userID int32, name string, items []db.Item) (*DigestResult, error) {
 	result := &DigestResult{
-		EmailSent:  false,
-		PodcastURL: nil,
-		ItemsCount: 0,
-		Error:      nil,
-		DigestType: "integrated",
+		ItemsCount: len(items),
+		DigestType: "collection",
 	}
 
-	// Get user info
 	user, err := s.queries.GetUser(ctx, userID)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to get user: %w", err)
 		return result, result.Error
 	}
+	if user.Email == nil || *user.Email == "" {
+		result.Error = fmt.Errorf("user %d has no email address", userID)
+		return result, result.Error
+	}
+	if len(items) == 0 {
+		log.Printf("No items for collection digest for user %d, skipping", userID)
+		return result, nil
+	}
+
+	prefs := s.preferencesFor(ctx, userID)
+	localNow := time.Now().In(preferenceLocation(prefs))
+	description := fmt.Sprintf("The %d items of your %s collection", len(items), name)
+
+	return s.deliverIntegratedDigest(ctx, user, prefs, items, name, description, localNow, result)
+}
+
+// sendIntegratedDigest builds and sends one user's integrated digest as of now
+func (s *digestService) sendIntegratedDigest(ctx context.Context, user db.User, prefs db.UserPreference, now time.Time) (*DigestResult, error) {
+	userID := user.ID
+	result := &DigestResult{
+		EmailSent:  false,
+		PodcastURL: nil,
+		ItemsCount: 0,
+		Error:      nil,
+		DigestType: "integrated",
+	}
 
 	if user.Email == nil || *user.Email == "" {
 		result.Error = fmt.Errorf("user %d has no email address", userID)
 		return result, result.Error
 	}
 
-	// Get unread items from previous day
-	items, err := s.GetDailyDigestItemsForUser(ctx, userID)
+	// Get unread items covered by this digest
+	items, err := s.digestItems(ctx, userID, prefs, now)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to get digest items: %w", err)
 		return result, result.Error
@@ -219,11 +337,26 @@ func (s *digestService) SendIntegratedDigestForUser(ctx context.Context, userID
 		return result, nil
 	}
 
+	localNow := now.In(preferenceLocation(prefs))
+	label := "Daily"
+	if prefs.DigestFrequency == DigestFrequencyWeekly {
+		label = "Weekly"
+	}
+	description := fmt.Sprintf("Your personalized %s digest with %d curated items", strings.ToLower(label), len(items))
+
+	return s.deliverIntegratedDigest(ctx, user, prefs, items, label+" Digest", description, localNow, result)
+}
+
+// deliverIntegratedDigest emails the items to the user under the heading,
+// with a podcast of them at the top when podcasts are enabled for the user
+func (s *digestService) deliverIntegratedDigest(ctx context.Context, user db.User, prefs db.UserPreference, items []db.Item, heading, description string, localNow time.Time, result *DigestResult) (*DigestResult, error) {
+	userID := user.ID
+
 	// Generate podcast from items (if enabled)
 	var podcastURL *string
 	var durationSeconds *int32
 
-	if s.podcastEnabled && s.podcastService != nil && len(items) > 0 {
+	if s.podcastEnabledFor(prefs) && s.podcastService != nil && len(items) > 0 {
 		log.Printf("Generating podcast for integrated digest for user %d with %d items", userID, len(items))
 
 		// Extract item IDs for podcast generation
@@ -233,9 +366,8 @@ func (s *digestService) SendIntegratedDigestForUser(ctx context.Context, userID
 		}
 
 		// Create podcast with meaningful title and description
-		dateStr := time.Now().Format("January 2, 2006")
-		title := fmt.Sprintf("Daily Digest for %s", dateStr)
-		description := fmt.Sprintf("Your personalized daily digest with %d curated items", len(items))
+		dateStr := localNow.Format("January 2, 2006")
+		title := fmt.Sprintf("%s for %s", heading, dateStr)
 
 		podcast, err := s.podcastService.CreatePodcastFromItems(ctx, userID, title, description, itemIDs)
 		if err != nil {
@@ -257,10 +389,10 @@ func (s *digestService) SendIntegratedDigestForUser(ctx context.Context, userID
 	}
 
 	// Generate integrated email with podcast link (if available)
-	htmlBody, textBody := GenerateIntegratedDigestEmail(items, podcastURL, durationSeconds, time.Now())
+	htmlBody, textBody := GenerateIntegratedDigestEmail(items, podcastURL, durationSeconds, localNow)
 
 	// Send email
-	subject := fmt.Sprintf("Daily Digest - %s", time.Now().Format("January 2, 2006"))
+	subject := fmt.Sprintf("%s - %s", heading, localNow.Format("January 2, 2006"))
 	emailReq := EmailRequest{
 		ToAddresses: []string{*user.Email},
 		Subject:     subject,
@@ -279,11 +411,30 @@ func (s *digestService) SendIntegratedDigestForUser(ctx context.Context, userID
 	return result, nil
 }
 
-// GetDailyDigestItemsForUser gets unread items from previous day for a user
+// GetDailyDigestItemsForUser gets the unread items the user's next digest would cover
 func (s *digestService) GetDailyDigestItemsForUser(ctx context.Context, userID int32) ([]db.Item, error) {
-	items, err := s.queries.GetUnreadItemsFromPreviousDayByUser(ctx, &userID)
+	return s.digestItems(ctx, userID, s.preferencesFor(ctx, userID), time.Now())
+}
+
+// digestItems gets unread items from previous day, or from the window set by
+// the user's timezone and digest frequency when preferences are enabled
+func (s *digestService) digestItems(ctx context.Context, userID int32, prefs db.UserPreference, now time.Time) ([]db.Item, error) {
+	if s.preferencesService == nil {
+		items, err := s.queries.GetUnreadItemsFromPreviousDayByUser(ctx, &userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get unread items from previous day for user %d: %w", userID, err)
+		}
+		return items, nil
+	}
+
+	start, end := DigestWindow(prefs, now)
+	items, err := s.queries.GetUnreadItemsByUserInRange(ctx, db.GetUnreadItemsByUserInRangeParams{
+		UserID:      &userID,
+		WindowStart: &start,
+		WindowEnd:   &end,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get unread items from previous day for user %d: %w", userID, err)
+		return nil, fmt.Errorf("failed to get unread digest items for user %d: %w", userID, err)
 	}
 	return items, nil
 }
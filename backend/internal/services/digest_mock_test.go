@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+type MockDigestService struct {
+	mock.Mock
+}
+
+func (m *MockDigestService) SendDailyDigest(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockDigestService) SendDailyDigestForUser(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockDigestService) GetDailyDigestItemsForUser(ctx context.Context, userID int32) ([]db.Item, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockDigestService) SendIntegratedDigest(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockDigestService) SendIntegratedDigestForUser(ctx context.Context, userID int32) (*DigestResult, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DigestResult), args.Error(1)
+}
+
+func (m *MockDigestService) SendCollectionDigest(ctx context.Context, userID int32, name string, items []db.Item) (*DigestResult, error) {
+	args := m.Called(ctx, userID, name, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DigestResult), args.Error(1)
+}
+
+func (m *MockDigestService) SendDueDigests(ctx context.Context, now time.Time) error {
+	args := m.Called(ctx, now)
+	return args.Error(0)
+}
+
+func (m *MockDigestService) SetPodcastGenerationEnabled(enabled bool) {
+	m.Called(enabled)
+}
+
+func (m *MockDigestService) IsPodcastGenerationEnabled() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockDigestService) SetPreferencesService(preferencesService PreferencesService) {
+	m.Called(preferencesService)
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,9 +23,11 @@ func TestSetPodcastGenerationEnabled(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
+
+	// Disabled by default
+	assert.False(t, service.IsPodcastGenerationEnabled())
 
-	// Initially should be false (unless env var is set)
 	service.SetPodcastGenerationEnabled(true)
 	assert.True(t, service.IsPodcastGenerationEnabled())
 
@@ -32,12 +35,19 @@ func TestSetPodcastGenerationEnabled(t *testing.T) {
 	assert.False(t, service.IsPodcastGenerationEnabled())
 }
 
+func TestNewDigestService_Config(t *testing.T) {
+	service := NewDigestService(new(test.MockQuerier), new(MockEmailService), new(MockPodcastService), DigestConfig{PodcastEnabled: true}).(*digestService)
+
+	assert.True(t, service.IsPodcastGenerationEnabled())
+	assert.Equal(t, DefaultDigestConfig().Subject, service.config.Subject, "empty subject falls back to the default")
+}
+
 func TestGetDailyDigestItemsForUser(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 
 	ctx := context.Background()
 	userID := int32(1)
@@ -61,7 +71,7 @@ func TestSendDailyDigestForUser(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 
 	ctx := context.Background()
 	userID := int32(1)
@@ -89,7 +99,7 @@ func TestSendDailyDigestForUser_NoItems(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 
 	ctx := context.Background()
 	userID := int32(1)
@@ -109,7 +119,7 @@ func TestSendDailyDigestForUser_NoEmail(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 
 	ctx := context.Background()
 	userID := int32(1)
@@ -133,7 +143,7 @@ func TestSendDailyDigest(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 
 	ctx := context.Background()
 	email1 := "user1@example.com"
@@ -167,7 +177,7 @@ func TestSendIntegratedDigestForUser_WithoutPodcast(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 	service.SetPodcastGenerationEnabled(false)
 
 	ctx := context.Background()
@@ -198,7 +208,7 @@ func TestSendIntegratedDigestForUser_WithPodcast(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 	service.SetPodcastGenerationEnabled(true)
 
 	ctx := context.Background()
@@ -240,7 +250,7 @@ func TestSendIntegratedDigestForUser_NoItems(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 
 	ctx := context.Background()
 	userID := int32(1)
@@ -259,12 +269,44 @@ func TestSendIntegratedDigestForUser_NoItems(t *testing.T) {
 	mockEmail.AssertNotCalled(t, "SendEmail")
 }
 
+func TestSendCollectionDigest(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmail := new(MockEmailService)
+	mockPodcast := new(MockPodcastService)
+
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
+
+	ctx := context.Background()
+	userID := int32(1)
+	email := "test@example.com"
+
+	items := []db.Item{
+		{ID: 7, Title: "First", Url: stringPtr("https://example.com/1"), CreatedAt: timeNow()},
+		{ID: 3, Title: "Second", Url: stringPtr("https://example.com/2"), CreatedAt: timeNow()},
+	}
+
+	mockQuerier.On("GetUser", ctx, userID).Return(db.User{ID: userID, Email: &email}, nil)
+	mockEmail.On("SendEmail", ctx, mock.MatchedBy(func(req EmailRequest) bool {
+		return strings.HasPrefix(req.Subject, "Q3 research - ") && strings.Contains(req.TextBody, "Second")
+	})).Return(nil)
+
+	result, err := service.SendCollectionDigest(ctx, userID, "Q3 research", items)
+
+	assert.NoError(t, err)
+	assert.True(t, result.EmailSent)
+	assert.Equal(t, 2, result.ItemsCount)
+	assert.Equal(t, "collection", result.DigestType)
+	mockQuerier.AssertNotCalled(t, "GetUnreadItemsFromPreviousDayByUser", mock.Anything, mock.Anything)
+	mockEmail.AssertExpectations(t)
+	mockPodcast.AssertNotCalled(t, "CreatePodcastFromItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestSendIntegratedDigest(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 	service.SetPodcastGenerationEnabled(false)
 
 	ctx := context.Background()
@@ -293,7 +335,7 @@ func TestWaitForPodcastCompletion_Success(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast).(*digestService)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig()).(*digestService)
 
 	ctx := context.Background()
 	podcastID := int32(1)
@@ -320,7 +362,7 @@ func TestWaitForPodcastCompletion_Failed(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast).(*digestService)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig()).(*digestService)
 
 	ctx := context.Background()
 	podcastID := int32(1)
@@ -351,7 +393,7 @@ func TestSendIntegratedDigestForUser_PodcastCreationFails(t *testing.T) {
 	mockEmail := new(MockEmailService)
 	mockPodcast := new(MockPodcastService)
 
-	service := NewDigestService(mockQuerier, mockEmail, mockPodcast)
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
 	service.SetPodcastGenerationEnabled(true)
 
 	ctx := context.Background()
@@ -378,3 +420,124 @@ func TestSendIntegratedDigestForUser_PodcastCreationFails(t *testing.T) {
 	mockEmail.AssertExpectations(t)
 	mockPodcast.AssertExpectations(t)
 }
+
+func TestSendIntegratedDigestForUser_PodcastDisabledByPreferences(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmail := new(MockEmailService)
+	mockPodcast := new(MockPodcastService)
+	mockPreferences := new(MockPreferencesService)
+
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
+	service.SetPodcastGenerationEnabled(true)
+	service.SetPreferencesService(mockPreferences)
+
+	ctx := context.Background()
+	userID := int32(1)
+	email := "test@example.com"
+	disabled := false
+
+	prefs := DefaultUserPreferences(userID)
+	prefs.Timezone = "America/New_York"
+	prefs.PodcastEnabled = &disabled
+
+	items := []db.Item{
+		{ID: 1, Title: "Test Item", Url: stringPtr("https://example.com"), CreatedAt: timeNow()},
+	}
+
+	mockQuerier.On("GetUser", ctx, userID).Return(db.User{ID: userID, Email: &email}, nil)
+	mockPreferences.On("GetPreferences", ctx, userID).Return(&prefs, nil)
+	mockQuerier.On("GetUnreadItemsByUserInRange", ctx, mock.MatchedBy(func(arg db.GetUnreadItemsByUserInRangeParams) bool {
+		// The window spans one local day ending at the user's midnight
+		return *arg.UserID == userID &&
+			arg.WindowEnd.Sub(*arg.WindowStart) >= 23*time.Hour &&
+			arg.WindowEnd.In(preferenceLocation(prefs)).Hour() == 0
+	})).Return(items, nil)
+	mockEmail.On("SendEmail", ctx, mock.Anything).Return(nil)
+
+	result, err := service.SendIntegratedDigestForUser(ctx, userID)
+
+	assert.NoError(t, err)
+	assert.True(t, result.EmailSent)
+	assert.Nil(t, result.PodcastURL)
+	mockQuerier.AssertExpectations(t)
+	mockPodcast.AssertNotCalled(t, "CreatePodcastFromItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendDailyDigest_SkipsUsersWithDigestOff(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmail := new(MockEmailService)
+	mockPodcast := new(MockPodcastService)
+	mockPreferences := new(MockPreferencesService)
+
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
+	service.SetPreferencesService(mockPreferences)
+
+	ctx := context.Background()
+	email := "test@example.com"
+	user := db.User{ID: 1, Email: &email}
+
+	prefs := DefaultUserPreferences(user.ID)
+	prefs.DigestFrequency = DigestFrequencyOff
+
+	mockQuerier.On("ListUsers", ctx).Return([]db.User{user}, nil)
+	mockPreferences.On("GetPreferences", ctx, user.ID).Return(&prefs, nil)
+
+	err := service.SendDailyDigest(ctx)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertNotCalled(t, "GetUnreadItemsByUserInRange", mock.Anything, mock.Anything)
+	mockEmail.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything)
+}
+
+func TestSendDueDigests(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmail := new(MockEmailService)
+	mockPodcast := new(MockPodcastService)
+	mockPreferences := new(MockPreferencesService)
+
+	service := NewDigestService(mockQuerier, mockEmail, mockPodcast, DefaultDigestConfig())
+	service.SetPodcastGenerationEnabled(false)
+	service.SetPreferencesService(mockPreferences)
+
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 4, 9, 30, 0, 0, time.UTC)
+	dueEmail := "due@example.com"
+	laterEmail := "later@example.com"
+	due := db.User{ID: 1, Email: &dueEmail}
+	later := db.User{ID: 2, Email: &laterEmail}
+
+	duePrefs := DefaultUserPreferences(due.ID)
+	laterPrefs := DefaultUserPreferences(later.ID)
+	laterPrefs.DigestTime = "18:00"
+
+	items := []db.Item{
+		{ID: 1, Title: "Test Item", Url: stringPtr("https://example.com"), CreatedAt: timeNow()},
+	}
+
+	mockQuerier.On("ListUsers", ctx).Return([]db.User{due, later}, nil)
+	mockPreferences.On("GetPreferences", ctx, due.ID).Return(&duePrefs, nil)
+	mockPreferences.On("GetPreferences", ctx, later.ID).Return(&laterPrefs, nil)
+	mockQuerier.On("GetUnreadItemsByUserInRange", ctx, mock.MatchedBy(func(arg db.GetUnreadItemsByUserInRangeParams) bool {
+		return *arg.UserID == due.ID
+	})).Return(items, nil)
+	mockEmail.On("SendEmail", ctx, mock.MatchedBy(func(req EmailRequest) bool {
+		return req.ToAddresses[0] == dueEmail && req.Subject == "Daily Digest - March 4, 2025"
+	})).Return(nil)
+	mockPreferences.On("MarkDigestSent", ctx, due.ID, now).Return(nil)
+
+	err := service.SendDueDigests(ctx, now)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+	mockEmail.AssertExpectations(t)
+	mockPreferences.AssertExpectations(t)
+	mockPreferences.AssertNotCalled(t, "MarkDigestSent", ctx, later.ID, mock.Anything)
+}
+
+func TestSendDueDigests_RequiresPreferences(t *testing.T) {
+	service := NewDigestService(new(test.MockQuerier), new(MockEmailService), new(MockPodcastService), DefaultDigestConfig())
+
+	err := service.SendDueDigests(context.Background(), time.Now())
+
+	assert.Error(t, err)
+}
@@ -3,7 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
-	"os"
+	"html"
 	"strings"
 	"time"
 
@@ -35,17 +35,26 @@ type emailService struct {
 	replyToEmail string
 }
 
-func NewEmailService() (EmailService, error) {
-	// Load from environment variables
-	accessKeyId := os.Getenv("AWS_ACCESS_KEY_ID")
-	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	region := os.Getenv("AWS_REGION")
-	fromEmail := os.Getenv("SES_FROM_EMAIL")
-	fromName := os.Getenv("SES_FROM_NAME")
-	replyToEmail := os.Getenv("SES_REPLY_TO_EMAIL")
+// EmailConfig holds the AWS SES credentials and sender identity
+type EmailConfig struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+	FromEmail       string
+	FromName        string // Defaults to "BriefBot"
+	ReplyToEmail    string // Defaults to FromEmail
+}
+
+func NewEmailService(emailConfig EmailConfig) (EmailService, error) {
+	accessKeyId := emailConfig.AccessKeyID
+	secretAccessKey := emailConfig.SecretAccessKey
+	region := emailConfig.Region
+	fromEmail := emailConfig.FromEmail
+	fromName := emailConfig.FromName
+	replyToEmail := emailConfig.ReplyToEmail
 
 	if accessKeyId == "" || secretAccessKey == "" || region == "" || fromEmail == "" {
-		return nil, fmt.Errorf("missing required AWS SES environment variables")
+		return nil, fmt.Errorf("missing required AWS SES configuration")
 	}
 
 	if fromName == "" {
@@ -180,9 +189,9 @@ func generateDailyDigestHTML(items []db.Item, date time.Time) string {
 	for _, item := range items {
 		html.WriteString(fmt.Sprintf(`
     <div class="item">
-        <div class="item-title"><a href="%s" class="item-link">%s</a></div>
+        <div class="item-title">%s</div>
         <div class="item-meta">`,
-			*item.Url, item.Title))
+			itemTitleHTML(item)))
 
 		if item.Platform != nil && *item.Platform != "" {
 			html.WriteString(fmt.Sprintf("%s | ", *item.Platform))
@@ -210,6 +219,15 @@ func generateDailyDigestHTML(items []db.Item, date time.Time) string {
 	return html.String()
 }
 
+// itemTitleHTML links the title to the item's URL. Items created from pasted
+// content or files have no URL, so their title is plain text.
+func itemTitleHTML(item db.Item) string {
+	if item.Url == nil {
+		return item.Title
+	}
+	return fmt.Sprintf(`<a href="%s" class="item-link">%s</a>`, *item.Url, item.Title)
+}
+
 func generateDailyDigestText(items []db.Item, date time.Time) string {
 	var text strings.Builder
 
@@ -219,7 +237,9 @@ func generateDailyDigestText(items []db.Item, date time.Time) string {
 
 	for i, item := range items {
 		text.WriteString(fmt.Sprintf("%d. %s\n", i+1, item.Title))
-		text.WriteString(fmt.Sprintf("   Link: %s\n", *item.Url))
+		if item.Url != nil {
+			text.WriteString(fmt.Sprintf("   Link: %s\n", *item.Url))
+		}
 
 		var meta []string
 		if item.Platform != nil && *item.Platform != "" {
@@ -314,9 +334,9 @@ func generateIntegratedDigestHTML(items []db.Item, podcastURL *string, durationS
 	for _, item := range items {
 		html.WriteString(fmt.Sprintf(`
         <div class="item">
-            <div class="item-title"><a href="%s" class="item-link">%s</a></div>
+            <div class="item-title">%s</div>
             <div class="item-meta">`,
-			*item.Url, item.Title))
+			itemTitleHTML(item)))
 
 		if item.Platform != nil && *item.Platform != "" {
 			html.WriteString(fmt.Sprintf("%s | ", *item.Platform))
@@ -368,7 +388,9 @@ func generateIntegratedDigestText(items []db.Item, podcastURL *string, durationS
 
 	for i, item := range items {
 		text.WriteString(fmt.Sprintf("\n%d. %s\n", i+1, item.Title))
-		text.WriteString(fmt.Sprintf("   Link: %s\n", *item.Url))
+		if item.Url != nil {
+			text.WriteString(fmt.Sprintf("   Link: %s\n", *item.Url))
+		}
 
 		var meta []string
 		if item.Platform != nil && *item.Platform != "" {
@@ -391,3 +413,63 @@ func generateIntegratedDigestText(items []db.Item, podcastURL *string, durationS
 
 	return text.String()
 }
+
+// GeneratePasswordResetEmail generates HTML and text content for a password reset email
+func GeneratePasswordResetEmail(resetURL string, expiresIn time.Duration) (string, string) {
+	validFor := formatExpiry(expiresIn)
+
+	htmlContent := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Reset your BriefBot password</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #f8f9fa; padding: 20px; border-radius: 8px; margin-bottom: 20px; }
+        .header h1 { color: #2c3e50; margin: 0; }
+        .button { display: inline-block; background-color: #007bff; color: #ffffff; padding: 12px 24px; border-radius: 6px; text-decoration: none; }
+        .footer { text-align: center; color: #6c757d; font-size: 12px; margin-top: 30px; padding-top: 20px; border-top: 1px solid #e9ecef; }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>Reset your password</h1>
+    </div>
+    <p>We received a request to reset the password for your BriefBot account.</p>
+    <p><a href="%s" class="button">Choose a new password</a></p>
+    <p>This link expires in %s and can only be used once. If you did not request a reset, you can ignore this email.</p>
+    <div class="footer">
+        <p>Sent by BriefBot - Your personal content curator</p>
+    </div>
+</body>
+</html>`, html.EscapeString(resetURL), validFor)
+
+	var text strings.Builder
+	text.WriteString("Reset your password\n")
+	text.WriteString(strings.Repeat("=", 50) + "\n\n")
+	text.WriteString("We received a request to reset the password for your BriefBot account.\n\n")
+	text.WriteString(fmt.Sprintf("Choose a new password: %s\n\n", resetURL))
+	text.WriteString(fmt.Sprintf("This link expires in %s and can only be used once. If you did not request a reset, you can ignore this email.\n\n", validFor))
+	text.WriteString(strings.Repeat("-", 50) + "\n")
+	text.WriteString("Sent by BriefBot - Your personal content curator\n")
+
+	return htmlContent, text.String()
+}
+
+// formatExpiry renders a link lifetime as "1 hour", "2 hours" or "30 minutes"
+func formatExpiry(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	switch {
+	case minutes >= 60 && minutes%60 == 0:
+		if minutes == 60 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", minutes/60)
+	case minutes == 1:
+		return "1 minute"
+	default:
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+}
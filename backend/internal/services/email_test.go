@@ -1,7 +1,6 @@
 package services
 
 import (
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -418,96 +417,32 @@ func TestGenerateIntegratedDigestText(t *testing.T) {
 	})
 }
 
-func TestNewEmailService_MissingEnvVars(t *testing.T) {
-	// Save original env vars
-	originalAccessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	originalSecretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	originalRegion := os.Getenv("AWS_REGION")
-	originalFromEmail := os.Getenv("SES_FROM_EMAIL")
-	originalFromName := os.Getenv("SES_FROM_NAME")
-	originalReplyTo := os.Getenv("SES_REPLY_TO_EMAIL")
+func TestGenerateIntegratedDigestEmail_ItemWithoutURL(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 1, 14, 10, 30, 0, 0, time.UTC)
+	// Items created from pasted content or files have no URL
+	items := []db.Item{{Title: "Planning Notes", SourceType: SourceTypeMarkdown, CreatedAt: &createdAt}}
 
-	// Unset all required env vars
-	os.Unsetenv("AWS_ACCESS_KEY_ID")
-	os.Unsetenv("AWS_SECRET_ACCESS_KEY")
-	os.Unsetenv("AWS_REGION")
-	os.Unsetenv("SES_FROM_EMAIL")
+	htmlContent, textContent := GenerateIntegratedDigestEmail(items, nil, nil, date)
 
-	defer func() {
-		// Restore original env vars
-		if originalAccessKey != "" {
-			os.Setenv("AWS_ACCESS_KEY_ID", originalAccessKey)
-		}
-		if originalSecretKey != "" {
-			os.Setenv("AWS_SECRET_ACCESS_KEY", originalSecretKey)
-		}
-		if originalRegion != "" {
-			os.Setenv("AWS_REGION", originalRegion)
-		}
-		if originalFromEmail != "" {
-			os.Setenv("SES_FROM_EMAIL", originalFromEmail)
-		}
-		if originalFromName != "" {
-			os.Setenv("SES_FROM_NAME", originalFromName)
-		}
-		if originalReplyTo != "" {
-			os.Setenv("SES_REPLY_TO_EMAIL", originalReplyTo)
-		}
-	}()
+	assert.Contains(t, htmlContent, `<div class="item-title">Planning Notes</div>`)
+	assert.Contains(t, textContent, "1. Planning Notes")
+	assert.NotContains(t, textContent, "Link:")
+}
 
-	_, err := NewEmailService()
+func TestNewEmailService_MissingConfig(t *testing.T) {
+	_, err := NewEmailService(EmailConfig{Region: "us-east-1", FromEmail: "test@example.com"})
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "missing required AWS SES environment variables")
+	assert.Contains(t, err.Error(), "missing required AWS SES configuration")
 }
 
 func TestNewEmailService_WithDefaults(t *testing.T) {
-	// Save original env vars
-	originalAccessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	originalSecretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	originalRegion := os.Getenv("AWS_REGION")
-	originalFromEmail := os.Getenv("SES_FROM_EMAIL")
-	originalFromName := os.Getenv("SES_FROM_NAME")
-	originalReplyTo := os.Getenv("SES_REPLY_TO_EMAIL")
-
-	// Set required vars without optional ones
-	os.Setenv("AWS_ACCESS_KEY_ID", "test-key")
-	os.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
-	os.Setenv("AWS_REGION", "us-east-1")
-	os.Setenv("SES_FROM_EMAIL", "test@example.com")
-	os.Unsetenv("SES_FROM_NAME")
-	os.Unsetenv("SES_REPLY_TO_EMAIL")
-
-	defer func() {
-		// Restore original env vars
-		if originalAccessKey != "" {
-			os.Setenv("AWS_ACCESS_KEY_ID", originalAccessKey)
-		} else {
-			os.Unsetenv("AWS_ACCESS_KEY_ID")
-		}
-		if originalSecretKey != "" {
-			os.Setenv("AWS_SECRET_ACCESS_KEY", originalSecretKey)
-		} else {
-			os.Unsetenv("AWS_SECRET_ACCESS_KEY")
-		}
-		if originalRegion != "" {
-			os.Setenv("AWS_REGION", originalRegion)
-		} else {
-			os.Unsetenv("AWS_REGION")
-		}
-		if originalFromEmail != "" {
-			os.Setenv("SES_FROM_EMAIL", originalFromEmail)
-		} else {
-			os.Unsetenv("SES_FROM_EMAIL")
-		}
-		if originalFromName != "" {
-			os.Setenv("SES_FROM_NAME", originalFromName)
-		}
-		if originalReplyTo != "" {
-			os.Setenv("SES_REPLY_TO_EMAIL", originalReplyTo)
-		}
-	}()
-
-	svc, err := NewEmailService()
+	svc, err := NewEmailService(EmailConfig{
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+		Region:          "us-east-1",
+		FromEmail:       "test@example.com",
+	})
 	assert.NoError(t, err)
 	assert.NotNil(t, svc)
 
@@ -517,3 +452,22 @@ func TestNewEmailService_WithDefaults(t *testing.T) {
 	assert.Equal(t, "BriefBot", emailSvc.fromName)
 	assert.Equal(t, "test@example.com", emailSvc.replyToEmail)
 }
+
+func TestGeneratePasswordResetEmail(t *testing.T) {
+	resetURL := "https://app.example.com/reset-password?token=abc&x=1"
+
+	htmlContent, textContent := GeneratePasswordResetEmail(resetURL, time.Hour)
+
+	assert.Contains(t, htmlContent, "https://app.example.com/reset-password?token=abc&amp;x=1")
+	assert.Contains(t, htmlContent, "1 hour")
+	assert.Contains(t, textContent, resetURL)
+	assert.Contains(t, textContent, "1 hour")
+}
+
+func TestFormatExpiry(t *testing.T) {
+	assert.Equal(t, "1 hour", formatExpiry(time.Hour))
+	assert.Equal(t, "2 hours", formatExpiry(2*time.Hour))
+	assert.Equal(t, "30 minutes", formatExpiry(30*time.Minute))
+	assert.Equal(t, "90 minutes", formatExpiry(90*time.Minute))
+	assert.Equal(t, "1 minute", formatExpiry(time.Minute))
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/openai/openai-go"
+)
+
+// EmbeddingService turns texts into embedding vectors, one per input and in
+// the same order
+type EmbeddingService interface {
+	Embed(ctx context.Context, inputs []string) ([][]float32, error)
+	// Model names the embedding model; vectors of different models are not comparable
+	Model() string
+}
+
+// DefaultEmbeddingModel is the embedding model used when EmbeddingConfig.Model is empty
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+// EmbeddingConfig holds the settings for the OpenAI-compatible embeddings API
+type EmbeddingConfig struct {
+	Model string
+}
+
+type embeddingService struct {
+	client openai.Client
+	model  string
+}
+
+func NewEmbeddingService(oaiClient *openai.Client, config EmbeddingConfig) EmbeddingService {
+	if config.Model == "" {
+		config.Model = DefaultEmbeddingModel
+	}
+	return &embeddingService{
+		client: *oaiClient,
+		model:  config.Model,
+	}
+}
+
+func (s *embeddingService) Model() string {
+	return s.model
+}
+
+func (s *embeddingService) Embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	resp, err := s.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
+		Model: s.model,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+	if len(resp.Data) != len(inputs) {
+		return nil, fmt.Errorf("failed to create embeddings: got %d vectors for %d inputs", len(resp.Data), len(inputs))
+	}
+
+	vectors := make([][]float32, len(inputs))
+	for _, data := range resp.Data {
+		if data.Index < 0 || int(data.Index) >= len(inputs) || vectors[data.Index] != nil {
+			return nil, fmt.Errorf("failed to create embeddings: unexpected vector index %d", data.Index)
+		}
+		vector := make([]float32, len(data.Embedding))
+		for i, value := range data.Embedding {
+			vector[i] = float32(value)
+		}
+		vectors[data.Index] = vector
+	}
+	return vectors, nil
+}
+
+// isRejectedEmbeddingInput reports whether the embeddings API refused the
+// request because of its inputs, as opposed to failing or being unreachable.
+// Such requests fail the same way when retried.
+func isRejectedEmbeddingInput(err error) bool {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEmbeddingService(t *testing.T, handler http.HandlerFunc) EmbeddingService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := openai.NewClient(
+		option.WithBaseURL(server.URL),
+		option.WithAPIKey("test-key"),
+		option.WithMaxRetries(0),
+	)
+	return NewEmbeddingService(&client, EmbeddingConfig{})
+}
+
+func TestEmbeddingService_Embed(t *testing.T) {
+	var request struct {
+		Input []string `json:"input"`
+		Model string   `json:"model"`
+	}
+	service := newTestEmbeddingService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		// Vectors may come back in any order; their index says which input they belong to
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","model":"text-embedding-3-small","data":[
+			{"object":"embedding","index":1,"embedding":[0.5,-0.25]},
+			{"object":"embedding","index":0,"embedding":[1,0]}
+		]}`))
+	})
+
+	vectors, err := service.Embed(context.Background(), []string{"first", "second"})
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultEmbeddingModel, service.Model())
+	assert.Equal(t, DefaultEmbeddingModel, request.Model)
+	assert.Equal(t, []string{"first", "second"}, request.Input)
+	assert.Equal(t, [][]float32{{1, 0}, {0.5, -0.25}}, vectors)
+}
+
+func TestEmbeddingService_Embed_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		rejected bool
+	}{
+		{"input too long", http.StatusBadRequest, `{"error":{"message":"maximum context length exceeded"}}`, true},
+		{"server error", http.StatusInternalServerError, `{"error":{"message":"internal error"}}`, false},
+		{"bad key", http.StatusUnauthorized, `{"error":{"message":"invalid api key"}}`, false},
+		{"missing vector", http.StatusOK, `{"object":"list","data":[{"object":"embedding","index":0,"embedding":[1]}]}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestEmbeddingService(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := service.Embed(context.Background(), []string{"first", "second"})
+
+			require.Error(t, err)
+			assert.Equal(t, tt.rejected, isRejectedEmbeddingInput(err))
+		})
+	}
+}
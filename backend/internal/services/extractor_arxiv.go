@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// extractArxivPaper scrapes the abstract page of an arXiv paper. PDF links
+// are mapped to the abstract page, which has the metadata.
+func extractArxivPaper(u *url.URL, fetch PageFetcher) (*ScrapeResult, error) {
+	id := arxivID(u.Path)
+	if id == "" {
+		return nil, ErrExtractorNotApplicable
+	}
+
+	doc, pageURL, err := fetchDocument(fetch, "https://arxiv.org/abs/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := extractPageMetadata(doc, pageURL)
+
+	var authors []string
+	doc.Find("meta[name='citation_author']").Each(func(_ int, s *goquery.Selection) {
+		authors = append(authors, arxivAuthorName(s.AttrOr("content", "")))
+	})
+	authors = normalizeAuthors(authors)
+
+	abstractBlock := doc.Find("blockquote.abstract").First()
+	abstractBlock.Find(".descriptor").Remove()
+	abstract := normalizeSpace(abstractBlock.Text())
+	if abstract == "" {
+		abstract = metaContent(doc, "citation_abstract")
+	}
+	if abstract == "" {
+		return nil, fmt.Errorf("no abstract found for arXiv paper %s", id)
+	}
+
+	title := firstNonEmpty(metaContent(doc, "citation_title"), strings.TrimPrefix(normalizeSpace(doc.Find("h1.title").Text()), "Title:"), meta.Title)
+	pdfURL := firstNonEmpty(metaContent(doc, "citation_pdf_url"), "https://arxiv.org/pdf/"+id)
+	subjects := normalizeSpace(doc.Find("td.subjects").First().Text())
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s\n\n", title)
+	if len(authors) > 0 {
+		fmt.Fprintf(&content, "**Authors:** %s\n\n", strings.Join(authors, ", "))
+	}
+	content.WriteString("## Abstract\n\n" + abstract + "\n\n")
+	if subjects != "" {
+		fmt.Fprintf(&content, "**Subjects:** %s\n\n", subjects)
+	}
+	fmt.Fprintf(&content, "**PDF:** [%s](%s)", pdfURL, pdfURL)
+
+	result := newScrapeResult(meta, content.String(), pageURL)
+	result.Title = title
+	result.Excerpt = truncateText(abstract, maxExcerptLength)
+	result.Authors = authors
+	result.SiteName = "arXiv"
+	result.PDFURL = pdfURL
+	result.Platform = platformArxiv
+	result.Type = typeResearchPaper
+	if published := parseMetadataDate(strings.ReplaceAll(metaContent(doc, "citation_date"), "/", "-")); published != nil {
+		result.PublishedAt = published
+	}
+	return result, nil
+}
+
+// arxivID reads the paper ID, with any version suffix, from an /abs/ or /pdf/ path
+func arxivID(path string) string {
+	for _, prefix := range []string{"/abs/", "/pdf/"} {
+		if strings.HasPrefix(path, prefix) {
+			id := strings.Trim(strings.TrimPrefix(path, prefix), "/")
+			return strings.TrimSuffix(id, ".pdf")
+		}
+	}
+	return ""
+}
+
+// arxivAuthorName turns the "Last, First" form of citation_author into "First Last"
+func arxivAuthorName(name string) string {
+	last, first, ok := strings.Cut(name, ",")
+	if !ok {
+		return strings.TrimSpace(name)
+	}
+	return strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// githubReservedOwners are top-level GitHub paths that look like owner/repo but are not repositories
+var githubReservedOwners = map[string]bool{
+	"about": true, "apps": true, "collections": true, "enterprise": true, "events": true,
+	"explore": true, "features": true, "login": true, "marketplace": true, "notifications": true,
+	"orgs": true, "pricing": true, "pulls": true, "settings": true, "sponsors": true,
+	"topics": true, "trending": true, "users": true,
+}
+
+// githubRepo is the metadata shown in a repository's sidebar
+type githubRepo struct {
+	Owner       string
+	Name        string
+	Description string
+	Website     string
+	Stars       string
+	Forks       string
+	Language    string
+	Topics      []string
+}
+
+// extractGithubRepo scrapes a repository page into its metadata followed by the README
+func extractGithubRepo(u *url.URL, fetch PageFetcher) (*ScrapeResult, error) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 || githubReservedOwners[strings.ToLower(parts[0])] {
+		return nil, ErrExtractorNotApplicable
+	}
+	repoURL := "https://github.com/" + parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")
+
+	doc, pageURL, err := fetchDocument(fetch, repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := extractPageMetadata(doc, pageURL)
+	repo := parseGithubRepo(doc, pageURL)
+
+	readme := doc.Find("article.markdown-body").First()
+	var readmeMarkdown string
+	if readme.Length() > 0 {
+		// Heading anchors render as empty links next to every heading
+		readme.Find("a.anchor, .anchor-element, svg").Remove()
+		readmeMarkdown = renderMarkdown(readme.Nodes, pageURL)
+	}
+	if readmeMarkdown == "" && repo.Description == "" {
+		return nil, fmt.Errorf("no README or description found for %s/%s", repo.Owner, repo.Name)
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s/%s\n\n", repo.Owner, repo.Name)
+	if repo.Description != "" {
+		content.WriteString(repo.Description + "\n\n")
+	}
+	for _, field := range [][2]string{
+		{"Website", repo.Website},
+		{"Language", repo.Language},
+		{"Stars", repo.Stars},
+		{"Forks", repo.Forks},
+		{"Topics", strings.Join(repo.Topics, ", ")},
+	} {
+		if field[1] != "" {
+			fmt.Fprintf(&content, "- **%s:** %s\n", field[0], field[1])
+		}
+	}
+	if readmeMarkdown != "" {
+		content.WriteString("\n## README\n\n" + readmeMarkdown)
+	}
+
+	result := newScrapeResult(meta, strings.TrimSpace(content.String()), pageURL)
+	result.Title = repo.Owner + "/" + repo.Name
+	result.Authors = []string{repo.Owner}
+	result.SiteName = "GitHub"
+	if repo.Description != "" {
+		result.Excerpt = truncateText(repo.Description, maxExcerptLength)
+	}
+	result.Platform = platformGithub
+	result.Type = typeGithubRepo
+	return result, nil
+}
+
+// parseGithubRepo reads repository metadata from the page, falling back to
+// the URL and OpenGraph tags for anything the layout no longer exposes
+func parseGithubRepo(doc *goquery.Document, pageURL *url.URL) githubRepo {
+	parts := strings.Split(strings.Trim(pageURL.Path, "/"), "/")
+	repo := githubRepo{Owner: parts[0]}
+	if len(parts) > 1 {
+		repo.Name = parts[1]
+	}
+	if nwo := strings.Split(metaContent(doc, "octolytics-dimension-repository_nwo"), "/"); len(nwo) == 2 {
+		repo.Owner, repo.Name = nwo[0], nwo[1]
+	}
+
+	about := doc.Find(".BorderGrid-cell").First()
+	repo.Description = normalizeSpace(about.Find("p.f4").First().Text())
+	if repo.Description == "" {
+		// og:description appends "Contribute to owner/repo development by creating an account on GitHub."
+		description := metaContent(doc, "og:description")
+		if i := strings.Index(description, " Contribute to "); i >= 0 {
+			description = description[:i]
+		}
+		if !strings.HasPrefix(description, "Contribute to ") {
+			repo.Description = strings.TrimSpace(description)
+		}
+	}
+	repo.Website = about.Find("a[role='link'][href^='http'], a.text-bold[href^='http']").First().AttrOr("href", "")
+
+	repo.Stars = githubCounter(doc.Find("#repo-stars-counter-star"))
+	repo.Forks = githubCounter(doc.Find("#repo-network-counter"))
+	repo.Language = normalizeSpace(doc.Find("[itemprop='programmingLanguage'], .BorderGrid-cell .color-fg-default.text-bold.mr-1").First().Text())
+
+	doc.Find("a.topic-tag").Each(func(_ int, s *goquery.Selection) {
+		if topic := normalizeSpace(s.Text()); topic != "" {
+			repo.Topics = append(repo.Topics, topic)
+		}
+	})
+	return repo
+}
+
+// githubCounter prefers the exact count in the title attribute over the abbreviated label
+func githubCounter(s *goquery.Selection) string {
+	return firstNonEmpty(s.First().AttrOr("title", ""), normalizeSpace(s.First().Text()))
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var youtubeVideoID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// transcriptParagraphSeconds is roughly how much speech goes into one transcript paragraph
+const transcriptParagraphSeconds = 60
+
+// youtubePlayerResponse is the subset of ytInitialPlayerResponse used for extraction
+type youtubePlayerResponse struct {
+	VideoDetails struct {
+		VideoID          string `json:"videoId"`
+		Title            string `json:"title"`
+		Author           string `json:"author"`
+		ShortDescription string `json:"shortDescription"`
+		LengthSeconds    string `json:"lengthSeconds"`
+	} `json:"videoDetails"`
+	Microformat struct {
+		PlayerMicroformatRenderer struct {
+			PublishDate string `json:"publishDate"`
+			UploadDate  string `json:"uploadDate"`
+			OwnerName   string `json:"ownerChannelName"`
+		} `json:"playerMicroformatRenderer"`
+	} `json:"microformat"`
+	Captions struct {
+		PlayerCaptionsTracklistRenderer struct {
+			CaptionTracks []youtubeCaptionTrack `json:"captionTracks"`
+		} `json:"playerCaptionsTracklistRenderer"`
+	} `json:"captions"`
+}
+
+type youtubeCaptionTrack struct {
+	BaseURL      string `json:"baseUrl"`
+	LanguageCode string `json:"languageCode"`
+	Kind         string `json:"kind"` // "asr" for automatic captions
+}
+
+// extractYoutubeVideo scrapes a video's title, description and, when
+// captions are available, its transcript
+func extractYoutubeVideo(u *url.URL, fetch PageFetcher) (*ScrapeResult, error) {
+	id := youtubeID(u)
+	if id == "" {
+		return nil, ErrExtractorNotApplicable
+	}
+
+	page, err := fetch("https://www.youtube.com/watch?v=" + id)
+	if err != nil {
+		return nil, err
+	}
+	player, err := parseYoutubePlayerResponse(page.Body)
+	if err != nil {
+		return nil, err
+	}
+	details := player.VideoDetails
+	if details.Title == "" {
+		return nil, fmt.Errorf("no video details found for YouTube video %s", id)
+	}
+
+	var transcript string
+	if track := pickCaptionTrack(player.Captions.PlayerCaptionsTracklistRenderer.CaptionTracks); track != nil {
+		transcript, err = fetchYoutubeTranscript(fetch, track.BaseURL)
+		if err != nil {
+			// The description alone is still worth summarizing
+			log.Printf("Failed to fetch transcript for YouTube video %s: %v", id, err)
+		}
+	}
+
+	channel := firstNonEmpty(details.Author, player.Microformat.PlayerMicroformatRenderer.OwnerName)
+	description := strings.TrimSpace(details.ShortDescription)
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# %s\n\n", details.Title)
+	if channel != "" {
+		fmt.Fprintf(&content, "- **Channel:** %s\n", channel)
+	}
+	if seconds, err := strconv.Atoi(details.LengthSeconds); err == nil && seconds > 0 {
+		fmt.Fprintf(&content, "- **Duration:** %s\n", formatVideoDuration(seconds))
+	}
+	if description != "" {
+		content.WriteString("\n## Description\n\n" + description + "\n")
+	}
+	if transcript != "" {
+		content.WriteString("\n## Transcript\n\n" + transcript + "\n")
+	}
+
+	result := &ScrapeResult{
+		URL:      "https://www.youtube.com/watch?v=" + id,
+		Title:    details.Title,
+		Content:  strings.TrimSpace(content.String()),
+		Excerpt:  firstParagraph(description, maxExcerptLength),
+		SiteName: "YouTube",
+		ImageURL: "https://i.ytimg.com/vi/" + id + "/hqdefault.jpg",
+		Platform: platformYoutube,
+		Type:     typeVideo,
+	}
+	if channel != "" {
+		result.Authors = []string{channel}
+	}
+	microformat := player.Microformat.PlayerMicroformatRenderer
+	result.PublishedAt = parseMetadataDate(firstNonEmpty(microformat.PublishDate, microformat.UploadDate))
+	return result, nil
+}
+
+// youtubeID reads the video ID from watch, shorts, live and youtu.be URLs
+func youtubeID(u *url.URL) string {
+	var id string
+	switch host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."); {
+	case host == "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	case u.Path == "/watch":
+		id = u.Query().Get("v")
+	default:
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) >= 2 && (parts[0] == "shorts" || parts[0] == "live") {
+			id = parts[1]
+		}
+	}
+	if !youtubeVideoID.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// parseYoutubePlayerResponse finds the ytInitialPlayerResponse object embedded in a watch page
+func parseYoutubePlayerResponse(body []byte) (*youtubePlayerResponse, error) {
+	marker := []byte("ytInitialPlayerResponse")
+	for offset := 0; ; {
+		i := bytes.Index(body[offset:], marker)
+		if i < 0 {
+			return nil, errors.New("player response not found in YouTube page")
+		}
+		offset += i + len(marker)
+		start := bytes.IndexByte(body[offset:], '{')
+		// The marker also appears in code that reads the variable
+		if start < 0 || len(bytes.TrimLeft(body[offset:offset+start], " =\t\n'\"]")) > 0 {
+			continue
+		}
+		object := jsonObjectAt(body[offset+start:])
+		if object == nil {
+			return nil, errors.New("player response in YouTube page is truncated")
+		}
+		var player youtubePlayerResponse
+		if err := json.Unmarshal(object, &player); err != nil {
+			return nil, fmt.Errorf("failed to parse YouTube player response: %w", err)
+		}
+		return &player, nil
+	}
+}
+
+// jsonObjectAt returns the JSON object at the start of data by matching
+// braces outside of strings, or nil if it is not closed
+func jsonObjectAt(data []byte) []byte {
+	depth := 0
+	inString, escaped := false, false
+	for i, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return data[:i+1]
+			}
+		}
+	}
+	return nil
+}
+
+// pickCaptionTrack prefers English captions written by a person over
+// automatic ones, then any English track, then whatever exists
+func pickCaptionTrack(tracks []youtubeCaptionTrack) *youtubeCaptionTrack {
+	var english, fallback *youtubeCaptionTrack
+	for i := range tracks {
+		track := &tracks[i]
+		if track.BaseURL == "" {
+			continue
+		}
+		isEnglish := track.LanguageCode == "en" || strings.HasPrefix(track.LanguageCode, "en-")
+		if isEnglish && track.Kind != "asr" {
+			return track
+		}
+		if isEnglish && english == nil {
+			english = track
+		}
+		if fallback == nil {
+			fallback = track
+		}
+	}
+	if english != nil {
+		return english
+	}
+	return fallback
+}
+
+// youtubeTimedText is the XML caption format returned by the timedtext endpoint
+type youtubeTimedText struct {
+	Texts []struct {
+		Start float64 `xml:"start,attr"`
+		Text  string  `xml:",chardata"`
+	} `xml:"text"`
+}
+
+// fetchYoutubeTranscript downloads a caption track and joins it into
+// paragraphs of about transcriptParagraphSeconds each
+func fetchYoutubeTranscript(fetch PageFetcher, trackURL string) (string, error) {
+	page, err := fetch(trackURL)
+	if err != nil {
+		return "", err
+	}
+
+	var timedText youtubeTimedText
+	if err := xml.Unmarshal(page.Body, &timedText); err != nil {
+		return "", fmt.Errorf("failed to parse transcript: %w", err)
+	}
+
+	var paragraphs []string
+	var current []string
+	paragraphStart := 0.0
+	for _, line := range timedText.Texts {
+		// Captions are HTML-escaped a second time inside the XML
+		text := normalizeSpace(html.UnescapeString(line.Text))
+		if text == "" {
+			continue
+		}
+		if len(current) > 0 && line.Start-paragraphStart >= transcriptParagraphSeconds {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+		if len(current) == 0 {
+			paragraphStart = line.Start
+		}
+		current = append(current, text)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, " "))
+	}
+	if len(paragraphs) == 0 {
+		return "", errors.New("transcript is empty")
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+func formatVideoDuration(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
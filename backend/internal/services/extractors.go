@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrExtractorNotApplicable is returned by an extractor when a URL matches its
+// pattern but is not a page it handles, e.g. a GitHub organization page
+var ErrExtractorNotApplicable = errors.New("extractor does not handle this URL")
+
+// Platform and type values from ItemExtraction
+const (
+	platformGithub   = "Github"
+	platformArxiv    = "Arxiv"
+	platformYoutube  = "Youtube"
+	platformMedium   = "Medium"
+	platformSubstack = "Substack"
+
+	typeArticle       = "article"
+	typeGithubRepo    = "github-repo"
+	typeResearchPaper = "research-paper"
+	typeVideo         = "video"
+)
+
+// ContentExtractor scrapes pages of a single platform, using fetch for every request
+type ContentExtractor interface {
+	Extract(u *url.URL, fetch PageFetcher) (*ScrapeResult, error)
+}
+
+// ExtractorFunc adapts a function to ContentExtractor
+type ExtractorFunc func(u *url.URL, fetch PageFetcher) (*ScrapeResult, error)
+
+func (f ExtractorFunc) Extract(u *url.URL, fetch PageFetcher) (*ScrapeResult, error) {
+	return f(u, fetch)
+}
+
+type registeredExtractor struct {
+	name      string
+	pattern   *regexp.Regexp
+	extractor ContentExtractor
+}
+
+// ExtractorRegistry picks a platform extractor by URL pattern. Patterns are
+// matched against the lowercased host and path, without "www." or "m.".
+type ExtractorRegistry struct {
+	extractors []registeredExtractor
+}
+
+func NewExtractorRegistry() *ExtractorRegistry {
+	return &ExtractorRegistry{}
+}
+
+// DefaultExtractorRegistry returns a registry with the built-in platform extractors
+func DefaultExtractorRegistry() *ExtractorRegistry {
+	registry := NewExtractorRegistry()
+	registry.MustRegister("github", `^github\.com/[^/]+/[^/]+/?$`, ExtractorFunc(extractGithubRepo))
+	registry.MustRegister("arxiv", `^(export\.)?arxiv\.org/(abs|pdf)/.+`, ExtractorFunc(extractArxivPaper))
+	registry.MustRegister("youtube", `^(youtube\.com/(watch$|shorts/.+|live/.+)|youtu\.be/.+)`, ExtractorFunc(extractYoutubeVideo))
+	registry.MustRegister("substack", `^[^/]+\.substack\.com/p/.+`, newsletterExtractor(platformSubstack, "div.available-content", "div.subscription-widget-wrap, div.subscribe-widget, div.captioned-button-wrap"))
+	registry.MustRegister("medium", `^([^/]+\.)?medium\.com/.+`, newsletterExtractor(platformMedium, "article", "h1[data-testid='storyTitle'], .pw-post-byline-header, [data-testid='headerClapButton']"))
+	return registry
+}
+
+// Register adds an extractor. Extractors are tried in registration order and
+// the first matching pattern wins.
+func (r *ExtractorRegistry) Register(name, pattern string, extractor ContentExtractor) error {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern for %s extractor: %w", name, err)
+	}
+	r.extractors = append(r.extractors, registeredExtractor{name: name, pattern: compiled, extractor: extractor})
+	return nil
+}
+
+// MustRegister is Register for built-in patterns, panicking if one is invalid
+func (r *ExtractorRegistry) MustRegister(name, pattern string, extractor ContentExtractor) {
+	if err := r.Register(name, pattern, extractor); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the first extractor whose pattern matches u
+func (r *ExtractorRegistry) Lookup(u *url.URL) (string, ContentExtractor, bool) {
+	if u == nil || u.Host == "" {
+		return "", nil, false
+	}
+	key := extractorKey(u)
+	for _, entry := range r.extractors {
+		if entry.pattern.MatchString(key) {
+			return entry.name, entry.extractor, true
+		}
+	}
+	return "", nil, false
+}
+
+// extractorKey is the host and path a URL is matched on
+func extractorKey(u *url.URL) string {
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+	return host + strings.ToLower(u.EscapedPath())
+}
+
+// fetchDocument downloads and parses an HTML page
+func fetchDocument(fetch PageFetcher, rawURL string) (*goquery.Document, *url.URL, error) {
+	page, err := fetch(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	return doc, page.URL, nil
+}
+
+// newsletterExtractor renders the post body container of a blogging
+// platform, which is more reliable there than scoring the page. chromeSelector
+// matches platform widgets inside the container that readability misses.
+func newsletterExtractor(platform, containerSelector, chromeSelector string) ExtractorFunc {
+	return func(u *url.URL, fetch PageFetcher) (*ScrapeResult, error) {
+		doc, pageURL, err := fetchDocument(fetch, u.String())
+		if err != nil {
+			return nil, err
+		}
+
+		meta := extractPageMetadata(doc, pageURL)
+		doc.Find(chromeSelector).Remove()
+		removeBoilerplate(doc)
+
+		container := doc.Find(containerSelector).First()
+		if container.Length() == 0 {
+			return nil, fmt.Errorf("%s post body not found", platform)
+		}
+		content := renderMarkdown(container.Nodes, pageURL)
+		if content == "" {
+			return nil, fmt.Errorf("%s post body is empty", platform)
+		}
+
+		result := newScrapeResult(meta, content, pageURL)
+		result.Platform = platform
+		result.Type = typeArticle
+		return result, nil
+	}
+}
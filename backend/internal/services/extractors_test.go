@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureFetcher serves recorded pages from testdata by URL and records every request
+type fixtureFetcher struct {
+	t        *testing.T
+	pages    map[string]string
+	requests []string
+}
+
+func newFixtureFetcher(t *testing.T, pages map[string]string) *fixtureFetcher {
+	return &fixtureFetcher{t: t, pages: pages}
+}
+
+func (f *fixtureFetcher) fetch(rawURL string) (*FetchedPage, error) {
+	f.requests = append(f.requests, rawURL)
+	name, ok := f.pages[rawURL]
+	if !ok {
+		return nil, errors.New("Not Found")
+	}
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(f.t, err)
+	return &FetchedPage{URL: mustParseURL(f.t, rawURL), StatusCode: 200, Body: body}, nil
+}
+
+func TestExtractorRegistry_Lookup(t *testing.T) {
+	registry := DefaultExtractorRegistry()
+
+	tests := map[string]string{
+		"https://github.com/gocolly/colly":                         "github",
+		"https://www.github.com/gocolly/colly/":                    "github",
+		"https://github.com/gocolly/colly/issues":                  "",
+		"https://github.com/gocolly":                               "",
+		"https://arxiv.org/abs/1706.03762":                         "arxiv",
+		"https://arxiv.org/pdf/1706.03762v7":                       "arxiv",
+		"https://arxiv.org/list/cs.CL/recent":                      "",
+		"https://www.youtube.com/watch?v=oV9rvDllKEg":              "youtube",
+		"https://m.youtube.com/watch?v=oV9rvDllKEg":                "youtube",
+		"https://youtu.be/oV9rvDllKEg":                             "youtube",
+		"https://www.youtube.com/shorts/oV9rvDllKEg":               "youtube",
+		"https://www.youtube.com/@golang":                          "",
+		"https://engnotes.substack.com/p/boring-technology":        "substack",
+		"https://engnotes.substack.com/archive":                    "",
+		"https://medium.com/better-programming/go-interfaces-1a2b": "medium",
+		"https://samokafor.medium.com/go-interfaces-1a2b":          "medium",
+		"https://example.com/blog/post":                            "",
+	}
+
+	for rawURL, expected := range tests {
+		t.Run(rawURL, func(t *testing.T) {
+			name, extractor, ok := registry.Lookup(mustParseURL(t, rawURL))
+
+			assert.Equal(t, expected, name)
+			assert.Equal(t, expected != "", ok)
+			assert.Equal(t, expected != "", extractor != nil)
+		})
+	}
+}
+
+func TestExtractorRegistry_Register(t *testing.T) {
+	registry := NewExtractorRegistry()
+	custom := ExtractorFunc(func(u *url.URL, fetch PageFetcher) (*ScrapeResult, error) {
+		return &ScrapeResult{Title: "custom"}, nil
+	})
+
+	require.NoError(t, registry.Register("custom", `^example\.com/docs/`, custom))
+	assert.Error(t, registry.Register("broken", `(`, custom))
+
+	_, _, ok := registry.Lookup(mustParseURL(t, "https://EXAMPLE.com/Docs/intro"))
+	assert.True(t, ok, "matching is case-insensitive")
+	_, _, ok = registry.Lookup(mustParseURL(t, "https://example.com/blog"))
+	assert.False(t, ok)
+}
+
+func TestExtractGithubRepo(t *testing.T) {
+	fetcher := newFixtureFetcher(t, map[string]string{"https://github.com/gocolly/colly": "github_repo.html"})
+
+	result, err := extractGithubRepo(mustParseURL(t, "https://github.com/gocolly/colly.git"), fetcher.fetch)
+
+	require.NoError(t, err)
+	assert.Equal(t, "gocolly/colly", result.Title)
+	assert.Equal(t, []string{"gocolly"}, result.Authors)
+	assert.Equal(t, "Elegant Scraper and Crawler Framework for Golang", result.Excerpt)
+	assert.Equal(t, platformGithub, result.Platform)
+	assert.Equal(t, typeGithubRepo, result.Type)
+
+	assert.Contains(t, result.Content, "- **Website:** http://go-colly.org/")
+	assert.Contains(t, result.Content, "- **Language:** Go")
+	assert.Contains(t, result.Content, "- **Stars:** 24,817")
+	assert.Contains(t, result.Content, "- **Forks:** 1,796")
+	assert.Contains(t, result.Content, "- **Topics:** go, crawler, scraping")
+	assert.Contains(t, result.Content, "## README\n\n# Colly\n\nLightning Fast and Elegant Scraping Framework for Gophers")
+	assert.Contains(t, result.Content, "- Fast (>1k request/sec on a single core)")
+	assert.Contains(t, result.Content, "c := colly.NewCollector()")
+	assert.Contains(t, result.Content, "[examples folder](https://github.com/gocolly/colly/blob/master/_examples)")
+	assert.NotContains(t, result.Content, "Sign in")
+	assert.NotContains(t, result.Content, "#colly")
+}
+
+func TestExtractGithubRepo_NotARepository(t *testing.T) {
+	fetcher := newFixtureFetcher(t, nil)
+
+	_, err := extractGithubRepo(mustParseURL(t, "https://github.com/topics/go"), fetcher.fetch)
+
+	assert.ErrorIs(t, err, ErrExtractorNotApplicable)
+	assert.Empty(t, fetcher.requests)
+}
+
+func TestExtractArxivPaper(t *testing.T) {
+	fetcher := newFixtureFetcher(t, map[string]string{"https://arxiv.org/abs/1706.03762v7": "arxiv_abs.html"})
+
+	// PDF links are scraped from the abstract page
+	result, err := extractArxivPaper(mustParseURL(t, "https://arxiv.org/pdf/1706.03762v7.pdf"), fetcher.fetch)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://arxiv.org/abs/1706.03762v7"}, fetcher.requests)
+	assert.Equal(t, "Attention Is All You Need", result.Title)
+	assert.Equal(t, []string{"Ashish Vaswani", "Noam Shazeer", "Niki Parmar", "Jakob Uszkoreit"}, result.Authors)
+	assert.Equal(t, "https://arxiv.org/pdf/1706.03762", result.PDFURL)
+	require.NotNil(t, result.PublishedAt)
+	assert.Equal(t, "2017-06-12", result.PublishedAt.Format("2006-01-02"))
+	assert.Equal(t, platformArxiv, result.Platform)
+	assert.Equal(t, typeResearchPaper, result.Type)
+
+	assert.Contains(t, result.Content, "**Authors:** Ashish Vaswani, Noam Shazeer, Niki Parmar, Jakob Uszkoreit")
+	assert.Contains(t, result.Content, "## Abstract\n\nThe dominant sequence transduction models")
+	assert.Contains(t, result.Content, "the Transformer, based solely on attention mechanisms")
+	assert.NotContains(t, result.Content, "Abstract:")
+	assert.Contains(t, result.Content, "**Subjects:** Computation and Language (cs.CL); Machine Learning (cs.LG)")
+	assert.Contains(t, result.Content, "**PDF:** [https://arxiv.org/pdf/1706.03762](https://arxiv.org/pdf/1706.03762)")
+}
+
+func TestArxivAuthorName(t *testing.T) {
+	assert.Equal(t, "Ashish Vaswani", arxivAuthorName("Vaswani, Ashish"))
+	assert.Equal(t, "The ATLAS Collaboration", arxivAuthorName("The ATLAS Collaboration"))
+}
+
+func TestExtractYoutubeVideo(t *testing.T) {
+	fetcher := newFixtureFetcher(t, map[string]string{
+		"https://www.youtube.com/watch?v=oV9rvDllKEg":                 "youtube_watch.html",
+		"https://www.youtube.com/api/timedtext?v=oV9rvDllKEg&lang=en": "youtube_transcript.xml",
+	})
+
+	result, err := extractYoutubeVideo(mustParseURL(t, "https://youtu.be/oV9rvDllKEg?t=42"), fetcher.fetch)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Concurrency is not Parallelism by Rob Pike", result.Title)
+	assert.Equal(t, "https://www.youtube.com/watch?v=oV9rvDllKEg", result.URL)
+	assert.Equal(t, []string{"gnbitcom"}, result.Authors)
+	require.NotNil(t, result.PublishedAt)
+	assert.Equal(t, "2013-10-25T06:57:33Z", result.PublishedAt.Format("2006-01-02T15:04:05Z07:00"))
+	assert.Equal(t, platformYoutube, result.Platform)
+	assert.Equal(t, typeVideo, result.Type)
+
+	assert.Contains(t, result.Content, "- **Duration:** 31:37")
+	assert.Contains(t, result.Content, "## Description\n\nRob Pike explains why concurrency is not parallelism, and how Go's concurrency model {goroutines and channels}")
+	assert.Contains(t, result.Content, "## Transcript\n\nSo I want to talk about concurrency, which isn't the same as parallelism. Concurrency is about \"dealing with\" lots of things at once.\n\nParallelism is about doing lots of things at once. Not the same, but related.")
+}
+
+func TestExtractYoutubeVideo_WithoutTranscript(t *testing.T) {
+	fetcher := newFixtureFetcher(t, map[string]string{"https://www.youtube.com/watch?v=oV9rvDllKEg": "youtube_watch.html"})
+
+	result, err := extractYoutubeVideo(mustParseURL(t, "https://www.youtube.com/watch?v=oV9rvDllKEg"), fetcher.fetch)
+
+	require.NoError(t, err)
+	assert.Contains(t, result.Content, "## Description")
+	assert.NotContains(t, result.Content, "## Transcript")
+}
+
+func TestPickCaptionTrack(t *testing.T) {
+	asr := youtubeCaptionTrack{BaseURL: "asr", LanguageCode: "en", Kind: "asr"}
+	british := youtubeCaptionTrack{BaseURL: "british", LanguageCode: "en-GB"}
+	japanese := youtubeCaptionTrack{BaseURL: "japanese", LanguageCode: "ja"}
+
+	assert.Equal(t, "british", pickCaptionTrack([]youtubeCaptionTrack{japanese, asr, british}).BaseURL)
+	assert.Equal(t, "asr", pickCaptionTrack([]youtubeCaptionTrack{japanese, asr}).BaseURL)
+	assert.Equal(t, "japanese", pickCaptionTrack([]youtubeCaptionTrack{japanese}).BaseURL)
+	assert.Nil(t, pickCaptionTrack(nil))
+}
+
+func TestParseYoutubePlayerResponse_Missing(t *testing.T) {
+	_, err := parseYoutubePlayerResponse([]byte(`<script>if (window.ytInitialPlayerResponse) {}</script>`))
+
+	assert.Error(t, err)
+}
+
+func TestNewsletterExtractors(t *testing.T) {
+	registry := DefaultExtractorRegistry()
+
+	tests := []struct {
+		name        string
+		url         string
+		fixture     string
+		platform    string
+		title       string
+		authors     []string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "substack",
+			url:      "https://engnotes.substack.com/p/boring-technology",
+			fixture:  "substack_post.html",
+			platform: platformSubstack,
+			title:    "The Case for Boring Technology",
+			authors:  []string{"Dana Reyes"},
+			contains: []string{
+				"Every team gets a limited number of innovation tokens.",
+				"## What counts as boring",
+				"**well understood**",
+				"[failure modes](https://example.com/failure-modes)",
+				"> Choose boring technology",
+			},
+			notContains: []string{"Thanks for reading", "Subscribe", "Great post", "Restack", "Privacy"},
+		},
+		{
+			name:     "medium",
+			url:      "https://medium.com/better-programming/understanding-go-interfaces-1a2b3c",
+			fixture:  "medium_post.html",
+			platform: platformMedium,
+			title:    "Understanding Go Interfaces",
+			authors:  []string{"Sam Okafor"},
+			contains: []string{
+				"Interfaces in Go are satisfied implicitly.",
+				"## Keep interfaces small",
+				"`io.Reader`",
+				"Read(p []byte) (n int, err error)",
+			},
+			notContains: []string{"6 min read", "Clap", "Responses", "Get started"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := newFixtureFetcher(t, map[string]string{tt.url: tt.fixture})
+			_, extractor, ok := registry.Lookup(mustParseURL(t, tt.url))
+			require.True(t, ok)
+
+			result, err := extractor.Extract(mustParseURL(t, tt.url), fetcher.fetch)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.title, result.Title)
+			assert.Equal(t, tt.authors, result.Authors)
+			assert.Equal(t, tt.platform, result.Platform)
+			assert.Equal(t, typeArticle, result.Type)
+			assert.NotNil(t, result.PublishedAt)
+			for _, text := range tt.contains {
+				assert.Contains(t, result.Content, text)
+			}
+			for _, text := range tt.notContains {
+				assert.NotContains(t, result.Content, text)
+			}
+		})
+	}
+}
+
+func TestScrape_UsesPlatformExtractor(t *testing.T) {
+	fetcher := newFixtureFetcher(t, map[string]string{"https://github.com/gocolly/colly": "github_repo.html"})
+	scraper := &scrapingService{fetch: fetcher.fetch, extractors: DefaultExtractorRegistry()}
+
+	result, err := scraper.Scrape("https://github.com/gocolly/colly")
+
+	require.NoError(t, err)
+	assert.Equal(t, platformGithub, result.Platform)
+	assert.Equal(t, "gocolly/colly", result.Title)
+}
+
+func TestScrape_FallsBackToGenericScraper(t *testing.T) {
+	fetcher := newFixtureFetcher(t, map[string]string{"https://github.com/gocolly/colly": "article.html"})
+	registry := NewExtractorRegistry()
+	registry.MustRegister("broken", `^github\.com/`, ExtractorFunc(func(u *url.URL, fetch PageFetcher) (*ScrapeResult, error) {
+		return nil, errors.New("layout changed")
+	}))
+	scraper := &scrapingService{fetch: fetcher.fetch, extractors: registry}
+
+	result, err := scraper.Scrape("https://github.com/gocolly/colly")
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Platform)
+	assert.NotEmpty(t, result.Content)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Failure reasons recorded with failed items, so clients can tell a page that
+// was blocked from one that could not be reached or summarized
+const (
+	FailureReasonPaywall      = "paywall"
+	FailureReasonLoginWall    = "login_wall"
+	FailureReasonCaptcha      = "captcha"
+	FailureReasonForbidden    = "forbidden"
+	FailureReasonRobots       = "robots"
+	FailureReasonNotFound     = "not_found"
+	FailureReasonClientError  = "client_error"
+	FailureReasonServerError  = "server_error"
+	FailureReasonEmptyContent = "empty_content"
+	FailureReasonTooLarge     = "too_large"
+	FailureReasonNetwork      = "network"
+	FailureReasonTimeout      = "timeout"
+	FailureReasonParse        = "parse"
+	FailureReasonAI           = "ai"
+	FailureReasonDuplicate    = "duplicate"
+	FailureReasonUnknown      = "unknown"
+)
+
+var (
+	// ErrPaywall is returned for pages that only show a teaser to non-subscribers
+	ErrPaywall = errors.New("page is behind a paywall")
+	// ErrLoginWall is returned for pages that ask to sign in before showing their content
+	ErrLoginWall = errors.New("page requires signing in")
+	// ErrCaptcha is returned for bot challenges served instead of the page
+	ErrCaptcha = errors.New("page is protected by a bot challenge")
+	// ErrEmptyContent is returned for pages with too little text to summarize
+	ErrEmptyContent = errors.New("page has no readable content")
+)
+
+const (
+	// minContentWords is the amount of text below which a page is treated as empty
+	minContentWords = 20
+	// maxTeaserWords is the length up to which a page marked as paywalled is
+	// treated as a teaser; longer pages carry the full text
+	maxTeaserWords = 400
+	// challengeBodySize is how much of an error response is read to look for a bot challenge
+	challengeBodySize = 64 << 10
+)
+
+var (
+	// paywallMarkers match the structured data publishers add for paywalled
+	// articles and the subscription prompts that replace the article text
+	paywallMarkers = regexp.MustCompile(`(?i)"isAccessibleForFree"\s*:\s*"?false|class="[^"]*\b(paywall|piano-offer|tp-modal|meter-paywall|subscriber-only|premium-content)\b|(subscribe|subscription) (now )?to (continue|keep) reading|this (article|story|content) is (only )?(available )?(for|to) (subscribers|members)|already a subscriber\?`)
+	// loginWallMarkers match prompts to sign in before reading
+	loginWallMarkers = regexp.MustCompile(`(?i)(sign|log) ?in to (continue|read|view|see)|you (must|need to) (be )?(sign(ed)? ?in|log(ged)? ?in)|please (sign|log) ?in|create a free account to (continue|read)`)
+	// challengeMarkers match the interstitials of common bot protection services
+	challengeMarkers = regexp.MustCompile(`(?i)cf-challenge|challenge-platform|cf_chl_opt|<title>just a moment\.\.\.</title>|attention required! \| cloudflare|px-captcha|captcha-delivery\.com|are you a (robot|human)|verify (that )?you are (a )?human|access to this page has been denied`)
+	passwordInput    = regexp.MustCompile(`(?i)<input[^>]+type=["']?password`)
+)
+
+// detectBlockedPage returns an error when the content extracted from a page
+// is a paywall teaser, a login prompt, a bot challenge, or too short to
+// summarize. Walls are only reported for short content, since full articles
+// often keep subscription prompts in their markup.
+func detectBlockedPage(body []byte, result *ScrapeResult) error {
+	words := len(strings.Fields(result.Content))
+	switch {
+	case words < minStaticWords && challengeMarkers.Match(body):
+		return ErrCaptcha
+	case words < maxTeaserWords && paywallMarkers.Match(body):
+		return ErrPaywall
+	case words < minStaticWords && (loginWallMarkers.MatchString(result.Content) || passwordInput.Match(body)):
+		return ErrLoginWall
+	case words < minContentWords:
+		return ErrEmptyContent
+	}
+	return nil
+}
+
+// isChallengeResponse reports whether an error response is a bot challenge
+// rather than the site's own error page
+func isChallengeResponse(header http.Header, body []byte) bool {
+	if strings.EqualFold(header.Get("Cf-Mitigated"), "challenge") {
+		return true
+	}
+	return challengeMarkers.Match(body)
+}
+
+// aiError marks failures of the AI service, which happen after the content
+// was fetched
+type aiError struct {
+	err error
+}
+
+func (e *aiError) Error() string { return e.err.Error() }
+func (e *aiError) Unwrap() error { return e.err }
+
+// FailureReason classifies the error an item failed with
+func FailureReason(err error) string {
+	var statusErr *HTTPStatusError
+	var netErr net.Error
+	var aiErr *aiError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &aiErr):
+		return FailureReasonAI
+	case errors.Is(err, ErrPaywall):
+		return FailureReasonPaywall
+	case errors.Is(err, ErrLoginWall):
+		return FailureReasonLoginWall
+	case errors.Is(err, ErrCaptcha):
+		return FailureReasonCaptcha
+	case errors.Is(err, ErrDisallowedByRobots):
+		return FailureReasonRobots
+	case errors.Is(err, ErrEmptyContent):
+		return FailureReasonEmptyContent
+	case errors.Is(err, ErrDuplicateItem):
+		return FailureReasonDuplicate
+	case errors.Is(err, ErrBodyTooLarge):
+		return FailureReasonTooLarge
+	case errors.As(err, &statusErr):
+		return statusFailureReason(statusErr.StatusCode)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return FailureReasonTimeout
+	case errors.As(err, &netErr):
+		return FailureReasonNetwork
+	case errors.Is(err, ErrInvalidPDF), errors.Is(err, ErrInvalidSource), strings.Contains(err.Error(), "parse"):
+		return FailureReasonParse
+	}
+	return FailureReasonUnknown
+}
+
+func statusFailureReason(status int) string {
+	switch {
+	case status == http.StatusUnauthorized:
+		return FailureReasonLoginWall
+	case status == http.StatusPaymentRequired:
+		return FailureReasonPaywall
+	case status == http.StatusForbidden, status == http.StatusUnavailableForLegalReasons:
+		return FailureReasonForbidden
+	case status == http.StatusNotFound, status == http.StatusGone:
+		return FailureReasonNotFound
+	case status == http.StatusRequestTimeout:
+		return FailureReasonTimeout
+	case status >= 500:
+		return FailureReasonServerError
+	}
+	return FailureReasonClientError
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectBlockedPage(t *testing.T) {
+	words := func(n int) string { return strings.TrimSpace(strings.Repeat("word ", n)) }
+	tests := []struct {
+		name     string
+		body     string
+		content  string
+		expected error
+	}{
+		{
+			name:     "paywall structured data",
+			body:     `<script type="application/ld+json">{"@type":"NewsArticle","isAccessibleForFree":"False"}</script>`,
+			content:  words(120),
+			expected: ErrPaywall,
+		},
+		{
+			name:     "subscription prompt",
+			body:     `<article><p>Teaser</p><div class="article-paywall">Subscribe to continue reading.</div></article>`,
+			content:  words(80),
+			expected: ErrPaywall,
+		},
+		{
+			name:    "full article with subscription prompt",
+			body:    `<article><p>Full text</p></article><div class="paywall">Already a subscriber?</div>`,
+			content: words(900),
+		},
+		{
+			name:     "login wall",
+			body:     `<form><input type="email" name="email"><input type="password" name="password"></form>`,
+			content:  "Please log in to view this page. " + words(30),
+			expected: ErrLoginWall,
+		},
+		{
+			name:     "cloudflare challenge",
+			body:     `<html><head><title>Just a moment...</title></head><body><div id="challenge-platform"></div></body></html>`,
+			content:  "Checking your browser before accessing the site.",
+			expected: ErrCaptcha,
+		},
+		{
+			name:     "near-empty page",
+			body:     `<html><body><p>Loading</p></body></html>`,
+			content:  "Loading",
+			expected: ErrEmptyContent,
+		},
+		{
+			name:    "short article",
+			body:    `<article><p>A short note.</p></article>`,
+			content: words(60),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := detectBlockedPage([]byte(tt.body), &ScrapeResult{Content: tt.content})
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScrape_DetectsPaywall(t *testing.T) {
+	teaser := `<html><head><script type="application/ld+json">{"@type":"NewsArticle","headline":"Markets","isAccessibleForFree":false}</script></head>` +
+		`<body><article><h1>Markets</h1><p>` + strings.Repeat("Stocks rose on Tuesday. ", 10) + `</p></article></body></html>`
+	scraper := &scrapingService{fetch: staticFetch(t, teaser)}
+
+	result, err := scraper.Scrape("https://news.example.com/markets")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrPaywall)
+	assert.Equal(t, FailureReasonPaywall, FailureReason(err))
+}
+
+func TestFetcher_Fetch_Challenge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cf-Mitigated", "challenge")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<html><head><title>Just a moment...</title></head></html>"))
+	}))
+	defer server.Close()
+
+	_, err := NewFetcher(testFetcherConfig()).Fetch(server.URL)
+
+	assert.ErrorIs(t, err, ErrCaptcha)
+	var statusErr *HTTPStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusForbidden, statusErr.StatusCode)
+	assert.Equal(t, FailureReasonCaptcha, FailureReason(err))
+}
+
+func TestFailureReason(t *testing.T) {
+	status := func(code int) error { return &HTTPStatusError{URL: "https://example.com", StatusCode: code} }
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{fmt.Errorf("%w: https://example.com", ErrPaywall), FailureReasonPaywall},
+		{fmt.Errorf("%w: https://example.com", ErrLoginWall), FailureReasonLoginWall},
+		{fmt.Errorf("%w: https://example.com", ErrCaptcha), FailureReasonCaptcha},
+		{fmt.Errorf("%w: https://example.com", ErrDisallowedByRobots), FailureReasonRobots},
+		{fmt.Errorf("%w: https://example.com", ErrEmptyContent), FailureReasonEmptyContent},
+		{fmt.Errorf("%w: same page as item 3", ErrDuplicateItem), FailureReasonDuplicate},
+		{fmt.Errorf("%w: https://example.com", ErrBodyTooLarge), FailureReasonTooLarge},
+		{status(http.StatusUnauthorized), FailureReasonLoginWall},
+		{status(http.StatusPaymentRequired), FailureReasonPaywall},
+		{status(http.StatusForbidden), FailureReasonForbidden},
+		{status(http.StatusNotFound), FailureReasonNotFound},
+		{status(http.StatusTeapot), FailureReasonClientError},
+		{status(http.StatusBadGateway), FailureReasonServerError},
+		{fmt.Errorf("failed to scrape URL: %w", context.DeadlineExceeded), FailureReasonTimeout},
+		{fmt.Errorf("%w: no extractable text", ErrInvalidPDF), FailureReasonParse},
+		{&aiError{errors.New("failed to summarize content: rate limited")}, FailureReasonAI},
+		{errors.New("something else"), FailureReasonUnknown},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FailureReason(tt.err), fmt.Sprint(tt.err))
+	}
+}
+
+func TestCategorizeError(t *testing.T) {
+	assert.Equal(t, "blocked", categorizeError(FailureReasonPaywall, "Failed after 1 attempts: page is behind a paywall"))
+	assert.Equal(t, "blocked", categorizeError(FailureReasonRobots, "Failed after 1 attempts: disallowed by robots.txt"))
+	assert.Equal(t, "network", categorizeError(FailureReasonServerError, "Failed after 3 attempts: request failed with status 502"))
+	assert.Equal(t, "ai", categorizeError(FailureReasonAI, "Failed after 3 attempts: failed to summarize content"))
+	assert.Equal(t, "scraping", categorizeError(FailureReasonEmptyContent, "Failed after 1 attempts: page has no readable content"))
+	// Unknown reasons fall back to the message
+	assert.Equal(t, "timeout", categorizeError(FailureReasonUnknown, "request timeout"))
+	assert.Equal(t, "unknown", categorizeError("", "something else"))
+}
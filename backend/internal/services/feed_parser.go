@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/charset"
+)
+
+// ErrInvalidFeed is returned for URLs that do not lead to an RSS, Atom or JSON feed
+var ErrInvalidFeed = errors.New("invalid feed")
+
+// ParsedFeed is a feed document reduced to what subscriptions need
+type ParsedFeed struct {
+	Title   string
+	SiteURL string
+	Entries []FeedEntry
+}
+
+// FeedEntry is one post in a feed. GUID identifies the entry across polls and
+// falls back to the entry URL when the feed gives no id.
+type FeedEntry struct {
+	GUID        string
+	URL         string
+	Title       string
+	PublishedAt *time.Time
+}
+
+// feedDateLayouts extend dateLayouts with the RFC 822 variants found in RSS
+var feedDateLayouts = []string{
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 06 15:04:05 -0700",
+}
+
+// feedLink covers both RSS text links and Atom href links
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// xmlFeed decodes RSS 2.0, RSS 1.0 (RDF) and Atom documents, which differ in
+// where they put the same few fields
+type xmlFeed struct {
+	XMLName xml.Name
+	// RSS 2.0 nests items in the channel; RDF puts them next to it
+	Channel struct {
+		Title string     `xml:"title"`
+		Links []feedLink `xml:"link"`
+		Items []rssItem  `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"`
+	// Atom
+	Title   string      `xml:"title"`
+	Links   []feedLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title   string     `xml:"title"`
+	Links   []feedLink `xml:"link"`
+	GUID    string     `xml:"guid"`
+	PubDate string     `xml:"pubDate"`
+	Date    string     `xml:"date"` // Dublin Core, used by RDF feeds
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []feedLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+// jsonFeed is a JSON Feed (https://jsonfeed.org) document
+type jsonFeed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	HomePageURL string `json:"home_page_url"`
+	Items       []struct {
+		ID            any    `json:"id"`
+		URL           string `json:"url"`
+		ExternalURL   string `json:"external_url"`
+		Title         string `json:"title"`
+		DatePublished string `json:"date_published"`
+	} `json:"items"`
+}
+
+// ParseFeed parses an RSS, Atom or JSON feed. Relative links are resolved
+// against feedURL and entries without a link are left out, since there is
+// nothing to save for them.
+func ParseFeed(body []byte, feedURL *url.URL) (*ParsedFeed, error) {
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("%w: empty document", ErrInvalidFeed)
+	}
+	if trimmed[0] == '{' {
+		return parseJSONFeed(trimmed, feedURL)
+	}
+	return parseXMLFeed(trimmed, feedURL)
+}
+
+func parseXMLFeed(body []byte, feedURL *url.URL) (*ParsedFeed, error) {
+	var doc xmlFeed
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charset.NewReaderLabel
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+
+	feed := &ParsedFeed{}
+	switch doc.XMLName.Local {
+	case "rss", "RDF":
+		feed.Title = strings.TrimSpace(doc.Channel.Title)
+		feed.SiteURL = resolveURL(feedURL, rssLink(doc.Channel.Links))
+		for _, item := range append(doc.Channel.Items, doc.Items...) {
+			link := rssLink(item.Links)
+			guid := strings.TrimSpace(item.GUID)
+			// A guid is a permalink unless it says otherwise, so it can stand in for a missing link
+			if link == "" && (strings.HasPrefix(guid, "http://") || strings.HasPrefix(guid, "https://")) {
+				link = guid
+			}
+			feed.addEntry(feedURL, guid, link, item.Title, firstNonEmpty(item.PubDate, item.Date))
+		}
+	case "feed":
+		feed.Title = strings.TrimSpace(doc.Title)
+		feed.SiteURL = resolveURL(feedURL, atomLink(doc.Links))
+		for _, entry := range doc.Entries {
+			feed.addEntry(feedURL, entry.ID, atomLink(entry.Links), entry.Title, firstNonEmpty(entry.Published, entry.Updated))
+		}
+	default:
+		return nil, fmt.Errorf("%w: unexpected <%s> document", ErrInvalidFeed, doc.XMLName.Local)
+	}
+	return feed, nil
+}
+
+func parseJSONFeed(body []byte, feedURL *url.URL) (*ParsedFeed, error) {
+	var doc jsonFeed
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+	if !strings.Contains(doc.Version, "jsonfeed.org") {
+		return nil, fmt.Errorf("%w: not a JSON Feed document", ErrInvalidFeed)
+	}
+
+	feed := &ParsedFeed{
+		Title:   strings.TrimSpace(doc.Title),
+		SiteURL: resolveURL(feedURL, doc.HomePageURL),
+	}
+	for _, item := range doc.Items {
+		var id string
+		if item.ID != nil {
+			id = fmt.Sprint(item.ID)
+		}
+		feed.addEntry(feedURL, id, firstNonEmpty(item.URL, item.ExternalURL), item.Title, item.DatePublished)
+	}
+	return feed, nil
+}
+
+func (f *ParsedFeed) addEntry(feedURL *url.URL, guid, link, title, published string) {
+	link = resolveURL(feedURL, link)
+	if link == "" {
+		return
+	}
+	f.Entries = append(f.Entries, FeedEntry{
+		GUID:        firstNonEmpty(guid, link),
+		URL:         link,
+		Title:       strings.TrimSpace(title),
+		PublishedAt: parseFeedDate(published),
+	})
+}
+
+// rssLink returns the text of the first plain <link>, skipping atom:link
+// elements that RSS feeds use to point at themselves
+func rssLink(links []feedLink) string {
+	for _, link := range links {
+		if link.Href == "" && strings.TrimSpace(link.Text) != "" {
+			return link.Text
+		}
+	}
+	return ""
+}
+
+// atomLink returns the alternate link, which Atom marks with rel="alternate" or no rel at all
+func atomLink(links []feedLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+func parseFeedDate(value string) *time.Time {
+	if parsed := parseMetadataDate(value); parsed != nil {
+		return parsed
+	}
+	value = strings.TrimSpace(value)
+	for _, layout := range feedDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			parsed = parsed.UTC()
+			return &parsed
+		}
+	}
+	return nil
+}
+
+// feedContentTypes are the <link rel=alternate> types that advertise a feed
+var feedContentTypes = []string{"application/rss+xml", "application/atom+xml", "application/feed+json", "application/json"}
+
+// discoverFeedURL returns the first feed an HTML page advertises with
+// <link rel="alternate">, so users can subscribe with a blog's address
+func discoverFeedURL(page *FetchedPage) string {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return ""
+	}
+	for _, contentType := range feedContentTypes {
+		selector := fmt.Sprintf("link[rel~='alternate'][type='%s']", contentType)
+		if href := doc.Find(selector).First().AttrOr("href", ""); href != "" {
+			return resolveURL(page.URL, href)
+		}
+	}
+	return ""
+}
+
+// isHTMLPage reports whether a fetched page is a web page rather than a feed
+func isHTMLPage(page *FetchedPage) bool {
+	if strings.Contains(strings.ToLower(page.ContentType), "text/html") {
+		return true
+	}
+	head := strings.ToLower(string(page.Body[:min(len(page.Body), 512)]))
+	return strings.Contains(head, "<!doctype html") || strings.Contains(head, "<html")
+}
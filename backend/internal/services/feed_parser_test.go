@@ -0,0 +1,162 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeed_RSS(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Example Blog</title>
+    <atom:link href="https://blog.example.com/feed.xml" rel="self" type="application/rss+xml"/>
+    <link>https://blog.example.com/</link>
+    <item>
+      <title>Second post</title>
+      <link>https://blog.example.com/posts/2</link>
+      <guid isPermaLink="false">post-2</guid>
+      <pubDate>Tue, 5 Mar 2024 09:30:00 +0000</pubDate>
+    </item>
+    <item>
+      <title>First post</title>
+      <guid>https://blog.example.com/posts/1</guid>
+      <pubDate>Mon, 04 Mar 2024 10:00:00 GMT</pubDate>
+    </item>
+    <item>
+      <title>Relative link</title>
+      <link>/posts/0</link>
+    </item>
+    <item>
+      <title>No link</title>
+      <guid isPermaLink="false">orphan</guid>
+    </item>
+  </channel>
+</rss>`
+
+	feed, err := ParseFeed([]byte(body), mustParseURL(t, "https://blog.example.com/feed.xml"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Example Blog", feed.Title)
+	assert.Equal(t, "https://blog.example.com/", feed.SiteURL)
+	require.Len(t, feed.Entries, 3)
+	assert.Equal(t, FeedEntry{
+		GUID:        "post-2",
+		URL:         "https://blog.example.com/posts/2",
+		Title:       "Second post",
+		PublishedAt: timePtr(time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC)),
+	}, feed.Entries[0])
+	assert.Equal(t, "https://blog.example.com/posts/1", feed.Entries[1].GUID)
+	assert.Equal(t, "https://blog.example.com/posts/1", feed.Entries[1].URL, "a permalink guid stands in for the link")
+	assert.Equal(t, time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC), *feed.Entries[1].PublishedAt)
+	assert.Equal(t, "https://blog.example.com/posts/0", feed.Entries[2].URL)
+	assert.Equal(t, "https://blog.example.com/posts/0", feed.Entries[2].GUID)
+}
+
+func TestParseFeed_RDF(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel rdf:about="https://news.example.com/">
+    <title>Example News</title>
+    <link>https://news.example.com/</link>
+  </channel>
+  <item rdf:about="https://news.example.com/1">
+    <title>Story</title>
+    <link>https://news.example.com/1</link>
+    <dc:date>2024-03-04T10:00:00Z</dc:date>
+  </item>
+</rdf:RDF>`
+
+	feed, err := ParseFeed([]byte(body), mustParseURL(t, "https://news.example.com/rss"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Example News", feed.Title)
+	require.Len(t, feed.Entries, 1)
+	assert.Equal(t, "https://news.example.com/1", feed.Entries[0].URL)
+	assert.NotNil(t, feed.Entries[0].PublishedAt)
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Atom</title>
+  <link rel="self" href="https://example.com/atom.xml"/>
+  <link href="https://example.com/"/>
+  <entry>
+    <id>tag:example.com,2024:1</id>
+    <title type="html">Hello &amp; welcome</title>
+    <link rel="replies" href="https://example.com/1#comments"/>
+    <link rel="alternate" href="/1"/>
+    <updated>2024-03-04T10:00:00Z</updated>
+  </entry>
+</feed>`
+
+	feed, err := ParseFeed([]byte(body), mustParseURL(t, "https://example.com/atom.xml"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Example Atom", feed.Title)
+	assert.Equal(t, "https://example.com/", feed.SiteURL)
+	require.Len(t, feed.Entries, 1)
+	assert.Equal(t, "tag:example.com,2024:1", feed.Entries[0].GUID)
+	assert.Equal(t, "https://example.com/1", feed.Entries[0].URL)
+	assert.Equal(t, "Hello & welcome", feed.Entries[0].Title)
+}
+
+func TestParseFeed_JSONFeed(t *testing.T) {
+	body := `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Example JSON",
+  "home_page_url": "https://example.org/",
+  "items": [
+    {"id": 42, "url": "https://example.org/42", "title": "Numbered", "date_published": "2024-03-04T10:00:00+01:00"},
+    {"id": "link-only", "external_url": "https://elsewhere.example.com/post"},
+    {"id": "no-url", "title": "Note"}
+  ]
+}`
+
+	feed, err := ParseFeed([]byte(body), mustParseURL(t, "https://example.org/feed.json"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Example JSON", feed.Title)
+	assert.Equal(t, "https://example.org/", feed.SiteURL)
+	require.Len(t, feed.Entries, 2)
+	assert.Equal(t, "42", feed.Entries[0].GUID)
+	assert.Equal(t, time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), *feed.Entries[0].PublishedAt)
+	assert.Equal(t, "https://elsewhere.example.com/post", feed.Entries[1].URL)
+}
+
+func TestParseFeed_Invalid(t *testing.T) {
+	for name, body := range map[string]string{
+		"empty":       "",
+		"html":        "<html><head><title>Blog</title></head><body></body></html>",
+		"other json":  `{"title": "Not a feed"}`,
+		"broken json": `{"version": `,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseFeed([]byte(body), mustParseURL(t, "https://example.com/feed"))
+			assert.ErrorIs(t, err, ErrInvalidFeed)
+		})
+	}
+}
+
+func TestDiscoverFeedURL(t *testing.T) {
+	page := &FetchedPage{
+		URL:         mustParseURL(t, "https://example.substack.com/"),
+		StatusCode:  http.StatusOK,
+		ContentType: "text/html; charset=utf-8",
+		Body: []byte(`<html><head>
+<link rel="alternate" type="application/atom+xml" href="/atom">
+<link rel="alternate" type="application/rss+xml" title="Example" href="/feed">
+</head><body></body></html>`),
+	}
+
+	assert.True(t, isHTMLPage(page))
+	assert.Equal(t, "https://example.substack.com/feed", discoverFeedURL(page), "RSS is preferred")
+
+	page.Body = []byte(`<html><head><link rel="stylesheet" href="/style.css"></head></html>`)
+	assert.Empty(t, discoverFeedURL(page))
+}
@@ -0,0 +1,624 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// Feed errors returned by FeedService
+var (
+	ErrFeedNotFound           = errors.New("feed not found")
+	ErrFeedSubscriptionExists = errors.New("already subscribed to this feed")
+)
+
+// maxFeedBackoff caps how far a failing feed's next poll is pushed out
+const maxFeedBackoff = 24 * time.Hour
+
+// FeedFetcher downloads feeds with conditional requests. It is implemented by Fetcher.
+type FeedFetcher interface {
+	FetchIfModified(rawURL, etag, lastModified string) (*FetchedPage, error)
+}
+
+// FeedConfig controls how often feeds are polled and how much they ingest
+type FeedConfig struct {
+	// RefreshInterval is the time between polls of a healthy feed
+	RefreshInterval time.Duration
+	// BatchSize is the number of due feeds polled per run
+	BatchSize int
+	// MaxNewEntries caps the entries ingested from one poll, so a feed that
+	// changes its GUIDs does not flood its subscribers
+	MaxNewEntries int
+}
+
+// DefaultFeedConfig returns the default feed polling settings
+func DefaultFeedConfig() FeedConfig {
+	return FeedConfig{
+		RefreshInterval: time.Hour,
+		BatchSize:       25,
+		MaxNewEntries:   10,
+	}
+}
+
+// FeedService manages feed subscriptions and polls subscribed feeds for new
+// entries. A feed is fetched once per poll however many users follow it, and
+// each new entry is queued as an item for every subscriber.
+type FeedService interface {
+	Subscribe(ctx context.Context, userID int32, feedURL string, title *string, tags []string) (*db.Feed, *db.FeedSubscription, error)
+	ListSubscriptions(ctx context.Context, userID int32) ([]db.ListFeedSubscriptionsByUserRow, error)
+	GetSubscription(ctx context.Context, userID int32, feedID int32) (*db.Feed, *db.FeedSubscription, error)
+	UpdateSubscription(ctx context.Context, userID int32, feedID int32, title *string, tags []string) (*db.Feed, *db.FeedSubscription, error)
+	Unsubscribe(ctx context.Context, userID int32, feedID int32) error
+
+	// OPML subscription lists
+	ImportOPML(ctx context.Context, userID int32, data []byte, dryRun bool) ([]OPMLImportResult, error)
+	ExportOPML(ctx context.Context, userID int32) ([]byte, error)
+
+	// PollDueFeeds polls the feeds whose next poll time has passed
+	PollDueFeeds(ctx context.Context, now time.Time) error
+
+	// Daily item quota
+	SetQuotaService(quotaService QuotaService)
+}
+
+type feedService struct {
+	querier         db.Querier
+	fetcher         FeedFetcher
+	jobQueueService JobQueueService
+	quotaService    QuotaService
+	config          FeedConfig
+}
+
+func NewFeedService(querier db.Querier, fetcher FeedFetcher, jobQueueService JobQueueService, config FeedConfig) FeedService {
+	return &feedService{
+		querier:         querier,
+		fetcher:         fetcher,
+		jobQueueService: jobQueueService,
+		config:          config,
+	}
+}
+
+// SetQuotaService makes ingested entries count against each subscriber's daily item quota
+func (s *feedService) SetQuotaService(quotaService QuotaService) {
+	s.quotaService = quotaService
+}
+
+// Subscribe follows the feed at feedURL. A web page that advertises a feed
+// with <link rel="alternate"> can be given instead of the feed itself. Entries
+// already in the feed are recorded as seen; only later ones become items,
+// tagged with the subscription's tags.
+func (s *feedService) Subscribe(ctx context.Context, userID int32, feedURL string, title *string, tags []string) (*db.Feed, *db.FeedSubscription, error) {
+	feedURL = strings.TrimSpace(feedURL)
+	if feedURL == "" {
+		return nil, nil, fmt.Errorf("%w: url is required", ErrInvalidFeed)
+	}
+
+	feed, err := s.querier.GetFeedByURL(ctx, feedURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		feed, err = s.createFeed(ctx, feedURL)
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidFeed) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	if _, err := s.querier.GetFeedSubscription(ctx, db.GetFeedSubscriptionParams{FeedID: feed.ID, UserID: userID}); err == nil {
+		return nil, nil, ErrFeedSubscriptionExists
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("failed to get feed subscription: %w", err)
+	}
+
+	subscription, err := s.querier.CreateFeedSubscription(ctx, db.CreateFeedSubscriptionParams{
+		FeedID: feed.ID,
+		UserID: userID,
+		Title:  normalizeFeedTitle(title),
+		Tags:   normalizeTags(tags),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, nil, ErrFeedSubscriptionExists
+		}
+		return nil, nil, fmt.Errorf("failed to create feed subscription: %w", err)
+	}
+	return &feed, &subscription, nil
+}
+
+// createFeed fetches and parses a feed that is not stored yet, following
+// feed discovery when the URL is a web page
+func (s *feedService) createFeed(ctx context.Context, feedURL string) (db.Feed, error) {
+	discovered, page, parsed, err := s.resolveFeed(feedURL)
+	if err != nil {
+		return db.Feed{}, err
+	}
+	if discovered != feedURL {
+		// The discovered feed may already be stored under its own URL
+		if feed, err := s.querier.GetFeedByURL(ctx, discovered); err == nil || !errors.Is(err, pgx.ErrNoRows) {
+			return feed, err
+		}
+		feedURL = discovered
+	}
+
+	feed, err := s.querier.CreateFeed(ctx, db.CreateFeedParams{
+		Url:          feedURL,
+		Title:        optionalString(parsed.Title),
+		SiteUrl:      optionalString(parsed.SiteURL),
+		Etag:         optionalString(page.ETag),
+		LastModified: optionalString(page.LastModified),
+		NextPollAt:   time.Now().Add(s.config.RefreshInterval),
+	})
+	if err != nil {
+		return db.Feed{}, fmt.Errorf("failed to create feed: %w", err)
+	}
+
+	// Subscribing follows new posts rather than importing the archive
+	for _, entry := range parsed.Entries {
+		if _, err := s.recordEntry(ctx, feed.ID, entry); err != nil {
+			return db.Feed{}, err
+		}
+	}
+	return feed, nil
+}
+
+// resolveFeed fetches and parses the feed at feedURL, following feed
+// discovery when the URL is a web page. It returns the URL of the feed itself.
+func (s *feedService) resolveFeed(feedURL string) (string, *FetchedPage, *ParsedFeed, error) {
+	page, err := s.fetcher.FetchIfModified(feedURL, "", "")
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+	if isHTMLPage(page) {
+		discovered := discoverFeedURL(page)
+		if discovered == "" {
+			return "", nil, nil, fmt.Errorf("%w: %s is a web page that does not link to a feed", ErrInvalidFeed, feedURL)
+		}
+		feedURL = discovered
+		if page, err = s.fetcher.FetchIfModified(feedURL, "", ""); err != nil {
+			return "", nil, nil, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+		}
+	}
+
+	parsed, err := ParseFeed(page.Body, page.URL)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return feedURL, page, parsed, nil
+}
+
+func (s *feedService) ListSubscriptions(ctx context.Context, userID int32) ([]db.ListFeedSubscriptionsByUserRow, error) {
+	subscriptions, err := s.querier.ListFeedSubscriptionsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// GetSubscription returns a feed the user follows together with their subscription.
+// Feeds the user does not follow are reported as not found.
+func (s *feedService) GetSubscription(ctx context.Context, userID int32, feedID int32) (*db.Feed, *db.FeedSubscription, error) {
+	subscription, err := s.querier.GetFeedSubscription(ctx, db.GetFeedSubscriptionParams{FeedID: feedID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrFeedNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get feed subscription: %w", err)
+	}
+
+	feed, err := s.querier.GetFeed(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrFeedNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	return &feed, &subscription, nil
+}
+
+// UpdateSubscription changes the user's own title and tags for a feed. Nil
+// leaves a field unchanged; a blank title restores the feed's title and an
+// empty tag list removes the tags. New tags apply to items saved from then on.
+func (s *feedService) UpdateSubscription(ctx context.Context, userID int32, feedID int32, title *string, tags []string) (*db.Feed, *db.FeedSubscription, error) {
+	feed, _, err := s.GetSubscription(ctx, userID, feedID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if title != nil {
+		trimmed := strings.TrimSpace(*title)
+		title = &trimmed
+	}
+	subscription, err := s.querier.UpdateFeedSubscription(ctx, db.UpdateFeedSubscriptionParams{
+		Title:  title,
+		Tags:   normalizeTags(tags),
+		FeedID: feedID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrFeedNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to update feed subscription: %w", err)
+	}
+	return feed, &subscription, nil
+}
+
+// Unsubscribe stops following a feed. Items already saved from it are kept.
+func (s *feedService) Unsubscribe(ctx context.Context, userID int32, feedID int32) error {
+	rows, err := s.querier.DeleteFeedSubscription(ctx, db.DeleteFeedSubscriptionParams{FeedID: feedID, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete feed subscription: %w", err)
+	}
+	if rows == 0 {
+		return ErrFeedNotFound
+	}
+	return nil
+}
+
+// Outcomes of importing a feed from an OPML document. Dry runs report
+// reachable instead of subscribed.
+const (
+	OPMLImportSubscribed        = "subscribed"
+	OPMLImportReachable         = "reachable"
+	OPMLImportAlreadySubscribed = "already_subscribed"
+	OPMLImportUnreachable       = "unreachable"
+	OPMLImportInvalid           = "invalid"
+	OPMLImportFailed            = "failed"
+)
+
+// opmlImportConcurrency is the number of feeds fetched at once during an import
+const opmlImportConcurrency = 8
+
+// OPMLImportResult is the outcome for one feed of an OPML import
+type OPMLImportResult struct {
+	URL    string
+	Title  string
+	Tags   []string
+	Status string
+	FeedID *int32
+	Error  string
+}
+
+// ImportOPML subscribes the user to every feed in an OPML document, tagging
+// each subscription with the folders the feed was in. Feeds that cannot be
+// fetched or parsed, or that fail for any other reason, are reported rather
+// than failing the import, so the results of the others are always returned.
+// A dry run fetches each feed to check that it is reachable without subscribing.
+func (s *feedService) ImportOPML(ctx context.Context, userID int32, data []byte, dryRun bool) ([]OPMLImportResult, error) {
+	feeds, err := ParseOPML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]OPMLImportResult, len(feeds))
+	sem := make(chan struct{}, opmlImportConcurrency)
+	var wg sync.WaitGroup
+	for i, feed := range feeds {
+		results[i] = OPMLImportResult{URL: feed.URL, Title: feed.Title, Tags: feed.Tags}
+		if u, err := url.Parse(feed.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			results[i].Status = OPMLImportInvalid
+			results[i].Error = fmt.Sprintf("invalid URL %q", feed.URL)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var err error
+			if dryRun {
+				err = s.checkFeed(ctx, userID, &results[i])
+			} else {
+				err = s.importFeed(ctx, userID, &results[i])
+			}
+			if err != nil {
+				log.Printf("Failed to import feed %s for user %d: %v", results[i].URL, userID, err)
+				results[i].Status = OPMLImportFailed
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// importFeed subscribes to one feed of an OPML import and records the
+// outcome. Errors other than an unusable feed are returned.
+func (s *feedService) importFeed(ctx context.Context, userID int32, result *OPMLImportResult) error {
+	feed, _, err := s.Subscribe(ctx, userID, result.URL, nil, result.Tags)
+	switch {
+	case err == nil:
+		result.Status = OPMLImportSubscribed
+		result.FeedID = &feed.ID
+	case errors.Is(err, ErrFeedSubscriptionExists):
+		result.Status = OPMLImportAlreadySubscribed
+	case errors.Is(err, ErrInvalidFeed):
+		result.Status = OPMLImportUnreachable
+		result.Error = err.Error()
+	default:
+		return err
+	}
+	return nil
+}
+
+// checkFeed reports whether the user already follows a feed of an OPML
+// import and, if not, whether it can be fetched and parsed
+func (s *feedService) checkFeed(ctx context.Context, userID int32, result *OPMLImportResult) error {
+	feed, err := s.querier.GetFeedByURL(ctx, result.URL)
+	if err == nil {
+		result.FeedID = &feed.ID
+		_, err = s.querier.GetFeedSubscription(ctx, db.GetFeedSubscriptionParams{FeedID: feed.ID, UserID: userID})
+		if err == nil {
+			result.Status = OPMLImportAlreadySubscribed
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to get feed subscription: %w", err)
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	if _, _, _, err := s.resolveFeed(result.URL); err != nil {
+		if !errors.Is(err, ErrInvalidFeed) {
+			return err
+		}
+		result.Status = OPMLImportUnreachable
+		result.Error = err.Error()
+		return nil
+	}
+	result.Status = OPMLImportReachable
+	return nil
+}
+
+// ExportOPML writes the user's subscriptions as an OPML 2.0 document
+func (s *feedService) ExportOPML(ctx context.Context, userID int32) ([]byte, error) {
+	subscriptions, err := s.ListSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	feeds := make([]OPMLFeed, len(subscriptions))
+	for i, subscription := range subscriptions {
+		title := subscription.CustomTitle
+		if title == nil {
+			title = subscription.Title
+		}
+		feeds[i] = OPMLFeed{
+			URL:     subscription.Url,
+			Title:   derefString(title),
+			SiteURL: derefString(subscription.SiteUrl),
+			Tags:    subscription.Tags,
+		}
+	}
+	return RenderOPML("BriefBot feeds", feeds, time.Now())
+}
+
+// PollDueFeeds polls each due feed in turn. A feed that fails is retried
+// with exponential backoff and does not stop the others.
+func (s *feedService) PollDueFeeds(ctx context.Context, now time.Time) error {
+	feeds, err := s.querier.ListDueFeeds(ctx, db.ListDueFeedsParams{
+		NextPollAt: now,
+		Limit:      int32(s.config.BatchSize),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list due feeds: %w", err)
+	}
+
+	for _, feed := range feeds {
+		if err := s.pollFeed(ctx, feed, now); err != nil {
+			log.Printf("Failed to poll feed %d (%s): %v", feed.ID, feed.Url, err)
+			message := err.Error()
+			if err := s.querier.UpdateFeedPollFailed(ctx, db.UpdateFeedPollFailedParams{
+				ID:         feed.ID,
+				NextPollAt: now.Add(s.backoff(feed.ConsecutiveFailures + 1)),
+				LastError:  &message,
+			}); err != nil {
+				log.Printf("Failed to record poll failure for feed %d: %v", feed.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// pollFeed fetches a feed with its stored validators and ingests the entries not seen before
+func (s *feedService) pollFeed(ctx context.Context, feed db.Feed, now time.Time) error {
+	page, err := s.fetcher.FetchIfModified(feed.Url, derefString(feed.Etag), derefString(feed.LastModified))
+	if err != nil {
+		return err
+	}
+
+	update := db.UpdateFeedPolledParams{
+		ID:           feed.ID,
+		Etag:         optionalString(page.ETag),
+		LastModified: optionalString(page.LastModified),
+		NextPollAt:   now.Add(s.config.RefreshInterval),
+	}
+	if page.StatusCode != http.StatusNotModified {
+		parsed, err := ParseFeed(page.Body, page.URL)
+		if err != nil {
+			return err
+		}
+		update.Title = optionalString(parsed.Title)
+		update.SiteUrl = optionalString(parsed.SiteURL)
+
+		entries, err := s.newEntries(ctx, feed.ID, parsed.Entries)
+		if err != nil {
+			return err
+		}
+		complete := true
+		if len(entries) > s.config.MaxNewEntries {
+			log.Printf("Feed %d has %d new entries, ingesting the first %d", feed.ID, len(entries), s.config.MaxNewEntries)
+			entries = entries[:s.config.MaxNewEntries]
+			complete = false
+		}
+		if len(entries) > 0 {
+			ingested, err := s.ingestEntries(ctx, feed, entries)
+			if err != nil {
+				return err
+			}
+			complete = complete && ingested
+		}
+		// Entries left for later are only seen again if the next poll
+		// fetches the whole feed instead of getting 304 Not Modified
+		if !complete {
+			update.Etag = nil
+			update.LastModified = nil
+		}
+	}
+
+	if err := s.querier.UpdateFeedPolled(ctx, update); err != nil {
+		return fmt.Errorf("failed to update feed: %w", err)
+	}
+	return nil
+}
+
+// recordEntry stores an entry's GUID, reporting whether it had not been seen before
+func (s *feedService) recordEntry(ctx context.Context, feedID int32, entry FeedEntry) (bool, error) {
+	rows, err := s.querier.InsertFeedEntry(ctx, db.InsertFeedEntryParams{
+		FeedID:      feedID,
+		Guid:        entry.GUID,
+		Url:         &entry.URL,
+		Title:       optionalString(entry.Title),
+		PublishedAt: entry.PublishedAt,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record feed entry: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// newEntries returns the entries whose GUIDs have not been recorded for the feed
+func (s *feedService) newEntries(ctx context.Context, feedID int32, entries []FeedEntry) ([]FeedEntry, error) {
+	guids := make([]string, len(entries))
+	for i, entry := range entries {
+		guids[i] = entry.GUID
+	}
+	seenGUIDs, err := s.querier.ListFeedEntryGUIDs(ctx, db.ListFeedEntryGUIDsParams{FeedID: feedID, Guids: guids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed entries: %w", err)
+	}
+	seen := make(map[string]bool, len(seenGUIDs))
+	for _, guid := range seenGUIDs {
+		seen[guid] = true
+	}
+
+	var unseen []FeedEntry
+	for _, entry := range entries {
+		if !seen[entry.GUID] {
+			seen[entry.GUID] = true
+			unseen = append(unseen, entry)
+		}
+	}
+	return unseen, nil
+}
+
+// ingestEntries queues each entry as an item for every subscriber. Pages a
+// subscriber already saved are skipped, as are entries past their daily quota.
+// An entry is recorded as seen once it has been handled for every subscriber,
+// so one that could not be queued is tried again on the next poll. It reports
+// whether every entry was recorded.
+func (s *feedService) ingestEntries(ctx context.Context, feed db.Feed, entries []FeedEntry) (bool, error) {
+	subscribers, err := s.querier.ListFeedSubscribers(ctx, feed.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to list feed subscribers: %w", err)
+	}
+
+	complete := true
+	for _, entry := range entries {
+		ingested := true
+		for _, subscriber := range subscribers {
+			if !s.ingestEntry(ctx, feed, subscriber, entry) {
+				ingested = false
+			}
+		}
+		if !ingested {
+			complete = false
+			continue
+		}
+		if _, err := s.recordEntry(ctx, feed.ID, entry); err != nil {
+			return false, err
+		}
+	}
+	return complete, nil
+}
+
+// ingestEntry queues an entry for one subscriber, reporting false when it
+// could not be queued and should be retried
+func (s *feedService) ingestEntry(ctx context.Context, feed db.Feed, subscriber db.ListFeedSubscribersRow, entry FeedEntry) bool {
+	userID := subscriber.UserID
+
+	// Checked first so a page the user saved by hand does not use up quota
+	existing, err := findItemByURL(ctx, s.querier, userID, entry.URL)
+	if err != nil {
+		log.Printf("Failed to look up entry %s of feed %d for user %d: %v", entry.URL, feed.ID, userID, err)
+		return false
+	}
+	if existing != nil {
+		return true
+	}
+	if s.quotaService != nil {
+		if err := s.quotaService.ConsumeItem(ctx, userID); err != nil {
+			log.Printf("Skipping entry %s of feed %d for user %d: %v", entry.URL, feed.ID, userID, err)
+			return true
+		}
+	}
+	item, err := s.jobQueueService.EnqueueItem(ctx, userID, entry.Title, entry.URL, nil)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateItem) {
+			return true
+		}
+		log.Printf("Failed to enqueue entry %s of feed %d for user %d: %v", entry.URL, feed.ID, userID, err)
+		return false
+	}
+	// Added rather than set, since the item may already be processed
+	if len(subscriber.Tags) > 0 {
+		if err := s.querier.AddItemTags(ctx, db.AddItemTagsParams{Tags: subscriber.Tags, ID: item.ID}); err != nil {
+			log.Printf("Failed to tag item %d from feed %d: %v", item.ID, feed.ID, err)
+		}
+	}
+	return true
+}
+
+// backoff is the delay before retrying a feed after the given number of
+// consecutive failures, doubling the refresh interval each time
+func (s *feedService) backoff(failures int32) time.Duration {
+	delay := s.config.RefreshInterval
+	for i := int32(1); i < failures && delay < maxFeedBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxFeedBackoff)
+}
+
+// normalizeFeedTitle turns a blank custom title into nil so the feed's own title is used
+func normalizeFeedTitle(title *string) *string {
+	if title == nil {
+		return nil
+	}
+	return optionalString(strings.TrimSpace(*title))
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
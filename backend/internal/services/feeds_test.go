@@ -0,0 +1,505 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+// feedFetcherFunc adapts a function to FeedFetcher
+type feedFetcherFunc func(rawURL, etag, lastModified string) (*FetchedPage, error)
+
+func (f feedFetcherFunc) FetchIfModified(rawURL, etag, lastModified string) (*FetchedPage, error) {
+	return f(rawURL, etag, lastModified)
+}
+
+const testFeedXML = `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+  <title>Example Blog</title>
+  <link>https://blog.example.com/</link>
+  <item><title>Second post</title><link>https://blog.example.com/posts/2</link><guid>post-2</guid></item>
+  <item><title>First post</title><link>https://blog.example.com/posts/1</link><guid>post-1</guid></item>
+</channel></rss>`
+
+// servePages returns a fetcher answering each URL with the given body
+func servePages(t *testing.T, pages map[string]string) feedFetcherFunc {
+	return func(rawURL, etag, lastModified string) (*FetchedPage, error) {
+		body, ok := pages[rawURL]
+		if !ok {
+			return nil, &HTTPStatusError{URL: rawURL, StatusCode: http.StatusNotFound}
+		}
+		contentType := "application/rss+xml"
+		if strings.HasPrefix(body, "<html") {
+			contentType = "text/html"
+		}
+		return &FetchedPage{URL: mustParseURL(t, rawURL), StatusCode: http.StatusOK, ContentType: contentType, Body: []byte(body), ETag: `"v1"`}, nil
+	}
+}
+
+func entryParams(feedID int32, guid, url, title string) db.InsertFeedEntryParams {
+	return db.InsertFeedEntryParams{FeedID: feedID, Guid: guid, Url: &url, Title: &title}
+}
+
+func TestSubscribe_NewFeed(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	feedURL := "https://blog.example.com/feed.xml"
+	service := NewFeedService(mockQuerier, servePages(t, map[string]string{feedURL: testFeedXML}), mockJobQueue, DefaultFeedConfig())
+	ctx := context.Background()
+	title := "Example"
+
+	mockQuerier.On("GetFeedByURL", ctx, feedURL).Return(db.Feed{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateFeed", ctx, mock.MatchedBy(func(arg db.CreateFeedParams) bool {
+		return arg.Url == feedURL && *arg.Title == "Example Blog" && *arg.SiteUrl == "https://blog.example.com/" &&
+			*arg.Etag == `"v1"` && arg.LastModified == nil && arg.NextPollAt.After(time.Now())
+	})).Return(db.Feed{ID: 7, Url: feedURL}, nil)
+	mockQuerier.On("InsertFeedEntry", ctx, entryParams(7, "post-2", "https://blog.example.com/posts/2", "Second post")).Return(int64(1), nil)
+	mockQuerier.On("InsertFeedEntry", ctx, entryParams(7, "post-1", "https://blog.example.com/posts/1", "First post")).Return(int64(1), nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 7, UserID: 1}).Return(db.FeedSubscription{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateFeedSubscription", ctx, db.CreateFeedSubscriptionParams{FeedID: 7, UserID: 1, Title: &title}).
+		Return(db.FeedSubscription{FeedID: 7, UserID: 1, Title: &title}, nil)
+
+	feed, subscription, err := service.Subscribe(ctx, 1, " "+feedURL+" ", &title, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), feed.ID)
+	assert.Equal(t, &title, subscription.Title)
+	mockQuerier.AssertExpectations(t)
+	// Entries already in the feed are only recorded as seen
+	mockJobQueue.AssertNotCalled(t, "EnqueueItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubscribe_DiscoversFeedFromPage(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	pageURL := "https://example.substack.com"
+	feedURL := "https://example.substack.com/feed"
+	service := NewFeedService(mockQuerier, servePages(t, map[string]string{
+		pageURL: `<html><head><link rel="alternate" type="application/rss+xml" href="/feed"></head></html>`,
+		feedURL: testFeedXML,
+	}), new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+
+	mockQuerier.On("GetFeedByURL", ctx, pageURL).Return(db.Feed{}, pgx.ErrNoRows)
+	mockQuerier.On("GetFeedByURL", ctx, feedURL).Return(db.Feed{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateFeed", ctx, mock.MatchedBy(func(arg db.CreateFeedParams) bool {
+		return arg.Url == feedURL
+	})).Return(db.Feed{ID: 8, Url: feedURL}, nil)
+	mockQuerier.On("InsertFeedEntry", ctx, mock.Anything).Return(int64(1), nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 8, UserID: 1}).Return(db.FeedSubscription{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateFeedSubscription", ctx, db.CreateFeedSubscriptionParams{FeedID: 8, UserID: 1}).
+		Return(db.FeedSubscription{FeedID: 8, UserID: 1}, nil)
+
+	feed, _, err := service.Subscribe(ctx, 1, pageURL, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, feedURL, feed.Url)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestSubscribe_ExistingFeed(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	feedURL := "https://blog.example.com/feed.xml"
+	service := NewFeedService(mockQuerier, feedFetcherFunc(func(rawURL, etag, lastModified string) (*FetchedPage, error) {
+		t.Errorf("stored feed %s was fetched", rawURL)
+		return nil, nil
+	}), new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+	blank := "  "
+
+	mockQuerier.On("GetFeedByURL", ctx, feedURL).Return(db.Feed{ID: 7, Url: feedURL}, nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 7, UserID: 2}).Return(db.FeedSubscription{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateFeedSubscription", ctx, db.CreateFeedSubscriptionParams{FeedID: 7, UserID: 2, Tags: []string{"tech"}}).
+		Return(db.FeedSubscription{FeedID: 7, UserID: 2, Tags: []string{"tech"}}, nil)
+
+	_, subscription, err := service.Subscribe(ctx, 2, feedURL, &blank, []string{" tech ", "Tech", ""})
+
+	require.NoError(t, err)
+	assert.Nil(t, subscription.Title)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestSubscribe_AlreadySubscribed(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewFeedService(mockQuerier, nil, new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+
+	mockQuerier.On("GetFeedByURL", ctx, "https://blog.example.com/feed.xml").Return(db.Feed{ID: 7}, nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 7, UserID: 1}).Return(db.FeedSubscription{FeedID: 7, UserID: 1}, nil)
+
+	_, _, err := service.Subscribe(ctx, 1, "https://blog.example.com/feed.xml", nil, nil)
+
+	assert.ErrorIs(t, err, ErrFeedSubscriptionExists)
+	mockQuerier.AssertNotCalled(t, "CreateFeedSubscription", mock.Anything, mock.Anything)
+}
+
+func TestSubscribe_InvalidFeed(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		pages map[string]string
+	}{
+		{"unreachable", map[string]string{}},
+		{"page without feed", map[string]string{"https://example.com/": "<html><head><title>Home</title></head></html>"}},
+		{"not a feed", map[string]string{"https://example.com/": `{"hello": "world"}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			service := NewFeedService(mockQuerier, servePages(t, tt.pages), new(MockJobQueueService), DefaultFeedConfig())
+			mockQuerier.On("GetFeedByURL", ctx, "https://example.com/").Return(db.Feed{}, pgx.ErrNoRows)
+
+			feed, _, err := service.Subscribe(ctx, 1, "https://example.com/", nil, nil)
+
+			assert.ErrorIs(t, err, ErrInvalidFeed)
+			assert.Nil(t, feed)
+			mockQuerier.AssertNotCalled(t, "CreateFeed", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetSubscription_NotSubscribed(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewFeedService(mockQuerier, nil, new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 7, UserID: 1}).Return(db.FeedSubscription{}, pgx.ErrNoRows)
+
+	feed, _, err := service.GetSubscription(ctx, 1, 7)
+
+	assert.ErrorIs(t, err, ErrFeedNotFound)
+	assert.Nil(t, feed)
+	mockQuerier.AssertNotCalled(t, "GetFeed", mock.Anything, mock.Anything)
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewFeedService(mockQuerier, nil, new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+	blank := ""
+
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 7, UserID: 1}).Return(db.FeedSubscription{FeedID: 7, UserID: 1}, nil)
+	mockQuerier.On("GetFeed", ctx, int32(7)).Return(db.Feed{ID: 7}, nil)
+	mockQuerier.On("UpdateFeedSubscription", ctx, db.UpdateFeedSubscriptionParams{Title: &blank, Tags: []string{}, FeedID: 7, UserID: 1}).
+		Return(db.FeedSubscription{FeedID: 7, UserID: 1, Tags: []string{}}, nil)
+	mockQuerier.On("UpdateFeedSubscription", ctx, db.UpdateFeedSubscriptionParams{Tags: []string{"news"}, FeedID: 7, UserID: 1}).
+		Return(db.FeedSubscription{FeedID: 7, UserID: 1, Tags: []string{"news"}}, nil)
+
+	// A blank title and no tags clear both
+	spaces := "   "
+	_, subscription, err := service.UpdateSubscription(ctx, 1, 7, &spaces, []string{})
+	require.NoError(t, err)
+	assert.Empty(t, subscription.Tags)
+
+	// A missing title is left alone
+	_, subscription, err = service.UpdateSubscription(ctx, 1, 7, nil, []string{"news", " News "})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"news"}, subscription.Tags)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewFeedService(mockQuerier, nil, new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+
+	mockQuerier.On("DeleteFeedSubscription", ctx, db.DeleteFeedSubscriptionParams{FeedID: 7, UserID: 1}).Return(int64(1), nil)
+	mockQuerier.On("DeleteFeedSubscription", ctx, db.DeleteFeedSubscriptionParams{FeedID: 8, UserID: 1}).Return(int64(0), nil)
+
+	assert.NoError(t, service.Unsubscribe(ctx, 1, 7))
+	assert.ErrorIs(t, service.Unsubscribe(ctx, 1, 8), ErrFeedNotFound)
+}
+
+func TestPollDueFeeds_IngestsNewEntries(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	mockQuota := new(MockQuotaService)
+	feedURL := "https://blog.example.com/feed.xml"
+	var requested []string
+	fetcher := feedFetcherFunc(func(rawURL, etag, lastModified string) (*FetchedPage, error) {
+		requested = append(requested, etag, lastModified)
+		return servePages(t, map[string]string{feedURL: testFeedXML})(rawURL, "", "")
+	})
+	service := NewFeedService(mockQuerier, fetcher, mockJobQueue, DefaultFeedConfig())
+	service.SetQuotaService(mockQuota)
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	etag, lastModified := `"v0"`, "Mon, 04 Mar 2024 09:00:00 GMT"
+	newURL := "https://blog.example.com/posts/2"
+	canonical := newURL
+	user1, user2, user3 := int32(1), int32(2), int32(3)
+
+	mockQuerier.On("ListDueFeeds", ctx, db.ListDueFeedsParams{NextPollAt: now, Limit: 25}).
+		Return([]db.Feed{{ID: 7, Url: feedURL, Etag: &etag, LastModified: &lastModified}}, nil)
+	mockQuerier.On("ListFeedEntryGUIDs", ctx, db.ListFeedEntryGUIDsParams{FeedID: 7, Guids: []string{"post-2", "post-1"}}).Return([]string{"post-1"}, nil)
+	mockQuerier.On("InsertFeedEntry", ctx, entryParams(7, "post-2", newURL, "Second post")).Return(int64(1), nil)
+	mockQuerier.On("ListFeedSubscribers", ctx, int32(7)).Return([]db.ListFeedSubscribersRow{
+		{UserID: 1, Tags: []string{"tech"}},
+		{UserID: 2},
+		{UserID: 3},
+	}, nil)
+	// User 2 already saved the post, user 3 is out of quota
+	mockQuerier.On("GetItemByCanonicalURL", ctx, db.GetItemByCanonicalURLParams{UserID: &user1, CanonicalUrl: &canonical}).Return(db.Item{}, pgx.ErrNoRows)
+	mockQuerier.On("GetItemByCanonicalURL", ctx, db.GetItemByCanonicalURLParams{UserID: &user2, CanonicalUrl: &canonical}).Return(db.Item{ID: 40}, nil)
+	mockQuerier.On("GetItemByCanonicalURL", ctx, db.GetItemByCanonicalURLParams{UserID: &user3, CanonicalUrl: &canonical}).Return(db.Item{}, pgx.ErrNoRows)
+	mockQuota.On("ConsumeItem", ctx, int32(1)).Return(nil)
+	mockQuota.On("ConsumeItem", ctx, int32(3)).Return(ErrQuotaExceeded)
+	mockJobQueue.On("EnqueueItem", ctx, int32(1), "Second post", newURL, (*int32)(nil)).Return(&db.Item{ID: 41}, nil)
+	mockQuerier.On("AddItemTags", ctx, db.AddItemTagsParams{Tags: []string{"tech"}, ID: 41}).Return(nil)
+	newETag := `"v1"`
+	title, siteURL := "Example Blog", "https://blog.example.com/"
+	mockQuerier.On("UpdateFeedPolled", ctx, db.UpdateFeedPolledParams{
+		ID:         7,
+		Title:      &title,
+		SiteUrl:    &siteURL,
+		Etag:       &newETag,
+		NextPollAt: now.Add(time.Hour),
+	}).Return(nil)
+
+	err := service.PollDueFeeds(ctx, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{etag, lastModified}, requested, "the stored validators are sent")
+	mockQuerier.AssertExpectations(t)
+	mockQuota.AssertExpectations(t)
+	mockJobQueue.AssertNumberOfCalls(t, "EnqueueItem", 1)
+}
+
+func TestPollDueFeeds_CapsNewEntries(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	feedURL := "https://blog.example.com/feed.xml"
+	config := DefaultFeedConfig()
+	config.MaxNewEntries = 1
+	service := NewFeedService(mockQuerier, servePages(t, map[string]string{feedURL: testFeedXML}), mockJobQueue, config)
+	ctx := context.Background()
+	now := time.Now()
+
+	etag := `"v0"`
+	mockQuerier.On("ListDueFeeds", ctx, mock.Anything).Return([]db.Feed{{ID: 7, Url: feedURL, Etag: &etag}}, nil)
+	mockQuerier.On("ListFeedEntryGUIDs", ctx, mock.Anything).Return([]string{}, nil)
+	mockQuerier.On("ListFeedSubscribers", ctx, int32(7)).Return([]db.ListFeedSubscribersRow{{UserID: 1}}, nil)
+	mockQuerier.On("GetItemByCanonicalURL", ctx, mock.Anything).Return(db.Item{}, pgx.ErrNoRows)
+	mockJobQueue.On("EnqueueItem", ctx, int32(1), "Second post", "https://blog.example.com/posts/2", (*int32)(nil)).Return(&db.Item{ID: 41}, nil)
+	mockQuerier.On("InsertFeedEntry", ctx, entryParams(7, "post-2", "https://blog.example.com/posts/2", "Second post")).Return(int64(1), nil)
+	// The entry past the cap is left for the next poll, which must fetch the whole feed
+	mockQuerier.On("UpdateFeedPolled", ctx, mock.MatchedBy(func(arg db.UpdateFeedPolledParams) bool {
+		return arg.ID == 7 && arg.Etag == nil && arg.LastModified == nil
+	})).Return(nil)
+
+	require.NoError(t, service.PollDueFeeds(ctx, now))
+
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNumberOfCalls(t, "InsertFeedEntry", 1)
+	mockJobQueue.AssertNumberOfCalls(t, "EnqueueItem", 1)
+}
+
+func TestPollDueFeeds_RetriesEntriesThatFailToQueue(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	feedURL := "https://blog.example.com/feed.xml"
+	service := NewFeedService(mockQuerier, servePages(t, map[string]string{feedURL: testFeedXML}), mockJobQueue, DefaultFeedConfig())
+	ctx := context.Background()
+	now := time.Now()
+
+	mockQuerier.On("ListDueFeeds", ctx, mock.Anything).Return([]db.Feed{{ID: 7, Url: feedURL}}, nil)
+	mockQuerier.On("ListFeedEntryGUIDs", ctx, mock.Anything).Return([]string{}, nil)
+	mockQuerier.On("ListFeedSubscribers", ctx, int32(7)).Return([]db.ListFeedSubscribersRow{{UserID: 1}}, nil)
+	mockQuerier.On("GetItemByCanonicalURL", ctx, mock.Anything).Return(db.Item{}, pgx.ErrNoRows)
+	mockJobQueue.On("EnqueueItem", ctx, int32(1), "Second post", "https://blog.example.com/posts/2", (*int32)(nil)).Return(nil, errors.New("database error"))
+	mockJobQueue.On("EnqueueItem", ctx, int32(1), "First post", "https://blog.example.com/posts/1", (*int32)(nil)).Return(&db.Item{ID: 41}, nil)
+	mockQuerier.On("InsertFeedEntry", ctx, entryParams(7, "post-1", "https://blog.example.com/posts/1", "First post")).Return(int64(1), nil)
+	mockQuerier.On("UpdateFeedPolled", ctx, mock.MatchedBy(func(arg db.UpdateFeedPolledParams) bool {
+		return arg.ID == 7 && arg.Etag == nil && arg.LastModified == nil
+	})).Return(nil)
+
+	require.NoError(t, service.PollDueFeeds(ctx, now))
+
+	// Only the queued entry is recorded, so the other is tried again
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNumberOfCalls(t, "InsertFeedEntry", 1)
+}
+
+func TestPollDueFeeds_NotModified(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	etag := `"v1"`
+	service := NewFeedService(mockQuerier, feedFetcherFunc(func(rawURL, ifNoneMatch, lastModified string) (*FetchedPage, error) {
+		return &FetchedPage{URL: mustParseURL(t, rawURL), StatusCode: http.StatusNotModified, ETag: ifNoneMatch}, nil
+	}), new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+	now := time.Now()
+
+	mockQuerier.On("ListDueFeeds", ctx, mock.Anything).Return([]db.Feed{{ID: 7, Url: "https://blog.example.com/feed.xml", Etag: &etag}}, nil)
+	mockQuerier.On("UpdateFeedPolled", ctx, db.UpdateFeedPolledParams{ID: 7, Etag: &etag, NextPollAt: now.Add(time.Hour)}).Return(nil)
+
+	require.NoError(t, service.PollDueFeeds(ctx, now))
+
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "InsertFeedEntry", mock.Anything, mock.Anything)
+}
+
+func TestPollDueFeeds_FailureBacksOff(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewFeedService(mockQuerier, feedFetcherFunc(func(rawURL, etag, lastModified string) (*FetchedPage, error) {
+		if rawURL == "https://down.example.com/feed" {
+			return nil, errors.New("connection refused")
+		}
+		return servePages(t, map[string]string{rawURL: testFeedXML})(rawURL, etag, lastModified)
+	}), new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+	now := time.Now()
+
+	mockQuerier.On("ListDueFeeds", ctx, mock.Anything).Return([]db.Feed{
+		{ID: 7, Url: "https://down.example.com/feed", ConsecutiveFailures: 2},
+		{ID: 8, Url: "https://up.example.com/feed"},
+	}, nil)
+	mockQuerier.On("UpdateFeedPollFailed", ctx, mock.MatchedBy(func(arg db.UpdateFeedPollFailedParams) bool {
+		return arg.ID == 7 && arg.NextPollAt.Equal(now.Add(4*time.Hour)) && *arg.LastError == "connection refused"
+	})).Return(nil)
+	mockQuerier.On("ListFeedEntryGUIDs", ctx, mock.Anything).Return([]string{"post-1", "post-2"}, nil)
+	mockQuerier.On("UpdateFeedPolled", ctx, mock.MatchedBy(func(arg db.UpdateFeedPolledParams) bool { return arg.ID == 8 })).Return(nil)
+
+	require.NoError(t, service.PollDueFeeds(ctx, now))
+
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestFeedBackoff(t *testing.T) {
+	service := &feedService{config: DefaultFeedConfig()}
+
+	assert.Equal(t, time.Hour, service.backoff(1))
+	assert.Equal(t, 2*time.Hour, service.backoff(2))
+	assert.Equal(t, 16*time.Hour, service.backoff(5))
+	assert.Equal(t, 24*time.Hour, service.backoff(6))
+	assert.Equal(t, 24*time.Hour, service.backoff(40))
+}
+
+const testOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Tech">
+      <outline type="rss" text="Example Blog" xmlUrl="https://blog.example.com/feed.xml"/>
+      <outline type="rss" text="Followed" xmlUrl="https://followed.example.com/feed.xml"/>
+    </outline>
+    <outline type="rss" text="Gone" xmlUrl="https://gone.example.com/feed.xml"/>
+    <outline type="rss" text="Broken" xmlUrl="feed.xml"/>
+  </body>
+</opml>`
+
+func TestImportOPML(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	feedURL := "https://blog.example.com/feed.xml"
+	service := NewFeedService(mockQuerier, servePages(t, map[string]string{feedURL: testFeedXML}), new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+
+	mockQuerier.On("GetFeedByURL", ctx, feedURL).Return(db.Feed{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateFeed", ctx, mock.Anything).Return(db.Feed{ID: 7, Url: feedURL}, nil)
+	mockQuerier.On("InsertFeedEntry", ctx, mock.Anything).Return(int64(1), nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 7, UserID: 1}).Return(db.FeedSubscription{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateFeedSubscription", ctx, db.CreateFeedSubscriptionParams{FeedID: 7, UserID: 1, Tags: []string{"Tech"}}).
+		Return(db.FeedSubscription{FeedID: 7, UserID: 1, Tags: []string{"Tech"}}, nil)
+	mockQuerier.On("GetFeedByURL", ctx, "https://followed.example.com/feed.xml").Return(db.Feed{ID: 8}, nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 8, UserID: 1}).Return(db.FeedSubscription{FeedID: 8, UserID: 1}, nil)
+	mockQuerier.On("GetFeedByURL", ctx, "https://gone.example.com/feed.xml").Return(db.Feed{}, pgx.ErrNoRows)
+
+	results, err := service.ImportOPML(ctx, 1, []byte(testOPML), false)
+
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, OPMLImportSubscribed, results[0].Status)
+	assert.Equal(t, int32(7), *results[0].FeedID)
+	assert.Equal(t, []string{"Tech"}, results[0].Tags)
+	assert.Equal(t, OPMLImportAlreadySubscribed, results[1].Status)
+	assert.Equal(t, OPMLImportUnreachable, results[2].Status)
+	assert.NotEmpty(t, results[2].Error)
+	assert.Equal(t, OPMLImportInvalid, results[3].Status)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestImportOPML_DryRun(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	feedURL := "https://blog.example.com/feed.xml"
+	service := NewFeedService(mockQuerier, servePages(t, map[string]string{feedURL: testFeedXML}), new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+
+	// The first feed is stored for other users, so only the subscription is checked before fetching
+	mockQuerier.On("GetFeedByURL", ctx, feedURL).Return(db.Feed{ID: 7}, nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 7, UserID: 1}).Return(db.FeedSubscription{}, pgx.ErrNoRows)
+	mockQuerier.On("GetFeedByURL", ctx, "https://followed.example.com/feed.xml").Return(db.Feed{ID: 8}, nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 8, UserID: 1}).Return(db.FeedSubscription{FeedID: 8, UserID: 1}, nil)
+	mockQuerier.On("GetFeedByURL", ctx, "https://gone.example.com/feed.xml").Return(db.Feed{}, pgx.ErrNoRows)
+
+	results, err := service.ImportOPML(ctx, 1, []byte(testOPML), true)
+
+	require.NoError(t, err)
+	statuses := make([]string, len(results))
+	for i, result := range results {
+		statuses[i] = result.Status
+	}
+	assert.Equal(t, []string{OPMLImportReachable, OPMLImportAlreadySubscribed, OPMLImportUnreachable, OPMLImportInvalid}, statuses)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "CreateFeed", mock.Anything, mock.Anything)
+	mockQuerier.AssertNotCalled(t, "CreateFeedSubscription", mock.Anything, mock.Anything)
+}
+
+func TestImportOPML_FeedErrorKeepsOtherResults(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	feedURL := "https://blog.example.com/feed.xml"
+	service := NewFeedService(mockQuerier, servePages(t, map[string]string{feedURL: testFeedXML}), new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+
+	mockQuerier.On("GetFeedByURL", ctx, feedURL).Return(db.Feed{}, errors.New("database error"))
+	mockQuerier.On("GetFeedByURL", ctx, "https://followed.example.com/feed.xml").Return(db.Feed{ID: 8}, nil)
+	mockQuerier.On("GetFeedSubscription", ctx, db.GetFeedSubscriptionParams{FeedID: 8, UserID: 1}).Return(db.FeedSubscription{FeedID: 8, UserID: 1}, nil)
+	mockQuerier.On("GetFeedByURL", ctx, "https://gone.example.com/feed.xml").Return(db.Feed{}, pgx.ErrNoRows)
+
+	results, err := service.ImportOPML(ctx, 1, []byte(testOPML), false)
+
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, OPMLImportFailed, results[0].Status)
+	assert.Contains(t, results[0].Error, "database error")
+	assert.Equal(t, OPMLImportAlreadySubscribed, results[1].Status)
+	assert.Equal(t, OPMLImportUnreachable, results[2].Status)
+	assert.Equal(t, OPMLImportInvalid, results[3].Status)
+}
+
+func TestImportOPML_InvalidDocument(t *testing.T) {
+	service := NewFeedService(new(test.MockQuerier), nil, new(MockJobQueueService), DefaultFeedConfig())
+
+	_, err := service.ImportOPML(context.Background(), 1, []byte(testFeedXML), false)
+
+	assert.ErrorIs(t, err, ErrInvalidOPML)
+}
+
+func TestExportOPML(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewFeedService(mockQuerier, nil, new(MockJobQueueService), DefaultFeedConfig())
+	ctx := context.Background()
+	feedTitle, customTitle := "Example Blog", "My Blog"
+
+	mockQuerier.On("ListFeedSubscriptionsByUser", ctx, int32(1)).Return([]db.ListFeedSubscriptionsByUserRow{
+		{ID: 7, Url: "https://blog.example.com/feed.xml", Title: &feedTitle, CustomTitle: &customTitle, Tags: []string{"Tech"}},
+		{ID: 8, Url: "https://news.example.com/rss", Tags: []string{}},
+	}, nil)
+
+	data, err := service.ExportOPML(ctx, 1)
+	require.NoError(t, err)
+
+	feeds, err := ParseOPML(data)
+	require.NoError(t, err)
+	assert.Equal(t, []OPMLFeed{
+		{URL: "https://blog.example.com/feed.xml", Title: "My Blog", Tags: []string{"Tech"}},
+		{URL: "https://news.example.com/rss", Title: "https://news.example.com/rss"},
+	}, feeds)
+}
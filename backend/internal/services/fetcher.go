@@ -0,0 +1,459 @@
+package services
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+var (
+	// ErrDisallowedByRobots is returned for pages the site's robots.txt asks crawlers not to fetch
+	ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+	// ErrBodyTooLarge is returned for responses larger than the configured maximum body size
+	ErrBodyTooLarge = errors.New("response body too large")
+)
+
+// HTTPStatusError is returned for responses with a 4xx or 5xx status
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("request to %s failed with status %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+const (
+	// maxFetchBodySize is the default largest response read, sized for long PDFs
+	maxFetchBodySize = 50 << 20
+	// robotsTTL is how long a host's robots.txt is trusted before it is fetched again
+	robotsTTL = time.Hour
+	// robotsRetryTTL is used instead when robots.txt could not be fetched,
+	// so an outage does not decide the host's rules for long
+	robotsRetryTTL = 5 * time.Minute
+	// maxCrawlDelay caps the Crawl-delay a robots.txt can impose on the workers
+	maxCrawlDelay = 30 * time.Second
+	// maxCachedBodySize keeps large downloads, such as PDFs, out of the cache
+	maxCachedBodySize = 2 << 20
+	// maxRedirects matches the limit of the default HTTP client
+	maxRedirects = 10
+	// hostIdleTTL is how long a host's state is kept after its last request;
+	// by then its robots.txt would be fetched again anyway
+	hostIdleTTL = robotsTTL
+	// hostSweepInterval is how often idle hosts are looked for
+	hostSweepInterval = time.Minute
+)
+
+// FetcherConfig controls how the shared fetcher downloads pages
+type FetcherConfig struct {
+	UserAgent   string
+	Timeout     time.Duration
+	MaxBodySize int64
+	// DomainInterval is the minimum time between requests to one host. A longer
+	// Crawl-delay from the host's robots.txt takes precedence.
+	DomainInterval time.Duration
+	// DomainConcurrency is how many requests to one host may be in flight
+	DomainConcurrency int
+	RespectRobots     bool
+	// CacheEntries is how many responses are kept for revalidation with
+	// ETag and Last-Modified; zero disables the cache
+	CacheEntries int
+}
+
+// DefaultFetcherConfig returns the settings used when none are configured
+func DefaultFetcherConfig() FetcherConfig {
+	return FetcherConfig{
+		UserAgent:         "BriefBot/1.0 (+https://github.com/yamirghofran/briefbot)",
+		Timeout:           30 * time.Second,
+		MaxBodySize:       maxFetchBodySize,
+		DomainInterval:    time.Second,
+		DomainConcurrency: 2,
+		RespectRobots:     true,
+		CacheEntries:      256,
+	}
+}
+
+// Fetcher downloads pages for every scrape in the process, so that limits
+// per host hold across workers and retries
+type Fetcher struct {
+	client *http.Client
+	config FetcherConfig
+
+	mu        sync.Mutex
+	hosts     map[string]*hostState
+	lastSweep time.Time
+	cache     *responseCache
+}
+
+// hostState tracks the requests and robots.txt rules of one host
+type hostState struct {
+	// Guarded by Fetcher.mu
+	slots      chan struct{} // Semaphore bounding concurrent requests
+	next       time.Time     // Earliest start of the next request
+	lastUsed   time.Time
+	crawlDelay time.Duration
+
+	robotsMu      sync.Mutex
+	robots        *robotstxt.RobotsData
+	robotsExpires time.Time
+}
+
+func NewFetcher(config FetcherConfig) *Fetcher {
+	defaults := DefaultFetcherConfig()
+	if config.UserAgent == "" {
+		config.UserAgent = defaults.UserAgent
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaults.MaxBodySize
+	}
+	if config.DomainConcurrency <= 0 {
+		config.DomainConcurrency = defaults.DomainConcurrency
+	}
+
+	fetcher := &Fetcher{
+		client: &http.Client{Timeout: config.Timeout},
+		config: config,
+		hosts:  make(map[string]*hostState),
+	}
+	if config.CacheEntries > 0 {
+		fetcher.cache = newResponseCache(config.CacheEntries)
+	}
+	return fetcher
+}
+
+// Fetch downloads a URL, waiting for its host's rate limit and revalidating
+// cached responses. It implements PageFetcher.
+func (f *Fetcher) Fetch(rawURL string) (*FetchedPage, error) {
+	return f.fetch(rawURL, pageAccept, "", "", true)
+}
+
+// FetchIfModified downloads a URL unless it is unchanged since the response
+// that carried the given ETag and Last-Modified values, in which case the
+// page has status 304 Not Modified and no body. Callers that store the
+// validators, such as the feed poller, keep them across restarts.
+func (f *Fetcher) FetchIfModified(rawURL, etag, lastModified string) (*FetchedPage, error) {
+	return f.fetch(rawURL, feedAccept, etag, lastModified, false)
+}
+
+const (
+	pageAccept = "text/html,application/xhtml+xml,application/pdf;q=0.9,*/*;q=0.8"
+	feedAccept = "application/rss+xml,application/atom+xml,application/feed+json,application/xml;q=0.9,text/xml;q=0.9,application/json;q=0.8,*/*;q=0.5"
+)
+
+// fetch downloads a URL. checkRobots applies robots.txt to redirect targets;
+// the first URL is checked by the caller with CheckRobots.
+func (f *Fetcher) fetch(rawURL, accept, etag, lastModified string, checkRobots bool) (*FetchedPage, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q", rawURL)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.config.UserAgent)
+	req.Header.Set("Accept", accept)
+
+	// Validators from the caller take precedence over the cache
+	conditional := etag != "" || lastModified != ""
+	var cached *cachedResponse
+	if f.cache != nil && !conditional {
+		if cached = f.cache.get(u.String()); cached != nil {
+			etag, lastModified = cached.etag, cached.lastModified
+		}
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	state := f.host(u.Host)
+	release := f.wait(state)
+	defer func() { release() }()
+
+	// Redirect targets get the same robots.txt check and per-host limit as
+	// the first URL; feeds skip robots.txt as they do for the first URL. Only one host slot is held at a time, so two fetches
+	// redirecting to each other's hosts cannot deadlock.
+	client := *f.client
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if checkRobots {
+			if err := f.CheckRobots(next.URL.String()); err != nil {
+				return err
+			}
+		}
+		if target := f.host(next.URL.Host); target != state {
+			release()
+			state, release = target, f.wait(target)
+		} else {
+			f.pace(state)
+		}
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		if cached != nil {
+			page := cached.page
+			return &page, nil
+		}
+		if conditional {
+			return &FetchedPage{
+				URL:          resp.Request.URL,
+				StatusCode:   resp.StatusCode,
+				ETag:         firstNonEmpty(resp.Header.Get("ETag"), etag),
+				LastModified: firstNonEmpty(resp.Header.Get("Last-Modified"), lastModified),
+			}, nil
+		}
+	}
+	if resp.StatusCode >= 400 {
+		statusErr := &HTTPStatusError{URL: rawURL, StatusCode: resp.StatusCode}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, challengeBodySize))
+		if isChallengeResponse(resp.Header, body) {
+			return nil, fmt.Errorf("%w: %w", ErrCaptcha, statusErr)
+		}
+		return nil, statusErr
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", rawURL, err)
+	}
+	if int64(len(body)) > f.config.MaxBodySize {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrBodyTooLarge, rawURL, f.config.MaxBodySize)
+	}
+
+	page := &FetchedPage{
+		URL:          resp.Request.URL,
+		StatusCode:   resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if f.cache != nil && !conditional && cacheable(resp, body) {
+		f.cache.put(&cachedResponse{
+			key:          u.String(),
+			etag:         page.ETag,
+			lastModified: page.LastModified,
+			page:         *page,
+		})
+	}
+	return page, nil
+}
+
+// CheckRobots returns ErrDisallowedByRobots when the site's robots.txt asks
+// crawlers with this user agent not to fetch the URL. Hosts whose robots.txt
+// cannot be fetched are treated as allowing everything.
+func (f *Fetcher) CheckRobots(rawURL string) error {
+	if !f.config.RespectRobots {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+
+	robots := f.robots(u)
+	if robots != nil && !robots.TestAgent(u.RequestURI(), f.agentToken()) {
+		return fmt.Errorf("%w: %s", ErrDisallowedByRobots, rawURL)
+	}
+	return nil
+}
+
+// host returns the state of a host, creating it on first use
+func (f *Fetcher) host(host string) *hostState {
+	host = strings.ToLower(host)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	f.sweep(now)
+	state, ok := f.hosts[host]
+	if !ok {
+		state = &hostState{slots: make(chan struct{}, f.config.DomainConcurrency)}
+		f.hosts[host] = state
+	}
+	state.lastUsed = now
+	return state
+}
+
+// sweep drops hosts that have been idle for hostIdleTTL, so the map does not
+// grow with every host ever fetched. Hosts with requests in flight are kept.
+func (f *Fetcher) sweep(now time.Time) {
+	if now.Sub(f.lastSweep) < hostSweepInterval {
+		return
+	}
+	f.lastSweep = now
+
+	for host, state := range f.hosts {
+		if now.Sub(state.lastUsed) >= hostIdleTTL && len(state.slots) == 0 {
+			delete(f.hosts, host)
+		}
+	}
+}
+
+// wait blocks until a request to the host may start, returning the function
+// that frees its slot once the response has been read
+func (f *Fetcher) wait(state *hostState) func() {
+	state.slots <- struct{}{}
+	f.pace(state)
+	return func() { <-state.slots }
+}
+
+// pace blocks until the host's interval since its previous request has passed
+func (f *Fetcher) pace(state *hostState) {
+	f.mu.Lock()
+	start := time.Now()
+	if state.next.After(start) {
+		start = state.next
+	}
+	state.next = start.Add(max(f.config.DomainInterval, state.crawlDelay))
+	f.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
+
+// robots returns the host's parsed robots.txt, fetching it when missing or
+// expired. It returns nil when the file could not be fetched or parsed.
+func (f *Fetcher) robots(u *url.URL) *robotstxt.RobotsData {
+	state := f.host(u.Host)
+	state.robotsMu.Lock()
+	defer state.robotsMu.Unlock()
+
+	if time.Now().Before(state.robotsExpires) {
+		return state.robots
+	}
+
+	robotsURL := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}).String()
+	robots, err := f.fetchRobots(robotsURL)
+	state.robots, state.robotsExpires = robots, time.Now().Add(robotsTTL)
+	if err != nil {
+		log.Printf("Failed to fetch %s, allowing all pages: %v", robotsURL, err)
+		state.robotsExpires = time.Now().Add(robotsRetryTTL)
+	}
+
+	var crawlDelay time.Duration
+	if robots != nil {
+		crawlDelay = min(robots.FindGroup(f.agentToken()).CrawlDelay, maxCrawlDelay)
+	}
+	f.mu.Lock()
+	state.crawlDelay = crawlDelay
+	f.mu.Unlock()
+	return robots
+}
+
+func (f *Fetcher) fetchRobots(robotsURL string) (*robotstxt.RobotsData, error) {
+	req, err := http.NewRequest(http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.config.UserAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		// Treated as temporary rather than as a disallow of the whole site
+		return nil, &HTTPStatusError{URL: robotsURL, StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 512<<10))
+	if err != nil {
+		return nil, err
+	}
+	return robotstxt.FromStatusAndBytes(resp.StatusCode, body)
+}
+
+// agentToken is the product name robots.txt groups are matched against,
+// e.g. "BriefBot" for "BriefBot/1.0 (+https://...)"
+func (f *Fetcher) agentToken() string {
+	token, _, _ := strings.Cut(f.config.UserAgent, "/")
+	return strings.TrimSpace(token)
+}
+
+// cacheable reports whether a response can be revalidated later
+func cacheable(resp *http.Response, body []byte) bool {
+	if resp.StatusCode != http.StatusOK || len(body) > maxCachedBodySize {
+		return false
+	}
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// cachedResponse is a page kept with the validators needed to revalidate it
+type cachedResponse struct {
+	key          string
+	etag         string
+	lastModified string
+	page         FetchedPage
+}
+
+// responseCache is a fixed-size least recently used cache of responses
+type responseCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Most recently used first
+	entries  map[string]*list.Element
+}
+
+func newResponseCache(capacity int) *responseCache {
+	return &responseCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *responseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cachedResponse)
+}
+
+func (c *responseCache) put(entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFetcherConfig returns settings without throttling so tests run quickly
+func testFetcherConfig() FetcherConfig {
+	config := DefaultFetcherConfig()
+	config.UserAgent = "TestBot/2.0 (+https://example.com/bot)"
+	config.DomainInterval = 0
+	return config
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<html><body>Hello</body></html>"))
+	}))
+	defer server.Close()
+
+	page, err := NewFetcher(testFetcherConfig()).Fetch(server.URL + "/old")
+
+	require.NoError(t, err)
+	assert.Equal(t, "TestBot/2.0 (+https://example.com/bot)", userAgent)
+	assert.Equal(t, server.URL+"/new", page.URL.String())
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", page.ContentType)
+	assert.Equal(t, "<html><body>Hello</body></html>", string(page.Body))
+}
+
+func TestFetcher_Fetch_InvalidURL(t *testing.T) {
+	fetcher := NewFetcher(testFetcherConfig())
+
+	for _, rawURL := range []string{"ftp://example.com/file", "not a url", "https://"} {
+		_, err := fetcher.Fetch(rawURL)
+		assert.Error(t, err, rawURL)
+	}
+}
+
+func TestFetcher_Fetch_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer server.Close()
+
+	page, err := NewFetcher(testFetcherConfig()).Fetch(server.URL + "/missing")
+
+	assert.Nil(t, page)
+	var statusErr *HTTPStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusGone, statusErr.StatusCode)
+	assert.Contains(t, err.Error(), "410 Gone")
+}
+
+func TestFetcher_Fetch_MaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 1024)))
+	}))
+	defer server.Close()
+
+	config := testFetcherConfig()
+	config.MaxBodySize = 1023
+	_, err := NewFetcher(config).Fetch(server.URL)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+
+	config.MaxBodySize = 1024
+	page, err := NewFetcher(config).Fetch(server.URL)
+	require.NoError(t, err)
+	assert.Len(t, page.Body, 1024)
+}
+
+func TestFetcher_Fetch_RevalidatesCachedResponses(t *testing.T) {
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 04 Mar 2024 10:00:00 GMT" {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 04 Mar 2024 10:00:00 GMT")
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><body>Cached</body></html>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(testFetcherConfig())
+	first, err := fetcher.Fetch(server.URL + "/article")
+	require.NoError(t, err)
+	second, err := fetcher.Fetch(server.URL + "/article")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int32(1), notModified.Load())
+	assert.Equal(t, first.Body, second.Body)
+	assert.Equal(t, "text/html", second.ContentType)
+	assert.Equal(t, http.StatusOK, second.StatusCode)
+}
+
+func TestFetcher_Fetch_SkipsCacheWithoutValidators(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		assert.Empty(t, r.Header.Get("If-Modified-Since"))
+		_, _ = w.Write([]byte("fresh"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(testFetcherConfig())
+	for range 2 {
+		_, err := fetcher.Fetch(server.URL)
+		require.NoError(t, err)
+	}
+}
+
+func TestFetcher_FetchIfModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept"), "application/rss+xml")
+		if r.Header.Get("If-None-Match") == `"v2"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v2"`)
+		w.Header().Set("Last-Modified", "Tue, 05 Mar 2024 10:00:00 GMT")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte("<rss/>"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(testFetcherConfig())
+	page, err := fetcher.FetchIfModified(server.URL+"/feed", `"v1"`, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+	assert.Equal(t, `"v2"`, page.ETag)
+	assert.Equal(t, "Tue, 05 Mar 2024 10:00:00 GMT", page.LastModified)
+
+	page, err = fetcher.FetchIfModified(server.URL+"/feed", `"v2"`, "Tue, 05 Mar 2024 10:00:00 GMT")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, page.StatusCode)
+	assert.Empty(t, page.Body)
+	assert.Equal(t, `"v2"`, page.ETag, "the validators are kept for the next request")
+}
+
+func TestFetcher_Fetch_DomainInterval(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	config := testFetcherConfig()
+	config.DomainInterval = 100 * time.Millisecond
+	fetcher := NewFetcher(config)
+
+	start := time.Now()
+	for i := range 3 {
+		_, err := fetcher.Fetch(fmt.Sprintf("%s/%d", server.URL, i))
+		require.NoError(t, err)
+	}
+
+	// The first request starts at once, the next two wait an interval each
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestFetcher_Fetch_DomainConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	config := testFetcherConfig()
+	config.DomainConcurrency = 2
+	fetcher := NewFetcher(config)
+
+	var wg sync.WaitGroup
+	for i := range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := fetcher.Fetch(fmt.Sprintf("%s/%d", server.URL, i))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestFetcher_Fetch_RedirectWaitsForTargetHost(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/moved", http.StatusFound)
+	}))
+	defer origin.Close()
+
+	config := testFetcherConfig()
+	config.RespectRobots = false
+	config.DomainInterval = 100 * time.Millisecond
+	fetcher := NewFetcher(config)
+
+	_, err := fetcher.Fetch(target.URL + "/first")
+	require.NoError(t, err)
+
+	start := time.Now()
+	page, err := fetcher.Fetch(origin.URL + "/old")
+
+	require.NoError(t, err)
+	assert.Equal(t, target.URL+"/moved", page.URL.String())
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "the redirect waits for the target host's interval")
+}
+
+func TestFetcher_Fetch_RedirectDisallowedByRobots(t *testing.T) {
+	var privateRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+		case "/old":
+			http.Redirect(w, r, "/private/report", http.StatusMovedPermanently)
+		default:
+			privateRequests.Add(1)
+			_, _ = w.Write([]byte("secret"))
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewFetcher(testFetcherConfig()).Fetch(server.URL + "/old")
+
+	assert.ErrorIs(t, err, ErrDisallowedByRobots)
+	assert.Zero(t, privateRequests.Load())
+}
+
+func TestFetcher_EvictsIdleHosts(t *testing.T) {
+	fetcher := NewFetcher(testFetcherConfig())
+	idle := fetcher.host("idle.example.com")
+	busy := fetcher.host("busy.example.com")
+	busy.slots <- struct{}{}
+
+	idle.lastUsed = time.Now().Add(-hostIdleTTL)
+	busy.lastUsed = time.Now().Add(-hostIdleTTL)
+	fetcher.lastSweep = time.Now().Add(-hostSweepInterval)
+	fetcher.host("new.example.com")
+
+	assert.NotContains(t, fetcher.hosts, "idle.example.com")
+	assert.Contains(t, fetcher.hosts, "busy.example.com", "hosts with requests in flight are kept")
+	assert.Contains(t, fetcher.hosts, "new.example.com")
+}
+
+func TestFetcher_CheckRobots(t *testing.T) {
+	var robotsRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsRequests.Add(1)
+			_, _ = w.Write([]byte("User-agent: TestBot\nDisallow: /private\n\nUser-agent: *\nDisallow: /\n"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(testFetcherConfig())
+
+	assert.NoError(t, fetcher.CheckRobots(server.URL+"/articles/1"))
+	assert.ErrorIs(t, fetcher.CheckRobots(server.URL+"/private/report"), ErrDisallowedByRobots)
+	assert.Equal(t, int32(1), robotsRequests.Load(), "robots.txt is fetched once per host")
+
+	config := testFetcherConfig()
+	config.RespectRobots = false
+	assert.NoError(t, NewFetcher(config).CheckRobots(server.URL+"/private/report"))
+}
+
+func TestFetcher_CheckRobots_Unavailable(t *testing.T) {
+	for name, status := range map[string]int{
+		"not found":    http.StatusNotFound,
+		"server error": http.StatusServiceUnavailable,
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			assert.NoError(t, NewFetcher(testFetcherConfig()).CheckRobots(server.URL+"/articles/1"))
+		})
+	}
+}
+
+func TestFetcher_CheckRobots_CrawlDelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: 0.1\n"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(testFetcherConfig())
+	require.NoError(t, fetcher.CheckRobots(server.URL+"/a"))
+
+	start := time.Now()
+	for _, path := range []string{"/a", "/b"} {
+		_, err := fetcher.Fetch(server.URL + path)
+		require.NoError(t, err)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestScrape_DisallowedByRobots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /members\n"))
+			return
+		}
+		t.Errorf("disallowed page %s was fetched", r.URL.Path)
+	}))
+	defer server.Close()
+
+	result, err := NewScraperWithFetcher(NewFetcher(testFetcherConfig())).Scrape(server.URL + "/members/article")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrDisallowedByRobots)
+}
+
+func TestResponseCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newResponseCache(2)
+	cache.put(&cachedResponse{key: "a"})
+	cache.put(&cachedResponse{key: "b"})
+	require.NotNil(t, cache.get("a"))
+	cache.put(&cachedResponse{key: "c"})
+
+	assert.NotNil(t, cache.get("a"))
+	assert.Nil(t, cache.get("b"))
+	assert.NotNil(t, cache.get("c"))
+}
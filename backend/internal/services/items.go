@@ -2,30 +2,84 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/yamirghofran/briefbot/internal/db"
 )
 
+// ErrItemNotFound is returned when an item does not exist or belongs to another user
+var ErrItemNotFound = errors.New("item not found")
+
+// ErrInvalidUpload is returned for upload content types that cannot become
+// items and for upload keys outside the user's upload folder
+var ErrInvalidUpload = errors.New("invalid upload")
+
+// ErrUploadsUnavailable is returned for file uploads when R2 storage is not configured
+var ErrUploadsUnavailable = errors.New("file uploads not available")
+
+// uploadContentTypes are the file types that can be uploaded as items
+var uploadContentTypes = map[string]bool{
+	"application/pdf": true,
+}
+
+// UploadStore is the part of R2Service needed for file uploads
+type UploadStore interface {
+	GenerateUploadURL(ctx context.Context, contentType string, folder string) (*UploadURLResponse, error)
+	GetPublicURL(key string) string
+	DeleteFile(ctx context.Context, key string) error
+	ExtractKeyFromURL(url string) string
+}
+
 type ItemService interface {
 	// Background processing methods
-	CreateItemAsync(ctx context.Context, userID int32, url string) (*db.Item, error)
+	CreateItemAsync(ctx context.Context, userID int32, url string, workspaceID *int32) (*db.Item, bool, error)
 	ProcessURL(ctx context.Context, userID int32, url string) (*db.Item, error)
 
+	// File upload methods
+	CreateUploadURL(ctx context.Context, userID int32, contentType string) (*UploadURLResponse, error)
+	CreateItemFromUpload(ctx context.Context, userID int32, key string, workspaceID *int32) (*db.Item, error)
+
+	// Pasted content and file methods, processed without scraping
+	CreateItemFromSource(ctx context.Context, userID int32, title string, sourceType string, content string, workspaceID *int32) (*db.Item, error)
+	CreateItemFromFile(ctx context.Context, userID int32, title string, filename string, contentType string, data []byte, workspaceID *int32) (*db.Item, error)
+
 	// Traditional CRUD methods
 	CreateItem(ctx context.Context, userID *int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string) (*db.Item, error)
-	GetItem(ctx context.Context, id int32) (*db.Item, error)
+	GetItem(ctx context.Context, userID int32, id int32) (*db.Item, error)
 	GetItemsByUser(ctx context.Context, userID *int32) ([]db.Item, error)
 	GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]db.Item, error)
 	GetUnreadItemsFromPreviousDay(ctx context.Context) ([]db.Item, error)
-	UpdateItem(ctx context.Context, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string, isRead *bool) error
-	PatchItem(ctx context.Context, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error)
-	MarkItemAsRead(ctx context.Context, id int32) error
-	ToggleItemReadStatus(ctx context.Context, id int32) (*db.Item, error)
-	DeleteItem(ctx context.Context, id int32) error
-	GetItemProcessingStatus(ctx context.Context, itemID int32) (*ItemStatus, error)
-	GetItemsByProcessingStatus(ctx context.Context, status *string) ([]db.Item, error)
+	UpdateItem(ctx context.Context, userID int32, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string) error
+	PatchItem(ctx context.Context, userID int32, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error)
+	DeleteItem(ctx context.Context, userID int32, id int32) error
+	GetItemProcessingStatus(ctx context.Context, userID int32, itemID int32) (*ItemStatus, error)
+	GetItemsByProcessingStatus(ctx context.Context, userID int32, status *string) ([]db.Item, error)
+
+	// Paginated, filtered and sorted listing
+	ListItems(ctx context.Context, userID int32, query ItemListQuery) (*ItemPage, error)
+
+	// Full-text search
+	SearchItems(ctx context.Context, userID int32, search ItemSearch) ([]SearchResult, error)
+
+	// Workspace sharing methods
+	GetItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error)
+	GetUnreadItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error)
+	MoveItemToWorkspace(ctx context.Context, userID int32, id int32, workspaceID *int32) (*db.Item, error)
+
+	// Per-user read state methods
+	MarkItemAsRead(ctx context.Context, userID int32, id int32) error
+	SetItemReadStatus(ctx context.Context, userID int32, id int32, isRead bool) error
+	ToggleItemReadStatus(ctx context.Context, userID int32, id int32) (*db.Item, bool, error)
+	GetReadState(ctx context.Context, userID int32, itemIDs []int32) (map[int32]bool, error)
+
+	// Daily item quota
+	SetQuotaService(quotaService QuotaService)
+	// File uploads
+	SetUploadStore(uploadStore UploadStore)
 }
 
 // Problem: The ItemService interface has 15+ methods mixing CRUD operations, background processing, and status management. Clients might only need a subset.
@@ -44,7 +98,7 @@ type ItemService interface {
 // }
 // type ItemReadStatus interface {
 //     MarkItemAsRead(ctx context.Context, id int32) error
-//     ToggleItemReadStatus(ctx context.Context, id int32) (*db.Item, error)
+//     ToggleItemReadStatus(ctx context.Context, id int32) (*db.Item, bool, error)
 //     GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]db.Item, error)
 // }
 
@@ -54,6 +108,8 @@ type itemService struct {
 	scrapingService ScrapingService
 	jobQueueService JobQueueService
 	sseManager      *SSEManager
+	quotaService    QuotaService
+	uploadStore     UploadStore
 }
 
 func NewItemService(querier db.Querier, aiService AIService, scrapingService ScrapingService, jobQueueService JobQueueService) ItemService {
@@ -71,30 +127,235 @@ func (s *itemService) SetSSEManager(sseManager *SSEManager) {
 	s.sseManager = sseManager
 }
 
-// CreateItemAsync creates an item asynchronously - just saves the URL and returns immediately
-func (s *itemService) CreateItemAsync(ctx context.Context, userID int32, url string) (*db.Item, error) {
+// SetQuotaService makes CreateItemAsync count submissions against the daily item quota
+func (s *itemService) SetQuotaService(quotaService QuotaService) {
+	s.quotaService = quotaService
+}
+
+// SetUploadStore enables uploading files, such as PDFs, as items
+func (s *itemService) SetUploadStore(uploadStore UploadStore) {
+	s.uploadStore = uploadStore
+}
+
+// CreateItemAsync creates an item asynchronously - just saves the URL and returns immediately.
+// When workspaceID is set the item is shared with that workspace, which requires
+// at least the member role. When the user already saved the page, under this or
+// another URL with the same canonical form, the existing item is returned with
+// duplicate set, and no quota is used; it is shared with the workspace if one is
+// given. An existing item that failed to process is queued again instead,
+// counting against the quota like a new one.
+func (s *itemService) CreateItemAsync(ctx context.Context, userID int32, url string, workspaceID *int32) (*db.Item, bool, error) {
+	// The workspace is checked first so a request it refuses changes nothing
+	if err := s.authorizeWorkspace(ctx, userID, workspaceID); err != nil {
+		return nil, false, err
+	}
+
+	existing, err := findItemByURL(ctx, s.querier, userID, url)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		if err := s.shareExisting(ctx, existing, workspaceID); err != nil {
+			return nil, false, err
+		}
+		if existing.ProcessingStatus == nil || *existing.ProcessingStatus != ProcessingStatusFailed {
+			return existing, true, nil
+		}
+		if err := s.requeueItem(ctx, userID, existing); err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+
+	if err := s.consumeItemQuota(ctx, userID); err != nil {
+		return nil, false, err
+	}
+
 	// For async creation, we just save the URL with a placeholder title
 	// The actual processing will happen in the background
-	item, err := s.jobQueueService.EnqueueItem(ctx, userID, url, url)
+	item, err := s.jobQueueService.EnqueueItem(ctx, userID, url, url, workspaceID)
+	if errors.Is(err, ErrDuplicateItem) {
+		// Saved by a concurrent request since the lookup above
+		if existing, findErr := findItemByURL(ctx, s.querier, userID, url); findErr == nil && existing != nil {
+			return existing, true, nil
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to enqueue item: %w", err)
+		return nil, false, fmt.Errorf("failed to enqueue item: %w", err)
 	}
 
+	return item, false, nil
+}
+
+// shareExisting shares an item the user already saved with the workspace a
+// new submission asked for. Items stay where they are when workspaceID is nil.
+func (s *itemService) shareExisting(ctx context.Context, item *db.Item, workspaceID *int32) error {
+	if workspaceID == nil || (item.WorkspaceID != nil && *item.WorkspaceID == *workspaceID) {
+		return nil
+	}
+	updated, err := s.querier.UpdateItemWorkspace(ctx, db.UpdateItemWorkspaceParams{
+		ID:          item.ID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move item: %w", err)
+	}
+	*item = updated
+	return nil
+}
+
+// requeueItem resets a failed item to pending so the worker processes it again
+func (s *itemService) requeueItem(ctx context.Context, userID int32, item *db.Item) error {
+	if err := s.consumeItemQuota(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.jobQueueService.RetryItem(ctx, item.ID); err != nil {
+		return fmt.Errorf("failed to requeue item: %w", err)
+	}
+
+	pending := ProcessingStatusPending
+	item.ProcessingStatus = &pending
+	item.ProcessingError = nil
+	item.FailureReason = nil
+	return nil
+}
+
+// findItemByURL returns the user's item whose canonical URL matches url, or
+// nil when they have none
+func findItemByURL(ctx context.Context, querier db.Querier, userID int32, url string) (*db.Item, error) {
+	canonical, err := CanonicalizeURL(url)
+	if err != nil {
+		return nil, nil
+	}
+	item, err := querier.GetItemByCanonicalURL(ctx, db.GetItemByCanonicalURLParams{
+		UserID:       &userID,
+		CanonicalUrl: &canonical,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up item: %w", err)
+	}
+	return &item, nil
+}
+
+// authorizeCreate checks the user may share with the workspace, then counts
+// the new item against their daily quota
+func (s *itemService) authorizeCreate(ctx context.Context, userID int32, workspaceID *int32) error {
+	if err := s.authorizeWorkspace(ctx, userID, workspaceID); err != nil {
+		return err
+	}
+	return s.consumeItemQuota(ctx, userID)
+}
+
+// authorizeWorkspace checks the user may share items with the workspace, if any
+func (s *itemService) authorizeWorkspace(ctx context.Context, userID int32, workspaceID *int32) error {
+	if workspaceID == nil {
+		return nil
+	}
+	_, err := requireWorkspaceRole(ctx, s.querier, userID, *workspaceID, WorkspaceRoleMember)
+	return err
+}
+
+// consumeItemQuota counts an item against the user's daily quota
+func (s *itemService) consumeItemQuota(ctx context.Context, userID int32) error {
+	if s.quotaService == nil {
+		return nil
+	}
+	return s.quotaService.ConsumeItem(ctx, userID)
+}
+
+// CreateItemFromSource queues pasted text, HTML or Markdown. The content is
+// stored with the item and processed like a scraped page. A title given here
+// is kept; otherwise the extracted title is used.
+func (s *itemService) CreateItemFromSource(ctx context.Context, userID int32, title string, sourceType string, content string, workspaceID *int32) (*db.Item, error) {
+	if err := validateSource(sourceType, content); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeCreate(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.jobQueueService.EnqueueSourceItem(ctx, userID, strings.TrimSpace(title), sourceType, content, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue item: %w", err)
+	}
 	return item, nil
 }
 
+// CreateItemFromFile queues an uploaded text, Markdown, HTML, XHTML or PDF
+// file. Text of PDFs is extracted here so that only text is stored.
+func (s *itemService) CreateItemFromFile(ctx context.Context, userID int32, title string, filename string, contentType string, data []byte, workspaceID *int32) (*db.Item, error) {
+	sourceType, pdf, ok := fileSourceType(filename, contentType)
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported file type %q", ErrInvalidSource, filename)
+	}
+	if len(data) > MaxSourceSize {
+		return nil, fmt.Errorf("%w: file is larger than %d MB", ErrInvalidSource, MaxSourceSize>>20)
+	}
+
+	content := string(data)
+	if pdf {
+		parsed, err := ParsePDF(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSource, err)
+		}
+		sourceType, content = SourceTypeMarkdown, parsed.Content
+	}
+	return s.CreateItemFromSource(ctx, userID, title, sourceType, content, workspaceID)
+}
+
+// uploadFolder is where a user's uploads are stored, so keys can be checked against their owner
+func uploadFolder(userID int32) string {
+	return fmt.Sprintf("uploads/%d", userID)
+}
+
+// isUploadOf reports whether key names a file directly in the user's upload folder
+func isUploadOf(key string, userID int32) bool {
+	name, ok := strings.CutPrefix(key, uploadFolder(userID)+"/")
+	return ok && name != "" && !strings.Contains(name, "/")
+}
+
+// CreateUploadURL returns a presigned URL the client uploads a file to before
+// calling CreateItemFromUpload with the returned key
+func (s *itemService) CreateUploadURL(ctx context.Context, userID int32, contentType string) (*UploadURLResponse, error) {
+	if s.uploadStore == nil {
+		return nil, ErrUploadsUnavailable
+	}
+	if !uploadContentTypes[contentType] {
+		return nil, fmt.Errorf("%w: unsupported content type %q", ErrInvalidUpload, contentType)
+	}
+	return s.uploadStore.GenerateUploadURL(ctx, contentType, uploadFolder(userID))
+}
+
+// CreateItemFromUpload queues an uploaded file for processing like a submitted
+// URL. The scraper reads the file from its public URL.
+func (s *itemService) CreateItemFromUpload(ctx context.Context, userID int32, key string, workspaceID *int32) (*db.Item, error) {
+	if s.uploadStore == nil {
+		return nil, ErrUploadsUnavailable
+	}
+	if !isUploadOf(key, userID) {
+		return nil, fmt.Errorf("%w: key %q is not one of your uploads", ErrInvalidUpload, key)
+	}
+	item, _, err := s.CreateItemAsync(ctx, userID, s.uploadStore.GetPublicURL(key), workspaceID)
+	return item, err
+}
+
 // ProcessURL processes a URL synchronously (for backward compatibility or manual processing)
 func (s *itemService) ProcessURL(ctx context.Context, userID int32, url string) (*db.Item, error) {
-	content, err := s.scrapingService.Scrape(url)
+	scraped, err := s.scrapingService.Scrape(url)
 	if err != nil {
 		return nil, err
 	}
+	content := scraped.Content
 	extraction, err := s.aiService.ExtractContent(ctx, content)
 	if err != nil {
 		return nil, err
 	}
+	applyScrapeMetadata(&extraction, scraped)
 
-	summary, err := s.aiService.SummarizeContent(ctx, content)
+	summary, err := s.aiService.SummarizeContent(ctx, content, SummaryOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -127,14 +388,44 @@ func (s *itemService) CreateItem(ctx context.Context, userID *int32, title strin
 	return &item, nil
 }
 
-func (s *itemService) GetItem(ctx context.Context, id int32) (*db.Item, error) {
-	item, err := s.querier.GetItem(ctx, id)
+// authorizeItem loads an item only if it belongs to the given user, returning
+// ErrItemNotFound otherwise so callers cannot probe other users' items.
+// It guards changes to the item itself, which stay with its owner.
+func (s *itemService) authorizeItem(ctx context.Context, userID int32, id int32) (*db.Item, error) {
+	item, err := s.querier.GetItemForUser(ctx, db.GetItemForUserParams{
+		ID:     id,
+		UserID: &userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// authorizeVisibleItem loads an item the user owns or that is shared with one
+// of their workspaces. It guards reads and the user's own read state.
+func (s *itemService) authorizeVisibleItem(ctx context.Context, userID int32, id int32) (*db.Item, error) {
+	item, err := s.querier.GetItemVisibleToUser(ctx, db.GetItemVisibleToUserParams{
+		ID:     id,
+		UserID: &userID,
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemNotFound
+		}
 		return nil, err
 	}
 	return &item, nil
 }
 
+func (s *itemService) GetItem(ctx context.Context, userID int32, id int32) (*db.Item, error) {
+	return s.authorizeVisibleItem(ctx, userID, id)
+}
+
+// GetItemsByUser returns the user's own items and those shared with their workspaces
 func (s *itemService) GetItemsByUser(ctx context.Context, userID *int32) ([]db.Item, error) {
 	items, err := s.querier.GetItemsByUser(ctx, userID)
 	if err != nil {
@@ -143,6 +434,7 @@ func (s *itemService) GetItemsByUser(ctx context.Context, userID *int32) ([]db.I
 	return items, nil
 }
 
+// GetUnreadItemsByUser returns visible items the user has not read yet
 func (s *itemService) GetUnreadItemsByUser(ctx context.Context, userID *int32) ([]db.Item, error) {
 	items, err := s.querier.GetUnreadItemsByUser(ctx, userID)
 	if err != nil {
@@ -151,12 +443,14 @@ func (s *itemService) GetUnreadItemsByUser(ctx context.Context, userID *int32) (
 	return items, nil
 }
 
-func (s *itemService) UpdateItem(ctx context.Context, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string, isRead *bool) error {
+func (s *itemService) UpdateItem(ctx context.Context, userID int32, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string) error {
+	if _, err := s.authorizeItem(ctx, userID, id); err != nil {
+		return err
+	}
 	params := db.UpdateItemParams{
 		ID:          id,
 		Title:       title,
 		Url:         url,
-		IsRead:      isRead,
 		TextContent: textContent,
 		Summary:     summary,
 		Type:        itemType,
@@ -167,7 +461,10 @@ func (s *itemService) UpdateItem(ctx context.Context, id int32, title string, ur
 	return s.querier.UpdateItem(ctx, params)
 }
 
-func (s *itemService) PatchItem(ctx context.Context, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error) {
+func (s *itemService) PatchItem(ctx context.Context, userID int32, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error) {
+	if _, err := s.authorizeItem(ctx, userID, id); err != nil {
+		return nil, err
+	}
 	params := db.PatchItemParams{
 		ID:      id,
 		Title:   title,
@@ -182,34 +479,150 @@ func (s *itemService) PatchItem(ctx context.Context, id int32, title *string, su
 	return &item, nil
 }
 
-func (s *itemService) MarkItemAsRead(ctx context.Context, id int32) error {
-	return s.querier.MarkItemAsRead(ctx, id)
+func (s *itemService) MarkItemAsRead(ctx context.Context, userID int32, id int32) error {
+	return s.SetItemReadStatus(ctx, userID, id, true)
+}
+
+// SetItemReadStatus records whether the user has read the item. Read state is
+// per user, so marking a shared item read does not affect other members.
+func (s *itemService) SetItemReadStatus(ctx context.Context, userID int32, id int32, isRead bool) error {
+	if _, err := s.authorizeVisibleItem(ctx, userID, id); err != nil {
+		return err
+	}
+	if isRead {
+		return s.querier.MarkItemRead(ctx, db.MarkItemReadParams{UserID: userID, ItemID: id})
+	}
+	return s.querier.MarkItemUnread(ctx, db.MarkItemUnreadParams{UserID: userID, ItemID: id})
 }
 
-func (s *itemService) ToggleItemReadStatus(ctx context.Context, id int32) (*db.Item, error) {
-	item, err := s.querier.ToggleItemReadStatus(ctx, id)
+// ToggleItemReadStatus flips the user's read state and returns the item with its new state
+func (s *itemService) ToggleItemReadStatus(ctx context.Context, userID int32, id int32) (*db.Item, bool, error) {
+	item, err := s.authorizeVisibleItem(ctx, userID, id)
 	if err != nil {
+		return nil, false, err
+	}
+	isRead, err := s.querier.IsItemRead(ctx, db.IsItemReadParams{UserID: userID, ItemID: id})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get read state: %w", err)
+	}
+
+	if isRead {
+		err = s.querier.MarkItemUnread(ctx, db.MarkItemUnreadParams{UserID: userID, ItemID: id})
+	} else {
+		err = s.querier.MarkItemRead(ctx, db.MarkItemReadParams{UserID: userID, ItemID: id})
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return item, !isRead, nil
+}
+
+// GetReadState reports which of the given items the user has read
+func (s *itemService) GetReadState(ctx context.Context, userID int32, itemIDs []int32) (map[int32]bool, error) {
+	state := make(map[int32]bool, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return state, nil
+	}
+	readIDs, err := s.querier.GetReadItemIDs(ctx, db.GetReadItemIDsParams{
+		UserID:  userID,
+		ItemIds: itemIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read state: %w", err)
+	}
+	for _, id := range readIDs {
+		state[id] = true
+	}
+	return state, nil
+}
+
+// GetItemsByWorkspace returns every item shared with the workspace
+func (s *itemService) GetItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error) {
+	if _, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	items, err := s.querier.GetItemsByWorkspace(ctx, &workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace items: %w", err)
+	}
+	return items, nil
+}
+
+// GetUnreadItemsByWorkspace returns workspace items the user has not read yet
+func (s *itemService) GetUnreadItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error) {
+	if _, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleViewer); err != nil {
 		return nil, err
 	}
+	items, err := s.querier.GetUnreadItemsByWorkspace(ctx, db.GetUnreadItemsByWorkspaceParams{
+		WorkspaceID: &workspaceID,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread workspace items: %w", err)
+	}
+	return items, nil
+}
+
+// MoveItemToWorkspace shares the user's item with a workspace, or makes it
+// personal again when workspaceID is nil
+func (s *itemService) MoveItemToWorkspace(ctx context.Context, userID int32, id int32, workspaceID *int32) (*db.Item, error) {
+	if _, err := s.authorizeItem(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if workspaceID != nil {
+		if _, err := requireWorkspaceRole(ctx, s.querier, userID, *workspaceID, WorkspaceRoleMember); err != nil {
+			return nil, err
+		}
+	}
+	item, err := s.querier.UpdateItemWorkspace(ctx, db.UpdateItemWorkspaceParams{
+		ID:          id,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to move item: %w", err)
+	}
 	return &item, nil
 }
 
-func (s *itemService) DeleteItem(ctx context.Context, id int32) error {
-	return s.querier.DeleteItem(ctx, id)
+func (s *itemService) DeleteItem(ctx context.Context, userID int32, id int32) error {
+	item, err := s.authorizeItem(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.querier.DeleteItem(ctx, id); err != nil {
+		return err
+	}
+
+	// An item created from an upload owns its file
+	if s.uploadStore != nil && item.Url != nil {
+		if key := s.uploadStore.ExtractKeyFromURL(*item.Url); isUploadOf(key, userID) {
+			if err := s.uploadStore.DeleteFile(ctx, key); err != nil {
+				log.Printf("Failed to delete uploaded file %s of deleted item %d: %v", key, id, err)
+			}
+		}
+	}
+	return nil
 }
 
-func (s *itemService) GetItemProcessingStatus(ctx context.Context, itemID int32) (*ItemStatus, error) {
+func (s *itemService) GetItemProcessingStatus(ctx context.Context, userID int32, itemID int32) (*ItemStatus, error) {
 	if s.jobQueueService == nil {
 		return nil, fmt.Errorf("job queue service not available")
 	}
+	if _, err := s.authorizeVisibleItem(ctx, userID, itemID); err != nil {
+		return nil, err
+	}
 	return s.jobQueueService.GetItemStatus(ctx, itemID)
 }
 
-func (s *itemService) GetItemsByProcessingStatus(ctx context.Context, status *string) ([]db.Item, error) {
-	if s.jobQueueService == nil {
-		return nil, fmt.Errorf("job queue service not available")
+func (s *itemService) GetItemsByProcessingStatus(ctx context.Context, userID int32, status *string) ([]db.Item, error) {
+	items, err := s.querier.GetItemsByUserAndProcessingStatus(ctx, db.GetItemsByUserAndProcessingStatusParams{
+		UserID:           &userID,
+		ProcessingStatus: status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items by status: %w", err)
 	}
-	return s.jobQueueService.GetItemsByStatus(ctx, *status)
+	return items, nil
 }
 
 func (s *itemService) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]db.Item, error) {
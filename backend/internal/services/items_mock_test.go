@@ -11,7 +11,15 @@ type MockItemService struct {
 	mock.Mock
 }
 
-func (m *MockItemService) CreateItemAsync(ctx context.Context, userID int32, url string) (*db.Item, error) {
+func (m *MockItemService) CreateItemAsync(ctx context.Context, userID int32, url string, workspaceID *int32) (*db.Item, bool, error) {
+	args := m.Called(ctx, userID, url, workspaceID)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*db.Item), args.Bool(1), args.Error(2)
+}
+
+func (m *MockItemService) ProcessURL(ctx context.Context, userID int32, url string) (*db.Item, error) {
 	args := m.Called(ctx, userID, url)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -19,8 +27,32 @@ func (m *MockItemService) CreateItemAsync(ctx context.Context, userID int32, url
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) ProcessURL(ctx context.Context, userID int32, url string) (*db.Item, error) {
-	args := m.Called(ctx, userID, url)
+func (m *MockItemService) CreateUploadURL(ctx context.Context, userID int32, contentType string) (*UploadURLResponse, error) {
+	args := m.Called(ctx, userID, contentType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*UploadURLResponse), args.Error(1)
+}
+
+func (m *MockItemService) CreateItemFromUpload(ctx context.Context, userID int32, key string, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, key, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Item), args.Error(1)
+}
+
+func (m *MockItemService) CreateItemFromSource(ctx context.Context, userID int32, title string, sourceType string, content string, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, title, sourceType, content, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Item), args.Error(1)
+}
+
+func (m *MockItemService) CreateItemFromFile(ctx context.Context, userID int32, title string, filename string, contentType string, data []byte, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, title, filename, contentType, data, workspaceID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -35,8 +67,8 @@ func (m *MockItemService) CreateItem(ctx context.Context, userID *int32, title s
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) GetItem(ctx context.Context, id int32) (*db.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockItemService) GetItem(ctx context.Context, userID int32, id int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -67,49 +99,110 @@ func (m *MockItemService) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]
 	return args.Get(0).([]db.Item), args.Error(1)
 }
 
-func (m *MockItemService) UpdateItem(ctx context.Context, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string, isRead *bool) error {
-	args := m.Called(ctx, id, title, url, textContent, summary, itemType, platform, tags, authors, isRead)
+func (m *MockItemService) UpdateItem(ctx context.Context, userID int32, id int32, title string, url *string, textContent *string, summary *string, itemType *string, platform *string, tags []string, authors []string) error {
+	args := m.Called(ctx, userID, id, title, url, textContent, summary, itemType, platform, tags, authors)
 	return args.Error(0)
 }
 
-func (m *MockItemService) PatchItem(ctx context.Context, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error) {
-	args := m.Called(ctx, id, title, summary, tags, authors)
+func (m *MockItemService) PatchItem(ctx context.Context, userID int32, id int32, title *string, summary *string, tags []string, authors []string) (*db.Item, error) {
+	args := m.Called(ctx, userID, id, title, summary, tags, authors)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) MarkItemAsRead(ctx context.Context, id int32) error {
-	args := m.Called(ctx, id)
+func (m *MockItemService) MarkItemAsRead(ctx context.Context, userID int32, id int32) error {
+	args := m.Called(ctx, userID, id)
 	return args.Error(0)
 }
 
-func (m *MockItemService) ToggleItemReadStatus(ctx context.Context, id int32) (*db.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockItemService) SetItemReadStatus(ctx context.Context, userID int32, id int32, isRead bool) error {
+	args := m.Called(ctx, userID, id, isRead)
+	return args.Error(0)
+}
+
+func (m *MockItemService) ToggleItemReadStatus(ctx context.Context, userID int32, id int32) (*db.Item, bool, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*db.Item), args.Bool(1), args.Error(2)
+}
+
+func (m *MockItemService) GetReadState(ctx context.Context, userID int32, itemIDs []int32) (map[int32]bool, error) {
+	args := m.Called(ctx, userID, itemIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int32]bool), args.Error(1)
+}
+
+func (m *MockItemService) SetQuotaService(quotaService QuotaService) {
+	m.Called(quotaService)
+}
+
+func (m *MockItemService) SetUploadStore(uploadStore UploadStore) {
+	m.Called(uploadStore)
+}
+
+func (m *MockItemService) GetItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error) {
+	args := m.Called(ctx, userID, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockItemService) GetUnreadItemsByWorkspace(ctx context.Context, userID int32, workspaceID int32) ([]db.Item, error) {
+	args := m.Called(ctx, userID, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockItemService) MoveItemToWorkspace(ctx context.Context, userID int32, id int32, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, id, workspaceID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
-func (m *MockItemService) DeleteItem(ctx context.Context, id int32) error {
-	args := m.Called(ctx, id)
+func (m *MockItemService) DeleteItem(ctx context.Context, userID int32, id int32) error {
+	args := m.Called(ctx, userID, id)
 	return args.Error(0)
 }
 
-func (m *MockItemService) GetItemProcessingStatus(ctx context.Context, itemID int32) (*ItemStatus, error) {
-	args := m.Called(ctx, itemID)
+func (m *MockItemService) GetItemProcessingStatus(ctx context.Context, userID int32, itemID int32) (*ItemStatus, error) {
+	args := m.Called(ctx, userID, itemID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*ItemStatus), args.Error(1)
 }
 
-func (m *MockItemService) GetItemsByProcessingStatus(ctx context.Context, status *string) ([]db.Item, error) {
-	args := m.Called(ctx, status)
+func (m *MockItemService) GetItemsByProcessingStatus(ctx context.Context, userID int32, status *string) ([]db.Item, error) {
+	args := m.Called(ctx, userID, status)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]db.Item), args.Error(1)
 }
+
+func (m *MockItemService) SearchItems(ctx context.Context, userID int32, search ItemSearch) ([]SearchResult, error) {
+	args := m.Called(ctx, userID, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]SearchResult), args.Error(1)
+}
+
+func (m *MockItemService) ListItems(ctx context.Context, userID int32, query ItemListQuery) (*ItemPage, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ItemPage), args.Error(1)
+}
@@ -3,10 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
 	"testing"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/yamirghofran/briefbot/internal/db"
 	"github.com/yamirghofran/briefbot/internal/test"
 )
@@ -74,6 +78,7 @@ func TestGetItem(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 	title := "Test Item"
 
@@ -82,9 +87,9 @@ func TestGetItem(t *testing.T) {
 		Title: title,
 	}
 
-	mockQuerier.On("GetItem", ctx, itemID).Return(expectedItem, nil)
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(expectedItem, nil)
 
-	item, err := service.GetItem(ctx, itemID)
+	item, err := service.GetItem(ctx, userID, itemID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, item)
@@ -101,11 +106,12 @@ func TestGetItem_Error(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 
-	mockQuerier.On("GetItem", ctx, itemID).Return(db.Item{}, errors.New("database error"))
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(db.Item{}, errors.New("database error"))
 
-	item, err := service.GetItem(ctx, itemID)
+	item, err := service.GetItem(ctx, userID, itemID)
 
 	assert.Error(t, err)
 	assert.Nil(t, item)
@@ -171,15 +177,17 @@ func TestUpdateItem(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 	title := "Updated Title"
-	isRead := true
 
 	mockQuerier.On("UpdateItem", ctx, mock.MatchedBy(func(params db.UpdateItemParams) bool {
 		return params.ID == itemID && params.Title == title
 	})).Return(nil)
 
-	err := service.UpdateItem(ctx, itemID, title, nil, nil, nil, nil, nil, []string{}, []string{}, &isRead)
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	err := service.UpdateItem(ctx, userID, itemID, title, nil, nil, nil, nil, nil, []string{}, []string{})
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
@@ -194,6 +202,7 @@ func TestPatchItem(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 	newTitle := "Updated Title"
 	newSummary := "Updated Summary"
@@ -216,7 +225,9 @@ func TestPatchItem(t *testing.T) {
 			len(params.Authors) == 1
 	})).Return(expectedItem, nil)
 
-	item, err := service.PatchItem(ctx, itemID, &newTitle, &newSummary, newTags, newAuthors)
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	item, err := service.PatchItem(ctx, userID, itemID, &newTitle, &newSummary, newTags, newAuthors)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, item)
@@ -236,6 +247,7 @@ func TestPatchItem_PartialUpdate(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 	newTitle := "Updated Title Only"
 
@@ -252,7 +264,9 @@ func TestPatchItem_PartialUpdate(t *testing.T) {
 			params.Authors == nil
 	})).Return(expectedItem, nil)
 
-	item, err := service.PatchItem(ctx, itemID, &newTitle, nil, nil, nil)
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	item, err := service.PatchItem(ctx, userID, itemID, &newTitle, nil, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, item)
@@ -269,12 +283,15 @@ func TestPatchItem_Error(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 	newTitle := "Updated Title"
 
 	mockQuerier.On("PatchItem", ctx, mock.Anything).Return(db.Item{}, errors.New("database error"))
 
-	item, err := service.PatchItem(ctx, itemID, &newTitle, nil, nil, nil)
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	item, err := service.PatchItem(ctx, userID, itemID, &newTitle, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Nil(t, item)
@@ -291,14 +308,38 @@ func TestMarkItemAsRead(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
+	itemID := int32(1)
+
+	mockQuerier.On("MarkItemRead", ctx, db.MarkItemReadParams{UserID: userID, ItemID: itemID}).Return(nil)
+
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	err := service.MarkItemAsRead(ctx, userID, itemID)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestSetItemReadStatus_SharedItemUnread(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	ownerID := int32(7)
+	memberID := int32(8)
+	workspaceID := int32(3)
 	itemID := int32(1)
 
-	mockQuerier.On("MarkItemAsRead", ctx, itemID).Return(nil)
+	// A workspace member can change their own read state on an item they do not own
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &memberID}).Return(db.Item{ID: itemID, UserID: &ownerID, WorkspaceID: &workspaceID}, nil)
+	mockQuerier.On("MarkItemUnread", ctx, db.MarkItemUnreadParams{UserID: memberID, ItemID: itemID}).Return(nil)
 
-	err := service.MarkItemAsRead(ctx, itemID)
+	err := service.SetItemReadStatus(ctx, memberID, itemID, false)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "GetItemForUser", mock.Anything, mock.Anything)
 }
 
 func TestToggleItemReadStatus(t *testing.T) {
@@ -310,24 +351,61 @@ func TestToggleItemReadStatus(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
-	isRead := true
 
-	expectedItem := db.Item{
-		ID:     itemID,
-		IsRead: &isRead,
-	}
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+	mockQuerier.On("IsItemRead", ctx, db.IsItemReadParams{UserID: userID, ItemID: itemID}).Return(false, nil)
+	mockQuerier.On("MarkItemRead", ctx, db.MarkItemReadParams{UserID: userID, ItemID: itemID}).Return(nil)
 
-	mockQuerier.On("ToggleItemReadStatus", ctx, itemID).Return(expectedItem, nil)
-
-	item, err := service.ToggleItemReadStatus(ctx, itemID)
+	item, isRead, err := service.ToggleItemReadStatus(ctx, userID, itemID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, item)
-	assert.True(t, *item.IsRead)
+	assert.True(t, isRead)
 	mockQuerier.AssertExpectations(t)
 }
 
+func TestToggleItemReadStatus_MarksUnread(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	userID := int32(7)
+	itemID := int32(1)
+
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+	mockQuerier.On("IsItemRead", ctx, db.IsItemReadParams{UserID: userID, ItemID: itemID}).Return(true, nil)
+	mockQuerier.On("MarkItemUnread", ctx, db.MarkItemUnreadParams{UserID: userID, ItemID: itemID}).Return(nil)
+
+	_, isRead, err := service.ToggleItemReadStatus(ctx, userID, itemID)
+
+	assert.NoError(t, err)
+	assert.False(t, isRead)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestGetReadState(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	userID := int32(7)
+
+	mockQuerier.On("GetReadItemIDs", ctx, db.GetReadItemIDsParams{UserID: userID, ItemIds: []int32{1, 2, 3}}).Return([]int32{2}, nil)
+
+	state, err := service.GetReadState(ctx, userID, []int32{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]bool{2: true}, state)
+
+	// No lookup is needed for an empty list
+	state, err = service.GetReadState(ctx, userID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, state)
+	mockQuerier.AssertNumberOfCalls(t, "GetReadItemIDs", 1)
+}
+
 func TestDeleteItem(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	mockAI := new(MockAIService)
@@ -337,16 +415,46 @@ func TestDeleteItem(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 
 	mockQuerier.On("DeleteItem", ctx, itemID).Return(nil)
 
-	err := service.DeleteItem(ctx, itemID)
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	err := service.DeleteItem(ctx, userID, itemID)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
 }
 
+func TestDeleteItem_RemovesUpload(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockR2 := new(MockR2Service)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+	service.SetUploadStore(mockR2)
+
+	ctx := context.Background()
+	userID := int32(7)
+	uploadURL := "https://files.example.com/uploads/7/abc.pdf"
+	pageURL := "https://example.com/article"
+
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: 1, UserID: &userID}).Return(db.Item{ID: 1, UserID: &userID, Url: &uploadURL}, nil)
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: 2, UserID: &userID}).Return(db.Item{ID: 2, UserID: &userID, Url: &pageURL}, nil)
+	mockQuerier.On("DeleteItem", ctx, int32(1)).Return(nil)
+	mockQuerier.On("DeleteItem", ctx, int32(2)).Return(nil)
+	mockR2.On("ExtractKeyFromURL", uploadURL).Return("uploads/7/abc.pdf")
+	mockR2.On("ExtractKeyFromURL", pageURL).Return(pageURL)
+	mockR2.On("DeleteFile", ctx, "uploads/7/abc.pdf").Return(nil)
+
+	assert.NoError(t, service.DeleteItem(ctx, userID, 1))
+	assert.NoError(t, service.DeleteItem(ctx, userID, 2))
+
+	mockQuerier.AssertExpectations(t)
+	mockR2.AssertExpectations(t)
+	mockR2.AssertNumberOfCalls(t, "DeleteFile", 1)
+}
+
 func TestCreateItemAsync(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	mockAI := new(MockAIService)
@@ -365,15 +473,446 @@ func TestCreateItemAsync(t *testing.T) {
 		Url:    &url,
 	}
 
-	mockJobQueue.On("EnqueueItem", ctx, userID, url, url).Return(expectedItem, nil)
+	canonical := "https://example.com/"
+	mockQuerier.On("GetItemByCanonicalURL", ctx, db.GetItemByCanonicalURLParams{UserID: &userID, CanonicalUrl: &canonical}).Return(db.Item{}, pgx.ErrNoRows)
+	mockJobQueue.On("EnqueueItem", ctx, userID, url, url, (*int32)(nil)).Return(expectedItem, nil)
 
-	item, err := service.CreateItemAsync(ctx, userID, url)
+	item, duplicate, err := service.CreateItemAsync(ctx, userID, url, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, item)
+	assert.False(t, duplicate)
+	mockJobQueue.AssertExpectations(t)
+}
+
+func TestCreateItemAsync_Duplicate(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	mockQuota := new(MockQuotaService)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+	service.SetQuotaService(mockQuota)
+
+	ctx := context.Background()
+	userID := int32(1)
+	saved := "https://www.example.com/post/"
+	canonical := "https://example.com/post"
+	existing := db.Item{ID: 7, UserID: &userID, Url: &saved, CanonicalUrl: &canonical}
+
+	mockQuerier.On("GetItemByCanonicalURL", ctx, db.GetItemByCanonicalURLParams{UserID: &userID, CanonicalUrl: &canonical}).Return(existing, nil)
+
+	item, duplicate, err := service.CreateItemAsync(ctx, userID, "http://example.com/post?utm_source=newsletter&fbclid=abc", nil)
+
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+	assert.Equal(t, int32(7), item.ID)
+	mockQuota.AssertNotCalled(t, "ConsumeItem", mock.Anything, mock.Anything)
+	mockJobQueue.AssertNotCalled(t, "EnqueueItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateItemAsync_RequeuesFailedDuplicate(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	mockQuota := new(MockQuotaService)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+	service.SetQuotaService(mockQuota)
+
+	ctx := context.Background()
+	userID := int32(1)
+	url := "https://example.com/post"
+	failed := ProcessingStatusFailed
+	reason := FailureReasonTimeout
+	processingError := "request timed out"
+	existing := db.Item{ID: 7, UserID: &userID, Url: &url, CanonicalUrl: &url, ProcessingStatus: &failed, ProcessingError: &processingError, FailureReason: &reason}
+
+	mockQuerier.On("GetItemByCanonicalURL", ctx, db.GetItemByCanonicalURLParams{UserID: &userID, CanonicalUrl: &url}).Return(existing, nil)
+	mockQuota.On("ConsumeItem", ctx, userID).Return(nil)
+	mockJobQueue.On("RetryItem", ctx, int32(7)).Return(nil)
+
+	item, duplicate, err := service.CreateItemAsync(ctx, userID, url, nil)
+
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+	assert.Equal(t, int32(7), item.ID)
+	assert.Equal(t, ProcessingStatusPending, *item.ProcessingStatus)
+	assert.Nil(t, item.ProcessingError)
+	assert.Nil(t, item.FailureReason)
+	mockQuota.AssertExpectations(t)
+	mockJobQueue.AssertExpectations(t)
+	mockJobQueue.AssertNotCalled(t, "EnqueueItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateItemAsync_ConcurrentDuplicate(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+
+	ctx := context.Background()
+	userID := int32(1)
+	url := "https://example.com/post"
+	params := db.GetItemByCanonicalURLParams{UserID: &userID, CanonicalUrl: &url}
+
+	// Another request saves the page between the lookup and the insert
+	mockQuerier.On("GetItemByCanonicalURL", ctx, params).Return(db.Item{}, pgx.ErrNoRows).Once()
+	mockQuerier.On("GetItemByCanonicalURL", ctx, params).Return(db.Item{ID: 9, UserID: &userID, Url: &url}, nil).Once()
+	mockJobQueue.On("EnqueueItem", ctx, userID, url, url, (*int32)(nil)).Return(nil, fmt.Errorf("%w: %s", ErrDuplicateItem, url))
+
+	item, duplicate, err := service.CreateItemAsync(ctx, userID, url, nil)
+
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+	assert.Equal(t, int32(9), item.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestCreateItemAsync_QuotaExceeded(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockAI := new(MockAIService)
+	mockScraper := new(MockScrapingService)
+	mockJobQueue := new(MockJobQueueService)
+	mockQuota := new(MockQuotaService)
+
+	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
+	service.SetQuotaService(mockQuota)
+
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("GetItemByCanonicalURL", ctx, mock.Anything).Return(db.Item{}, pgx.ErrNoRows)
+	mockQuota.On("ConsumeItem", ctx, userID).Return(&QuotaExceededError{Quota: QuotaItems, Limit: 50})
+
+	item, _, err := service.CreateItemAsync(ctx, userID, "https://example.com", nil)
+
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Nil(t, item)
+	mockJobQueue.AssertNotCalled(t, "EnqueueItem")
+}
+
+func TestCreateUploadURL(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(7)
+	mockR2 := new(MockR2Service)
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+	service.SetUploadStore(mockR2)
+
+	expected := &UploadURLResponse{UploadURL: "https://r2.example.com/put", Key: "uploads/7/abc.pdf", PublicURL: "https://files.example.com/uploads/7/abc.pdf"}
+	mockR2.On("GenerateUploadURL", ctx, "application/pdf", "uploads/7").Return(expected, nil)
+
+	response, err := service.CreateUploadURL(ctx, userID, "application/pdf")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, response)
+
+	_, err = service.CreateUploadURL(ctx, userID, "image/png")
+	assert.ErrorIs(t, err, ErrInvalidUpload)
+	mockR2.AssertNumberOfCalls(t, "GenerateUploadURL", 1)
+}
+
+func TestCreateUploadURL_StorageNotConfigured(t *testing.T) {
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	_, err := service.CreateUploadURL(context.Background(), 1, "application/pdf")
+	assert.ErrorIs(t, err, ErrUploadsUnavailable)
+
+	_, err = service.CreateItemFromUpload(context.Background(), 1, "uploads/1/abc.pdf", nil)
+	assert.ErrorIs(t, err, ErrUploadsUnavailable)
+}
+
+func TestCreateItemFromUpload(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(7)
+	mockR2 := new(MockR2Service)
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+	service.SetUploadStore(mockR2)
+
+	publicURL := "https://files.example.com/uploads/7/abc.pdf"
+	mockR2.On("GetPublicURL", "uploads/7/abc.pdf").Return(publicURL)
+	mockQuerier.On("GetItemByCanonicalURL", ctx, mock.Anything).Return(db.Item{}, pgx.ErrNoRows)
+	mockJobQueue.On("EnqueueItem", ctx, userID, publicURL, publicURL, (*int32)(nil)).Return(&db.Item{ID: 1, Url: &publicURL}, nil)
+
+	item, err := service.CreateItemFromUpload(ctx, userID, "uploads/7/abc.pdf", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, publicURL, *item.Url)
 	mockJobQueue.AssertExpectations(t)
 }
 
+func TestCreateItemFromUpload_RejectsOtherKeys(t *testing.T) {
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+	service.SetUploadStore(new(MockR2Service))
+
+	for _, key := range []string{"uploads/8/abc.pdf", "uploads/7/", "uploads/77/abc.pdf", "uploads/7/../8/abc.pdf", "generated/podcasts/podcast_1.mp3"} {
+		_, err := service.CreateItemFromUpload(context.Background(), 7, key, nil)
+		assert.ErrorIs(t, err, ErrInvalidUpload, key)
+	}
+}
+
+func TestCreateItemFromSource(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(7)
+	mockJobQueue := new(MockJobQueueService)
+	mockQuota := new(MockQuotaService)
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), mockJobQueue)
+	service.SetQuotaService(mockQuota)
+
+	content := "<p>This week in infrastructure</p>"
+	mockQuota.On("ConsumeItem", ctx, userID).Return(nil)
+	mockJobQueue.On("EnqueueSourceItem", ctx, userID, "Newsletter", SourceTypeHTML, content, (*int32)(nil)).
+		Return(&db.Item{ID: 1, SourceType: SourceTypeHTML, SourceContent: &content}, nil)
+
+	item, err := service.CreateItemFromSource(ctx, userID, " Newsletter ", SourceTypeHTML, content, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, SourceTypeHTML, item.SourceType)
+	mockQuota.AssertExpectations(t)
+	mockJobQueue.AssertExpectations(t)
+}
+
+func TestCreateItemFromSource_Invalid(t *testing.T) {
+	mockJobQueue := new(MockJobQueueService)
+	mockQuota := new(MockQuotaService)
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), mockJobQueue)
+	service.SetQuotaService(mockQuota)
+
+	_, err := service.CreateItemFromSource(context.Background(), 7, "", "rtf", "{\\rtf1}", nil)
+	assert.ErrorIs(t, err, ErrInvalidSource)
+	_, err = service.CreateItemFromSource(context.Background(), 7, "", SourceTypeText, "  ", nil)
+	assert.ErrorIs(t, err, ErrInvalidSource)
+
+	// Rejected content does not use up the quota
+	mockQuota.AssertNotCalled(t, "ConsumeItem", mock.Anything, mock.Anything)
+	mockJobQueue.AssertNotCalled(t, "EnqueueSourceItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateItemFromFile(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(7)
+	mockJobQueue := new(MockJobQueueService)
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), mockJobQueue)
+
+	notes := "# Planning Notes\n\nWe agreed to ship the importer first."
+	mockJobQueue.On("EnqueueSourceItem", ctx, userID, "", SourceTypeMarkdown, notes, (*int32)(nil)).Return(&db.Item{ID: 1}, nil)
+
+	_, err := service.CreateItemFromFile(ctx, userID, "", "notes.md", "application/octet-stream", []byte(notes), nil)
+
+	require.NoError(t, err)
+	mockJobQueue.AssertExpectations(t)
+}
+
+func TestCreateItemFromFile_PDF(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(7)
+	mockJobQueue := new(MockJobQueueService)
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), mockJobQueue)
+
+	data, err := os.ReadFile("testdata/pdf_report.pdf")
+	require.NoError(t, err)
+	// The extracted text is stored, not the PDF
+	expected := "# Infrastructure Cost Report\n\nQuarterly infrastructure costs fell by twelve percent after the migration to reserved instances."
+	mockJobQueue.On("EnqueueSourceItem", ctx, userID, "", SourceTypeMarkdown, expected, (*int32)(nil)).Return(&db.Item{ID: 1}, nil)
+
+	_, err = service.CreateItemFromFile(ctx, userID, "", "report.pdf", "application/pdf", data, nil)
+
+	require.NoError(t, err)
+	mockJobQueue.AssertExpectations(t)
+}
+
+func TestCreateItemFromFile_Unsupported(t *testing.T) {
+	service := NewItemService(new(test.MockQuerier), new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	_, err := service.CreateItemFromFile(context.Background(), 7, "", "book.epub", "application/epub+zip", []byte("PK"), nil)
+	assert.ErrorIs(t, err, ErrInvalidSource)
+
+	_, err = service.CreateItemFromFile(context.Background(), 7, "", "scan.pdf", "application/pdf", []byte("%PDF-1.4 truncated"), nil)
+	assert.ErrorIs(t, err, ErrInvalidSource)
+}
+
+func TestCreateItemAsync_Workspace(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+	workspaceID := int32(3)
+	url := "https://example.com"
+	membership := db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}
+
+	tests := []struct {
+		name        string
+		role        string
+		lookupErr   error
+		expectedErr error
+	}{
+		{name: "member", role: WorkspaceRoleMember},
+		{name: "admin", role: WorkspaceRoleAdmin},
+		{name: "viewer", role: WorkspaceRoleViewer, expectedErr: ErrWorkspaceForbidden},
+		{name: "not a member", lookupErr: pgx.ErrNoRows, expectedErr: ErrWorkspaceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			mockJobQueue := new(MockJobQueueService)
+			service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+
+			mockQuerier.On("GetItemByCanonicalURL", ctx, mock.Anything).Return(db.Item{}, pgx.ErrNoRows)
+			mockQuerier.On("GetWorkspaceMember", ctx, membership).Return(db.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: tt.role}, tt.lookupErr)
+			if tt.expectedErr == nil {
+				mockJobQueue.On("EnqueueItem", ctx, userID, url, url, &workspaceID).Return(&db.Item{ID: 1, WorkspaceID: &workspaceID}, nil)
+			}
+
+			item, _, err := service.CreateItemAsync(ctx, userID, url, &workspaceID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, item)
+				mockJobQueue.AssertNotCalled(t, "EnqueueItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, workspaceID, *item.WorkspaceID)
+			mockJobQueue.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateItemAsync_WorkspaceCheckedBeforeDuplicate(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	mockQuota := new(MockQuotaService)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+	service.SetQuotaService(mockQuota)
+
+	ctx := context.Background()
+	userID := int32(1)
+	workspaceID := int32(3)
+
+	mockQuerier.On("GetWorkspaceMember", ctx, db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}).Return(db.WorkspaceMember{Role: WorkspaceRoleViewer}, nil)
+
+	item, _, err := service.CreateItemAsync(ctx, userID, "https://example.com/post", &workspaceID)
+
+	assert.ErrorIs(t, err, ErrWorkspaceForbidden)
+	assert.Nil(t, item)
+	mockQuerier.AssertNotCalled(t, "GetItemByCanonicalURL", mock.Anything, mock.Anything)
+	mockQuota.AssertNotCalled(t, "ConsumeItem", mock.Anything, mock.Anything)
+	mockJobQueue.AssertNotCalled(t, "RetryItem", mock.Anything, mock.Anything)
+}
+
+func TestCreateItemAsync_DuplicateSharedWithWorkspace(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockJobQueue := new(MockJobQueueService)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+
+	ctx := context.Background()
+	userID := int32(1)
+	workspaceID := int32(3)
+	url := "https://example.com/post"
+	existing := db.Item{ID: 7, UserID: &userID, Url: &url, CanonicalUrl: &url}
+
+	mockQuerier.On("GetWorkspaceMember", ctx, db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}).Return(db.WorkspaceMember{Role: WorkspaceRoleMember}, nil)
+	mockQuerier.On("GetItemByCanonicalURL", ctx, db.GetItemByCanonicalURLParams{UserID: &userID, CanonicalUrl: &url}).Return(existing, nil)
+	mockQuerier.On("UpdateItemWorkspace", ctx, db.UpdateItemWorkspaceParams{ID: 7, WorkspaceID: &workspaceID}).Return(db.Item{ID: 7, UserID: &userID, Url: &url, WorkspaceID: &workspaceID}, nil)
+
+	item, duplicate, err := service.CreateItemAsync(ctx, userID, url, &workspaceID)
+
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+	assert.Equal(t, workspaceID, *item.WorkspaceID)
+	mockQuerier.AssertExpectations(t)
+	mockJobQueue.AssertNotCalled(t, "EnqueueItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetItemsByWorkspace(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	userID := int32(1)
+	workspaceID := int32(3)
+
+	mockQuerier.On("GetWorkspaceMember", ctx, db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}).Return(db.WorkspaceMember{Role: WorkspaceRoleViewer}, nil)
+	mockQuerier.On("GetItemsByWorkspace", ctx, &workspaceID).Return([]db.Item{{ID: 1}, {ID: 2}}, nil)
+
+	items, err := service.GetItemsByWorkspace(ctx, userID, workspaceID)
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestGetUnreadItemsByWorkspace(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	userID := int32(1)
+	workspaceID := int32(3)
+
+	mockQuerier.On("GetWorkspaceMember", ctx, db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}).Return(db.WorkspaceMember{Role: WorkspaceRoleMember}, nil)
+	mockQuerier.On("GetUnreadItemsByWorkspace", ctx, db.GetUnreadItemsByWorkspaceParams{WorkspaceID: &workspaceID, UserID: userID}).Return([]db.Item{{ID: 2}}, nil)
+
+	items, err := service.GetUnreadItemsByWorkspace(ctx, userID, workspaceID)
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestGetItemsByWorkspace_NotMember(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	userID := int32(1)
+	workspaceID := int32(3)
+
+	mockQuerier.On("GetWorkspaceMember", ctx, db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}).Return(db.WorkspaceMember{}, pgx.ErrNoRows)
+
+	items, err := service.GetItemsByWorkspace(ctx, userID, workspaceID)
+
+	assert.ErrorIs(t, err, ErrWorkspaceNotFound)
+	assert.Nil(t, items)
+	mockQuerier.AssertNotCalled(t, "GetItemsByWorkspace", mock.Anything, mock.Anything)
+}
+
+func TestMoveItemToWorkspace(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	userID := int32(1)
+	itemID := int32(5)
+	workspaceID := int32(3)
+
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+	mockQuerier.On("GetWorkspaceMember", ctx, db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}).Return(db.WorkspaceMember{Role: WorkspaceRoleMember}, nil)
+	mockQuerier.On("UpdateItemWorkspace", ctx, db.UpdateItemWorkspaceParams{ID: itemID, WorkspaceID: &workspaceID}).Return(db.Item{ID: itemID, UserID: &userID, WorkspaceID: &workspaceID}, nil)
+
+	item, err := service.MoveItemToWorkspace(ctx, userID, itemID, &workspaceID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, workspaceID, *item.WorkspaceID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestMoveItemToWorkspace_Personal(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), new(MockJobQueueService))
+
+	ctx := context.Background()
+	userID := int32(1)
+	itemID := int32(5)
+
+	// Making an item personal again needs no workspace membership
+	mockQuerier.On("GetItemForUser", ctx, db.GetItemForUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+	mockQuerier.On("UpdateItemWorkspace", ctx, db.UpdateItemWorkspaceParams{ID: itemID, WorkspaceID: nil}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	item, err := service.MoveItemToWorkspace(ctx, userID, itemID, nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, item.WorkspaceID)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "GetWorkspaceMember", mock.Anything, mock.Anything)
+}
+
 func TestProcessURL(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	mockAI := new(MockAIService)
@@ -403,9 +942,9 @@ func TestProcessURL(t *testing.T) {
 		KeyPoints: []string{"Point 1", "Point 2"},
 	}
 
-	mockScraper.On("Scrape", url).Return(content, nil)
+	mockScraper.On("Scrape", url).Return(&ScrapeResult{Content: content}, nil)
 	mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
-	mockAI.On("SummarizeContent", ctx, content).Return(summary, nil)
+	mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(summary, nil)
 
 	concatenatedSummary := "Overview text Point 1 Point 2"
 	expectedItem := db.Item{
@@ -439,7 +978,7 @@ func TestProcessURL_ScrapingError(t *testing.T) {
 	userID := int32(1)
 	url := "https://example.com"
 
-	mockScraper.On("Scrape", url).Return("", errors.New("scraping failed"))
+	mockScraper.On("Scrape", url).Return(nil, errors.New("scraping failed"))
 
 	item, err := service.ProcessURL(ctx, userID, url)
 
@@ -457,6 +996,7 @@ func TestGetItemProcessingStatus(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	itemID := int32(1)
 
 	item := &db.Item{ID: itemID, Title: "Test"}
@@ -468,7 +1008,9 @@ func TestGetItemProcessingStatus(t *testing.T) {
 
 	mockJobQueue.On("GetItemStatus", ctx, itemID).Return(expectedStatus, nil)
 
-	status, err := service.GetItemProcessingStatus(ctx, itemID)
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(db.Item{ID: itemID, UserID: &userID}, nil)
+
+	status, err := service.GetItemProcessingStatus(ctx, userID, itemID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, status)
@@ -521,6 +1063,7 @@ func TestGetItemsByProcessingStatus(t *testing.T) {
 	service := NewItemService(mockQuerier, mockAI, mockScraper, mockJobQueue)
 
 	ctx := context.Background()
+	userID := int32(7)
 	status := "completed"
 
 	expectedItems := []db.Item{
@@ -528,12 +1071,113 @@ func TestGetItemsByProcessingStatus(t *testing.T) {
 		{ID: 2, Title: "Item 2", ProcessingStatus: &status},
 	}
 
-	// The service calls jobQueueService.GetItemsByStatus with dereferenced status
-	mockJobQueue.On("GetItemsByStatus", ctx, status).Return(expectedItems, nil)
+	mockQuerier.On("GetItemsByUserAndProcessingStatus", ctx, db.GetItemsByUserAndProcessingStatusParams{
+		UserID:           &userID,
+		ProcessingStatus: &status,
+	}).Return(expectedItems, nil)
 
-	items, err := service.GetItemsByProcessingStatus(ctx, &status)
+	items, err := service.GetItemsByProcessingStatus(ctx, userID, &status)
 
 	assert.NoError(t, err)
 	assert.Len(t, items, 2)
-	mockJobQueue.AssertExpectations(t)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestItemService_OtherUsersItemNotFound(t *testing.T) {
+	ctx := context.Background()
+	otherUserID := int32(8)
+	itemID := int32(1)
+	status := "completed"
+
+	// Reads and read state are checked against items visible through workspaces;
+	// changes to the item itself are checked against ownership
+	tests := []struct {
+		name    string
+		visible bool
+		call    func(service ItemService) error
+	}{
+		{
+			name:    "GetItem",
+			visible: true,
+			call: func(service ItemService) error {
+				_, err := service.GetItem(ctx, otherUserID, itemID)
+				return err
+			},
+		},
+		{
+			name: "UpdateItem",
+			call: func(service ItemService) error {
+				return service.UpdateItem(ctx, otherUserID, itemID, "title", nil, nil, nil, nil, nil, nil, nil)
+			},
+		},
+		{
+			name: "PatchItem",
+			call: func(service ItemService) error {
+				_, err := service.PatchItem(ctx, otherUserID, itemID, &status, nil, nil, nil)
+				return err
+			},
+		},
+		{
+			name:    "MarkItemAsRead",
+			visible: true,
+			call: func(service ItemService) error {
+				return service.MarkItemAsRead(ctx, otherUserID, itemID)
+			},
+		},
+		{
+			name:    "ToggleItemReadStatus",
+			visible: true,
+			call: func(service ItemService) error {
+				_, _, err := service.ToggleItemReadStatus(ctx, otherUserID, itemID)
+				return err
+			},
+		},
+		{
+			name: "MoveItemToWorkspace",
+			call: func(service ItemService) error {
+				_, err := service.MoveItemToWorkspace(ctx, otherUserID, itemID, nil)
+				return err
+			},
+		},
+		{
+			name: "DeleteItem",
+			call: func(service ItemService) error {
+				return service.DeleteItem(ctx, otherUserID, itemID)
+			},
+		},
+		{
+			name:    "GetItemProcessingStatus",
+			visible: true,
+			call: func(service ItemService) error {
+				_, err := service.GetItemProcessingStatus(ctx, otherUserID, itemID)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			mockJobQueue := new(MockJobQueueService)
+			service := NewItemService(mockQuerier, new(MockAIService), new(MockScrapingService), mockJobQueue)
+
+			// The item belongs to someone else, so the scoped lookup finds nothing
+			lookup := "GetItemForUser"
+			if tt.visible {
+				lookup = "GetItemVisibleToUser"
+				mockQuerier.On(lookup, ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &otherUserID}).Return(db.Item{}, pgx.ErrNoRows)
+			} else {
+				mockQuerier.On(lookup, ctx, db.GetItemForUserParams{ID: itemID, UserID: &otherUserID}).Return(db.Item{}, pgx.ErrNoRows)
+			}
+
+			err := tt.call(service)
+
+			assert.ErrorIs(t, err, ErrItemNotFound)
+			// Only the access lookup may run; no mutation reaches the database
+			mockQuerier.AssertExpectations(t)
+			mockQuerier.AssertNumberOfCalls(t, lookup, 1)
+			assert.Len(t, mockQuerier.Calls, 1)
+			mockJobQueue.AssertNotCalled(t, "GetItemStatus", mock.Anything, mock.Anything)
+		})
+	}
 }
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/yamirghofran/briefbot/internal/db"
 	"github.com/yamirghofran/briefbot/internal/metrics"
 )
@@ -20,13 +22,15 @@ const (
 
 type JobQueueService interface {
 	// Queue management
-	EnqueueItem(ctx context.Context, userID int32, title string, url string) (*db.Item, error)
+	EnqueueItem(ctx context.Context, userID int32, title string, url string, workspaceID *int32) (*db.Item, error)
+	EnqueueSourceItem(ctx context.Context, userID int32, title string, sourceType string, content string, workspaceID *int32) (*db.Item, error)
 	DequeuePendingItems(ctx context.Context, limit int32) ([]db.Item, error)
 	MarkItemAsProcessing(ctx context.Context, itemID int32) error
+	SetCanonicalURL(ctx context.Context, item db.Item, pageURL string) error
 
 	// Status management
 	CompleteItem(ctx context.Context, itemID int32, title, textContent, summary, itemType, platform string, tags, authors []string) error
-	FailItem(ctx context.Context, itemID int32, errorMsg string) error
+	FailItem(ctx context.Context, itemID int32, reason, errorMsg string) error
 	GetItemStatus(ctx context.Context, itemID int32) (*ItemStatus, error)
 
 	// Utility methods
@@ -44,6 +48,7 @@ type ItemStatus struct {
 	IsCompleted     bool
 	IsFailed        bool
 	ProcessingError *string
+	FailureReason   *string
 }
 
 type jobQueueService struct {
@@ -63,15 +68,25 @@ func (s *jobQueueService) SetSSEManager(sseManager *SSEManager) {
 	s.sseManager = sseManager
 }
 
-func (s *jobQueueService) EnqueueItem(ctx context.Context, userID int32, title string, url string) (*db.Item, error) {
+// EnqueueItem queues a URL for scraping. It returns ErrDuplicateItem when the
+// user already has an item whose canonical URL matches.
+func (s *jobQueueService) EnqueueItem(ctx context.Context, userID int32, title string, url string, workspaceID *int32) (*db.Item, error) {
 	params := db.CreatePendingItemParams{
-		UserID: &userID,
-		Title:  title,
-		Url:    &url,
+		UserID:      &userID,
+		Title:       title,
+		Url:         &url,
+		WorkspaceID: workspaceID,
+	}
+	// Invalid URLs are still queued and fail when scraped
+	if canonical, err := CanonicalizeURL(url); err == nil {
+		params.CanonicalUrl = &canonical
 	}
 
 	item, err := s.querier.CreatePendingItem(ctx, params)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateItem, url)
+		}
 		return nil, fmt.Errorf("failed to enqueue item: %w", err)
 	}
 
@@ -86,6 +101,64 @@ func (s *jobQueueService) EnqueueItem(ctx context.Context, userID int32, title s
 	return &item, nil
 }
 
+// EnqueueSourceItem queues pasted or uploaded content, which the worker
+// processes without scraping
+func (s *jobQueueService) EnqueueSourceItem(ctx context.Context, userID int32, title string, sourceType string, content string, workspaceID *int32) (*db.Item, error) {
+	params := db.CreatePendingSourceItemParams{
+		UserID:        &userID,
+		Title:         title,
+		SourceType:    sourceType,
+		SourceContent: &content,
+		WorkspaceID:   workspaceID,
+	}
+
+	item, err := s.querier.CreatePendingSourceItem(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue item: %w", err)
+	}
+
+	metrics.IncrementJobsEnqueued()
+
+	if s.sseManager != nil && item.ProcessingStatus != nil {
+		s.sseManager.NotifyItemUpdate(userID, item.ID, item.ProcessingStatus, "created")
+	}
+
+	return &item, nil
+}
+
+// SetCanonicalURL records the URL an item's page resolved to after redirects
+// and <link rel=canonical>. It returns ErrDuplicateItem when another of the
+// user's items already has that URL.
+func (s *jobQueueService) SetCanonicalURL(ctx context.Context, item db.Item, pageURL string) error {
+	canonical, err := CanonicalizeURL(pageURL)
+	if err != nil || item.UserID == nil || (item.CanonicalUrl != nil && *item.CanonicalUrl == canonical) {
+		return nil
+	}
+
+	existing, err := s.querier.GetItemByCanonicalURL(ctx, db.GetItemByCanonicalURLParams{
+		UserID:       item.UserID,
+		CanonicalUrl: &canonical,
+	})
+	if err == nil && existing.ID != item.ID {
+		return fmt.Errorf("%w: same page as item %d", ErrDuplicateItem, existing.ID)
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to look up canonical URL: %w", err)
+	}
+
+	err = s.querier.UpdateItemCanonicalURL(ctx, db.UpdateItemCanonicalURLParams{
+		ID:           item.ID,
+		CanonicalUrl: &canonical,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: %s", ErrDuplicateItem, canonical)
+		}
+		return fmt.Errorf("failed to update canonical URL: %w", err)
+	}
+	return nil
+}
+
 func (s *jobQueueService) DequeuePendingItems(ctx context.Context, limit int32) ([]db.Item, error) {
 	items, err := s.querier.GetPendingItems(ctx, limit)
 	if err != nil {
@@ -132,16 +205,21 @@ func (s *jobQueueService) CompleteItem(ctx context.Context, itemID int32, title,
 		return fmt.Errorf("failed to get item for completion: %w", err)
 	}
 
+	// Extracted tags are normalized and mapped through the owner's tag aliases
+	tags, err = resolveTags(ctx, s.querier, item.UserID, tags)
+	if err != nil {
+		return fmt.Errorf("failed to normalize tags: %w", err)
+	}
+
 	// Update the item with processed data, using the AI-extracted title and preserving URL
 	params := db.UpdateItemParams{
 		ID:          itemID,
-		Title:       title,       // Use AI-extracted title
-		Url:         item.Url,    // Preserve existing URL
-		IsRead:      item.IsRead, // Preserve existing is_read value
+		Title:       title,    // Use AI-extracted title
+		Url:         item.Url, // Preserve existing URL
 		TextContent: &textContent,
 		Summary:     &summary,
 		Type:        &itemType,
-		Tags:        tags,
+		Tags:        mergeTags(item.Tags, tags), // Keep tags set before processing, such as a feed's
 		Platform:    &platform,
 		Authors:     authors,
 	}
@@ -176,7 +254,9 @@ func (s *jobQueueService) CompleteItem(ctx context.Context, itemID int32, title,
 	return nil
 }
 
-func (s *jobQueueService) FailItem(ctx context.Context, itemID int32, errorMsg string) error {
+// FailItem marks an item as failed with one of the FailureReason values and
+// the full error message
+func (s *jobQueueService) FailItem(ctx context.Context, itemID int32, reason, errorMsg string) error {
 	// Get item to find user ID
 	item, err := s.querier.GetItem(ctx, itemID)
 	if err != nil {
@@ -189,6 +269,9 @@ func (s *jobQueueService) FailItem(ctx context.Context, itemID int32, errorMsg s
 		ProcessingStatus: &failedStatus,
 		ProcessingError:  &errorMsg,
 	}
+	if reason != "" {
+		statusParams.FailureReason = &reason
+	}
 
 	err = s.querier.UpdateItemProcessingStatus(ctx, statusParams)
 	if err != nil {
@@ -197,7 +280,7 @@ func (s *jobQueueService) FailItem(ctx context.Context, itemID int32, errorMsg s
 
 	// Record metrics
 	metrics.DecrementJobsProcessing()
-	metrics.IncrementJobsFailed(categorizeError(errorMsg))
+	metrics.IncrementJobsFailed(categorizeError(reason, errorMsg))
 
 	// Notify SSE clients about failure
 	if s.sseManager != nil && item.UserID != nil {
@@ -207,8 +290,27 @@ func (s *jobQueueService) FailItem(ctx context.Context, itemID int32, errorMsg s
 	return nil
 }
 
-// categorizeError categorizes error messages for metrics
-func categorizeError(errorMsg string) string {
+// categorizeError groups failures for metrics: pages that were blocked,
+// could not be reached or read, or failed in the AI service. Failures
+// without a known reason are categorized by their message.
+func categorizeError(reason, errorMsg string) string {
+	switch reason {
+	case FailureReasonPaywall, FailureReasonLoginWall, FailureReasonCaptcha, FailureReasonForbidden, FailureReasonRobots:
+		return "blocked"
+	case FailureReasonNetwork, FailureReasonServerError:
+		return "network"
+	case FailureReasonTimeout:
+		return "timeout"
+	case FailureReasonParse:
+		return "parse"
+	case FailureReasonNotFound, FailureReasonClientError, FailureReasonEmptyContent, FailureReasonTooLarge:
+		return "scraping"
+	case FailureReasonAI:
+		return "ai"
+	case FailureReasonDuplicate:
+		return "duplicate"
+	}
+
 	errorLower := strings.ToLower(errorMsg)
 	if strings.Contains(errorLower, "network") || strings.Contains(errorLower, "connection") {
 		return "network"
@@ -236,6 +338,7 @@ func (s *jobQueueService) GetItemStatus(ctx context.Context, itemID int32) (*Ite
 		IsCompleted:     item.ProcessingStatus != nil && *item.ProcessingStatus == ProcessingStatusCompleted,
 		IsFailed:        item.ProcessingStatus != nil && *item.ProcessingStatus == ProcessingStatusFailed,
 		ProcessingError: item.ProcessingError,
+		FailureReason:   item.FailureReason,
 	}
 
 	return status, nil
@@ -273,3 +376,22 @@ func (s *jobQueueService) RetryItem(ctx context.Context, itemID int32) error {
 
 	return nil
 }
+
+// mergeTags appends the tags not already present, ignoring case, keeping the existing ones first
+func mergeTags(existing, added []string) []string {
+	if len(existing) == 0 {
+		return added
+	}
+	merged := append([]string(nil), existing...)
+	seen := make(map[string]bool, len(existing)+len(added))
+	for _, tag := range existing {
+		seen[strings.ToLower(tag)] = true
+	}
+	for _, tag := range added {
+		if key := strings.ToLower(tag); !seen[key] {
+			seen[key] = true
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
@@ -11,14 +11,27 @@ type MockJobQueueService struct {
 	mock.Mock
 }
 
-func (m *MockJobQueueService) EnqueueItem(ctx context.Context, userID int32, title string, url string) (*db.Item, error) {
-	args := m.Called(ctx, userID, title, url)
+func (m *MockJobQueueService) EnqueueItem(ctx context.Context, userID int32, title string, url string, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, title, url, workspaceID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*db.Item), args.Error(1)
 }
 
+func (m *MockJobQueueService) EnqueueSourceItem(ctx context.Context, userID int32, title string, sourceType string, content string, workspaceID *int32) (*db.Item, error) {
+	args := m.Called(ctx, userID, title, sourceType, content, workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Item), args.Error(1)
+}
+
+func (m *MockJobQueueService) SetCanonicalURL(ctx context.Context, item db.Item, pageURL string) error {
+	args := m.Called(ctx, item, pageURL)
+	return args.Error(0)
+}
+
 func (m *MockJobQueueService) DequeuePendingItems(ctx context.Context, limit int32) ([]db.Item, error) {
 	args := m.Called(ctx, limit)
 	if args.Get(0) == nil {
@@ -37,8 +50,8 @@ func (m *MockJobQueueService) CompleteItem(ctx context.Context, itemID int32, ti
 	return args.Error(0)
 }
 
-func (m *MockJobQueueService) FailItem(ctx context.Context, itemID int32, errorMsg string) error {
-	args := m.Called(ctx, itemID, errorMsg)
+func (m *MockJobQueueService) FailItem(ctx context.Context, itemID int32, reason, errorMsg string) error {
+	args := m.Called(ctx, itemID, reason, errorMsg)
 	return args.Error(0)
 }
 
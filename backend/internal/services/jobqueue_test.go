@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/yamirghofran/briefbot/internal/db"
@@ -22,9 +24,10 @@ func TestEnqueueItem(t *testing.T) {
 	url := "https://example.com/test"
 
 	expectedParams := db.CreatePendingItemParams{
-		UserID: &userID,
-		Title:  title,
-		Url:    &url,
+		UserID:       &userID,
+		Title:        title,
+		Url:          &url,
+		CanonicalUrl: &url,
 	}
 
 	expectedItem := test.NewTestDataBuilder().BuildPendingItem()
@@ -34,7 +37,7 @@ func TestEnqueueItem(t *testing.T) {
 
 	mockQuerier.On("CreatePendingItem", ctx, expectedParams).Return(*expectedItem, nil)
 
-	item, err := jobQueueService.EnqueueItem(ctx, userID, title, url)
+	item, err := jobQueueService.EnqueueItem(ctx, userID, title, url, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, item)
@@ -73,9 +76,10 @@ func TestEnqueueItem_WithSSE(t *testing.T) {
 	url := "https://example.com/test"
 
 	expectedParams := db.CreatePendingItemParams{
-		UserID: &userID,
-		Title:  title,
-		Url:    &url,
+		UserID:       &userID,
+		Title:        title,
+		Url:          &url,
+		CanonicalUrl: &url,
 	}
 
 	expectedItem := test.NewTestDataBuilder().BuildPendingItem()
@@ -85,14 +89,105 @@ func TestEnqueueItem_WithSSE(t *testing.T) {
 
 	mockQuerier.On("CreatePendingItem", ctx, expectedParams).Return(*expectedItem, nil)
 
-	item, err := service.EnqueueItem(ctx, userID, title, url)
+	item, err := service.EnqueueItem(ctx, userID, title, url, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, item)
 	mockQuerier.AssertExpectations(t)
 }
 
+func TestEnqueueSourceItem(t *testing.T) {
+	mockQuerier := &test.MockQuerier{}
+	service := NewJobQueueService(mockQuerier)
+	ctx := context.Background()
+
+	userID := int32(1)
+	content := "# Planning Notes\n\nWe agreed to ship the importer first."
+	expectedParams := db.CreatePendingSourceItemParams{
+		UserID:        &userID,
+		Title:         "Planning Notes",
+		SourceType:    SourceTypeMarkdown,
+		SourceContent: &content,
+	}
+
+	expectedItem := test.NewTestDataBuilder().BuildPendingItem()
+	expectedItem.Url = nil
+	expectedItem.SourceType = SourceTypeMarkdown
+	expectedItem.SourceContent = &content
+
+	mockQuerier.On("CreatePendingSourceItem", ctx, expectedParams).Return(*expectedItem, nil)
+
+	item, err := service.EnqueueSourceItem(ctx, userID, "Planning Notes", SourceTypeMarkdown, content, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SourceTypeMarkdown, item.SourceType)
+	assert.Nil(t, item.Url)
+	mockQuerier.AssertExpectations(t)
+}
+
 // TestDequeuePendingItems tests dequeuing pending items
+func TestEnqueueItem_Duplicate(t *testing.T) {
+	mockQuerier := &test.MockQuerier{}
+	jobQueueService := NewJobQueueService(mockQuerier)
+	ctx := context.Background()
+
+	userID := int32(1)
+	url := "https://example.com/test?utm_campaign=spring"
+	canonical := "https://example.com/test"
+
+	mockQuerier.On("CreatePendingItem", ctx, db.CreatePendingItemParams{
+		UserID:       &userID,
+		Title:        url,
+		Url:          &url,
+		CanonicalUrl: &canonical,
+	}).Return(db.Item{}, &pgconn.PgError{Code: "23505", ConstraintName: "idx_items_user_canonical_url"})
+
+	item, err := jobQueueService.EnqueueItem(ctx, userID, url, url, nil)
+
+	assert.Nil(t, item)
+	assert.ErrorIs(t, err, ErrDuplicateItem)
+}
+
+func TestSetCanonicalURL(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+	submitted := "https://t.co/abc"
+	canonical := "https://example.com/post"
+	item := db.Item{ID: 5, UserID: &userID, Url: &submitted, CanonicalUrl: &submitted}
+	lookup := db.GetItemByCanonicalURLParams{UserID: &userID, CanonicalUrl: &canonical}
+
+	t.Run("records the page URL", func(t *testing.T) {
+		mockQuerier := &test.MockQuerier{}
+		mockQuerier.On("GetItemByCanonicalURL", ctx, lookup).Return(db.Item{}, pgx.ErrNoRows)
+		mockQuerier.On("UpdateItemCanonicalURL", ctx, db.UpdateItemCanonicalURLParams{ID: 5, CanonicalUrl: &canonical}).Return(nil)
+
+		err := NewJobQueueService(mockQuerier).SetCanonicalURL(ctx, item, "https://www.example.com/post/?utm_source=twitter")
+
+		assert.NoError(t, err)
+		mockQuerier.AssertExpectations(t)
+	})
+
+	t.Run("another item has the page", func(t *testing.T) {
+		mockQuerier := &test.MockQuerier{}
+		mockQuerier.On("GetItemByCanonicalURL", ctx, lookup).Return(db.Item{ID: 3, UserID: &userID}, nil)
+
+		err := NewJobQueueService(mockQuerier).SetCanonicalURL(ctx, item, canonical)
+
+		assert.ErrorIs(t, err, ErrDuplicateItem)
+		assert.ErrorContains(t, err, "same page as item 3")
+		mockQuerier.AssertNotCalled(t, "UpdateItemCanonicalURL", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		mockQuerier := &test.MockQuerier{}
+
+		err := NewJobQueueService(mockQuerier).SetCanonicalURL(ctx, item, submitted)
+
+		assert.NoError(t, err)
+		mockQuerier.AssertNotCalled(t, "GetItemByCanonicalURL", mock.Anything, mock.Anything)
+	})
+}
+
 func TestDequeuePendingItems(t *testing.T) {
 	mockQuerier := &test.MockQuerier{}
 	jobQueueService := NewJobQueueService(mockQuerier)
@@ -205,10 +300,12 @@ func TestFailItem(t *testing.T) {
 			params.ProcessingStatus != nil &&
 			*params.ProcessingStatus == "failed" &&
 			params.ProcessingError != nil &&
-			*params.ProcessingError == errorMsg
+			*params.ProcessingError == errorMsg &&
+			params.FailureReason != nil &&
+			*params.FailureReason == FailureReasonPaywall
 	})).Return(nil)
 
-	err := jobQueueService.FailItem(ctx, itemID, errorMsg)
+	err := jobQueueService.FailItem(ctx, itemID, FailureReasonPaywall, errorMsg)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
@@ -282,23 +379,22 @@ func TestRetryItem(t *testing.T) {
 	mockQuerier.AssertExpectations(t)
 }
 
-// TestCompleteItemPreservesIsRead tests the critical bug fix where is_read was being set to NULL
-func TestCompleteItemPreservesIsRead(t *testing.T) {
+// TestCompleteItemLeavesReadStateAlone ensures completing an item never touches
+// read state, which now lives per user in item_reads
+func TestCompleteItemLeavesReadStateAlone(t *testing.T) {
 	// Setup
 	mockQuerier := &test.MockQuerier{}
 	jobQueueService := NewJobQueueService(mockQuerier)
 	ctx := context.Background()
 
-	// Test data - item with is_read = false (the bug would set this to NULL)
 	testItem := test.NewTestDataBuilder().BuildItem()
-	testItem.IsRead = boolPtr(false) // Explicitly set to false
+	testItem.Tags = nil
 
 	// Expected parameters for UpdateItem call
 	expectedUpdateParams := db.UpdateItemParams{
 		ID:          testItem.ID,
 		Title:       "AI Extracted Title",
 		Url:         testItem.Url,
-		IsRead:      testItem.IsRead, // This should preserve the original value, not be nil
 		TextContent: strPtr("Extracted content"),
 		Summary:     strPtr("Extracted summary"),
 		Type:        strPtr("article"),
@@ -309,6 +405,7 @@ func TestCompleteItemPreservesIsRead(t *testing.T) {
 
 	// Mock expectations
 	mockQuerier.On("GetItem", ctx, testItem.ID).Return(*testItem, nil)
+	mockQuerier.On("ResolveTagAliases", ctx, db.ResolveTagAliasesParams{UserID: 1, Aliases: []string{"tag1", "tag2"}}).Return([]db.ResolveTagAliasesRow{}, nil)
 	mockQuerier.On("UpdateItem", ctx, expectedUpdateParams).Return(nil)
 
 	completedStatus := "completed"
@@ -335,114 +432,56 @@ func TestCompleteItemPreservesIsRead(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "MarkItemRead", mock.Anything, mock.Anything)
+	mockQuerier.AssertNotCalled(t, "MarkItemUnread", mock.Anything, mock.Anything)
 }
 
-// TestCompleteItemWithNullIsRead tests the case where is_read is initially NULL
-func TestCompleteItemWithNullIsRead(t *testing.T) {
-	// Setup
+// TestCompleteItemKeepsExistingTags ensures tags given before processing, such
+// as a feed subscription's, survive the extracted ones
+func TestCompleteItemKeepsExistingTags(t *testing.T) {
 	mockQuerier := &test.MockQuerier{}
 	jobQueueService := NewJobQueueService(mockQuerier)
 	ctx := context.Background()
 
-	// Test data - item with is_read = nil (unread)
 	testItem := test.NewTestDataBuilder().BuildItem()
-	testItem.IsRead = nil // Initially NULL
-
-	// Expected parameters for UpdateItem call
-	expectedUpdateParams := db.UpdateItemParams{
-		ID:          testItem.ID,
-		Title:       "AI Extracted Title",
-		Url:         testItem.Url,
-		IsRead:      testItem.IsRead, // Should preserve nil, not overwrite with false
-		TextContent: strPtr("Extracted content"),
-		Summary:     strPtr("Extracted summary"),
-		Type:        strPtr("article"),
-		Tags:        []string{"tag1", "tag2"},
-		Platform:    strPtr("web"),
-		Authors:     []string{"Author 1"},
-	}
+	testItem.Tags = []string{"Tech", "newsletters"}
 
-	// Mock expectations
 	mockQuerier.On("GetItem", ctx, testItem.ID).Return(*testItem, nil)
-	mockQuerier.On("UpdateItem", ctx, expectedUpdateParams).Return(nil)
-
-	completedStatus := "completed"
-	mockQuerier.On("UpdateItemProcessingStatus", ctx, mock.MatchedBy(func(params db.UpdateItemProcessingStatusParams) bool {
-		return params.ID == testItem.ID &&
-			params.ProcessingStatus != nil &&
-			*params.ProcessingStatus == completedStatus &&
-			params.ProcessingError == nil
+	mockQuerier.On("ResolveTagAliases", ctx, mock.Anything).Return([]db.ResolveTagAliasesRow{}, nil)
+	mockQuerier.On("UpdateItem", ctx, mock.MatchedBy(func(params db.UpdateItemParams) bool {
+		return assert.ObjectsAreEqual([]string{"Tech", "newsletters", "ai"}, params.Tags)
 	})).Return(nil)
+	mockQuerier.On("UpdateItemProcessingStatus", ctx, mock.Anything).Return(nil)
 
-	// Execute
-	err := jobQueueService.CompleteItem(
-		ctx,
-		testItem.ID,
-		"AI Extracted Title",
-		"Extracted content",
-		"Extracted summary",
-		"article",
-		"web",
-		[]string{"tag1", "tag2"},
-		[]string{"Author 1"},
-	)
+	err := jobQueueService.CompleteItem(ctx, testItem.ID, "Title", "content", "summary", "article", "web", []string{"tech", "ai"}, nil)
 
-	// Assert
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
 }
 
-// TestCompleteItemWithTrueIsRead tests the case where is_read is true
-func TestCompleteItemWithTrueIsRead(t *testing.T) {
-	// Setup
+// TestCompleteItemNormalizesTags ensures extracted tags are normalized and
+// mapped through the owner's tag aliases
+func TestCompleteItemNormalizesTags(t *testing.T) {
 	mockQuerier := &test.MockQuerier{}
 	jobQueueService := NewJobQueueService(mockQuerier)
 	ctx := context.Background()
 
-	// Test data - item with is_read = true (already read)
 	testItem := test.NewTestDataBuilder().BuildItem()
-	testItem.IsRead = boolPtr(true) // Already read
-
-	// Expected parameters for UpdateItem call
-	expectedUpdateParams := db.UpdateItemParams{
-		ID:          testItem.ID,
-		Title:       "AI Extracted Title",
-		Url:         testItem.Url,
-		IsRead:      testItem.IsRead, // Should preserve true, not change to false
-		TextContent: strPtr("Extracted content"),
-		Summary:     strPtr("Extracted summary"),
-		Type:        strPtr("article"),
-		Tags:        []string{"tag1", "tag2"},
-		Platform:    strPtr("web"),
-		Authors:     []string{"Author 1"},
-	}
+	testItem.Tags = nil
 
-	// Mock expectations
 	mockQuerier.On("GetItem", ctx, testItem.ID).Return(*testItem, nil)
-	mockQuerier.On("UpdateItem", ctx, expectedUpdateParams).Return(nil)
-
-	completedStatus := "completed"
-	mockQuerier.On("UpdateItemProcessingStatus", ctx, mock.MatchedBy(func(params db.UpdateItemProcessingStatusParams) bool {
-		return params.ID == testItem.ID &&
-			params.ProcessingStatus != nil &&
-			*params.ProcessingStatus == completedStatus &&
-			params.ProcessingError == nil
+	mockQuerier.On("ResolveTagAliases", ctx, db.ResolveTagAliasesParams{
+		UserID:  1,
+		Aliases: []string{"ai", "machine-learning", "artificial-intelligence"},
+	}).Return([]db.ResolveTagAliasesRow{{Alias: "artificial-intelligence", Tag: "ai"}}, nil)
+	mockQuerier.On("UpdateItem", ctx, mock.MatchedBy(func(params db.UpdateItemParams) bool {
+		return assert.ObjectsAreEqual([]string{"ai", "machine-learning"}, params.Tags)
 	})).Return(nil)
+	mockQuerier.On("UpdateItemProcessingStatus", ctx, mock.Anything).Return(nil)
 
-	// Execute
-	err := jobQueueService.CompleteItem(
-		ctx,
-		testItem.ID,
-		"AI Extracted Title",
-		"Extracted content",
-		"Extracted summary",
-		"article",
-		"web",
-		[]string{"tag1", "tag2"},
-		[]string{"Author 1"},
-	)
+	err := jobQueueService.CompleteItem(ctx, testItem.ID, "Title", "content", "summary", "article", "web",
+		[]string{"AI", "Machine Learning", " ", "machine_learning", "Artificial Intelligence"}, nil)
 
-	// Assert
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
 }
@@ -0,0 +1,679 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for RS384/RS512/ES384
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// OIDC sign-in errors returned by OIDCService
+var (
+	ErrOIDCStateInvalid    = errors.New("invalid or expired oidc state")
+	ErrOIDCAuthFailed      = errors.New("oidc authentication failed")
+	ErrOIDCAccountConflict = errors.New("email is already registered to another account")
+)
+
+// oidcClockSkew is how far the identity provider's clock may drift from ours
+const oidcClockSkew = time.Minute
+
+// maxOIDCResponseSize caps discovery, JWKS and token responses
+const maxOIDCResponseSize = 1 << 20
+
+// OIDCService signs users in through an OpenID Connect identity provider
+// using the authorization-code flow with PKCE
+type OIDCService interface {
+	// BeginLogin builds the provider authorization URL and a signed state
+	// value that must be returned unchanged to CompleteLogin
+	BeginLogin(ctx context.Context) (*OIDCLoginRequest, error)
+
+	// CompleteLogin exchanges an authorization code, verifies the ID token and
+	// returns a session for the user linked to the provider identity
+	CompleteLogin(ctx context.Context, state string, code string) (*AuthSession, error)
+}
+
+// OIDCConfig holds configuration for the OIDC service
+type OIDCConfig struct {
+	ProviderName string        // Stored in users.auth_provider for linked accounts
+	IssuerURL    string        // Issuer used for discovery and ID token validation
+	ClientID     string        // OAuth client ID registered with the provider
+	ClientSecret string        // OAuth client secret; empty for public clients
+	RedirectURL  string        // Callback URL registered with the provider
+	Scopes       []string      // Requested scopes; "openid" is always included
+	StateSecret  []byte        // HMAC key used to sign the login state
+	StateTTL     time.Duration // How long a login attempt may take
+	HTTPClient   *http.Client  // Client used to talk to the provider
+}
+
+// OIDCLoginRequest is the start of an authorization-code login
+type OIDCLoginRequest struct {
+	AuthURL   string
+	State     string
+	ExpiresAt time.Time
+}
+
+// oidcProviderMetadata is the subset of the discovery document we rely on
+type oidcProviderMetadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	SigningAlgorithms     []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// oidcState is the signed payload carried through the provider redirect
+type oidcState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"verifier"`
+	ExpiresAt    int64  `json:"exp"`
+}
+
+// oidcTokenResponse is the token endpoint response
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// idTokenClaims are the ID token claims used to identify the user
+type idTokenClaims struct {
+	Issuer        string          `json:"iss"`
+	Subject       string          `json:"sub"`
+	Audience      audienceClaim   `json:"aud"`
+	AuthorizedBy  string          `json:"azp"`
+	ExpiresAt     int64           `json:"exp"`
+	IssuedAt      int64           `json:"iat"`
+	Nonce         string          `json:"nonce"`
+	Email         string          `json:"email"`
+	EmailVerified json.RawMessage `json:"email_verified"`
+	Name          string          `json:"name"`
+}
+
+// audienceClaim accepts both the string and array forms of "aud"
+type audienceClaim []string
+
+func (a *audienceClaim) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audienceClaim{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+// emailVerified accepts both boolean and string values, as some providers
+// send "true" instead of true
+func (c idTokenClaims) emailVerified() bool {
+	value := strings.Trim(string(c.EmailVerified), `"`)
+	return value == "true"
+}
+
+// jsonWebKey is a single key from the provider's JWKS
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type oidcService struct {
+	querier     db.Querier
+	authService AuthService
+	config      OIDCConfig
+	now         func() time.Time
+
+	mu       sync.Mutex
+	metadata *oidcProviderMetadata
+	keys     map[string]crypto.PublicKey
+}
+
+// NewOIDCService creates a new OIDC service. Provider metadata is discovered
+// lazily on first use so the server can start while the provider is down.
+func NewOIDCService(querier db.Querier, authService AuthService, config OIDCConfig) OIDCService {
+	if config.ProviderName == "" {
+		config.ProviderName = "oidc"
+	}
+	if config.StateTTL <= 0 {
+		config.StateTTL = 10 * time.Minute
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if !slices.Contains(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+
+	return &oidcService{
+		querier:     querier,
+		authService: authService,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// BeginLogin builds the authorization URL for a new login attempt
+func (s *oidcService) BeginLogin(ctx context.Context) (*OIDCLoginRequest, error) {
+	metadata, err := s.providerMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomURLString(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	expiresAt := s.now().Add(s.config.StateTTL)
+	state, err := s.signState(oidcState{
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.config.ClientID},
+		"redirect_uri":          {s.config.RedirectURL},
+		"scope":                 {strings.Join(s.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return &OIDCLoginRequest{
+		AuthURL:   metadata.AuthorizationEndpoint + separator + query.Encode(),
+		State:     state,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// CompleteLogin finishes a login attempt started by BeginLogin
+func (s *oidcService) CompleteLogin(ctx context.Context, state string, code string) (*AuthSession, error) {
+	loginState, err := s.verifyState(state)
+	if err != nil {
+		return nil, err
+	}
+	if code == "" {
+		return nil, fmt.Errorf("%w: missing authorization code", ErrOIDCAuthFailed)
+	}
+
+	metadata, err := s.providerMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := s.exchangeCode(ctx, metadata, code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.verifyIDToken(ctx, metadata, rawIDToken, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.IssueSession(ctx, user)
+}
+
+// providerMetadata returns the cached discovery document, fetching it on first use
+func (s *oidcService) providerMetadata(ctx context.Context) (*oidcProviderMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.metadata != nil {
+		return s.metadata, nil
+	}
+
+	var metadata oidcProviderMetadata
+	if err := s.getJSON(ctx, s.config.IssuerURL+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != s.config.IssuerURL {
+		return nil, fmt.Errorf("oidc discovery issuer %q does not match configured issuer %q", metadata.Issuer, s.config.IssuerURL)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery document is missing required endpoints")
+	}
+
+	s.metadata = &metadata
+	return s.metadata, nil
+}
+
+// signingKey returns the provider key with the given ID, refreshing the JWKS
+// once when the key is unknown so provider key rotation is picked up
+func (s *oidcService) signingKey(ctx context.Context, metadata *oidcProviderMetadata, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := s.getJSON(ctx, metadata.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip keys we cannot use rather than failing every login
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	s.keys = keys
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrOIDCAuthFailed, kid)
+	}
+	return key, nil
+}
+
+// exchangeCode redeems an authorization code for the provider's ID token
+func (s *oidcService) exchangeCode(ctx context.Context, metadata *oidcProviderMetadata, code string, codeVerifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.config.RedirectURL},
+		"client_id":     {s.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOIDCResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var tokenResponse oidcTokenResponse
+	if err := json.Unmarshal(body, &tokenResponse); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || tokenResponse.Error != "" {
+		if tokenResponse.Error != "" {
+			return "", fmt.Errorf("%w: token endpoint returned %s: %s", ErrOIDCAuthFailed, tokenResponse.Error, tokenResponse.ErrorDescription)
+		}
+		return "", fmt.Errorf("%w: token endpoint returned status %d", ErrOIDCAuthFailed, resp.StatusCode)
+	}
+	if tokenResponse.IDToken == "" {
+		return "", fmt.Errorf("%w: token response did not include an id_token", ErrOIDCAuthFailed)
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+// verifyIDToken checks the ID token signature and the claims that bind it to
+// this client and login attempt
+func (s *oidcService) verifyIDToken(ctx context.Context, metadata *oidcProviderMetadata, rawIDToken string, nonce string) (*idTokenClaims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed id token", ErrOIDCAuthFailed)
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed id token header", ErrOIDCAuthFailed)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed id token header", ErrOIDCAuthFailed)
+	}
+
+	if len(metadata.SigningAlgorithms) > 0 && !slices.Contains(metadata.SigningAlgorithms, header.Alg) {
+		return nil, fmt.Errorf("%w: id token algorithm %q is not advertised by the provider", ErrOIDCAuthFailed, header.Alg)
+	}
+
+	key, err := s.signingKey(ctx, metadata, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed id token signature", ErrOIDCAuthFailed)
+	}
+	if err := verifyJWSSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCAuthFailed, err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed id token payload", ErrOIDCAuthFailed)
+	}
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed id token claims", ErrOIDCAuthFailed)
+	}
+
+	now := s.now()
+	switch {
+	case claims.Issuer != metadata.Issuer:
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrOIDCAuthFailed, claims.Issuer)
+	case !slices.Contains(claims.Audience, s.config.ClientID):
+		return nil, fmt.Errorf("%w: id token was not issued for this client", ErrOIDCAuthFailed)
+	case len(claims.Audience) > 1 && claims.AuthorizedBy != s.config.ClientID:
+		return nil, fmt.Errorf("%w: id token was not authorized for this client", ErrOIDCAuthFailed)
+	case claims.ExpiresAt == 0 || now.Add(-oidcClockSkew).Unix() >= claims.ExpiresAt:
+		return nil, fmt.Errorf("%w: id token has expired", ErrOIDCAuthFailed)
+	case claims.IssuedAt > now.Add(oidcClockSkew).Unix():
+		return nil, fmt.Errorf("%w: id token was issued in the future", ErrOIDCAuthFailed)
+	case !hmac.Equal([]byte(claims.Nonce), []byte(nonce)):
+		return nil, fmt.Errorf("%w: id token nonce does not match", ErrOIDCAuthFailed)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: id token has no subject", ErrOIDCAuthFailed)
+	}
+
+	return &claims, nil
+}
+
+// resolveUser finds the user linked to the provider identity, linking an
+// existing account with the same email when both sides have verified it, or
+// creating a new one
+func (s *oidcService) resolveUser(ctx context.Context, claims *idTokenClaims) (*db.User, error) {
+	provider := s.config.ProviderName
+	subject := claims.Subject
+
+	user, err := s.querier.GetUserByOAuthID(ctx, db.GetUserByOAuthIDParams{
+		AuthProvider: &provider,
+		OauthID:      &subject,
+	})
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up user by oauth id: %w", err)
+	}
+
+	var email *string
+	if claims.Email != "" {
+		email = &claims.Email
+
+		existing, err := s.querier.GetUserByEmail(ctx, email)
+		if err == nil {
+			// Only link when the provider vouches for the email, the account
+			// owner has proven control of it too, and the account is not
+			// already tied to another identity
+			if !claims.emailVerified() || !existing.EmailVerified || existing.OauthID != nil {
+				return nil, ErrOIDCAccountConflict
+			}
+
+			linked, err := s.querier.LinkUserOAuthIdentity(ctx, db.LinkUserOAuthIdentityParams{
+				ID:           existing.ID,
+				AuthProvider: &provider,
+				OauthID:      &subject,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to link oauth identity: %w", err)
+			}
+			return &linked, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to look up user by email: %w", err)
+		}
+	}
+
+	var name *string
+	if claims.Name != "" {
+		name = &claims.Name
+	}
+
+	created, err := s.querier.CreateUser(ctx, db.CreateUserParams{
+		Name:          name,
+		Email:         email,
+		AuthProvider:  &provider,
+		OauthID:       &subject,
+		EmailVerified: email != nil && claims.emailVerified(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &created, nil
+}
+
+// getJSON fetches a JSON document from the provider
+func (s *oidcService) getJSON(ctx context.Context, endpoint string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", endpoint, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxOIDCResponseSize)).Decode(target)
+}
+
+// signState serializes and signs the login state
+func (s *oidcService) signState(state oidcState) (string, error) {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal oidc state: %w", err)
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + s.stateSignature(encodedPayload), nil
+}
+
+// verifyState checks the login state's signature and expiry
+func (s *oidcService) verifyState(state string) (*oidcState, error) {
+	encodedPayload, signature, found := strings.Cut(state, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.stateSignature(encodedPayload))) {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	var loginState oidcState
+	if err := json.Unmarshal(payload, &loginState); err != nil {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	if s.now().Unix() >= loginState.ExpiresAt {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	return &loginState, nil
+}
+
+// stateSignature signs with a key derived from the state secret so a login
+// state can never be replayed as a session token signed with the same secret
+func (s *oidcService) stateSignature(encodedPayload string) string {
+	keyMAC := hmac.New(sha256.New, s.config.StateSecret)
+	keyMAC.Write([]byte("briefbot-oidc-state"))
+
+	mac := hmac.New(sha256.New, keyMAC.Sum(nil))
+	mac.Write([]byte(encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// publicKey converts a JWK into an RSA or ECDSA public key
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("invalid EC coordinate length")
+		}
+		point := append([]byte{4}, append(x, y...)...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// verifyJWSSignature verifies a compact JWS signature for the supported algorithms
+func verifyJWSSignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported id token algorithm %q", alg)
+	}
+
+	hasher := hash.New()
+	hasher.Write([]byte(signingInput))
+	digest := hasher.Sum(nil)
+
+	switch strings.ToUpper(alg[:2]) {
+	case "RS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key does not match algorithm %s", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature); err != nil {
+			return fmt.Errorf("invalid id token signature")
+		}
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key does not match algorithm %s", alg)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid id token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		sig := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, sig) {
+			return fmt.Errorf("invalid id token signature")
+		}
+	}
+
+	return nil
+}
+
+// randomURLString returns n random bytes encoded as base64url
+func randomURLString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+const (
+	testOIDCClientID     = "briefbot-test"
+	testOIDCClientSecret = "client-secret"
+	testOIDCRedirectURL  = "http://localhost:8080/auth/oidc/callback"
+)
+
+// mockIssuer is a minimal OpenID provider serving discovery, JWKS and a token
+// endpoint that signs whatever claims the test configures
+type mockIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	keyID  string
+
+	// claims customizes the ID token returned for an authorization code
+	claims func(nonce string) map[string]interface{}
+
+	// tokenKey, when set, signs ID tokens instead of the published key
+	tokenKey *rsa.PrivateKey
+
+	// advertisedIssuer, when set, replaces the issuer in the discovery document
+	advertisedIssuer string
+
+	// Captured from the authorization request so the token endpoint can
+	// behave like a real provider
+	nonce         string
+	codeChallenge string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &mockIssuer{t: t, key: key, keyID: "test-key"}
+	issuer.claims = func(nonce string) map[string]interface{} {
+		return issuer.defaultClaims(nonce)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		advertised := issuer.server.URL
+		if issuer.advertisedIssuer != "" {
+			advertised = issuer.advertisedIssuer
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                advertised,
+			"authorization_endpoint":                issuer.server.URL + "/authorize",
+			"token_endpoint":                        issuer.server.URL + "/token",
+			"jwks_uri":                              issuer.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": issuer.keyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(issuer.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", issuer.handleToken)
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *mockIssuer) defaultClaims(nonce string) map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":            i.server.URL,
+		"sub":            "subject-123",
+		"aud":            testOIDCClientID,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          nonce,
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+	}
+}
+
+func (i *mockIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	require.NoError(i.t, r.ParseForm())
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != testOIDCClientID || clientSecret != testOIDCClientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	verifierHash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != "valid-code" ||
+		r.PostForm.Get("redirect_uri") != testOIDCRedirectURL ||
+		base64.RawURLEncoding.EncodeToString(verifierHash[:]) != i.codeChallenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	key := i.key
+	if i.tokenKey != nil {
+		key = i.tokenKey
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     i.signIDToken(key, i.claims(i.nonce)),
+	})
+}
+
+// signIDToken signs claims as an RS256 JWT
+func (i *mockIssuer) signIDToken(key *rsa.PrivateKey, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": i.keyID})
+	require.NoError(i.t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(i.t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(i.t, err)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// authorize plays the provider's authorization endpoint, recording the nonce
+// and PKCE challenge and returning the state to pass to CompleteLogin
+func (i *mockIssuer) authorize(authURL string) string {
+	parsed, err := url.Parse(authURL)
+	require.NoError(i.t, err)
+
+	query := parsed.Query()
+	assert.Equal(i.t, i.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(i.t, "code", query.Get("response_type"))
+	assert.Equal(i.t, testOIDCClientID, query.Get("client_id"))
+	assert.Equal(i.t, testOIDCRedirectURL, query.Get("redirect_uri"))
+	assert.Equal(i.t, "S256", query.Get("code_challenge_method"))
+	assert.Contains(i.t, query.Get("scope"), "openid")
+
+	i.nonce = query.Get("nonce")
+	i.codeChallenge = query.Get("code_challenge")
+	return query.Get("state")
+}
+
+func newTestOIDCService(t *testing.T, querier db.Querier, issuer *mockIssuer) OIDCService {
+	t.Helper()
+	return NewOIDCService(querier, newTestAuthService(querier), OIDCConfig{
+		ProviderName: "test-idp",
+		IssuerURL:    issuer.server.URL,
+		ClientID:     testOIDCClientID,
+		ClientSecret: testOIDCClientSecret,
+		RedirectURL:  testOIDCRedirectURL,
+		Scopes:       []string{"email", "profile"},
+		StateSecret:  []byte("test-secret"),
+	})
+}
+
+// beginLogin starts a login and walks it through the mock authorization endpoint
+func beginLogin(t *testing.T, service OIDCService, issuer *mockIssuer) string {
+	t.Helper()
+	login, err := service.BeginLogin(context.Background())
+	require.NoError(t, err)
+	return issuer.authorize(login.AuthURL)
+}
+
+func oauthIdentityParams(provider, subject string) db.GetUserByOAuthIDParams {
+	return db.GetUserByOAuthIDParams{AuthProvider: &provider, OauthID: &subject}
+}
+
+func TestOIDCLogin_CreatesUser(t *testing.T) {
+	issuer := newMockIssuer(t)
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	ctx := context.Background()
+	email := "jane@example.com"
+
+	mockQuerier.On("GetUserByOAuthID", ctx, oauthIdentityParams("test-idp", "subject-123")).Return(db.User{}, pgx.ErrNoRows)
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{}, pgx.ErrNoRows)
+	mockQuerier.On("CreateUser", ctx, mock.MatchedBy(func(params db.CreateUserParams) bool {
+		return *params.Name == "Jane Doe" &&
+			*params.Email == email &&
+			*params.AuthProvider == "test-idp" &&
+			*params.OauthID == "subject-123" &&
+			params.EmailVerified &&
+			params.PasswordHash == nil
+	})).Return(db.User{ID: 7, Email: &email}, nil)
+
+	state := beginLogin(t, service, issuer)
+	session, err := service.CompleteLogin(ctx, state, "valid-code")
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), session.User.ID)
+	assert.NotEmpty(t, session.Token)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestOIDCLogin_ExistingIdentity(t *testing.T) {
+	issuer := newMockIssuer(t)
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	ctx := context.Background()
+
+	mockQuerier.On("GetUserByOAuthID", ctx, oauthIdentityParams("test-idp", "subject-123")).Return(db.User{ID: 3}, nil)
+
+	state := beginLogin(t, service, issuer)
+	session, err := service.CompleteLogin(ctx, state, "valid-code")
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), session.User.ID)
+	mockQuerier.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	mockQuerier.AssertNotCalled(t, "LinkUserOAuthIdentity", mock.Anything, mock.Anything)
+}
+
+func TestOIDCLogin_LinksVerifiedEmail(t *testing.T) {
+	issuer := newMockIssuer(t)
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	ctx := context.Background()
+	email := "jane@example.com"
+	provider := "test-idp"
+	subject := "subject-123"
+
+	mockQuerier.On("GetUserByOAuthID", ctx, oauthIdentityParams(provider, subject)).Return(db.User{}, pgx.ErrNoRows)
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 5, Email: &email, EmailVerified: true}, nil)
+	mockQuerier.On("LinkUserOAuthIdentity", ctx, db.LinkUserOAuthIdentityParams{
+		ID:           5,
+		AuthProvider: &provider,
+		OauthID:      &subject,
+	}).Return(db.User{ID: 5, Email: &email, AuthProvider: &provider, OauthID: &subject}, nil)
+
+	state := beginLogin(t, service, issuer)
+	session, err := service.CompleteLogin(ctx, state, "valid-code")
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), session.User.ID)
+	mockQuerier.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestOIDCLogin_EmailVerifiedAsString(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.claims = func(nonce string) map[string]interface{} {
+		claims := issuer.defaultClaims(nonce)
+		claims["email_verified"] = "true"
+		return claims
+	}
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	ctx := context.Background()
+	email := "jane@example.com"
+
+	mockQuerier.On("GetUserByOAuthID", ctx, mock.Anything).Return(db.User{}, pgx.ErrNoRows)
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 5, Email: &email, EmailVerified: true}, nil)
+	mockQuerier.On("LinkUserOAuthIdentity", ctx, mock.Anything).Return(db.User{ID: 5}, nil)
+
+	state := beginLogin(t, service, issuer)
+	_, err := service.CompleteLogin(ctx, state, "valid-code")
+
+	require.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestOIDCLogin_AccountConflict(t *testing.T) {
+	otherSubject := "other-subject"
+
+	tests := []struct {
+		name          string
+		emailVerified interface{}
+		existing      db.User
+	}{
+		{name: "unverified email", emailVerified: false, existing: db.User{ID: 5, EmailVerified: true}},
+		{name: "unverified account email", emailVerified: true, existing: db.User{ID: 5}},
+		{name: "already linked", emailVerified: true, existing: db.User{ID: 5, EmailVerified: true, OauthID: &otherSubject}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			issuer.claims = func(nonce string) map[string]interface{} {
+				claims := issuer.defaultClaims(nonce)
+				claims["email_verified"] = tt.emailVerified
+				return claims
+			}
+			mockQuerier := new(test.MockQuerier)
+			service := newTestOIDCService(t, mockQuerier, issuer)
+
+			ctx := context.Background()
+			email := "jane@example.com"
+
+			mockQuerier.On("GetUserByOAuthID", ctx, mock.Anything).Return(db.User{}, pgx.ErrNoRows)
+			mockQuerier.On("GetUserByEmail", ctx, &email).Return(tt.existing, nil)
+
+			state := beginLogin(t, service, issuer)
+			session, err := service.CompleteLogin(ctx, state, "valid-code")
+
+			assert.ErrorIs(t, err, ErrOIDCAccountConflict)
+			assert.Nil(t, session)
+			mockQuerier.AssertNotCalled(t, "LinkUserOAuthIdentity", mock.Anything, mock.Anything)
+			mockQuerier.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOIDCLogin_RejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(claims map[string]interface{})
+	}{
+		{name: "wrong nonce", modify: func(claims map[string]interface{}) { claims["nonce"] = "replayed" }},
+		{name: "wrong audience", modify: func(claims map[string]interface{}) { claims["aud"] = "another-client" }},
+		{name: "wrong issuer", modify: func(claims map[string]interface{}) { claims["iss"] = "https://evil.example.com" }},
+		{name: "expired", modify: func(claims map[string]interface{}) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "missing subject", modify: func(claims map[string]interface{}) { delete(claims, "sub") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			issuer.claims = func(nonce string) map[string]interface{} {
+				claims := issuer.defaultClaims(nonce)
+				tt.modify(claims)
+				return claims
+			}
+			mockQuerier := new(test.MockQuerier)
+			service := newTestOIDCService(t, mockQuerier, issuer)
+
+			state := beginLogin(t, service, issuer)
+			session, err := service.CompleteLogin(context.Background(), state, "valid-code")
+
+			assert.ErrorIs(t, err, ErrOIDCAuthFailed)
+			assert.Nil(t, session)
+			mockQuerier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOIDCLogin_RejectsForgedSignature(t *testing.T) {
+	issuer := newMockIssuer(t)
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	// Sign with a key the provider never published, under the published key ID
+	forgedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer.tokenKey = forgedKey
+
+	state := beginLogin(t, service, issuer)
+	session, err := service.CompleteLogin(context.Background(), state, "valid-code")
+
+	assert.ErrorIs(t, err, ErrOIDCAuthFailed)
+	assert.Nil(t, session)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestOIDCLogin_InvalidState(t *testing.T) {
+	issuer := newMockIssuer(t)
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	state := beginLogin(t, service, issuer)
+
+	tests := []struct {
+		name  string
+		state string
+	}{
+		{name: "empty", state: ""},
+		{name: "garbage", state: "not-a-state"},
+		{name: "tampered", state: state + "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := service.CompleteLogin(context.Background(), tt.state, "valid-code")
+
+			assert.ErrorIs(t, err, ErrOIDCStateInvalid)
+			assert.Nil(t, session)
+		})
+	}
+}
+
+func TestOIDCLogin_ExpiredState(t *testing.T) {
+	issuer := newMockIssuer(t)
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	state := beginLogin(t, service, issuer)
+	service.(*oidcService).now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	session, err := service.CompleteLogin(context.Background(), state, "valid-code")
+
+	assert.ErrorIs(t, err, ErrOIDCStateInvalid)
+	assert.Nil(t, session)
+}
+
+func TestOIDCLogin_TokenExchangeFails(t *testing.T) {
+	issuer := newMockIssuer(t)
+	mockQuerier := new(test.MockQuerier)
+	service := newTestOIDCService(t, mockQuerier, issuer)
+
+	state := beginLogin(t, service, issuer)
+	session, err := service.CompleteLogin(context.Background(), state, "wrong-code")
+
+	assert.ErrorIs(t, err, ErrOIDCAuthFailed)
+	assert.Contains(t, err.Error(), "invalid_grant")
+	assert.Nil(t, session)
+}
+
+func TestOIDCBeginLogin_IssuerMismatch(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.advertisedIssuer = "https://evil.example.com"
+	service := newTestOIDCService(t, new(test.MockQuerier), issuer)
+
+	login, err := service.BeginLogin(context.Background())
+
+	assert.ErrorContains(t, err, "does not match configured issuer")
+	assert.Nil(t, login)
+}
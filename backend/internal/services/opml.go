@@ -0,0 +1,223 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ErrInvalidOPML is returned for documents that are not OPML subscription lists
+var ErrInvalidOPML = errors.New("invalid OPML document")
+
+// MaxOPMLSize bounds uploaded OPML documents, in bytes
+const MaxOPMLSize = 1 << 20
+
+// maxOPMLOutlines caps the outlines read from one document
+const maxOPMLOutlines = 1000
+
+// OPMLFeed is a feed listed in an OPML document. Tags come from the folders
+// the feed is nested in and from its category attribute.
+type OPMLFeed struct {
+	URL     string
+	Title   string
+	SiteURL string
+	Tags    []string
+}
+
+type opmlDocument struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Head    opmlHead  `xml:"head"`
+	Body    *opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Category string        `xml:"category,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ParseOPML reads the feeds of an OPML 1.0 or 2.0 subscription list. Outlines
+// with an xmlUrl are feeds; the others are folders whose names become tags of
+// the feeds inside them. A feed listed more than once is returned once with
+// the tags of every listing. URLs are returned as written, so callers can
+// report the ones that are not valid.
+func ParseOPML(data []byte) ([]OPMLFeed, error) {
+	var doc opmlDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOPML, err)
+	}
+	if doc.XMLName.Local != "opml" {
+		return nil, fmt.Errorf("%w: unexpected <%s> document", ErrInvalidOPML, doc.XMLName.Local)
+	}
+	switch doc.Version {
+	case "", "1.0", "1.1", "2.0":
+	default:
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidOPML, doc.Version)
+	}
+	if doc.Body == nil {
+		return nil, fmt.Errorf("%w: missing <body>", ErrInvalidOPML)
+	}
+
+	parser := &opmlParser{index: make(map[string]int)}
+	if err := parser.walk(doc.Body.Outlines, nil); err != nil {
+		return nil, err
+	}
+	return parser.feeds, nil
+}
+
+type opmlParser struct {
+	feeds    []OPMLFeed
+	index    map[string]int
+	outlines int
+}
+
+func (p *opmlParser) walk(outlines []opmlOutline, folders []string) error {
+	for _, outline := range outlines {
+		p.outlines++
+		if p.outlines > maxOPMLOutlines {
+			return fmt.Errorf("%w: more than %d outlines", ErrInvalidOPML, maxOPMLOutlines)
+		}
+
+		name := strings.TrimSpace(outline.Title)
+		if name == "" {
+			name = strings.TrimSpace(outline.Text)
+		}
+
+		feedURL := strings.TrimSpace(outline.XMLURL)
+		if feedURL == "" {
+			// A folder; nameless ones group nothing
+			nested := folders
+			if name != "" {
+				nested = append(append([]string(nil), folders...), name)
+			}
+			if err := p.walk(outline.Outlines, nested); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tags := normalizeTags(append(append([]string(nil), folders...), opmlCategories(outline.Category)...))
+		if i, ok := p.index[feedURL]; ok {
+			p.feeds[i].Tags = mergeTags(p.feeds[i].Tags, tags)
+			continue
+		}
+		p.index[feedURL] = len(p.feeds)
+		p.feeds = append(p.feeds, OPMLFeed{
+			URL:     feedURL,
+			Title:   name,
+			SiteURL: strings.TrimSpace(outline.HTMLURL),
+			Tags:    tags,
+		})
+	}
+	return nil
+}
+
+// opmlCategories splits a category attribute, a comma-separated list of
+// slash-delimited paths such as "/Tech/Go,/News", into its path segments
+func opmlCategories(category string) []string {
+	var tags []string
+	for _, path := range strings.Split(category, ",") {
+		for _, segment := range strings.Split(path, "/") {
+			if segment = strings.TrimSpace(segment); segment != "" {
+				tags = append(tags, segment)
+			}
+		}
+	}
+	return tags
+}
+
+// RenderOPML writes feeds as an OPML 2.0 document. Each feed is placed in a
+// folder named after its first tag and lists all its tags as categories, so
+// importing the document again restores them.
+func RenderOPML(title string, feeds []OPMLFeed, created time.Time) ([]byte, error) {
+	doc := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       title,
+			DateCreated: created.UTC().Format(time.RFC1123Z),
+		},
+		Body: &opmlBody{},
+	}
+
+	folders := make(map[string]int)
+	for _, feed := range feeds {
+		outline := opmlOutline{
+			Text:    feed.Title,
+			Title:   feed.Title,
+			Type:    "rss",
+			XMLURL:  feed.URL,
+			HTMLURL: feed.SiteURL,
+		}
+		if outline.Text == "" {
+			outline.Text = feed.URL
+		}
+		if len(feed.Tags) == 0 {
+			doc.Body.Outlines = append(doc.Body.Outlines, outline)
+			continue
+		}
+
+		var categories []string
+		for _, tag := range feed.Tags {
+			// Separators would split the tag when read back; the folder still carries the first tag
+			if !strings.ContainsAny(tag, "/,") {
+				categories = append(categories, "/"+tag)
+			}
+		}
+		outline.Category = strings.Join(categories, ",")
+
+		folder := feed.Tags[0]
+		i, ok := folders[folder]
+		if !ok {
+			i = len(doc.Body.Outlines)
+			folders[folder] = i
+			doc.Body.Outlines = append(doc.Body.Outlines, opmlOutline{Text: folder, Title: folder})
+		}
+		doc.Body.Outlines[i].Outlines = append(doc.Body.Outlines[i].Outlines, outline)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render OPML: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// normalizeTags trims tags and drops blank and repeated ones, ignoring case.
+// A nil slice stays nil so callers can tell "no change" from "no tags".
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if key := strings.ToLower(tag); tag != "" && !seen[key] {
+			seen[key] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOPML(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Reader export</title></head>
+  <body>
+    <outline text="Tech" title="Tech">
+      <outline text="Go">
+        <outline type="rss" text="Go Blog" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+      </outline>
+      <outline type="rss" text="Example" title="Example Blog" xmlUrl=" https://blog.example.com/feed.xml " category="/Reading,/tech"/>
+    </outline>
+    <outline type="rss" text="Example again" xmlUrl="https://blog.example.com/feed.xml"/>
+    <outline text="">
+      <outline type="rss" text="Loose" xmlUrl="https://loose.example.com/rss"/>
+    </outline>
+    <outline text="Empty folder"/>
+  </body>
+</opml>`
+
+	feeds, err := ParseOPML([]byte(body))
+
+	require.NoError(t, err)
+	assert.Equal(t, []OPMLFeed{
+		{URL: "https://go.dev/blog/feed.atom", Title: "Go Blog", SiteURL: "https://go.dev/blog", Tags: []string{"Tech", "Go"}},
+		{URL: "https://blog.example.com/feed.xml", Title: "Example Blog", Tags: []string{"Tech", "Reading"}},
+		{URL: "https://loose.example.com/rss", Title: "Loose"},
+	}, feeds)
+}
+
+func TestParseOPML_Invalid(t *testing.T) {
+	for name, body := range map[string]string{
+		"empty":       "",
+		"not opml":    testFeedXML,
+		"no body":     `<opml version="2.0"><head/></opml>`,
+		"bad version": `<opml version="3.0"><body/></opml>`,
+		"too many":    `<opml version="2.0"><body>` + strings.Repeat(`<outline text="x"/>`, maxOPMLOutlines+1) + `</body></opml>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseOPML([]byte(body))
+			assert.ErrorIs(t, err, ErrInvalidOPML)
+		})
+	}
+}
+
+func TestRenderOPML(t *testing.T) {
+	feeds := []OPMLFeed{
+		{URL: "https://blog.example.com/feed.xml", Title: "Example & Co", SiteURL: "https://blog.example.com/", Tags: []string{"Tech", "AI/ML", "Reading"}},
+		{URL: "https://news.example.com/rss", Title: "News"},
+		{URL: "https://go.dev/blog/feed.atom", Title: "Go Blog", Tags: []string{"Tech"}},
+	}
+
+	data, err := RenderOPML("Feeds", feeds, time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	document := string(data)
+	assert.True(t, strings.HasPrefix(document, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, document, `<opml version="2.0">`)
+	assert.Contains(t, document, `<dateCreated>Mon, 04 Mar 2024 10:00:00 +0000</dateCreated>`)
+	assert.Contains(t, document, `category="/Tech,/Reading"`, "tags with separators are left to the folder")
+	assert.Equal(t, 1, strings.Count(document, `<outline text="Tech" title="Tech">`), "feeds sharing a first tag share a folder")
+
+	parsed, err := ParseOPML(data)
+	require.NoError(t, err)
+	assert.Equal(t, []OPMLFeed{
+		{URL: "https://blog.example.com/feed.xml", Title: "Example & Co", SiteURL: "https://blog.example.com/", Tags: []string{"Tech", "Reading"}},
+		{URL: "https://go.dev/blog/feed.atom", Title: "Go Blog", Tags: []string{"Tech"}},
+		{URL: "https://news.example.com/rss", Title: "News"},
+	}, parsed)
+}
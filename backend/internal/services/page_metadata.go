@@ -0,0 +1,327 @@
+package services
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// pageMetadata is what a page says about itself in OpenGraph, JSON-LD and <meta> tags
+type pageMetadata struct {
+	Title       string
+	Description string
+	Authors     []string
+	PublishedAt *time.Time
+	SiteName    string
+	ImageURL    string
+	Language    string
+	// CanonicalURL is the page's <link rel=canonical>, made absolute
+	CanonicalURL string
+}
+
+// jsonLDArticleTypes are the schema.org types whose properties describe the page content
+var jsonLDArticleTypes = map[string]bool{
+	"Article":              true,
+	"NewsArticle":          true,
+	"BlogPosting":          true,
+	"TechArticle":          true,
+	"ScholarlyArticle":     true,
+	"Report":               true,
+	"AnalysisNewsArticle":  true,
+	"OpinionNewsArticle":   true,
+	"ReportageNewsArticle": true,
+	"SocialMediaPosting":   true,
+	"VideoObject":          true,
+	"PodcastEpisode":       true,
+}
+
+// dateLayouts are the formats seen in published-date metadata
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// extractPageMetadata takes each field from the first source that has it,
+// among OpenGraph, JSON-LD, plain <meta> tags and the document itself
+func extractPageMetadata(doc *goquery.Document, pageURL *url.URL) pageMetadata {
+	ld := extractJSONLD(doc)
+
+	meta := pageMetadata{
+		Title: firstNonEmpty(
+			metaContent(doc, "og:title"),
+			ld.Title,
+			metaContent(doc, "twitter:title"),
+			metaContent(doc, "citation_title"),
+			strings.TrimSpace(doc.Find("title").First().Text()),
+			strings.TrimSpace(doc.Find("h1").First().Text()),
+		),
+		Description: firstNonEmpty(
+			metaContent(doc, "og:description"),
+			ld.Description,
+			metaContent(doc, "description"),
+			metaContent(doc, "twitter:description"),
+		),
+		SiteName: firstNonEmpty(
+			metaContent(doc, "og:site_name"),
+			ld.SiteName,
+			metaContent(doc, "application-name"),
+		),
+		ImageURL: resolveURL(pageURL, firstNonEmpty(
+			metaContent(doc, "og:image"),
+			metaContent(doc, "og:image:url"),
+			ld.ImageURL,
+			metaContent(doc, "twitter:image"),
+		)),
+		Language: firstNonEmpty(
+			strings.TrimSpace(doc.Find("html").AttrOr("lang", "")),
+			metaContent(doc, "og:locale"),
+		),
+		CanonicalURL: resolveURL(pageURL, doc.Find("link[rel~='canonical']").First().AttrOr("href", "")),
+	}
+
+	meta.Authors = ld.Authors
+	if len(meta.Authors) == 0 {
+		meta.Authors = metaAuthors(doc)
+	}
+
+	meta.PublishedAt = ld.PublishedAt
+	if meta.PublishedAt == nil {
+		for _, value := range []string{
+			metaContent(doc, "article:published_time"),
+			metaContent(doc, "citation_publication_date"),
+			metaContent(doc, "date"),
+			metaContent(doc, "pubdate"),
+			metaContent(doc, "publishdate"),
+			metaContent(doc, "DC.date.issued"),
+			doc.Find("[itemprop='datePublished']").First().AttrOr("content", ""),
+			doc.Find("[itemprop='datePublished']").First().AttrOr("datetime", ""),
+		} {
+			if parsed := parseMetadataDate(value); parsed != nil {
+				meta.PublishedAt = parsed
+				break
+			}
+		}
+	}
+
+	return meta
+}
+
+// metaContent returns the content of the first <meta> with the given name or property
+func metaContent(doc *goquery.Document, key string) string {
+	var value string
+	doc.Find("meta").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		name := s.AttrOr("property", s.AttrOr("name", s.AttrOr("itemprop", "")))
+		if strings.EqualFold(name, key) {
+			value = strings.TrimSpace(s.AttrOr("content", ""))
+			return value == ""
+		}
+		return true
+	})
+	return value
+}
+
+// metaAuthors collects authors from <meta> tags and rel=author links
+func metaAuthors(doc *goquery.Document) []string {
+	var authors []string
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		name := strings.ToLower(s.AttrOr("name", s.AttrOr("property", "")))
+		switch name {
+		case "author", "article:author", "citation_author", "dc.creator", "parsely-author", "sailthru.author":
+			content := strings.TrimSpace(s.AttrOr("content", ""))
+			// article:author is often a profile URL rather than a name
+			if content != "" && !strings.HasPrefix(content, "http") {
+				authors = append(authors, content)
+			}
+		}
+	})
+	if len(authors) == 0 {
+		doc.Find("a[rel='author'], [itemprop='author'] [itemprop='name']").Each(func(_ int, s *goquery.Selection) {
+			authors = append(authors, strings.TrimSpace(s.Text()))
+		})
+	}
+	return normalizeAuthors(authors)
+}
+
+// normalizeAuthors trims bylines, drops duplicates and keeps the original order
+func normalizeAuthors(names []string) []string {
+	seen := make(map[string]bool)
+	var authors []string
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		if len(name) > 3 && strings.EqualFold(name[:3], "by ") {
+			name = strings.TrimSpace(name[3:])
+		}
+		key := strings.ToLower(name)
+		if name == "" || len(name) > 100 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		authors = append(authors, name)
+	}
+	return authors
+}
+
+// jsonLDMetadata is the subset of schema.org properties used from JSON-LD
+type jsonLDMetadata struct {
+	Title       string
+	Description string
+	Authors     []string
+	PublishedAt *time.Time
+	SiteName    string
+	ImageURL    string
+}
+
+// extractJSONLD reads the first article-like object from the page's JSON-LD
+// scripts, looking inside arrays and @graph containers
+func extractJSONLD(doc *goquery.Document) jsonLDMetadata {
+	var found map[string]any
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var data any
+		if err := json.Unmarshal([]byte(s.Text()), &data); err != nil {
+			return true
+		}
+		found = findJSONLDArticle(data)
+		return found == nil
+	})
+	if found == nil {
+		return jsonLDMetadata{}
+	}
+
+	meta := jsonLDMetadata{
+		Title:       firstNonEmpty(jsonString(found["headline"]), jsonString(found["name"])),
+		Description: jsonString(found["description"]),
+		Authors:     normalizeAuthors(jsonLDNames(found["author"])),
+		PublishedAt: parseMetadataDate(firstNonEmpty(jsonString(found["datePublished"]), jsonString(found["uploadDate"]))),
+		ImageURL:    jsonLDURL(found["image"]),
+	}
+	if publisher := jsonLDNames(found["publisher"]); len(publisher) > 0 {
+		meta.SiteName = publisher[0]
+	}
+	return meta
+}
+
+func findJSONLDArticle(data any) map[string]any {
+	switch v := data.(type) {
+	case []any:
+		for _, entry := range v {
+			if article := findJSONLDArticle(entry); article != nil {
+				return article
+			}
+		}
+	case map[string]any:
+		if jsonLDHasType(v["@type"]) {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findJSONLDArticle(graph)
+		}
+	}
+	return nil
+}
+
+func jsonLDHasType(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return jsonLDArticleTypes[v]
+	case []any:
+		for _, entry := range v {
+			if s, ok := entry.(string); ok && jsonLDArticleTypes[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDNames reads names from a Person/Organization value, a plain string, or a list of either
+func jsonLDNames(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case map[string]any:
+		if name := jsonString(v["name"]); name != "" {
+			return []string{name}
+		}
+	case []any:
+		var names []string
+		for _, entry := range v {
+			names = append(names, jsonLDNames(entry)...)
+		}
+		return names
+	}
+	return nil
+}
+
+// jsonLDURL reads a URL from a string, an ImageObject, or the first entry of a list
+func jsonLDURL(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		return jsonString(v["url"])
+	case []any:
+		if len(v) > 0 {
+			return jsonLDURL(v[0])
+		}
+	}
+	return ""
+}
+
+func jsonString(value any) string {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return ""
+}
+
+// parseMetadataDate parses the date formats pages use, returning nil when none match
+func parseMetadataDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			parsed = parsed.UTC()
+			return &parsed
+		}
+	}
+	return nil
+}
+
+// resolveURL makes ref absolute against base, returning "" for unusable references
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "data:") || strings.HasPrefix(strings.ToLower(ref), "javascript:") {
+		return ""
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		parsed = base.ResolveReference(parsed)
+	}
+	return parsed.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// ErrInvalidListQuery is returned for unknown sort orders or filters and for
+// cursors that are malformed or belong to a different sort order
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// List sort keys and orders
+const (
+	SortCreatedAt = "created_at"
+	SortTitle     = "title"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// List page sizes
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// PageQuery selects one page of a list. Lists are sorted by SortCreatedAt,
+// newest first, unless asked otherwise; titles sort A to Z by default. Ties
+// are broken by ID so every row appears on exactly one page.
+type PageQuery struct {
+	Sort  string
+	Order string
+	Limit int
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
+}
+
+// ItemListQuery filters the items a user can see. Nil and empty filters match every item.
+type ItemListQuery struct {
+	// WorkspaceID limits the list to one workspace's items
+	WorkspaceID *int32
+	// Tags must all be present on an item. They are normalized and resolved
+	// through the user's aliases as extracted tags are.
+	Tags          []string
+	Platform      *string
+	Type          *string
+	IsRead        *bool
+	Status        *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Page          PageQuery
+}
+
+// ItemPage is one page of items with the user's read state. NextCursor is
+// empty on the last page.
+type ItemPage struct {
+	Items      []db.Item
+	ReadState  map[int32]bool
+	NextCursor string
+}
+
+// pageCursor is the position after the last row of a page. Cursors are
+// opaque to clients: base64url-encoded JSON of this struct.
+type pageCursor struct {
+	Sort       string     `json:"s"`
+	Descending bool       `json:"d"`
+	ID         int32      `json:"id"`
+	CreatedAt  *time.Time `json:"c,omitempty"`
+	Title      *string    `json:"t,omitempty"`
+}
+
+func (c pageCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// pageRequest is a validated PageQuery
+type pageRequest struct {
+	sort       string
+	descending bool
+	limit      int
+	after      *pageCursor
+}
+
+func (q PageQuery) resolve() (pageRequest, error) {
+	page := pageRequest{sort: strings.ToLower(strings.TrimSpace(q.Sort))}
+	switch page.sort {
+	case "":
+		page.sort = SortCreatedAt
+	case SortCreatedAt, SortTitle:
+	default:
+		return page, fmt.Errorf("%w: sort must be %q or %q", ErrInvalidListQuery, SortCreatedAt, SortTitle)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(q.Order)) {
+	case "":
+		page.descending = page.sort == SortCreatedAt
+	case SortOrderAsc:
+		page.descending = false
+	case SortOrderDesc:
+		page.descending = true
+	default:
+		return page, fmt.Errorf("%w: order must be %q or %q", ErrInvalidListQuery, SortOrderAsc, SortOrderDesc)
+	}
+
+	page.limit = q.Limit
+	if page.limit <= 0 {
+		page.limit = DefaultPageSize
+	}
+	page.limit = min(page.limit, MaxPageSize)
+
+	if q.Cursor == "" {
+		return page, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return page, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return page, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+	}
+	if cursor.Sort != page.sort || cursor.Descending != page.descending {
+		return page, fmt.Errorf("%w: the cursor belongs to a different sort order", ErrInvalidListQuery)
+	}
+	if (page.sort == SortTitle && cursor.Title == nil) || (page.sort == SortCreatedAt && cursor.CreatedAt == nil) {
+		return page, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+	}
+	page.after = &cursor
+	return page, nil
+}
+
+// next returns the cursor of the page following the row with these sort values
+func (p pageRequest) next(id int32, createdAt *time.Time, title string) string {
+	cursor := pageCursor{Sort: p.sort, Descending: p.descending, ID: id}
+	if p.sort == SortTitle {
+		cursor.Title = &title
+	} else {
+		// Rows without a creation time cannot be passed; they end the list
+		if createdAt == nil {
+			return ""
+		}
+		cursor.CreatedAt = createdAt
+	}
+	return cursor.encode()
+}
+
+// validateCreatedRange rejects creation time ranges that cannot match anything
+func validateCreatedRange(after, before *time.Time) error {
+	if after != nil && before != nil && !after.Before(*before) {
+		return fmt.Errorf("%w: the date range is empty", ErrInvalidListQuery)
+	}
+	return nil
+}
+
+// ListItems returns one page of the items a user can see, or of one of their
+// workspaces' items
+func (s *itemService) ListItems(ctx context.Context, userID int32, query ItemListQuery) (*ItemPage, error) {
+	page, err := query.Page.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCreatedRange(query.CreatedAfter, query.CreatedBefore); err != nil {
+		return nil, err
+	}
+	if query.Status != nil {
+		switch *query.Status {
+		case ProcessingStatusPending, ProcessingStatusProcessing, ProcessingStatusCompleted, ProcessingStatusFailed:
+		default:
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidListQuery, *query.Status)
+		}
+	}
+	if query.WorkspaceID != nil {
+		if _, err := requireWorkspaceRole(ctx, s.querier, userID, *query.WorkspaceID, WorkspaceRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	var tags []string
+	if len(query.Tags) > 0 {
+		if tags, err = filterTags(ctx, s.querier, userID, query.Tags); err != nil {
+			return nil, err
+		}
+	}
+	params := db.ListItemsPageByCreatedDescParams{
+		UserID:        userID,
+		WorkspaceID:   query.WorkspaceID,
+		Tags:          tags,
+		Platform:      query.Platform,
+		Type:          query.Type,
+		IsRead:        query.IsRead,
+		Status:        query.Status,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+		// One extra row tells whether there is a next page
+		Limit: int32(page.limit + 1),
+	}
+	rows, err := s.listItemsPage(ctx, page, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	result := &ItemPage{ReadState: make(map[int32]bool, len(rows))}
+	if len(rows) > page.limit {
+		rows = rows[:page.limit]
+		last := rows[len(rows)-1].Item
+		result.NextCursor = page.next(last.ID, last.CreatedAt, last.Title)
+	}
+	result.Items = make([]db.Item, len(rows))
+	for i, row := range rows {
+		result.Items[i] = row.Item
+		if row.IsRead {
+			result.ReadState[row.Item.ID] = true
+		}
+	}
+	return result, nil
+}
+
+// listItemsPage runs the page query of the requested order with the filters
+// and limit in params. Each order has its own query, with a plain ORDER BY and
+// cursor comparison, so that it can be served from an index.
+func (s *itemService) listItemsPage(ctx context.Context, page pageRequest, params db.ListItemsPageByCreatedDescParams) ([]db.ListItemsPageByCreatedDescRow, error) {
+	if page.sort != SortTitle {
+		if page.after != nil {
+			params.CursorID = &page.after.ID
+			params.CursorCreatedAt = page.after.CreatedAt
+		}
+		if page.descending {
+			return s.querier.ListItemsPageByCreatedDesc(ctx, params)
+		}
+		rows, err := s.querier.ListItemsPageByCreatedAsc(ctx, db.ListItemsPageByCreatedAscParams(params))
+		return itemPageRows(rows, err)
+	}
+
+	titleParams := db.ListItemsPageByTitleAscParams{
+		UserID:        params.UserID,
+		WorkspaceID:   params.WorkspaceID,
+		Tags:          params.Tags,
+		Platform:      params.Platform,
+		Type:          params.Type,
+		IsRead:        params.IsRead,
+		Status:        params.Status,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Limit:         params.Limit,
+	}
+	if page.after != nil {
+		titleParams.CursorID = &page.after.ID
+		titleParams.CursorTitle = page.after.Title
+	}
+	if page.descending {
+		rows, err := s.querier.ListItemsPageByTitleDesc(ctx, db.ListItemsPageByTitleDescParams(titleParams))
+		return itemPageRows(rows, err)
+	}
+	rows, err := s.querier.ListItemsPageByTitleAsc(ctx, titleParams)
+	return itemPageRows(rows, err)
+}
+
+// itemPageRows converts the rows of any ListItemsPageBy query, which all have
+// the same columns
+func itemPageRows[R db.ListItemsPageByCreatedAscRow | db.ListItemsPageByTitleAscRow | db.ListItemsPageByTitleDescRow](rows []R, err error) ([]db.ListItemsPageByCreatedDescRow, error) {
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]db.ListItemsPageByCreatedDescRow, len(rows))
+	for i, row := range rows {
+		converted[i] = db.ListItemsPageByCreatedDescRow(row)
+	}
+	return converted, nil
+}
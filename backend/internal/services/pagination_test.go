@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func TestPageQuery_Resolve(t *testing.T) {
+	page, err := PageQuery{}.resolve()
+	require.NoError(t, err)
+	assert.Equal(t, SortCreatedAt, page.sort)
+	assert.True(t, page.descending, "newest first by default")
+	assert.Equal(t, DefaultPageSize, page.limit)
+	assert.Nil(t, page.after)
+
+	page, err = PageQuery{Sort: "Title", Limit: 1000}.resolve()
+	require.NoError(t, err)
+	assert.Equal(t, SortTitle, page.sort)
+	assert.False(t, page.descending, "titles sort A to Z by default")
+	assert.Equal(t, MaxPageSize, page.limit)
+
+	page, err = PageQuery{Sort: SortCreatedAt, Order: "asc"}.resolve()
+	require.NoError(t, err)
+	assert.False(t, page.descending)
+}
+
+func TestPageQuery_ResolveCursor(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cursor := pageRequest{sort: SortCreatedAt, descending: true}.next(42, &createdAt, "ignored")
+
+	page, err := PageQuery{Cursor: cursor}.resolve()
+	require.NoError(t, err)
+	require.NotNil(t, page.after)
+	assert.Equal(t, int32(42), page.after.ID)
+	require.NotNil(t, page.after.CreatedAt)
+	assert.True(t, createdAt.Equal(*page.after.CreatedAt))
+	assert.Nil(t, page.after.Title)
+
+	titleCursor := pageRequest{sort: SortTitle}.next(7, nil, "Go generics")
+	page, err = PageQuery{Sort: SortTitle, Cursor: titleCursor}.resolve()
+	require.NoError(t, err)
+	require.NotNil(t, page.after)
+	assert.Equal(t, "Go generics", *page.after.Title)
+}
+
+func TestPageQuery_ResolveInvalid(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newestFirst := pageRequest{sort: SortCreatedAt, descending: true}.next(42, &createdAt, "")
+
+	tests := []struct {
+		name  string
+		query PageQuery
+	}{
+		{"unknown sort", PageQuery{Sort: "rating"}},
+		{"unknown order", PageQuery{Order: "sideways"}},
+		{"malformed cursor", PageQuery{Cursor: "not a cursor!"}},
+		{"cursor that is not JSON", PageQuery{Cursor: "bm90IGpzb24"}},
+		{"cursor of another order", PageQuery{Order: SortOrderAsc, Cursor: newestFirst}},
+		{"cursor of another sort", PageQuery{Sort: SortTitle, Order: SortOrderDesc, Cursor: newestFirst}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.query.resolve()
+			assert.True(t, errors.Is(err, ErrInvalidListQuery), "got %v", err)
+		})
+	}
+}
+
+func TestListItems(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	ctx := context.Background()
+	platform := "web"
+	status := ProcessingStatusCompleted
+	first := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	third := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	mockQuerier.On("ResolveTagAliases", ctx, db.ResolveTagAliasesParams{
+		UserID:  1,
+		Aliases: []string{"databases", "db", "machine-learning"},
+	}).Return([]db.ResolveTagAliasesRow{{Alias: "db", Tag: "databases"}}, nil)
+	mockQuerier.On("ListItemsPageByCreatedDesc", ctx, db.ListItemsPageByCreatedDescParams{
+		UserID:   1,
+		Tags:     []string{"databases", "machine-learning"},
+		Platform: &platform,
+		Status:   &status,
+		Limit:    3,
+	}).Return([]db.ListItemsPageByCreatedDescRow{
+		{Item: db.Item{ID: 9, CreatedAt: &first}, IsRead: true},
+		{Item: db.Item{ID: 8, CreatedAt: &second}},
+		{Item: db.Item{ID: 7, CreatedAt: &third}},
+	}, nil)
+
+	page, err := service.ListItems(ctx, 1, ItemListQuery{
+		Tags:     []string{" databases", "Databases", "db", "Machine_Learning", " "},
+		Platform: &platform,
+		Status:   &status,
+		Page:     PageQuery{Limit: 2},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2, "the extra row only signals a next page")
+	assert.Equal(t, int32(9), page.Items[0].ID)
+	assert.Equal(t, map[int32]bool{9: true}, page.ReadState)
+	require.NotEmpty(t, page.NextCursor)
+
+	next, err := PageQuery{Cursor: page.NextCursor}.resolve()
+	require.NoError(t, err)
+	assert.Equal(t, int32(8), next.after.ID, "the next page starts after the last item returned")
+	assert.True(t, second.Equal(*next.after.CreatedAt))
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestListItems_LastPage(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	ctx := context.Background()
+	cursorTitle := "B"
+	cursor := pageRequest{sort: SortTitle}.next(5, nil, cursorTitle)
+	cursorID := int32(5)
+
+	mockQuerier.On("ListItemsPageByTitleAsc", ctx, db.ListItemsPageByTitleAscParams{
+		UserID:      1,
+		CursorID:    &cursorID,
+		CursorTitle: &cursorTitle,
+		Limit:       DefaultPageSize + 1,
+	}).Return([]db.ListItemsPageByTitleAscRow{{Item: db.Item{ID: 6, Title: "C"}}}, nil)
+
+	page, err := service.ListItems(ctx, 1, ItemListQuery{Page: PageQuery{Sort: SortTitle, Cursor: cursor}})
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestListItems_QueryPerOrder(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	cursor := pageRequest{sort: SortCreatedAt}.next(4, &createdAt, "")
+	cursorID := int32(4)
+
+	mockQuerier.On("ListItemsPageByCreatedAsc", ctx, db.ListItemsPageByCreatedAscParams{
+		UserID:          1,
+		CursorID:        &cursorID,
+		CursorCreatedAt: &createdAt,
+		Limit:           DefaultPageSize + 1,
+	}).Return([]db.ListItemsPageByCreatedAscRow{{Item: db.Item{ID: 5}, IsRead: true}}, nil)
+	mockQuerier.On("ListItemsPageByTitleDesc", ctx, db.ListItemsPageByTitleDescParams{
+		UserID: 1,
+		Limit:  DefaultPageSize + 1,
+	}).Return([]db.ListItemsPageByTitleDescRow{{Item: db.Item{ID: 3, Title: "Z"}}}, nil)
+
+	page, err := service.ListItems(ctx, 1, ItemListQuery{Page: PageQuery{Order: SortOrderAsc, Cursor: cursor}})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, map[int32]bool{5: true}, page.ReadState)
+
+	page, err = service.ListItems(ctx, 1, ItemListQuery{Page: PageQuery{Sort: SortTitle, Order: SortOrderDesc}})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Z", page.Items[0].Title)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestListItems_Invalid(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	ctx := context.Background()
+	status := "archived"
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.ListItems(ctx, 1, ItemListQuery{Status: &status})
+	assert.True(t, errors.Is(err, ErrInvalidListQuery))
+
+	_, err = service.ListItems(ctx, 1, ItemListQuery{CreatedAfter: &day, CreatedBefore: &day})
+	assert.True(t, errors.Is(err, ErrInvalidListQuery))
+
+	_, err = service.ListItems(ctx, 1, ItemListQuery{Page: PageQuery{Sort: "rating"}})
+	assert.True(t, errors.Is(err, ErrInvalidListQuery))
+
+	mockQuerier.AssertNotCalled(t, "ListItemsPageByCreatedDesc", mock.Anything, mock.Anything)
+}
+
+func TestListItems_WorkspaceForbidden(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	ctx := context.Background()
+	workspaceID := int32(3)
+
+	mockQuerier.On("GetWorkspaceMember", ctx, db.GetWorkspaceMemberParams{WorkspaceID: 3, UserID: 1}).
+		Return(db.WorkspaceMember{}, pgx.ErrNoRows)
+
+	_, err := service.ListItems(ctx, 1, ItemListQuery{WorkspaceID: &workspaceID})
+
+	assert.True(t, errors.Is(err, ErrWorkspaceNotFound), "got %v", err)
+	mockQuerier.AssertNotCalled(t, "ListItemsPageByCreatedDesc", mock.Anything, mock.Anything)
+}
+
+func TestListPodcasts(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPodcastService(mockQuerier, nil, nil, nil, PodcastConfig{})
+	ctx := context.Background()
+	userID := int32(1)
+	status := PodcastStatusCompleted
+	statusParam := string(status)
+	after := time.Date(2024, 5, 1, 0, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	mockQuerier.On("ListPodcastsPageByTitleAsc", ctx, db.ListPodcastsPageByTitleAscParams{
+		UserID:       &userID,
+		Status:       &statusParam,
+		CreatedAfter: pgtype.Timestamp{Time: after.UTC(), Valid: true},
+		Limit:        2,
+	}).Return([]db.Podcast{
+		{ID: 2, Title: "A"},
+		{ID: 1, Title: "B"},
+	}, nil)
+
+	page, err := service.ListPodcasts(ctx, userID, PodcastListQuery{
+		Status:       &status,
+		CreatedAfter: &after,
+		Page:         PageQuery{Sort: SortTitle, Limit: 1},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, page.Podcasts, 1)
+	assert.Equal(t, int32(2), page.Podcasts[0].ID)
+
+	next, err := PageQuery{Sort: SortTitle, Cursor: page.NextCursor}.resolve()
+	require.NoError(t, err)
+	assert.Equal(t, "A", *next.after.Title)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestListPodcasts_InvalidStatus(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPodcastService(mockQuerier, nil, nil, nil, PodcastConfig{})
+	status := PodcastStatus("queued")
+
+	_, err := service.ListPodcasts(context.Background(), 1, PodcastListQuery{Status: &status})
+
+	assert.True(t, errors.Is(err, ErrInvalidListQuery))
+	mockQuerier.AssertNotCalled(t, "ListPodcastsPageByCreatedDesc", mock.Anything, mock.Anything)
+}
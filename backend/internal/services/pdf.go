@@ -0,0 +1,336 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ErrInvalidPDF is returned for files that cannot be read as PDF documents
+var ErrInvalidPDF = errors.New("invalid PDF document")
+
+const (
+	// maxPDFPages bounds extraction time; the opening pages carry what a summary needs
+	maxPDFPages = 100
+	// pdfLineTolerance is the share of the font size two glyphs' baselines may
+	// differ by and still be on one line
+	pdfLineTolerance = 0.5
+	// pdfWordGap is the share of the font size of horizontal space that separates words
+	pdfWordGap = 0.15
+	// pdfParagraphGap is the line spacing, relative to the font size, that starts a new paragraph
+	pdfParagraphGap = 1.6
+)
+
+var (
+	// placeholderPDFTitles are document titles left behind by authoring tools
+	placeholderPDFTitles = regexp.MustCompile(`(?i)^(untitled|microsoft (word|powerpoint) - .*|.*\.(docx?|pdf|tex|dvi|odt|pptx?))$`)
+	// authorMarkers are footnote and affiliation marks that follow author names
+	authorMarkers       = regexp.MustCompile(`[0-9\x{00B9}\x{00B2}\x{00B3}\x{2070}-\x{2079}*†‡§¶∗]+`)
+	authorSeparators    = regexp.MustCompile(`\s*(,|;|&|\band\b)\s*`)
+	semicolonSeparators = regexp.MustCompile(`\s*;\s*`)
+	abstractPrefix      = regexp.MustCompile(`(?i)^abstract[\s.:—-]*`)
+)
+
+// pdfLine is a line of text with the size of its largest glyph
+type pdfLine struct {
+	Text     string
+	X, Y     float64
+	FontSize float64
+}
+
+// isPDF reports whether a fetched page is a PDF document
+func isPDF(page *FetchedPage) bool {
+	if mediaType, _, err := mime.ParseMediaType(page.ContentType); err == nil && mediaType == "application/pdf" {
+		return true
+	}
+	return bytes.HasPrefix(page.Body, []byte("%PDF-"))
+}
+
+// ParsePDF extracts the text of a PDF as Markdown paragraphs, together with
+// its title and authors from the document info or, failing that, the first page
+func ParsePDF(data []byte) (result *ScrapeResult, err error) {
+	// The PDF reader panics on malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%w: %v", ErrInvalidPDF, r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+
+	var pages [][]pdfLine
+	for i := 1; i <= reader.NumPage() && i <= maxPDFPages; i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		pages = append(pages, pdfPageLines(page.Content().Text))
+	}
+
+	var paragraphs []string
+	for _, lines := range pages {
+		paragraphs = append(paragraphs, pdfParagraphs(lines)...)
+	}
+	if len(paragraphs) == 0 {
+		// Scanned documents have no text layer
+		return nil, fmt.Errorf("%w: no extractable text", ErrInvalidPDF)
+	}
+
+	info := reader.Trailer().Key("Info")
+	title := strings.TrimSpace(info.Key("Title").Text())
+	if placeholderPDFTitles.MatchString(title) {
+		title = ""
+	}
+	authors := splitPDFAuthors(info.Key("Author").Text())
+
+	if (title == "" || len(authors) == 0) && len(pages) > 0 {
+		pageTitle, pageAuthors := pdfTitlePage(pages[0])
+		if title == "" {
+			title = pageTitle
+		}
+		if len(authors) == 0 {
+			authors = pageAuthors
+		}
+	}
+
+	content := strings.Join(paragraphs, "\n\n")
+	if title != "" {
+		if paragraphs[0] == title {
+			paragraphs = paragraphs[1:]
+		}
+		content = "# " + title + "\n\n" + strings.Join(paragraphs, "\n\n")
+	}
+
+	return &ScrapeResult{
+		Title:       title,
+		Content:     content,
+		Excerpt:     pdfExcerpt(paragraphs),
+		Authors:     authors,
+		PublishedAt: parsePDFDate(info.Key("CreationDate").Text()),
+	}, nil
+}
+
+// pdfExcerpt returns the abstract when the document has one, otherwise the
+// first paragraph long enough to be prose
+func pdfExcerpt(paragraphs []string) string {
+	for i, paragraph := range paragraphs {
+		if strings.EqualFold(paragraph, "abstract") && i+1 < len(paragraphs) {
+			return truncateText(paragraphs[i+1], maxExcerptLength)
+		}
+		if abstract := abstractPrefix.ReplaceAllString(paragraph, ""); abstract != paragraph && len(abstract) > minParagraphLength {
+			return truncateText(abstract, maxExcerptLength)
+		}
+	}
+	for _, paragraph := range paragraphs {
+		if len(paragraph) >= 4*minParagraphLength {
+			return truncateText(paragraph, maxExcerptLength)
+		}
+	}
+	if len(paragraphs) > 0 {
+		return truncateText(paragraphs[0], maxExcerptLength)
+	}
+	return ""
+}
+
+// pdfPageLines joins positioned glyphs into lines in content stream order,
+// which follows the reading order in the PDFs authoring tools produce
+func pdfPageLines(glyphs []pdf.Text) []pdfLine {
+	var lines []pdfLine
+	var current strings.Builder
+	var line pdfLine
+	var lastEnd float64
+	started := false
+
+	flush := func() {
+		if text := normalizeSpace(current.String()); text != "" {
+			line.Text = text
+			lines = append(lines, line)
+		}
+		current.Reset()
+		started = false
+	}
+
+	for _, glyph := range glyphs {
+		if glyph.S == "" || glyph.S == "\n" {
+			continue
+		}
+		size := math.Max(glyph.FontSize, 1)
+		width := glyph.W
+		if width <= 0 {
+			// Fonts without a widths table; assume an average glyph
+			width = size / 2
+		}
+
+		if started && (math.Abs(glyph.Y-line.Y) > size*pdfLineTolerance || glyph.X < lastEnd-size) {
+			flush()
+		}
+		if !started {
+			line = pdfLine{X: glyph.X, Y: glyph.Y}
+			started = true
+		} else if glyph.X-lastEnd > size*pdfWordGap {
+			current.WriteByte(' ')
+		}
+		current.WriteString(glyph.S)
+		line.FontSize = math.Max(line.FontSize, glyph.FontSize)
+		lastEnd = glyph.X + width
+	}
+	flush()
+	return lines
+}
+
+// pdfParagraphs groups lines into paragraphs on wide line spacing or a change
+// of font size, dropping page numbers and joining hyphenated words
+func pdfParagraphs(lines []pdfLine) []string {
+	var paragraphs []string
+	var current string
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			gap := prev.Y - line.Y
+			if gap <= 0 || gap > prev.FontSize*pdfParagraphGap || math.Abs(prev.FontSize-line.FontSize) > 1 {
+				paragraphs = appendPDFParagraph(paragraphs, current)
+				current = ""
+			}
+		}
+		current = joinPDFLines(current, line.Text)
+	}
+	return appendPDFParagraph(paragraphs, current)
+}
+
+func appendPDFParagraph(paragraphs []string, paragraph string) []string {
+	paragraph = strings.TrimSpace(paragraph)
+	if paragraph == "" || strings.IndexFunc(paragraph, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return paragraphs
+	}
+	return append(paragraphs, paragraph)
+}
+
+// joinPDFLines appends a line to a paragraph, rejoining words hyphenated across the break
+func joinPDFLines(paragraph, line string) string {
+	if paragraph == "" {
+		return line
+	}
+	if strings.HasSuffix(paragraph, "-") && len(paragraph) > 1 && unicode.IsLetter(rune(paragraph[len(paragraph)-2])) {
+		if first := []rune(line); len(first) > 0 && unicode.IsLower(first[0]) {
+			return paragraph[:len(paragraph)-1] + line
+		}
+	}
+	return paragraph + " " + line
+}
+
+// pdfTitlePage guesses the title from the largest text near the top of the
+// first page, and the authors from the line below it when it reads like a
+// list of names
+func pdfTitlePage(lines []pdfLine) (string, []string) {
+	if len(lines) > 20 {
+		lines = lines[:20]
+	}
+	if len(lines) < 2 {
+		return "", nil
+	}
+
+	sizes := make([]float64, len(lines))
+	for i, line := range lines {
+		sizes[i] = line.FontSize
+	}
+	sort.Float64s(sizes)
+	median, largest := sizes[len(sizes)/2], sizes[len(sizes)-1]
+	if largest < median*1.2 {
+		return "", nil
+	}
+
+	// The title is the first run of lines set in the largest size
+	var titleParts []string
+	end := 0
+	for i, line := range lines {
+		if math.Abs(line.FontSize-largest) > 0.5 {
+			if len(titleParts) > 0 {
+				break
+			}
+			continue
+		}
+		titleParts = append(titleParts, line.Text)
+		end = i + 1
+	}
+	title := normalizeSpace(strings.Join(titleParts, " "))
+
+	var authors []string
+	if end < len(lines) {
+		authors = pdfAuthorLine(lines[end].Text)
+	}
+	return title, authors
+}
+
+// pdfAuthorLine returns the names in a line such as "Ada Lovelace¹, Alan
+// Turing and Grace Hopper*", or nil when the line reads like anything else
+func pdfAuthorLine(text string) []string {
+	parts := authorSeparators.Split(authorMarkers.ReplaceAllString(text, ""), -1)
+	var names []string
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		words := strings.Fields(part)
+		if len(words) < 2 || len(words) > 4 {
+			return nil
+		}
+		for _, word := range words {
+			if first := []rune(word); !unicode.IsUpper(first[0]) {
+				return nil
+			}
+		}
+		names = append(names, part)
+	}
+	return normalizeAuthors(names)
+}
+
+// splitPDFAuthors splits the Author field of the document info, which tools
+// fill with semicolon or comma separated names
+func splitPDFAuthors(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	separator := semicolonSeparators
+	if !strings.Contains(value, ";") {
+		separator = authorSeparators
+	}
+	return normalizeAuthors(separator.Split(value, -1))
+}
+
+// parsePDFDate parses PDF dates such as D:20240305101500+01'00', returning nil when invalid
+func parsePDFDate(value string) *time.Time {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "D:")
+	digits := len(value) - len(strings.TrimLeft(value, "0123456789"))
+	layouts := map[int]string{4: "2006", 6: "200601", 8: "20060102", 10: "2006010215", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[digits]
+	if !ok {
+		return nil
+	}
+	parsed, err := time.Parse(layout, value[:digits])
+	if err != nil {
+		return nil
+	}
+
+	// The offset is written as Z, or +HH'mm' / -HH'mm'
+	if zone := strings.ReplaceAll(value[digits:], "'", ""); len(zone) >= 3 && (zone[0] == '+' || zone[0] == '-') {
+		if offset, err := time.Parse("-0700", (zone + "00")[:5]); err == nil {
+			parsed = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), parsed.Hour(), parsed.Minute(), parsed.Second(), 0, offset.Location())
+		}
+	}
+	parsed = parsed.UTC()
+	return &parsed
+}
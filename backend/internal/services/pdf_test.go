@@ -0,0 +1,104 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePDF_Paper(t *testing.T) {
+	data, err := os.ReadFile("testdata/pdf_paper.pdf")
+	require.NoError(t, err)
+
+	result, err := ParsePDF(data)
+
+	require.NoError(t, err)
+	// The document info only has a placeholder title, so it comes from the first page
+	assert.Equal(t, "Sparse Attention for Long Documents", result.Title)
+	assert.Equal(t, []string{"Maria Lopez", "Kenji Watanabe", "Priya Natarajan"}, result.Authors)
+	assert.Nil(t, result.PublishedAt)
+	assert.Equal(t, "Transformers struggle with long inputs because attention cost grows quadratically with sequence length. We propose a sparse attention pattern that combines local windows with a small set of global tokens, reducing memory use by an order of magnitude.", result.Excerpt)
+
+	assert.True(t, strings.HasPrefix(result.Content, "# Sparse Attention for Long Documents\n\nMaria Lopez"))
+	assert.Equal(t, 1, strings.Count(result.Content, "Sparse Attention for Long Documents"), "the title is not repeated")
+	// Word spacing written as glyph offsets, and words hyphenated across lines
+	assert.Contains(t, result.Content, "Transformers struggle with long inputs because attention cost grows quadratically")
+	assert.Contains(t, result.Content, "a sparse attention pattern")
+	assert.Contains(t, result.Content, "\n\n1 Introduction\n\n")
+	assert.Contains(t, result.Content, "Our experiments on three summarization benchmarks show that sparse attention matches dense attention at a fraction of the cost.")
+	assert.NotContains(t, result.Content, "\n\n2", "page numbers are dropped")
+}
+
+func TestParsePDF_DocumentInfo(t *testing.T) {
+	data, err := os.ReadFile("testdata/pdf_report.pdf")
+	require.NoError(t, err)
+
+	result, err := ParsePDF(data)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Infrastructure Cost Report", result.Title)
+	assert.Equal(t, []string{"Alex Chen", "Sam Rivera"}, result.Authors)
+	require.NotNil(t, result.PublishedAt)
+	assert.Equal(t, time.Date(2024, 3, 5, 10, 15, 0, 0, time.UTC), *result.PublishedAt)
+	assert.Equal(t, "# Infrastructure Cost Report\n\nQuarterly infrastructure costs fell by twelve percent after the migration to reserved instances.", result.Content)
+}
+
+func TestParsePDF_Invalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"not a PDF": []byte("<html><body>Not a PDF</body></html>"),
+		"truncated": []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParsePDF(data)
+
+			assert.ErrorIs(t, err, ErrInvalidPDF)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestScrapingServiceScrape_PDF(t *testing.T) {
+	data, err := os.ReadFile("testdata/pdf_report.pdf")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	result, err := NewScraper().Scrape(server.URL + "/reports/q1")
+
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/reports/q1", result.URL)
+	assert.Equal(t, "Infrastructure Cost Report", result.Title)
+	assert.Contains(t, result.Content, "Quarterly infrastructure costs fell")
+}
+
+func TestPDFAuthorLine(t *testing.T) {
+	assert.Equal(t, []string{"Ada Lovelace", "Alan Turing", "Grace Hopper"}, pdfAuthorLine("Ada Lovelace¹, Alan Turing² and Grace Hopper*"))
+	assert.Nil(t, pdfAuthorLine("Department of Computer Science, Example University"))
+	assert.Nil(t, pdfAuthorLine("Abstract"))
+}
+
+func TestParsePDFDate(t *testing.T) {
+	tests := map[string]string{
+		"D:20240305101500Z":       "2024-03-05T10:15:00Z",
+		"D:20240305101500+01'00'": "2024-03-05T09:15:00Z",
+		"D:20240305101500-05'30'": "2024-03-05T15:45:00Z",
+		"D:20240305":              "2024-03-05T00:00:00Z",
+	}
+	for value, expected := range tests {
+		parsed := parsePDFDate(value)
+		require.NotNil(t, parsed, value)
+		assert.Equal(t, expected, parsed.Format(time.RFC3339), value)
+	}
+	assert.Nil(t, parsePDFDate(""))
+	assert.Nil(t, parsePDFDate("yesterday"))
+}
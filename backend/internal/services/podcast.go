@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -11,9 +12,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/yamirghofran/briefbot/internal/db"
 )
 
+// ErrPodcastNotFound is returned when a podcast does not exist or belongs to another user
+var ErrPodcastNotFound = errors.New("podcast not found")
+
 // DialogueAudioResult represents the result of generating audio for a single dialogue
 type DialogueAudioResult struct {
 	Index    int    // Dialogue index
@@ -43,17 +49,18 @@ type PodcastService interface {
 	GeneratePodcastAudio(ctx context.Context, podcastID int32) error
 	ProcessPodcast(ctx context.Context, podcastID int32) error
 
-	// CRUD operations
-	GetPodcast(ctx context.Context, podcastID int32) (*db.Podcast, error)
+	// CRUD operations, scoped to the owning user
+	GetPodcast(ctx context.Context, userID int32, podcastID int32) (*db.Podcast, error)
 	GetPodcastsByUser(ctx context.Context, userID int32) ([]db.Podcast, error)
-	GetPodcastsByStatus(ctx context.Context, status PodcastStatus) ([]db.Podcast, error)
-	UpdatePodcast(ctx context.Context, podcastID int32, title string, description string) error
-	DeletePodcast(ctx context.Context, podcastID int32) error
+	GetPodcastsByStatus(ctx context.Context, userID int32, status PodcastStatus) ([]db.Podcast, error)
+	ListPodcasts(ctx context.Context, userID int32, query PodcastListQuery) (*PodcastPage, error)
+	UpdatePodcast(ctx context.Context, userID int32, podcastID int32, title string, description string) error
+	DeletePodcast(ctx context.Context, userID int32, podcastID int32) error
 
-	// Item management
-	AddItemToPodcast(ctx context.Context, podcastID int32, itemID int32, order int) error
-	RemoveItemFromPodcast(ctx context.Context, podcastID int32, itemID int32) error
-	GetPodcastItems(ctx context.Context, podcastID int32) ([]db.GetPodcastItemsRow, error)
+	// Item management, scoped to the owning user
+	AddItemToPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32, order int) error
+	RemoveItemFromPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32) error
+	GetPodcastItems(ctx context.Context, userID int32, podcastID int32) ([]db.GetPodcastItemsRow, error)
 
 	// Status management
 	UpdatePodcastStatus(ctx context.Context, podcastID int32, status PodcastStatus) error
@@ -63,13 +70,19 @@ type PodcastService interface {
 	// Atomic podcast acquisition with locking - prevents multiple workers from processing the same podcast
 	AcquirePendingPodcasts(ctx context.Context, limit int32) ([]db.Podcast, error)
 
-	// Audio management
-	GetPodcastAudio(ctx context.Context, podcastID int32) ([]byte, error)
-	HasPodcastAudio(ctx context.Context, podcastID int32) (bool, error)
-	GeneratePodcastUploadURL(ctx context.Context, podcastID int32) (*UploadURLResponse, error)
+	// Audio management, scoped to the owning user
+	GetPodcastAudio(ctx context.Context, userID int32, podcastID int32) ([]byte, error)
+	HasPodcastAudio(ctx context.Context, userID int32, podcastID int32) (bool, error)
+	GeneratePodcastUploadURL(ctx context.Context, userID int32, podcastID int32) (*UploadURLResponse, error)
 
 	// SSE management
 	SetSSEManager(sseManager *SSEManager)
+
+	// Per-user voice and speed preferences
+	SetPreferencesService(preferencesService PreferencesService)
+
+	// Daily podcast minutes quota
+	SetQuotaService(quotaService QuotaService)
 }
 
 // podcastService implements PodcastService
@@ -80,6 +93,9 @@ type podcastService struct {
 	r2Service     *R2Service
 	config        PodcastConfig
 	sseManager    *SSEManager
+
+	preferencesService PreferencesService
+	quotaService       QuotaService
 }
 
 // PodcastConfig holds configuration for podcast service
@@ -103,8 +119,8 @@ func DefaultPodcastConfig() PodcastConfig {
 		MaxItemsPerPodcast: 10,
 		MaxConcurrentAudio: 5, // Default 5 concurrent audio requests
 		VoiceMapping: map[string]VoiceEnum{
-			"heart": VoiceAfHeart,
-			"adam":  VoiceAmAdam,
+			PodcastHostSpeaker:   VoiceAfHeart,
+			PodcastCohostSpeaker: VoiceAmAdam,
 		},
 	}
 }
@@ -126,6 +142,46 @@ func (s *podcastService) SetSSEManager(sseManager *SSEManager) {
 	s.sseManager = sseManager
 }
 
+// SetPreferencesService makes audio generation use each owner's preferred voices and speed
+func (s *podcastService) SetPreferencesService(preferencesService PreferencesService) {
+	s.preferencesService = preferencesService
+}
+
+// SetQuotaService makes podcast creation respect the daily podcast minutes quota
+func (s *podcastService) SetQuotaService(quotaService QuotaService) {
+	s.quotaService = quotaService
+}
+
+// speechSettings holds the voices and speed used to voice one podcast
+type speechSettings struct {
+	voices map[string]VoiceEnum
+	speed  float64
+}
+
+// speechSettingsFor resolves the owner's preferred voices and speed, falling back to the config
+func (s *podcastService) speechSettingsFor(ctx context.Context, userID *int32) speechSettings {
+	settings := speechSettings{
+		voices: s.config.VoiceMapping,
+		speed:  s.config.DefaultSpeed,
+	}
+	if s.preferencesService == nil || userID == nil {
+		return settings
+	}
+
+	prefs, err := s.preferencesService.GetPreferences(ctx, *userID)
+	if err != nil {
+		log.Printf("Failed to load preferences for user %d, using default voices: %v", *userID, err)
+		return settings
+	}
+
+	settings.voices = map[string]VoiceEnum{
+		PodcastHostSpeaker:   VoiceEnum(prefs.HostVoice),
+		PodcastCohostSpeaker: VoiceEnum(prefs.CohostVoice),
+	}
+	settings.speed = prefs.SpeechSpeed
+	return settings
+}
+
 // CreatePodcastFromItems creates a podcast from multiple items
 func (s *podcastService) CreatePodcastFromItems(ctx context.Context, userID int32, title string, description string, itemIDs []int32) (*db.Podcast, error) {
 	if len(itemIDs) == 0 {
@@ -136,6 +192,16 @@ func (s *podcastService) CreatePodcastFromItems(ctx context.Context, userID int3
 		return nil, fmt.Errorf("too many items: maximum %d items per podcast", s.config.MaxItemsPerPodcast)
 	}
 
+	if err := s.authorizeItems(ctx, userID, itemIDs); err != nil {
+		return nil, err
+	}
+
+	if s.quotaService != nil {
+		if err := s.quotaService.CheckPodcastMinutes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create the podcast
 	params := db.CreatePodcastParams{
 		UserID:      &userID,
@@ -175,8 +241,14 @@ func (s *podcastService) CreatePodcastFromItems(ctx context.Context, userID int3
 // CreatePodcastFromSingleItem creates a podcast from a single item with auto-generated title
 func (s *podcastService) CreatePodcastFromSingleItem(ctx context.Context, userID int32, itemID int32) (*db.Podcast, error) {
 	// Get the item to create a meaningful title
-	item, err := s.querier.GetItem(ctx, itemID)
+	item, err := s.querier.GetItemVisibleToUser(ctx, db.GetItemVisibleToUserParams{
+		ID:     itemID,
+		UserID: &userID,
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemNotFound
+		}
 		return nil, fmt.Errorf("failed to get item: %w", err)
 	}
 
@@ -294,6 +366,8 @@ func (s *podcastService) GeneratePodcastAudio(ctx context.Context, podcastID int
 		return fmt.Errorf("no dialogues to convert to audio")
 	}
 
+	settings := s.speechSettingsFor(ctx, podcast.UserID)
+
 	// Create temp directory for audio files
 	tempDir := filepath.Join(s.config.TempDir, fmt.Sprintf("podcast_%d", podcastID))
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
@@ -328,7 +402,7 @@ func (s *podcastService) GeneratePodcastAudio(ctx context.Context, podcastID int
 			defer func() { <-semaphore }() // Release semaphore when done
 
 			// Generate audio for this dialogue
-			audioFile, err := s.convertDialogueToAudio(ctx, dlg, idx, len(dialogues), tempDir)
+			audioFile, err := s.convertDialogueToAudio(ctx, dlg, idx, len(dialogues), tempDir, settings)
 			if err != nil {
 				resultChan <- DialogueAudioResult{
 					Index: idx,
@@ -456,6 +530,13 @@ func (s *podcastService) GeneratePodcastAudio(ctx context.Context, podcastID int
 		return fmt.Errorf("failed to update podcast with audio: %w", err)
 	}
 
+	// Count the generated audio against the owner's podcast minutes
+	if s.quotaService != nil && podcast.UserID != nil {
+		if err := s.quotaService.RecordPodcastSeconds(ctx, *podcast.UserID, duration); err != nil {
+			log.Printf("Warning: Failed to record podcast usage for podcast %d: %v", podcastID, err)
+		}
+	}
+
 	// Clean up temp directory only after everything is successfully completed
 	if err := os.RemoveAll(tempDir); err != nil {
 		log.Printf("Warning: Failed to clean up temp directory %s: %v", tempDir, err)
@@ -465,9 +546,9 @@ func (s *podcastService) GeneratePodcastAudio(ctx context.Context, podcastID int
 }
 
 // convertDialogueToAudio converts a single dialogue to audio
-func (s *podcastService) convertDialogueToAudio(ctx context.Context, dialogue Dialogue, index int, total int, tempDir string) (string, error) {
+func (s *podcastService) convertDialogueToAudio(ctx context.Context, dialogue Dialogue, index int, total int, tempDir string, settings speechSettings) (string, error) {
 	// Map speaker to voice
-	voice, ok := s.config.VoiceMapping[dialogue.Speaker]
+	voice, ok := settings.voices[dialogue.Speaker]
 	if !ok {
 		return "", fmt.Errorf("unknown speaker: %s", dialogue.Speaker)
 	}
@@ -481,7 +562,7 @@ func (s *podcastService) convertDialogueToAudio(ctx context.Context, dialogue Di
 	}
 
 	// Generate audio using speech service
-	audioFile, err := s.speechService.TextToSpeech(dialogue.Content, voice, s.config.DefaultSpeed, dialogueInfo)
+	audioFile, err := s.speechService.TextToSpeech(dialogue.Content, voice, settings.speed, dialogueInfo)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate speech: %w", err)
 	}
@@ -524,11 +605,11 @@ func (s *podcastService) convertDialogueToAudio(ctx context.Context, dialogue Di
 }
 
 // generateDialogueAudioConcurrent generates audio for a single dialogue concurrently
-func (s *podcastService) generateDialogueAudioConcurrent(ctx context.Context, dialogue Dialogue, index int, total int, tempDir string, resultChan chan<- DialogueAudioResult, wg *sync.WaitGroup) {
+func (s *podcastService) generateDialogueAudioConcurrent(ctx context.Context, dialogue Dialogue, index int, total int, tempDir string, settings speechSettings, resultChan chan<- DialogueAudioResult, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	// Generate audio using the existing method
-	audioFile, err := s.convertDialogueToAudio(ctx, dialogue, index, total, tempDir)
+	audioFile, err := s.convertDialogueToAudio(ctx, dialogue, index, total, tempDir, settings)
 	if err != nil {
 		resultChan <- DialogueAudioResult{
 			Index: index,
@@ -611,6 +692,17 @@ func (s *podcastService) ProcessPodcast(ctx context.Context, podcastID int32) er
 		return fmt.Errorf("failed to get podcast: %w", err)
 	}
 
+	// Creation only sees the minutes generated so far, so podcasts queued in a
+	// burst are checked again here, one at a time, before any LLM or TTS call
+	if s.quotaService != nil && podcast.UserID != nil {
+		if err := s.quotaService.CheckPodcastMinutes(ctx, *podcast.UserID); err != nil {
+			if s.sseManager != nil {
+				s.sseManager.NotifyPodcastUpdate(*podcast.UserID, podcastID, string(PodcastStatusFailed), "failed")
+			}
+			return err
+		}
+	}
+
 	// Generate script first
 	if err := s.GeneratePodcastScript(ctx, podcastID); err != nil {
 		// Notify failure via SSE
@@ -637,15 +729,53 @@ func (s *podcastService) ProcessPodcast(ctx context.Context, podcastID int32) er
 	return nil
 }
 
-// GetPodcast retrieves a podcast by ID
-func (s *podcastService) GetPodcast(ctx context.Context, podcastID int32) (*db.Podcast, error) {
-	podcast, err := s.querier.GetPodcast(ctx, podcastID)
+// authorizePodcast loads a podcast only if it belongs to the given user,
+// returning ErrPodcastNotFound otherwise
+func (s *podcastService) authorizePodcast(ctx context.Context, userID int32, podcastID int32) (*db.Podcast, error) {
+	podcast, err := s.querier.GetPodcastForUser(ctx, db.GetPodcastForUserParams{
+		ID:     podcastID,
+		UserID: &userID,
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPodcastNotFound
+		}
 		return nil, fmt.Errorf("failed to get podcast: %w", err)
 	}
 	return &podcast, nil
 }
 
+// authorizeItems verifies that every item ID is visible to the given user,
+// either their own or shared with one of their workspaces, returning
+// ErrItemNotFound if any of them is not
+func (s *podcastService) authorizeItems(ctx context.Context, userID int32, itemIDs []int32) error {
+	unique := make([]int32, 0, len(itemIDs))
+	seen := make(map[int32]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	count, err := s.querier.CountItemsVisibleToUser(ctx, db.CountItemsVisibleToUserParams{
+		ItemIds: unique,
+		UserID:  &userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify item access: %w", err)
+	}
+	if count != int64(len(unique)) {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+// GetPodcast retrieves a podcast owned by the user
+func (s *podcastService) GetPodcast(ctx context.Context, userID int32, podcastID int32) (*db.Podcast, error) {
+	return s.authorizePodcast(ctx, userID, podcastID)
+}
+
 // GetPodcastsByUser retrieves all podcasts for a user
 func (s *podcastService) GetPodcastsByUser(ctx context.Context, userID int32) ([]db.Podcast, error) {
 	userIDPtr := int32(userID)
@@ -656,9 +786,111 @@ func (s *podcastService) GetPodcastsByUser(ctx context.Context, userID int32) ([
 	return podcasts, nil
 }
 
-// GetPodcastsByStatus retrieves podcasts by status
-func (s *podcastService) GetPodcastsByStatus(ctx context.Context, status PodcastStatus) ([]db.Podcast, error) {
-	podcasts, err := s.querier.GetPodcastsByStatus(ctx, string(status))
+// PodcastListQuery filters a user's podcasts. Nil filters match every podcast.
+type PodcastListQuery struct {
+	Status        *PodcastStatus
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Page          PageQuery
+}
+
+// PodcastPage is one page of podcasts. NextCursor is empty on the last page.
+type PodcastPage struct {
+	Podcasts   []db.Podcast
+	NextCursor string
+}
+
+// ListPodcasts retrieves one page of the user's podcasts
+func (s *podcastService) ListPodcasts(ctx context.Context, userID int32, query PodcastListQuery) (*PodcastPage, error) {
+	page, err := query.Page.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCreatedRange(query.CreatedAfter, query.CreatedBefore); err != nil {
+		return nil, err
+	}
+
+	params := db.ListPodcastsPageByCreatedDescParams{
+		UserID:        &userID,
+		CreatedAfter:  timestampParam(query.CreatedAfter),
+		CreatedBefore: timestampParam(query.CreatedBefore),
+		Limit:         int32(page.limit + 1),
+	}
+	if query.Status != nil {
+		switch *query.Status {
+		case PodcastStatusPending, PodcastStatusWriting, PodcastStatusGenerating, PodcastStatusCompleted, PodcastStatusFailed:
+		default:
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidListQuery, *query.Status)
+		}
+		status := string(*query.Status)
+		params.Status = &status
+	}
+
+	podcasts, err := s.listPodcastsPage(ctx, page, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list podcasts: %w", err)
+	}
+
+	result := &PodcastPage{Podcasts: podcasts}
+	if len(podcasts) > page.limit {
+		result.Podcasts = podcasts[:page.limit]
+		last := result.Podcasts[page.limit-1]
+		var createdAt *time.Time
+		if last.CreatedAt.Valid {
+			createdAt = &last.CreatedAt.Time
+		}
+		result.NextCursor = page.next(last.ID, createdAt, last.Title)
+	}
+	return result, nil
+}
+
+// listPodcastsPage runs the page query of the requested order with the
+// filters and limit in params. Each order has its own query so that it can be
+// served from an index.
+func (s *podcastService) listPodcastsPage(ctx context.Context, page pageRequest, params db.ListPodcastsPageByCreatedDescParams) ([]db.Podcast, error) {
+	if page.sort != SortTitle {
+		if page.after != nil {
+			params.CursorID = &page.after.ID
+			params.CursorCreatedAt = timestampParam(page.after.CreatedAt)
+		}
+		if page.descending {
+			return s.querier.ListPodcastsPageByCreatedDesc(ctx, params)
+		}
+		return s.querier.ListPodcastsPageByCreatedAsc(ctx, db.ListPodcastsPageByCreatedAscParams(params))
+	}
+
+	titleParams := db.ListPodcastsPageByTitleAscParams{
+		UserID:        params.UserID,
+		Status:        params.Status,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Limit:         params.Limit,
+	}
+	if page.after != nil {
+		titleParams.CursorID = &page.after.ID
+		titleParams.CursorTitle = page.after.Title
+	}
+	if page.descending {
+		return s.querier.ListPodcastsPageByTitleDesc(ctx, db.ListPodcastsPageByTitleDescParams(titleParams))
+	}
+	return s.querier.ListPodcastsPageByTitleAsc(ctx, titleParams)
+}
+
+// timestampParam converts an optional time for a timestamp column, which
+// holds UTC times without a zone
+func timestampParam(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+}
+
+// GetPodcastsByStatus retrieves the user's podcasts with the given status
+func (s *podcastService) GetPodcastsByStatus(ctx context.Context, userID int32, status PodcastStatus) ([]db.Podcast, error) {
+	podcasts, err := s.querier.GetPodcastsByUserAndStatus(ctx, db.GetPodcastsByUserAndStatusParams{
+		UserID: &userID,
+		Status: string(status),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get podcasts by status: %w", err)
 	}
@@ -666,7 +898,11 @@ func (s *podcastService) GetPodcastsByStatus(ctx context.Context, status Podcast
 }
 
 // UpdatePodcast updates podcast metadata
-func (s *podcastService) UpdatePodcast(ctx context.Context, podcastID int32, title string, description string) error {
+func (s *podcastService) UpdatePodcast(ctx context.Context, userID int32, podcastID int32, title string, description string) error {
+	if _, err := s.authorizePodcast(ctx, userID, podcastID); err != nil {
+		return err
+	}
+
 	params := db.UpdatePodcastParams{
 		ID:          podcastID,
 		Title:       title,
@@ -681,7 +917,11 @@ func (s *podcastService) UpdatePodcast(ctx context.Context, podcastID int32, tit
 }
 
 // DeletePodcast deletes a podcast and its associated data
-func (s *podcastService) DeletePodcast(ctx context.Context, podcastID int32) error {
+func (s *podcastService) DeletePodcast(ctx context.Context, userID int32, podcastID int32) error {
+	if _, err := s.authorizePodcast(ctx, userID, podcastID); err != nil {
+		return err
+	}
+
 	// Clear podcast items first
 	podcastIDPtr := int32(podcastID)
 	if err := s.querier.ClearPodcastItems(ctx, &podcastIDPtr); err != nil {
@@ -697,7 +937,14 @@ func (s *podcastService) DeletePodcast(ctx context.Context, podcastID int32) err
 }
 
 // AddItemToPodcast adds an item to a podcast
-func (s *podcastService) AddItemToPodcast(ctx context.Context, podcastID int32, itemID int32, order int) error {
+func (s *podcastService) AddItemToPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32, order int) error {
+	if _, err := s.authorizePodcast(ctx, userID, podcastID); err != nil {
+		return err
+	}
+	if err := s.authorizeItems(ctx, userID, []int32{itemID}); err != nil {
+		return err
+	}
+
 	// Get current item count to determine order if not specified
 	if order < 0 {
 		count, err := s.querier.CountPodcastItems(ctx, &podcastID)
@@ -721,7 +968,11 @@ func (s *podcastService) AddItemToPodcast(ctx context.Context, podcastID int32,
 }
 
 // RemoveItemFromPodcast removes an item from a podcast
-func (s *podcastService) RemoveItemFromPodcast(ctx context.Context, podcastID int32, itemID int32) error {
+func (s *podcastService) RemoveItemFromPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32) error {
+	if _, err := s.authorizePodcast(ctx, userID, podcastID); err != nil {
+		return err
+	}
+
 	params := db.RemoveItemFromPodcastParams{
 		PodcastID: &podcastID,
 		ItemID:    &itemID,
@@ -735,7 +986,11 @@ func (s *podcastService) RemoveItemFromPodcast(ctx context.Context, podcastID in
 }
 
 // GetPodcastItems retrieves all items in a podcast
-func (s *podcastService) GetPodcastItems(ctx context.Context, podcastID int32) ([]db.GetPodcastItemsRow, error) {
+func (s *podcastService) GetPodcastItems(ctx context.Context, userID int32, podcastID int32) ([]db.GetPodcastItemsRow, error) {
+	if _, err := s.authorizePodcast(ctx, userID, podcastID); err != nil {
+		return nil, err
+	}
+
 	items, err := s.querier.GetPodcastItems(ctx, &podcastID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get podcast items: %w", err)
@@ -813,10 +1068,10 @@ func (s *podcastService) GetProcessingPodcasts(ctx context.Context, limit int32)
 }
 
 // GetPodcastAudio retrieves the audio data for a podcast
-func (s *podcastService) GetPodcastAudio(ctx context.Context, podcastID int32) ([]byte, error) {
-	podcast, err := s.querier.GetPodcast(ctx, podcastID)
+func (s *podcastService) GetPodcastAudio(ctx context.Context, userID int32, podcastID int32) ([]byte, error) {
+	podcast, err := s.authorizePodcast(ctx, userID, podcastID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get podcast: %w", err)
+		return nil, err
 	}
 
 	if podcast.AudioUrl == nil {
@@ -829,21 +1084,25 @@ func (s *podcastService) GetPodcastAudio(ctx context.Context, podcastID int32) (
 }
 
 // HasPodcastAudio checks if a podcast has audio available
-func (s *podcastService) HasPodcastAudio(ctx context.Context, podcastID int32) (bool, error) {
-	podcast, err := s.querier.GetPodcast(ctx, podcastID)
+func (s *podcastService) HasPodcastAudio(ctx context.Context, userID int32, podcastID int32) (bool, error) {
+	podcast, err := s.authorizePodcast(ctx, userID, podcastID)
 	if err != nil {
-		return false, fmt.Errorf("failed to get podcast: %w", err)
+		return false, err
 	}
 
 	return podcast.AudioUrl != nil && *podcast.AudioUrl != "", nil
 }
 
 // GeneratePodcastUploadURL generates a presigned URL for uploading podcast audio
-func (s *podcastService) GeneratePodcastUploadURL(ctx context.Context, podcastID int32) (*UploadURLResponse, error) {
+func (s *podcastService) GeneratePodcastUploadURL(ctx context.Context, userID int32, podcastID int32) (*UploadURLResponse, error) {
 	if s.r2Service == nil {
 		return nil, fmt.Errorf("R2 service not available")
 	}
 
+	if _, err := s.authorizePodcast(ctx, userID, podcastID); err != nil {
+		return nil, err
+	}
+
 	// Generate R2 key for podcast audio
 	key := fmt.Sprintf("generated/podcasts/podcast_%d_%d.mp3", podcastID, time.Now().Unix())
 
@@ -42,8 +42,8 @@ func (m *MockPodcastService) ProcessPodcast(ctx context.Context, podcastID int32
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) GetPodcast(ctx context.Context, podcastID int32) (*db.Podcast, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GetPodcast(ctx context.Context, userID int32, podcastID int32) (*db.Podcast, error) {
+	args := m.Called(ctx, userID, podcastID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -58,36 +58,44 @@ func (m *MockPodcastService) GetPodcastsByUser(ctx context.Context, userID int32
 	return args.Get(0).([]db.Podcast), args.Error(1)
 }
 
-func (m *MockPodcastService) GetPodcastsByStatus(ctx context.Context, status PodcastStatus) ([]db.Podcast, error) {
-	args := m.Called(ctx, status)
+func (m *MockPodcastService) GetPodcastsByStatus(ctx context.Context, userID int32, status PodcastStatus) ([]db.Podcast, error) {
+	args := m.Called(ctx, userID, status)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]db.Podcast), args.Error(1)
 }
 
-func (m *MockPodcastService) UpdatePodcast(ctx context.Context, podcastID int32, title string, description string) error {
-	args := m.Called(ctx, podcastID, title, description)
+func (m *MockPodcastService) ListPodcasts(ctx context.Context, userID int32, query PodcastListQuery) (*PodcastPage, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PodcastPage), args.Error(1)
+}
+
+func (m *MockPodcastService) UpdatePodcast(ctx context.Context, userID int32, podcastID int32, title string, description string) error {
+	args := m.Called(ctx, userID, podcastID, title, description)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) DeletePodcast(ctx context.Context, podcastID int32) error {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) DeletePodcast(ctx context.Context, userID int32, podcastID int32) error {
+	args := m.Called(ctx, userID, podcastID)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) AddItemToPodcast(ctx context.Context, podcastID int32, itemID int32, order int) error {
-	args := m.Called(ctx, podcastID, itemID, order)
+func (m *MockPodcastService) AddItemToPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32, order int) error {
+	args := m.Called(ctx, userID, podcastID, itemID, order)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) RemoveItemFromPodcast(ctx context.Context, podcastID int32, itemID int32) error {
-	args := m.Called(ctx, podcastID, itemID)
+func (m *MockPodcastService) RemoveItemFromPodcast(ctx context.Context, userID int32, podcastID int32, itemID int32) error {
+	args := m.Called(ctx, userID, podcastID, itemID)
 	return args.Error(0)
 }
 
-func (m *MockPodcastService) GetPodcastItems(ctx context.Context, podcastID int32) ([]db.GetPodcastItemsRow, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GetPodcastItems(ctx context.Context, userID int32, podcastID int32) ([]db.GetPodcastItemsRow, error) {
+	args := m.Called(ctx, userID, podcastID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -123,21 +131,21 @@ func (m *MockPodcastService) AcquirePendingPodcasts(ctx context.Context, limit i
 	return args.Get(0).([]db.Podcast), args.Error(1)
 }
 
-func (m *MockPodcastService) GetPodcastAudio(ctx context.Context, podcastID int32) ([]byte, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GetPodcastAudio(ctx context.Context, userID int32, podcastID int32) ([]byte, error) {
+	args := m.Called(ctx, userID, podcastID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]byte), args.Error(1)
 }
 
-func (m *MockPodcastService) HasPodcastAudio(ctx context.Context, podcastID int32) (bool, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) HasPodcastAudio(ctx context.Context, userID int32, podcastID int32) (bool, error) {
+	args := m.Called(ctx, userID, podcastID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockPodcastService) GeneratePodcastUploadURL(ctx context.Context, podcastID int32) (*UploadURLResponse, error) {
-	args := m.Called(ctx, podcastID)
+func (m *MockPodcastService) GeneratePodcastUploadURL(ctx context.Context, userID int32, podcastID int32) (*UploadURLResponse, error) {
+	args := m.Called(ctx, userID, podcastID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -147,3 +155,11 @@ func (m *MockPodcastService) GeneratePodcastUploadURL(ctx context.Context, podca
 func (m *MockPodcastService) SetSSEManager(sseManager *SSEManager) {
 	m.Called(sseManager)
 }
+
+func (m *MockPodcastService) SetPreferencesService(preferencesService PreferencesService) {
+	m.Called(preferencesService)
+}
+
+func (m *MockPodcastService) SetQuotaService(quotaService QuotaService) {
+	m.Called(quotaService)
+}
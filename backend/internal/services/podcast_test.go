@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/yamirghofran/briefbot/internal/db"
@@ -41,6 +42,40 @@ func TestSetSSEManager_PodcastService(t *testing.T) {
 	assert.Equal(t, manager, podcastSvc.sseManager)
 }
 
+func TestSpeechSettingsFor(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+	config := DefaultPodcastConfig()
+	service := NewPodcastService(new(test.MockQuerier), new(MockAIService), new(MockSpeechService), nil, config).(*podcastService)
+
+	// Without a preferences service the configured voices are used
+	settings := service.speechSettingsFor(ctx, &userID)
+	assert.Equal(t, config.VoiceMapping, settings.voices)
+	assert.Equal(t, config.DefaultSpeed, settings.speed)
+
+	mockPreferences := new(MockPreferencesService)
+	service.SetPreferencesService(mockPreferences)
+
+	prefs := DefaultUserPreferences(userID)
+	prefs.HostVoice = string(VoiceAfNova)
+	prefs.CohostVoice = string(VoiceAmLiam)
+	prefs.SpeechSpeed = 1.25
+	mockPreferences.On("GetPreferences", ctx, userID).Return(&prefs, nil).Once()
+
+	settings = service.speechSettingsFor(ctx, &userID)
+	assert.Equal(t, VoiceAfNova, settings.voices[PodcastHostSpeaker])
+	assert.Equal(t, VoiceAmLiam, settings.voices[PodcastCohostSpeaker])
+	assert.Equal(t, 1.25, settings.speed)
+
+	// Lookup failures fall back to the configured voices
+	mockPreferences.On("GetPreferences", ctx, userID).Return(nil, fmt.Errorf("database error")).Once()
+
+	settings = service.speechSettingsFor(ctx, &userID)
+	assert.Equal(t, config.VoiceMapping, settings.voices)
+	assert.Equal(t, config.DefaultSpeed, settings.speed)
+	mockPreferences.AssertExpectations(t)
+}
+
 func TestPodcastConfig_Structure(t *testing.T) {
 	config := PodcastConfig{
 		DefaultSpeed:       1.5,
@@ -83,6 +118,7 @@ func TestCreatePodcastFromItems(t *testing.T) {
 		Status:      "pending",
 	}
 
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: itemIDs, UserID: &userID}).Return(int64(len(itemIDs)), nil)
 	mockQuerier.On("CreatePodcast", ctx, mock.MatchedBy(func(params db.CreatePodcastParams) bool {
 		return params.Title == title && *params.UserID == userID
 	})).Return(expectedPodcast, nil)
@@ -126,6 +162,26 @@ func TestCreatePodcastFromItems_TooManyItems(t *testing.T) {
 	assert.Contains(t, err.Error(), "too many items")
 }
 
+func TestCreatePodcastFromItems_QuotaExceeded(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockQuota := new(MockQuotaService)
+	service := NewPodcastService(mockQuerier, new(MockAIService), new(MockSpeechService), nil, DefaultPodcastConfig())
+	service.SetQuotaService(mockQuota)
+
+	ctx := context.Background()
+	userID := int32(1)
+	itemIDs := []int32{1, 2}
+
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: itemIDs, UserID: &userID}).Return(int64(len(itemIDs)), nil)
+	mockQuota.On("CheckPodcastMinutes", ctx, userID).Return(&QuotaExceededError{Quota: QuotaPodcastMinutes, Limit: 30})
+
+	podcast, err := service.CreatePodcastFromItems(ctx, userID, "Title", "Description", itemIDs)
+
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Nil(t, podcast)
+	mockQuerier.AssertNotCalled(t, "CreatePodcast")
+}
+
 func TestCreatePodcastFromSingleItem(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	mockAI := new(MockAIService)
@@ -154,7 +210,8 @@ func TestCreatePodcastFromSingleItem(t *testing.T) {
 		Status: "pending",
 	}
 
-	mockQuerier.On("GetItem", ctx, itemID).Return(item, nil)
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(item, nil)
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: []int32{itemID}, UserID: &userID}).Return(int64(1), nil)
 	mockQuerier.On("CreatePodcast", ctx, mock.Anything).Return(expectedPodcast, nil)
 	mockQuerier.On("AddItemToPodcast", ctx, mock.Anything).Return(db.PodcastItem{}, nil)
 
@@ -175,6 +232,7 @@ func TestGetPodcast(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 
 	expectedPodcast := db.Podcast{
@@ -183,9 +241,9 @@ func TestGetPodcast(t *testing.T) {
 		Status: "completed",
 	}
 
-	mockQuerier.On("GetPodcast", ctx, podcastID).Return(expectedPodcast, nil)
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(expectedPodcast, nil)
 
-	podcast, err := service.GetPodcast(ctx, podcastID)
+	podcast, err := service.GetPodcast(ctx, userID, podcastID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, podcast)
@@ -229,15 +287,17 @@ func TestUpdatePodcast(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 	title := "Updated Title"
 	description := "Updated Description"
 
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
 	mockQuerier.On("UpdatePodcast", ctx, mock.MatchedBy(func(params db.UpdatePodcastParams) bool {
 		return params.ID == podcastID && params.Title == title
 	})).Return(nil)
 
-	err := service.UpdatePodcast(ctx, podcastID, title, description)
+	err := service.UpdatePodcast(ctx, userID, podcastID, title, description)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
@@ -253,12 +313,14 @@ func TestDeletePodcast(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
 	mockQuerier.On("ClearPodcastItems", ctx, &podcastID).Return(nil)
 	mockQuerier.On("DeletePodcast", ctx, podcastID).Return(nil)
 
-	err := service.DeletePodcast(ctx, podcastID)
+	err := service.DeletePodcast(ctx, userID, podcastID)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
@@ -274,15 +336,18 @@ func TestAddItemToPodcast(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 	itemID := int32(1)
 	order := 0
 
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: []int32{itemID}, UserID: &userID}).Return(int64(1), nil)
 	mockQuerier.On("AddItemToPodcast", ctx, mock.MatchedBy(func(params db.AddItemToPodcastParams) bool {
 		return *params.PodcastID == podcastID && *params.ItemID == itemID
 	})).Return(db.PodcastItem{}, nil)
 
-	err := service.AddItemToPodcast(ctx, podcastID, itemID, order)
+	err := service.AddItemToPodcast(ctx, userID, podcastID, itemID, order)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
@@ -298,14 +363,16 @@ func TestRemoveItemFromPodcast(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 	itemID := int32(1)
 
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
 	mockQuerier.On("RemoveItemFromPodcast", ctx, mock.MatchedBy(func(params db.RemoveItemFromPodcastParams) bool {
 		return *params.PodcastID == podcastID && *params.ItemID == itemID
 	})).Return(nil)
 
-	err := service.RemoveItemFromPodcast(ctx, podcastID, itemID)
+	err := service.RemoveItemFromPodcast(ctx, userID, podcastID, itemID)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
@@ -344,6 +411,7 @@ func TestGetPodcastItems(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 
 	expectedItems := []db.GetPodcastItemsRow{
@@ -351,9 +419,10 @@ func TestGetPodcastItems(t *testing.T) {
 		{ID: 2, Title: "Item 2"},
 	}
 
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
 	mockQuerier.On("GetPodcastItems", ctx, &podcastID).Return(expectedItems, nil)
 
-	items, err := service.GetPodcastItems(ctx, podcastID)
+	items, err := service.GetPodcastItems(ctx, userID, podcastID)
 
 	assert.NoError(t, err)
 	assert.Len(t, items, 2)
@@ -396,6 +465,7 @@ func TestGetPodcastsByStatus(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	status := PodcastStatusPending
 
 	expectedPodcasts := []db.Podcast{
@@ -403,9 +473,9 @@ func TestGetPodcastsByStatus(t *testing.T) {
 		{ID: 2, Status: "pending"},
 	}
 
-	mockQuerier.On("GetPodcastsByStatus", ctx, string(status)).Return(expectedPodcasts, nil)
+	mockQuerier.On("GetPodcastsByUserAndStatus", ctx, db.GetPodcastsByUserAndStatusParams{UserID: &userID, Status: string(status)}).Return(expectedPodcasts, nil)
 
-	podcasts, err := service.GetPodcastsByStatus(ctx, status)
+	podcasts, err := service.GetPodcastsByStatus(ctx, userID, status)
 
 	assert.NoError(t, err)
 	assert.Len(t, podcasts, 2)
@@ -448,6 +518,7 @@ func TestGetPodcastAudio(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 	audioURL := "https://example.com/audio.mp3"
 
@@ -456,9 +527,9 @@ func TestGetPodcastAudio(t *testing.T) {
 		AudioUrl: &audioURL,
 	}
 
-	mockQuerier.On("GetPodcast", ctx, podcastID).Return(podcast, nil)
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(podcast, nil)
 
-	_, err := service.GetPodcastAudio(ctx, podcastID)
+	_, err := service.GetPodcastAudio(ctx, userID, podcastID)
 
 	// Should return error with URL
 	assert.Error(t, err)
@@ -476,9 +547,10 @@ func TestGeneratePodcastUploadURL_NoR2Service(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 
-	_, err := service.GeneratePodcastUploadURL(ctx, podcastID)
+	_, err := service.GeneratePodcastUploadURL(ctx, userID, podcastID)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "R2 service not available")
@@ -494,13 +566,16 @@ func TestAddItemToPodcast_ErrorCounting(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 	itemID := int32(100)
 
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: []int32{itemID}, UserID: &userID}).Return(int64(1), nil)
 	// Test error when counting items
 	mockQuerier.On("CountPodcastItems", ctx, &podcastID).Return(int64(0), fmt.Errorf("database error"))
 
-	err := service.AddItemToPodcast(ctx, podcastID, itemID, -1)
+	err := service.AddItemToPodcast(ctx, userID, podcastID, itemID, -1)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to count podcast items")
@@ -517,15 +592,18 @@ func TestAddItemToPodcast_ErrorAdding(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 	itemID := int32(100)
 
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: []int32{itemID}, UserID: &userID}).Return(int64(1), nil)
 	// Test error when adding item with explicit order
 	mockQuerier.On("AddItemToPodcast", ctx, mock.MatchedBy(func(params db.AddItemToPodcastParams) bool {
 		return *params.PodcastID == podcastID && *params.ItemID == itemID
 	})).Return(db.PodcastItem{}, fmt.Errorf("database error"))
 
-	err := service.AddItemToPodcast(ctx, podcastID, itemID, 5)
+	err := service.AddItemToPodcast(ctx, userID, podcastID, itemID, 5)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to add item to podcast")
@@ -663,6 +741,30 @@ func TestProcessPodcast(t *testing.T) {
 	// Better tested in integration tests
 }
 
+func TestProcessPodcast_QuotaExceeded(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockAI := new(MockAIService)
+	mockQuota := new(MockQuotaService)
+	service := NewPodcastService(mockQuerier, mockAI, nil, nil, DefaultPodcastConfig())
+	service.SetQuotaService(mockQuota)
+
+	ctx := context.Background()
+	userID := int32(1)
+	podcastID := int32(3)
+
+	mockQuerier.On("GetPodcast", ctx, podcastID).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
+	mockQuota.On("CheckPodcastMinutes", ctx, userID).Return(&QuotaExceededError{Quota: QuotaPodcastMinutes, Limit: 30})
+
+	err := service.ProcessPodcast(ctx, podcastID)
+
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	// Nothing is spent on a podcast the user has no minutes left for
+	mockAI.AssertNotCalled(t, "WritePodcast", mock.Anything)
+	mockQuerier.AssertNotCalled(t, "UpdatePodcastStatus", mock.Anything, mock.Anything)
+	mockQuerier.AssertExpectations(t)
+	mockQuota.AssertExpectations(t)
+}
+
 func TestHasPodcastAudio(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	mockAI := new(MockAIService)
@@ -673,6 +775,7 @@ func TestHasPodcastAudio(t *testing.T) {
 	service := NewPodcastService(mockQuerier, mockAI, mockSpeech, mockR2, config)
 
 	ctx := context.Background()
+	userID := int32(1)
 	podcastID := int32(1)
 	audioURL := "https://example.com/podcast.mp3"
 
@@ -681,11 +784,146 @@ func TestHasPodcastAudio(t *testing.T) {
 		AudioUrl: &audioURL,
 	}
 
-	mockQuerier.On("GetPodcast", ctx, podcastID).Return(podcast, nil)
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(podcast, nil)
 
-	hasAudio, err := service.HasPodcastAudio(ctx, podcastID)
+	hasAudio, err := service.HasPodcastAudio(ctx, userID, podcastID)
 
 	assert.NoError(t, err)
 	assert.True(t, hasAudio)
 	mockQuerier.AssertExpectations(t)
 }
+
+func TestPodcastService_OtherUsersPodcastNotFound(t *testing.T) {
+	ctx := context.Background()
+	otherUserID := int32(8)
+	podcastID := int32(1)
+	itemID := int32(2)
+
+	tests := []struct {
+		name string
+		call func(service PodcastService) error
+	}{
+		{
+			name: "GetPodcast",
+			call: func(service PodcastService) error {
+				_, err := service.GetPodcast(ctx, otherUserID, podcastID)
+				return err
+			},
+		},
+		{
+			name: "UpdatePodcast",
+			call: func(service PodcastService) error {
+				return service.UpdatePodcast(ctx, otherUserID, podcastID, "title", "description")
+			},
+		},
+		{
+			name: "DeletePodcast",
+			call: func(service PodcastService) error {
+				return service.DeletePodcast(ctx, otherUserID, podcastID)
+			},
+		},
+		{
+			name: "AddItemToPodcast",
+			call: func(service PodcastService) error {
+				return service.AddItemToPodcast(ctx, otherUserID, podcastID, itemID, 0)
+			},
+		},
+		{
+			name: "RemoveItemFromPodcast",
+			call: func(service PodcastService) error {
+				return service.RemoveItemFromPodcast(ctx, otherUserID, podcastID, itemID)
+			},
+		},
+		{
+			name: "GetPodcastItems",
+			call: func(service PodcastService) error {
+				_, err := service.GetPodcastItems(ctx, otherUserID, podcastID)
+				return err
+			},
+		},
+		{
+			name: "GetPodcastAudio",
+			call: func(service PodcastService) error {
+				_, err := service.GetPodcastAudio(ctx, otherUserID, podcastID)
+				return err
+			},
+		},
+		{
+			name: "HasPodcastAudio",
+			call: func(service PodcastService) error {
+				_, err := service.HasPodcastAudio(ctx, otherUserID, podcastID)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			service := NewPodcastService(mockQuerier, new(MockAIService), new(MockSpeechService), nil, DefaultPodcastConfig())
+
+			mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &otherUserID}).Return(db.Podcast{}, pgx.ErrNoRows)
+
+			err := tt.call(service)
+
+			assert.ErrorIs(t, err, ErrPodcastNotFound)
+			// Only the ownership lookup may run; nothing else reaches the database
+			assert.Len(t, mockQuerier.Calls, 1)
+			mockQuerier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddItemToPodcast_OtherUsersItem(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPodcastService(mockQuerier, new(MockAIService), new(MockSpeechService), nil, DefaultPodcastConfig())
+
+	ctx := context.Background()
+	userID := int32(1)
+	podcastID := int32(1)
+	itemID := int32(99)
+
+	mockQuerier.On("GetPodcastForUser", ctx, db.GetPodcastForUserParams{ID: podcastID, UserID: &userID}).Return(db.Podcast{ID: podcastID, UserID: &userID}, nil)
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: []int32{itemID}, UserID: &userID}).Return(int64(0), nil)
+
+	err := service.AddItemToPodcast(ctx, userID, podcastID, itemID, 0)
+
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "AddItemToPodcast", mock.Anything, mock.Anything)
+}
+
+func TestCreatePodcastFromItems_OtherUsersItem(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPodcastService(mockQuerier, new(MockAIService), new(MockSpeechService), nil, DefaultPodcastConfig())
+
+	ctx := context.Background()
+	userID := int32(1)
+
+	// Duplicate IDs are collapsed before counting, so only one of the two distinct items is owned
+	mockQuerier.On("CountItemsVisibleToUser", ctx, db.CountItemsVisibleToUserParams{ItemIds: []int32{1, 2}, UserID: &userID}).Return(int64(1), nil)
+
+	podcast, err := service.CreatePodcastFromItems(ctx, userID, "Title", "Description", []int32{1, 2, 1})
+
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	assert.Nil(t, podcast)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "CreatePodcast", mock.Anything, mock.Anything)
+}
+
+func TestCreatePodcastFromSingleItem_OtherUsersItem(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPodcastService(mockQuerier, new(MockAIService), new(MockSpeechService), nil, DefaultPodcastConfig())
+
+	ctx := context.Background()
+	userID := int32(1)
+	itemID := int32(99)
+
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}).Return(db.Item{}, pgx.ErrNoRows)
+
+	podcast, err := service.CreatePodcastFromSingleItem(ctx, userID, itemID)
+
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	assert.Nil(t, podcast)
+	mockQuerier.AssertExpectations(t)
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// Digest frequencies a user can choose from
+const (
+	DigestFrequencyDaily    = "daily"
+	DigestFrequencyWeekdays = "weekdays"
+	DigestFrequencyWeekly   = "weekly"
+	DigestFrequencyOff      = "off"
+)
+
+// Summary lengths a user can choose from
+const (
+	SummaryLengthShort  = "short"
+	SummaryLengthMedium = "medium"
+	SummaryLengthLong   = "long"
+)
+
+// Bounds for the text-to-speech playback speed
+const (
+	MinSpeechSpeed = 0.5
+	MaxSpeechSpeed = 2.0
+)
+
+// Speakers used by the podcast writer; each is voiced by one of the user's preferred voices
+const (
+	PodcastHostSpeaker   = "heart"
+	PodcastCohostSpeaker = "adam"
+)
+
+var ErrInvalidPreferences = errors.New("invalid preferences")
+
+var languageCodePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// PreferencesService stores and validates per-user settings
+type PreferencesService interface {
+	GetPreferences(ctx context.Context, userID int32) (*db.UserPreference, error)
+	UpdatePreferences(ctx context.Context, userID int32, update PreferencesUpdate) (*db.UserPreference, error)
+	MarkDigestSent(ctx context.Context, userID int32, sentAt time.Time) error
+}
+
+// PreferencesUpdate is a partial update; nil fields keep their current value
+type PreferencesUpdate struct {
+	Timezone        *string
+	DigestTime      *string
+	DigestFrequency *string
+	PodcastEnabled  *bool
+	HostVoice       *string
+	CohostVoice     *string
+	SpeechSpeed     *float64
+	SummaryLength   *string
+	SummaryLanguage *string
+}
+
+type preferencesService struct {
+	querier db.Querier
+}
+
+func NewPreferencesService(querier db.Querier) PreferencesService {
+	return &preferencesService{querier: querier}
+}
+
+// DefaultUserPreferences returns the settings used for users who never saved any.
+// PodcastEnabled is left nil so the global DIGEST_PODCAST_ENABLED setting applies.
+func DefaultUserPreferences(userID int32) db.UserPreference {
+	return db.UserPreference{
+		UserID:          userID,
+		Timezone:        "UTC",
+		DigestTime:      "08:00",
+		DigestFrequency: DigestFrequencyDaily,
+		HostVoice:       string(VoiceAfHeart),
+		CohostVoice:     string(VoiceAmAdam),
+		SpeechSpeed:     1.0,
+		SummaryLength:   SummaryLengthMedium,
+		SummaryLanguage: "en",
+	}
+}
+
+// GetPreferences returns the stored preferences, or the defaults when none were saved
+func (s *preferencesService) GetPreferences(ctx context.Context, userID int32) (*db.UserPreference, error) {
+	prefs, err := s.querier.GetUserPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			defaults := DefaultUserPreferences(userID)
+			return &defaults, nil
+		}
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences merges the update into the current preferences, validates and saves them
+func (s *preferencesService) UpdatePreferences(ctx context.Context, userID int32, update PreferencesUpdate) (*db.UserPreference, error) {
+	current, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := *current
+	if update.Timezone != nil {
+		merged.Timezone = *update.Timezone
+	}
+	if update.DigestTime != nil {
+		merged.DigestTime = *update.DigestTime
+	}
+	if update.DigestFrequency != nil {
+		merged.DigestFrequency = *update.DigestFrequency
+	}
+	if update.PodcastEnabled != nil {
+		merged.PodcastEnabled = update.PodcastEnabled
+	}
+	if update.HostVoice != nil {
+		merged.HostVoice = *update.HostVoice
+	}
+	if update.CohostVoice != nil {
+		merged.CohostVoice = *update.CohostVoice
+	}
+	if update.SpeechSpeed != nil {
+		merged.SpeechSpeed = *update.SpeechSpeed
+	}
+	if update.SummaryLength != nil {
+		merged.SummaryLength = *update.SummaryLength
+	}
+	if update.SummaryLanguage != nil {
+		merged.SummaryLanguage = *update.SummaryLanguage
+	}
+
+	if err := ValidatePreferences(merged); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.querier.UpsertUserPreferences(ctx, db.UpsertUserPreferencesParams{
+		UserID:          userID,
+		Timezone:        merged.Timezone,
+		DigestTime:      merged.DigestTime,
+		DigestFrequency: merged.DigestFrequency,
+		PodcastEnabled:  merged.PodcastEnabled,
+		HostVoice:       merged.HostVoice,
+		CohostVoice:     merged.CohostVoice,
+		SpeechSpeed:     merged.SpeechSpeed,
+		SummaryLength:   merged.SummaryLength,
+		SummaryLanguage: merged.SummaryLanguage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return &saved, nil
+}
+
+// MarkDigestSent records when the user's last scheduled digest went out
+func (s *preferencesService) MarkDigestSent(ctx context.Context, userID int32, sentAt time.Time) error {
+	err := s.querier.UpdateLastDigestSent(ctx, db.UpdateLastDigestSentParams{
+		UserID:           userID,
+		LastDigestSentAt: &sentAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	return nil
+}
+
+// ValidatePreferences checks every field and wraps failures in ErrInvalidPreferences
+func ValidatePreferences(prefs db.UserPreference) error {
+	if _, err := time.LoadLocation(prefs.Timezone); err != nil || prefs.Timezone == "" || prefs.Timezone == "Local" {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidPreferences, prefs.Timezone)
+	}
+	if _, _, err := parseDigestTime(prefs.DigestTime); err != nil {
+		return fmt.Errorf("%w: digest_time must be HH:MM", ErrInvalidPreferences)
+	}
+	switch prefs.DigestFrequency {
+	case DigestFrequencyDaily, DigestFrequencyWeekdays, DigestFrequencyWeekly, DigestFrequencyOff:
+	default:
+		return fmt.Errorf("%w: unknown digest_frequency %q", ErrInvalidPreferences, prefs.DigestFrequency)
+	}
+	if !IsValidVoice(prefs.HostVoice) {
+		return fmt.Errorf("%w: unknown host_voice %q", ErrInvalidPreferences, prefs.HostVoice)
+	}
+	if !IsValidVoice(prefs.CohostVoice) {
+		return fmt.Errorf("%w: unknown cohost_voice %q", ErrInvalidPreferences, prefs.CohostVoice)
+	}
+	if prefs.SpeechSpeed < MinSpeechSpeed || prefs.SpeechSpeed > MaxSpeechSpeed {
+		return fmt.Errorf("%w: speech_speed must be between %.1f and %.1f", ErrInvalidPreferences, MinSpeechSpeed, MaxSpeechSpeed)
+	}
+	switch prefs.SummaryLength {
+	case SummaryLengthShort, SummaryLengthMedium, SummaryLengthLong:
+	default:
+		return fmt.Errorf("%w: unknown summary_length %q", ErrInvalidPreferences, prefs.SummaryLength)
+	}
+	if !languageCodePattern.MatchString(prefs.SummaryLanguage) {
+		return fmt.Errorf("%w: summary_language must be a language code such as \"en\" or \"pt-BR\"", ErrInvalidPreferences)
+	}
+	return nil
+}
+
+// IsDigestDue reports whether a scheduled digest should be sent at now.
+// A digest is due once the user's local digest time has passed on an eligible
+// day and nothing has been sent since that slot.
+func IsDigestDue(prefs db.UserPreference, now time.Time) bool {
+	loc := preferenceLocation(prefs)
+	local := now.In(loc)
+
+	switch prefs.DigestFrequency {
+	case DigestFrequencyOff:
+		return false
+	case DigestFrequencyWeekdays:
+		if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+			return false
+		}
+	}
+
+	hour, minute, err := parseDigestTime(prefs.DigestTime)
+	if err != nil {
+		return false
+	}
+	slot := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if local.Before(slot) {
+		return false
+	}
+	if prefs.LastDigestSentAt == nil {
+		return true
+	}
+
+	if prefs.DigestFrequency == DigestFrequencyWeekly {
+		// Allow for the scheduler firing a little late last week
+		return prefs.LastDigestSentAt.Before(slot.AddDate(0, 0, -6))
+	}
+	return prefs.LastDigestSentAt.Before(slot)
+}
+
+// DigestWindow returns the [start, end) creation-time range of items covered by
+// a digest sent at now, measured in whole days of the user's timezone
+func DigestWindow(prefs db.UserPreference, now time.Time) (time.Time, time.Time) {
+	loc := preferenceLocation(prefs)
+	local := now.In(loc)
+	end := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	days := 1
+	switch prefs.DigestFrequency {
+	case DigestFrequencyWeekly:
+		days = 7
+	case DigestFrequencyWeekdays:
+		if local.Weekday() == time.Monday {
+			// Cover the weekend as well as Friday
+			days = 3
+		}
+	}
+	return end.AddDate(0, 0, -days), end
+}
+
+// SummaryOptionsFromPreferences maps stored preferences to AI summary options
+func SummaryOptionsFromPreferences(prefs db.UserPreference) SummaryOptions {
+	return SummaryOptions{
+		Length:   prefs.SummaryLength,
+		Language: prefs.SummaryLanguage,
+	}
+}
+
+// preferenceLocation resolves the user's timezone, falling back to UTC
+func preferenceLocation(prefs db.UserPreference) *time.Location {
+	loc, err := time.LoadLocation(prefs.Timezone)
+	if err != nil || prefs.Timezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseDigestTime parses a 24-hour HH:MM string
+func parseDigestTime(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Hour(), t.Minute(), nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+type MockPreferencesService struct {
+	mock.Mock
+}
+
+func (m *MockPreferencesService) GetPreferences(ctx context.Context, userID int32) (*db.UserPreference, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.UserPreference), args.Error(1)
+}
+
+func (m *MockPreferencesService) UpdatePreferences(ctx context.Context, userID int32, update PreferencesUpdate) (*db.UserPreference, error) {
+	args := m.Called(ctx, userID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.UserPreference), args.Error(1)
+}
+
+func (m *MockPreferencesService) MarkDigestSent(ctx context.Context, userID int32, sentAt time.Time) error {
+	args := m.Called(ctx, userID, sentAt)
+	return args.Error(0)
+}
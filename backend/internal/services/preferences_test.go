@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func TestGetPreferences_DefaultsWhenMissing(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPreferencesService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetUserPreferences", ctx, int32(1)).Return(db.UserPreference{}, pgx.ErrNoRows)
+
+	prefs, err := service.GetPreferences(ctx, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUserPreferences(1), *prefs)
+	assert.Nil(t, prefs.PodcastEnabled)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestGetPreferences_Stored(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPreferencesService(mockQuerier)
+	ctx := context.Background()
+
+	stored := DefaultUserPreferences(1)
+	stored.Timezone = "Europe/Madrid"
+	mockQuerier.On("GetUserPreferences", ctx, int32(1)).Return(stored, nil)
+
+	prefs, err := service.GetPreferences(ctx, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Madrid", prefs.Timezone)
+}
+
+func TestGetPreferences_Error(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPreferencesService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetUserPreferences", ctx, int32(1)).Return(db.UserPreference{}, errors.New("database error"))
+
+	prefs, err := service.GetPreferences(ctx, 1)
+
+	assert.Error(t, err)
+	assert.Nil(t, prefs)
+}
+
+func TestUpdatePreferences_MergesAndSaves(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPreferencesService(mockQuerier)
+	ctx := context.Background()
+
+	stored := DefaultUserPreferences(1)
+	stored.SummaryLanguage = "es"
+	mockQuerier.On("GetUserPreferences", ctx, int32(1)).Return(stored, nil)
+
+	timezone := "Asia/Tokyo"
+	frequency := DigestFrequencyWeekly
+	enabled := true
+	speed := 1.5
+	expected := db.UpsertUserPreferencesParams{
+		UserID:          1,
+		Timezone:        timezone,
+		DigestTime:      "08:00",
+		DigestFrequency: frequency,
+		PodcastEnabled:  &enabled,
+		HostVoice:       string(VoiceAfHeart),
+		CohostVoice:     string(VoiceAmAdam),
+		SpeechSpeed:     speed,
+		SummaryLength:   SummaryLengthMedium,
+		SummaryLanguage: "es",
+	}
+	saved := stored
+	saved.Timezone = timezone
+	mockQuerier.On("UpsertUserPreferences", ctx, expected).Return(saved, nil)
+
+	prefs, err := service.UpdatePreferences(ctx, 1, PreferencesUpdate{
+		Timezone:        &timezone,
+		DigestFrequency: &frequency,
+		PodcastEnabled:  &enabled,
+		SpeechSpeed:     &speed,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, timezone, prefs.Timezone)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestUpdatePreferences_Invalid(t *testing.T) {
+	str := func(s string) *string { return &s }
+	speed := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name   string
+		update PreferencesUpdate
+	}{
+		{"unknown timezone", PreferencesUpdate{Timezone: str("Mars/Olympus")}},
+		{"local timezone", PreferencesUpdate{Timezone: str("Local")}},
+		{"bad digest time", PreferencesUpdate{DigestTime: str("25:00")}},
+		{"unknown frequency", PreferencesUpdate{DigestFrequency: str("hourly")}},
+		{"unknown host voice", PreferencesUpdate{HostVoice: str("robot")}},
+		{"unknown cohost voice", PreferencesUpdate{CohostVoice: str("")}},
+		{"speed too slow", PreferencesUpdate{SpeechSpeed: speed(0.1)}},
+		{"speed too fast", PreferencesUpdate{SpeechSpeed: speed(3)}},
+		{"unknown length", PreferencesUpdate{SummaryLength: str("epic")}},
+		{"bad language", PreferencesUpdate{SummaryLanguage: str("english please")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			service := NewPreferencesService(mockQuerier)
+			ctx := context.Background()
+
+			mockQuerier.On("GetUserPreferences", ctx, int32(1)).Return(db.UserPreference{}, pgx.ErrNoRows)
+
+			prefs, err := service.UpdatePreferences(ctx, 1, tt.update)
+
+			assert.ErrorIs(t, err, ErrInvalidPreferences)
+			assert.Nil(t, prefs)
+			mockQuerier.AssertNotCalled(t, "UpsertUserPreferences", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMarkDigestSent(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewPreferencesService(mockQuerier)
+	ctx := context.Background()
+	sentAt := time.Date(2025, time.March, 4, 8, 0, 0, 0, time.UTC)
+
+	mockQuerier.On("UpdateLastDigestSent", ctx, db.UpdateLastDigestSentParams{UserID: 1, LastDigestSentAt: &sentAt}).Return(nil)
+
+	err := service.MarkDigestSent(ctx, 1, sentAt)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestIsDigestDue(t *testing.T) {
+	// Tuesday 4 March 2025, 09:30 UTC
+	now := time.Date(2025, time.March, 4, 9, 30, 0, 0, time.UTC)
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(p *db.UserPreference)
+		now      time.Time
+		expected bool
+	}{
+		{"never sent after digest time", func(p *db.UserPreference) {}, now, true},
+		{"before digest time", func(p *db.UserPreference) { p.DigestTime = "10:00" }, now, false},
+		{"already sent today", func(p *db.UserPreference) { p.LastDigestSentAt = at(4, 8) }, now, false},
+		{"sent yesterday", func(p *db.UserPreference) { p.LastDigestSentAt = at(3, 8) }, now, true},
+		{"off", func(p *db.UserPreference) { p.DigestFrequency = DigestFrequencyOff }, now, false},
+		{"weekdays on saturday", func(p *db.UserPreference) { p.DigestFrequency = DigestFrequencyWeekdays }, now.AddDate(0, 0, 4), false},
+		{"weekdays on tuesday", func(p *db.UserPreference) { p.DigestFrequency = DigestFrequencyWeekdays }, now, true},
+		{"weekly sent yesterday", func(p *db.UserPreference) {
+			p.DigestFrequency = DigestFrequencyWeekly
+			p.LastDigestSentAt = at(3, 8)
+		}, now, false},
+		{"weekly sent a week ago", func(p *db.UserPreference) {
+			p.DigestFrequency = DigestFrequencyWeekly
+			sent := time.Date(2025, time.February, 25, 8, 1, 0, 0, time.UTC)
+			p.LastDigestSentAt = &sent
+		}, now, true},
+		{"timezone ahead of utc", func(p *db.UserPreference) {
+			// 09:30 UTC is 18:30 in Tokyo
+			p.Timezone = "Asia/Tokyo"
+			p.DigestTime = "18:00"
+		}, now, true},
+		{"timezone behind utc", func(p *db.UserPreference) {
+			// 09:30 UTC is 04:30 in New York
+			p.Timezone = "America/New_York"
+		}, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := DefaultUserPreferences(1)
+			tt.modify(&prefs)
+			assert.Equal(t, tt.expected, IsDigestDue(prefs, tt.now))
+		})
+	}
+}
+
+func TestDigestWindow(t *testing.T) {
+	prefs := DefaultUserPreferences(1)
+	prefs.Timezone = "Europe/Madrid"
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	// Tuesday 4 March 2025, 09:30 in Madrid
+	now := time.Date(2025, time.March, 4, 9, 30, 0, 0, madrid)
+
+	start, end := DigestWindow(prefs, now)
+	assert.Equal(t, time.Date(2025, time.March, 3, 0, 0, 0, 0, madrid), start)
+	assert.Equal(t, time.Date(2025, time.March, 4, 0, 0, 0, 0, madrid), end)
+
+	prefs.DigestFrequency = DigestFrequencyWeekly
+	start, _ = DigestWindow(prefs, now)
+	assert.Equal(t, time.Date(2025, time.February, 25, 0, 0, 0, 0, madrid), start)
+
+	// Monday digests on weekdays cover Friday and the weekend
+	prefs.DigestFrequency = DigestFrequencyWeekdays
+	start, _ = DigestWindow(prefs, now.AddDate(0, 0, -1))
+	assert.Equal(t, time.Date(2025, time.February, 28, 0, 0, 0, 0, madrid), start)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/metrics"
+)
+
+// ErrQuotaExceeded is matched by every QuotaExceededError
+var ErrQuotaExceeded = errors.New("daily quota exceeded")
+
+// Quota names, as reported in errors and metrics
+const (
+	QuotaItems          = "items"
+	QuotaPodcastMinutes = "podcast_minutes"
+)
+
+// QuotaExceededError reports which daily quota was hit and how long until it resets
+type QuotaExceededError struct {
+	Quota      string
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("daily %s quota of %d exceeded", e.Quota, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrQuotaExceeded) match
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// QuotaConfig holds the per-user daily limits. Zero disables a quota.
+type QuotaConfig struct {
+	ItemsPerDay          int
+	PodcastMinutesPerDay int
+}
+
+// QuotaService counts per-user daily usage of the expensive operations:
+// item submission (scraping plus LLM calls) and podcast audio (TTS calls).
+// Days run from midnight to midnight UTC.
+type QuotaService interface {
+	// ConsumeItem counts one submitted item, failing once the day's quota is used up
+	ConsumeItem(ctx context.Context, userID int32) error
+	// CheckPodcastMinutes fails when the user has no podcast minutes left today
+	CheckPodcastMinutes(ctx context.Context, userID int32) error
+	// RecordPodcastSeconds counts generated audio against today's podcast quota
+	RecordPodcastSeconds(ctx context.Context, userID int32, seconds int32) error
+}
+
+type quotaService struct {
+	querier db.Querier
+	config  QuotaConfig
+	now     func() time.Time
+}
+
+// NewQuotaService creates a new quota service
+func NewQuotaService(querier db.Querier, config QuotaConfig) QuotaService {
+	return &quotaService{
+		querier: querier,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+func (s *quotaService) ConsumeItem(ctx context.Context, userID int32) error {
+	if s.config.ItemsPerDay <= 0 {
+		return nil
+	}
+
+	now := s.now().UTC()
+	_, err := s.querier.IncrementItemUsage(ctx, db.IncrementItemUsageParams{
+		UserID:   userID,
+		Day:      quotaDay(now),
+		MaxItems: int32(s.config.ItemsPerDay),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.exceeded(QuotaItems, s.config.ItemsPerDay, now)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to count item usage: %w", err)
+	}
+
+	metrics.AddQuotaUsage(QuotaItems, 1)
+	return nil
+}
+
+func (s *quotaService) CheckPodcastMinutes(ctx context.Context, userID int32) error {
+	if s.config.PodcastMinutesPerDay <= 0 {
+		return nil
+	}
+
+	now := s.now().UTC()
+	usage, err := s.querier.GetUsageCounter(ctx, db.GetUsageCounterParams{
+		UserID: userID,
+		Day:    quotaDay(now),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get podcast usage: %w", err)
+	}
+
+	if int(usage.PodcastSeconds) >= s.config.PodcastMinutesPerDay*60 {
+		return s.exceeded(QuotaPodcastMinutes, s.config.PodcastMinutesPerDay, now)
+	}
+	return nil
+}
+
+func (s *quotaService) RecordPodcastSeconds(ctx context.Context, userID int32, seconds int32) error {
+	if seconds <= 0 {
+		return nil
+	}
+
+	err := s.querier.AddPodcastUsage(ctx, db.AddPodcastUsageParams{
+		UserID:         userID,
+		Day:            quotaDay(s.now().UTC()),
+		PodcastSeconds: seconds,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record podcast usage: %w", err)
+	}
+
+	// Usage is reported in the quota's own unit so it lines up with the limit
+	metrics.AddQuotaUsage(QuotaPodcastMinutes, float64(seconds)/60)
+	return nil
+}
+
+// exceeded builds the error for a spent quota, which resets at the next UTC midnight
+func (s *quotaService) exceeded(quota string, limit int, now time.Time) error {
+	metrics.IncrementQuotaExceeded(quota)
+	resetAt := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	return &QuotaExceededError{
+		Quota:      quota,
+		Limit:      limit,
+		RetryAfter: resetAt.Sub(now),
+	}
+}
+
+// quotaDay is the usage_counters day holding usage at t
+func quotaDay(t time.Time) pgtype.Date {
+	year, month, day := t.Date()
+	return pgtype.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Valid: true}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockQuotaService struct {
+	mock.Mock
+}
+
+func (m *MockQuotaService) ConsumeItem(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuotaService) CheckPodcastMinutes(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuotaService) RecordPodcastSeconds(ctx context.Context, userID int32, seconds int32) error {
+	args := m.Called(ctx, userID, seconds)
+	return args.Error(0)
+}
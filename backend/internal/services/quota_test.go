@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func newTestQuotaService(querier db.Querier, config QuotaConfig, now time.Time) *quotaService {
+	service := NewQuotaService(querier, config).(*quotaService)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestConsumeItem(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC)
+	params := db.IncrementItemUsageParams{
+		UserID:   userID,
+		Day:      pgtype.Date{Time: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), Valid: true},
+		MaxItems: 50,
+	}
+
+	t.Run("within quota", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := newTestQuotaService(mockQuerier, QuotaConfig{ItemsPerDay: 50}, now)
+		mockQuerier.On("IncrementItemUsage", ctx, params).Return(int32(12), nil)
+
+		assert.NoError(t, service.ConsumeItem(ctx, userID))
+		mockQuerier.AssertExpectations(t)
+	})
+
+	t.Run("quota used up", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := newTestQuotaService(mockQuerier, QuotaConfig{ItemsPerDay: 50}, now)
+		mockQuerier.On("IncrementItemUsage", ctx, params).Return(int32(0), pgx.ErrNoRows)
+
+		err := service.ConsumeItem(ctx, userID)
+
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		var quotaErr *QuotaExceededError
+		require.ErrorAs(t, err, &quotaErr)
+		assert.Equal(t, QuotaItems, quotaErr.Quota)
+		assert.Equal(t, 6*time.Hour, quotaErr.RetryAfter, "quota resets at midnight UTC")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := newTestQuotaService(mockQuerier, QuotaConfig{ItemsPerDay: 50}, now)
+		mockQuerier.On("IncrementItemUsage", ctx, params).Return(int32(0), errors.New("connection refused"))
+
+		err := service.ConsumeItem(ctx, userID)
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrQuotaExceeded)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := newTestQuotaService(mockQuerier, QuotaConfig{}, now)
+
+		assert.NoError(t, service.ConsumeItem(ctx, userID))
+		mockQuerier.AssertNotCalled(t, "IncrementItemUsage")
+	})
+}
+
+func TestCheckPodcastMinutes(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+	now := time.Date(2026, 3, 4, 23, 30, 0, 0, time.UTC)
+	params := db.GetUsageCounterParams{
+		UserID: userID,
+		Day:    pgtype.Date{Time: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+
+	tests := []struct {
+		name        string
+		usage       db.UsageCounter
+		lookupErr   error
+		expectedErr error
+	}{
+		{name: "no usage today", lookupErr: pgx.ErrNoRows},
+		{name: "minutes left", usage: db.UsageCounter{PodcastSeconds: 29*60 + 59}},
+		{name: "minutes used up", usage: db.UsageCounter{PodcastSeconds: 30 * 60}, expectedErr: ErrQuotaExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			service := newTestQuotaService(mockQuerier, QuotaConfig{PodcastMinutesPerDay: 30}, now)
+			mockQuerier.On("GetUsageCounter", ctx, params).Return(tt.usage, tt.lookupErr)
+
+			err := service.CheckPodcastMinutes(ctx, userID)
+
+			if tt.expectedErr != nil {
+				var quotaErr *QuotaExceededError
+				require.ErrorAs(t, err, &quotaErr)
+				assert.Equal(t, QuotaPodcastMinutes, quotaErr.Quota)
+				assert.Equal(t, 30*time.Minute, quotaErr.RetryAfter)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRecordPodcastSeconds(t *testing.T) {
+	ctx := context.Background()
+	mockQuerier := new(test.MockQuerier)
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	service := newTestQuotaService(mockQuerier, QuotaConfig{PodcastMinutesPerDay: 30}, now)
+
+	mockQuerier.On("AddPodcastUsage", ctx, db.AddPodcastUsageParams{
+		UserID:         1,
+		Day:            pgtype.Date{Time: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), Valid: true},
+		PodcastSeconds: 312,
+	}).Return(nil)
+
+	assert.NoError(t, service.RecordPodcastSeconds(ctx, 1, 312))
+	assert.NoError(t, service.RecordPodcastSeconds(ctx, 1, 0))
+	mockQuerier.AssertNumberOfCalls(t, "AddPodcastUsage", 1)
+}
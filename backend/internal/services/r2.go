@@ -155,6 +155,29 @@ func (r *R2Service) DeleteFiles(ctx context.Context, keys []string) error {
 	return err
 }
 
+// DeletePrefix deletes every file whose key starts with prefix
+func (r *R2Service) DeletePrefix(ctx context.Context, prefix string) error {
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(r.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list files: %w", err)
+		}
+		// A page holds at most 1000 keys, the DeleteObjects limit
+		keys := make([]string, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+		if err := r.DeleteFiles(ctx, keys); err != nil {
+			return fmt.Errorf("failed to delete files: %w", err)
+		}
+	}
+	return nil
+}
+
 // ExtractKeyFromURL extracts the key from a public URL
 func (r *R2Service) ExtractKeyFromURL(url string) string {
 	// Handle custom domain URLs
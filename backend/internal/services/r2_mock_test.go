@@ -33,9 +33,14 @@ func (m *MockR2Service) DeleteFiles(ctx context.Context, keys []string) error {
 	return args.Error(0)
 }
 
-func (m *MockR2Service) ExtractKeyFromURL(url string) (string, error) {
+func (m *MockR2Service) DeletePrefix(ctx context.Context, prefix string) error {
+	args := m.Called(ctx, prefix)
+	return args.Error(0)
+}
+
+func (m *MockR2Service) ExtractKeyFromURL(url string) string {
 	args := m.Called(url)
-	return args.String(0), args.Error(1)
+	return args.String(0)
 }
 
 func (m *MockR2Service) GenerateUploadURLForKey(ctx context.Context, key string, contentType string) (*UploadURLResponse, error) {
@@ -0,0 +1,540 @@
+package services
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Main content extraction in the style of Mozilla's Readability: boilerplate
+// is stripped, paragraphs are scored into their ancestors, and the best
+// scoring element (plus related siblings) is rendered as Markdown.
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)-ad-|ad-break|agegate|banner|breadcrumb|combx|comment|community|consent|cookie|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|modal|navbar|newsletter|pager|pagination|paywall|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skip-link|skyscraper|social|sponsor|subscribe|supplemental|toolbar|widget`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveWeight     = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeWeight     = regexp.MustCompile(`(?i)-ad-|hidden|^hid$|banner|combx|comment|com-|consent|contact|cookie|foot|footer|footnote|gdpr|masthead|media|meta|newsletter|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|subscribe|tags|tool|widget`)
+	whitespaceRun      = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLines         = regexp.MustCompile(`\n{3,}`)
+)
+
+// boilerplateSelector matches elements that never hold article text
+const boilerplateSelector = "script, style, noscript, template, iframe, object, embed, svg, canvas, form, button, input, select, textarea, nav, aside, footer, dialog, link, meta"
+
+// unlikelyRoles are ARIA roles used for page chrome
+var unlikelyRoles = map[string]bool{
+	"alert": true, "alertdialog": true, "banner": true, "complementary": true,
+	"contentinfo": true, "dialog": true, "menu": true, "menubar": true, "navigation": true,
+}
+
+// blockElements start a new Markdown block
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "dd": true, "details": true,
+	"div": true, "dl": true, "dt": true, "figcaption": true, "figure": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true,
+	"hr": true, "li": true, "main": true, "nav": true, "ol": true, "p": true, "pre": true,
+	"section": true, "summary": true, "table": true, "tbody": true, "td": true, "tfoot": true,
+	"th": true, "thead": true, "tr": true, "ul": true,
+}
+
+const (
+	// minParagraphLength is the shortest text worth scoring
+	minParagraphLength = 25
+	// minArticleLength is the shortest extracted article accepted before
+	// falling back to the whole cleaned body
+	minArticleLength = 250
+)
+
+// extractArticle returns the main content of the page as Markdown. It
+// modifies doc, so metadata must be read first.
+func extractArticle(doc *goquery.Document, pageURL *url.URL) string {
+	removeBoilerplate(doc)
+
+	body := doc.Find("body")
+	if body.Length() == 0 {
+		body = doc.Selection
+	}
+
+	var markdown string
+	if nodes := articleNodes(body); len(nodes) > 0 {
+		cleanArticle(nodes)
+		markdown = renderMarkdown(nodes, pageURL)
+	}
+	if len(markdown) < minArticleLength {
+		// Scoring found too little, e.g. on pages built from short fragments
+		if fallback := renderMarkdown(body.Nodes, pageURL); len(fallback) > len(markdown) {
+			markdown = fallback
+		}
+	}
+	return markdown
+}
+
+// removeBoilerplate drops scripts, navigation, hidden elements and anything
+// whose class or id marks it as page chrome
+func removeBoilerplate(doc *goquery.Document) {
+	doc.Find(boilerplateSelector).Remove()
+	doc.Find("[hidden], [aria-hidden='true'], [aria-modal='true']").Remove()
+
+	var unlikely []*html.Node
+	doc.Find("body *").Each(func(_ int, s *goquery.Selection) {
+		tag := goquery.NodeName(s)
+		if tag == "body" || tag == "article" || tag == "main" || tag == "a" {
+			return
+		}
+		if style := strings.ReplaceAll(strings.ToLower(s.AttrOr("style", "")), " ", ""); strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+			unlikely = append(unlikely, s.Nodes[0])
+			return
+		}
+		if unlikelyRoles[s.AttrOr("role", "")] {
+			unlikely = append(unlikely, s.Nodes[0])
+			return
+		}
+		if tag == "header" && s.Closest("article, main").Length() == 0 {
+			unlikely = append(unlikely, s.Nodes[0])
+			return
+		}
+		match := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikelyCandidates.MatchString(match) && !maybeCandidate.MatchString(match) && s.Closest("table").Length() == 0 {
+			unlikely = append(unlikely, s.Nodes[0])
+		}
+	})
+	for _, node := range unlikely {
+		if node.Parent != nil {
+			node.Parent.RemoveChild(node)
+		}
+	}
+}
+
+// articleNodes scores paragraphs into their ancestors and returns the best
+// candidate together with siblings that look like part of the same article
+func articleNodes(body *goquery.Selection) []*html.Node {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+
+	body.Find("p, pre, td, div").Each(func(_ int, s *goquery.Selection) {
+		// Divs only count when they hold text directly rather than other blocks
+		if goquery.NodeName(s) == "div" && hasBlockChild(s.Nodes[0]) {
+			return
+		}
+		text := normalizeSpace(s.Text())
+		if len(text) < minParagraphLength {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text)/100), 3)
+
+		level := 0
+		for ancestor := s.Nodes[0].Parent; ancestor != nil && level < 3; ancestor = ancestor.Parent {
+			if ancestor.Type != html.ElementNode || ancestor.Data == "html" {
+				break
+			}
+			if _, ok := scores[ancestor]; !ok {
+				scores[ancestor] = initialScore(ancestor)
+				candidates = append(candidates, ancestor)
+			}
+			switch level {
+			case 0:
+				scores[ancestor] += score
+			case 1:
+				scores[ancestor] += score / 2
+			default:
+				scores[ancestor] += score / float64(level*3)
+			}
+			level++
+		}
+	})
+
+	var top *html.Node
+	for _, candidate := range candidates {
+		scores[candidate] *= 1 - linkDensity(goquery.NewDocumentFromNode(candidate).Selection)
+		if top == nil || scores[candidate] > scores[top] {
+			top = candidate
+		}
+	}
+	if top == nil {
+		return nil
+	}
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+
+	// Articles are often split across sibling containers
+	threshold := math.Max(10, scores[top]*0.2)
+	var nodes []*html.Node
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if score, ok := scores[sibling]; ok && score >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.Data == "p" {
+			s := goquery.NewDocumentFromNode(sibling).Selection
+			text := normalizeSpace(s.Text())
+			density := linkDensity(s)
+			if (len(text) > 80 && density < 0.25) || (len(text) > 0 && density == 0 && strings.Contains(text, ". ")) {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+	return nodes
+}
+
+// cleanArticle removes link lists and negatively weighted containers left
+// inside the chosen content
+func cleanArticle(nodes []*html.Node) {
+	for _, node := range nodes {
+		var remove []*html.Node
+		goquery.NewDocumentFromNode(node).Find("div, section, ul, ol, table, p").Each(func(_ int, s *goquery.Selection) {
+			weight := classWeight(s.Nodes[0])
+			text := normalizeSpace(s.Text())
+			density := linkDensity(s)
+			switch {
+			case weight < 0 && len(text) < 500:
+				remove = append(remove, s.Nodes[0])
+			case density > 0.5 && weight < 25 && s.Find("img").Length() == 0:
+				remove = append(remove, s.Nodes[0])
+			}
+		})
+		for _, n := range remove {
+			if n.Parent != nil {
+				n.Parent.RemoveChild(n)
+			}
+		}
+	}
+}
+
+func initialScore(node *html.Node) float64 {
+	score := classWeight(node)
+	switch node.Data {
+	case "div", "article", "main":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+	return score
+}
+
+// classWeight rewards class names and ids like "article" or "content" and
+// penalises ones like "comment" or "sidebar"
+func classWeight(node *html.Node) float64 {
+	var weight float64
+	for _, attr := range node.Attr {
+		if attr.Key != "class" && attr.Key != "id" || attr.Val == "" {
+			continue
+		}
+		if negativeWeight.MatchString(attr.Val) {
+			weight -= 25
+		}
+		if positiveWeight.MatchString(attr.Val) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the share of an element's text that sits inside links
+func linkDensity(s *goquery.Selection) float64 {
+	textLength := len(normalizeSpace(s.Text()))
+	if textLength == 0 {
+		return 0
+	}
+	linkLength := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLength += len(normalizeSpace(a.Text()))
+	})
+	return float64(linkLength) / float64(textLength)
+}
+
+func hasBlockChild(node *html.Node) bool {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && (blockElements[child.Data] || child.Data == "img") {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeSpace(text string) string {
+	return strings.TrimSpace(whitespaceRun.ReplaceAllString(text, " "))
+}
+
+// renderMarkdown converts HTML nodes into Markdown, keeping headings,
+// paragraphs, lists, quotes, code blocks, tables, links and images
+func renderMarkdown(nodes []*html.Node, base *url.URL) string {
+	r := &markdownRenderer{base: base}
+	var blocks []string
+	for _, node := range nodes {
+		blocks = append(blocks, r.blocks(node)...)
+	}
+	markdown := strings.Join(blocks, "\n\n")
+	return strings.TrimSpace(blankLines.ReplaceAllString(markdown, "\n\n"))
+}
+
+type markdownRenderer struct {
+	base *url.URL
+}
+
+// blocks renders the children of a node as a list of Markdown blocks
+func (r *markdownRenderer) blocks(node *html.Node) []string {
+	if node.Type == html.ElementNode && blockElements[node.Data] && node.Data != "div" && node.Data != "section" && node.Data != "article" && node.Data != "main" {
+		return r.block(node)
+	}
+
+	var blocks []string
+	var inline strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(collapseInline(inline.String())); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && blockElements[child.Data] {
+			flush()
+			blocks = append(blocks, r.block(child)...)
+			continue
+		}
+		inline.WriteString(r.inline(child))
+	}
+	flush()
+	return blocks
+}
+
+// block renders a single block-level element
+func (r *markdownRenderer) block(node *html.Node) []string {
+	switch node.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := strings.TrimSpace(collapseInline(r.inlineChildren(node)))
+		if text == "" {
+			return nil
+		}
+		level, _ := strconv.Atoi(node.Data[1:])
+		return []string{strings.Repeat("#", level) + " " + text}
+	case "p", "dt", "dd", "figcaption", "summary", "address":
+		if text := strings.TrimSpace(collapseInline(r.inlineChildren(node))); text != "" {
+			return []string{text}
+		}
+		return nil
+	case "hr":
+		return []string{"---"}
+	case "pre":
+		code := strings.Trim(textContent(node), "\n")
+		if strings.TrimSpace(code) == "" {
+			return nil
+		}
+		return []string{"```\n" + code + "\n```"}
+	case "blockquote":
+		inner := r.childBlocks(node)
+		if len(inner) == 0 {
+			return nil
+		}
+		lines := strings.Split(strings.Join(inner, "\n\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return []string{strings.Join(lines, "\n")}
+	case "ul", "ol":
+		return r.list(node)
+	case "li":
+		// A list item outside a list
+		if content := strings.Join(r.childBlocks(node), "\n"); strings.TrimSpace(content) != "" {
+			return []string{"- " + content}
+		}
+		return nil
+	case "table":
+		return r.table(node)
+	default:
+		return r.childBlocks(node)
+	}
+}
+
+func (r *markdownRenderer) childBlocks(node *html.Node) []string {
+	container := &html.Node{Type: html.ElementNode, Data: "div", FirstChild: node.FirstChild, LastChild: node.LastChild}
+	return r.blocks(container)
+}
+
+func (r *markdownRenderer) list(node *html.Node) []string {
+	var items []string
+	index := 1
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if node.Data == "ol" {
+			marker = strconv.Itoa(index) + ". "
+		}
+		index++
+
+		content := strings.Join(r.childBlocks(child), "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(content, "\n")
+		for i := range lines {
+			if i == 0 {
+				lines[i] = marker + lines[i]
+			} else if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		items = append(items, strings.Join(lines, "\n"))
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return []string{strings.Join(items, "\n")}
+}
+
+func (r *markdownRenderer) table(node *html.Node) []string {
+	var rows [][]string
+	goquery.NewDocumentFromNode(node).Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		var cells []string
+		tr.Children().Each(func(_ int, cell *goquery.Selection) {
+			text := strings.TrimSpace(collapseInline(r.inlineChildren(cell.Nodes[0])))
+			cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+		})
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+	})
+	if len(rows) == 0 {
+		return nil
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	var lines []string
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+	return []string{strings.Join(lines, "\n")}
+}
+
+func (r *markdownRenderer) inlineChildren(node *html.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(r.inline(child))
+	}
+	return b.String()
+}
+
+// inline renders text-level content
+func (r *markdownRenderer) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return whitespaceRun.ReplaceAllString(node.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch node.Data {
+	case "br":
+		return "\n"
+	case "a":
+		text := strings.TrimSpace(collapseInline(r.inlineChildren(node)))
+		href := r.link(attr(node, "href"))
+		if text == "" || href == "" {
+			return text
+		}
+		return "[" + text + "](" + href + ")"
+	case "strong", "b":
+		return wrapInline(collapseInline(r.inlineChildren(node)), "**")
+	case "em", "i":
+		return wrapInline(collapseInline(r.inlineChildren(node)), "_")
+	case "code", "kbd", "samp":
+		return wrapInline(textContent(node), "`")
+	case "img":
+		src := r.link(firstNonEmpty(attr(node, "src"), attr(node, "data-src")))
+		if src == "" {
+			return ""
+		}
+		return "![" + strings.TrimSpace(attr(node, "alt")) + "](" + src + ")"
+	}
+
+	text := r.inlineChildren(node)
+	if blockElements[node.Data] {
+		// Block content inside an inline context, e.g. a table cell
+		return " " + text + " "
+	}
+	return text
+}
+
+// link resolves an href, dropping fragments-only and script links
+func (r *markdownRenderer) link(href string) string {
+	if strings.HasPrefix(strings.TrimSpace(href), "#") {
+		return ""
+	}
+	resolved := resolveURL(r.base, href)
+	if resolved == "" || (r.base != nil && !strings.HasPrefix(resolved, "http")) {
+		return ""
+	}
+	return resolved
+}
+
+// wrapInline wraps text in a Markdown marker, keeping surrounding spaces outside it
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+	return leading + marker + trimmed + marker + trailing
+}
+
+// collapseInline tidies spaces around line breaks produced by <br>
+func collapseInline(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.Join(strings.Fields(line), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+func attr(node *html.Node, key string) string {
+	for _, a := range node.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// minStaticWords is the amount of extracted text below which a page may
+	// build its content with JavaScript
+	minStaticWords = 150
+	// minShellWords is the amount below which a page is treated as an empty
+	// shell whatever its markup
+	minShellWords = 30
+	// maxScriptShare is the share of the HTML taken by inline scripts above
+	// which a page is treated as script-driven
+	maxScriptShare = 0.5
+
+	// renderSettleTime bounds the wait, after the load event, for scripts to
+	// stop adding text to the page
+	renderSettleTime = 5 * time.Second
+	// renderPollInterval is how often the page text is measured while settling
+	renderPollInterval = 250 * time.Millisecond
+)
+
+var (
+	// appMountPoint matches the empty element single page apps render into
+	appMountPoint = regexp.MustCompile(`(?is)<(div|main|section)[^>]*\sid=["']?(root|app|__next|___gatsby|svelte|application)["']?[^>]*>\s*</(div|main|section)>`)
+	// noscriptNotice matches <noscript> messages asking for JavaScript
+	noscriptNotice = regexp.MustCompile(`(?is)<noscript[^>]*>[^<]*?(enable|requires?|turn on|need)[^<]{0,40}javascript`)
+	inlineScript   = regexp.MustCompile(`(?is)<script[^>]*>(.*?)</script>`)
+)
+
+// PageRenderer loads a page in a browser and returns its HTML after scripts have run
+type PageRenderer interface {
+	Render(rawURL string) (*FetchedPage, error)
+}
+
+// needsRendering reports whether the statically extracted content of a page
+// looks like the empty shell of a page that builds its content with JavaScript
+func needsRendering(body []byte, result *ScrapeResult) bool {
+	words := len(strings.Fields(result.Content))
+	if words >= minStaticWords {
+		return false
+	}
+	if words < minShellWords {
+		return true
+	}
+	return appMountPoint.Match(body) || noscriptNotice.Match(body) || scriptShare(body) > maxScriptShare
+}
+
+// scriptShare returns the share of the HTML taken by inline scripts
+func scriptShare(body []byte) float64 {
+	if len(body) == 0 {
+		return 0
+	}
+	scripts := 0
+	for _, match := range inlineScript.FindAllSubmatchIndex(body, -1) {
+		scripts += match[3] - match[2]
+	}
+	return float64(scripts) / float64(len(body))
+}
+
+// CDPRenderer renders pages in a headless Chrome over the Chrome DevTools
+// Protocol. Each page gets its own tab, closed once its HTML has been read.
+type CDPRenderer struct {
+	endpoint  *url.URL
+	userAgent string
+	timeout   time.Duration
+	client    *http.Client
+}
+
+// NewCDPRenderer returns a renderer for the Chrome whose DevTools HTTP
+// endpoint, e.g. http://localhost:9222, is given
+func NewCDPRenderer(endpoint, userAgent string, timeout time.Duration) (*CDPRenderer, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid DevTools endpoint %q", endpoint)
+	}
+	if timeout <= 0 {
+		timeout = 20 * time.Second
+	}
+	return &CDPRenderer{
+		endpoint:  parsed,
+		userAgent: userAgent,
+		timeout:   timeout,
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// cdpTarget is a browser tab as listed by the DevTools HTTP endpoint
+type cdpTarget struct {
+	ID                   string `json:"id"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+// Render opens the URL in a new tab, waits for the load event and for the
+// page text to settle, and returns the resulting HTML
+func (r *CDPRenderer) Render(rawURL string) (*FetchedPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	target, err := r.newTarget(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open browser tab: %w", err)
+	}
+	defer r.closeTarget(target.ID)
+
+	session, err := r.connect(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to browser tab: %w", err)
+	}
+	defer session.close()
+
+	if r.userAgent != "" {
+		if err := session.call("Network.setUserAgentOverride", map[string]any{"userAgent": r.userAgent}, nil); err != nil {
+			return nil, err
+		}
+	}
+	if err := session.call("Page.enable", nil, nil); err != nil {
+		return nil, err
+	}
+	var navigation struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := session.call("Page.navigate", map[string]any{"url": rawURL}, &navigation); err != nil {
+		return nil, err
+	}
+	if navigation.ErrorText != "" {
+		return nil, fmt.Errorf("failed to load %s: %s", rawURL, navigation.ErrorText)
+	}
+	if err := session.waitEvent("Page.loadEventFired"); err != nil {
+		return nil, err
+	}
+
+	// Leave time within the timeout to read the HTML
+	settleUntil := time.Now().Add(renderSettleTime)
+	if deadline, _ := ctx.Deadline(); deadline.Add(-time.Second).Before(settleUntil) {
+		settleUntil = deadline.Add(-time.Second)
+	}
+	session.settle(settleUntil)
+
+	var snapshot struct {
+		URL  string `json:"url"`
+		HTML string `json:"html"`
+	}
+	if err := session.evaluate("({url: location.href, html: document.documentElement.outerHTML})", &snapshot); err != nil {
+		return nil, err
+	}
+	pageURL, err := url.Parse(snapshot.URL)
+	if err != nil {
+		return nil, fmt.Errorf("browser returned invalid URL %q: %w", snapshot.URL, err)
+	}
+	return &FetchedPage{
+		URL:         pageURL,
+		StatusCode:  http.StatusOK,
+		ContentType: "text/html; charset=utf-8",
+		Body:        []byte(snapshot.HTML),
+	}, nil
+}
+
+func (r *CDPRenderer) newTarget(ctx context.Context) (*cdpTarget, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.endpoint.JoinPath("json", "new").String()+"?about:blank", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("DevTools endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var target cdpTarget
+	if err := json.NewDecoder(resp.Body).Decode(&target); err != nil {
+		return nil, fmt.Errorf("failed to decode target: %w", err)
+	}
+	if target.ID == "" || target.WebSocketDebuggerURL == "" {
+		return nil, errors.New("DevTools endpoint returned a target without a debugger URL")
+	}
+	return &target, nil
+}
+
+func (r *CDPRenderer) closeTarget(id string) {
+	resp, err := r.client.Get(r.endpoint.JoinPath("json", "close", id).String())
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// connect opens the tab's DevTools WebSocket. The host Chrome reports is
+// replaced by the configured one, which differs when Chrome runs in a container.
+func (r *CDPRenderer) connect(ctx context.Context, target *cdpTarget) (*cdpSession, error) {
+	wsURL, err := url.Parse(target.WebSocketDebuggerURL)
+	if err != nil {
+		return nil, err
+	}
+	wsURL.Host = r.endpoint.Host
+	wsURL.Scheme = "ws"
+	if r.endpoint.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	}
+
+	config, err := websocket.NewConfig(wsURL.String(), r.endpoint.String())
+	if err != nil {
+		return nil, err
+	}
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn.MaxPayloadBytes = maxFetchBodySize
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	return &cdpSession{conn: conn}, nil
+}
+
+// cdpMessage is a DevTools Protocol command response or event
+type cdpMessage struct {
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// cdpSession sends commands to one tab and reads its messages. Commands are
+// sent one at a time; events read while waiting for a response are kept for
+// waitEvent.
+type cdpSession struct {
+	conn   *websocket.Conn
+	nextID int64
+	events []string
+}
+
+func (s *cdpSession) close() {
+	s.conn.Close()
+}
+
+func (s *cdpSession) call(method string, params any, result any) error {
+	s.nextID++
+	command := map[string]any{"id": s.nextID, "method": method}
+	if params != nil {
+		command["params"] = params
+	}
+	if err := websocket.JSON.Send(s.conn, command); err != nil {
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	for {
+		var message cdpMessage
+		if err := websocket.JSON.Receive(s.conn, &message); err != nil {
+			return fmt.Errorf("failed to read %s response: %w", method, err)
+		}
+		if message.ID == 0 {
+			s.events = append(s.events, message.Method)
+			continue
+		}
+		if message.ID != s.nextID {
+			continue
+		}
+		if message.Error != nil {
+			return fmt.Errorf("%s failed: %s", method, message.Error.Message)
+		}
+		if result == nil || len(message.Result) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(message.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", method, err)
+		}
+		return nil
+	}
+}
+
+func (s *cdpSession) waitEvent(method string) error {
+	for _, event := range s.events {
+		if event == method {
+			return nil
+		}
+	}
+	for {
+		var message cdpMessage
+		if err := websocket.JSON.Receive(s.conn, &message); err != nil {
+			return fmt.Errorf("failed waiting for %s: %w", method, err)
+		}
+		if message.ID == 0 && message.Method == method {
+			return nil
+		}
+	}
+}
+
+// evaluate runs a JavaScript expression in the page, decoding its value into result
+func (s *cdpSession) evaluate(expression string, result any) error {
+	var response struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	params := map[string]any{"expression": expression, "returnByValue": true}
+	if err := s.call("Runtime.evaluate", params, &response); err != nil {
+		return err
+	}
+	if response.ExceptionDetails != nil {
+		return fmt.Errorf("script failed: %s", response.ExceptionDetails.Text)
+	}
+	if err := json.Unmarshal(response.Result.Value, result); err != nil {
+		return fmt.Errorf("failed to decode script result: %w", err)
+	}
+	return nil
+}
+
+// settle waits until the length of the page text stops changing between two
+// polls, or until the deadline
+func (s *cdpSession) settle(deadline time.Time) {
+	previous := -1
+	for time.Now().Before(deadline) {
+		var length int
+		if err := s.evaluate("document.body ? document.body.innerText.length : 0", &length); err != nil {
+			return
+		}
+		if length > 0 && length == previous {
+			return
+		}
+		previous = length
+		time.Sleep(renderPollInterval)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// devToolsStub is a minimal Chrome DevTools endpoint serving one tab
+type devToolsStub struct {
+	*httptest.Server
+	html      string // Document returned once the page has loaded
+	errorText string // Navigation error reported by Page.navigate
+
+	mu        sync.Mutex
+	navigated string
+	userAgent string
+	closed    bool
+}
+
+func newDevToolsStub(t *testing.T, html string) *devToolsStub {
+	stub := &devToolsStub{html: html}
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /json/new", func(w http.ResponseWriter, r *http.Request) {
+		// Chrome reports its own address, which the renderer must replace
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"id":                   "tab-1",
+			"webSocketDebuggerUrl": "ws://127.0.0.1:1/devtools/page/tab-1",
+		})
+	})
+	mux.HandleFunc("/json/close/tab-1", func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		stub.closed = true
+		stub.mu.Unlock()
+	})
+	mux.Handle("/devtools/page/tab-1", websocket.Handler(stub.serveTab))
+	stub.Server = httptest.NewServer(mux)
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *devToolsStub) serveTab(conn *websocket.Conn) {
+	for {
+		var command struct {
+			ID     int64          `json:"id"`
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if err := websocket.JSON.Receive(conn, &command); err != nil {
+			return
+		}
+
+		result := map[string]any{}
+		s.mu.Lock()
+		switch command.Method {
+		case "Network.setUserAgentOverride":
+			s.userAgent, _ = command.Params["userAgent"].(string)
+		case "Page.navigate":
+			s.navigated, _ = command.Params["url"].(string)
+			result["frameId"] = "frame-1"
+			if s.errorText != "" {
+				result["errorText"] = s.errorText
+			}
+		case "Runtime.evaluate":
+			expression, _ := command.Params["expression"].(string)
+			if strings.Contains(expression, "innerText.length") {
+				result["result"] = map[string]any{"type": "number", "value": len(s.html)}
+			} else {
+				result["result"] = map[string]any{"type": "object", "value": map[string]string{"url": s.navigated, "html": s.html}}
+			}
+		}
+		navigated := command.Method == "Page.navigate" && s.errorText == ""
+		s.mu.Unlock()
+
+		_ = websocket.JSON.Send(conn, map[string]any{"id": command.ID, "result": result})
+		if navigated {
+			_ = websocket.JSON.Send(conn, map[string]any{"method": "Page.frameNavigated", "params": map[string]any{}})
+			_ = websocket.JSON.Send(conn, map[string]any{"method": "Page.loadEventFired", "params": map[string]any{"timestamp": 1}})
+		}
+	}
+}
+
+// rendererFunc adapts a function to PageRenderer
+type rendererFunc func(rawURL string) (*FetchedPage, error)
+
+func (f rendererFunc) Render(rawURL string) (*FetchedPage, error) {
+	return f(rawURL)
+}
+
+// articleHTML returns a page whose main content has the given number of words
+func articleHTML(title string, words int) string {
+	return "<html><head><title>" + title + "</title></head><body><article><h1>" + title + "</h1><p>" +
+		strings.TrimSpace(strings.Repeat("word ", words)) + "</p></article></body></html>"
+}
+
+func TestCDPRenderer_Render(t *testing.T) {
+	stub := newDevToolsStub(t, articleHTML("Rendered", 200))
+	renderer, err := NewCDPRenderer(stub.URL, "TestBot/1.0", 5*time.Second)
+	require.NoError(t, err)
+
+	page, err := renderer.Render("https://app.example.com/posts/1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/posts/1", page.URL.String())
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+	assert.Contains(t, string(page.Body), "<h1>Rendered</h1>")
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	assert.Equal(t, "TestBot/1.0", stub.userAgent)
+	assert.True(t, stub.closed, "the tab is closed")
+}
+
+func TestCDPRenderer_Render_NavigationError(t *testing.T) {
+	stub := newDevToolsStub(t, "")
+	stub.errorText = "net::ERR_NAME_NOT_RESOLVED"
+	renderer, err := NewCDPRenderer(stub.URL, "", 5*time.Second)
+	require.NoError(t, err)
+
+	page, err := renderer.Render("https://missing.example.com/")
+
+	assert.Nil(t, page)
+	assert.ErrorContains(t, err, "net::ERR_NAME_NOT_RESOLVED")
+}
+
+func TestCDPRenderer_Render_EndpointUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	renderer, err := NewCDPRenderer(server.URL, "", time.Second)
+	require.NoError(t, err)
+
+	_, err = renderer.Render("https://app.example.com/")
+
+	assert.ErrorContains(t, err, "failed to open browser tab")
+}
+
+func TestNewCDPRenderer_InvalidEndpoint(t *testing.T) {
+	_, err := NewCDPRenderer("localhost:9222", "", time.Second)
+	assert.Error(t, err)
+}
+
+func TestNeedsRendering(t *testing.T) {
+	words := func(n int) string { return strings.TrimSpace(strings.Repeat("word ", n)) }
+	tests := []struct {
+		name     string
+		body     string
+		content  string
+		expected bool
+	}{
+		{"long article", "<html><body><div id=\"root\"></div></body></html>", words(200), false},
+		{"empty shell", "<html><body><div id=\"root\"></div></body></html>", "", true},
+		{"near-empty page", "<html><body><p>Loading…</p></body></html>", "Loading…", true},
+		{"short page with mount point", `<html><body><nav>Menu</nav><div id="__next"> </div></body></html>`, words(60), true},
+		{"short page asking for JavaScript", `<html><body><noscript>You need to enable JavaScript to run this app.</noscript></body></html>`, words(60), true},
+		{"short script-heavy page", "<html><body><p>Intro</p><script>" + strings.Repeat("x", 2000) + "</script></body></html>", words(60), true},
+		{"short static page", "<html><body><article><p>A short note.</p></article></body></html>", words(60), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, needsRendering([]byte(tt.body), &ScrapeResult{Content: tt.content}))
+		})
+	}
+}
+
+func TestScrape_RendersJavaScriptPages(t *testing.T) {
+	shell := `<html><head><title>App</title></head><body><div id="root"></div><script src="/app.js"></script></body></html>`
+	scraper := &scrapingService{fetch: staticFetch(t, shell)}
+	var rendered []string
+	scraper.SetRenderer(rendererFunc(func(rawURL string) (*FetchedPage, error) {
+		rendered = append(rendered, rawURL)
+		return &FetchedPage{URL: mustParseURL(t, rawURL), StatusCode: http.StatusOK, Body: []byte(articleHTML("Rendered post", 200))}, nil
+	}))
+
+	result, err := scraper.Scrape("https://app.example.com/posts/1")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com/posts/1"}, rendered)
+	assert.Equal(t, "Rendered post", result.Title)
+	assert.Contains(t, result.Content, "word word word")
+}
+
+func TestScrape_SkipsRenderingForStaticPages(t *testing.T) {
+	scraper := &scrapingService{fetch: staticFetch(t, articleHTML("Static post", 300))}
+	scraper.SetRenderer(rendererFunc(func(rawURL string) (*FetchedPage, error) {
+		t.Errorf("static page %s was rendered", rawURL)
+		return nil, nil
+	}))
+
+	result, err := scraper.Scrape("https://blog.example.com/posts/1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Static post", result.Title)
+}
+
+func TestScrape_KeepsStaticContentWhenRenderingFails(t *testing.T) {
+	scraper := &scrapingService{fetch: staticFetch(t, articleHTML("Teaser", 25))}
+	rendered := false
+	scraper.SetRenderer(rendererFunc(func(rawURL string) (*FetchedPage, error) {
+		rendered = true
+		return nil, assert.AnError
+	}))
+
+	result, err := scraper.Scrape("https://app.example.com/posts/1")
+
+	require.NoError(t, err)
+	assert.True(t, rendered)
+	assert.Equal(t, "Teaser", result.Title)
+}
+
+// staticFetch returns a fetcher serving the same HTML for every URL
+func staticFetch(t *testing.T, html string) PageFetcher {
+	return func(rawURL string) (*FetchedPage, error) {
+		return &FetchedPage{URL: mustParseURL(t, rawURL), StatusCode: http.StatusOK, ContentType: "text/html", Body: []byte(html)}, nil
+	}
+}
@@ -1,33 +1,248 @@
 package services
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
 	"strings"
+	"time"
 
-	"github.com/gocolly/colly"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yamirghofran/briefbot/internal/metrics"
 )
 
+// ScrapeResult is the main content of a page plus the metadata it declares
+type ScrapeResult struct {
+	URL         string     `json:"url"`                    // Final URL after redirects
+	Title       string     `json:"title"`                  // From OpenGraph, JSON-LD or <title>
+	Content     string     `json:"content"`                // Main content as Markdown, boilerplate removed
+	Excerpt     string     `json:"excerpt,omitempty"`      // Page description or first paragraph
+	Authors     []string   `json:"authors,omitempty"`      // From JSON-LD, OpenGraph or <meta> tags
+	PublishedAt *time.Time `json:"published_at,omitempty"` // Publication date when the page declares one
+	SiteName    string     `json:"site_name,omitempty"`
+	ImageURL    string     `json:"image_url,omitempty"`
+	Language    string     `json:"language,omitempty"`
+	// From <link rel=canonical>; identifies the page when it has several URLs
+	CanonicalURL string `json:"canonical_url,omitempty"`
+	// Set by platform extractors, using the ItemExtraction platform and type values
+	Platform string `json:"platform,omitempty"`
+	Type     string `json:"type,omitempty"`
+	PDFURL   string `json:"pdf_url,omitempty"` // Full text of papers
+}
+
 type ScrapingService interface {
-	Scrape(url string) (string, error)
+	Scrape(url string) (*ScrapeResult, error)
+}
+
+// FetchedPage is a downloaded page
+type FetchedPage struct {
+	URL         *url.URL // Final URL after redirects
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	// Validators for revalidating the page with a conditional request
+	ETag         string
+	LastModified string
 }
 
-type scrapingService struct{}
+// PageFetcher downloads a URL, returning an error for failed requests
+type PageFetcher func(rawURL string) (*FetchedPage, error)
+
+type scrapingService struct {
+	fetch      PageFetcher
+	fetcher    *Fetcher // Checks robots.txt when set
+	extractors *ExtractorRegistry
+	renderer   PageRenderer // Renders pages whose content is built by JavaScript, when set
+}
 
 func NewScraper() *scrapingService {
-	return &scrapingService{}
+	return NewScraperWithFetcher(NewFetcher(DefaultFetcherConfig()))
 }
 
-func (s *scrapingService) Scrape(url string) (string, error) {
-	c := colly.NewCollector()
-	var content strings.Builder
+// NewScraperWithFetcher returns a scraper that downloads pages through a
+// shared fetcher, so its per-host limits and cache cover every scrape
+func NewScraperWithFetcher(fetcher *Fetcher) *scrapingService {
+	return &scrapingService{
+		fetch:      fetcher.Fetch,
+		fetcher:    fetcher,
+		extractors: DefaultExtractorRegistry(),
+	}
+}
 
-	c.OnHTML("body", func(e *colly.HTMLElement) {
-		content.WriteString(e.Text)
-	})
+// SetExtractorRegistry replaces the platform extractors; nil disables them
+func (s *scrapingService) SetExtractorRegistry(registry *ExtractorRegistry) {
+	s.extractors = registry
+}
+
+// SetRenderer enables rendering pages in a browser when their static HTML
+// yields too little text; nil disables it
+func (s *scrapingService) SetRenderer(renderer PageRenderer) {
+	s.renderer = renderer
+}
 
-	err := c.Visit(url)
+func (s *scrapingService) Scrape(rawURL string) (*ScrapeResult, error) {
+	metrics.IncrementScrapingRequests()
+	result, err := s.scrape(rawURL)
 	if err != nil {
-		return "", err
+		metrics.IncrementScrapingFailures(FailureReason(err))
+		return nil, err
 	}
+	return result, nil
+}
 
-	return content.String(), nil
+func (s *scrapingService) scrape(rawURL string) (*ScrapeResult, error) {
+	// Only the submitted page is checked; platform extractors call the
+	// sites' APIs, which robots.txt files commonly disallow for crawlers
+	if s.fetcher != nil {
+		if err := s.fetcher.CheckRobots(rawURL); err != nil {
+			return nil, err
+		}
+	}
+
+	if parsed, err := url.Parse(rawURL); err == nil && s.extractors != nil {
+		if name, extractor, ok := s.extractors.Lookup(parsed); ok {
+			result, err := extractor.Extract(parsed, s.fetch)
+			if err == nil {
+				return result, nil
+			}
+			// Platform pages change often; the generic extractor still gets the content
+			if !errors.Is(err, ErrExtractorNotApplicable) {
+				log.Printf("%s extractor failed for %s, using generic scraper: %v", name, rawURL, err)
+			}
+		}
+	}
+
+	page, err := s.fetch(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if isPDF(page) {
+		result, err := ParsePDF(page.Body)
+		if err != nil {
+			return nil, err
+		}
+		result.URL = page.URL.String()
+		return result, nil
+	}
+
+	result, err := ParseHTML(page.Body, page.URL)
+	if err != nil {
+		return nil, err
+	}
+	if s.renderer != nil && needsRendering(page.Body, result) {
+		renderedPage, rendered, err := s.render(rawURL)
+		if err != nil {
+			log.Printf("Failed to render %s, using static content: %v", rawURL, err)
+		} else if len(strings.Fields(rendered.Content)) > len(strings.Fields(result.Content)) {
+			page, result = renderedPage, rendered
+		}
+	}
+
+	if err := detectBlockedPage(page.Body, result); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, rawURL)
+	}
+	return result, nil
+}
+
+// render loads the page in the browser, counting against the host's limits
+func (s *scrapingService) render(rawURL string) (*FetchedPage, *ScrapeResult, error) {
+	if s.fetcher != nil {
+		if u, err := url.Parse(rawURL); err == nil {
+			release := s.fetcher.wait(s.fetcher.host(u.Host))
+			defer release()
+		}
+	}
+	page, err := s.renderer.Render(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := ParseHTML(page.Body, page.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page, result, nil
+}
+
+// ParseHTML extracts the main content and metadata from an HTML page.
+// pageURL resolves relative links and images.
+func ParseHTML(body []byte, pageURL *url.URL) (*ScrapeResult, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	// Metadata lives in <head> and scripts, so read it before the page is cleaned
+	meta := extractPageMetadata(doc, pageURL)
+	return newScrapeResult(meta, extractArticle(doc, pageURL), pageURL), nil
+}
+
+// newScrapeResult combines page metadata with extracted Markdown content
+func newScrapeResult(meta pageMetadata, content string, pageURL *url.URL) *ScrapeResult {
+	result := &ScrapeResult{
+		Title:        meta.Title,
+		Content:      content,
+		Excerpt:      meta.Description,
+		Authors:      meta.Authors,
+		PublishedAt:  meta.PublishedAt,
+		SiteName:     meta.SiteName,
+		ImageURL:     meta.ImageURL,
+		Language:     meta.Language,
+		CanonicalURL: meta.CanonicalURL,
+	}
+	if pageURL != nil {
+		result.URL = pageURL.String()
+	}
+	if result.Excerpt == "" {
+		result.Excerpt = firstParagraph(content, maxExcerptLength)
+	}
+	return result
+}
+
+const maxExcerptLength = 300
+
+// firstParagraph returns the first prose paragraph of Markdown content, cut to maxLength
+func firstParagraph(markdown string, maxLength int) string {
+	for _, block := range strings.Split(markdown, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" || strings.ContainsAny(block[:1], "#>-|`!") {
+			continue
+		}
+		return truncateText(block, maxLength)
+	}
+	return ""
+}
+
+// truncateText cuts text to at most maxLength bytes at a word boundary
+func truncateText(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	cut := strings.LastIndex(text[:maxLength], " ")
+	if cut <= 0 {
+		cut = maxLength
+	}
+	return strings.TrimSpace(text[:cut]) + "…"
+}
+
+// applyScrapeMetadata fills gaps in the AI extraction with metadata the page
+// declares. Authors from the page are preferred because models often guess
+// them, as are the platform and type set by platform extractors.
+func applyScrapeMetadata(extraction *ItemExtraction, result *ScrapeResult) {
+	if result == nil {
+		return
+	}
+	if extraction.Title == "" {
+		extraction.Title = result.Title
+	}
+	if len(result.Authors) > 0 {
+		extraction.Authors = result.Authors
+	}
+	if result.Platform != "" {
+		extraction.Platform = result.Platform
+	}
+	if result.Type != "" {
+		extraction.Type = result.Type
+	}
 }
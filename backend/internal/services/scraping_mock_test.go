@@ -8,7 +8,10 @@ type MockScrapingService struct {
 	mock.Mock
 }
 
-func (m *MockScrapingService) Scrape(url string) (string, error) {
+func (m *MockScrapingService) Scrape(url string) (*ScrapeResult, error) {
 	args := m.Called(url)
-	return args.String(0), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ScrapeResult), args.Error(1)
 }
@@ -1,9 +1,15 @@
 package services
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -44,14 +50,14 @@ func TestScrapingServiceScrape(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			content, err := scraper.Scrape(tc.url)
+			result, err := scraper.Scrape(tc.url)
 
 			require.NoError(t, err)
-			assert.NotEmpty(t, content)
+			assert.NotEmpty(t, result.Content)
 
 			// Check that expected content is present
 			for _, expected := range tc.contains {
-				assert.True(t, strings.Contains(content, expected),
+				assert.True(t, strings.Contains(result.Content, expected),
 					"Content should contain '%s'", expected)
 			}
 		})
@@ -62,11 +68,11 @@ func TestScrapingServiceScrape(t *testing.T) {
 func TestScrapeBlogPost(t *testing.T) {
 	scraper := NewScraper()
 
-	content, err := scraper.Scrape("https://amirghofran.com/blog/how-i-study/")
+	result, err := scraper.Scrape("https://amirghofran.com/blog/how-i-study/")
 
 	require.NoError(t, err)
-	assert.NotEmpty(t, content)
-	assert.True(t, strings.Contains(content, "study") || strings.Contains(content, "Study"),
+	assert.NotEmpty(t, result.Content)
+	assert.True(t, strings.Contains(result.Content, "study") || strings.Contains(result.Content, "Study"),
 		"Content should contain 'study'")
 }
 
@@ -96,18 +102,150 @@ func TestScrapingServiceErrorHandling(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			content, err := scraper.Scrape(tc.url)
+			result, err := scraper.Scrape(tc.url)
 
 			if tc.expectError {
 				assert.Error(t, err)
-				assert.Empty(t, content)
+				assert.Nil(t, result)
 				if tc.errorMsg != "" {
 					assert.Contains(t, err.Error(), tc.errorMsg)
 				}
 			} else {
 				assert.NoError(t, err)
-				assert.NotEmpty(t, content)
+				assert.NotEmpty(t, result.Content)
 			}
 		})
 	}
 }
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	parsed, err := url.Parse(raw)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestParseHTML_ArticleFixture(t *testing.T) {
+	body, err := os.ReadFile("testdata/article.html")
+	require.NoError(t, err)
+
+	result, err := ParseHTML(body, mustParseURL(t, "https://planner.example.com/posts/tiny-indexes"))
+	require.NoError(t, err)
+
+	t.Run("metadata", func(t *testing.T) {
+		assert.Equal(t, "https://planner.example.com/posts/tiny-indexes", result.URL)
+		assert.Equal(t, "Why Tiny Indexes Win", result.Title)
+		assert.Equal(t, "A look at partial indexes in Postgres.", result.Excerpt)
+		assert.Equal(t, []string{"Ada Lovelace", "Grace Hopper"}, result.Authors, "JSON-LD authors take precedence over <meta name=author>")
+		require.NotNil(t, result.PublishedAt)
+		assert.Equal(t, time.Date(2025, 3, 14, 8, 30, 0, 0, time.UTC), *result.PublishedAt)
+		assert.Equal(t, "The Query Planner", result.SiteName)
+		assert.Equal(t, "https://planner.example.com/images/cover.png", result.ImageURL)
+		assert.Equal(t, "en", result.Language)
+	})
+
+	t.Run("content keeps structure as Markdown", func(t *testing.T) {
+		assert.Contains(t, result.Content, "# Why Tiny Indexes Win")
+		assert.Contains(t, result.Content, "## The problem with full indexes")
+		assert.Contains(t, result.Content, "only ask for the **pending** ones")
+		assert.Contains(t, result.Content, "[official documentation](https://planner.example.com/docs/partial-indexes)")
+		assert.Contains(t, result.Content, "- Smaller indexes fit in memory\n- Writes to other rows skip the index")
+		assert.Contains(t, result.Content, "```\nCREATE INDEX items_pending ON items (created_at)\n  WHERE status = 'pending';\n```")
+		assert.Contains(t, result.Content, "> Index what you query, not what you store.")
+		assert.Contains(t, result.Content, "![Query plan](https://planner.example.com/images/plan.png)")
+	})
+
+	t.Run("boilerplate is removed", func(t *testing.T) {
+		for _, boilerplate := range []string{"cookies", "Archive", "Share on Twitter", "Great post", "Popular posts", "Copyright", "analytics", "font-family"} {
+			assert.NotContains(t, result.Content, boilerplate)
+		}
+	})
+}
+
+func TestParseHTML_MetadataFallbacks(t *testing.T) {
+	page := `<html><head>
+		<title>Plain Title</title>
+		<meta name="author" content="By Jane Doe">
+		<meta name="citation_publication_date" content="2024/06/01">
+		<link rel="canonical" href="/posts/plain-title">
+	</head><body><p>Short body.</p></body></html>`
+
+	result, err := ParseHTML([]byte(page), mustParseURL(t, "https://example.com/post"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Plain Title", result.Title)
+	assert.Equal(t, []string{"Jane Doe"}, result.Authors)
+	require.NotNil(t, result.PublishedAt)
+	assert.Equal(t, "2024-06-01", result.PublishedAt.Format("2006-01-02"))
+	assert.Equal(t, "Short body.", result.Content, "short pages fall back to the cleaned body")
+	assert.Equal(t, "Short body.", result.Excerpt)
+	assert.Equal(t, "https://example.com/posts/plain-title", result.CanonicalURL)
+}
+
+func TestParseHTML_IgnoresInvalidJSONLD(t *testing.T) {
+	page := `<html><head>
+		<script type="application/ld+json">{not json</script>
+		<script type="application/ld+json">{"@type": "NewsArticle", "headline": "From JSON-LD", "author": "Sam Reporter"}</script>
+	</head><body><p>Body</p></body></html>`
+
+	result, err := ParseHTML([]byte(page), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "From JSON-LD", result.Title)
+	assert.Equal(t, []string{"Sam Reporter"}, result.Authors)
+	assert.Nil(t, result.PublishedAt)
+}
+
+func TestRenderMarkdown_Tables(t *testing.T) {
+	page := `<table><tr><th>Plan</th><th>Cost</th></tr><tr><td>Seq Scan</td><td>1|2</td></tr></table>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	require.NoError(t, err)
+
+	markdown := renderMarkdown(doc.Find("table").Nodes, nil)
+
+	assert.Equal(t, "| Plan | Cost |\n| --- | --- |\n| Seq Scan | 1\\|2 |", markdown)
+}
+
+func TestScrapingServiceScrape_LocalServer(t *testing.T) {
+	body, err := os.ReadFile("testdata/article.html")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/posts/tiny-indexes", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/posts/tiny-indexes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(body)
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scraper := NewScraper()
+
+	result, err := scraper.Scrape(server.URL + "/old")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/posts/tiny-indexes", result.URL)
+	assert.Equal(t, "Why Tiny Indexes Win", result.Title)
+	assert.Contains(t, result.Content, "## The problem with full indexes")
+
+	result, err = scraper.Scrape(server.URL + "/missing")
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestApplyScrapeMetadata(t *testing.T) {
+	extraction := ItemExtraction{Authors: []string{"Guessed Author"}}
+
+	applyScrapeMetadata(&extraction, &ScrapeResult{Title: "Page Title", Authors: []string{"Real Author"}})
+
+	assert.Equal(t, "Page Title", extraction.Title)
+	assert.Equal(t, []string{"Real Author"}, extraction.Authors)
+
+	extraction = ItemExtraction{Title: "AI Title", Authors: []string{"Guessed Author"}}
+	applyScrapeMetadata(&extraction, &ScrapeResult{Title: "Page Title"})
+
+	assert.Equal(t, "AI Title", extraction.Title)
+	assert.Equal(t, []string{"Guessed Author"}, extraction.Authors)
+}
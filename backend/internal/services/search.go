@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// ErrInvalidSearch is returned for searches without a query or with contradictory filters
+var ErrInvalidSearch = errors.New("invalid search")
+
+// Search page sizes
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// ItemSearch is a full-text search over the items a user can see. Nil and
+// empty filters match every item.
+type ItemSearch struct {
+	// Query uses web search syntax: "quoted phrases", OR and -excluded words
+	Query string
+	// Tags must all be present on an item. They are normalized and resolved
+	// through the user's aliases as extracted tags are.
+	Tags          []string
+	Platform      *string
+	Type          *string
+	IsRead        *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Offset        int
+}
+
+// SearchResult is an item matching a search with its rank and highlighted
+// matches. Highlights are HTML with matches wrapped in <mark> and all other
+// markup escaped.
+type SearchResult struct {
+	Item           db.Item
+	IsRead         bool
+	Rank           float32
+	TitleHighlight string
+	Snippet        string
+}
+
+// SearchItems finds the items a user can see that match a query, best
+// matches first. Titles weigh most, then tags and summaries, then authors,
+// then the text.
+func (s *itemService) SearchItems(ctx context.Context, userID int32, search ItemSearch) ([]SearchResult, error) {
+	query := strings.TrimSpace(search.Query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: a query is required", ErrInvalidSearch)
+	}
+	if search.CreatedAfter != nil && search.CreatedBefore != nil && !search.CreatedAfter.Before(*search.CreatedBefore) {
+		return nil, fmt.Errorf("%w: the date range is empty", ErrInvalidSearch)
+	}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+
+	var tags []string
+	if len(search.Tags) > 0 {
+		var err error
+		if tags, err = filterTags(ctx, s.querier, userID, search.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.querier.SearchItems(ctx, db.SearchItemsParams{
+		UserID:        userID,
+		Query:         query,
+		Tags:          tags,
+		Platform:      search.Platform,
+		Type:          search.Type,
+		IsRead:        search.IsRead,
+		CreatedAfter:  search.CreatedAfter,
+		CreatedBefore: search.CreatedBefore,
+		Limit:         int32(limit),
+		Offset:        int32(max(search.Offset, 0)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search items: %w", err)
+	}
+
+	results := make([]SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = SearchResult{
+			Item:           row.Item,
+			IsRead:         row.IsRead,
+			Rank:           row.Rank,
+			TitleHighlight: escapeHighlight(row.TitleHighlight),
+			Snippet:        escapeHighlight(row.Snippet),
+		}
+	}
+	return results, nil
+}
+
+// escapeHighlight escapes a ts_headline result for use as HTML, keeping only
+// the <mark> tags it added around matches
+func escapeHighlight(highlight string) string {
+	escaped := html.EscapeString(highlight)
+	escaped = strings.ReplaceAll(escaped, "&lt;mark&gt;", "<mark>")
+	return strings.ReplaceAll(escaped, "&lt;/mark&gt;", "</mark>")
+}
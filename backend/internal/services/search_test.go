@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func TestSearchItems(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	ctx := context.Background()
+	platform := "web"
+	isRead := false
+	after := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockQuerier.On("ResolveTagAliases", ctx, db.ResolveTagAliasesParams{UserID: 1, Aliases: []string{"databases"}}).
+		Return([]db.ResolveTagAliasesRow{}, nil)
+	mockQuerier.On("SearchItems", ctx, db.SearchItemsParams{
+		UserID:       1,
+		Query:        "postgres tuning",
+		Tags:         []string{"databases"},
+		Platform:     &platform,
+		IsRead:       &isRead,
+		CreatedAfter: &after,
+		Limit:        MaxSearchLimit,
+	}).Return([]db.SearchItemsRow{{
+		Item:           db.Item{ID: 4, Title: "Tuning Postgres"},
+		Rank:           0.5,
+		TitleHighlight: "Tuning <mark>Postgres</mark>",
+		Snippet:        "use <b>EXPLAIN</b> before <mark>tuning</mark>",
+	}}, nil)
+
+	results, err := service.SearchItems(ctx, 1, ItemSearch{
+		Query:        "  postgres tuning ",
+		Tags:         []string{" databases", "Databases"},
+		Platform:     &platform,
+		IsRead:       &isRead,
+		CreatedAfter: &after,
+		Limit:        500,
+		Offset:       -3,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int32(4), results[0].Item.ID)
+	assert.Equal(t, "Tuning <mark>Postgres</mark>", results[0].TitleHighlight)
+	assert.Equal(t, "use &lt;b&gt;EXPLAIN&lt;/b&gt; before <mark>tuning</mark>", results[0].Snippet, "markup from the item is escaped")
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestSearchItems_Invalid(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.SearchItems(context.Background(), 1, ItemSearch{Query: "   "})
+	assert.ErrorIs(t, err, ErrInvalidSearch)
+
+	_, err = service.SearchItems(context.Background(), 1, ItemSearch{Query: "go", CreatedAfter: &day, CreatedBefore: &day})
+	assert.ErrorIs(t, err, ErrInvalidSearch)
+
+	mockQuerier.AssertNotCalled(t, "SearchItems", mock.Anything, mock.Anything)
+}
+
+func TestSearchItems_DefaultLimit(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewItemService(mockQuerier, nil, nil, nil)
+	ctx := context.Background()
+
+	mockQuerier.On("SearchItems", ctx, db.SearchItemsParams{UserID: 1, Query: "go", Limit: DefaultSearchLimit, Offset: 40}).
+		Return([]db.SearchItemsRow{}, nil)
+
+	results, err := service.SearchItems(ctx, 1, ItemSearch{Query: "go", Offset: 40})
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	mockQuerier.AssertExpectations(t)
+}
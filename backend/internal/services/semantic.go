@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// ErrEmbeddingNotReady is returned for related items of an item that has not been embedded yet
+var ErrEmbeddingNotReady = errors.New("item has not been embedded yet")
+
+// SemanticConfig controls how items are embedded
+type SemanticConfig struct {
+	// BatchSize is the number of texts sent in one embeddings request
+	BatchSize int
+	// MaxInputChars truncates the text embedded for an item or query
+	MaxInputChars int
+	// MaxCandidates caps the embeddings compared per search, newest items
+	// first, so the cost of a search stays bounded as a library grows
+	MaxCandidates int
+}
+
+// embeddingPageSize is the number of embeddings loaded at once during a search
+const embeddingPageSize = 1000
+
+// DefaultSemanticConfig returns the default embedding settings
+func DefaultSemanticConfig() SemanticConfig {
+	return SemanticConfig{
+		BatchSize:     32,
+		MaxInputChars: 8000,
+		MaxCandidates: 20000,
+	}
+}
+
+// SemanticResult is an item close in meaning to a query or another item.
+// Score is the cosine similarity of their embeddings, at most 1.
+type SemanticResult struct {
+	Item   db.Item
+	IsRead bool
+	Score  float32
+}
+
+// SemanticService embeds completed items and finds items by meaning rather
+// than by keyword. Vectors are kept unit-length in item_embeddings and
+// compared in memory, page by page, so searches scan up to MaxCandidates of
+// the most recent completed items the user can see.
+type SemanticService interface {
+	// EmbedPendingItems embeds up to limit completed items that have no
+	// current embedding and returns how many it stored
+	EmbedPendingItems(ctx context.Context, limit int32) (int, error)
+	SearchItems(ctx context.Context, userID int32, query string, limit int) ([]SemanticResult, error)
+	RelatedItems(ctx context.Context, userID int32, itemID int32, limit int) ([]SemanticResult, error)
+}
+
+type semanticService struct {
+	querier          db.Querier
+	embeddingService EmbeddingService
+	config           SemanticConfig
+
+	// embedMu keeps concurrent workers from embedding the same items
+	embedMu sync.Mutex
+}
+
+func NewSemanticService(querier db.Querier, embeddingService EmbeddingService, config SemanticConfig) SemanticService {
+	defaults := DefaultSemanticConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.MaxInputChars <= 0 {
+		config.MaxInputChars = defaults.MaxInputChars
+	}
+	if config.MaxCandidates <= 0 {
+		config.MaxCandidates = defaults.MaxCandidates
+	}
+	return &semanticService{
+		querier:          querier,
+		embeddingService: embeddingService,
+		config:           config,
+	}
+}
+
+func (s *semanticService) EmbedPendingItems(ctx context.Context, limit int32) (int, error) {
+	if !s.embedMu.TryLock() {
+		return 0, nil
+	}
+	defer s.embedMu.Unlock()
+
+	items, err := s.querier.ListItemsToEmbed(ctx, db.ListItemsToEmbedParams{
+		Model: s.embeddingService.Model(),
+		Limit: limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list items to embed: %w", err)
+	}
+
+	stored := 0
+	for batch := range slices.Chunk(items, s.config.BatchSize) {
+		n, err := s.embedItems(ctx, batch)
+		stored += n
+		if err != nil {
+			return stored, err
+		}
+	}
+	if stored > 0 {
+		log.Printf("Embedded %d items", stored)
+	}
+	return stored, nil
+}
+
+// embedItems embeds a batch of items and stores their vectors. When the API
+// rejects the batch the items are retried one by one, so a single bad input
+// is recorded as failed instead of holding back the rest.
+func (s *semanticService) embedItems(ctx context.Context, items []db.Item) (int, error) {
+	inputs := make([]string, len(items))
+	for i, item := range items {
+		inputs[i] = s.itemInput(item)
+	}
+
+	vectors, err := s.embeddingService.Embed(ctx, inputs)
+	if err != nil {
+		if !isRejectedEmbeddingInput(err) {
+			return 0, err
+		}
+		if len(items) == 1 {
+			log.Printf("Embeddings API rejected item %d: %v", items[0].ID, err)
+			return 0, s.storeEmbedding(ctx, items[0].ID, nil, err.Error())
+		}
+		stored := 0
+		for _, item := range items {
+			n, err := s.embedItems(ctx, []db.Item{item})
+			stored += n
+			if err != nil {
+				return stored, err
+			}
+		}
+		return stored, nil
+	}
+
+	for i, item := range items {
+		if err := s.storeEmbedding(ctx, item.ID, normalizeVector(vectors[i]), ""); err != nil {
+			return i, err
+		}
+	}
+	return len(items), nil
+}
+
+func (s *semanticService) storeEmbedding(ctx context.Context, itemID int32, vector []float32, embedErr string) error {
+	params := db.UpsertItemEmbeddingParams{
+		ItemID:    itemID,
+		Model:     s.embeddingService.Model(),
+		Embedding: vector,
+	}
+	if embedErr != "" {
+		params.Error = &embedErr
+	}
+	if err := s.querier.UpsertItemEmbedding(ctx, params); err != nil {
+		return fmt.Errorf("failed to store embedding of item %d: %w", itemID, err)
+	}
+	return nil
+}
+
+// itemInput is the text embedded for an item: its title, tags, summary and
+// the start of its content
+func (s *semanticService) itemInput(item db.Item) string {
+	parts := []string{item.Title}
+	if len(item.Tags) > 0 {
+		parts = append(parts, strings.Join(item.Tags, ", "))
+	}
+	if item.Summary != nil {
+		parts = append(parts, *item.Summary)
+	}
+	if item.TextContent != nil {
+		parts = append(parts, *item.TextContent)
+	}
+
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	input := strings.Join(nonEmpty, "\n\n")
+	if input == "" {
+		// The API rejects empty inputs
+		input = fmt.Sprintf("item %d", item.ID)
+	}
+	return truncateRunes(input, s.config.MaxInputChars)
+}
+
+func (s *semanticService) SearchItems(ctx context.Context, userID int32, query string, limit int) ([]SemanticResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: a query is required", ErrInvalidSearch)
+	}
+
+	vectors, err := s.embeddingService.Embed(ctx, []string{truncateRunes(query, s.config.MaxInputChars)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	return s.nearestItems(ctx, userID, normalizeVector(vectors[0]), 0, limit)
+}
+
+func (s *semanticService) RelatedItems(ctx context.Context, userID int32, itemID int32, limit int) ([]SemanticResult, error) {
+	if _, err := s.querier.GetItemVisibleToUser(ctx, db.GetItemVisibleToUserParams{ID: itemID, UserID: &userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	embedding, err := s.querier.GetItemEmbedding(ctx, db.GetItemEmbeddingParams{
+		ItemID: itemID,
+		Model:  s.embeddingService.Model(),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEmbeddingNotReady
+		}
+		return nil, fmt.Errorf("failed to get item embedding: %w", err)
+	}
+	if embedding.Embedding == nil {
+		return nil, ErrEmbeddingNotReady
+	}
+	return s.nearestItems(ctx, userID, embedding.Embedding, itemID, limit)
+}
+
+// nearestItems returns the items the user can see whose embeddings are
+// closest to vector, best first, leaving out excludeID. Embeddings are loaded
+// a page at a time and only the best limit matches are kept between pages.
+func (s *semanticService) nearestItems(ctx context.Context, userID int32, vector []float32, excludeID int32, limit int) ([]SemanticResult, error) {
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+
+	type match struct {
+		itemID int32
+		score  float32
+	}
+	byScore := func(a, b match) int {
+		if a.score != b.score {
+			if a.score > b.score {
+				return -1
+			}
+			return 1
+		}
+		return int(a.itemID) - int(b.itemID)
+	}
+
+	var matches []match
+	var beforeID *int32
+	for scanned := 0; scanned < s.config.MaxCandidates; {
+		candidates, err := s.querier.ListItemEmbeddingsVisibleToUser(ctx, db.ListItemEmbeddingsVisibleToUserParams{
+			Model:    s.embeddingService.Model(),
+			UserID:   &userID,
+			BeforeID: beforeID,
+			Limit:    int32(min(embeddingPageSize, s.config.MaxCandidates-scanned)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list item embeddings: %w", err)
+		}
+		for _, candidate := range candidates {
+			if candidate.ItemID == excludeID || len(candidate.Embedding) != len(vector) {
+				continue
+			}
+			matches = append(matches, match{itemID: candidate.ItemID, score: dotProduct(vector, candidate.Embedding)})
+		}
+		slices.SortFunc(matches, byScore)
+		matches = matches[:min(limit, len(matches))]
+
+		scanned += len(candidates)
+		if len(candidates) < embeddingPageSize {
+			break
+		}
+		beforeID = &candidates[len(candidates)-1].ItemID
+	}
+	if len(matches) == 0 {
+		return []SemanticResult{}, nil
+	}
+
+	ids := make([]int32, len(matches))
+	for i, m := range matches {
+		ids[i] = m.itemID
+	}
+	rows, err := s.querier.GetItemsVisibleToUserByIDs(ctx, db.GetItemsVisibleToUserByIDsParams{
+		UserID:  userID,
+		ItemIds: ids,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+	byID := make(map[int32]db.GetItemsVisibleToUserByIDsRow, len(rows))
+	for _, row := range rows {
+		byID[row.Item.ID] = row
+	}
+
+	results := make([]SemanticResult, 0, len(matches))
+	for _, m := range matches {
+		// Items deleted since their embeddings were listed are skipped
+		if row, ok := byID[m.itemID]; ok {
+			results = append(results, SemanticResult{Item: row.Item, IsRead: row.IsRead, Score: m.score})
+		}
+	}
+	return results, nil
+}
+
+// normalizeVector scales a vector to unit length, so the dot product of two
+// normalized vectors is their cosine similarity
+func normalizeVector(vector []float32) []float32 {
+	var sum float64
+	for _, value := range vector {
+		sum += float64(value) * float64(value)
+	}
+	if sum == 0 {
+		return vector
+	}
+	norm := math.Sqrt(sum)
+	normalized := make([]float32, len(vector))
+	for i, value := range vector {
+		normalized[i] = float32(float64(value) / norm)
+	}
+	return normalized
+}
+
+func dotProduct(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// truncateRunes cuts s to at most n runes
+func truncateRunes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmbeddingService struct {
+	mock.Mock
+}
+
+func (m *MockEmbeddingService) Embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	args := m.Called(ctx, inputs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]float32), args.Error(1)
+}
+
+func (m *MockEmbeddingService) Model() string {
+	return "test-embedding"
+}
+
+type MockSemanticService struct {
+	mock.Mock
+}
+
+func (m *MockSemanticService) EmbedPendingItems(ctx context.Context, limit int32) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSemanticService) SearchItems(ctx context.Context, userID int32, query string, limit int) ([]SemanticResult, error) {
+	args := m.Called(ctx, userID, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]SemanticResult), args.Error(1)
+}
+
+func (m *MockSemanticService) RelatedItems(ctx context.Context, userID int32, itemID int32, limit int) ([]SemanticResult, error) {
+	args := m.Called(ctx, userID, itemID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]SemanticResult), args.Error(1)
+}
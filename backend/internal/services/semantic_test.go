@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+// embeddingAPIError is the error the embeddings client returns for a response with status
+func embeddingAPIError(status int) error {
+	request := httptest.NewRequest(http.MethodPost, "https://api.example.com/v1/embeddings", nil)
+	return &openai.Error{StatusCode: status, Request: request, Response: &http.Response{StatusCode: status}}
+}
+
+func TestEmbedPendingItems(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmbeddings := new(MockEmbeddingService)
+	service := NewSemanticService(mockQuerier, mockEmbeddings, SemanticConfig{BatchSize: 2, MaxInputChars: 40})
+	ctx := context.Background()
+	summary := "How indexes speed up queries"
+	text := strings.Repeat("word ", 20)
+
+	mockQuerier.On("ListItemsToEmbed", ctx, db.ListItemsToEmbedParams{Model: "test-embedding", Limit: 10}).Return([]db.Item{
+		{ID: 1, Title: "Postgres indexes", Tags: []string{"databases"}, Summary: &summary},
+		{ID: 2, Title: "Go generics", TextContent: &text},
+		{ID: 3, Title: "Rust lifetimes"},
+	}, nil)
+	mockEmbeddings.On("Embed", ctx, []string{
+		"Postgres indexes\n\ndatabases\n\nHow indexes",
+		"Go generics\n\nword word word word word wo",
+	}).Return([][]float32{{3, 4}, {0, 2}}, nil)
+	mockEmbeddings.On("Embed", ctx, []string{"Rust lifetimes"}).Return([][]float32{{1, 0}}, nil)
+	mockQuerier.On("UpsertItemEmbedding", ctx, db.UpsertItemEmbeddingParams{ItemID: 1, Model: "test-embedding", Embedding: []float32{0.6, 0.8}}).Return(nil)
+	mockQuerier.On("UpsertItemEmbedding", ctx, db.UpsertItemEmbeddingParams{ItemID: 2, Model: "test-embedding", Embedding: []float32{0, 1}}).Return(nil)
+	mockQuerier.On("UpsertItemEmbedding", ctx, db.UpsertItemEmbeddingParams{ItemID: 3, Model: "test-embedding", Embedding: []float32{1, 0}}).Return(nil)
+
+	stored, err := service.EmbedPendingItems(ctx, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored)
+	mockQuerier.AssertExpectations(t)
+	mockEmbeddings.AssertExpectations(t)
+}
+
+func TestEmbedPendingItems_RejectedInput(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmbeddings := new(MockEmbeddingService)
+	service := NewSemanticService(mockQuerier, mockEmbeddings, DefaultSemanticConfig())
+	ctx := context.Background()
+	rejected := embeddingAPIError(http.StatusBadRequest)
+
+	mockQuerier.On("ListItemsToEmbed", ctx, mock.Anything).Return([]db.Item{
+		{ID: 1, Title: "Fine"},
+		{ID: 2, Title: "Too long"},
+	}, nil)
+	mockEmbeddings.On("Embed", ctx, []string{"Fine", "Too long"}).Return(nil, rejected)
+	mockEmbeddings.On("Embed", ctx, []string{"Fine"}).Return([][]float32{{1, 0}}, nil)
+	mockEmbeddings.On("Embed", ctx, []string{"Too long"}).Return(nil, rejected)
+	mockQuerier.On("UpsertItemEmbedding", ctx, db.UpsertItemEmbeddingParams{ItemID: 1, Model: "test-embedding", Embedding: []float32{1, 0}}).Return(nil)
+	mockQuerier.On("UpsertItemEmbedding", ctx, mock.MatchedBy(func(arg db.UpsertItemEmbeddingParams) bool {
+		return arg.ItemID == 2 && arg.Embedding == nil && arg.Error != nil
+	})).Return(nil)
+
+	stored, err := service.EmbedPendingItems(ctx, 10)
+
+	require.NoError(t, err, "a rejected item is recorded, not retried")
+	assert.Equal(t, 1, stored)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestEmbedPendingItems_APIUnavailable(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmbeddings := new(MockEmbeddingService)
+	service := NewSemanticService(mockQuerier, mockEmbeddings, DefaultSemanticConfig())
+	ctx := context.Background()
+
+	mockQuerier.On("ListItemsToEmbed", ctx, mock.Anything).Return([]db.Item{{ID: 1, Title: "Fine"}}, nil)
+	mockEmbeddings.On("Embed", ctx, []string{"Fine"}).Return(nil, embeddingAPIError(http.StatusServiceUnavailable))
+
+	stored, err := service.EmbedPendingItems(ctx, 10)
+
+	require.Error(t, err)
+	assert.Zero(t, stored)
+	mockQuerier.AssertNotCalled(t, "UpsertItemEmbedding", mock.Anything, mock.Anything)
+}
+
+func TestSemanticSearchItems(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmbeddings := new(MockEmbeddingService)
+	service := NewSemanticService(mockQuerier, mockEmbeddings, DefaultSemanticConfig())
+	ctx := context.Background()
+	userID := int32(7)
+
+	mockEmbeddings.On("Embed", ctx, []string{"faster databases"}).Return([][]float32{{0, 5}}, nil)
+	mockQuerier.On("ListItemEmbeddingsVisibleToUser", ctx, db.ListItemEmbeddingsVisibleToUserParams{Model: "test-embedding", UserID: &userID, Limit: 1000}).Return([]db.ListItemEmbeddingsVisibleToUserRow{
+		{ItemID: 1, Embedding: []float32{1, 0}},
+		{ItemID: 2, Embedding: []float32{0.6, 0.8}},
+		{ItemID: 3, Embedding: []float32{0, 1}},
+		{ItemID: 4, Embedding: []float32{0, 1, 0}}, // from another model configuration
+	}, nil)
+	mockQuerier.On("GetItemsVisibleToUserByIDs", ctx, db.GetItemsVisibleToUserByIDsParams{UserID: userID, ItemIds: []int32{3, 2}}).Return([]db.GetItemsVisibleToUserByIDsRow{
+		{Item: db.Item{ID: 2, Title: "Indexes"}, IsRead: true},
+		{Item: db.Item{ID: 3, Title: "Query planning"}},
+	}, nil)
+
+	results, err := service.SearchItems(ctx, userID, " faster databases ", 2)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, int32(3), results[0].Item.ID)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-6)
+	assert.Equal(t, int32(2), results[1].Item.ID)
+	assert.InDelta(t, 0.8, results[1].Score, 1e-6)
+	assert.True(t, results[1].IsRead)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestSemanticSearchItems_PagesUpToMaxCandidates(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockEmbeddings := new(MockEmbeddingService)
+	config := DefaultSemanticConfig()
+	config.MaxCandidates = 1500
+	service := NewSemanticService(mockQuerier, mockEmbeddings, config)
+	ctx := context.Background()
+	userID := int32(7)
+
+	firstPage := make([]db.ListItemEmbeddingsVisibleToUserRow, 1000)
+	for i := range firstPage {
+		firstPage[i] = db.ListItemEmbeddingsVisibleToUserRow{ItemID: int32(2000 - i), Embedding: []float32{1, 0}}
+	}
+	lastID := int32(1001)
+
+	mockEmbeddings.On("Embed", ctx, []string{"faster databases"}).Return([][]float32{{0, 1}}, nil)
+	mockQuerier.On("ListItemEmbeddingsVisibleToUser", ctx, db.ListItemEmbeddingsVisibleToUserParams{Model: "test-embedding", UserID: &userID, Limit: 1000}).Return(firstPage, nil)
+	// The second page is cut to the remaining candidates
+	mockQuerier.On("ListItemEmbeddingsVisibleToUser", ctx, db.ListItemEmbeddingsVisibleToUserParams{Model: "test-embedding", UserID: &userID, BeforeID: &lastID, Limit: 500}).Return([]db.ListItemEmbeddingsVisibleToUserRow{
+		{ItemID: 10, Embedding: []float32{0, 1}},
+	}, nil)
+	mockQuerier.On("GetItemsVisibleToUserByIDs", ctx, db.GetItemsVisibleToUserByIDsParams{UserID: userID, ItemIds: []int32{10}}).Return([]db.GetItemsVisibleToUserByIDsRow{
+		{Item: db.Item{ID: 10, Title: "Query planning"}},
+	}, nil)
+
+	results, err := service.SearchItems(ctx, userID, "faster databases", 1)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int32(10), results[0].Item.ID)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNumberOfCalls(t, "ListItemEmbeddingsVisibleToUser", 2)
+}
+
+func TestSemanticSearchItems_EmptyQuery(t *testing.T) {
+	service := NewSemanticService(new(test.MockQuerier), new(MockEmbeddingService), DefaultSemanticConfig())
+
+	_, err := service.SearchItems(context.Background(), 7, "  ", 0)
+
+	assert.ErrorIs(t, err, ErrInvalidSearch)
+}
+
+func TestRelatedItems(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewSemanticService(mockQuerier, new(MockEmbeddingService), DefaultSemanticConfig())
+	ctx := context.Background()
+	userID := int32(7)
+
+	mockQuerier.On("GetItemVisibleToUser", ctx, db.GetItemVisibleToUserParams{ID: 1, UserID: &userID}).Return(db.Item{ID: 1}, nil)
+	mockQuerier.On("GetItemEmbedding", ctx, db.GetItemEmbeddingParams{ItemID: 1, Model: "test-embedding"}).Return(db.ItemEmbedding{ItemID: 1, Embedding: []float32{1, 0}}, nil)
+	mockQuerier.On("ListItemEmbeddingsVisibleToUser", ctx, mock.Anything).Return([]db.ListItemEmbeddingsVisibleToUserRow{
+		{ItemID: 1, Embedding: []float32{1, 0}},
+		{ItemID: 2, Embedding: []float32{0.6, 0.8}},
+	}, nil)
+	mockQuerier.On("GetItemsVisibleToUserByIDs", ctx, db.GetItemsVisibleToUserByIDsParams{UserID: userID, ItemIds: []int32{2}}).Return([]db.GetItemsVisibleToUserByIDsRow{
+		{Item: db.Item{ID: 2}},
+	}, nil)
+
+	results, err := service.RelatedItems(ctx, userID, 1, 0)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1, "the item itself is left out")
+	assert.Equal(t, int32(2), results[0].Item.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestRelatedItems_Errors(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(7)
+
+	t.Run("not visible", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := NewSemanticService(mockQuerier, new(MockEmbeddingService), DefaultSemanticConfig())
+		mockQuerier.On("GetItemVisibleToUser", ctx, mock.Anything).Return(db.Item{}, pgx.ErrNoRows)
+
+		_, err := service.RelatedItems(ctx, userID, 1, 0)
+
+		assert.ErrorIs(t, err, ErrItemNotFound)
+	})
+
+	t.Run("not embedded", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := NewSemanticService(mockQuerier, new(MockEmbeddingService), DefaultSemanticConfig())
+		mockQuerier.On("GetItemVisibleToUser", ctx, mock.Anything).Return(db.Item{ID: 1}, nil)
+		mockQuerier.On("GetItemEmbedding", ctx, mock.Anything).Return(db.ItemEmbedding{}, pgx.ErrNoRows)
+
+		_, err := service.RelatedItems(ctx, userID, 1, 0)
+
+		assert.ErrorIs(t, err, ErrEmbeddingNotReady)
+	})
+
+	t.Run("embedding failed", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := NewSemanticService(mockQuerier, new(MockEmbeddingService), DefaultSemanticConfig())
+		failure := "input rejected"
+		mockQuerier.On("GetItemVisibleToUser", ctx, mock.Anything).Return(db.Item{ID: 1}, nil)
+		mockQuerier.On("GetItemEmbedding", ctx, mock.Anything).Return(db.ItemEmbedding{ItemID: 1, Error: &failure}, nil)
+
+		_, err := service.RelatedItems(ctx, userID, 1, 0)
+
+		assert.True(t, errors.Is(err, ErrEmbeddingNotReady))
+	})
+}
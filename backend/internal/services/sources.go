@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Source types of an item. URL items are scraped by the worker; the others
+// carry their content in SourceContent and skip scraping.
+const (
+	SourceTypeURL      = "url"
+	SourceTypeText     = "text"
+	SourceTypeHTML     = "html"
+	SourceTypeMarkdown = "markdown"
+)
+
+// ErrInvalidSource is returned for pasted content and files that cannot become items
+var ErrInvalidSource = errors.New("invalid item source")
+
+// MaxSourceSize bounds pasted content and uploaded files, in bytes
+const MaxSourceSize = 5 << 20
+
+// contentSourceTypes are the source types created from pasted content
+var contentSourceTypes = map[string]bool{
+	SourceTypeText:     true,
+	SourceTypeHTML:     true,
+	SourceTypeMarkdown: true,
+}
+
+// fileSourceTypes map uploaded file extensions to source types. XHTML covers
+// EPUB chapters, which are XHTML documents inside the archive.
+var fileSourceTypes = map[string]string{
+	".txt":      SourceTypeText,
+	".text":     SourceTypeText,
+	".md":       SourceTypeMarkdown,
+	".markdown": SourceTypeMarkdown,
+	".html":     SourceTypeHTML,
+	".htm":      SourceTypeHTML,
+	".xhtml":    SourceTypeHTML,
+}
+
+// mediaSourceTypes map uploaded file content types to source types, for files without a known extension
+var mediaSourceTypes = map[string]string{
+	"text/plain":            SourceTypeText,
+	"text/markdown":         SourceTypeMarkdown,
+	"text/x-markdown":       SourceTypeMarkdown,
+	"text/html":             SourceTypeHTML,
+	"application/xhtml+xml": SourceTypeHTML,
+}
+
+var markdownHeading = regexp.MustCompile(`(?m)^#[ \t]+(.+?)[ \t#]*$`)
+
+// fileSourceType picks the source type of an uploaded file from its name,
+// then its content type. PDFs are reported separately since their text is
+// extracted before the item is stored.
+func fileSourceType(filename, contentType string) (sourceType string, isPDF bool, ok bool) {
+	ext := strings.ToLower(path.Ext(filename))
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if ext == ".pdf" || mediaType == "application/pdf" {
+		return "", true, true
+	}
+	if sourceType, ok := fileSourceTypes[ext]; ok {
+		return sourceType, false, true
+	}
+	sourceType, ok = mediaSourceTypes[mediaType]
+	return sourceType, false, ok
+}
+
+// validateSource checks pasted content before it is stored
+func validateSource(sourceType, content string) error {
+	if !contentSourceTypes[sourceType] {
+		return fmt.Errorf("%w: unsupported source type %q", ErrInvalidSource, sourceType)
+	}
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("%w: content is empty", ErrInvalidSource)
+	}
+	if len(content) > MaxSourceSize {
+		return fmt.Errorf("%w: content is larger than %d MB", ErrInvalidSource, MaxSourceSize>>20)
+	}
+	if !utf8.ValidString(content) {
+		return fmt.Errorf("%w: content is not UTF-8 text", ErrInvalidSource)
+	}
+	return nil
+}
+
+// ParseSource converts the stored source of an item into Markdown content
+// and metadata, the same result the scraper returns for a URL
+func ParseSource(sourceType, content string) (*ScrapeResult, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var result *ScrapeResult
+	switch sourceType {
+	case SourceTypeHTML:
+		parsed, err := ParseHTML([]byte(content), nil)
+		if err != nil {
+			return nil, err
+		}
+		result = parsed
+	case SourceTypeMarkdown:
+		result = &ScrapeResult{Content: strings.TrimSpace(content)}
+		if heading := markdownHeading.FindStringSubmatch(content); heading != nil {
+			result.Title = strings.TrimSpace(heading[1])
+		}
+	case SourceTypeText:
+		result = &ScrapeResult{Content: strings.TrimSpace(blankLines.ReplaceAllString(content, "\n\n"))}
+	default:
+		return nil, fmt.Errorf("%w: unsupported source type %q", ErrInvalidSource, sourceType)
+	}
+
+	if result.Content == "" {
+		return nil, fmt.Errorf("%w: no text content", ErrInvalidSource)
+	}
+	if result.Excerpt == "" {
+		result.Excerpt = firstParagraph(result.Content, maxExcerptLength)
+	}
+	return result, nil
+}
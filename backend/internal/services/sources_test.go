@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSource_HTML(t *testing.T) {
+	content := `<html><head><title>This Week in Infrastructure</title></head><body>
+<nav><a href="/">Home</a></nav>
+<h1>This Week in Infrastructure</h1>
+<p>Reserved instances cut our compute bill by twelve percent, and the migration took less than a week.</p>
+<p>Read the <a href="https://example.com/report">full report</a> for the details.</p>
+</body></html>`
+
+	result, err := ParseSource(SourceTypeHTML, content)
+
+	require.NoError(t, err)
+	assert.Equal(t, "This Week in Infrastructure", result.Title)
+	assert.Contains(t, result.Content, "Reserved instances cut our compute bill")
+	assert.Contains(t, result.Content, "[full report](https://example.com/report)")
+	assert.NotContains(t, result.Content, "Home")
+	assert.Empty(t, result.URL)
+}
+
+func TestParseSource_Markdown(t *testing.T) {
+	content := "Notes from the offsite\r\n\r\n# Planning Notes #\r\n\r\nWe agreed to ship the importer first.\r\n"
+
+	result, err := ParseSource(SourceTypeMarkdown, content)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Planning Notes", result.Title)
+	assert.Equal(t, "Notes from the offsite\n\n# Planning Notes #\n\nWe agreed to ship the importer first.", result.Content)
+	assert.Equal(t, "Notes from the offsite", result.Excerpt)
+}
+
+func TestParseSource_Text(t *testing.T) {
+	result, err := ParseSource(SourceTypeText, "  First paragraph.\n\n\n\nSecond paragraph.\n")
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Title)
+	assert.Equal(t, "First paragraph.\n\nSecond paragraph.", result.Content)
+	assert.Equal(t, "First paragraph.", result.Excerpt)
+}
+
+func TestParseSource_Invalid(t *testing.T) {
+	_, err := ParseSource("pdf", "content")
+	assert.ErrorIs(t, err, ErrInvalidSource)
+
+	_, err = ParseSource(SourceTypeHTML, "<script>alert(1)</script>")
+	assert.ErrorIs(t, err, ErrInvalidSource)
+}
+
+func TestValidateSource(t *testing.T) {
+	assert.NoError(t, validateSource(SourceTypeMarkdown, "# Note"))
+	assert.ErrorIs(t, validateSource(SourceTypeURL, "https://example.com"), ErrInvalidSource)
+	assert.ErrorIs(t, validateSource(SourceTypeText, " \n "), ErrInvalidSource)
+	assert.ErrorIs(t, validateSource(SourceTypeText, "\xff\xfe"), ErrInvalidSource)
+	assert.ErrorIs(t, validateSource(SourceTypeText, strings.Repeat("a", MaxSourceSize+1)), ErrInvalidSource)
+}
+
+func TestFileSourceType(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		sourceType  string
+		isPDF       bool
+		ok          bool
+	}{
+		{"notes.md", "application/octet-stream", SourceTypeMarkdown, false, true},
+		{"chapter-03.xhtml", "", SourceTypeHTML, false, true},
+		{"Newsletter.HTML", "", SourceTypeHTML, false, true},
+		{"readme", "text/plain; charset=utf-8", SourceTypeText, false, true},
+		{"paper.pdf", "", "", true, true},
+		{"download", "application/pdf", "", true, true},
+		{"book.epub", "application/epub+zip", "", false, false},
+	}
+	for _, tt := range tests {
+		sourceType, isPDF, ok := fileSourceType(tt.filename, tt.contentType)
+		assert.Equal(t, tt.sourceType, sourceType, tt.filename)
+		assert.Equal(t, tt.isPDF, isPDF, tt.filename)
+		assert.Equal(t, tt.ok, ok, tt.filename)
+	}
+}
@@ -118,6 +118,18 @@ const (
 	VoiceAmSanta   VoiceEnum = "am_santa"
 )
 
+// IsValidVoice reports whether the given name is one of the supported voices
+func IsValidVoice(voice string) bool {
+	switch VoiceEnum(voice) {
+	case VoiceAfHeart, VoiceAfAlloy, VoiceAfAoede, VoiceAfBella, VoiceAfJessica,
+		VoiceAfKore, VoiceAfNicole, VoiceAfNova, VoiceAfRiver, VoiceAfSarah, VoiceAfSky,
+		VoiceAmAdam, VoiceAmEcho, VoiceAmFenrir, VoiceAmLiam, VoiceAmMichael,
+		VoiceAmOnyx, VoiceAmPuck, VoiceAmSanta:
+		return true
+	}
+	return false
+}
+
 // File represents a file response from the API
 type File struct {
 	URL         string `json:"url"`
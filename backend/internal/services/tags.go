@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// Tag errors returned by TagService
+var (
+	ErrInvalidTag       = errors.New("invalid tag")
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTagExists        = errors.New("tag already exists")
+	ErrTagAliasNotFound = errors.New("tag alias not found")
+)
+
+// maxTagLength caps the length in characters of a normalized tag
+const maxTagLength = 64
+
+// TagSummary is a tag with the number of the user's items carrying it and
+// the aliases that resolve to it
+type TagSummary struct {
+	Name      string
+	ItemCount int
+	Aliases   []string
+}
+
+// TagMergeResult is the tag others were merged into, the aliases recorded
+// for it and the number of items whose tags were rewritten
+type TagMergeResult struct {
+	Tag          string
+	Aliases      []string
+	ItemsUpdated int64
+}
+
+// TagService manages a user's tags. Tags live on items; merging or renaming
+// rewrites them on every item the user owns and records the old names as
+// aliases, so tags extracted from later items are spelled the same way.
+type TagService interface {
+	ListTags(ctx context.Context, userID int32) ([]TagSummary, error)
+	// MergeTags replaces sources by target on the user's items and makes them its aliases
+	MergeTags(ctx context.Context, userID int32, sources []string, target string) (*TagMergeResult, error)
+	// RenameTag is MergeTags for a single tag, refusing to merge into a tag that already exists
+	RenameTag(ctx context.Context, userID int32, from, to string) (*TagMergeResult, error)
+	DeleteAlias(ctx context.Context, userID int32, alias string) error
+
+	// Configuration
+	SetTxRunner(txRunner TxRunner)
+}
+
+type tagService struct {
+	querier  db.Querier
+	txRunner TxRunner
+}
+
+func NewTagService(querier db.Querier) TagService {
+	return &tagService{querier: querier}
+}
+
+// SetTxRunner makes merges and renames run in a single transaction
+func (s *tagService) SetTxRunner(txRunner TxRunner) {
+	s.txRunner = txRunner
+}
+
+func (s *tagService) ListTags(ctx context.Context, userID int32) ([]TagSummary, error) {
+	counts, err := s.querier.ListTagCounts(ctx, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	curated, err := s.querier.ListTagsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	aliases, err := s.querier.ListTagAliasesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tag aliases: %w", err)
+	}
+
+	// Most used first, then curated tags no item carries yet
+	summaries := make([]TagSummary, 0, len(counts)+len(curated))
+	byName := make(map[string]int, len(counts)+len(curated))
+	for _, count := range counts {
+		byName[count.Name] = len(summaries)
+		summaries = append(summaries, TagSummary{Name: count.Name, ItemCount: int(count.ItemCount)})
+	}
+	for _, tag := range curated {
+		if _, ok := byName[tag.Name]; !ok {
+			byName[tag.Name] = len(summaries)
+			summaries = append(summaries, TagSummary{Name: tag.Name})
+		}
+	}
+	for _, alias := range aliases {
+		if i, ok := byName[alias.Tag]; ok {
+			summaries[i].Aliases = append(summaries[i].Aliases, alias.Alias)
+		}
+	}
+	return summaries, nil
+}
+
+func (s *tagService) MergeTags(ctx context.Context, userID int32, sources []string, target string) (*TagMergeResult, error) {
+	name, err := validateTag(target)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w: at least one tag to merge is required", ErrInvalidTag)
+	}
+
+	// Items are matched on the spellings given as well as the normalized
+	// ones, so "Machine Learning" on an item is caught by either form
+	matches := []string{name, strings.ToLower(strings.TrimSpace(target))}
+	var aliases []string
+	for _, source := range sources {
+		alias, err := validateTag(source)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, alias, strings.ToLower(strings.TrimSpace(source)))
+		if alias != name && !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	slices.Sort(matches)
+	matches = slices.Compact(matches)
+
+	// The tag, its aliases and the items' tags change together, so a failure
+	// part way leaves the user's tags as they were
+	var updated int64
+	err = runInTx(ctx, s.txRunner, s.querier, func(q db.Querier) error {
+		tag, err := q.UpsertTag(ctx, db.UpsertTagParams{UserID: userID, Name: name})
+		if err != nil {
+			return fmt.Errorf("failed to save tag: %w", err)
+		}
+		// The target is a tag in its own right now, not another tag's alias
+		if _, err := q.DeleteTagAlias(ctx, db.DeleteTagAliasParams{UserID: userID, Alias: name}); err != nil {
+			return fmt.Errorf("failed to delete tag alias: %w", err)
+		}
+
+		if len(aliases) > 0 {
+			// Aliases of merged tags follow them into the target
+			if err := q.RetargetTagAliases(ctx, db.RetargetTagAliasesParams{TagID: tag.ID, UserID: userID, Names: aliases}); err != nil {
+				return fmt.Errorf("failed to move tag aliases: %w", err)
+			}
+			if err := q.DeleteTagsByName(ctx, db.DeleteTagsByNameParams{UserID: userID, Names: aliases}); err != nil {
+				return fmt.Errorf("failed to delete merged tags: %w", err)
+			}
+			for _, alias := range aliases {
+				if err := q.UpsertTagAlias(ctx, db.UpsertTagAliasParams{UserID: userID, Alias: alias, TagID: tag.ID}); err != nil {
+					return fmt.Errorf("failed to save tag alias: %w", err)
+				}
+			}
+		}
+
+		updated, err = q.ReplaceItemTags(ctx, db.ReplaceItemTagsParams{
+			Matches: matches,
+			Tag:     name,
+			UserID:  &userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rewrite item tags: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TagMergeResult{Tag: name, Aliases: aliases, ItemsUpdated: updated}, nil
+}
+
+func (s *tagService) RenameTag(ctx context.Context, userID int32, from, to string) (*TagMergeResult, error) {
+	fromName, err := validateTag(from)
+	if err != nil {
+		return nil, err
+	}
+	toName, err := validateTag(to)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.hasTag(ctx, userID, strings.TrimSpace(from), fromName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTagNotFound
+	}
+	if toName != fromName {
+		exists, err := s.hasTag(ctx, userID, strings.TrimSpace(to), toName)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: %q; merge the tags instead", ErrTagExists, toName)
+		}
+	}
+
+	return s.MergeTags(ctx, userID, []string{from}, to)
+}
+
+// hasTag reports whether the user has a tag spelled as given or in its normalized form
+func (s *tagService) hasTag(ctx context.Context, userID int32, names ...string) (bool, error) {
+	for _, name := range slices.Compact(names) {
+		exists, err := s.querier.UserHasTag(ctx, db.UserHasTagParams{UserID: userID, Name: name})
+		if err != nil {
+			return false, fmt.Errorf("failed to look up tag: %w", err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *tagService) DeleteAlias(ctx context.Context, userID int32, alias string) error {
+	deleted, err := s.querier.DeleteTagAlias(ctx, db.DeleteTagAliasParams{UserID: userID, Alias: canonicalTag(alias)})
+	if err != nil {
+		return fmt.Errorf("failed to delete tag alias: %w", err)
+	}
+	if deleted == 0 {
+		return ErrTagAliasNotFound
+	}
+	return nil
+}
+
+// canonicalTag returns the normalized spelling of a tag: lowercase words
+// joined by hyphens, so "Machine Learning", "machine_learning" and
+// "#machine-learning" are the same tag
+func canonicalTag(tag string) string {
+	words := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_' || r == '#'
+	})
+	return strings.Join(words, "-")
+}
+
+// validateTag returns the normalized spelling of a tag given by a user
+func validateTag(tag string) (string, error) {
+	name := canonicalTag(tag)
+	if name == "" {
+		return "", fmt.Errorf("%w: a tag name is required", ErrInvalidTag)
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return "", fmt.Errorf("%w: tags are at most %d characters", ErrInvalidTag, maxTagLength)
+	}
+	return name, nil
+}
+
+// resolveTags normalizes extracted tags and replaces those that are aliases
+// of the user's tags. Blank, overlong and repeated tags are dropped.
+func resolveTags(ctx context.Context, querier db.Querier, userID *int32, tags []string) ([]string, error) {
+	resolved := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if name := canonicalTag(tag); name != "" && utf8.RuneCountInString(name) <= maxTagLength {
+			resolved = append(resolved, name)
+		}
+	}
+	resolved = dedupeTags(resolved)
+	if userID == nil || len(resolved) == 0 {
+		return resolved, nil
+	}
+	return resolveTagAliases(ctx, querier, *userID, resolved)
+}
+
+// filterTags resolves the tags a list is filtered by to the spellings stored
+// on items. Blank and repeated tags are dropped.
+func filterTags(ctx context.Context, querier db.Querier, userID int32, tags []string) ([]string, error) {
+	resolved := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if name := canonicalTag(tag); name != "" {
+			resolved = append(resolved, name)
+		}
+	}
+	resolved = dedupeTags(resolved)
+	if len(resolved) == 0 {
+		return nil, nil
+	}
+	resolved, err := resolveTagAliases(ctx, querier, userID, resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tag aliases: %w", err)
+	}
+	return resolved, nil
+}
+
+// resolveTagAliases replaces the normalized tags that are aliases of the
+// user's tags by the tag they point to
+func resolveTagAliases(ctx context.Context, querier db.Querier, userID int32, tags []string) ([]string, error) {
+	rows, err := querier.ResolveTagAliases(ctx, db.ResolveTagAliasesParams{UserID: userID, Aliases: tags})
+	if err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]string, len(rows))
+	for _, row := range rows {
+		aliases[row.Alias] = row.Tag
+	}
+	for i, tag := range tags {
+		if name, ok := aliases[tag]; ok {
+			tags[i] = name
+		}
+	}
+	return dedupeTags(tags), nil
+}
+
+// dedupeTags drops repeated tags, keeping the first of each
+func dedupeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	deduped := tags[:0]
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			deduped = append(deduped, tag)
+		}
+	}
+	return deduped
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func TestCanonicalTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{"AI", "ai"},
+		{"Machine Learning", "machine-learning"},
+		{"machine_learning", "machine-learning"},
+		{"  #Machine--Learning ", "machine-learning"},
+		{"C++", "c++"},
+		{"Node.js", "node.js"},
+		{" - ", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, canonicalTag(tt.tag), tt.tag)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewTagService(mockQuerier)
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("ListTagCounts", ctx, &userID).Return([]db.ListTagCountsRow{
+		{Name: "ai", ItemCount: 4},
+		{Name: "golang", ItemCount: 2},
+	}, nil)
+	mockQuerier.On("ListTagsByUser", ctx, userID).Return([]db.Tag{
+		{ID: 1, UserID: 1, Name: "ai"},
+		{ID: 2, UserID: 1, Name: "rust"},
+	}, nil)
+	mockQuerier.On("ListTagAliasesByUser", ctx, userID).Return([]db.ListTagAliasesByUserRow{
+		{Alias: "artificial-intelligence", Tag: "ai"},
+		{Alias: "llm", Tag: "ai"},
+		{Alias: "rustlang", Tag: "rust"},
+	}, nil)
+
+	tags, err := service.ListTags(ctx, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []TagSummary{
+		{Name: "ai", ItemCount: 4, Aliases: []string{"artificial-intelligence", "llm"}},
+		{Name: "golang", ItemCount: 2},
+		{Name: "rust", Aliases: []string{"rustlang"}},
+	}, tags)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestMergeTags(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewTagService(mockQuerier)
+	ctx := context.Background()
+	userID := int32(1)
+	aliases := []string{"artificial-intelligence", "ml"}
+
+	mockQuerier.On("UpsertTag", ctx, db.UpsertTagParams{UserID: userID, Name: "ai"}).Return(db.Tag{ID: 5, UserID: userID, Name: "ai"}, nil)
+	mockQuerier.On("DeleteTagAlias", ctx, db.DeleteTagAliasParams{UserID: userID, Alias: "ai"}).Return(int64(0), nil)
+	mockQuerier.On("RetargetTagAliases", ctx, db.RetargetTagAliasesParams{TagID: 5, UserID: userID, Names: aliases}).Return(nil)
+	mockQuerier.On("DeleteTagsByName", ctx, db.DeleteTagsByNameParams{UserID: userID, Names: aliases}).Return(nil)
+	mockQuerier.On("UpsertTagAlias", ctx, db.UpsertTagAliasParams{UserID: userID, Alias: "artificial-intelligence", TagID: 5}).Return(nil)
+	mockQuerier.On("UpsertTagAlias", ctx, db.UpsertTagAliasParams{UserID: userID, Alias: "ml", TagID: 5}).Return(nil)
+	mockQuerier.On("ReplaceItemTags", ctx, db.ReplaceItemTagsParams{
+		Matches: []string{"ai", "artificial intelligence", "artificial-intelligence", "ml"},
+		Tag:     "ai",
+		UserID:  &userID,
+	}).Return(int64(6), nil)
+
+	result, err := service.MergeTags(ctx, userID, []string{"AI", "Artificial Intelligence", "ML", "ml"}, " AI ")
+
+	require.NoError(t, err)
+	assert.Equal(t, &TagMergeResult{Tag: "ai", Aliases: aliases, ItemsUpdated: 6}, result)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestMergeTags_OnlyRespells(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewTagService(mockQuerier)
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("UpsertTag", ctx, db.UpsertTagParams{UserID: userID, Name: "machine-learning"}).Return(db.Tag{ID: 5, Name: "machine-learning"}, nil)
+	mockQuerier.On("DeleteTagAlias", ctx, db.DeleteTagAliasParams{UserID: userID, Alias: "machine-learning"}).Return(int64(1), nil)
+	mockQuerier.On("ReplaceItemTags", ctx, db.ReplaceItemTagsParams{
+		Matches: []string{"machine learning", "machine-learning"},
+		Tag:     "machine-learning",
+		UserID:  &userID,
+	}).Return(int64(2), nil)
+
+	result, err := service.MergeTags(ctx, userID, []string{"Machine Learning"}, "machine-learning")
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Aliases, "a different spelling of the target is not an alias")
+	assert.Equal(t, int64(2), result.ItemsUpdated)
+	mockQuerier.AssertNotCalled(t, "UpsertTagAlias", mock.Anything, mock.Anything)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestMergeTags_RollsBackOnFailure(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	runner := &fakeTxRunner{querier: mockQuerier}
+	service := NewTagService(mockQuerier)
+	service.SetTxRunner(runner)
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("UpsertTag", ctx, db.UpsertTagParams{UserID: userID, Name: "ai"}).Return(db.Tag{ID: 5, UserID: userID, Name: "ai"}, nil)
+	mockQuerier.On("DeleteTagAlias", ctx, db.DeleteTagAliasParams{UserID: userID, Alias: "ai"}).Return(int64(0), nil)
+	mockQuerier.On("RetargetTagAliases", ctx, db.RetargetTagAliasesParams{TagID: 5, UserID: userID, Names: []string{"ml"}}).Return(nil)
+	mockQuerier.On("DeleteTagsByName", ctx, db.DeleteTagsByNameParams{UserID: userID, Names: []string{"ml"}}).Return(nil)
+	mockQuerier.On("UpsertTagAlias", ctx, db.UpsertTagAliasParams{UserID: userID, Alias: "ml", TagID: 5}).Return(nil)
+	mockQuerier.On("ReplaceItemTags", ctx, mock.Anything).Return(int64(0), errors.New("database error"))
+
+	_, err := service.MergeTags(ctx, userID, []string{"ml"}, "ai")
+
+	assert.ErrorContains(t, err, "failed to rewrite item tags")
+	// The deleted tag and new aliases roll back with the failed rewrite
+	assert.Equal(t, 1, runner.runs)
+	assert.True(t, runner.rolledBack)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestMergeTags_Invalid(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewTagService(mockQuerier)
+	ctx := context.Background()
+
+	_, err := service.MergeTags(ctx, 1, []string{"ai"}, " # ")
+	assert.True(t, errors.Is(err, ErrInvalidTag))
+
+	_, err = service.MergeTags(ctx, 1, nil, "ai")
+	assert.True(t, errors.Is(err, ErrInvalidTag))
+
+	_, err = service.MergeTags(ctx, 1, []string{strings.Repeat("a", maxTagLength+1)}, "ai")
+	assert.True(t, errors.Is(err, ErrInvalidTag))
+
+	mockQuerier.AssertNotCalled(t, "UpsertTag", mock.Anything, mock.Anything)
+}
+
+func TestRenameTag(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewTagService(mockQuerier)
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("UserHasTag", ctx, db.UserHasTagParams{UserID: userID, Name: "ml"}).Return(true, nil)
+	mockQuerier.On("UserHasTag", ctx, db.UserHasTagParams{UserID: userID, Name: "Machine Learning"}).Return(false, nil)
+	mockQuerier.On("UserHasTag", ctx, db.UserHasTagParams{UserID: userID, Name: "machine-learning"}).Return(false, nil)
+	mockQuerier.On("UpsertTag", ctx, db.UpsertTagParams{UserID: userID, Name: "machine-learning"}).Return(db.Tag{ID: 3, Name: "machine-learning"}, nil)
+	mockQuerier.On("DeleteTagAlias", ctx, db.DeleteTagAliasParams{UserID: userID, Alias: "machine-learning"}).Return(int64(0), nil)
+	mockQuerier.On("RetargetTagAliases", ctx, db.RetargetTagAliasesParams{TagID: 3, UserID: userID, Names: []string{"ml"}}).Return(nil)
+	mockQuerier.On("DeleteTagsByName", ctx, db.DeleteTagsByNameParams{UserID: userID, Names: []string{"ml"}}).Return(nil)
+	mockQuerier.On("UpsertTagAlias", ctx, db.UpsertTagAliasParams{UserID: userID, Alias: "ml", TagID: 3}).Return(nil)
+	mockQuerier.On("ReplaceItemTags", ctx, mock.Anything).Return(int64(4), nil)
+
+	result, err := service.RenameTag(ctx, userID, "ml", "Machine Learning")
+
+	require.NoError(t, err)
+	assert.Equal(t, "machine-learning", result.Tag)
+	assert.Equal(t, []string{"ml"}, result.Aliases)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestRenameTag_Conflicts(t *testing.T) {
+	ctx := context.Background()
+	userID := int32(1)
+
+	t.Run("unknown tag", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := NewTagService(mockQuerier)
+		mockQuerier.On("UserHasTag", ctx, db.UserHasTagParams{UserID: userID, Name: "rust"}).Return(false, nil)
+
+		_, err := service.RenameTag(ctx, userID, "rust", "rustlang")
+
+		assert.True(t, errors.Is(err, ErrTagNotFound))
+	})
+
+	t.Run("existing target", func(t *testing.T) {
+		mockQuerier := new(test.MockQuerier)
+		service := NewTagService(mockQuerier)
+		mockQuerier.On("UserHasTag", ctx, db.UserHasTagParams{UserID: userID, Name: "ml"}).Return(true, nil)
+		mockQuerier.On("UserHasTag", ctx, db.UserHasTagParams{UserID: userID, Name: "ai"}).Return(true, nil)
+
+		_, err := service.RenameTag(ctx, userID, "ml", "ai")
+
+		assert.True(t, errors.Is(err, ErrTagExists))
+		mockQuerier.AssertNotCalled(t, "ReplaceItemTags", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteTagAlias(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewTagService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("DeleteTagAlias", ctx, db.DeleteTagAliasParams{UserID: 1, Alias: "artificial-intelligence"}).Return(int64(1), nil)
+	mockQuerier.On("DeleteTagAlias", ctx, db.DeleteTagAliasParams{UserID: 1, Alias: "ml"}).Return(int64(0), nil)
+
+	assert.NoError(t, service.DeleteAlias(ctx, 1, "Artificial Intelligence"))
+	assert.True(t, errors.Is(service.DeleteAlias(ctx, 1, "ml"), ErrTagAliasNotFound))
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestResolveTags_WithoutOwner(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+
+	tags, err := resolveTags(context.Background(), mockQuerier, nil, []string{"AI", "ai", "Deep Learning"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ai", "deep-learning"}, tags)
+	mockQuerier.AssertNotCalled(t, "ResolveTagAliases", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// TxRunner runs a function against a Querier bound to one database
+// transaction, committing when the function returns nil and rolling back
+// otherwise
+type TxRunner interface {
+	RunInTx(ctx context.Context, fn func(q db.Querier) error) error
+}
+
+// TxBeginner starts database transactions. It is satisfied by *pgxpool.Pool.
+type TxBeginner interface {
+	db.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type poolTxRunner struct {
+	pool    TxBeginner
+	queries *db.Queries
+}
+
+// NewTxRunner returns a TxRunner that runs transactions on the pool
+func NewTxRunner(pool TxBeginner) TxRunner {
+	return &poolTxRunner{pool: pool, queries: db.New(pool)}
+}
+
+func (r *poolTxRunner) RunInTx(ctx context.Context, fn func(q db.Querier) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(r.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// runInTx runs fn in a transaction when a TxRunner is configured, and
+// directly against querier otherwise
+func runInTx(ctx context.Context, runner TxRunner, querier db.Querier, fn func(q db.Querier) error) error {
+	if runner == nil {
+		return fn(querier)
+	}
+	return runner.RunInTx(ctx, fn)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+// fakeTxRunner runs each transaction against the querier, recording how many
+// ran and whether the last one rolled back
+type fakeTxRunner struct {
+	querier    db.Querier
+	runs       int
+	rolledBack bool
+}
+
+func (r *fakeTxRunner) RunInTx(ctx context.Context, fn func(q db.Querier) error) error {
+	r.runs++
+	err := fn(r.querier)
+	r.rolledBack = err != nil
+	return err
+}
+
+func TestRunInTx_UsesRunner(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	runner := &fakeTxRunner{querier: mockQuerier}
+
+	var got db.Querier
+	err := runInTx(context.Background(), runner, nil, func(q db.Querier) error {
+		got = q
+		return errors.New("boom")
+	})
+
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, 1, runner.runs)
+	assert.True(t, runner.rolledBack)
+	assert.Same(t, mockQuerier, got)
+}
+
+func TestRunInTx_WithoutRunner(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+
+	var got db.Querier
+	err := runInTx(context.Background(), nil, mockQuerier, func(q db.Querier) error {
+		got = q
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Same(t, mockQuerier, got)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// UserExport is everything stored about a user, as returned by ExportUserData
+type UserExport struct {
+	ExportedAt  time.Time          `json:"exported_at"`
+	User        ExportedUser       `json:"user"`
+	Preferences *db.UserPreference `json:"preferences,omitempty"`
+	Items       []ExportedItem     `json:"items"`
+	Podcasts    []ExportedPodcast  `json:"podcasts"`
+}
+
+// ExportedUser is the user's profile without credentials
+type ExportedUser struct {
+	ID           int32      `json:"id"`
+	Name         *string    `json:"name"`
+	Email        *string    `json:"email"`
+	AuthProvider *string    `json:"auth_provider"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+}
+
+// ExportedItem is a saved item, including its summary and the user's read state
+type ExportedItem struct {
+	db.Item
+	IsRead bool       `json:"is_read"`
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
+
+// ExportedPodcast is a podcast with its script and a link to the audio
+type ExportedPodcast struct {
+	ID              int32            `json:"id"`
+	Title           string           `json:"title"`
+	Description     *string          `json:"description"`
+	Status          string           `json:"status"`
+	AudioURL        *string          `json:"audio_url"`
+	DurationSeconds *int32           `json:"duration_seconds"`
+	Script          json.RawMessage  `json:"script,omitempty"`
+	ItemIDs         []int32          `json:"item_ids"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	CompletedAt     pgtype.Timestamp `json:"completed_at"`
+}
+
+// ExportUserData collects a user's profile, preferences, items and podcasts
+func (s *userService) ExportUserData(ctx context.Context, id int32) (*UserExport, error) {
+	user, err := s.querier.GetUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	export := &UserExport{
+		ExportedAt: time.Now().UTC(),
+		User: ExportedUser{
+			ID:           user.ID,
+			Name:         user.Name,
+			Email:        user.Email,
+			AuthProvider: user.AuthProvider,
+			CreatedAt:    user.CreatedAt,
+			UpdatedAt:    user.UpdatedAt,
+		},
+		Items:    []ExportedItem{},
+		Podcasts: []ExportedPodcast{},
+	}
+
+	prefs, err := s.querier.GetUserPreferences(ctx, id)
+	if err == nil {
+		export.Preferences = &prefs
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	items, err := s.querier.GetItemsOwnedByUser(ctx, &id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+	reads, err := s.querier.GetItemReadsByUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read state: %w", err)
+	}
+	readAt := make(map[int32]time.Time, len(reads))
+	for _, read := range reads {
+		readAt[read.ItemID] = read.ReadAt
+	}
+	for _, item := range items {
+		exported := ExportedItem{Item: item}
+		if at, ok := readAt[item.ID]; ok {
+			exported.IsRead = true
+			exported.ReadAt = &at
+		}
+		export.Items = append(export.Items, exported)
+	}
+
+	podcasts, err := s.querier.GetPodcastByUser(ctx, &id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcasts: %w", err)
+	}
+	for _, podcast := range podcasts {
+		podcastID := podcast.ID
+		itemIDs, err := s.querier.GetPodcastItemIDs(ctx, &podcastID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get podcast items: %w", err)
+		}
+		exported := ExportedPodcast{
+			ID:              podcast.ID,
+			Title:           podcast.Title,
+			Description:     podcast.Description,
+			Status:          podcast.Status,
+			AudioURL:        podcast.AudioUrl,
+			DurationSeconds: podcast.DurationSeconds,
+			ItemIDs:         []int32{},
+			CreatedAt:       podcast.CreatedAt,
+			CompletedAt:     podcast.CompletedAt,
+		}
+		if len(podcast.Dialogues) > 0 {
+			exported.Script = json.RawMessage(podcast.Dialogues)
+		}
+		for _, itemID := range itemIDs {
+			if itemID != nil {
+				exported.ItemIDs = append(exported.ItemIDs, *itemID)
+			}
+		}
+		export.Podcasts = append(export.Podcasts, exported)
+	}
+
+	return export, nil
+}
+
+// WriteZip writes the export as a zip archive with one JSON file per section
+func (e *UserExport) WriteZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	type exportFile struct {
+		name string
+		data interface{}
+	}
+	files := []exportFile{
+		{"user.json", e.User},
+		{"items.json", e.Items},
+		{"podcasts.json", e.Podcasts},
+		{"export.json", map[string]interface{}{"exported_at": e.ExportedAt, "user_id": e.User.ID}},
+	}
+	if e.Preferences != nil {
+		files = append(files, exportFile{"preferences.json", e.Preferences})
+	}
+
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+		f, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: e.ExportedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return archive.Close()
+}
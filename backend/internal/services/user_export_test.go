@@ -0,0 +1,102 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func TestExportUserData(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewUserService(mockQuerier)
+
+	ctx := context.Background()
+	userID := int32(1)
+	podcastID := int32(4)
+	name := "Jane"
+	hash := "$2a$10$secret"
+	summary := "A short summary"
+	audioURL := "https://cdn.example.com/podcasts/4.mp3"
+	readAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	firstItem, secondItem := int32(10), int32(11)
+
+	mockQuerier.On("GetUser", ctx, userID).Return(db.User{ID: userID, Name: &name, PasswordHash: &hash}, nil)
+	mockQuerier.On("GetUserPreferences", ctx, userID).Return(db.UserPreference{}, pgx.ErrNoRows)
+	mockQuerier.On("GetItemsOwnedByUser", ctx, &userID).Return([]db.Item{
+		{ID: 10, Title: "Read item", Summary: &summary},
+		{ID: 11, Title: "Unread item"},
+	}, nil)
+	mockQuerier.On("GetItemReadsByUser", ctx, userID).Return([]db.GetItemReadsByUserRow{{ItemID: 10, ReadAt: readAt}}, nil)
+	mockQuerier.On("GetPodcastByUser", ctx, &userID).Return([]db.Podcast{
+		{ID: podcastID, Title: "Daily", AudioUrl: &audioURL, Dialogues: []byte(`[{"speaker":"heart","content":"Hi"}]`)},
+	}, nil)
+	mockQuerier.On("GetPodcastItemIDs", ctx, &podcastID).Return([]*int32{&firstItem, &secondItem}, nil)
+
+	export, err := service.ExportUserData(ctx, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", *export.User.Name)
+	assert.Nil(t, export.Preferences)
+
+	require.Len(t, export.Items, 2)
+	assert.True(t, export.Items[0].IsRead)
+	assert.Equal(t, readAt, *export.Items[0].ReadAt)
+	assert.Equal(t, "A short summary", *export.Items[0].Summary)
+	assert.False(t, export.Items[1].IsRead)
+
+	require.Len(t, export.Podcasts, 1)
+	assert.Equal(t, []int32{10, 11}, export.Podcasts[0].ItemIDs)
+	assert.Equal(t, audioURL, *export.Podcasts[0].AudioURL)
+	assert.JSONEq(t, `[{"speaker":"heart","content":"Hi"}]`, string(export.Podcasts[0].Script))
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestUserExport_WriteZip(t *testing.T) {
+	email := "jane@example.com"
+	export := &UserExport{
+		ExportedAt:  time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		User:        ExportedUser{ID: 1, Email: &email},
+		Preferences: &db.UserPreference{UserID: 1, Timezone: "UTC"},
+		Items:       []ExportedItem{{Item: db.Item{ID: 10, Title: "Item"}, IsRead: true}},
+		Podcasts:    []ExportedPodcast{{ID: 4, Title: "Daily", ItemIDs: []int32{10}}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, export.WriteZip(&buf))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = data
+	}
+
+	assert.Len(t, files, 5)
+	assert.Contains(t, files, "preferences.json")
+
+	var items []map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["items.json"], &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, true, items[0]["is_read"])
+	assert.Equal(t, "Item", items[0]["title"])
+
+	// Credentials never leave the server
+	assert.NotContains(t, string(files["user.json"]), "password")
+	assert.Contains(t, string(files["user.json"]), email)
+}
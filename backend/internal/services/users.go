@@ -2,42 +2,42 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"log"
 
 	"github.com/yamirghofran/briefbot/internal/db"
 )
 
 type UserService interface {
-	CreateUser(ctx context.Context, name, email, authProvider, oauthID, passwordHash *string) (*db.User, error)
 	GetUser(ctx context.Context, id int32) (*db.User, error)
 	GetUserByEmail(ctx context.Context, email *string) (*db.User, error)
 	ListUsers(ctx context.Context) ([]db.User, error)
-	UpdateUser(ctx context.Context, id int32, name, email, authProvider, oauthID, passwordHash *string) error
+	UpdateUser(ctx context.Context, id int32, name, email *string) error
 	DeleteUser(ctx context.Context, id int32) error
+	ExportUserData(ctx context.Context, id int32) (*UserExport, error)
+
+	// Configuration
+	SetFileStore(fileStore FileStore)
+	SetTxRunner(txRunner TxRunner)
+}
+
+// FileStore is the part of R2Service needed to remove a user's stored files
+type FileStore interface {
+	DeleteFiles(ctx context.Context, keys []string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+	ExtractKeyFromURL(url string) string
 }
 
 type userService struct {
-	querier db.Querier
+	querier   db.Querier
+	fileStore FileStore
+	txRunner  TxRunner
 }
 
 func NewUserService(querier db.Querier) UserService {
 	return &userService{querier: querier}
 }
 
-func (s *userService) CreateUser(ctx context.Context, name, email, authProvider, oauthID, passwordHash *string) (*db.User, error) {
-	params := db.CreateUserParams{
-		Name:         name,
-		Email:        email,
-		AuthProvider: authProvider,
-		OauthID:      oauthID,
-		PasswordHash: passwordHash,
-	}
-	user, err := s.querier.CreateUser(ctx, params)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
 func (s *userService) GetUser(ctx context.Context, id int32) (*db.User, error) {
 	user, err := s.querier.GetUser(ctx, id)
 	if err != nil {
@@ -62,18 +62,99 @@ func (s *userService) ListUsers(ctx context.Context) ([]db.User, error) {
 	return users, nil
 }
 
-func (s *userService) UpdateUser(ctx context.Context, id int32, name, email, authProvider, oauthID, passwordHash *string) error {
+// UpdateUser updates a user's profile. Passwords are changed through AuthService.
+// Changing the email marks it unverified and revokes outstanding password
+// reset links, which were sent to the old address.
+func (s *userService) UpdateUser(ctx context.Context, id int32, name, email *string) error {
+	user, err := s.querier.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	params := db.UpdateUserParams{
-		ID:           id,
-		Name:         name,
-		Email:        email,
-		AuthProvider: authProvider,
-		OauthID:      oauthID,
-		PasswordHash: passwordHash,
+		ID:    id,
+		Name:  name,
+		Email: email,
 	}
-	return s.querier.UpdateUser(ctx, params)
+	if err := s.querier.UpdateUser(ctx, params); err != nil {
+		return err
+	}
+
+	if !equalStringPtr(user.Email, email) {
+		if err := s.querier.DeletePasswordResetTokensByUser(ctx, id); err != nil {
+			return fmt.Errorf("failed to revoke password reset links: %w", err)
+		}
+	}
+	return nil
+}
+
+// equalStringPtr reports whether two optional strings hold the same value
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// SetFileStore enables removal of podcast audio and uploads when an account is deleted
+func (s *userService) SetFileStore(fileStore FileStore) {
+	s.fileStore = fileStore
+}
+
+// SetTxRunner makes account deletion run in a single transaction
+func (s *userService) SetTxRunner(txRunner TxRunner) {
+	s.txRunner = txRunner
 }
 
+// DeleteUser deletes an account together with its items, podcasts and stored
+// audio. The rows go in one transaction so a failure leaves the account
+// intact and the deletion can be retried. Stored files are removed after the
+// commit; a storage failure then only leaves orphaned files, which are logged.
 func (s *userService) DeleteUser(ctx context.Context, id int32) error {
-	return s.querier.DeleteUser(ctx, id)
+	userID := &id
+
+	var keys []string
+	if s.fileStore != nil {
+		podcasts, err := s.querier.GetPodcastByUser(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get podcasts: %w", err)
+		}
+		for _, podcast := range podcasts {
+			if podcast.AudioUrl != nil && *podcast.AudioUrl != "" {
+				keys = append(keys, s.fileStore.ExtractKeyFromURL(*podcast.AudioUrl))
+			}
+		}
+	}
+
+	err := runInTx(ctx, s.txRunner, s.querier, func(q db.Querier) error {
+		if err := q.DeletePodcastItemsByUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete podcast items: %w", err)
+		}
+		if err := q.DeletePodcastsByUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete podcasts: %w", err)
+		}
+		if err := q.DeleteItemsByUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete items: %w", err)
+		}
+		if err := q.DeleteUser(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.fileStore != nil {
+		if len(keys) > 0 {
+			if err := s.fileStore.DeleteFiles(ctx, keys); err != nil {
+				log.Printf("Failed to delete stored files of deleted user %d: %v", id, err)
+			}
+		}
+		// Uploads include files that never became items
+		if err := s.fileStore.DeletePrefix(ctx, uploadFolder(id)+"/"); err != nil {
+			log.Printf("Failed to delete uploads of deleted user %d: %v", id, err)
+		}
+	}
+	return nil
 }
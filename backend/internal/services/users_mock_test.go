@@ -11,14 +11,6 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) CreateUser(ctx context.Context, name, email, authProvider, oauthID, passwordHash *string) (*db.User, error) {
-	args := m.Called(ctx, name, email, authProvider, oauthID, passwordHash)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*db.User), args.Error(1)
-}
-
 func (m *MockUserService) GetUser(ctx context.Context, id int32) (*db.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -43,8 +35,8 @@ func (m *MockUserService) ListUsers(ctx context.Context) ([]db.User, error) {
 	return args.Get(0).([]db.User), args.Error(1)
 }
 
-func (m *MockUserService) UpdateUser(ctx context.Context, id int32, name, email, authProvider, oauthID, passwordHash *string) error {
-	args := m.Called(ctx, id, name, email, authProvider, oauthID, passwordHash)
+func (m *MockUserService) UpdateUser(ctx context.Context, id int32, name, email *string) error {
+	args := m.Called(ctx, id, name, email)
 	return args.Error(0)
 }
 
@@ -52,3 +44,19 @@ func (m *MockUserService) DeleteUser(ctx context.Context, id int32) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockUserService) ExportUserData(ctx context.Context, id int32) (*UserExport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*UserExport), args.Error(1)
+}
+
+func (m *MockUserService) SetFileStore(fileStore FileStore) {
+	m.Called(fileStore)
+}
+
+func (m *MockUserService) SetTxRunner(txRunner TxRunner) {
+	m.Called(txRunner)
+}
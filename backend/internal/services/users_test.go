@@ -11,55 +11,6 @@ import (
 	"github.com/yamirghofran/briefbot/internal/test"
 )
 
-func TestCreateUser(t *testing.T) {
-	mockQuerier := new(test.MockQuerier)
-	service := NewUserService(mockQuerier)
-
-	ctx := context.Background()
-	name := "John Doe"
-	email := "john@example.com"
-	authProvider := "google"
-	oauthID := "oauth123"
-	var passwordHash *string
-
-	expectedUser := db.User{
-		ID:           1,
-		Name:         &name,
-		Email:        &email,
-		AuthProvider: &authProvider,
-		OauthID:      &oauthID,
-	}
-
-	mockQuerier.On("CreateUser", ctx, mock.MatchedBy(func(params db.CreateUserParams) bool {
-		return *params.Name == name && *params.Email == email
-	})).Return(expectedUser, nil)
-
-	user, err := service.CreateUser(ctx, &name, &email, &authProvider, &oauthID, passwordHash)
-
-	assert.NoError(t, err)
-	assert.NotNil(t, user)
-	assert.Equal(t, int32(1), user.ID)
-	assert.Equal(t, name, *user.Name)
-	mockQuerier.AssertExpectations(t)
-}
-
-func TestCreateUser_Error(t *testing.T) {
-	mockQuerier := new(test.MockQuerier)
-	service := NewUserService(mockQuerier)
-
-	ctx := context.Background()
-	name := "John Doe"
-	email := "john@example.com"
-
-	mockQuerier.On("CreateUser", ctx, mock.Anything).Return(db.User{}, errors.New("database error"))
-
-	user, err := service.CreateUser(ctx, &name, &email, nil, nil, nil)
-
-	assert.Error(t, err)
-	assert.Nil(t, user)
-	mockQuerier.AssertExpectations(t)
-}
-
 func TestGetUser(t *testing.T) {
 	mockQuerier := new(test.MockQuerier)
 	service := NewUserService(mockQuerier)
@@ -138,11 +89,32 @@ func TestUpdateUser(t *testing.T) {
 	name := "Updated Name"
 	email := "updated@example.com"
 
+	mockQuerier.On("GetUser", ctx, userID).Return(db.User{ID: userID, Email: &email}, nil)
 	mockQuerier.On("UpdateUser", ctx, mock.MatchedBy(func(params db.UpdateUserParams) bool {
 		return params.ID == userID && *params.Name == name
 	})).Return(nil)
 
-	err := service.UpdateUser(ctx, userID, &name, &email, nil, nil, nil)
+	err := service.UpdateUser(ctx, userID, &name, &email)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "DeletePasswordResetTokensByUser", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUser_EmailChangeRevokesResetLinks(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewUserService(mockQuerier)
+
+	ctx := context.Background()
+	userID := int32(1)
+	oldEmail := "old@example.com"
+	newEmail := "new@example.com"
+
+	mockQuerier.On("GetUser", ctx, userID).Return(db.User{ID: userID, Email: &oldEmail, EmailVerified: true}, nil)
+	mockQuerier.On("UpdateUser", ctx, db.UpdateUserParams{ID: userID, Email: &newEmail}).Return(nil)
+	mockQuerier.On("DeletePasswordResetTokensByUser", ctx, userID).Return(nil)
+
+	err := service.UpdateUser(ctx, userID, nil, &newEmail)
 
 	assert.NoError(t, err)
 	mockQuerier.AssertExpectations(t)
@@ -155,10 +127,103 @@ func TestDeleteUser(t *testing.T) {
 	ctx := context.Background()
 	userID := int32(1)
 
+	// Without a file store only database rows are removed, children first
+	mockQuerier.On("DeletePodcastItemsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeletePodcastsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeleteItemsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeleteUser", ctx, userID).Return(nil)
+
+	err := service.DeleteUser(ctx, userID)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+	mockQuerier.AssertNotCalled(t, "GetPodcastByUser", mock.Anything, mock.Anything)
+}
+
+func TestDeleteUser_RemovesPodcastAudio(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockR2 := new(MockR2Service)
+	service := NewUserService(mockQuerier)
+	service.SetFileStore(mockR2)
+
+	ctx := context.Background()
+	userID := int32(1)
+	audioURL := "https://cdn.example.com/podcasts/1.mp3"
+
+	mockQuerier.On("GetPodcastByUser", ctx, &userID).Return([]db.Podcast{
+		{ID: 1, AudioUrl: &audioURL},
+		{ID: 2}, // still pending, no audio yet
+	}, nil)
+	mockR2.On("ExtractKeyFromURL", audioURL).Return("podcasts/1.mp3")
+	mockR2.On("DeleteFiles", ctx, []string{"podcasts/1.mp3"}).Return(nil)
+	mockR2.On("DeletePrefix", ctx, "uploads/1/").Return(nil)
+	mockQuerier.On("DeletePodcastItemsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeletePodcastsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeleteItemsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeleteUser", ctx, userID).Return(nil)
+
+	err := service.DeleteUser(ctx, userID)
+
+	assert.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+	mockR2.AssertExpectations(t)
+}
+
+func TestDeleteUser_StorageErrorAfterCommit(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockR2 := new(MockR2Service)
+	runner := &fakeTxRunner{querier: mockQuerier}
+	service := NewUserService(mockQuerier)
+	service.SetFileStore(mockR2)
+	service.SetTxRunner(runner)
+
+	ctx := context.Background()
+	userID := int32(1)
+	audioURL := "https://cdn.example.com/podcasts/1.mp3"
+
+	mockQuerier.On("GetPodcastByUser", ctx, &userID).Return([]db.Podcast{{ID: 1, AudioUrl: &audioURL}}, nil)
+	mockR2.On("ExtractKeyFromURL", audioURL).Return("podcasts/1.mp3")
+	mockQuerier.On("DeletePodcastItemsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeletePodcastsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeleteItemsByUser", ctx, &userID).Return(nil)
 	mockQuerier.On("DeleteUser", ctx, userID).Return(nil)
+	mockR2.On("DeleteFiles", ctx, []string{"podcasts/1.mp3"}).Return(errors.New("storage unavailable"))
+	mockR2.On("DeletePrefix", ctx, "uploads/1/").Return(errors.New("storage unavailable"))
 
 	err := service.DeleteUser(ctx, userID)
 
+	// The account is already gone; leftover files are only logged
 	assert.NoError(t, err)
+	assert.Equal(t, 1, runner.runs)
+	assert.False(t, runner.rolledBack)
 	mockQuerier.AssertExpectations(t)
+	mockR2.AssertExpectations(t)
+}
+
+func TestDeleteUser_DatabaseError(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	mockR2 := new(MockR2Service)
+	runner := &fakeTxRunner{querier: mockQuerier}
+	service := NewUserService(mockQuerier)
+	service.SetFileStore(mockR2)
+	service.SetTxRunner(runner)
+
+	ctx := context.Background()
+	userID := int32(1)
+	audioURL := "https://cdn.example.com/podcasts/1.mp3"
+
+	mockQuerier.On("GetPodcastByUser", ctx, &userID).Return([]db.Podcast{{ID: 1, AudioUrl: &audioURL}}, nil)
+	mockR2.On("ExtractKeyFromURL", audioURL).Return("podcasts/1.mp3")
+	mockQuerier.On("DeletePodcastItemsByUser", ctx, &userID).Return(nil)
+	mockQuerier.On("DeletePodcastsByUser", ctx, &userID).Return(errors.New("database error"))
+
+	err := service.DeleteUser(ctx, userID)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete podcasts")
+	// The transaction rolls back and stored files are kept so the deletion can be retried
+	assert.True(t, runner.rolledBack)
+	mockQuerier.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+	mockR2.AssertNotCalled(t, "DeleteFiles", mock.Anything, mock.Anything)
+	mockR2.AssertNotCalled(t, "DeletePrefix", mock.Anything, mock.Anything)
 }
@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"sync"
 	"time"
 
@@ -14,6 +16,8 @@ type WorkerService interface {
 	Start(ctx context.Context) error
 	Stop() error
 	IsRunning() bool
+	SetPreferencesService(preferencesService PreferencesService)
+	SetSemanticService(semanticService SemanticService)
 }
 
 type workerService struct {
@@ -22,6 +26,11 @@ type workerService struct {
 	scrapingService ScrapingService
 	podcastService  PodcastService
 
+	// Optional per-user settings for summaries
+	preferencesService PreferencesService
+	// Optional embedding of completed items for semantic search
+	semanticService SemanticService
+
 	// Configuration
 	workerCount    int
 	pollInterval   time.Duration
@@ -78,6 +87,16 @@ func NewWorkerService(
 	}
 }
 
+// SetPreferencesService enables per-user summary length and language
+func (s *workerService) SetPreferencesService(preferencesService PreferencesService) {
+	s.preferencesService = preferencesService
+}
+
+// SetSemanticService adds a stage that embeds completed items
+func (s *workerService) SetSemanticService(semanticService SemanticService) {
+	s.semanticService = semanticService
+}
+
 func (s *workerService) Start(ctx context.Context) error {
 	s.runningMu.Lock()
 	if s.running {
@@ -187,6 +206,13 @@ func (s *workerService) processBatch() error {
 		}
 	}
 
+	// Embed items completed since the last batch
+	if s.semanticService != nil {
+		if _, err := s.semanticService.EmbedPendingItems(s.ctx, s.batchSize); err != nil {
+			return fmt.Errorf("failed to embed items: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -222,22 +248,34 @@ func (s *workerService) processItem(ctx context.Context, item db.Item) error {
 		return fmt.Errorf("failed to mark item as processing: %w", err)
 	}
 
-	log.Printf("Processing item %d: %s", item.ID, *item.Url)
+	if item.Url != nil {
+		log.Printf("Processing item %d: %s", item.ID, *item.Url)
+	} else {
+		log.Printf("Processing item %d from %s source", item.ID, item.SourceType)
+	}
+
+	options := s.summaryOptionsFor(ctx, item.UserID)
 
-	// Process the URL with retry logic
+	// Process the item with retry logic
 	var textContent string
 	var extraction ItemExtraction
 	var summary string
 	var err error
 
 	// Retry processing up to maxRetries times
+	attempts := 0
 	for attempt := 1; attempt <= s.maxRetries; attempt++ {
-		textContent, extraction, summary, err = s.processURL(ctx, *item.Url)
+		attempts = attempt
+		textContent, extraction, summary, err = s.processItemContent(ctx, item, options)
 		if err == nil {
 			break // Success!
 		}
 
 		log.Printf("Attempt %d failed for item %d: %v", attempt, item.ID, err)
+		if isPermanentFetchError(err) {
+			// Retrying would only send the host the same request again
+			break
+		}
 
 		if attempt < s.maxRetries {
 			// Wait before retry with exponential backoff
@@ -253,11 +291,11 @@ func (s *workerService) processItem(ctx context.Context, item db.Item) error {
 
 	if err != nil {
 		// All retries failed, mark as failed
-		errorMsg := fmt.Sprintf("Failed after %d attempts: %v", s.maxRetries, err)
-		if failErr := s.jobQueueService.FailItem(ctx, item.ID, errorMsg); failErr != nil {
+		errorMsg := fmt.Sprintf("Failed after %d attempts: %v", attempts, err)
+		if failErr := s.jobQueueService.FailItem(ctx, item.ID, FailureReason(err), errorMsg); failErr != nil {
 			log.Printf("Failed to mark item %d as failed: %v", item.ID, failErr)
 		}
-		return fmt.Errorf("failed to process URL after %d attempts: %w", s.maxRetries, err)
+		return fmt.Errorf("failed to process URL after %d attempts: %w", attempts, err)
 	}
 
 	// Mark as completed with AI-extracted title
@@ -269,23 +307,103 @@ func (s *workerService) processItem(ctx context.Context, item db.Item) error {
 	return nil
 }
 
-func (s *workerService) processURL(ctx context.Context, url string) (string, ItemExtraction, string, error) {
-	// Scrape content
-	content, err := s.scrapingService.Scrape(url)
+// summaryOptionsFor loads the item owner's summary preferences, falling back to defaults
+func (s *workerService) summaryOptionsFor(ctx context.Context, userID *int32) SummaryOptions {
+	if s.preferencesService == nil || userID == nil {
+		return SummaryOptions{}
+	}
+	prefs, err := s.preferencesService.GetPreferences(ctx, *userID)
+	if err != nil {
+		log.Printf("Failed to load preferences for user %d, using default summary options: %v", *userID, err)
+		return SummaryOptions{}
+	}
+	return SummaryOptionsFromPreferences(*prefs)
+}
+
+// processItemContent scrapes URL items and parses the stored source of
+// pasted and uploaded items, then extracts metadata and summarizes the content
+func (s *workerService) processItemContent(ctx context.Context, item db.Item, options SummaryOptions) (string, ItemExtraction, string, error) {
+	if item.SourceType != "" && item.SourceType != SourceTypeURL {
+		return s.processSource(ctx, item, options)
+	}
+	if item.Url == nil {
+		return "", ItemExtraction{}, "", errors.New("item has no URL")
+	}
+	return s.processURL(ctx, item, options)
+}
+
+func (s *workerService) processURL(ctx context.Context, item db.Item, options SummaryOptions) (string, ItemExtraction, string, error) {
+	// Scrape the main content and page metadata
+	scraped, err := s.scrapingService.Scrape(*item.Url)
 	if err != nil {
 		return "", ItemExtraction{}, "", fmt.Errorf("failed to scrape URL: %w", err)
 	}
 
+	// The page may have been saved under another URL that redirects to it or
+	// shares its canonical link; stop before summarizing it a second time
+	if err := s.jobQueueService.SetCanonicalURL(ctx, item, scrapedCanonicalURL(scraped)); err != nil {
+		if errors.Is(err, ErrDuplicateItem) {
+			return "", ItemExtraction{}, "", err
+		}
+		log.Printf("Failed to record canonical URL of item %d: %v", item.ID, err)
+	}
+	return s.processScraped(ctx, scraped, options)
+}
+
+// isPermanentFetchError reports whether a page failed in a way retrying
+// cannot fix: robots.txt disallows it, it is too large, it is walled off or
+// empty, it duplicates another item, or the host answered with a client error
+// other than a timeout or rate limit
+func isPermanentFetchError(err error) bool {
+	for _, permanent := range []error{ErrDisallowedByRobots, ErrBodyTooLarge, ErrPaywall, ErrLoginWall, ErrCaptcha, ErrEmptyContent, ErrDuplicateItem} {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 &&
+			statusErr.StatusCode != http.StatusRequestTimeout && statusErr.StatusCode != http.StatusTooManyRequests
+	}
+	return false
+}
+
+// processSource parses the content stored with an item instead of scraping.
+// A title the user gave when creating the item is kept.
+func (s *workerService) processSource(ctx context.Context, item db.Item, options SummaryOptions) (string, ItemExtraction, string, error) {
+	if item.SourceContent == nil {
+		return "", ItemExtraction{}, "", errors.New("item has no source content")
+	}
+	parsed, err := ParseSource(item.SourceType, *item.SourceContent)
+	if err != nil {
+		return "", ItemExtraction{}, "", fmt.Errorf("failed to parse %s source: %w", item.SourceType, err)
+	}
+
+	content, extraction, summary, err := s.processScraped(ctx, parsed, options)
+	if err != nil {
+		return "", ItemExtraction{}, "", err
+	}
+	if item.Title != "" {
+		extraction.Title = item.Title
+	}
+	return content, extraction, summary, nil
+}
+
+// processScraped extracts metadata from and summarizes scraped or parsed content
+func (s *workerService) processScraped(ctx context.Context, scraped *ScrapeResult, options SummaryOptions) (string, ItemExtraction, string, error) {
+	content := scraped.Content
+
 	// Extract metadata
 	extraction, err := s.aiService.ExtractContent(ctx, content)
 	if err != nil {
-		return "", ItemExtraction{}, "", fmt.Errorf("failed to extract content: %w", err)
+		return "", ItemExtraction{}, "", &aiError{fmt.Errorf("failed to extract content: %w", err)}
 	}
+	applyScrapeMetadata(&extraction, scraped)
 
 	// Summarize content
-	summary, err := s.aiService.SummarizeContent(ctx, content)
+	summary, err := s.aiService.SummarizeContent(ctx, content, options)
 	if err != nil {
-		return "", ItemExtraction{}, "", fmt.Errorf("failed to summarize content: %w", err)
+		return "", ItemExtraction{}, "", &aiError{fmt.Errorf("failed to summarize content: %w", err)}
 	}
 
 	// Concatenate summary
@@ -332,6 +450,10 @@ func (s *workerService) processPodcast(ctx context.Context, podcast db.Podcast)
 		if err == nil {
 			break // Success!
 		}
+		// The quota does not reset until the next day
+		if errors.Is(err, ErrQuotaExceeded) {
+			break
+		}
 
 		log.Printf("Attempt %d failed for podcast %d: %v", attempt, podcast.ID, err)
 
@@ -24,3 +24,11 @@ func (m *MockWorkerService) IsRunning() bool {
 	args := m.Called()
 	return args.Bool(0)
 }
+
+func (m *MockWorkerService) SetPreferencesService(preferencesService PreferencesService) {
+	m.Called(preferencesService)
+}
+
+func (m *MockWorkerService) SetSemanticService(semanticService SemanticService) {
+	m.Called(semanticService)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -165,9 +166,10 @@ func TestWorkerService_ProcessItem_Success(t *testing.T) {
 	}
 
 	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
-	mockScraping.On("Scrape", url).Return(content, nil)
+	mockScraping.On("Scrape", url).Return(&ScrapeResult{Content: content}, nil)
+	mockJobQueue.On("SetCanonicalURL", ctx, item, "").Return(nil)
 	mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
-	mockAI.On("SummarizeContent", ctx, content).Return(summary, nil)
+	mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(summary, nil)
 	mockJobQueue.On("CompleteItem", ctx, item.ID, extraction.Title, content, mock.Anything, extraction.Type, extraction.Platform, extraction.Tags, extraction.Authors).Return(nil)
 
 	err := service.processItem(ctx, item)
@@ -178,6 +180,96 @@ func TestWorkerService_ProcessItem_Success(t *testing.T) {
 	mockAI.AssertExpectations(t)
 }
 
+func TestWorkerService_ProcessItem_Source(t *testing.T) {
+	config := WorkerConfig{WorkerCount: 1, MaxRetries: 1, BatchSize: 5}
+	ctx := context.Background()
+	source := "<h1>This Week in Infrastructure</h1><p>Reserved instances cut our compute bill by twelve percent.</p>"
+	content := "# This Week in Infrastructure\n\nReserved instances cut our compute bill by twelve percent."
+	extraction := ItemExtraction{Title: "Cutting Compute Costs", Type: "article", Platform: "newsletter"}
+	summary := ItemSummary{Overview: "Reserved instances saved money", KeyPoints: []string{"Twelve percent"}}
+
+	tests := []struct {
+		name          string
+		title         string
+		expectedTitle string
+	}{
+		{name: "extracted title", title: "", expectedTitle: "Cutting Compute Costs"},
+		{name: "title given by the user", title: "Infra Newsletter", expectedTitle: "Infra Newsletter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockJobQueue := new(MockJobQueueService)
+			mockAI := new(MockAIService)
+			mockScraping := new(MockScrapingService)
+			service := NewWorkerService(mockJobQueue, mockAI, mockScraping, new(MockPodcastService), config).(*workerService)
+
+			item := db.Item{ID: 1, Title: tt.title, SourceType: SourceTypeHTML, SourceContent: &source}
+
+			mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
+			mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
+			mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(summary, nil)
+			mockJobQueue.On("CompleteItem", ctx, item.ID, tt.expectedTitle, content, mock.Anything, extraction.Type, extraction.Platform, extraction.Tags, extraction.Authors).Return(nil)
+
+			err := service.processItem(ctx, item)
+
+			assert.NoError(t, err)
+			mockJobQueue.AssertExpectations(t)
+			mockAI.AssertExpectations(t)
+			mockScraping.AssertNotCalled(t, "Scrape", mock.Anything)
+		})
+	}
+}
+
+func TestWorkerService_ProcessItem_UsesOwnerSummaryPreferences(t *testing.T) {
+	mockJobQueue := new(MockJobQueueService)
+	mockAI := new(MockAIService)
+	mockScraping := new(MockScrapingService)
+	mockPodcast := new(MockPodcastService)
+	mockPreferences := new(MockPreferencesService)
+
+	config := WorkerConfig{
+		WorkerCount:  1,
+		PollInterval: 1 * time.Second,
+		MaxRetries:   1,
+		BatchSize:    5,
+	}
+
+	service := NewWorkerService(mockJobQueue, mockAI, mockScraping, mockPodcast, config).(*workerService)
+	service.SetPreferencesService(mockPreferences)
+
+	ctx := context.Background()
+	url := "https://example.com/article"
+	userID := int32(7)
+	item := db.Item{
+		ID:     1,
+		UserID: &userID,
+		Url:    &url,
+	}
+
+	prefs := DefaultUserPreferences(userID)
+	prefs.SummaryLength = SummaryLengthShort
+	prefs.SummaryLanguage = "de"
+
+	content := "Article content here"
+	extraction := ItemExtraction{Title: "Test Article", Type: "article"}
+	summary := ItemSummary{Overview: "Kurzer Überblick"}
+
+	mockPreferences.On("GetPreferences", ctx, userID).Return(&prefs, nil)
+	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
+	mockScraping.On("Scrape", url).Return(&ScrapeResult{Content: content}, nil)
+	mockJobQueue.On("SetCanonicalURL", ctx, item, "").Return(nil)
+	mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
+	mockAI.On("SummarizeContent", ctx, content, SummaryOptions{Length: SummaryLengthShort, Language: "de"}).Return(summary, nil)
+	mockJobQueue.On("CompleteItem", ctx, item.ID, extraction.Title, content, mock.Anything, extraction.Type, extraction.Platform, extraction.Tags, extraction.Authors).Return(nil)
+
+	err := service.processItem(ctx, item)
+
+	assert.NoError(t, err)
+	mockPreferences.AssertExpectations(t)
+	mockAI.AssertExpectations(t)
+}
+
 func TestWorkerService_ProcessItem_ScrapingFails(t *testing.T) {
 	mockJobQueue := new(MockJobQueueService)
 	mockAI := new(MockAIService)
@@ -203,8 +295,8 @@ func TestWorkerService_ProcessItem_ScrapingFails(t *testing.T) {
 	scrapingError := errors.New("scraping failed")
 
 	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
-	mockScraping.On("Scrape", url).Return("", scrapingError).Times(2) // MaxRetries = 2
-	mockJobQueue.On("FailItem", ctx, item.ID, mock.MatchedBy(func(msg string) bool {
+	mockScraping.On("Scrape", url).Return(nil, scrapingError).Times(2) // MaxRetries = 2
+	mockJobQueue.On("FailItem", ctx, item.ID, FailureReasonUnknown, mock.MatchedBy(func(msg string) bool {
 		return true // Accept any error message
 	})).Return(nil)
 
@@ -216,6 +308,74 @@ func TestWorkerService_ProcessItem_ScrapingFails(t *testing.T) {
 	mockScraping.AssertExpectations(t)
 }
 
+func TestWorkerService_ProcessItem_PermanentFetchErrorNotRetried(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		reason string
+	}{
+		"robots":    {fmt.Errorf("%w: https://example.com/article", ErrDisallowedByRobots), FailureReasonRobots},
+		"not found": {&HTTPStatusError{URL: "https://example.com/article", StatusCode: 404}, FailureReasonNotFound},
+		"too large": {fmt.Errorf("%w: https://example.com/article", ErrBodyTooLarge), FailureReasonTooLarge},
+		"paywall":   {fmt.Errorf("%w: https://example.com/article", ErrPaywall), FailureReasonPaywall},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJobQueue := new(MockJobQueueService)
+			mockScraping := new(MockScrapingService)
+			config := WorkerConfig{WorkerCount: 1, PollInterval: time.Second, MaxRetries: 3, BatchSize: 5}
+			service := NewWorkerService(mockJobQueue, new(MockAIService), mockScraping, new(MockPodcastService), config).(*workerService)
+
+			ctx := context.Background()
+			url := "https://example.com/article"
+			item := db.Item{ID: 1, Url: &url}
+
+			mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
+			mockScraping.On("Scrape", url).Return(nil, tt.err).Once()
+			mockJobQueue.On("FailItem", ctx, item.ID, tt.reason, mock.MatchedBy(func(msg string) bool {
+				return strings.HasPrefix(msg, "Failed after 1 attempts")
+			})).Return(nil)
+
+			err := service.processItem(ctx, item)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Contains(t, err.Error(), "failed to process URL after 1 attempts")
+			mockJobQueue.AssertExpectations(t)
+			mockScraping.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWorkerService_ProcessItem_DuplicateAfterRedirect(t *testing.T) {
+	mockJobQueue := new(MockJobQueueService)
+	mockAI := new(MockAIService)
+	mockScraping := new(MockScrapingService)
+	config := WorkerConfig{WorkerCount: 1, PollInterval: time.Second, MaxRetries: 3, BatchSize: 5}
+	service := NewWorkerService(mockJobQueue, mockAI, mockScraping, new(MockPodcastService), config).(*workerService)
+
+	ctx := context.Background()
+	url := "https://t.co/abc"
+	item := db.Item{ID: 2, Url: &url}
+	scraped := &ScrapeResult{
+		URL:          "https://example.com/post?ref=social",
+		CanonicalURL: "https://example.com/post",
+		Content:      "Article content here",
+	}
+
+	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
+	mockScraping.On("Scrape", url).Return(scraped, nil).Once()
+	mockJobQueue.On("SetCanonicalURL", ctx, item, "https://example.com/post").
+		Return(fmt.Errorf("%w: same page as item 1", ErrDuplicateItem)).Once()
+	mockJobQueue.On("FailItem", ctx, item.ID, FailureReasonDuplicate, mock.MatchedBy(func(msg string) bool {
+		return strings.Contains(msg, "same page as item 1")
+	})).Return(nil)
+
+	err := service.processItem(ctx, item)
+
+	assert.ErrorIs(t, err, ErrDuplicateItem)
+	mockJobQueue.AssertExpectations(t)
+	mockAI.AssertNotCalled(t, "ExtractContent", mock.Anything, mock.Anything)
+}
+
 func TestWorkerService_ProcessItem_ExtractionFails(t *testing.T) {
 	mockJobQueue := new(MockJobQueueService)
 	mockAI := new(MockAIService)
@@ -242,9 +402,10 @@ func TestWorkerService_ProcessItem_ExtractionFails(t *testing.T) {
 	extractionError := errors.New("extraction failed")
 
 	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
-	mockScraping.On("Scrape", url).Return(content, nil).Times(2)
+	mockScraping.On("Scrape", url).Return(&ScrapeResult{Content: content}, nil).Times(2)
+	mockJobQueue.On("SetCanonicalURL", ctx, item, "").Return(nil).Times(2)
 	mockAI.On("ExtractContent", ctx, content).Return(ItemExtraction{}, extractionError).Times(2)
-	mockJobQueue.On("FailItem", ctx, item.ID, mock.Anything).Return(nil)
+	mockJobQueue.On("FailItem", ctx, item.ID, FailureReasonAI, mock.Anything).Return(nil)
 
 	err := service.processItem(ctx, item)
 
@@ -285,10 +446,11 @@ func TestWorkerService_ProcessItem_SummarizationFails(t *testing.T) {
 	summarizationError := errors.New("summarization failed")
 
 	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
-	mockScraping.On("Scrape", url).Return(content, nil).Times(2)
+	mockScraping.On("Scrape", url).Return(&ScrapeResult{Content: content}, nil).Times(2)
+	mockJobQueue.On("SetCanonicalURL", ctx, item, "").Return(nil).Times(2)
 	mockAI.On("ExtractContent", ctx, content).Return(extraction, nil).Times(2)
-	mockAI.On("SummarizeContent", ctx, content).Return(ItemSummary{}, summarizationError).Times(2)
-	mockJobQueue.On("FailItem", ctx, item.ID, mock.Anything).Return(nil)
+	mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(ItemSummary{}, summarizationError).Times(2)
+	mockJobQueue.On("FailItem", ctx, item.ID, FailureReasonAI, mock.Anything).Return(nil)
 
 	err := service.processItem(ctx, item)
 
@@ -333,10 +495,11 @@ func TestWorkerService_ProcessItem_RetrySuccess(t *testing.T) {
 
 	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
 	// Fail twice, succeed on third attempt
-	mockScraping.On("Scrape", url).Return("", errors.New("scraping failed")).Times(2)
-	mockScraping.On("Scrape", url).Return(content, nil).Once()
+	mockScraping.On("Scrape", url).Return(nil, errors.New("scraping failed")).Times(2)
+	mockScraping.On("Scrape", url).Return(&ScrapeResult{Content: content}, nil).Once()
+	mockJobQueue.On("SetCanonicalURL", ctx, item, "").Return(nil).Once()
 	mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
-	mockAI.On("SummarizeContent", ctx, content).Return(summary, nil)
+	mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(summary, nil)
 	mockJobQueue.On("CompleteItem", ctx, item.ID, extraction.Title, content, mock.Anything, extraction.Type, extraction.Platform, mock.Anything, mock.Anything).Return(nil)
 
 	err := service.processItem(ctx, item)
@@ -465,6 +628,29 @@ func TestWorkerService_ProcessPodcast_Failure(t *testing.T) {
 	mockPodcast.AssertExpectations(t)
 }
 
+func TestWorkerService_ProcessPodcast_QuotaExceededIsNotRetried(t *testing.T) {
+	mockPodcast := new(MockPodcastService)
+	config := WorkerConfig{
+		WorkerCount:    1,
+		PollInterval:   1 * time.Second,
+		MaxRetries:     3,
+		BatchSize:      5,
+		EnablePodcasts: true,
+	}
+	service := NewWorkerService(new(MockJobQueueService), new(MockAIService), new(MockScrapingService), mockPodcast, config).(*workerService)
+
+	ctx := context.Background()
+	podcast := db.Podcast{ID: 1, Title: "Test Podcast"}
+
+	mockPodcast.On("ProcessPodcast", ctx, podcast.ID).Return(&QuotaExceededError{Quota: QuotaPodcastMinutes, Limit: 30}).Once()
+	mockPodcast.On("UpdatePodcastStatus", ctx, podcast.ID, PodcastStatusFailed).Return(nil)
+
+	err := service.processPodcast(ctx, podcast)
+
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	mockPodcast.AssertExpectations(t)
+}
+
 func TestWorkerService_ProcessPodcast_RetrySuccess(t *testing.T) {
 	mockJobQueue := new(MockJobQueueService)
 	mockAI := new(MockAIService)
@@ -634,6 +820,31 @@ func TestWorkerService_ProcessBatch_PodcastServiceNil(t *testing.T) {
 	mockJobQueue.AssertExpectations(t)
 }
 
+func TestWorkerService_ProcessBatch_EmbedsItems(t *testing.T) {
+	mockJobQueue := new(MockJobQueueService)
+	mockSemantic := new(MockSemanticService)
+
+	config := WorkerConfig{
+		WorkerCount:  1,
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    5,
+	}
+
+	service := NewWorkerService(mockJobQueue, new(MockAIService), new(MockScrapingService), nil, config).(*workerService)
+	service.SetSemanticService(mockSemantic)
+
+	ctx := context.Background()
+	service.ctx = ctx
+	mockJobQueue.On("DequeuePendingItems", ctx, config.BatchSize).Return([]db.Item{}, nil)
+	mockSemantic.On("EmbedPendingItems", ctx, config.BatchSize).Return(0, errors.New("embeddings API unavailable"))
+
+	err := service.processBatch()
+
+	assert.ErrorContains(t, err, "failed to embed items")
+	mockJobQueue.AssertExpectations(t)
+	mockSemantic.AssertExpectations(t)
+}
+
 func TestWorkerService_ProcessURL_Success(t *testing.T) {
 	mockJobQueue := new(MockJobQueueService)
 	mockAI := new(MockAIService)
@@ -662,11 +873,13 @@ func TestWorkerService_ProcessURL_Success(t *testing.T) {
 		KeyPoints: []string{"Key point 1"},
 	}
 
-	mockScraping.On("Scrape", url).Return(content, nil)
+	item := db.Item{ID: 1, Url: &url}
+	mockScraping.On("Scrape", url).Return(&ScrapeResult{URL: url, Content: content}, nil)
+	mockJobQueue.On("SetCanonicalURL", ctx, item, url).Return(nil)
 	mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
-	mockAI.On("SummarizeContent", ctx, content).Return(summary, nil)
+	mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(summary, nil)
 
-	resultContent, resultExtraction, resultSummary, err := service.processURL(ctx, url)
+	resultContent, resultExtraction, resultSummary, err := service.processURL(ctx, item, SummaryOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, content, resultContent)
@@ -743,9 +956,10 @@ func TestWorkerService_CompleteItemError(t *testing.T) {
 	}
 
 	mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
-	mockScraping.On("Scrape", url).Return(content, nil)
+	mockScraping.On("Scrape", url).Return(&ScrapeResult{Content: content}, nil)
+	mockJobQueue.On("SetCanonicalURL", ctx, item, "").Return(nil)
 	mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
-	mockAI.On("SummarizeContent", ctx, content).Return(summary, nil)
+	mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(summary, nil)
 	mockJobQueue.On("CompleteItem", ctx, item.ID, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("failed to complete item"))
 
 	err := service.processItem(ctx, item)
@@ -839,9 +1053,10 @@ func TestWorkerService_ProcessItemBatch_MultipleItems(t *testing.T) {
 	// Setup expectations for both items
 	for _, item := range items {
 		mockJobQueue.On("MarkItemAsProcessing", ctx, item.ID).Return(nil)
-		mockScraping.On("Scrape", *item.Url).Return(content, nil)
+		mockScraping.On("Scrape", *item.Url).Return(&ScrapeResult{Content: content}, nil)
+		mockJobQueue.On("SetCanonicalURL", ctx, item, "").Return(nil)
 		mockAI.On("ExtractContent", ctx, content).Return(extraction, nil)
-		mockAI.On("SummarizeContent", ctx, content).Return(summary, nil)
+		mockAI.On("SummarizeContent", ctx, content, SummaryOptions{}).Return(summary, nil)
 		mockJobQueue.On("CompleteItem", ctx, item.ID, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	}
 
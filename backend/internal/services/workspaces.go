@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yamirghofran/briefbot/internal/db"
+)
+
+// Workspace roles, from most to least privileged
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+	WorkspaceRoleViewer = "viewer"
+)
+
+// Workspace errors returned by WorkspaceService and the item service
+var (
+	ErrWorkspaceNotFound      = errors.New("workspace not found")
+	ErrWorkspaceForbidden     = errors.New("insufficient workspace role")
+	ErrInvalidWorkspace       = errors.New("invalid workspace")
+	ErrWorkspaceMemberExists  = errors.New("user is already a member of this workspace")
+	ErrWorkspaceMemberMissing = errors.New("workspace member not found")
+	ErrLastWorkspaceOwner     = errors.New("a workspace must keep at least one owner")
+)
+
+var workspaceRoleRank = map[string]int{
+	WorkspaceRoleViewer: 1,
+	WorkspaceRoleMember: 2,
+	WorkspaceRoleAdmin:  3,
+	WorkspaceRoleOwner:  4,
+}
+
+// WorkspaceService manages workspaces and their memberships.
+// Members can read the shared list, members and above can add items, admins
+// manage non-owner members and owners can do everything including deletion.
+type WorkspaceService interface {
+	CreateWorkspace(ctx context.Context, userID int32, name string) (*db.Workspace, error)
+	ListWorkspaces(ctx context.Context, userID int32) ([]db.ListWorkspacesByUserRow, error)
+	GetWorkspace(ctx context.Context, userID int32, workspaceID int32) (*db.Workspace, string, error)
+	RenameWorkspace(ctx context.Context, userID int32, workspaceID int32, name string) (*db.Workspace, string, error)
+	DeleteWorkspace(ctx context.Context, userID int32, workspaceID int32) error
+
+	ListMembers(ctx context.Context, userID int32, workspaceID int32) ([]db.ListWorkspaceMembersRow, error)
+	AddMember(ctx context.Context, userID int32, workspaceID int32, email string, role string) (*db.WorkspaceMember, error)
+	UpdateMemberRole(ctx context.Context, userID int32, workspaceID int32, memberID int32, role string) (*db.WorkspaceMember, error)
+	RemoveMember(ctx context.Context, userID int32, workspaceID int32, memberID int32) error
+}
+
+type workspaceService struct {
+	querier db.Querier
+}
+
+func NewWorkspaceService(querier db.Querier) WorkspaceService {
+	return &workspaceService{querier: querier}
+}
+
+// IsValidWorkspaceRole reports whether role is one of the known workspace roles
+func IsValidWorkspaceRole(role string) bool {
+	_, ok := workspaceRoleRank[role]
+	return ok
+}
+
+// WorkspaceRoleAtLeast reports whether role grants at least the privileges of minRole
+func WorkspaceRoleAtLeast(role string, minRole string) bool {
+	return workspaceRoleRank[role] >= workspaceRoleRank[minRole]
+}
+
+// requireWorkspaceRole loads the user's membership and checks it grants minRole.
+// Non-members get ErrWorkspaceNotFound so they cannot probe other workspaces.
+func requireWorkspaceRole(ctx context.Context, querier db.Querier, userID int32, workspaceID int32, minRole string) (*db.WorkspaceMember, error) {
+	member, err := querier.GetWorkspaceMember(ctx, db.GetWorkspaceMemberParams{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace membership: %w", err)
+	}
+	if !WorkspaceRoleAtLeast(member.Role, minRole) {
+		return nil, ErrWorkspaceForbidden
+	}
+	return &member, nil
+}
+
+func (s *workspaceService) CreateWorkspace(ctx context.Context, userID int32, name string) (*db.Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidWorkspace)
+	}
+
+	// The creator is added as owner in the same statement
+	workspace, err := s.querier.CreateWorkspace(ctx, db.CreateWorkspaceParams{
+		Name:      name,
+		CreatedBy: &userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return &workspace, nil
+}
+
+func (s *workspaceService) ListWorkspaces(ctx context.Context, userID int32) ([]db.ListWorkspacesByUserRow, error) {
+	workspaces, err := s.querier.ListWorkspacesByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return workspaces, nil
+}
+
+// GetWorkspace returns the workspace together with the caller's role in it
+func (s *workspaceService) GetWorkspace(ctx context.Context, userID int32, workspaceID int32) (*db.Workspace, string, error) {
+	member, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleViewer)
+	if err != nil {
+		return nil, "", err
+	}
+	workspace, err := s.querier.GetWorkspace(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", ErrWorkspaceNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get workspace: %w", err)
+	}
+	return &workspace, member.Role, nil
+}
+
+// RenameWorkspace renames the workspace and returns it with the caller's role
+func (s *workspaceService) RenameWorkspace(ctx context.Context, userID int32, workspaceID int32, name string) (*db.Workspace, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidWorkspace)
+	}
+	member, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleAdmin)
+	if err != nil {
+		return nil, "", err
+	}
+
+	workspace, err := s.querier.UpdateWorkspaceName(ctx, db.UpdateWorkspaceNameParams{
+		ID:   workspaceID,
+		Name: name,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to rename workspace: %w", err)
+	}
+	return &workspace, member.Role, nil
+}
+
+// DeleteWorkspace removes the workspace; its items fall back to their owners' personal lists
+func (s *workspaceService) DeleteWorkspace(ctx context.Context, userID int32, workspaceID int32) error {
+	if _, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleOwner); err != nil {
+		return err
+	}
+	if err := s.querier.DeleteWorkspace(ctx, workspaceID); err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+	return nil
+}
+
+func (s *workspaceService) ListMembers(ctx context.Context, userID int32, workspaceID int32) ([]db.ListWorkspaceMembersRow, error) {
+	if _, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	members, err := s.querier.ListWorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace members: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember adds an existing user by email. Only owners can add other owners.
+func (s *workspaceService) AddMember(ctx context.Context, userID int32, workspaceID int32, email string, role string) (*db.WorkspaceMember, error) {
+	if role == "" {
+		role = WorkspaceRoleMember
+	}
+	if !IsValidWorkspaceRole(role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidWorkspace, role)
+	}
+
+	actor, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if role == WorkspaceRoleOwner && actor.Role != WorkspaceRoleOwner {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	email = strings.TrimSpace(email)
+	user, err := s.querier.GetUserByEmail(ctx, &email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no user with email %q", ErrInvalidWorkspace, email)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	_, err = s.querier.GetWorkspaceMember(ctx, db.GetWorkspaceMemberParams{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+	})
+	if err == nil {
+		return nil, ErrWorkspaceMemberExists
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get workspace membership: %w", err)
+	}
+
+	member, err := s.querier.AddWorkspaceMember(ctx, db.AddWorkspaceMemberParams{
+		WorkspaceID: workspaceID,
+		UserID:      user.ID,
+		Role:        role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add workspace member: %w", err)
+	}
+	return &member, nil
+}
+
+// UpdateMemberRole changes a member's role. Admins can only manage non-owners;
+// granting or revoking ownership requires an owner.
+func (s *workspaceService) UpdateMemberRole(ctx context.Context, userID int32, workspaceID int32, memberID int32, role string) (*db.WorkspaceMember, error) {
+	if !IsValidWorkspaceRole(role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidWorkspace, role)
+	}
+
+	actor, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, WorkspaceRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if (role == WorkspaceRoleOwner || target.Role == WorkspaceRoleOwner) && actor.Role != WorkspaceRoleOwner {
+		return nil, ErrWorkspaceForbidden
+	}
+	if target.Role == WorkspaceRoleOwner && role != WorkspaceRoleOwner {
+		if err := s.ensureAnotherOwner(ctx, workspaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	member, err := s.querier.UpdateWorkspaceMemberRole(ctx, db.UpdateWorkspaceMemberRoleParams{
+		WorkspaceID: workspaceID,
+		UserID:      memberID,
+		Role:        role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update workspace member: %w", err)
+	}
+	return &member, nil
+}
+
+// RemoveMember removes a member from the workspace. Any member may remove
+// themselves; removing someone else requires an admin, or an owner for owners.
+func (s *workspaceService) RemoveMember(ctx context.Context, userID int32, workspaceID int32, memberID int32) error {
+	minRole := WorkspaceRoleAdmin
+	if memberID == userID {
+		minRole = WorkspaceRoleViewer
+	}
+	actor, err := requireWorkspaceRole(ctx, s.querier, userID, workspaceID, minRole)
+	if err != nil {
+		return err
+	}
+	target, err := s.getMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return err
+	}
+	if target.Role == WorkspaceRoleOwner {
+		if actor.Role != WorkspaceRoleOwner {
+			return ErrWorkspaceForbidden
+		}
+		if err := s.ensureAnotherOwner(ctx, workspaceID); err != nil {
+			return err
+		}
+	}
+
+	removed, err := s.querier.RemoveWorkspaceMember(ctx, db.RemoveWorkspaceMemberParams{
+		WorkspaceID: workspaceID,
+		UserID:      memberID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+	if removed == 0 {
+		return ErrWorkspaceMemberMissing
+	}
+	return nil
+}
+
+func (s *workspaceService) getMember(ctx context.Context, workspaceID int32, memberID int32) (*db.WorkspaceMember, error) {
+	member, err := s.querier.GetWorkspaceMember(ctx, db.GetWorkspaceMemberParams{
+		WorkspaceID: workspaceID,
+		UserID:      memberID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkspaceMemberMissing
+		}
+		return nil, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+	return &member, nil
+}
+
+// ensureAnotherOwner guards against demoting or removing the last owner
+func (s *workspaceService) ensureAnotherOwner(ctx context.Context, workspaceID int32) error {
+	owners, err := s.querier.CountWorkspaceOwners(ctx, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to count workspace owners: %w", err)
+	}
+	if owners <= 1 {
+		return ErrLastWorkspaceOwner
+	}
+	return nil
+}
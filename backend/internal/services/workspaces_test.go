@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yamirghofran/briefbot/internal/db"
+	"github.com/yamirghofran/briefbot/internal/test"
+)
+
+func memberParams(workspaceID, userID int32) db.GetWorkspaceMemberParams {
+	return db.GetWorkspaceMemberParams{WorkspaceID: workspaceID, UserID: userID}
+}
+
+func TestCreateWorkspace(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+	userID := int32(1)
+
+	mockQuerier.On("CreateWorkspace", ctx, db.CreateWorkspaceParams{Name: "Research", CreatedBy: &userID}).
+		Return(db.Workspace{ID: 3, Name: "Research", CreatedBy: &userID}, nil)
+
+	workspace, err := service.CreateWorkspace(ctx, userID, "  Research ")
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), workspace.ID)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestCreateWorkspace_EmptyName(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+
+	workspace, err := service.CreateWorkspace(context.Background(), 1, "   ")
+
+	assert.ErrorIs(t, err, ErrInvalidWorkspace)
+	assert.Nil(t, workspace)
+	mockQuerier.AssertNotCalled(t, "CreateWorkspace", mock.Anything, mock.Anything)
+}
+
+func TestGetWorkspace_NotMember(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{}, pgx.ErrNoRows)
+
+	workspace, role, err := service.GetWorkspace(ctx, 1, 3)
+
+	assert.ErrorIs(t, err, ErrWorkspaceNotFound)
+	assert.Nil(t, workspace)
+	assert.Empty(t, role)
+	mockQuerier.AssertNotCalled(t, "GetWorkspace", mock.Anything, mock.Anything)
+}
+
+func TestRenameWorkspace_RequiresAdmin(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{WorkspaceID: 3, UserID: 1, Role: WorkspaceRoleMember}, nil)
+
+	_, _, err := service.RenameWorkspace(ctx, 1, 3, "Renamed")
+
+	assert.ErrorIs(t, err, ErrWorkspaceForbidden)
+	mockQuerier.AssertNotCalled(t, "UpdateWorkspaceName", mock.Anything, mock.Anything)
+}
+
+func TestDeleteWorkspace_RequiresOwner(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{WorkspaceID: 3, UserID: 1, Role: WorkspaceRoleAdmin}, nil)
+
+	err := service.DeleteWorkspace(ctx, 1, 3)
+
+	assert.ErrorIs(t, err, ErrWorkspaceForbidden)
+	mockQuerier.AssertNotCalled(t, "DeleteWorkspace", mock.Anything, mock.Anything)
+}
+
+func TestAddMember(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+	email := "jane@example.com"
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{WorkspaceID: 3, UserID: 1, Role: WorkspaceRoleAdmin}, nil)
+	mockQuerier.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 8}, nil)
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 8)).Return(db.WorkspaceMember{}, pgx.ErrNoRows)
+	mockQuerier.On("AddWorkspaceMember", ctx, db.AddWorkspaceMemberParams{WorkspaceID: 3, UserID: 8, Role: WorkspaceRoleMember}).
+		Return(db.WorkspaceMember{WorkspaceID: 3, UserID: 8, Role: WorkspaceRoleMember}, nil)
+
+	// An empty role defaults to member
+	member, err := service.AddMember(ctx, 1, 3, " jane@example.com ", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, WorkspaceRoleMember, member.Role)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestAddMember_Errors(t *testing.T) {
+	ctx := context.Background()
+	email := "jane@example.com"
+
+	tests := []struct {
+		name        string
+		role        string
+		setup       func(m *test.MockQuerier)
+		expectedErr error
+	}{
+		{
+			name:        "unknown role",
+			role:        "superuser",
+			setup:       func(m *test.MockQuerier) {},
+			expectedErr: ErrInvalidWorkspace,
+		},
+		{
+			name: "admin cannot add an owner",
+			role: WorkspaceRoleOwner,
+			setup: func(m *test.MockQuerier) {
+				m.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{Role: WorkspaceRoleAdmin}, nil)
+			},
+			expectedErr: ErrWorkspaceForbidden,
+		},
+		{
+			name: "unknown email",
+			setup: func(m *test.MockQuerier) {
+				m.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{Role: WorkspaceRoleOwner}, nil)
+				m.On("GetUserByEmail", ctx, &email).Return(db.User{}, pgx.ErrNoRows)
+			},
+			expectedErr: ErrInvalidWorkspace,
+		},
+		{
+			name: "already a member",
+			setup: func(m *test.MockQuerier) {
+				m.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{Role: WorkspaceRoleOwner}, nil)
+				m.On("GetUserByEmail", ctx, &email).Return(db.User{ID: 8}, nil)
+				m.On("GetWorkspaceMember", ctx, memberParams(3, 8)).Return(db.WorkspaceMember{Role: WorkspaceRoleViewer}, nil)
+			},
+			expectedErr: ErrWorkspaceMemberExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQuerier := new(test.MockQuerier)
+			service := NewWorkspaceService(mockQuerier)
+			tt.setup(mockQuerier)
+
+			member, err := service.AddMember(ctx, 1, 3, email, tt.role)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, member)
+			mockQuerier.AssertNotCalled(t, "AddWorkspaceMember", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateMemberRole_LastOwner(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	// The only owner tries to demote themselves
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{WorkspaceID: 3, UserID: 1, Role: WorkspaceRoleOwner}, nil)
+	mockQuerier.On("CountWorkspaceOwners", ctx, int32(3)).Return(int64(1), nil)
+
+	_, err := service.UpdateMemberRole(ctx, 1, 3, 1, WorkspaceRoleAdmin)
+
+	assert.ErrorIs(t, err, ErrLastWorkspaceOwner)
+	mockQuerier.AssertNotCalled(t, "UpdateWorkspaceMemberRole", mock.Anything, mock.Anything)
+}
+
+func TestUpdateMemberRole_AdminCannotTouchOwner(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{Role: WorkspaceRoleAdmin}, nil)
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 2)).Return(db.WorkspaceMember{Role: WorkspaceRoleOwner}, nil)
+
+	_, err := service.UpdateMemberRole(ctx, 1, 3, 2, WorkspaceRoleViewer)
+
+	assert.ErrorIs(t, err, ErrWorkspaceForbidden)
+}
+
+func TestUpdateMemberRole(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{Role: WorkspaceRoleAdmin}, nil)
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 2)).Return(db.WorkspaceMember{Role: WorkspaceRoleViewer}, nil)
+	mockQuerier.On("UpdateWorkspaceMemberRole", ctx, db.UpdateWorkspaceMemberRoleParams{WorkspaceID: 3, UserID: 2, Role: WorkspaceRoleMember}).
+		Return(db.WorkspaceMember{WorkspaceID: 3, UserID: 2, Role: WorkspaceRoleMember}, nil)
+
+	member, err := service.UpdateMemberRole(ctx, 1, 3, 2, WorkspaceRoleMember)
+
+	require.NoError(t, err)
+	assert.Equal(t, WorkspaceRoleMember, member.Role)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestRemoveMember_Self(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	// Viewers may leave a workspace on their own
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 5)).Return(db.WorkspaceMember{WorkspaceID: 3, UserID: 5, Role: WorkspaceRoleViewer}, nil)
+	mockQuerier.On("RemoveWorkspaceMember", ctx, db.RemoveWorkspaceMemberParams{WorkspaceID: 3, UserID: 5}).Return(int64(1), nil)
+
+	err := service.RemoveMember(ctx, 5, 3, 5)
+
+	require.NoError(t, err)
+	mockQuerier.AssertExpectations(t)
+}
+
+func TestRemoveMember_OtherRequiresAdmin(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 5)).Return(db.WorkspaceMember{Role: WorkspaceRoleMember}, nil)
+
+	err := service.RemoveMember(ctx, 5, 3, 6)
+
+	assert.ErrorIs(t, err, ErrWorkspaceForbidden)
+	mockQuerier.AssertNotCalled(t, "RemoveWorkspaceMember", mock.Anything, mock.Anything)
+}
+
+func TestRemoveMember_LastOwner(t *testing.T) {
+	mockQuerier := new(test.MockQuerier)
+	service := NewWorkspaceService(mockQuerier)
+	ctx := context.Background()
+
+	mockQuerier.On("GetWorkspaceMember", ctx, memberParams(3, 1)).Return(db.WorkspaceMember{Role: WorkspaceRoleOwner}, nil)
+	mockQuerier.On("CountWorkspaceOwners", ctx, int32(3)).Return(int64(1), nil)
+
+	err := service.RemoveMember(ctx, 1, 3, 1)
+
+	assert.ErrorIs(t, err, ErrLastWorkspaceOwner)
+	mockQuerier.AssertNotCalled(t, "RemoveWorkspaceMember", mock.Anything, mock.Anything)
+}
+
+func TestWorkspaceRoleAtLeast(t *testing.T) {
+	assert.True(t, WorkspaceRoleAtLeast(WorkspaceRoleOwner, WorkspaceRoleAdmin))
+	assert.True(t, WorkspaceRoleAtLeast(WorkspaceRoleMember, WorkspaceRoleMember))
+	assert.False(t, WorkspaceRoleAtLeast(WorkspaceRoleViewer, WorkspaceRoleMember))
+	assert.False(t, WorkspaceRoleAtLeast("unknown", WorkspaceRoleViewer))
+}
@@ -36,7 +36,6 @@ func (b *TestDataBuilder) BuildItem() *db.Item {
 		ID:               1,
 		UserID:           int32Ptr(1),
 		Url:              strPtr("https://example.com/article"),
-		IsRead:           boolPtr(false),
 		TextContent:      strPtr("This is test content"),
 		Summary:          strPtr("This is a test summary"),
 		Type:             strPtr("article"),
@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/yamirghofran/briefbot/internal/db"
@@ -12,6 +13,11 @@ type MockQuerier struct {
 	mock.Mock
 }
 
+func (m *MockQuerier) CountItemsVisibleToUser(ctx context.Context, arg db.CountItemsVisibleToUserParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockQuerier) CreateItem(ctx context.Context, arg db.CreateItemParams) (db.Item, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(db.Item), args.Error(1)
@@ -22,6 +28,11 @@ func (m *MockQuerier) CreatePendingItem(ctx context.Context, arg db.CreatePendin
 	return args.Get(0).(db.Item), args.Error(1)
 }
 
+func (m *MockQuerier) CreatePendingSourceItem(ctx context.Context, arg db.CreatePendingSourceItemParams) (db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Item), args.Error(1)
+}
+
 func (m *MockQuerier) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(db.User), args.Error(1)
@@ -32,6 +43,21 @@ func (m *MockQuerier) DeleteItem(ctx context.Context, id int32) error {
 	return args.Error(0)
 }
 
+func (m *MockQuerier) DeleteItemsByUser(ctx context.Context, userID *int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetItemsOwnedByUser(ctx context.Context, userID *int32) ([]db.Item, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockQuerier) GetItemReadsByUser(ctx context.Context, userID int32) ([]db.GetItemReadsByUserRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.GetItemReadsByUserRow), args.Error(1)
+}
+
 func (m *MockQuerier) DeleteUser(ctx context.Context, id int32) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -47,6 +73,16 @@ func (m *MockQuerier) GetItem(ctx context.Context, id int32) (db.Item, error) {
 	return args.Get(0).(db.Item), args.Error(1)
 }
 
+func (m *MockQuerier) GetItemByCanonicalURL(ctx context.Context, arg db.GetItemByCanonicalURLParams) (db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Item), args.Error(1)
+}
+
+func (m *MockQuerier) GetItemForUser(ctx context.Context, arg db.GetItemForUserParams) (db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Item), args.Error(1)
+}
+
 func (m *MockQuerier) GetItemsByProcessingStatus(ctx context.Context, processingStatus *string) ([]db.Item, error) {
 	args := m.Called(ctx, processingStatus)
 	return args.Get(0).([]db.Item), args.Error(1)
@@ -57,6 +93,11 @@ func (m *MockQuerier) GetItemsByUser(ctx context.Context, userID *int32) ([]db.I
 	return args.Get(0).([]db.Item), args.Error(1)
 }
 
+func (m *MockQuerier) GetItemsByUserAndProcessingStatus(ctx context.Context, arg db.GetItemsByUserAndProcessingStatusParams) ([]db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
 func (m *MockQuerier) GetPendingItems(ctx context.Context, limit int32) ([]db.Item, error) {
 	args := m.Called(ctx, limit)
 	return args.Get(0).([]db.Item), args.Error(1)
@@ -77,21 +118,66 @@ func (m *MockQuerier) GetUserByEmail(ctx context.Context, email *string) (db.Use
 	return args.Get(0).(db.User), args.Error(1)
 }
 
+func (m *MockQuerier) GetUserByOAuthID(ctx context.Context, arg db.GetUserByOAuthIDParams) (db.User, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.User), args.Error(1)
+}
+
+func (m *MockQuerier) LinkUserOAuthIdentity(ctx context.Context, arg db.LinkUserOAuthIdentityParams) (db.User, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.User), args.Error(1)
+}
+
 func (m *MockQuerier) ListUsers(ctx context.Context) ([]db.User, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]db.User), args.Error(1)
 }
 
-func (m *MockQuerier) MarkItemAsRead(ctx context.Context, id int32) error {
+func (m *MockQuerier) MarkUserEmailVerified(ctx context.Context, id int32) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockQuerier) ToggleItemReadStatus(ctx context.Context, id int32) (db.Item, error) {
-	args := m.Called(ctx, id)
+func (m *MockQuerier) GetItemVisibleToUser(ctx context.Context, arg db.GetItemVisibleToUserParams) (db.Item, error) {
+	args := m.Called(ctx, arg)
 	return args.Get(0).(db.Item), args.Error(1)
 }
 
+func (m *MockQuerier) GetItemsByWorkspace(ctx context.Context, workspaceID *int32) ([]db.Item, error) {
+	args := m.Called(ctx, workspaceID)
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockQuerier) GetUnreadItemsByWorkspace(ctx context.Context, arg db.GetUnreadItemsByWorkspaceParams) ([]db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateItemWorkspace(ctx context.Context, arg db.UpdateItemWorkspaceParams) (db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Item), args.Error(1)
+}
+
+func (m *MockQuerier) MarkItemRead(ctx context.Context, arg db.MarkItemReadParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) MarkItemUnread(ctx context.Context, arg db.MarkItemUnreadParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) IsItemRead(ctx context.Context, arg db.IsItemReadParams) (bool, error) {
+	args := m.Called(ctx, arg)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQuerier) GetReadItemIDs(ctx context.Context, arg db.GetReadItemIDsParams) ([]int32, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]int32), args.Error(1)
+}
+
 func (m *MockQuerier) UpdateItem(ctx context.Context, arg db.UpdateItemParams) error {
 	args := m.Called(ctx, arg)
 	return args.Error(0)
@@ -102,11 +188,56 @@ func (m *MockQuerier) PatchItem(ctx context.Context, arg db.PatchItemParams) (db
 	return args.Get(0).(db.Item), args.Error(1)
 }
 
+func (m *MockQuerier) AddItemTags(ctx context.Context, arg db.AddItemTagsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) SearchItems(ctx context.Context, arg db.SearchItemsParams) ([]db.SearchItemsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.SearchItemsRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListItemsPageByCreatedAsc(ctx context.Context, arg db.ListItemsPageByCreatedAscParams) ([]db.ListItemsPageByCreatedAscRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListItemsPageByCreatedAscRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListItemsPageByCreatedDesc(ctx context.Context, arg db.ListItemsPageByCreatedDescParams) ([]db.ListItemsPageByCreatedDescRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListItemsPageByCreatedDescRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListItemsPageByTitleAsc(ctx context.Context, arg db.ListItemsPageByTitleAscParams) ([]db.ListItemsPageByTitleAscRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListItemsPageByTitleAscRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListItemsPageByTitleDesc(ctx context.Context, arg db.ListItemsPageByTitleDescParams) ([]db.ListItemsPageByTitleDescRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListItemsPageByTitleDescRow), args.Error(1)
+}
+
 func (m *MockQuerier) UpdateItemAsProcessing(ctx context.Context, id int32) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockQuerier) UpdateItemCanonicalURL(ctx context.Context, arg db.UpdateItemCanonicalURLParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) ListItemURLsAfter(ctx context.Context, arg db.ListItemURLsAfterParams) ([]db.ListItemURLsAfterRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListItemURLsAfterRow), args.Error(1)
+}
+
+func (m *MockQuerier) BackfillItemCanonicalURL(ctx context.Context, arg db.BackfillItemCanonicalURLParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockQuerier) UpdateItemProcessingStatus(ctx context.Context, arg db.UpdateItemProcessingStatusParams) error {
 	args := m.Called(ctx, arg)
 	return args.Error(0)
@@ -117,6 +248,11 @@ func (m *MockQuerier) UpdateUser(ctx context.Context, arg db.UpdateUserParams) e
 	return args.Error(0)
 }
 
+func (m *MockQuerier) UpdateUserPassword(ctx context.Context, arg db.UpdateUserPasswordParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
 func (m *MockQuerier) GetUnreadItemsFromPreviousDay(ctx context.Context) ([]db.Item, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]db.Item), args.Error(1)
@@ -158,6 +294,16 @@ func (m *MockQuerier) DeletePodcast(ctx context.Context, id int32) error {
 	return args.Error(0)
 }
 
+func (m *MockQuerier) DeletePodcastsByUser(ctx context.Context, userID *int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) DeletePodcastItemsByUser(ctx context.Context, userID *int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
 func (m *MockQuerier) GetCompletedPodcasts(ctx context.Context, limit int32) ([]db.Podcast, error) {
 	args := m.Called(ctx, limit)
 	return args.Get(0).([]db.Podcast), args.Error(1)
@@ -178,6 +324,31 @@ func (m *MockQuerier) GetPodcastByUser(ctx context.Context, userID *int32) ([]db
 	return args.Get(0).([]db.Podcast), args.Error(1)
 }
 
+func (m *MockQuerier) ListPodcastsPageByCreatedAsc(ctx context.Context, arg db.ListPodcastsPageByCreatedAscParams) ([]db.Podcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Podcast), args.Error(1)
+}
+
+func (m *MockQuerier) ListPodcastsPageByCreatedDesc(ctx context.Context, arg db.ListPodcastsPageByCreatedDescParams) ([]db.Podcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Podcast), args.Error(1)
+}
+
+func (m *MockQuerier) ListPodcastsPageByTitleAsc(ctx context.Context, arg db.ListPodcastsPageByTitleAscParams) ([]db.Podcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Podcast), args.Error(1)
+}
+
+func (m *MockQuerier) ListPodcastsPageByTitleDesc(ctx context.Context, arg db.ListPodcastsPageByTitleDescParams) ([]db.Podcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Podcast), args.Error(1)
+}
+
+func (m *MockQuerier) GetPodcastForUser(ctx context.Context, arg db.GetPodcastForUserParams) (db.Podcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Podcast), args.Error(1)
+}
+
 func (m *MockQuerier) GetPodcastItemIDs(ctx context.Context, podcastID *int32) ([]*int32, error) {
 	args := m.Called(ctx, podcastID)
 	return args.Get(0).([]*int32), args.Error(1)
@@ -262,3 +433,378 @@ func (m *MockQuerier) UpdatePodcastsStatus(ctx context.Context, arg db.UpdatePod
 	args := m.Called(ctx, arg)
 	return args.Error(0)
 }
+
+// Auth-related methods
+func (m *MockQuerier) CreateAPIToken(ctx context.Context, arg db.CreateAPITokenParams) (db.ApiToken, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ApiToken), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteAPIToken(ctx context.Context, arg db.DeleteAPITokenParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteAPITokensByUser(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetAPITokenByHash(ctx context.Context, tokenHash string) (db.ApiToken, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(db.ApiToken), args.Error(1)
+}
+
+func (m *MockQuerier) ListAPITokensByUser(ctx context.Context, userID int32) ([]db.ApiToken, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ApiToken), args.Error(1)
+}
+
+func (m *MockQuerier) TouchAPIToken(ctx context.Context, id int32) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) CreatePasswordResetToken(ctx context.Context, arg db.CreatePasswordResetTokenParams) (db.PasswordResetToken, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockQuerier) GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (db.PasswordResetToken, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(db.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockQuerier) MarkPasswordResetTokenUsed(ctx context.Context, id int32) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) DeletePasswordResetTokensByUser(ctx context.Context, userID int32) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetUnreadItemsByUserInRange(ctx context.Context, arg db.GetUnreadItemsByUserInRangeParams) ([]db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+// Preferences-related methods
+func (m *MockQuerier) GetUserPreferences(ctx context.Context, userID int32) (db.UserPreference, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(db.UserPreference), args.Error(1)
+}
+
+func (m *MockQuerier) UpsertUserPreferences(ctx context.Context, arg db.UpsertUserPreferencesParams) (db.UserPreference, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.UserPreference), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateLastDigestSent(ctx context.Context, arg db.UpdateLastDigestSentParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// Workspace-related methods
+func (m *MockQuerier) AddWorkspaceMember(ctx context.Context, arg db.AddWorkspaceMemberParams) (db.WorkspaceMember, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockQuerier) CountWorkspaceOwners(ctx context.Context, workspaceID int32) (int64, error) {
+	args := m.Called(ctx, workspaceID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CreateWorkspace(ctx context.Context, arg db.CreateWorkspaceParams) (db.Workspace, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Workspace), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteWorkspace(ctx context.Context, id int32) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetWorkspace(ctx context.Context, id int32) (db.Workspace, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Workspace), args.Error(1)
+}
+
+func (m *MockQuerier) GetWorkspaceMember(ctx context.Context, arg db.GetWorkspaceMemberParams) (db.WorkspaceMember, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockQuerier) ListWorkspaceMembers(ctx context.Context, workspaceID int32) ([]db.ListWorkspaceMembersRow, error) {
+	args := m.Called(ctx, workspaceID)
+	return args.Get(0).([]db.ListWorkspaceMembersRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListWorkspacesByUser(ctx context.Context, userID int32) ([]db.ListWorkspacesByUserRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ListWorkspacesByUserRow), args.Error(1)
+}
+
+func (m *MockQuerier) RemoveWorkspaceMember(ctx context.Context, arg db.RemoveWorkspaceMemberParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateWorkspaceMemberRole(ctx context.Context, arg db.UpdateWorkspaceMemberRoleParams) (db.WorkspaceMember, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateWorkspaceName(ctx context.Context, arg db.UpdateWorkspaceNameParams) (db.Workspace, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Workspace), args.Error(1)
+}
+
+// Rate limit-related methods
+
+func (m *MockQuerier) DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt time.Time) error {
+	args := m.Called(ctx, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetRateLimitTokens(ctx context.Context, arg db.GetRateLimitTokensParams) (float64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockQuerier) TakeRateLimitToken(ctx context.Context, arg db.TakeRateLimitTokenParams) (float64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+// Usage-related methods
+
+func (m *MockQuerier) AddPodcastUsage(ctx context.Context, arg db.AddPodcastUsageParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetUsageCounter(ctx context.Context, arg db.GetUsageCounterParams) (db.UsageCounter, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.UsageCounter), args.Error(1)
+}
+
+func (m *MockQuerier) IncrementItemUsage(ctx context.Context, arg db.IncrementItemUsageParams) (int32, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+// Feed-related methods
+
+func (m *MockQuerier) CreateFeed(ctx context.Context, arg db.CreateFeedParams) (db.Feed, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Feed), args.Error(1)
+}
+
+func (m *MockQuerier) GetFeed(ctx context.Context, id int32) (db.Feed, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Feed), args.Error(1)
+}
+
+func (m *MockQuerier) GetFeedByURL(ctx context.Context, url string) (db.Feed, error) {
+	args := m.Called(ctx, url)
+	return args.Get(0).(db.Feed), args.Error(1)
+}
+
+func (m *MockQuerier) ListDueFeeds(ctx context.Context, arg db.ListDueFeedsParams) ([]db.Feed, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Feed), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateFeedPolled(ctx context.Context, arg db.UpdateFeedPolledParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) UpdateFeedPollFailed(ctx context.Context, arg db.UpdateFeedPollFailedParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) InsertFeedEntry(ctx context.Context, arg db.InsertFeedEntryParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) CreateFeedSubscription(ctx context.Context, arg db.CreateFeedSubscriptionParams) (db.FeedSubscription, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.FeedSubscription), args.Error(1)
+}
+
+func (m *MockQuerier) GetFeedSubscription(ctx context.Context, arg db.GetFeedSubscriptionParams) (db.FeedSubscription, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.FeedSubscription), args.Error(1)
+}
+
+func (m *MockQuerier) ListFeedSubscriptionsByUser(ctx context.Context, userID int32) ([]db.ListFeedSubscriptionsByUserRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ListFeedSubscriptionsByUserRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListFeedEntryGUIDs(ctx context.Context, arg db.ListFeedEntryGUIDsParams) ([]string, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockQuerier) ListFeedSubscribers(ctx context.Context, feedID int32) ([]db.ListFeedSubscribersRow, error) {
+	args := m.Called(ctx, feedID)
+	return args.Get(0).([]db.ListFeedSubscribersRow), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateFeedSubscription(ctx context.Context, arg db.UpdateFeedSubscriptionParams) (db.FeedSubscription, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.FeedSubscription), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteFeedSubscription(ctx context.Context, arg db.DeleteFeedSubscriptionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Embedding-related methods
+
+func (m *MockQuerier) ListItemsToEmbed(ctx context.Context, arg db.ListItemsToEmbedParams) ([]db.Item, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Item), args.Error(1)
+}
+
+func (m *MockQuerier) UpsertItemEmbedding(ctx context.Context, arg db.UpsertItemEmbeddingParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) GetItemEmbedding(ctx context.Context, arg db.GetItemEmbeddingParams) (db.ItemEmbedding, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ItemEmbedding), args.Error(1)
+}
+
+func (m *MockQuerier) ListItemEmbeddingsVisibleToUser(ctx context.Context, arg db.ListItemEmbeddingsVisibleToUserParams) ([]db.ListItemEmbeddingsVisibleToUserRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListItemEmbeddingsVisibleToUserRow), args.Error(1)
+}
+
+func (m *MockQuerier) GetItemsVisibleToUserByIDs(ctx context.Context, arg db.GetItemsVisibleToUserByIDsParams) ([]db.GetItemsVisibleToUserByIDsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.GetItemsVisibleToUserByIDsRow), args.Error(1)
+}
+
+// Tag-related methods
+
+func (m *MockQuerier) UpsertTag(ctx context.Context, arg db.UpsertTagParams) (db.Tag, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Tag), args.Error(1)
+}
+
+func (m *MockQuerier) GetTagByName(ctx context.Context, arg db.GetTagByNameParams) (db.Tag, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Tag), args.Error(1)
+}
+
+func (m *MockQuerier) ListTagsByUser(ctx context.Context, userID int32) ([]db.Tag, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.Tag), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteTagsByName(ctx context.Context, arg db.DeleteTagsByNameParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) ListTagCounts(ctx context.Context, userID *int32) ([]db.ListTagCountsRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ListTagCountsRow), args.Error(1)
+}
+
+func (m *MockQuerier) UserHasTag(ctx context.Context, arg db.UserHasTagParams) (bool, error) {
+	args := m.Called(ctx, arg)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQuerier) UpsertTagAlias(ctx context.Context, arg db.UpsertTagAliasParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) RetargetTagAliases(ctx context.Context, arg db.RetargetTagAliasesParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) DeleteTagAlias(ctx context.Context, arg db.DeleteTagAliasParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) ListTagAliasesByUser(ctx context.Context, userID int32) ([]db.ListTagAliasesByUserRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ListTagAliasesByUserRow), args.Error(1)
+}
+
+func (m *MockQuerier) ResolveTagAliases(ctx context.Context, arg db.ResolveTagAliasesParams) ([]db.ResolveTagAliasesRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ResolveTagAliasesRow), args.Error(1)
+}
+
+func (m *MockQuerier) ReplaceItemTags(ctx context.Context, arg db.ReplaceItemTagsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Collection-related methods
+
+func (m *MockQuerier) CreateCollection(ctx context.Context, arg db.CreateCollectionParams) (db.Collection, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Collection), args.Error(1)
+}
+
+func (m *MockQuerier) GetCollectionByUser(ctx context.Context, arg db.GetCollectionByUserParams) (db.Collection, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Collection), args.Error(1)
+}
+
+func (m *MockQuerier) ListCollectionsByUser(ctx context.Context, userID int32) ([]db.ListCollectionsByUserRow, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.ListCollectionsByUserRow), args.Error(1)
+}
+
+func (m *MockQuerier) ListCollectionsForItem(ctx context.Context, arg db.ListCollectionsForItemParams) ([]db.Collection, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.Collection), args.Error(1)
+}
+
+func (m *MockQuerier) UpdateCollection(ctx context.Context, arg db.UpdateCollectionParams) (db.Collection, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Collection), args.Error(1)
+}
+
+func (m *MockQuerier) DeleteCollection(ctx context.Context, arg db.DeleteCollectionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) AddItemToCollection(ctx context.Context, arg db.AddItemToCollectionParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockQuerier) RemoveItemFromCollection(ctx context.Context, arg db.RemoveItemFromCollectionParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQuerier) ListCollectionItems(ctx context.Context, arg db.ListCollectionItemsParams) ([]db.ListCollectionItemsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]db.ListCollectionItemsRow), args.Error(1)
+}
+
+func (m *MockQuerier) SetCollectionItemPositions(ctx context.Context, arg db.SetCollectionItemPositionsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Personal API tokens for long-lived programmatic access (browser extension, scripts)
+CREATE TABLE IF NOT EXISTS api_tokens (
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+name TEXT NOT NULL,
+token_hash TEXT NOT NULL UNIQUE,
+token_prefix TEXT NOT NULL,
+last_used_at TIMESTAMPTZ,
+expires_at TIMESTAMPTZ,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+-- Email is the login identifier, so it must resolve to a single user
+CREATE UNIQUE INDEX idx_users_email_unique ON users(email) WHERE email IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_email_unique;
+DROP INDEX IF EXISTS idx_api_tokens_user_id;
+DROP TABLE IF EXISTS api_tokens;
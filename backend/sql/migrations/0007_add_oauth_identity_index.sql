@@ -0,0 +1,6 @@
+-- +goose Up
+-- An identity provider subject maps to exactly one user
+CREATE UNIQUE INDEX idx_users_oauth_identity ON users(auth_provider, oauth_id) WHERE oauth_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_oauth_identity;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Single-use tokens emailed to users who forgot their password
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+token_hash TEXT NOT NULL UNIQUE,
+expires_at TIMESTAMPTZ NOT NULL,
+used_at TIMESTAMPTZ,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_password_reset_tokens_user_id;
+DROP TABLE IF EXISTS password_reset_tokens;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Per-user settings for digests, podcasts and AI summaries
+CREATE TABLE IF NOT EXISTS user_preferences (
+user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+timezone TEXT NOT NULL DEFAULT 'UTC',
+digest_time TEXT NOT NULL DEFAULT '08:00',
+digest_frequency TEXT NOT NULL DEFAULT 'daily' CHECK (digest_frequency IN ('daily', 'weekdays', 'weekly', 'off')),
+podcast_enabled BOOLEAN,
+host_voice TEXT NOT NULL DEFAULT 'af_heart',
+cohost_voice TEXT NOT NULL DEFAULT 'am_adam',
+speech_speed DOUBLE PRECISION NOT NULL DEFAULT 1.0,
+summary_length TEXT NOT NULL DEFAULT 'medium' CHECK (summary_length IN ('short', 'medium', 'long')),
+summary_language TEXT NOT NULL DEFAULT 'en',
+last_digest_sent_at TIMESTAMPTZ,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,58 @@
+-- +goose Up
+-- Workspaces let a group of users share one reading list
+CREATE TABLE IF NOT EXISTS workspaces (
+id SERIAL PRIMARY KEY,
+name TEXT NOT NULL,
+created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member', 'viewer')),
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user_id ON workspace_members(user_id);
+
+-- Items stay owned by the user who saved them; a workspace makes them visible to its members
+ALTER TABLE items ADD COLUMN workspace_id INTEGER REFERENCES workspaces(id) ON DELETE SET NULL;
+CREATE INDEX idx_items_workspace_id ON items(workspace_id);
+
+-- Read state is tracked per user so members of a workspace read independently
+CREATE TABLE IF NOT EXISTS item_reads (
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+read_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (user_id, item_id)
+);
+
+CREATE INDEX idx_item_reads_item_id ON item_reads(item_id);
+
+INSERT INTO item_reads (user_id, item_id, read_at)
+SELECT user_id, id, COALESCE(modified_at, CURRENT_TIMESTAMP) FROM items
+WHERE is_read = TRUE AND user_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_items_user_id_is_read;
+DROP INDEX IF EXISTS idx_items_is_read;
+ALTER TABLE items DROP COLUMN is_read;
+
+-- +goose Down
+ALTER TABLE items ADD COLUMN is_read BOOLEAN DEFAULT FALSE;
+UPDATE items SET is_read = TRUE
+WHERE EXISTS (SELECT 1 FROM item_reads WHERE item_reads.item_id = items.id AND item_reads.user_id = items.user_id);
+CREATE INDEX idx_items_is_read ON items(is_read);
+CREATE INDEX idx_items_user_id_is_read ON items(user_id, is_read);
+
+DROP INDEX IF EXISTS idx_item_reads_item_id;
+DROP TABLE IF EXISTS item_reads;
+
+DROP INDEX IF EXISTS idx_items_workspace_id;
+ALTER TABLE items DROP COLUMN IF EXISTS workspace_id;
+
+DROP INDEX IF EXISTS idx_workspace_members_user_id;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Deleting a user removes the items they own instead of failing on the foreign key
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_user_id_fkey;
+ALTER TABLE items ADD CONSTRAINT items_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_user_id_fkey;
+ALTER TABLE items ADD CONSTRAINT items_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Token buckets shared between API instances when RATE_LIMIT_STORE=postgres
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+bucket_key TEXT PRIMARY KEY,
+tokens DOUBLE PRECISION NOT NULL,
+updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Per-user daily usage counted against the item and podcast quotas
+CREATE TABLE IF NOT EXISTS usage_counters (
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+day DATE NOT NULL,
+items INTEGER NOT NULL DEFAULT 0,
+podcast_seconds INTEGER NOT NULL DEFAULT 0,
+PRIMARY KEY (user_id, day)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS usage_counters;
+DROP TABLE IF EXISTS rate_limit_buckets;
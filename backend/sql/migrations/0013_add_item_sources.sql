@@ -0,0 +1,10 @@
+-- +goose Up
+-- Items can be created from pasted text, HTML or Markdown and from uploaded
+-- files as well as URLs. The submitted source is kept so failed items can be
+-- reprocessed on retry.
+ALTER TABLE items ADD COLUMN source_type TEXT NOT NULL DEFAULT 'url' CHECK (source_type IN ('url', 'text', 'html', 'markdown'));
+ALTER TABLE items ADD COLUMN source_content TEXT;
+
+-- +goose Down
+ALTER TABLE items DROP COLUMN IF EXISTS source_content;
+ALTER TABLE items DROP COLUMN IF EXISTS source_type;
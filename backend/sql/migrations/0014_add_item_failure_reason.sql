@@ -0,0 +1,11 @@
+-- +goose Up
+-- Failed items record why they failed, so clients can tell a paywalled or
+-- blocked page from a network or AI failure. processing_error keeps the
+-- full message.
+ALTER TABLE items ADD COLUMN failure_reason TEXT CHECK (failure_reason IN (
+    'paywall', 'login_wall', 'captcha', 'forbidden', 'robots', 'not_found', 'client_error',
+    'server_error', 'empty_content', 'too_large', 'network', 'timeout', 'parse', 'ai', 'unknown'
+));
+
+-- +goose Down
+ALTER TABLE items DROP COLUMN IF EXISTS failure_reason;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Items store their URL in canonical form, without tracking parameters and
+-- after redirects and <link rel=canonical>, so a page a user already saved is
+-- returned instead of being processed again. Existing items keep their URL as
+-- is; only the oldest item per user and URL gets one so the index can be built.
+-- cmd/backfill-canonical-urls then canonicalizes them with the Go code.
+ALTER TABLE items ADD COLUMN canonical_url TEXT;
+
+UPDATE items SET canonical_url = url
+WHERE id IN (
+    SELECT DISTINCT ON (user_id, url) id
+    FROM items
+    WHERE user_id IS NOT NULL AND url IS NOT NULL
+    ORDER BY user_id, url, id
+);
+
+CREATE UNIQUE INDEX idx_items_user_canonical_url ON items(user_id, canonical_url) WHERE canonical_url IS NOT NULL;
+
+-- Items that turn out to be the same page as an existing one after redirects
+-- fail with their own reason
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_failure_reason_check;
+ALTER TABLE items ADD CONSTRAINT items_failure_reason_check CHECK (failure_reason IN (
+    'paywall', 'login_wall', 'captcha', 'forbidden', 'robots', 'not_found', 'client_error',
+    'server_error', 'empty_content', 'too_large', 'network', 'timeout', 'parse', 'ai', 'unknown',
+    'duplicate'
+));
+
+-- +goose Down
+UPDATE items SET failure_reason = 'unknown' WHERE failure_reason = 'duplicate';
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_failure_reason_check;
+ALTER TABLE items ADD CONSTRAINT items_failure_reason_check CHECK (failure_reason IN (
+    'paywall', 'login_wall', 'captcha', 'forbidden', 'robots', 'not_found', 'client_error',
+    'server_error', 'empty_content', 'too_large', 'network', 'timeout', 'parse', 'ai', 'unknown'
+));
+DROP INDEX IF EXISTS idx_items_user_canonical_url;
+ALTER TABLE items DROP COLUMN IF EXISTS canonical_url;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- Feeds are RSS, Atom or JSON Feed documents polled on behalf of their
+-- subscribers. A feed is stored once however many users follow it.
+CREATE TABLE IF NOT EXISTS feeds (
+id SERIAL PRIMARY KEY,
+url TEXT NOT NULL UNIQUE,
+title TEXT,
+site_url TEXT,
+etag TEXT,
+last_modified TEXT,
+last_polled_at TIMESTAMPTZ,
+next_poll_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+consecutive_failures INTEGER NOT NULL DEFAULT 0,
+last_error TEXT,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_feeds_next_poll_at ON feeds(next_poll_at);
+
+CREATE TABLE IF NOT EXISTS feed_subscriptions (
+feed_id INTEGER NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+title TEXT,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (feed_id, user_id)
+);
+
+CREATE INDEX idx_feed_subscriptions_user_id ON feed_subscriptions(user_id);
+
+-- Entries already seen, keyed by their GUID, so each is ingested only once
+CREATE TABLE IF NOT EXISTS feed_entries (
+feed_id INTEGER NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+guid TEXT NOT NULL,
+url TEXT,
+title TEXT,
+published_at TIMESTAMPTZ,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (feed_id, guid)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS feed_entries;
+DROP INDEX IF EXISTS idx_feed_subscriptions_user_id;
+DROP TABLE IF EXISTS feed_subscriptions;
+DROP INDEX IF EXISTS idx_feeds_next_poll_at;
+DROP TABLE IF EXISTS feeds;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Tags given to a subscription, such as the folder it was in when imported
+-- from OPML, are added to every item saved from the feed
+ALTER TABLE feed_subscriptions ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE feed_subscriptions DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Full-text search over items. array_to_string is only stable, so the vector
+-- is built by an immutable function that a generated column can use. Titles
+-- rank highest, then tags and summaries, then authors, then the text itself,
+-- of which only the start is indexed to stay under the tsvector size limit.
+-- +goose StatementBegin
+CREATE FUNCTION items_search_vector(title TEXT, summary TEXT, text_content TEXT, tags TEXT[], authors TEXT[])
+RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(title, '')), 'A')
+        || setweight(to_tsvector('english', COALESCE(array_to_string(tags, ' '), '')), 'B')
+        || setweight(to_tsvector('english', COALESCE(summary, '')), 'B')
+        || setweight(to_tsvector('english', COALESCE(array_to_string(authors, ' '), '')), 'C')
+        || setweight(to_tsvector('english', LEFT(COALESCE(text_content, ''), 200000)), 'D')
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE items ADD COLUMN search_vector tsvector NOT NULL
+    GENERATED ALWAYS AS (items_search_vector(title, summary, text_content, tags, authors)) STORED;
+
+CREATE INDEX idx_items_search_vector ON items USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_items_search_vector;
+ALTER TABLE items DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS items_search_vector(TEXT, TEXT, TEXT, TEXT[], TEXT[]);
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Embedding vectors of completed items for semantic search and related items.
+-- Vectors are stored unit-length as plain arrays and compared in the
+-- application, so no database extension is needed. An item whose text the
+-- embeddings API rejected keeps a row with the error and no vector, and is
+-- tried again once the item changes.
+CREATE TABLE IF NOT EXISTS item_embeddings (
+item_id INTEGER PRIMARY KEY REFERENCES items(id) ON DELETE CASCADE,
+model TEXT NOT NULL,
+embedding REAL[],
+error TEXT,
+updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_item_embeddings_model ON item_embeddings(model);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_item_embeddings_model;
+DROP TABLE IF EXISTS item_embeddings;
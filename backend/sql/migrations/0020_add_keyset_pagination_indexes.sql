@@ -0,0 +1,10 @@
+-- +goose Up
+-- Keyset pagination walks these in either direction, newest first by default
+CREATE INDEX idx_items_user_id_created_at ON items(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_items_workspace_id_created_at ON items(workspace_id, created_at DESC, id DESC);
+CREATE INDEX idx_podcasts_user_id_created_at ON podcasts(user_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_podcasts_user_id_created_at;
+DROP INDEX IF EXISTS idx_items_workspace_id_created_at;
+DROP INDEX IF EXISTS idx_items_user_id_created_at;
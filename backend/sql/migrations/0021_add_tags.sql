@@ -0,0 +1,28 @@
+-- +goose Up
+-- Tags a user has curated. Names are normalized (lowercase words joined by
+-- hyphens); items keep their tags in items.tags, so a tag only needs a row
+-- once it has aliases.
+CREATE TABLE IF NOT EXISTS tags (
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+name TEXT NOT NULL,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+UNIQUE (user_id, name)
+);
+
+-- Other spellings of a tag. Extracted tags matching an alias are replaced by
+-- the tag it points to.
+CREATE TABLE IF NOT EXISTS tag_aliases (
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+alias TEXT NOT NULL,
+tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (user_id, alias)
+);
+
+CREATE INDEX idx_tag_aliases_tag_id ON tag_aliases(tag_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tag_aliases_tag_id;
+DROP TABLE IF EXISTS tag_aliases;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Collections a user curates to organize items, such as "Q3 research"
+CREATE TABLE IF NOT EXISTS collections (
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+name TEXT NOT NULL,
+description TEXT,
+created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+UNIQUE (user_id, name)
+);
+
+-- Items in a collection, in the order the user arranged them. An item can be
+-- in any number of collections.
+CREATE TABLE IF NOT EXISTS collection_items (
+collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+position INTEGER NOT NULL,
+added_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (collection_id, item_id)
+);
+
+CREATE INDEX idx_collection_items_item_id ON collection_items(item_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_collection_items_item_id;
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
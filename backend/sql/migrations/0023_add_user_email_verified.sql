@@ -0,0 +1,8 @@
+-- +goose Up
+-- Whether the user has proven control of their email address, either through
+-- an identity provider that vouches for it or by completing a password reset.
+-- Only verified accounts are linked to an identity provider by email.
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
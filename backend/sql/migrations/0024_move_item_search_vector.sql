@@ -0,0 +1,42 @@
+-- +goose Up
+-- The search vector moves out of items into its own table, so loading items
+-- no longer reads it back with every row. A generated column cannot write to
+-- another table, so a trigger keeps it up to date instead.
+CREATE TABLE IF NOT EXISTS item_search (
+item_id INTEGER PRIMARY KEY REFERENCES items(id) ON DELETE CASCADE,
+search_vector tsvector NOT NULL
+);
+
+INSERT INTO item_search (item_id, search_vector)
+SELECT id, search_vector FROM items;
+
+-- +goose StatementBegin
+CREATE FUNCTION item_search_refresh()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    INSERT INTO item_search (item_id, search_vector)
+    VALUES (NEW.id, items_search_vector(NEW.title, NEW.summary, NEW.text_content, NEW.tags, NEW.authors))
+    ON CONFLICT (item_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+    RETURN NULL;
+END;
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER items_search_refresh
+AFTER INSERT OR UPDATE OF title, summary, text_content, tags, authors ON items
+FOR EACH ROW EXECUTE FUNCTION item_search_refresh();
+
+DROP INDEX IF EXISTS idx_items_search_vector;
+ALTER TABLE items DROP COLUMN IF EXISTS search_vector;
+
+CREATE INDEX idx_item_search_vector ON item_search USING GIN (search_vector);
+
+-- +goose Down
+ALTER TABLE items ADD COLUMN search_vector tsvector NOT NULL
+    GENERATED ALWAYS AS (items_search_vector(title, summary, text_content, tags, authors)) STORED;
+CREATE INDEX idx_items_search_vector ON items USING GIN (search_vector);
+DROP TRIGGER IF EXISTS items_search_refresh ON items;
+DROP FUNCTION IF EXISTS item_search_refresh();
+DROP TABLE IF EXISTS item_search;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Items saved before tags were normalized carry tags as they were extracted.
+-- Rewrite them as resolveTags does for new items: lowercase words joined by
+-- hyphens, the owner's aliases replaced by their tag, and blank, overlong and
+-- repeated tags dropped.
+WITH spelled AS (
+    SELECT items.id, items.user_id, t.ord,
+        trim(BOTH '-' FROM regexp_replace(lower(t.tag), '[[:space:]_#-]+', '-', 'g')) AS name
+    FROM items
+    CROSS JOIN LATERAL unnest(items.tags) WITH ORDINALITY AS t(tag, ord)
+),
+resolved AS (
+    SELECT spelled.id, COALESCE(tags.name, spelled.name) AS name, min(spelled.ord) AS ord
+    FROM spelled
+    LEFT JOIN tag_aliases ON tag_aliases.user_id = spelled.user_id AND tag_aliases.alias = spelled.name
+    LEFT JOIN tags ON tags.id = tag_aliases.tag_id
+    WHERE spelled.name <> '' AND char_length(spelled.name) <= 64
+    GROUP BY spelled.id, COALESCE(tags.name, spelled.name)
+),
+normalized AS (
+    SELECT items.id,
+        COALESCE(array_agg(resolved.name ORDER BY resolved.ord) FILTER (WHERE resolved.name IS NOT NULL), '{}') AS tags
+    FROM items
+    LEFT JOIN resolved ON resolved.id = items.id
+    WHERE items.tags IS NOT NULL
+    GROUP BY items.id
+)
+UPDATE items SET tags = normalized.tags
+FROM normalized
+WHERE items.id = normalized.id AND items.tags IS DISTINCT FROM normalized.tags;
+
+-- +goose Down
+-- The original spellings are not kept, so there is nothing to restore
+SELECT 1;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Session tokens carry the version current when they were issued. Changing or
+-- resetting the password bumps it, which signs out every earlier session.
+ALTER TABLE users ADD COLUMN session_version INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS session_version;
@@ -0,0 +1,60 @@
+-- name: CreateCollection :one
+INSERT INTO collections (user_id, name, description)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetCollectionByUser :one
+SELECT * FROM collections WHERE id = $1 AND user_id = $2;
+
+-- name: ListCollectionsByUser :many
+-- The user's collections by name, with the number of items in each
+SELECT sqlc.embed(collections),
+  (SELECT count(*) FROM collection_items ci WHERE ci.collection_id = collections.id)::int AS item_count
+FROM collections
+WHERE collections.user_id = $1
+ORDER BY collections.name ASC;
+
+-- name: ListCollectionsForItem :many
+SELECT collections.* FROM collections
+JOIN collection_items ON collection_items.collection_id = collections.id
+WHERE collections.user_id = $1 AND collection_items.item_id = $2
+ORDER BY collections.name ASC;
+
+-- name: UpdateCollection :one
+UPDATE collections
+SET name = $3, description = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteCollection :execrows
+DELETE FROM collections WHERE id = $1 AND user_id = $2;
+
+-- name: AddItemToCollection :exec
+-- Appends the item to the collection; an item already in it keeps its place
+INSERT INTO collection_items (collection_id, item_id, position)
+SELECT sqlc.arg('collection_id')::int, sqlc.arg('item_id')::int, COALESCE(MAX(position) + 1, 1)
+FROM collection_items WHERE collection_id = sqlc.arg('collection_id')
+ON CONFLICT (collection_id, item_id) DO NOTHING;
+
+-- name: RemoveItemFromCollection :execrows
+DELETE FROM collection_items WHERE collection_id = $1 AND item_id = $2;
+
+-- name: ListCollectionItems :many
+-- The items of a collection in order, with whether the user has read each.
+-- Items the user can no longer see, such as those of a workspace they left,
+-- are left out.
+SELECT sqlc.embed(items),
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) AS is_read
+FROM collection_items
+JOIN items ON items.id = collection_items.item_id
+WHERE collection_items.collection_id = sqlc.arg('collection_id')
+  AND (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')))
+ORDER BY collection_items.position ASC, collection_items.added_at ASC;
+
+-- name: SetCollectionItemPositions :exec
+-- Numbers the given items of a collection from 1 in the order given
+UPDATE collection_items
+SET position = ordered.position
+FROM unnest(sqlc.arg('item_ids')::int[]) WITH ORDINALITY AS ordered(item_id, position)
+WHERE collection_items.collection_id = sqlc.arg('collection_id')
+  AND collection_items.item_id = ordered.item_id;
@@ -0,0 +1,37 @@
+-- name: ListItemsToEmbed :many
+-- Completed items with no embedding from the model, or one older than the item
+SELECT items.* FROM items
+LEFT JOIN item_embeddings e ON e.item_id = items.id
+WHERE items.processing_status = 'completed'
+  AND (e.item_id IS NULL OR e.model <> sqlc.arg('model') OR e.updated_at < items.modified_at)
+ORDER BY items.id
+LIMIT sqlc.arg('limit');
+
+-- name: UpsertItemEmbedding :exec
+INSERT INTO item_embeddings (item_id, model, embedding, error)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (item_id) DO UPDATE
+SET model = EXCLUDED.model, embedding = EXCLUDED.embedding, error = EXCLUDED.error, updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetItemEmbedding :one
+SELECT * FROM item_embeddings WHERE item_id = $1 AND model = $2;
+
+-- name: ListItemEmbeddingsVisibleToUser :many
+-- One page of the embeddings of completed items the user can see, newest
+-- item first, starting below before_id when it is set
+SELECT e.item_id, e.embedding FROM item_embeddings e
+JOIN items ON items.id = e.item_id
+WHERE e.model = sqlc.arg('model')
+  AND e.embedding IS NOT NULL
+  AND items.processing_status = 'completed'
+  AND (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')))
+  AND (sqlc.narg('before_id')::int IS NULL OR e.item_id < sqlc.narg('before_id'))
+ORDER BY e.item_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetItemsVisibleToUserByIDs :many
+SELECT sqlc.embed(items),
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) AS is_read
+FROM items
+WHERE items.id = ANY(sqlc.arg('item_ids')::int[])
+  AND (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')));
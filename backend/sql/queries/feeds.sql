@@ -0,0 +1,77 @@
+-- name: CreateFeed :one
+INSERT INTO feeds (url, title, site_url, etag, last_modified, last_polled_at, next_poll_at)
+VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, $6)
+ON CONFLICT (url) DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetFeed :one
+SELECT * FROM feeds WHERE id = $1;
+
+-- name: GetFeedByURL :one
+SELECT * FROM feeds WHERE url = $1;
+
+-- name: ListDueFeeds :many
+SELECT * FROM feeds
+WHERE next_poll_at <= $1
+  AND EXISTS (SELECT 1 FROM feed_subscriptions WHERE feed_subscriptions.feed_id = feeds.id)
+ORDER BY next_poll_at ASC
+LIMIT $2;
+
+-- name: UpdateFeedPolled :exec
+UPDATE feeds
+SET title = COALESCE($2, title),
+    site_url = COALESCE($3, site_url),
+    etag = $4,
+    last_modified = $5,
+    last_polled_at = CURRENT_TIMESTAMP,
+    next_poll_at = $6,
+    consecutive_failures = 0,
+    last_error = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: UpdateFeedPollFailed :exec
+UPDATE feeds
+SET last_polled_at = CURRENT_TIMESTAMP,
+    next_poll_at = $2,
+    consecutive_failures = consecutive_failures + 1,
+    last_error = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: InsertFeedEntry :execrows
+INSERT INTO feed_entries (feed_id, guid, url, title, published_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (feed_id, guid) DO NOTHING;
+
+-- name: ListFeedEntryGUIDs :many
+SELECT guid FROM feed_entries WHERE feed_id = $1 AND guid = ANY($2::text[]);
+
+-- name: CreateFeedSubscription :one
+INSERT INTO feed_subscriptions (feed_id, user_id, title, tags)
+VALUES ($1, $2, $3, COALESCE(sqlc.narg('tags')::text[], '{}'))
+RETURNING *;
+
+-- name: GetFeedSubscription :one
+SELECT * FROM feed_subscriptions WHERE feed_id = $1 AND user_id = $2;
+
+-- name: ListFeedSubscriptionsByUser :many
+SELECT feeds.*, feed_subscriptions.title AS custom_title, feed_subscriptions.tags, feed_subscriptions.created_at AS subscribed_at
+FROM feeds
+JOIN feed_subscriptions ON feeds.id = feed_subscriptions.feed_id
+WHERE feed_subscriptions.user_id = $1
+ORDER BY LOWER(COALESCE(feed_subscriptions.title, feeds.title, feeds.url)) ASC;
+
+-- name: ListFeedSubscribers :many
+SELECT user_id, tags FROM feed_subscriptions WHERE feed_id = $1 ORDER BY user_id ASC;
+
+-- name: UpdateFeedSubscription :one
+UPDATE feed_subscriptions
+SET
+  title = CASE WHEN sqlc.narg('title')::text IS NULL THEN title ELSE NULLIF(sqlc.narg('title'), '') END,
+  tags = CASE WHEN sqlc.narg('tags')::text[] IS NULL THEN tags ELSE sqlc.narg('tags') END
+WHERE feed_id = sqlc.arg('feed_id') AND user_id = sqlc.arg('user_id')
+RETURNING *;
+
+-- name: DeleteFeedSubscription :execrows
+DELETE FROM feed_subscriptions WHERE feed_id = $1 AND user_id = $2;
@@ -1,33 +1,104 @@
 -- name: GetItem :one
 SELECT * FROM items WHERE id = $1;
 
+-- name: GetItemForUser :one
+SELECT * FROM items WHERE id = $1 AND user_id = $2;
+
+-- name: GetItemVisibleToUser :one
+SELECT * FROM items
+WHERE id = $1
+  AND (items.user_id = $2 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $2));
+
+-- name: CountItemsVisibleToUser :one
+SELECT COUNT(*) FROM items
+WHERE id = ANY(sqlc.arg('item_ids')::int[])
+  AND (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')));
+
 -- name: GetItemsByUser :many
-SELECT * FROM items WHERE user_id = $1 ORDER BY created_at DESC;
+SELECT * FROM items
+WHERE items.user_id = $1
+   OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1)
+ORDER BY created_at DESC;
 
 -- name: GetUnreadItemsByUser :many
-SELECT * FROM items WHERE user_id = $1 AND is_read = FALSE ORDER BY created_at DESC;
+SELECT * FROM items
+WHERE (items.user_id = $1 OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = $1))
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $1)
+ORDER BY created_at DESC;
+
+-- name: GetItemsByWorkspace :many
+SELECT * FROM items WHERE workspace_id = $1 ORDER BY created_at DESC;
+
+-- name: GetUnreadItemsByWorkspace :many
+SELECT * FROM items
+WHERE workspace_id = $1
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = $2)
+ORDER BY created_at DESC;
 
 -- name: CreateItem :one
-INSERT INTO items (user_id, title, url, text_content, summary, type, tags, platform, authors, processing_status, processing_error) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
+INSERT INTO items (user_id, title, url, text_content, summary, type, tags, platform, authors, processing_status, processing_error, workspace_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
 
 -- name: CreatePendingItem :one
-INSERT INTO items (user_id, title, url, processing_status) VALUES ($1, $2, $3, 'pending') RETURNING *;
+INSERT INTO items (user_id, title, url, canonical_url, workspace_id, processing_status) VALUES ($1, $2, $3, $4, $5, 'pending') RETURNING *;
+
+-- name: GetItemByCanonicalURL :one
+SELECT * FROM items WHERE user_id = $1 AND canonical_url = $2;
+
+-- name: UpdateItemCanonicalURL :exec
+UPDATE items SET canonical_url = $2, modified_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: ListItemURLsAfter :many
+SELECT id, user_id, url FROM items
+WHERE id > $1 AND user_id IS NOT NULL AND url IS NOT NULL
+ORDER BY id
+LIMIT $2;
+
+-- name: BackfillItemCanonicalURL :execrows
+-- Sets the canonical URL unless another item of the same user already has it
+UPDATE items SET canonical_url = $2
+WHERE id = $1
+  AND canonical_url IS DISTINCT FROM $2
+  AND NOT EXISTS (
+    SELECT 1 FROM items other
+    WHERE other.user_id = items.user_id AND other.canonical_url = $2 AND other.id <> items.id
+  );
+
+-- name: CreatePendingSourceItem :one
+INSERT INTO items (user_id, title, source_type, source_content, workspace_id, processing_status) VALUES ($1, $2, $3, $4, $5, 'pending') RETURNING *;
 
 -- name: UpdateItem :exec
-UPDATE items SET title = $2, url = $3, is_read = $4, text_content = $5, summary = $6, type = $7, tags = $8, platform = $9, authors = $10, modified_at = CURRENT_TIMESTAMP WHERE id = $1;
+UPDATE items SET title = $2, url = $3, text_content = $4, summary = $5, type = $6, tags = $7, platform = $8, authors = $9, modified_at = CURRENT_TIMESTAMP WHERE id = $1;
 
 -- name: UpdateItemProcessingStatus :exec
-UPDATE items SET processing_status = $2, processing_error = $3, modified_at = CURRENT_TIMESTAMP WHERE id = $1;
+UPDATE items SET processing_status = $2, processing_error = $3, failure_reason = $4, modified_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: UpdateItemWorkspace :one
+UPDATE items SET workspace_id = $2, modified_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING *;
+
+-- name: MarkItemRead :exec
+INSERT INTO item_reads (user_id, item_id) VALUES ($1, $2) ON CONFLICT (user_id, item_id) DO NOTHING;
+
+-- name: MarkItemUnread :exec
+DELETE FROM item_reads WHERE user_id = $1 AND item_id = $2;
 
--- name: MarkItemAsRead :exec
-UPDATE items SET is_read = TRUE, modified_at = CURRENT_TIMESTAMP WHERE id = $1;
+-- name: IsItemRead :one
+SELECT EXISTS (SELECT 1 FROM item_reads WHERE user_id = $1 AND item_id = $2);
 
--- name: ToggleItemReadStatus :one
-UPDATE items SET is_read = NOT is_read, modified_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING *;
+-- name: GetReadItemIDs :many
+SELECT item_id FROM item_reads WHERE user_id = sqlc.arg('user_id') AND item_id = ANY(sqlc.arg('item_ids')::int[]);
 
 -- name: DeleteItem :exec
 DELETE FROM items WHERE id = $1;
 
+-- name: DeleteItemsByUser :exec
+DELETE FROM items WHERE user_id = $1;
+
+-- name: GetItemsOwnedByUser :many
+SELECT * FROM items WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: GetItemReadsByUser :many
+SELECT item_id, read_at FROM item_reads WHERE user_id = $1;
+
 -- name: GetPendingItems :many
 SELECT * FROM items WHERE processing_status = 'pending' ORDER BY created_at ASC LIMIT $1;
 
@@ -37,6 +108,9 @@ UPDATE items SET processing_status = 'processing', modified_at = CURRENT_TIMESTA
 -- name: GetItemsByProcessingStatus :many
 SELECT * FROM items WHERE processing_status = $1 ORDER BY created_at DESC;
 
+-- name: GetItemsByUserAndProcessingStatus :many
+SELECT * FROM items WHERE user_id = $1 AND processing_status = $2 ORDER BY created_at DESC;
+
 -- name: GetFailedItemsForRetry :many
 SELECT * FROM items WHERE processing_status = 'failed' AND created_at > NOW() - INTERVAL '24 hours' ORDER BY created_at ASC LIMIT $1;
 
@@ -44,7 +118,7 @@ SELECT * FROM items WHERE processing_status = 'failed' AND created_at > NOW() -
 SELECT * FROM items 
 WHERE created_at >= DATE_TRUNC('day', NOW() - INTERVAL '1 day') 
   AND created_at < DATE_TRUNC('day', NOW())
-  AND is_read = FALSE
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = items.user_id)
   AND processing_status = 'completed'
 ORDER BY created_at DESC;
 
@@ -53,7 +127,16 @@ SELECT * FROM items
 WHERE user_id = $1
   AND created_at >= DATE_TRUNC('day', NOW() - INTERVAL '1 day')
   AND created_at < DATE_TRUNC('day', NOW())
-  AND is_read = FALSE
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = items.user_id)
+  AND processing_status = 'completed'
+ORDER BY created_at DESC;
+
+-- name: GetUnreadItemsByUserInRange :many
+SELECT * FROM items
+WHERE user_id = $1
+  AND created_at >= sqlc.arg(window_start)
+  AND created_at < sqlc.arg(window_end)
+  AND NOT EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = items.user_id)
   AND processing_status = 'completed'
 ORDER BY created_at DESC;
 
@@ -68,4 +151,131 @@ SET
 WHERE id = sqlc.arg('id')
 RETURNING *;
 
+-- name: AddItemTags :exec
+-- Appends the tags the item does not have yet, ignoring case
+UPDATE items
+SET tags = COALESCE(tags, '{}') || ARRAY(
+  SELECT t FROM unnest(sqlc.arg('tags')::text[]) WITH ORDINALITY AS added(t, n)
+  WHERE lower(t) NOT IN (SELECT lower(x) FROM unnest(COALESCE(tags, '{}')) AS x)
+  ORDER BY n
+)
+WHERE id = sqlc.arg('id');
+
+
+
+-- name: SearchItems :many
+SELECT sqlc.embed(items),
+  ts_rank_cd(item_search.search_vector, q.query, 32)::real AS rank,
+  ts_headline('english', items.title, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+  ts_headline('english', COALESCE(items.summary, '') || ' ' || LEFT(COALESCE(items.text_content, ''), 20000), q.query,
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "') AS snippet,
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) AS is_read
+FROM items
+JOIN item_search ON item_search.item_id = items.id
+CROSS JOIN websearch_to_tsquery('english', sqlc.arg('query')) AS q(query)
+WHERE (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')))
+  AND item_search.search_vector @@ q.query
+  AND (sqlc.narg('tags')::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.narg('tags')::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND (sqlc.narg('platform')::text IS NULL OR lower(items.platform) = lower(sqlc.narg('platform')))
+  AND (sqlc.narg('type')::text IS NULL OR lower(items.type) = lower(sqlc.narg('type')))
+  AND (sqlc.narg('is_read')::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) = sqlc.narg('is_read'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR items.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR items.created_at < sqlc.narg('created_before'))
+ORDER BY rank DESC, items.created_at DESC, items.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListItemsPageByCreatedAsc :many
+-- One page of the items a user can see, oldest first. Rows come after the cursor,
+-- the creation time and ID of the last row of the previous page.
+SELECT sqlc.embed(items),
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) AS is_read
+FROM items
+WHERE (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')))
+  AND (sqlc.narg('workspace_id')::int IS NULL OR items.workspace_id = sqlc.narg('workspace_id'))
+  AND (sqlc.narg('tags')::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.narg('tags')::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND (sqlc.narg('platform')::text IS NULL OR lower(items.platform) = lower(sqlc.narg('platform')))
+  AND (sqlc.narg('type')::text IS NULL OR lower(items.type) = lower(sqlc.narg('type')))
+  AND (sqlc.narg('is_read')::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) = sqlc.narg('is_read'))
+  AND (sqlc.narg('status')::text IS NULL OR items.processing_status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR items.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR items.created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (items.created_at, items.id) > (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')))
+ORDER BY items.created_at ASC, items.id ASC
+LIMIT sqlc.arg('limit');
+
+
+-- name: ListItemsPageByCreatedDesc :many
+-- One page of the items a user can see, newest first, after the cursor
+SELECT sqlc.embed(items),
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) AS is_read
+FROM items
+WHERE (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')))
+  AND (sqlc.narg('workspace_id')::int IS NULL OR items.workspace_id = sqlc.narg('workspace_id'))
+  AND (sqlc.narg('tags')::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.narg('tags')::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND (sqlc.narg('platform')::text IS NULL OR lower(items.platform) = lower(sqlc.narg('platform')))
+  AND (sqlc.narg('type')::text IS NULL OR lower(items.type) = lower(sqlc.narg('type')))
+  AND (sqlc.narg('is_read')::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) = sqlc.narg('is_read'))
+  AND (sqlc.narg('status')::text IS NULL OR items.processing_status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR items.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR items.created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (items.created_at, items.id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')))
+ORDER BY items.created_at DESC, items.id DESC
+LIMIT sqlc.arg('limit');
+
+
+-- name: ListItemsPageByTitleAsc :many
+-- One page of the items a user can see, by title A to Z, after the cursor
+SELECT sqlc.embed(items),
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) AS is_read
+FROM items
+WHERE (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')))
+  AND (sqlc.narg('workspace_id')::int IS NULL OR items.workspace_id = sqlc.narg('workspace_id'))
+  AND (sqlc.narg('tags')::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.narg('tags')::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND (sqlc.narg('platform')::text IS NULL OR lower(items.platform) = lower(sqlc.narg('platform')))
+  AND (sqlc.narg('type')::text IS NULL OR lower(items.type) = lower(sqlc.narg('type')))
+  AND (sqlc.narg('is_read')::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) = sqlc.narg('is_read'))
+  AND (sqlc.narg('status')::text IS NULL OR items.processing_status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR items.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR items.created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (items.title, items.id) > (sqlc.narg('cursor_title')::text, sqlc.narg('cursor_id')))
+ORDER BY items.title ASC, items.id ASC
+LIMIT sqlc.arg('limit');
+
 
+-- name: ListItemsPageByTitleDesc :many
+-- One page of the items a user can see, by title Z to A, after the cursor
+SELECT sqlc.embed(items),
+  EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) AS is_read
+FROM items
+WHERE (items.user_id = sqlc.arg('user_id') OR items.workspace_id IN (SELECT wm.workspace_id FROM workspace_members wm WHERE wm.user_id = sqlc.arg('user_id')))
+  AND (sqlc.narg('workspace_id')::int IS NULL OR items.workspace_id = sqlc.narg('workspace_id'))
+  AND (sqlc.narg('tags')::text[] IS NULL OR NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.narg('tags')::text[]) AS wanted(tag)
+    WHERE lower(wanted.tag) NOT IN (SELECT lower(t) FROM unnest(COALESCE(items.tags, '{}')) AS t)
+  ))
+  AND (sqlc.narg('platform')::text IS NULL OR lower(items.platform) = lower(sqlc.narg('platform')))
+  AND (sqlc.narg('type')::text IS NULL OR lower(items.type) = lower(sqlc.narg('type')))
+  AND (sqlc.narg('is_read')::boolean IS NULL
+    OR EXISTS (SELECT 1 FROM item_reads r WHERE r.item_id = items.id AND r.user_id = sqlc.arg('user_id')) = sqlc.narg('is_read'))
+  AND (sqlc.narg('status')::text IS NULL OR items.processing_status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR items.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR items.created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (items.title, items.id) < (sqlc.narg('cursor_title')::text, sqlc.narg('cursor_id')))
+ORDER BY items.title DESC, items.id DESC
+LIMIT sqlc.arg('limit');
\ No newline at end of file
@@ -1,6 +1,9 @@
 -- name: GetPodcast :one
 SELECT * FROM podcasts WHERE id = $1;
 
+-- name: GetPodcastForUser :one
+SELECT * FROM podcasts WHERE id = $1 AND user_id = $2;
+
 -- name: GetPodcastByUser :many
 SELECT * FROM podcasts WHERE user_id = $1 ORDER BY created_at DESC;
 
@@ -10,6 +13,53 @@ SELECT * FROM podcasts WHERE status = $1 ORDER BY created_at DESC;
 -- name: GetPodcastsByUserAndStatus :many
 SELECT * FROM podcasts WHERE user_id = $1 AND status = $2 ORDER BY created_at DESC;
 
+-- name: ListPodcastsPageByCreatedAsc :many
+-- One page of a user's podcasts, oldest first, after the cursor
+SELECT * FROM podcasts
+WHERE user_id = sqlc.arg('user_id')
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+
+-- name: ListPodcastsPageByCreatedDesc :many
+-- One page of a user's podcasts, newest first, after the cursor
+SELECT * FROM podcasts
+WHERE user_id = sqlc.arg('user_id')
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+
+-- name: ListPodcastsPageByTitleAsc :many
+-- One page of a user's podcasts, by title A to Z, after the cursor
+SELECT * FROM podcasts
+WHERE user_id = sqlc.arg('user_id')
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (title, id) > (sqlc.narg('cursor_title')::text, sqlc.narg('cursor_id')))
+ORDER BY title ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+
+-- name: ListPodcastsPageByTitleDesc :many
+-- One page of a user's podcasts, by title Z to A, after the cursor
+SELECT * FROM podcasts
+WHERE user_id = sqlc.arg('user_id')
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_id')::int IS NULL OR (title, id) < (sqlc.narg('cursor_title')::text, sqlc.narg('cursor_id')))
+ORDER BY title DESC, id DESC
+LIMIT sqlc.arg('limit');
+
 -- name: GetPendingPodcasts :many
 SELECT * FROM podcasts 
 WHERE status = 'pending' 
@@ -53,6 +103,9 @@ UPDATE podcasts SET audio_url = $2, duration_seconds = $3, updated_at = CURRENT_
 -- name: DeletePodcast :exec
 DELETE FROM podcasts WHERE id = $1;
 
+-- name: DeletePodcastsByUser :exec
+DELETE FROM podcasts WHERE user_id = $1;
+
 -- name: GetPodcastItems :many
 SELECT items.*, podcast_items.item_order 
 FROM items 
@@ -63,6 +116,11 @@ ORDER BY podcast_items.item_order ASC;
 -- name: GetPodcastItemIDs :many
 SELECT item_id FROM podcast_items WHERE podcast_id = $1 ORDER BY item_order ASC;
 
+-- name: DeletePodcastItemsByUser :exec
+DELETE FROM podcast_items
+WHERE podcast_id IN (SELECT id FROM podcasts WHERE podcasts.user_id = $1)
+   OR item_id IN (SELECT id FROM items WHERE items.user_id = $1);
+
 -- name: AddItemToPodcast :one
 INSERT INTO podcast_items (podcast_id, item_id, item_order) VALUES ($1, $2, $3) RETURNING *;
 
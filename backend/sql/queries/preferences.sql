@@ -0,0 +1,24 @@
+-- name: GetUserPreferences :one
+SELECT * FROM user_preferences WHERE user_id = $1;
+
+-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (
+  user_id, timezone, digest_time, digest_frequency, podcast_enabled,
+  host_voice, cohost_voice, speech_speed, summary_length, summary_language
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (user_id) DO UPDATE SET
+  timezone = EXCLUDED.timezone,
+  digest_time = EXCLUDED.digest_time,
+  digest_frequency = EXCLUDED.digest_frequency,
+  podcast_enabled = EXCLUDED.podcast_enabled,
+  host_voice = EXCLUDED.host_voice,
+  cohost_voice = EXCLUDED.cohost_voice,
+  speech_speed = EXCLUDED.speech_speed,
+  summary_length = EXCLUDED.summary_length,
+  summary_language = EXCLUDED.summary_language,
+  updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: UpdateLastDigestSent :exec
+INSERT INTO user_preferences (user_id, last_digest_sent_at) VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET last_digest_sent_at = EXCLUDED.last_digest_sent_at;
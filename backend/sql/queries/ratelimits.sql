@@ -0,0 +1,18 @@
+-- name: TakeRateLimitToken :one
+-- Refills the bucket for the time elapsed since its last update and takes one
+-- token. No row is returned when less than one token is available.
+INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, updated_at)
+VALUES (sqlc.arg(bucket_key), sqlc.arg(burst)::float8 - 1, CURRENT_TIMESTAMP)
+ON CONFLICT (bucket_key) DO UPDATE SET
+  tokens = LEAST(sqlc.arg(burst)::float8, b.tokens + EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - b.updated_at))::float8 * sqlc.arg(rate)::float8) - 1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE LEAST(sqlc.arg(burst)::float8, b.tokens + EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - b.updated_at))::float8 * sqlc.arg(rate)::float8) >= 1
+RETURNING tokens;
+
+-- name: GetRateLimitTokens :one
+SELECT LEAST(sqlc.arg(burst)::float8, tokens + EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - updated_at))::float8 * sqlc.arg(rate)::float8)::float8 AS tokens
+FROM rate_limit_buckets
+WHERE bucket_key = sqlc.arg(bucket_key);
+
+-- name: DeleteStaleRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets WHERE updated_at < $1;
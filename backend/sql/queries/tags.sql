@@ -0,0 +1,77 @@
+-- name: UpsertTag :one
+INSERT INTO tags (user_id, name)
+VALUES ($1, $2)
+ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetTagByName :one
+SELECT * FROM tags WHERE user_id = $1 AND name = $2;
+
+-- name: ListTagsByUser :many
+SELECT * FROM tags WHERE user_id = $1 ORDER BY name ASC;
+
+-- name: DeleteTagsByName :exec
+DELETE FROM tags WHERE user_id = sqlc.arg('user_id') AND name = ANY(sqlc.arg('names')::text[]);
+
+-- name: ListTagCounts :many
+-- How many of the user's items carry each tag, spelled as on the items
+SELECT t.tag::text AS name, count(*)::int AS item_count
+FROM items, unnest(items.tags) AS t(tag)
+WHERE items.user_id = $1
+GROUP BY t.tag
+ORDER BY item_count DESC, name ASC;
+
+-- name: UserHasTag :one
+-- Whether the user has a tag with this name, curated or on an item, ignoring case
+SELECT EXISTS (
+  SELECT 1 FROM tags WHERE tags.user_id = sqlc.arg('user_id') AND tags.name = lower(sqlc.arg('name'))
+) OR EXISTS (
+  SELECT 1 FROM items, unnest(items.tags) AS t(tag)
+  WHERE items.user_id = sqlc.arg('user_id') AND lower(t.tag) = lower(sqlc.arg('name'))
+);
+
+-- name: UpsertTagAlias :exec
+INSERT INTO tag_aliases (user_id, alias, tag_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, alias) DO UPDATE SET tag_id = EXCLUDED.tag_id;
+
+-- name: RetargetTagAliases :exec
+-- Points the aliases of the named tags at another tag
+UPDATE tag_aliases SET tag_id = sqlc.arg('tag_id')
+WHERE user_id = sqlc.arg('user_id')
+  AND tag_id IN (SELECT id FROM tags WHERE tags.user_id = sqlc.arg('user_id') AND tags.name = ANY(sqlc.arg('names')::text[]));
+
+-- name: DeleteTagAlias :execrows
+DELETE FROM tag_aliases WHERE user_id = $1 AND alias = $2;
+
+-- name: ListTagAliasesByUser :many
+SELECT tag_aliases.alias, tags.name AS tag
+FROM tag_aliases
+JOIN tags ON tags.id = tag_aliases.tag_id
+WHERE tag_aliases.user_id = $1
+ORDER BY tag_aliases.alias ASC;
+
+-- name: ResolveTagAliases :many
+-- The tags the given aliases point to
+SELECT tag_aliases.alias, tags.name AS tag
+FROM tag_aliases
+JOIN tags ON tags.id = tag_aliases.tag_id
+WHERE tag_aliases.user_id = sqlc.arg('user_id') AND tag_aliases.alias = ANY(sqlc.arg('aliases')::text[]);
+
+-- name: ReplaceItemTags :execrows
+-- Replaces the matching tags of the user's items, compared in lowercase, by
+-- one tag, keeping each item's first spelling of every tag
+UPDATE items
+SET tags = ARRAY(
+  SELECT deduped.tag FROM (
+    SELECT DISTINCT ON (lower(replaced.tag)) replaced.tag, replaced.n
+    FROM (
+      SELECT CASE WHEN lower(t.tag) = ANY(sqlc.arg('matches')::text[]) THEN sqlc.arg('tag')::text ELSE t.tag END AS tag, t.n
+      FROM unnest(items.tags) WITH ORDINALITY AS t(tag, n)
+    ) AS replaced
+    ORDER BY lower(replaced.tag), replaced.n
+  ) AS deduped
+  ORDER BY deduped.n
+), modified_at = CURRENT_TIMESTAMP
+WHERE items.user_id = sqlc.arg('user_id')
+  AND EXISTS (SELECT 1 FROM unnest(items.tags) AS t(tag) WHERE lower(t.tag) = ANY(sqlc.arg('matches')::text[]));
@@ -0,0 +1,13 @@
+-- name: GetUsageCounter :one
+SELECT * FROM usage_counters WHERE user_id = $1 AND day = $2;
+
+-- name: IncrementItemUsage :one
+-- No row is returned once the day's count has reached max_items
+INSERT INTO usage_counters (user_id, day, items) VALUES (sqlc.arg(user_id), sqlc.arg(day), 1)
+ON CONFLICT (user_id, day) DO UPDATE SET items = usage_counters.items + 1
+WHERE usage_counters.items < sqlc.arg(max_items)::integer
+RETURNING items;
+
+-- name: AddPodcastUsage :exec
+INSERT INTO usage_counters (user_id, day, podcast_seconds) VALUES ($1, $2, $3)
+ON CONFLICT (user_id, day) DO UPDATE SET podcast_seconds = usage_counters.podcast_seconds + EXCLUDED.podcast_seconds;
@@ -4,14 +4,33 @@ SELECT * FROM users WHERE id = $1;
 -- name: GetUserByEmail :one
 SELECT * FROM users WHERE email = $1;
 
+-- name: GetUserByOAuthID :one
+SELECT * FROM users WHERE auth_provider = $1 AND oauth_id = $2;
+
 -- name: ListUsers :many
 SELECT * FROM users ORDER BY created_at DESC;
 
 -- name: CreateUser :one
-INSERT INTO users (name, email, auth_provider, oauth_id, password_hash) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+INSERT INTO users (name, email, auth_provider, oauth_id, password_hash, email_verified) VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
 
 -- name: UpdateUser :exec
-UPDATE users SET name = $2, email = $3, auth_provider = $4, oauth_id = $5, password_hash = $6, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
+-- Changing the email clears its verified state
+UPDATE users
+SET name = $2,
+    email = $3,
+    email_verified = email_verified AND email IS NOT DISTINCT FROM $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: UpdateUserPassword :exec
+-- Bumping the session version signs out every session issued before the change
+UPDATE users SET password_hash = $2, session_version = session_version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: LinkUserOAuthIdentity :one
+UPDATE users SET auth_provider = $2, oauth_id = $3, email_verified = TRUE, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING *;
+
+-- name: MarkUserEmailVerified :exec
+UPDATE users SET email_verified = TRUE, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
 
 -- name: DeleteUser :exec
 DELETE FROM users WHERE id = $1;
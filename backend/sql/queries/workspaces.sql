@@ -0,0 +1,46 @@
+-- name: CreateWorkspace :one
+WITH workspace AS (
+  INSERT INTO workspaces (name, created_by) VALUES ($1, $2) RETURNING *
+), owner AS (
+  INSERT INTO workspace_members (workspace_id, user_id, role)
+  SELECT workspace.id, $2, 'owner' FROM workspace
+)
+SELECT * FROM workspace;
+
+-- name: GetWorkspace :one
+SELECT * FROM workspaces WHERE id = $1;
+
+-- name: ListWorkspacesByUser :many
+SELECT workspaces.*, workspace_members.role
+FROM workspaces
+JOIN workspace_members ON workspaces.id = workspace_members.workspace_id
+WHERE workspace_members.user_id = $1
+ORDER BY workspaces.name ASC;
+
+-- name: UpdateWorkspaceName :one
+UPDATE workspaces SET name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING *;
+
+-- name: DeleteWorkspace :exec
+DELETE FROM workspaces WHERE id = $1;
+
+-- name: AddWorkspaceMember :one
+INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: GetWorkspaceMember :one
+SELECT * FROM workspace_members WHERE workspace_id = $1 AND user_id = $2;
+
+-- name: ListWorkspaceMembers :many
+SELECT workspace_members.*, users.name, users.email
+FROM workspace_members
+JOIN users ON users.id = workspace_members.user_id
+WHERE workspace_members.workspace_id = $1
+ORDER BY workspace_members.created_at ASC;
+
+-- name: UpdateWorkspaceMemberRole :one
+UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2 RETURNING *;
+
+-- name: RemoveWorkspaceMember :execrows
+DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2;
+
+-- name: CountWorkspaceOwners :one
+SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1 AND role = 'owner';
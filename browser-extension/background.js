@@ -19,7 +19,7 @@ chrome.runtime.onInstalled.addListener((details) => {
             iconUrl: 'icons/icon128.png',
             title: 'BriefBot Extension',
             message: 'Your elegant reading companion is ready! Click the extension icon to start saving articles.',
-            contextMessage: 'Set your User ID in the popup to get started.'
+            contextMessage: 'Set your API token in the popup to get started.'
         });
     }
 });
@@ -77,7 +77,7 @@ async function checkBackendStatus() {
         const controller = new AbortController();
         const timeoutId = setTimeout(() => controller.abort(), 5000);
         
-        const response = await fetch(`${apiUrl}/users`, {
+        const response = await fetch(`${apiUrl}/health`, {
             method: 'GET',
             signal: controller.signal
         });
@@ -102,7 +102,7 @@ async function checkBackendStatus() {
 // Submit URL to backend with enhanced error handling
 async function submitUrlToBackend(data) {
     try {
-        const storage = await chrome.storage.local.get(['apiUrl', 'showNotifications']);
+        const storage = await chrome.storage.local.get(['apiUrl', 'apiToken', 'showNotifications']);
         const apiUrl = storage.apiUrl || 'http://localhost:8080';
         const showNotifications = storage.showNotifications !== false;
         
@@ -113,9 +113,9 @@ async function submitUrlToBackend(data) {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json',
+                'Authorization': `Bearer ${storage.apiToken}`,
             },
             body: JSON.stringify({
-                user_id: data.user_id,
                 url: data.url
             }),
             signal: controller.signal
@@ -194,16 +194,15 @@ chrome.contextMenus.onClicked.addListener(async (info, tab) => {
         const url = info.linkUrl || info.pageUrl;
         const title = tab.title || 'Untitled';
         
-        // Get user ID from storage
-        const storage = await chrome.storage.local.get(['userId']);
-        const userId = storage.userId;
+        // Get API token from storage
+        const storage = await chrome.storage.local.get(['apiToken']);
         
-        if (!userId) {
-            chrome.notifications.create('no-userid', {
+        if (!storage.apiToken) {
+            chrome.notifications.create('no-token', {
                 type: 'basic',
                 iconUrl: 'icons/icon128.png',
                 title: 'BriefBot',
-                message: 'Please set your User ID in the extension popup first.',
+                message: 'Please set your API token in the extension popup first.',
                 contextMessage: 'Click the extension icon to configure.'
             });
             return;
@@ -211,7 +210,6 @@ chrome.contextMenus.onClicked.addListener(async (info, tab) => {
         
         // Submit the URL with loading state
         const result = await submitUrlToBackend({
-            user_id: userId,
             url: url,
             title: title
         });
@@ -9,7 +9,7 @@ class BriefBotExtension {
 
     async init() {
         await this.loadCurrentTab();
-        await this.loadUserId();
+        await this.loadApiToken();
         this.setupEventListeners();
         this.checkBackendStatus();
         this.updateUI();
@@ -69,45 +69,45 @@ class BriefBotExtension {
         return url.substring(0, maxLength - 3) + '...';
     }
 
-    async loadUserId() {
+    async loadApiToken() {
         try {
-            const result = await chrome.storage.local.get(['userId']);
-            if (result.userId) {
-                const userIdInput = document.getElementById('userId');
-                userIdInput.value = result.userId;
+            const result = await chrome.storage.local.get(['apiToken']);
+            if (result.apiToken) {
+                const apiTokenInput = document.getElementById('apiToken');
+                apiTokenInput.value = result.apiToken;
                 
                 // Add success animation
-                userIdInput.style.animation = 'fadeIn 0.3s ease';
+                apiTokenInput.style.animation = 'fadeIn 0.3s ease';
                 
                 // Show subtle success indicator
-                this.showStatus('User ID loaded', 'info');
+                this.showStatus('API token loaded', 'info');
                 setTimeout(() => this.hideStatus(), 1500);
             }
         } catch (error) {
-            console.error('Error loading user ID:', error);
+            console.error('Error loading API token:', error);
         }
     }
 
     setupEventListeners() {
-        // Save user ID with enhanced UX
-        document.getElementById('saveUserId').addEventListener('click', async () => {
-            const userId = document.getElementById('userId').value;
-            const saveBtn = document.getElementById('saveUserId');
+        // Save API token with enhanced UX
+        document.getElementById('saveApiToken').addEventListener('click', async () => {
+            const apiToken = document.getElementById('apiToken').value.trim();
+            const saveBtn = document.getElementById('saveApiToken');
             
-            if (userId && userId > 0) {
+            if (apiToken) {
                 try {
                     // Show loading state
                     saveBtn.textContent = 'Saving...';
                     saveBtn.disabled = true;
                     
-                    await chrome.storage.local.set({ userId: parseInt(userId) });
+                    await chrome.storage.local.set({ apiToken: apiToken });
                     
                     // Show success with animation
                     saveBtn.textContent = 'Saved!';
                     saveBtn.classList.add('button-secondary');
                     saveBtn.classList.remove('button-secondary');
                     
-                    this.showStatus('User ID saved successfully!', 'success');
+                    this.showStatus('API token saved successfully!', 'success');
                     
                     // Reset button after success
                     setTimeout(() => {
@@ -117,13 +117,13 @@ class BriefBotExtension {
                     }, 2000);
                     
                 } catch (error) {
-                    console.error('Error saving user ID:', error);
-                    this.showStatus('Error saving user ID', 'error');
+                    console.error('Error saving API token:', error);
+                    this.showStatus('Error saving API token', 'error');
                     saveBtn.textContent = 'Save';
                     saveBtn.disabled = false;
                 }
             } else {
-                this.showStatus('Please enter a valid user ID', 'error');
+                this.showStatus('Please enter an API token', 'error');
             }
         });
 
@@ -133,16 +133,16 @@ class BriefBotExtension {
         });
 
         // Enter key to submit
-        document.getElementById('userId').addEventListener('keypress', (e) => {
+        document.getElementById('apiToken').addEventListener('keypress', (e) => {
             if (e.key === 'Enter') {
-                document.getElementById('saveUserId').click();
+                document.getElementById('saveApiToken').click();
             }
         });
     }
 
     async checkBackendStatus() {
         try {
-            const response = await fetch(`${this.API_BASE_URL}/users`, {
+            const response = await fetch(`${this.API_BASE_URL}/health`, {
                 method: 'GET',
                 timeout: 5000
             });
@@ -167,12 +167,12 @@ class BriefBotExtension {
     }
 
     updateUI() {
-        const userId = document.getElementById('userId').value;
+        const apiToken = document.getElementById('apiToken').value.trim();
         const submitBtn = document.getElementById('submitBtn');
         
-        if (!userId || userId <= 0) {
+        if (!apiToken) {
             submitBtn.disabled = true;
-            this.showStatus('Please enter and save your User ID first', 'info');
+            this.showStatus('Please enter and save your API token first', 'info');
         } else if (!this.currentTab || !this.currentTab.url) {
             submitBtn.disabled = true;
             this.showStatus('Unable to get current page information', 'error');
@@ -183,12 +183,12 @@ class BriefBotExtension {
     }
 
     async submitUrl() {
-        const userId = parseInt(document.getElementById('userId').value);
+        const apiToken = document.getElementById('apiToken').value.trim();
         const url = this.currentTab.url;
         const title = this.currentTab.title || 'Untitled Page';
 
-        if (!userId || userId <= 0) {
-            this.showStatus('Please enter and save your User ID', 'error');
+        if (!apiToken) {
+            this.showStatus('Please enter and save your API token', 'error');
             return;
         }
 
@@ -216,9 +216,9 @@ class BriefBotExtension {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
+                    'Authorization': `Bearer ${apiToken}`,
                 },
                 body: JSON.stringify({
-                    user_id: userId,
                     url: url
                 })
             });
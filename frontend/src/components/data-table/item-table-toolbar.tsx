@@ -28,13 +28,7 @@ export function ItemTableToolbar({ table, data, userId }: ItemTableToolbarProps)
 
   // Mutation for triggering integrated digest
   const triggerDigestMutation = useMutation({
-    mutationFn: () => {
-      if (userId) {
-        return digestApi.triggerIntegratedDigestForUser(userId)
-      } else {
-        return digestApi.triggerIntegratedDigest()
-      }
-    },
+    mutationFn: () => digestApi.triggerIntegratedDigest(),
     onSuccess: () => {
       toast.success(
         "Digest processing started! You'll receive an email when ready.",
@@ -54,11 +48,7 @@ export function ItemTableToolbar({ table, data, userId }: ItemTableToolbarProps)
   // Mutation for creating podcast
   const createPodcastMutation = useMutation({
     mutationFn: () => {
-      if (!userId) {
-        throw new Error("User ID is required")
-      }
       return podcastApi.createPodcast({
-        user_id: userId,
         title: `Podcast from ${selectedItemIds.length} items`,
         description: `Generated podcast from selected items`,
         item_ids: selectedItemIds,
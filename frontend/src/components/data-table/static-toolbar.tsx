@@ -22,13 +22,7 @@ export function StaticToolbar({
 
   // Mutation for triggering integrated digest
   const triggerDigestMutation = useMutation({
-    mutationFn: () => {
-      if (userId) {
-        return digestApi.triggerIntegratedDigestForUser(userId);
-      } else {
-        return digestApi.triggerIntegratedDigest();
-      }
-    },
+    mutationFn: () => digestApi.triggerIntegratedDigest(),
     onSuccess: () => {
       toast.success(
         "Digest processing started! You'll receive an email when ready.",
@@ -14,7 +14,7 @@ export default function UrlSubmissionForm({ userId, onSuccess }: UrlSubmissionFo
 
   // Mutation for submitting URL
   const submitUrlMutation = useMutation({
-    mutationFn: (url: string) => itemApi.submitUrl({ user_id: userId, url }),
+    mutationFn: (url: string) => itemApi.submitUrl({ url }),
     onSuccess: () => {
       toast.success('URL submitted successfully!')
       queryClient.invalidateQueries({ queryKey: ['items', userId] })
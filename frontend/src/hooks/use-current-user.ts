@@ -0,0 +1,10 @@
+import { useQuery } from '@tanstack/react-query'
+import { userApi } from '@/services/api'
+
+// Fetch the signed-in user from /auth/me; shared by every page that needs the user ID
+export function useCurrentUser() {
+  return useQuery({
+    queryKey: ['users', 'me'],
+    queryFn: () => userApi.getCurrentUser(),
+  })
+}
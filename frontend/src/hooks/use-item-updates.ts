@@ -1,6 +1,6 @@
 import { useEffect, useRef, useState } from 'react'
 import { useQueryClient } from '@tanstack/react-query'
-import { API_BASE_URL } from '@/services/api'
+import { streamUrl } from '@/services/api'
 
 interface ItemUpdateEvent {
   item_id: number
@@ -26,9 +26,7 @@ export function useItemUpdates(userId: number | undefined) {
 
     try {
       // Create EventSource connection
-      eventSource = new EventSource(
-        `${API_BASE_URL}/items/user/${userId}/stream`
-      )
+      eventSource = new EventSource(streamUrl('/items/stream'))
 
       eventSourceRef.current = eventSource
 
@@ -5,6 +5,7 @@ import { itemApi } from "@/services/api";
 import { UrlSubmissionDialog } from "@/components/url-submission-dialog";
 import { Button } from "@/components/ui/button";
 import { useItemUpdates } from "@/hooks/use-item-updates";
+import { useCurrentUser } from "@/hooks/use-current-user";
 import type { Item } from "@/types";
 
 export const Route = createFileRoute("/")({
@@ -12,8 +13,8 @@ export const Route = createFileRoute("/")({
 });
 
 function App() {
-  // For now, we'll use a hardcoded user ID. In a real app, this would come from auth context
-  const userId = 1; // TODO: Get this from auth context
+  const { data: currentUser, isLoading: isUserLoading, error: userError } = useCurrentUser();
+  const userId = currentUser?.id;
 
   // Subscribe to real-time item updates via SSE
   const { isConnected } = useItemUpdates(userId);
@@ -21,7 +22,7 @@ function App() {
   // Fetch real items data (no polling - updates come via SSE)
   const { data: items = [], isLoading, error, refetch } = useQuery({
     queryKey: ['items', userId],
-    queryFn: () => itemApi.getItemsByUser(userId),
+    queryFn: () => itemApi.getItems(),
     enabled: !!userId,
     staleTime: Infinity, // Data is always fresh since we get SSE updates
     refetchOnWindowFocus: true, // Refetch on window focus as fallback
@@ -38,16 +39,16 @@ function App() {
 
       {/* Data Table Section */}
       <div className="space-y-4">
-        {isLoading ? (
+        {isLoading || isUserLoading ? (
           <div className="flex items-center justify-center h-64">
             <div className="text-center">
               <div className="animate-spin rounded-full h-8 w-8 border-b-2 border-gray-900 mx-auto mb-4"></div>
               <p className="text-muted-foreground">Loading your items...</p>
             </div>
           </div>
-        ) : error ? (
+        ) : error || userError || !userId ? (
           <div className="text-center p-8 text-red-500">
-            <p>Error loading items: {error.message}</p>
+            <p>Error loading items: {(error ?? userError)?.message}</p>
             <Button 
               onClick={() => window.location.reload()} 
               variant="outline" 
@@ -6,6 +6,7 @@ import { Input } from '@/components/ui/input'
 import { Label } from '@/components/ui/label'
 import { Textarea } from '@/components/ui/textarea'
 import { ItemDataTable } from '@/components/item-data-table'
+import { useCurrentUser } from '@/hooks/use-current-user'
 import { useState } from 'react'
 import type { CreateItemRequest } from '@/types'
 
@@ -16,10 +17,10 @@ export const Route = createFileRoute('/items/')({
 function ItemsPage() {
   const queryClient = useQueryClient()
   const [showForm, setShowForm] = useState(false)
-  const [selectedUserId, setSelectedUserId] = useState<number>(1) // Default user ID
+  const { data: currentUser, isLoading: isUserLoading, error: userError } = useCurrentUser()
+  const userId = currentUser?.id
   const [filter, setFilter] = useState<'all' | 'unread'>('all')
   const [formData, setFormData] = useState<CreateItemRequest>({
-    user_id: 1,
     url: '',
     text_content: '',
     summary: '',
@@ -31,22 +32,23 @@ function ItemsPage() {
 
   // Fetch items based on filter
   const { data: items = [], isLoading, error, refetch } = useQuery({
-    queryKey: ['items', selectedUserId, filter],
+    queryKey: ['items', userId, filter],
     queryFn: async () => {
       if (filter === 'unread') {
-        return await itemApi.getUnreadItemsByUser(selectedUserId)
+        return await itemApi.getUnreadItems()
       } else {
-        return await itemApi.getItemsByUser(selectedUserId)
+        return await itemApi.getItems()
       }
     },
+    enabled: !!userId,
   })
 
   const createItemMutation = useMutation({
     mutationFn: itemApi.createItem,
     onSuccess: () => {
-      queryClient.invalidateQueries({ queryKey: ['items', selectedUserId] })
+      queryClient.invalidateQueries({ queryKey: ['items', userId] })
       setShowForm(false)
-      setFormData({ user_id: selectedUserId, url: '', text_content: '', summary: '', type: '', platform: '', tags: [], authors: [] })
+      setFormData({ url: '', text_content: '', summary: '', type: '', platform: '', tags: [], authors: [] })
     },
   })
 
@@ -73,8 +75,8 @@ function ItemsPage() {
     }
   }
 
-  if (isLoading) return <div>Loading...</div>
-  if (error) return <div>Error loading items</div>
+  if (isLoading || isUserLoading) return <div>Loading...</div>
+  if (error || userError || !userId) return <div>Error loading items</div>
 
   return (
     <div className="container mx-auto p-6">
@@ -86,16 +88,6 @@ function ItemsPage() {
       </div>
 
       <div className="mb-6 flex gap-4 items-center">
-        <div>
-          <Label htmlFor="userId">User ID</Label>
-          <Input
-            id="userId"
-            type="number"
-            value={selectedUserId}
-            onChange={(e) => setSelectedUserId(parseInt(e.target.value) || 1)}
-            className="w-24"
-          />
-        </div>
         <div className="flex gap-2">
           <Button
             variant={filter === 'all' ? 'default' : 'outline'}
@@ -119,16 +111,6 @@ function ItemsPage() {
         <div className="bg-white p-6 rounded-lg shadow-md mb-6">
           <h2 className="text-lg font-semibold mb-4">Create New Item</h2>
           <form onSubmit={handleSubmit} className="space-y-4">
-            <div>
-              <Label htmlFor="user_id">User ID</Label>
-              <Input
-                id="user_id"
-                type="number"
-                value={formData.user_id}
-                onChange={(e) => setFormData({ ...formData, user_id: parseInt(e.target.value) || 1 })}
-                required
-              />
-            </div>
             <div>
               <Label htmlFor="url">URL</Label>
               <Input
@@ -212,7 +194,7 @@ function ItemsPage() {
         ) : (
           <ItemDataTable
             data={items}
-            userId={selectedUserId}
+            userId={userId}
           />
         )}
       </div>
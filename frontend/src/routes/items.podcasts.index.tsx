@@ -1,9 +1,10 @@
 import { createFileRoute } from '@tanstack/react-router'
 import { useQuery } from '@tanstack/react-query'
-import { podcastApi, API_BASE_URL } from '@/services/api'
+import { podcastApi, streamUrl } from '@/services/api'
 import { StaticDataTable } from '@/components/data-table/static-data-table'
 import { podcastColumns } from '@/components/data-table/podcast-columns'
 import { Podcast } from '@/types'
+import { useCurrentUser } from '@/hooks/use-current-user'
 import { useEffect, useState } from 'react'
 
 export const Route = createFileRoute('/items/podcasts/')({
@@ -11,13 +12,15 @@ export const Route = createFileRoute('/items/podcasts/')({
 })
 
 function PodcastsPage() {
-  const userId = 1 // TODO: Get from auth context
+  const { data: currentUser, isLoading: isUserLoading, error: userError } = useCurrentUser()
+  const userId = currentUser?.id
   const [podcasts, setPodcasts] = useState<Podcast[]>([])
 
   // Fetch podcasts
   const { data, isLoading, error } = useQuery({
     queryKey: ['podcasts', userId],
-    queryFn: () => podcastApi.getPodcastsByUser(userId),
+    queryFn: () => podcastApi.getPodcasts(),
+    enabled: !!userId,
     refetchInterval: 5000, // Refetch every 5 seconds to update status
   })
 
@@ -29,7 +32,7 @@ function PodcastsPage() {
 
   // Setup SSE for real-time updates
   useEffect(() => {
-    const eventSource = new EventSource(`${API_BASE_URL}/podcasts/user/${userId}/stream`)
+    const eventSource = new EventSource(streamUrl('/podcasts/stream'))
 
     eventSource.addEventListener('podcast-update', (event) => {
       const updateData = JSON.parse(event.data)
@@ -53,7 +56,7 @@ function PodcastsPage() {
     }
   }, [userId])
 
-  if (isLoading) {
+  if (isLoading || isUserLoading) {
     return (
       <div className="flex items-center justify-center h-64">
         <div className="text-lg">Loading podcasts...</div>
@@ -61,7 +64,7 @@ function PodcastsPage() {
     )
   }
 
-  if (error) {
+  if (error || userError || !userId) {
     return (
       <div className="flex items-center justify-center h-64">
         <div className="text-lg text-red-600">Error loading podcasts</div>
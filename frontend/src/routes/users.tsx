@@ -1,47 +1,15 @@
 import { createFileRoute } from '@tanstack/react-router'
-import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
-import { userApi } from '@/services/api'
 import { Button } from '@/components/ui/button'
-import { Input } from '@/components/ui/input'
-import { Label } from '@/components/ui/label'
-import { useState } from 'react'
-import type { CreateUserRequest } from '@/types'
+import { useCurrentUser } from '@/hooks/use-current-user'
 
 export const Route = createFileRoute('/users')({
   component: UsersPage,
 })
 
 function UsersPage() {
-  const queryClient = useQueryClient()
-  const [showForm, setShowForm] = useState(false)
-  const [formData, setFormData] = useState<CreateUserRequest>({
-    name: '',
-    email: '',
-  })
-
-  // Fetch all users
-  const { data: users = [], isLoading, error } = useQuery({
-    queryKey: ['users'],
-    queryFn: async () => {
-      return await userApi.listUsers()
-    },
-  })
-
-  const createUserMutation = useMutation({
-    mutationFn: userApi.createUser,
-    onSuccess: () => {
-      queryClient.invalidateQueries({ queryKey: ['users'] })
-      setShowForm(false)
-      setFormData({ name: '', email: '' })
-    },
-  })
-
-  const handleSubmit = (e: React.FormEvent) => {
-    e.preventDefault()
-    if (formData.name && formData.email) {
-      createUserMutation.mutate(formData)
-    }
-  }
+  // Fetch the signed-in user; other accounts are not visible
+  const { data: currentUser, isLoading, error } = useCurrentUser()
+  const users = currentUser ? [currentUser] : []
 
   if (isLoading) return <div>Loading...</div>
   if (error) return <div>Error loading users</div>
@@ -50,47 +18,13 @@ function UsersPage() {
     <div className="container mx-auto p-6">
       <div className="flex justify-between items-center mb-6">
         <h1 className="text-2xl font-bold">Users</h1>
-        <Button onClick={() => setShowForm(!showForm)}>
-          {showForm ? 'Cancel' : 'Create User'}
-        </Button>
       </div>
 
-      {showForm && (
-        <div className="bg-white p-6 rounded-lg shadow-md mb-6">
-          <h2 className="text-lg font-semibold mb-4">Create New User</h2>
-          <form onSubmit={handleSubmit} className="space-y-4">
-            <div>
-              <Label htmlFor="name">Name</Label>
-              <Input
-                id="name"
-                type="text"
-                value={formData.name}
-                onChange={(e) => setFormData({ ...formData, name: e.target.value })}
-                required
-              />
-            </div>
-            <div>
-              <Label htmlFor="email">Email</Label>
-              <Input
-                id="email"
-                type="email"
-                value={formData.email}
-                onChange={(e) => setFormData({ ...formData, email: e.target.value })}
-                required
-              />
-            </div>
-            <Button type="submit" disabled={createUserMutation.isPending}>
-              {createUserMutation.isPending ? 'Creating...' : 'Create User'}
-            </Button>
-          </form>
-        </div>
-      )}
-
       <div className="bg-white rounded-lg shadow-md">
         <div className="p-6">
           <h2 className="text-lg font-semibold mb-4">Users ({users.length})</h2>
           {users.length === 0 ? (
-            <p className="text-gray-500">No users found. Create a user to get started.</p>
+            <p className="text-gray-500">No users found. Sign up to get started.</p>
           ) : (
             <div className="space-y-2">
               {users.map((user) => (
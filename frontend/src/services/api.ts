@@ -2,7 +2,6 @@ import axios from 'axios'
 import type {
   User,
   Item,
-  CreateUserRequest,
   UpdateUserRequest,
   CreateItemRequest,
   UpdateItemRequest,
@@ -15,6 +14,31 @@ import type {
 // Use environment variable with fallback to localhost for development
 const API_BASE_URL = import.meta.env.VITE_API_URL || 'http://localhost:8080'
 
+// Key of the session or API token in localStorage
+const AUTH_TOKEN_KEY = 'briefbot_token'
+
+// getAuthToken returns the stored session token, falling back to an API token
+// configured at build time
+export function getAuthToken(): string | null {
+  return localStorage.getItem(AUTH_TOKEN_KEY) || import.meta.env.VITE_API_TOKEN || null
+}
+
+export function setAuthToken(token: string | null) {
+  if (token) {
+    localStorage.setItem(AUTH_TOKEN_KEY, token)
+  } else {
+    localStorage.removeItem(AUTH_TOKEN_KEY)
+  }
+}
+
+// streamUrl builds an SSE URL. EventSource cannot set headers, so the token
+// is passed as the access_token query parameter.
+export function streamUrl(path: string): string {
+  const token = getAuthToken()
+  const url = `${API_BASE_URL}${path}`
+  return token ? `${url}?access_token=${encodeURIComponent(token)}` : url
+}
+
 const api = axios.create({
   baseURL: API_BASE_URL,
   headers: {
@@ -22,38 +46,50 @@ const api = axios.create({
   },
 })
 
+// Every API route except login and registration requires a bearer token
+api.interceptors.request.use((config) => {
+  const token = getAuthToken()
+  if (token) {
+    config.headers.Authorization = `Bearer ${token}`
+  }
+  return config
+})
+
 // Export API_BASE_URL for use in SSE connections
-export { API_BASE_URL }
+export { API_BASE_URL, api }
+
+// Largest page the paginated list endpoints return
+const MAX_PAGE_SIZE = 200
+
+// fetchAllPages follows next_cursor from the first page of a paginated list
+// until the last one and returns the rows of every page
+async function fetchAllPages<T, P extends { next_cursor?: string | null }>(
+  path: string,
+  rows: (page: P) => T[],
+): Promise<T[]> {
+  const all: T[] = []
+  let cursor: string | null | undefined
+  do {
+    const response = await api.get<P>(path, { params: { limit: MAX_PAGE_SIZE, cursor } })
+    all.push(...rows(response.data))
+    cursor = response.data.next_cursor
+  } while (cursor)
+  return all
+}
 
 // User API functions
 export const userApi = {
-  createUser: async (data: CreateUserRequest): Promise<User> => {
-    const response = await api.post<User>('/users', data)
+  getCurrentUser: async (): Promise<User> => {
+    const response = await api.get<User>('/auth/me')
     return response.data
   },
 
-  getUser: async (id: number): Promise<User> => {
-    const response = await api.get<User>(`/users/${id}`)
-    return response.data
+  updateCurrentUser: async (data: UpdateUserRequest): Promise<void> => {
+    await api.put('/users/me', data)
   },
 
-  getUserByEmail: async (email: string): Promise<User> => {
-    const response = await api.get<User>(`/users/email/${email}`)
-    return response.data
-  },
-
-  listUsers: async (): Promise<User[]> => {
-    const response = await api.get<User[]>('/users')
-    return response.data
-  },
-
-  updateUser: async (id: number, data: UpdateUserRequest): Promise<User> => {
-    const response = await api.put<User>(`/users/${id}`, data)
-    return response.data
-  },
-
-  deleteUser: async (id: number): Promise<void> => {
-    await api.delete(`/users/${id}`)
+  deleteCurrentUser: async (): Promise<void> => {
+    await api.delete('/users/me')
   },
 }
 
@@ -66,9 +102,7 @@ export const itemApi = {
 
   submitUrl: async (data: SubmitUrlRequest): Promise<Item> => {
     const requestData: CreateItemRequest = {
-      user_id: data.user_id,
       url: data.url,
-      text_content: '', // Will be populated by backend scraping
     }
     const response = await api.post<Item>('/items', requestData)
     return response.data
@@ -79,18 +113,12 @@ export const itemApi = {
     return response.data
   },
 
-  getItemById: async (id: number, userId: number): Promise<Item> => {
-    const response = await api.get<Item>(`/items/${id}?user_id=${userId}`)
-    return response.data
-  },
-
-  getItemsByUser: async (userId: number): Promise<Item[]> => {
-    const response = await api.get<Item[]>(`/items/user/${userId}`)
-    return response.data
+  getItems: async (): Promise<Item[]> => {
+    return fetchAllPages('/items', (page: { items: Item[]; next_cursor: string | null }) => page.items)
   },
 
-  getUnreadItemsByUser: async (userId: number): Promise<Item[]> => {
-    const response = await api.get<Item[]>(`/items/user/${userId}/unread`)
+  getUnreadItems: async (): Promise<Item[]> => {
+    const response = await api.get<Item[]>('/items/unread')
     return response.data
   },
 
@@ -121,19 +149,11 @@ export const itemApi = {
 // Digest API functions
 export const digestApi = {
   triggerIntegratedDigest: async (): Promise<void> => {
-    await api.post('/digest/trigger/integrated')
-  },
-
-  triggerIntegratedDigestForUser: async (userId: number): Promise<void> => {
-    await api.post(`/digest/trigger/integrated/user/${userId}`)
+    await api.post('/digest/trigger/integrated/me')
   },
 
   triggerDailyDigest: async (): Promise<void> => {
-    await api.post('/digest/trigger')
-  },
-
-  triggerDailyDigestForUser: async (userId: number): Promise<void> => {
-    await api.post(`/digest/trigger/user/${userId}`)
+    await api.post('/digest/trigger/me')
   },
 }
 
@@ -149,9 +169,9 @@ export const podcastApi = {
     return response.data
   },
 
-  getPodcastsByUser: async (userId: number): Promise<{ podcasts: Podcast[]; count: number }> => {
-    const response = await api.get<{ podcasts: Podcast[]; count: number }>(`/podcasts/user/${userId}`)
-    return response.data
+  getPodcasts: async (): Promise<{ podcasts: Podcast[]; count: number }> => {
+    const podcasts = await fetchAllPages('/podcasts', (page: { podcasts: Podcast[]; next_cursor?: string }) => page.podcasts)
+    return { podcasts, count: podcasts.length }
   },
 
   getPodcastProcessingStatus: async (id: number): Promise<{
@@ -3,8 +3,8 @@ export interface User {
   name?: string | null
   email?: string | null
   auth_provider?: string | null
-  oauth_id?: string | null
-  password_hash?: string | null
+  has_password?: boolean
+  email_verified?: boolean
   created_at?: string | null
   updated_at?: string | null
 }
@@ -25,26 +25,14 @@ export interface Item {
   modified_at?: string | null
 }
 
-export interface CreateUserRequest {
-  name: string
-  email: string
-  auth_provider?: string
-  oauth_id?: string
-  password_hash?: string
-}
-
 export interface UpdateUserRequest {
   name?: string
   email?: string
-  auth_provider?: string
-  oauth_id?: string
-  password_hash?: string
 }
 
 export interface CreateItemRequest {
-  user_id: number
   url: string
-  text_content: string
+  text_content?: string
   summary?: string
   type?: string
   platform?: string
@@ -65,7 +53,6 @@ export interface UpdateItemRequest {
 }
 
 export interface SubmitUrlRequest {
-  user_id: number
   url: string
 }
 
@@ -83,7 +70,6 @@ export interface Podcast {
 }
 
 export interface CreatePodcastRequest {
-  user_id: number
   title: string
   description?: string
   item_ids: number[]